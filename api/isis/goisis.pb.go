@@ -23,6 +23,7 @@ var _ = math.Inf
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
 type EnableRequest struct {
+	Instance             string   `protobuf:"bytes,1,opt,name=instance,proto3" json:"instance,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -53,6 +54,13 @@ func (m *EnableRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_EnableRequest proto.InternalMessageInfo
 
+func (m *EnableRequest) GetInstance() string {
+	if m != nil {
+		return m.Instance
+	}
+	return ""
+}
+
 type EnableResponse struct {
 	Result               string   `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -93,6 +101,7 @@ func (m *EnableResponse) GetResult() string {
 }
 
 type DisableRequest struct {
+	Instance             string   `protobuf:"bytes,1,opt,name=instance,proto3" json:"instance,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -123,6 +132,13 @@ func (m *DisableRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_DisableRequest proto.InternalMessageInfo
 
+func (m *DisableRequest) GetInstance() string {
+	if m != nil {
+		return m.Instance
+	}
+	return ""
+}
+
 type DisableResponse struct {
 	Result               string   `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -164,6 +180,7 @@ func (m *DisableResponse) GetResult() string {
 
 type InterfaceEnableRequest struct {
 	Interface            string   `protobuf:"bytes,1,opt,name=interface,proto3" json:"interface,omitempty"`
+	Instance             string   `protobuf:"bytes,2,opt,name=instance,proto3" json:"instance,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -201,6 +218,13 @@ func (m *InterfaceEnableRequest) GetInterface() string {
 	return ""
 }
 
+func (m *InterfaceEnableRequest) GetInstance() string {
+	if m != nil {
+		return m.Instance
+	}
+	return ""
+}
+
 type InterfaceEnableResponse struct {
 	Result               string   `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -242,6 +266,7 @@ func (m *InterfaceEnableResponse) GetResult() string {
 
 type InterfaceDisableRequest struct {
 	Interface            string   `protobuf:"bytes,1,opt,name=interface,proto3" json:"interface,omitempty"`
+	Instance             string   `protobuf:"bytes,2,opt,name=instance,proto3" json:"instance,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -279,6 +304,13 @@ func (m *InterfaceDisableRequest) GetInterface() string {
 	return ""
 }
 
+func (m *InterfaceDisableRequest) GetInstance() string {
+	if m != nil {
+		return m.Instance
+	}
+	return ""
+}
+
 type InterfaceDisableResponse struct {
 	Result               string   `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -319,6 +351,7 @@ func (m *InterfaceDisableResponse) GetResult() string {
 }
 
 type AdjacencyGetRequest struct {
+	Instance             string   `protobuf:"bytes,1,opt,name=instance,proto3" json:"instance,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -349,6 +382,13 @@ func (m *AdjacencyGetRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_AdjacencyGetRequest proto.InternalMessageInfo
 
+func (m *AdjacencyGetRequest) GetInstance() string {
+	if m != nil {
+		return m.Instance
+	}
+	return ""
+}
+
 type AdjacencyGetResponse struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -382,6 +422,7 @@ var xxx_messageInfo_AdjacencyGetResponse proto.InternalMessageInfo
 
 type AdjacencyMonitorRequest struct {
 	Interface            string   `protobuf:"bytes,1,opt,name=interface,proto3" json:"interface,omitempty"`
+	Instance             string   `protobuf:"bytes,2,opt,name=instance,proto3" json:"instance,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -419,6 +460,13 @@ func (m *AdjacencyMonitorRequest) GetInterface() string {
 	return ""
 }
 
+func (m *AdjacencyMonitorRequest) GetInstance() string {
+	if m != nil {
+		return m.Instance
+	}
+	return ""
+}
+
 type AdjacencyMonitorResponse struct {
 	Adjacencies          []*Adjacency `protobuf:"bytes,1,rep,name=adjacencies,proto3" json:"adjacencies,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
@@ -459,6 +507,7 @@ func (m *AdjacencyMonitorResponse) GetAdjacencies() []*Adjacency {
 }
 
 type DbLsGetRequest struct {
+	Instance             string   `protobuf:"bytes,1,opt,name=instance,proto3" json:"instance,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -489,6 +538,13 @@ func (m *DbLsGetRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_DbLsGetRequest proto.InternalMessageInfo
 
+func (m *DbLsGetRequest) GetInstance() string {
+	if m != nil {
+		return m.Instance
+	}
+	return ""
+}
+
 type DbLsGetResponse struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -522,6 +578,7 @@ var xxx_messageInfo_DbLsGetResponse proto.InternalMessageInfo
 
 type DbLsMonitorRequest struct {
 	Level                string   `protobuf:"bytes,1,opt,name=level,proto3" json:"level,omitempty"`
+	Instance             string   `protobuf:"bytes,2,opt,name=instance,proto3" json:"instance,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -559,6 +616,13 @@ func (m *DbLsMonitorRequest) GetLevel() string {
 	return ""
 }
 
+func (m *DbLsMonitorRequest) GetInstance() string {
+	if m != nil {
+		return m.Instance
+	}
+	return ""
+}
+
 type DbLsMonitorResponse struct {
 	Lsps                 []*Lsp   `protobuf:"bytes,1,rep,name=lsps,proto3" json:"lsps,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -598,7 +662,102 @@ func (m *DbLsMonitorResponse) GetLsps() []*Lsp {
 	return nil
 }
 
+type DbLsExportRequest struct {
+	Level                string   `protobuf:"bytes,1,opt,name=level,proto3" json:"level,omitempty"`
+	Instance             string   `protobuf:"bytes,2,opt,name=instance,proto3" json:"instance,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DbLsExportRequest) Reset()         { *m = DbLsExportRequest{} }
+func (m *DbLsExportRequest) String() string { return proto.CompactTextString(m) }
+func (*DbLsExportRequest) ProtoMessage()    {}
+func (*DbLsExportRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_07ca5a18eb6d27f6, []int{16}
+}
+
+func (m *DbLsExportRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DbLsExportRequest.Unmarshal(m, b)
+}
+func (m *DbLsExportRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DbLsExportRequest.Marshal(b, m, deterministic)
+}
+func (m *DbLsExportRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DbLsExportRequest.Merge(m, src)
+}
+func (m *DbLsExportRequest) XXX_Size() int {
+	return xxx_messageInfo_DbLsExportRequest.Size(m)
+}
+func (m *DbLsExportRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DbLsExportRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DbLsExportRequest proto.InternalMessageInfo
+
+func (m *DbLsExportRequest) GetLevel() string {
+	if m != nil {
+		return m.Level
+	}
+	return ""
+}
+
+func (m *DbLsExportRequest) GetInstance() string {
+	if m != nil {
+		return m.Instance
+	}
+	return ""
+}
+
+type DbLsExportResponse struct {
+	Result               string   `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	Data                 []byte   `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DbLsExportResponse) Reset()         { *m = DbLsExportResponse{} }
+func (m *DbLsExportResponse) String() string { return proto.CompactTextString(m) }
+func (*DbLsExportResponse) ProtoMessage()    {}
+func (*DbLsExportResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_07ca5a18eb6d27f6, []int{17}
+}
+
+func (m *DbLsExportResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DbLsExportResponse.Unmarshal(m, b)
+}
+func (m *DbLsExportResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DbLsExportResponse.Marshal(b, m, deterministic)
+}
+func (m *DbLsExportResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DbLsExportResponse.Merge(m, src)
+}
+func (m *DbLsExportResponse) XXX_Size() int {
+	return xxx_messageInfo_DbLsExportResponse.Size(m)
+}
+func (m *DbLsExportResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DbLsExportResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DbLsExportResponse proto.InternalMessageInfo
+
+func (m *DbLsExportResponse) GetResult() string {
+	if m != nil {
+		return m.Result
+	}
+	return ""
+}
+
+func (m *DbLsExportResponse) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
 type DbRiGetRequest struct {
+	Instance             string   `protobuf:"bytes,1,opt,name=instance,proto3" json:"instance,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -608,7 +767,7 @@ func (m *DbRiGetRequest) Reset()         { *m = DbRiGetRequest{} }
 func (m *DbRiGetRequest) String() string { return proto.CompactTextString(m) }
 func (*DbRiGetRequest) ProtoMessage()    {}
 func (*DbRiGetRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_07ca5a18eb6d27f6, []int{16}
+	return fileDescriptor_07ca5a18eb6d27f6, []int{18}
 }
 
 func (m *DbRiGetRequest) XXX_Unmarshal(b []byte) error {
@@ -629,6 +788,13 @@ func (m *DbRiGetRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_DbRiGetRequest proto.InternalMessageInfo
 
+func (m *DbRiGetRequest) GetInstance() string {
+	if m != nil {
+		return m.Instance
+	}
+	return ""
+}
+
 type DbRiGetResponse struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -639,7 +805,7 @@ func (m *DbRiGetResponse) Reset()         { *m = DbRiGetResponse{} }
 func (m *DbRiGetResponse) String() string { return proto.CompactTextString(m) }
 func (*DbRiGetResponse) ProtoMessage()    {}
 func (*DbRiGetResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_07ca5a18eb6d27f6, []int{17}
+	return fileDescriptor_07ca5a18eb6d27f6, []int{19}
 }
 
 func (m *DbRiGetResponse) XXX_Unmarshal(b []byte) error {
@@ -663,6 +829,7 @@ var xxx_messageInfo_DbRiGetResponse proto.InternalMessageInfo
 type DbRiMonitorRequest struct {
 	Level                string   `protobuf:"bytes,1,opt,name=level,proto3" json:"level,omitempty"`
 	AddressFamily        string   `protobuf:"bytes,2,opt,name=address_family,json=addressFamily,proto3" json:"address_family,omitempty"`
+	Instance             string   `protobuf:"bytes,3,opt,name=instance,proto3" json:"instance,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -672,7 +839,7 @@ func (m *DbRiMonitorRequest) Reset()         { *m = DbRiMonitorRequest{} }
 func (m *DbRiMonitorRequest) String() string { return proto.CompactTextString(m) }
 func (*DbRiMonitorRequest) ProtoMessage()    {}
 func (*DbRiMonitorRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_07ca5a18eb6d27f6, []int{18}
+	return fileDescriptor_07ca5a18eb6d27f6, []int{20}
 }
 
 func (m *DbRiMonitorRequest) XXX_Unmarshal(b []byte) error {
@@ -707,6 +874,13 @@ func (m *DbRiMonitorRequest) GetAddressFamily() string {
 	return ""
 }
 
+func (m *DbRiMonitorRequest) GetInstance() string {
+	if m != nil {
+		return m.Instance
+	}
+	return ""
+}
+
 type DbRiMonitorResponse struct {
 	Routes               []*Route `protobuf:"bytes,1,rep,name=routes,proto3" json:"routes,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -718,7 +892,7 @@ func (m *DbRiMonitorResponse) Reset()         { *m = DbRiMonitorResponse{} }
 func (m *DbRiMonitorResponse) String() string { return proto.CompactTextString(m) }
 func (*DbRiMonitorResponse) ProtoMessage()    {}
 func (*DbRiMonitorResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_07ca5a18eb6d27f6, []int{19}
+	return fileDescriptor_07ca5a18eb6d27f6, []int{21}
 }
 
 func (m *DbRiMonitorResponse) XXX_Unmarshal(b []byte) error {
@@ -746,369 +920,1796 @@ func (m *DbRiMonitorResponse) GetRoutes() []*Route {
 	return nil
 }
 
-type Adjacency struct {
-	Interface                 string   `protobuf:"bytes,1,opt,name=interface,proto3" json:"interface,omitempty"`
-	NeighborType              string   `protobuf:"bytes,2,opt,name=neighbor_type,json=neighborType,proto3" json:"neighbor_type,omitempty"`
-	NeighborSysid             string   `protobuf:"bytes,3,opt,name=neighbor_sysid,json=neighborSysid,proto3" json:"neighbor_sysid,omitempty"`
-	NeighborExtendedCircuitId uint32   `protobuf:"varint,4,opt,name=neighbor_extended_circuit_id,json=neighborExtendedCircuitId,proto3" json:"neighbor_extended_circuit_id,omitempty"`
-	NeighborSnpa              string   `protobuf:"bytes,5,opt,name=neighbor_snpa,json=neighborSnpa,proto3" json:"neighbor_snpa,omitempty"`
-	Usage                     string   `protobuf:"bytes,6,opt,name=usage,proto3" json:"usage,omitempty"`
-	HoldTimer                 uint32   `protobuf:"varint,7,opt,name=hold_timer,json=holdTimer,proto3" json:"hold_timer,omitempty"`
-	NeighborPriority          uint32   `protobuf:"varint,8,opt,name=neighbor_priority,json=neighborPriority,proto3" json:"neighbor_priority,omitempty"`
-	Lastuptime                uint32   `protobuf:"varint,9,opt,name=lastuptime,proto3" json:"lastuptime,omitempty"`
-	State                     string   `protobuf:"bytes,10,opt,name=state,proto3" json:"state,omitempty"`
-	XXX_NoUnkeyedLiteral      struct{} `json:"-"`
-	XXX_unrecognized          []byte   `json:"-"`
-	XXX_sizecache             int32    `json:"-"`
+type TraceSetRequest struct {
+	Enable               bool     `protobuf:"varint,1,opt,name=enable,proto3" json:"enable,omitempty"`
+	Interfaces           []string `protobuf:"bytes,2,rep,name=interfaces,proto3" json:"interfaces,omitempty"`
+	Directions           []string `protobuf:"bytes,3,rep,name=directions,proto3" json:"directions,omitempty"`
+	PduTypes             []string `protobuf:"bytes,4,rep,name=pdu_types,json=pduTypes,proto3" json:"pdu_types,omitempty"`
+	Instance             string   `protobuf:"bytes,5,opt,name=instance,proto3" json:"instance,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *Adjacency) Reset()         { *m = Adjacency{} }
-func (m *Adjacency) String() string { return proto.CompactTextString(m) }
-func (*Adjacency) ProtoMessage()    {}
-func (*Adjacency) Descriptor() ([]byte, []int) {
-	return fileDescriptor_07ca5a18eb6d27f6, []int{20}
+func (m *TraceSetRequest) Reset()         { *m = TraceSetRequest{} }
+func (m *TraceSetRequest) String() string { return proto.CompactTextString(m) }
+func (*TraceSetRequest) ProtoMessage()    {}
+func (*TraceSetRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_07ca5a18eb6d27f6, []int{22}
 }
 
-func (m *Adjacency) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Adjacency.Unmarshal(m, b)
+func (m *TraceSetRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TraceSetRequest.Unmarshal(m, b)
 }
-func (m *Adjacency) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Adjacency.Marshal(b, m, deterministic)
+func (m *TraceSetRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TraceSetRequest.Marshal(b, m, deterministic)
 }
-func (m *Adjacency) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Adjacency.Merge(m, src)
+func (m *TraceSetRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TraceSetRequest.Merge(m, src)
 }
-func (m *Adjacency) XXX_Size() int {
-	return xxx_messageInfo_Adjacency.Size(m)
+func (m *TraceSetRequest) XXX_Size() int {
+	return xxx_messageInfo_TraceSetRequest.Size(m)
 }
-func (m *Adjacency) XXX_DiscardUnknown() {
-	xxx_messageInfo_Adjacency.DiscardUnknown(m)
+func (m *TraceSetRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TraceSetRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Adjacency proto.InternalMessageInfo
+var xxx_messageInfo_TraceSetRequest proto.InternalMessageInfo
 
-func (m *Adjacency) GetInterface() string {
+func (m *TraceSetRequest) GetEnable() bool {
 	if m != nil {
-		return m.Interface
+		return m.Enable
 	}
-	return ""
+	return false
 }
 
-func (m *Adjacency) GetNeighborType() string {
+func (m *TraceSetRequest) GetInterfaces() []string {
 	if m != nil {
-		return m.NeighborType
+		return m.Interfaces
 	}
-	return ""
+	return nil
 }
 
-func (m *Adjacency) GetNeighborSysid() string {
+func (m *TraceSetRequest) GetDirections() []string {
 	if m != nil {
-		return m.NeighborSysid
+		return m.Directions
 	}
-	return ""
+	return nil
 }
 
-func (m *Adjacency) GetNeighborExtendedCircuitId() uint32 {
+func (m *TraceSetRequest) GetPduTypes() []string {
 	if m != nil {
-		return m.NeighborExtendedCircuitId
+		return m.PduTypes
 	}
-	return 0
+	return nil
 }
 
-func (m *Adjacency) GetNeighborSnpa() string {
+func (m *TraceSetRequest) GetInstance() string {
 	if m != nil {
-		return m.NeighborSnpa
+		return m.Instance
 	}
 	return ""
 }
 
-func (m *Adjacency) GetUsage() string {
-	if m != nil {
-		return m.Usage
-	}
-	return ""
+type TraceSetResponse struct {
+	Result               string   `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *Adjacency) GetHoldTimer() uint32 {
-	if m != nil {
-		return m.HoldTimer
-	}
-	return 0
+func (m *TraceSetResponse) Reset()         { *m = TraceSetResponse{} }
+func (m *TraceSetResponse) String() string { return proto.CompactTextString(m) }
+func (*TraceSetResponse) ProtoMessage()    {}
+func (*TraceSetResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_07ca5a18eb6d27f6, []int{23}
 }
 
-func (m *Adjacency) GetNeighborPriority() uint32 {
-	if m != nil {
-		return m.NeighborPriority
-	}
-	return 0
+func (m *TraceSetResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TraceSetResponse.Unmarshal(m, b)
 }
-
-func (m *Adjacency) GetLastuptime() uint32 {
-	if m != nil {
-		return m.Lastuptime
-	}
-	return 0
+func (m *TraceSetResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TraceSetResponse.Marshal(b, m, deterministic)
+}
+func (m *TraceSetResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TraceSetResponse.Merge(m, src)
+}
+func (m *TraceSetResponse) XXX_Size() int {
+	return xxx_messageInfo_TraceSetResponse.Size(m)
+}
+func (m *TraceSetResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TraceSetResponse.DiscardUnknown(m)
 }
 
-func (m *Adjacency) GetState() string {
+var xxx_messageInfo_TraceSetResponse proto.InternalMessageInfo
+
+func (m *TraceSetResponse) GetResult() string {
 	if m != nil {
-		return m.State
+		return m.Result
 	}
 	return ""
 }
 
-type Lsp struct {
-	Level                string              `protobuf:"bytes,1,opt,name=level,proto3" json:"level,omitempty"`
-	DecodedCompleted     bool                `protobuf:"varint,2,opt,name=decoded_completed,json=decodedCompleted,proto3" json:"decoded_completed,omitempty"`
-	RawData              string              `protobuf:"bytes,3,opt,name=raw_data,json=rawData,proto3" json:"raw_data,omitempty"`
-	LspId                string              `protobuf:"bytes,4,opt,name=lsp_id,json=lspId,proto3" json:"lsp_id,omitempty"`
-	Checksum             uint32              `protobuf:"varint,5,opt,name=checksum,proto3" json:"checksum,omitempty"`
-	RemainingLifetime    uint32              `protobuf:"varint,6,opt,name=remaining_lifetime,json=remainingLifetime,proto3" json:"remaining_lifetime,omitempty"`
-	Sequence             uint32              `protobuf:"varint,7,opt,name=sequence,proto3" json:"sequence,omitempty"`
-	Attributes           uint32              `protobuf:"varint,8,opt,name=attributes,proto3" json:"attributes,omitempty"`
-	Ipv4Addresses        []string            `protobuf:"bytes,9,rep,name=ipv4_addresses,json=ipv4Addresses,proto3" json:"ipv4_addresses,omitempty"`
-	Ipv6Addresses        []string            `protobuf:"bytes,10,rep,name=ipv6_addresses,json=ipv6Addresses,proto3" json:"ipv6_addresses,omitempty"`
-	Ipv4TeRouterid       string              `protobuf:"bytes,11,opt,name=ipv4_te_routerid,json=ipv4TeRouterid,proto3" json:"ipv4_te_routerid,omitempty"`
-	Ipv6TeRouterid       string              `protobuf:"bytes,12,opt,name=ipv6_te_routerid,json=ipv6TeRouterid,proto3" json:"ipv6_te_routerid,omitempty"`
-	ProtocolSupporteds   []uint32            `protobuf:"varint,13,rep,packed,name=protocol_supporteds,json=protocolSupporteds,proto3" json:"protocol_supporteds,omitempty"`
-	DynamicHostname      string              `protobuf:"bytes,14,opt,name=dynamic_hostname,json=dynamicHostname,proto3" json:"dynamic_hostname,omitempty"`
-	Authentication       *Authentication     `protobuf:"bytes,15,opt,name=authentication,proto3" json:"authentication,omitempty"`
-	MtEntries            *MtEntries          `protobuf:"bytes,16,opt,name=mt_entries,json=mtEntries,proto3" json:"mt_entries,omitempty"`
-	RouterCapabilities   *RouterCapabilities `protobuf:"bytes,17,opt,name=router_capabilities,json=routerCapabilities,proto3" json:"router_capabilities,omitempty"`
-	NodeTags             *NodeTags           `protobuf:"bytes,18,opt,name=node_tags,json=nodeTags,proto3" json:"node_tags,omitempty"`
-	Binary               []byte              `protobuf:"bytes,19,opt,name=binary,proto3" json:"binary,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
-	XXX_unrecognized     []byte              `json:"-"`
-	XXX_sizecache        int32               `json:"-"`
+type TopologyGetRequest struct {
+	Level                string   `protobuf:"bytes,1,opt,name=level,proto3" json:"level,omitempty"`
+	Instance             string   `protobuf:"bytes,2,opt,name=instance,proto3" json:"instance,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *Lsp) Reset()         { *m = Lsp{} }
-func (m *Lsp) String() string { return proto.CompactTextString(m) }
-func (*Lsp) ProtoMessage()    {}
-func (*Lsp) Descriptor() ([]byte, []int) {
-	return fileDescriptor_07ca5a18eb6d27f6, []int{21}
+func (m *TopologyGetRequest) Reset()         { *m = TopologyGetRequest{} }
+func (m *TopologyGetRequest) String() string { return proto.CompactTextString(m) }
+func (*TopologyGetRequest) ProtoMessage()    {}
+func (*TopologyGetRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_07ca5a18eb6d27f6, []int{24}
 }
 
-func (m *Lsp) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Lsp.Unmarshal(m, b)
+func (m *TopologyGetRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TopologyGetRequest.Unmarshal(m, b)
 }
-func (m *Lsp) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Lsp.Marshal(b, m, deterministic)
+func (m *TopologyGetRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TopologyGetRequest.Marshal(b, m, deterministic)
 }
-func (m *Lsp) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Lsp.Merge(m, src)
+func (m *TopologyGetRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TopologyGetRequest.Merge(m, src)
 }
-func (m *Lsp) XXX_Size() int {
-	return xxx_messageInfo_Lsp.Size(m)
+func (m *TopologyGetRequest) XXX_Size() int {
+	return xxx_messageInfo_TopologyGetRequest.Size(m)
 }
-func (m *Lsp) XXX_DiscardUnknown() {
-	xxx_messageInfo_Lsp.DiscardUnknown(m)
+func (m *TopologyGetRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TopologyGetRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Lsp proto.InternalMessageInfo
+var xxx_messageInfo_TopologyGetRequest proto.InternalMessageInfo
 
-func (m *Lsp) GetLevel() string {
+func (m *TopologyGetRequest) GetLevel() string {
 	if m != nil {
 		return m.Level
 	}
 	return ""
 }
 
-func (m *Lsp) GetDecodedCompleted() bool {
-	if m != nil {
-		return m.DecodedCompleted
-	}
-	return false
-}
-
-func (m *Lsp) GetRawData() string {
+func (m *TopologyGetRequest) GetInstance() string {
 	if m != nil {
-		return m.RawData
+		return m.Instance
 	}
 	return ""
 }
 
-func (m *Lsp) GetLspId() string {
-	if m != nil {
-		return m.LspId
-	}
-	return ""
+type TopologyGetResponse struct {
+	Result               string           `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	Graphs               []*TopologyGraph `protobuf:"bytes,2,rep,name=graphs,proto3" json:"graphs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *TopologyGetResponse) Reset()         { *m = TopologyGetResponse{} }
+func (m *TopologyGetResponse) String() string { return proto.CompactTextString(m) }
+func (*TopologyGetResponse) ProtoMessage()    {}
+func (*TopologyGetResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_07ca5a18eb6d27f6, []int{25}
+}
+
+func (m *TopologyGetResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TopologyGetResponse.Unmarshal(m, b)
+}
+func (m *TopologyGetResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TopologyGetResponse.Marshal(b, m, deterministic)
+}
+func (m *TopologyGetResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TopologyGetResponse.Merge(m, src)
+}
+func (m *TopologyGetResponse) XXX_Size() int {
+	return xxx_messageInfo_TopologyGetResponse.Size(m)
+}
+func (m *TopologyGetResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TopologyGetResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TopologyGetResponse proto.InternalMessageInfo
+
+func (m *TopologyGetResponse) GetResult() string {
+	if m != nil {
+		return m.Result
+	}
+	return ""
+}
+
+func (m *TopologyGetResponse) GetGraphs() []*TopologyGraph {
+	if m != nil {
+		return m.Graphs
+	}
+	return nil
+}
+
+type InstanceListRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *InstanceListRequest) Reset()         { *m = InstanceListRequest{} }
+func (m *InstanceListRequest) String() string { return proto.CompactTextString(m) }
+func (*InstanceListRequest) ProtoMessage()    {}
+func (*InstanceListRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_07ca5a18eb6d27f6, []int{26}
+}
+
+func (m *InstanceListRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InstanceListRequest.Unmarshal(m, b)
+}
+func (m *InstanceListRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InstanceListRequest.Marshal(b, m, deterministic)
+}
+func (m *InstanceListRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InstanceListRequest.Merge(m, src)
+}
+func (m *InstanceListRequest) XXX_Size() int {
+	return xxx_messageInfo_InstanceListRequest.Size(m)
+}
+func (m *InstanceListRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_InstanceListRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InstanceListRequest proto.InternalMessageInfo
+
+type InstanceListResponse struct {
+	Instances            []*Instance `protobuf:"bytes,1,rep,name=instances,proto3" json:"instances,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *InstanceListResponse) Reset()         { *m = InstanceListResponse{} }
+func (m *InstanceListResponse) String() string { return proto.CompactTextString(m) }
+func (*InstanceListResponse) ProtoMessage()    {}
+func (*InstanceListResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_07ca5a18eb6d27f6, []int{27}
+}
+
+func (m *InstanceListResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InstanceListResponse.Unmarshal(m, b)
+}
+func (m *InstanceListResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InstanceListResponse.Marshal(b, m, deterministic)
+}
+func (m *InstanceListResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InstanceListResponse.Merge(m, src)
+}
+func (m *InstanceListResponse) XXX_Size() int {
+	return xxx_messageInfo_InstanceListResponse.Size(m)
+}
+func (m *InstanceListResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_InstanceListResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InstanceListResponse proto.InternalMessageInfo
+
+func (m *InstanceListResponse) GetInstances() []*Instance {
+	if m != nil {
+		return m.Instances
+	}
+	return nil
+}
+
+type PolicyGetRequest struct {
+	Instance             string   `protobuf:"bytes,1,opt,name=instance,proto3" json:"instance,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PolicyGetRequest) Reset()         { *m = PolicyGetRequest{} }
+func (m *PolicyGetRequest) String() string { return proto.CompactTextString(m) }
+func (*PolicyGetRequest) ProtoMessage()    {}
+func (*PolicyGetRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_07ca5a18eb6d27f6, []int{28}
+}
+
+func (m *PolicyGetRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PolicyGetRequest.Unmarshal(m, b)
+}
+func (m *PolicyGetRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PolicyGetRequest.Marshal(b, m, deterministic)
+}
+func (m *PolicyGetRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PolicyGetRequest.Merge(m, src)
+}
+func (m *PolicyGetRequest) XXX_Size() int {
+	return xxx_messageInfo_PolicyGetRequest.Size(m)
+}
+func (m *PolicyGetRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PolicyGetRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PolicyGetRequest proto.InternalMessageInfo
+
+func (m *PolicyGetRequest) GetInstance() string {
+	if m != nil {
+		return m.Instance
+	}
+	return ""
+}
+
+type PolicyGetResponse struct {
+	Result               string            `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	PrefixSets           []*PrefixSet      `protobuf:"bytes,2,rep,name=prefix_sets,json=prefixSets,proto3" json:"prefix_sets,omitempty"`
+	TagSets              []*TagSet         `protobuf:"bytes,3,rep,name=tag_sets,json=tagSets,proto3" json:"tag_sets,omitempty"`
+	Policies             []*Policy         `protobuf:"bytes,4,rep,name=policies,proto3" json:"policies,omitempty"`
+	Assignment           *PolicyAssignment `protobuf:"bytes,5,opt,name=assignment,proto3" json:"assignment,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *PolicyGetResponse) Reset()         { *m = PolicyGetResponse{} }
+func (m *PolicyGetResponse) String() string { return proto.CompactTextString(m) }
+func (*PolicyGetResponse) ProtoMessage()    {}
+func (*PolicyGetResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_07ca5a18eb6d27f6, []int{29}
+}
+
+func (m *PolicyGetResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PolicyGetResponse.Unmarshal(m, b)
+}
+func (m *PolicyGetResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PolicyGetResponse.Marshal(b, m, deterministic)
+}
+func (m *PolicyGetResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PolicyGetResponse.Merge(m, src)
+}
+func (m *PolicyGetResponse) XXX_Size() int {
+	return xxx_messageInfo_PolicyGetResponse.Size(m)
+}
+func (m *PolicyGetResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PolicyGetResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PolicyGetResponse proto.InternalMessageInfo
+
+func (m *PolicyGetResponse) GetResult() string {
+	if m != nil {
+		return m.Result
+	}
+	return ""
+}
+
+func (m *PolicyGetResponse) GetPrefixSets() []*PrefixSet {
+	if m != nil {
+		return m.PrefixSets
+	}
+	return nil
+}
+
+func (m *PolicyGetResponse) GetTagSets() []*TagSet {
+	if m != nil {
+		return m.TagSets
+	}
+	return nil
+}
+
+func (m *PolicyGetResponse) GetPolicies() []*Policy {
+	if m != nil {
+		return m.Policies
+	}
+	return nil
+}
+
+func (m *PolicyGetResponse) GetAssignment() *PolicyAssignment {
+	if m != nil {
+		return m.Assignment
+	}
+	return nil
+}
+
+type DefinedSetAddRequest struct {
+	PrefixSets           []*PrefixSet `protobuf:"bytes,1,rep,name=prefix_sets,json=prefixSets,proto3" json:"prefix_sets,omitempty"`
+	TagSets              []*TagSet    `protobuf:"bytes,2,rep,name=tag_sets,json=tagSets,proto3" json:"tag_sets,omitempty"`
+	Instance             string       `protobuf:"bytes,3,opt,name=instance,proto3" json:"instance,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *DefinedSetAddRequest) Reset()         { *m = DefinedSetAddRequest{} }
+func (m *DefinedSetAddRequest) String() string { return proto.CompactTextString(m) }
+func (*DefinedSetAddRequest) ProtoMessage()    {}
+func (*DefinedSetAddRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_07ca5a18eb6d27f6, []int{30}
+}
+
+func (m *DefinedSetAddRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DefinedSetAddRequest.Unmarshal(m, b)
+}
+func (m *DefinedSetAddRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DefinedSetAddRequest.Marshal(b, m, deterministic)
+}
+func (m *DefinedSetAddRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DefinedSetAddRequest.Merge(m, src)
+}
+func (m *DefinedSetAddRequest) XXX_Size() int {
+	return xxx_messageInfo_DefinedSetAddRequest.Size(m)
+}
+func (m *DefinedSetAddRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DefinedSetAddRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DefinedSetAddRequest proto.InternalMessageInfo
+
+func (m *DefinedSetAddRequest) GetPrefixSets() []*PrefixSet {
+	if m != nil {
+		return m.PrefixSets
+	}
+	return nil
+}
+
+func (m *DefinedSetAddRequest) GetTagSets() []*TagSet {
+	if m != nil {
+		return m.TagSets
+	}
+	return nil
+}
+
+func (m *DefinedSetAddRequest) GetInstance() string {
+	if m != nil {
+		return m.Instance
+	}
+	return ""
+}
+
+type DefinedSetAddResponse struct {
+	Result               string   `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DefinedSetAddResponse) Reset()         { *m = DefinedSetAddResponse{} }
+func (m *DefinedSetAddResponse) String() string { return proto.CompactTextString(m) }
+func (*DefinedSetAddResponse) ProtoMessage()    {}
+func (*DefinedSetAddResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_07ca5a18eb6d27f6, []int{31}
+}
+
+func (m *DefinedSetAddResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DefinedSetAddResponse.Unmarshal(m, b)
+}
+func (m *DefinedSetAddResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DefinedSetAddResponse.Marshal(b, m, deterministic)
+}
+func (m *DefinedSetAddResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DefinedSetAddResponse.Merge(m, src)
+}
+func (m *DefinedSetAddResponse) XXX_Size() int {
+	return xxx_messageInfo_DefinedSetAddResponse.Size(m)
+}
+func (m *DefinedSetAddResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DefinedSetAddResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DefinedSetAddResponse proto.InternalMessageInfo
+
+func (m *DefinedSetAddResponse) GetResult() string {
+	if m != nil {
+		return m.Result
+	}
+	return ""
+}
+
+type DefinedSetDeleteRequest struct {
+	PrefixSets           []string `protobuf:"bytes,1,rep,name=prefix_sets,json=prefixSets,proto3" json:"prefix_sets,omitempty"`
+	TagSets              []string `protobuf:"bytes,2,rep,name=tag_sets,json=tagSets,proto3" json:"tag_sets,omitempty"`
+	Instance             string   `protobuf:"bytes,3,opt,name=instance,proto3" json:"instance,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DefinedSetDeleteRequest) Reset()         { *m = DefinedSetDeleteRequest{} }
+func (m *DefinedSetDeleteRequest) String() string { return proto.CompactTextString(m) }
+func (*DefinedSetDeleteRequest) ProtoMessage()    {}
+func (*DefinedSetDeleteRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_07ca5a18eb6d27f6, []int{32}
+}
+
+func (m *DefinedSetDeleteRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DefinedSetDeleteRequest.Unmarshal(m, b)
+}
+func (m *DefinedSetDeleteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DefinedSetDeleteRequest.Marshal(b, m, deterministic)
+}
+func (m *DefinedSetDeleteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DefinedSetDeleteRequest.Merge(m, src)
+}
+func (m *DefinedSetDeleteRequest) XXX_Size() int {
+	return xxx_messageInfo_DefinedSetDeleteRequest.Size(m)
+}
+func (m *DefinedSetDeleteRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DefinedSetDeleteRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DefinedSetDeleteRequest proto.InternalMessageInfo
+
+func (m *DefinedSetDeleteRequest) GetPrefixSets() []string {
+	if m != nil {
+		return m.PrefixSets
+	}
+	return nil
+}
+
+func (m *DefinedSetDeleteRequest) GetTagSets() []string {
+	if m != nil {
+		return m.TagSets
+	}
+	return nil
+}
+
+func (m *DefinedSetDeleteRequest) GetInstance() string {
+	if m != nil {
+		return m.Instance
+	}
+	return ""
+}
+
+type DefinedSetDeleteResponse struct {
+	Result               string   `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DefinedSetDeleteResponse) Reset()         { *m = DefinedSetDeleteResponse{} }
+func (m *DefinedSetDeleteResponse) String() string { return proto.CompactTextString(m) }
+func (*DefinedSetDeleteResponse) ProtoMessage()    {}
+func (*DefinedSetDeleteResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_07ca5a18eb6d27f6, []int{33}
+}
+
+func (m *DefinedSetDeleteResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DefinedSetDeleteResponse.Unmarshal(m, b)
+}
+func (m *DefinedSetDeleteResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DefinedSetDeleteResponse.Marshal(b, m, deterministic)
+}
+func (m *DefinedSetDeleteResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DefinedSetDeleteResponse.Merge(m, src)
+}
+func (m *DefinedSetDeleteResponse) XXX_Size() int {
+	return xxx_messageInfo_DefinedSetDeleteResponse.Size(m)
+}
+func (m *DefinedSetDeleteResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DefinedSetDeleteResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DefinedSetDeleteResponse proto.InternalMessageInfo
+
+func (m *DefinedSetDeleteResponse) GetResult() string {
+	if m != nil {
+		return m.Result
+	}
+	return ""
+}
+
+type PolicyAddRequest struct {
+	Policies             []*Policy `protobuf:"bytes,1,rep,name=policies,proto3" json:"policies,omitempty"`
+	Instance             string    `protobuf:"bytes,2,opt,name=instance,proto3" json:"instance,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *PolicyAddRequest) Reset()         { *m = PolicyAddRequest{} }
+func (m *PolicyAddRequest) String() string { return proto.CompactTextString(m) }
+func (*PolicyAddRequest) ProtoMessage()    {}
+func (*PolicyAddRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_07ca5a18eb6d27f6, []int{34}
+}
+
+func (m *PolicyAddRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PolicyAddRequest.Unmarshal(m, b)
+}
+func (m *PolicyAddRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PolicyAddRequest.Marshal(b, m, deterministic)
+}
+func (m *PolicyAddRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PolicyAddRequest.Merge(m, src)
+}
+func (m *PolicyAddRequest) XXX_Size() int {
+	return xxx_messageInfo_PolicyAddRequest.Size(m)
+}
+func (m *PolicyAddRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PolicyAddRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PolicyAddRequest proto.InternalMessageInfo
+
+func (m *PolicyAddRequest) GetPolicies() []*Policy {
+	if m != nil {
+		return m.Policies
+	}
+	return nil
+}
+
+func (m *PolicyAddRequest) GetInstance() string {
+	if m != nil {
+		return m.Instance
+	}
+	return ""
+}
+
+type PolicyAddResponse struct {
+	Result               string   `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PolicyAddResponse) Reset()         { *m = PolicyAddResponse{} }
+func (m *PolicyAddResponse) String() string { return proto.CompactTextString(m) }
+func (*PolicyAddResponse) ProtoMessage()    {}
+func (*PolicyAddResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_07ca5a18eb6d27f6, []int{35}
+}
+
+func (m *PolicyAddResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PolicyAddResponse.Unmarshal(m, b)
+}
+func (m *PolicyAddResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PolicyAddResponse.Marshal(b, m, deterministic)
+}
+func (m *PolicyAddResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PolicyAddResponse.Merge(m, src)
+}
+func (m *PolicyAddResponse) XXX_Size() int {
+	return xxx_messageInfo_PolicyAddResponse.Size(m)
+}
+func (m *PolicyAddResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PolicyAddResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PolicyAddResponse proto.InternalMessageInfo
+
+func (m *PolicyAddResponse) GetResult() string {
+	if m != nil {
+		return m.Result
+	}
+	return ""
+}
+
+type PolicyDeleteRequest struct {
+	Policies             []string `protobuf:"bytes,1,rep,name=policies,proto3" json:"policies,omitempty"`
+	Instance             string   `protobuf:"bytes,2,opt,name=instance,proto3" json:"instance,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PolicyDeleteRequest) Reset()         { *m = PolicyDeleteRequest{} }
+func (m *PolicyDeleteRequest) String() string { return proto.CompactTextString(m) }
+func (*PolicyDeleteRequest) ProtoMessage()    {}
+func (*PolicyDeleteRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_07ca5a18eb6d27f6, []int{36}
+}
+
+func (m *PolicyDeleteRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PolicyDeleteRequest.Unmarshal(m, b)
+}
+func (m *PolicyDeleteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PolicyDeleteRequest.Marshal(b, m, deterministic)
+}
+func (m *PolicyDeleteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PolicyDeleteRequest.Merge(m, src)
+}
+func (m *PolicyDeleteRequest) XXX_Size() int {
+	return xxx_messageInfo_PolicyDeleteRequest.Size(m)
+}
+func (m *PolicyDeleteRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PolicyDeleteRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PolicyDeleteRequest proto.InternalMessageInfo
+
+func (m *PolicyDeleteRequest) GetPolicies() []string {
+	if m != nil {
+		return m.Policies
+	}
+	return nil
+}
+
+func (m *PolicyDeleteRequest) GetInstance() string {
+	if m != nil {
+		return m.Instance
+	}
+	return ""
+}
+
+type PolicyDeleteResponse struct {
+	Result               string   `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PolicyDeleteResponse) Reset()         { *m = PolicyDeleteResponse{} }
+func (m *PolicyDeleteResponse) String() string { return proto.CompactTextString(m) }
+func (*PolicyDeleteResponse) ProtoMessage()    {}
+func (*PolicyDeleteResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_07ca5a18eb6d27f6, []int{37}
+}
+
+func (m *PolicyDeleteResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PolicyDeleteResponse.Unmarshal(m, b)
+}
+func (m *PolicyDeleteResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PolicyDeleteResponse.Marshal(b, m, deterministic)
+}
+func (m *PolicyDeleteResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PolicyDeleteResponse.Merge(m, src)
+}
+func (m *PolicyDeleteResponse) XXX_Size() int {
+	return xxx_messageInfo_PolicyDeleteResponse.Size(m)
+}
+func (m *PolicyDeleteResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PolicyDeleteResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PolicyDeleteResponse proto.InternalMessageInfo
+
+func (m *PolicyDeleteResponse) GetResult() string {
+	if m != nil {
+		return m.Result
+	}
+	return ""
+}
+
+type PolicyAssignmentSetRequest struct {
+	Assignment           *PolicyAssignment `protobuf:"bytes,1,opt,name=assignment,proto3" json:"assignment,omitempty"`
+	Instance             string            `protobuf:"bytes,2,opt,name=instance,proto3" json:"instance,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *PolicyAssignmentSetRequest) Reset()         { *m = PolicyAssignmentSetRequest{} }
+func (m *PolicyAssignmentSetRequest) String() string { return proto.CompactTextString(m) }
+func (*PolicyAssignmentSetRequest) ProtoMessage()    {}
+func (*PolicyAssignmentSetRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_07ca5a18eb6d27f6, []int{38}
+}
+
+func (m *PolicyAssignmentSetRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PolicyAssignmentSetRequest.Unmarshal(m, b)
+}
+func (m *PolicyAssignmentSetRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PolicyAssignmentSetRequest.Marshal(b, m, deterministic)
+}
+func (m *PolicyAssignmentSetRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PolicyAssignmentSetRequest.Merge(m, src)
+}
+func (m *PolicyAssignmentSetRequest) XXX_Size() int {
+	return xxx_messageInfo_PolicyAssignmentSetRequest.Size(m)
+}
+func (m *PolicyAssignmentSetRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PolicyAssignmentSetRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PolicyAssignmentSetRequest proto.InternalMessageInfo
+
+func (m *PolicyAssignmentSetRequest) GetAssignment() *PolicyAssignment {
+	if m != nil {
+		return m.Assignment
+	}
+	return nil
+}
+
+func (m *PolicyAssignmentSetRequest) GetInstance() string {
+	if m != nil {
+		return m.Instance
+	}
+	return ""
+}
+
+type PolicyAssignmentSetResponse struct {
+	Result               string   `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PolicyAssignmentSetResponse) Reset()         { *m = PolicyAssignmentSetResponse{} }
+func (m *PolicyAssignmentSetResponse) String() string { return proto.CompactTextString(m) }
+func (*PolicyAssignmentSetResponse) ProtoMessage()    {}
+func (*PolicyAssignmentSetResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_07ca5a18eb6d27f6, []int{39}
+}
+
+func (m *PolicyAssignmentSetResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PolicyAssignmentSetResponse.Unmarshal(m, b)
+}
+func (m *PolicyAssignmentSetResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PolicyAssignmentSetResponse.Marshal(b, m, deterministic)
+}
+func (m *PolicyAssignmentSetResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PolicyAssignmentSetResponse.Merge(m, src)
+}
+func (m *PolicyAssignmentSetResponse) XXX_Size() int {
+	return xxx_messageInfo_PolicyAssignmentSetResponse.Size(m)
+}
+func (m *PolicyAssignmentSetResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PolicyAssignmentSetResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PolicyAssignmentSetResponse proto.InternalMessageInfo
+
+func (m *PolicyAssignmentSetResponse) GetResult() string {
+	if m != nil {
+		return m.Result
+	}
+	return ""
+}
+
+type Adjacency struct {
+	Interface                 string   `protobuf:"bytes,1,opt,name=interface,proto3" json:"interface,omitempty"`
+	NeighborType              string   `protobuf:"bytes,2,opt,name=neighbor_type,json=neighborType,proto3" json:"neighbor_type,omitempty"`
+	NeighborSysid             string   `protobuf:"bytes,3,opt,name=neighbor_sysid,json=neighborSysid,proto3" json:"neighbor_sysid,omitempty"`
+	NeighborExtendedCircuitId uint32   `protobuf:"varint,4,opt,name=neighbor_extended_circuit_id,json=neighborExtendedCircuitId,proto3" json:"neighbor_extended_circuit_id,omitempty"`
+	NeighborSnpa              string   `protobuf:"bytes,5,opt,name=neighbor_snpa,json=neighborSnpa,proto3" json:"neighbor_snpa,omitempty"`
+	Usage                     string   `protobuf:"bytes,6,opt,name=usage,proto3" json:"usage,omitempty"`
+	HoldTimer                 uint32   `protobuf:"varint,7,opt,name=hold_timer,json=holdTimer,proto3" json:"hold_timer,omitempty"`
+	NeighborPriority          uint32   `protobuf:"varint,8,opt,name=neighbor_priority,json=neighborPriority,proto3" json:"neighbor_priority,omitempty"`
+	Lastuptime                uint32   `protobuf:"varint,9,opt,name=lastuptime,proto3" json:"lastuptime,omitempty"`
+	State                     string   `protobuf:"bytes,10,opt,name=state,proto3" json:"state,omitempty"`
+	XXX_NoUnkeyedLiteral      struct{} `json:"-"`
+	XXX_unrecognized          []byte   `json:"-"`
+	XXX_sizecache             int32    `json:"-"`
+}
+
+func (m *Adjacency) Reset()         { *m = Adjacency{} }
+func (m *Adjacency) String() string { return proto.CompactTextString(m) }
+func (*Adjacency) ProtoMessage()    {}
+func (*Adjacency) Descriptor() ([]byte, []int) {
+	return fileDescriptor_07ca5a18eb6d27f6, []int{40}
+}
+
+func (m *Adjacency) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Adjacency.Unmarshal(m, b)
+}
+func (m *Adjacency) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Adjacency.Marshal(b, m, deterministic)
+}
+func (m *Adjacency) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Adjacency.Merge(m, src)
+}
+func (m *Adjacency) XXX_Size() int {
+	return xxx_messageInfo_Adjacency.Size(m)
+}
+func (m *Adjacency) XXX_DiscardUnknown() {
+	xxx_messageInfo_Adjacency.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Adjacency proto.InternalMessageInfo
+
+func (m *Adjacency) GetInterface() string {
+	if m != nil {
+		return m.Interface
+	}
+	return ""
+}
+
+func (m *Adjacency) GetNeighborType() string {
+	if m != nil {
+		return m.NeighborType
+	}
+	return ""
+}
+
+func (m *Adjacency) GetNeighborSysid() string {
+	if m != nil {
+		return m.NeighborSysid
+	}
+	return ""
+}
+
+func (m *Adjacency) GetNeighborExtendedCircuitId() uint32 {
+	if m != nil {
+		return m.NeighborExtendedCircuitId
+	}
+	return 0
+}
+
+func (m *Adjacency) GetNeighborSnpa() string {
+	if m != nil {
+		return m.NeighborSnpa
+	}
+	return ""
+}
+
+func (m *Adjacency) GetUsage() string {
+	if m != nil {
+		return m.Usage
+	}
+	return ""
+}
+
+func (m *Adjacency) GetHoldTimer() uint32 {
+	if m != nil {
+		return m.HoldTimer
+	}
+	return 0
+}
+
+func (m *Adjacency) GetNeighborPriority() uint32 {
+	if m != nil {
+		return m.NeighborPriority
+	}
+	return 0
+}
+
+func (m *Adjacency) GetLastuptime() uint32 {
+	if m != nil {
+		return m.Lastuptime
+	}
+	return 0
+}
+
+func (m *Adjacency) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+type Lsp struct {
+	Level                string              `protobuf:"bytes,1,opt,name=level,proto3" json:"level,omitempty"`
+	DecodedCompleted     bool                `protobuf:"varint,2,opt,name=decoded_completed,json=decodedCompleted,proto3" json:"decoded_completed,omitempty"`
+	RawData              string              `protobuf:"bytes,3,opt,name=raw_data,json=rawData,proto3" json:"raw_data,omitempty"`
+	LspId                string              `protobuf:"bytes,4,opt,name=lsp_id,json=lspId,proto3" json:"lsp_id,omitempty"`
+	Checksum             uint32              `protobuf:"varint,5,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	RemainingLifetime    uint32              `protobuf:"varint,6,opt,name=remaining_lifetime,json=remainingLifetime,proto3" json:"remaining_lifetime,omitempty"`
+	Sequence             uint32              `protobuf:"varint,7,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Attributes           uint32              `protobuf:"varint,8,opt,name=attributes,proto3" json:"attributes,omitempty"`
+	Ipv4Addresses        []string            `protobuf:"bytes,9,rep,name=ipv4_addresses,json=ipv4Addresses,proto3" json:"ipv4_addresses,omitempty"`
+	Ipv6Addresses        []string            `protobuf:"bytes,10,rep,name=ipv6_addresses,json=ipv6Addresses,proto3" json:"ipv6_addresses,omitempty"`
+	Ipv4TeRouterid       string              `protobuf:"bytes,11,opt,name=ipv4_te_routerid,json=ipv4TeRouterid,proto3" json:"ipv4_te_routerid,omitempty"`
+	Ipv6TeRouterid       string              `protobuf:"bytes,12,opt,name=ipv6_te_routerid,json=ipv6TeRouterid,proto3" json:"ipv6_te_routerid,omitempty"`
+	ProtocolSupporteds   []uint32            `protobuf:"varint,13,rep,packed,name=protocol_supporteds,json=protocolSupporteds,proto3" json:"protocol_supporteds,omitempty"`
+	DynamicHostname      string              `protobuf:"bytes,14,opt,name=dynamic_hostname,json=dynamicHostname,proto3" json:"dynamic_hostname,omitempty"`
+	Authentication       *Authentication     `protobuf:"bytes,15,opt,name=authentication,proto3" json:"authentication,omitempty"`
+	MtEntries            *MtEntries          `protobuf:"bytes,16,opt,name=mt_entries,json=mtEntries,proto3" json:"mt_entries,omitempty"`
+	RouterCapabilities   *RouterCapabilities `protobuf:"bytes,17,opt,name=router_capabilities,json=routerCapabilities,proto3" json:"router_capabilities,omitempty"`
+	NodeTags             *NodeTags           `protobuf:"bytes,18,opt,name=node_tags,json=nodeTags,proto3" json:"node_tags,omitempty"`
+	Binary               []byte              `protobuf:"bytes,19,opt,name=binary,proto3" json:"binary,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *Lsp) Reset()         { *m = Lsp{} }
+func (m *Lsp) String() string { return proto.CompactTextString(m) }
+func (*Lsp) ProtoMessage()    {}
+func (*Lsp) Descriptor() ([]byte, []int) {
+	return fileDescriptor_07ca5a18eb6d27f6, []int{41}
+}
+
+func (m *Lsp) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Lsp.Unmarshal(m, b)
+}
+func (m *Lsp) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Lsp.Marshal(b, m, deterministic)
+}
+func (m *Lsp) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Lsp.Merge(m, src)
+}
+func (m *Lsp) XXX_Size() int {
+	return xxx_messageInfo_Lsp.Size(m)
+}
+func (m *Lsp) XXX_DiscardUnknown() {
+	xxx_messageInfo_Lsp.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Lsp proto.InternalMessageInfo
+
+func (m *Lsp) GetLevel() string {
+	if m != nil {
+		return m.Level
+	}
+	return ""
+}
+
+func (m *Lsp) GetDecodedCompleted() bool {
+	if m != nil {
+		return m.DecodedCompleted
+	}
+	return false
+}
+
+func (m *Lsp) GetRawData() string {
+	if m != nil {
+		return m.RawData
+	}
+	return ""
+}
+
+func (m *Lsp) GetLspId() string {
+	if m != nil {
+		return m.LspId
+	}
+	return ""
 }
 
 func (m *Lsp) GetChecksum() uint32 {
 	if m != nil {
-		return m.Checksum
+		return m.Checksum
+	}
+	return 0
+}
+
+func (m *Lsp) GetRemainingLifetime() uint32 {
+	if m != nil {
+		return m.RemainingLifetime
+	}
+	return 0
+}
+
+func (m *Lsp) GetSequence() uint32 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+func (m *Lsp) GetAttributes() uint32 {
+	if m != nil {
+		return m.Attributes
+	}
+	return 0
+}
+
+func (m *Lsp) GetIpv4Addresses() []string {
+	if m != nil {
+		return m.Ipv4Addresses
+	}
+	return nil
+}
+
+func (m *Lsp) GetIpv6Addresses() []string {
+	if m != nil {
+		return m.Ipv6Addresses
+	}
+	return nil
+}
+
+func (m *Lsp) GetIpv4TeRouterid() string {
+	if m != nil {
+		return m.Ipv4TeRouterid
+	}
+	return ""
+}
+
+func (m *Lsp) GetIpv6TeRouterid() string {
+	if m != nil {
+		return m.Ipv6TeRouterid
+	}
+	return ""
+}
+
+func (m *Lsp) GetProtocolSupporteds() []uint32 {
+	if m != nil {
+		return m.ProtocolSupporteds
+	}
+	return nil
+}
+
+func (m *Lsp) GetDynamicHostname() string {
+	if m != nil {
+		return m.DynamicHostname
+	}
+	return ""
+}
+
+func (m *Lsp) GetAuthentication() *Authentication {
+	if m != nil {
+		return m.Authentication
+	}
+	return nil
+}
+
+func (m *Lsp) GetMtEntries() *MtEntries {
+	if m != nil {
+		return m.MtEntries
+	}
+	return nil
+}
+
+func (m *Lsp) GetRouterCapabilities() *RouterCapabilities {
+	if m != nil {
+		return m.RouterCapabilities
+	}
+	return nil
+}
+
+func (m *Lsp) GetNodeTags() *NodeTags {
+	if m != nil {
+		return m.NodeTags
+	}
+	return nil
+}
+
+func (m *Lsp) GetBinary() []byte {
+	if m != nil {
+		return m.Binary
+	}
+	return nil
+}
+
+type Route struct {
+	Level                string     `protobuf:"bytes,1,opt,name=level,proto3" json:"level,omitempty"`
+	AddressFamily        string     `protobuf:"bytes,2,opt,name=address_family,json=addressFamily,proto3" json:"address_family,omitempty"`
+	Prefix               string     `protobuf:"bytes,3,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	NextHops             []*NextHop `protobuf:"bytes,4,rep,name=next_hops,json=nextHops,proto3" json:"next_hops,omitempty"`
+	Metric               uint32     `protobuf:"varint,5,opt,name=metric,proto3" json:"metric,omitempty"`
+	External             bool       `protobuf:"varint,6,opt,name=external,proto3" json:"external,omitempty"`
+	Preference           uint32     `protobuf:"varint,7,opt,name=preference,proto3" json:"preference,omitempty"`
+	Active               bool       `protobuf:"varint,8,opt,name=active,proto3" json:"active,omitempty"`
+	UpDown               bool       `protobuf:"varint,9,opt,name=up_down,json=upDown,proto3" json:"up_down,omitempty"`
+	Tags                 []uint32   `protobuf:"varint,10,rep,packed,name=tags,proto3" json:"tags,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *Route) Reset()         { *m = Route{} }
+func (m *Route) String() string { return proto.CompactTextString(m) }
+func (*Route) ProtoMessage()    {}
+func (*Route) Descriptor() ([]byte, []int) {
+	return fileDescriptor_07ca5a18eb6d27f6, []int{42}
+}
+
+func (m *Route) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Route.Unmarshal(m, b)
+}
+func (m *Route) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Route.Marshal(b, m, deterministic)
+}
+func (m *Route) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Route.Merge(m, src)
+}
+func (m *Route) XXX_Size() int {
+	return xxx_messageInfo_Route.Size(m)
+}
+func (m *Route) XXX_DiscardUnknown() {
+	xxx_messageInfo_Route.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Route proto.InternalMessageInfo
+
+func (m *Route) GetLevel() string {
+	if m != nil {
+		return m.Level
 	}
-	return 0
+	return ""
 }
 
-func (m *Lsp) GetRemainingLifetime() uint32 {
+func (m *Route) GetAddressFamily() string {
 	if m != nil {
-		return m.RemainingLifetime
+		return m.AddressFamily
 	}
-	return 0
+	return ""
 }
 
-func (m *Lsp) GetSequence() uint32 {
+func (m *Route) GetPrefix() string {
 	if m != nil {
-		return m.Sequence
+		return m.Prefix
+	}
+	return ""
+}
+
+func (m *Route) GetNextHops() []*NextHop {
+	if m != nil {
+		return m.NextHops
+	}
+	return nil
+}
+
+func (m *Route) GetMetric() uint32 {
+	if m != nil {
+		return m.Metric
 	}
 	return 0
 }
 
-func (m *Lsp) GetAttributes() uint32 {
+func (m *Route) GetExternal() bool {
 	if m != nil {
-		return m.Attributes
+		return m.External
+	}
+	return false
+}
+
+func (m *Route) GetPreference() uint32 {
+	if m != nil {
+		return m.Preference
 	}
 	return 0
 }
 
-func (m *Lsp) GetIpv4Addresses() []string {
+func (m *Route) GetActive() bool {
 	if m != nil {
-		return m.Ipv4Addresses
+		return m.Active
 	}
-	return nil
+	return false
 }
 
-func (m *Lsp) GetIpv6Addresses() []string {
+func (m *Route) GetUpDown() bool {
 	if m != nil {
-		return m.Ipv6Addresses
+		return m.UpDown
+	}
+	return false
+}
+
+func (m *Route) GetTags() []uint32 {
+	if m != nil {
+		return m.Tags
 	}
 	return nil
 }
 
-func (m *Lsp) GetIpv4TeRouterid() string {
+type Prefix struct {
+	IpPrefix             string   `protobuf:"bytes,1,opt,name=ip_prefix,json=ipPrefix,proto3" json:"ip_prefix,omitempty"`
+	MasklengthRange      string   `protobuf:"bytes,2,opt,name=masklength_range,json=masklengthRange,proto3" json:"masklength_range,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Prefix) Reset()         { *m = Prefix{} }
+func (m *Prefix) String() string { return proto.CompactTextString(m) }
+func (*Prefix) ProtoMessage()    {}
+func (*Prefix) Descriptor() ([]byte, []int) {
+	return fileDescriptor_07ca5a18eb6d27f6, []int{43}
+}
+
+func (m *Prefix) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Prefix.Unmarshal(m, b)
+}
+func (m *Prefix) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Prefix.Marshal(b, m, deterministic)
+}
+func (m *Prefix) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Prefix.Merge(m, src)
+}
+func (m *Prefix) XXX_Size() int {
+	return xxx_messageInfo_Prefix.Size(m)
+}
+func (m *Prefix) XXX_DiscardUnknown() {
+	xxx_messageInfo_Prefix.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Prefix proto.InternalMessageInfo
+
+func (m *Prefix) GetIpPrefix() string {
 	if m != nil {
-		return m.Ipv4TeRouterid
+		return m.IpPrefix
 	}
 	return ""
 }
 
-func (m *Lsp) GetIpv6TeRouterid() string {
+func (m *Prefix) GetMasklengthRange() string {
 	if m != nil {
-		return m.Ipv6TeRouterid
+		return m.MasklengthRange
 	}
 	return ""
 }
 
-func (m *Lsp) GetProtocolSupporteds() []uint32 {
+type PrefixSet struct {
+	Name                 string    `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Prefixes             []*Prefix `protobuf:"bytes,2,rep,name=prefixes,proto3" json:"prefixes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *PrefixSet) Reset()         { *m = PrefixSet{} }
+func (m *PrefixSet) String() string { return proto.CompactTextString(m) }
+func (*PrefixSet) ProtoMessage()    {}
+func (*PrefixSet) Descriptor() ([]byte, []int) {
+	return fileDescriptor_07ca5a18eb6d27f6, []int{44}
+}
+
+func (m *PrefixSet) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PrefixSet.Unmarshal(m, b)
+}
+func (m *PrefixSet) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PrefixSet.Marshal(b, m, deterministic)
+}
+func (m *PrefixSet) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PrefixSet.Merge(m, src)
+}
+func (m *PrefixSet) XXX_Size() int {
+	return xxx_messageInfo_PrefixSet.Size(m)
+}
+func (m *PrefixSet) XXX_DiscardUnknown() {
+	xxx_messageInfo_PrefixSet.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PrefixSet proto.InternalMessageInfo
+
+func (m *PrefixSet) GetName() string {
 	if m != nil {
-		return m.ProtocolSupporteds
+		return m.Name
+	}
+	return ""
+}
+
+func (m *PrefixSet) GetPrefixes() []*Prefix {
+	if m != nil {
+		return m.Prefixes
 	}
 	return nil
 }
 
-func (m *Lsp) GetDynamicHostname() string {
+type TagSet struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Tags                 []uint32 `protobuf:"varint,2,rep,packed,name=tags,proto3" json:"tags,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TagSet) Reset()         { *m = TagSet{} }
+func (m *TagSet) String() string { return proto.CompactTextString(m) }
+func (*TagSet) ProtoMessage()    {}
+func (*TagSet) Descriptor() ([]byte, []int) {
+	return fileDescriptor_07ca5a18eb6d27f6, []int{45}
+}
+
+func (m *TagSet) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TagSet.Unmarshal(m, b)
+}
+func (m *TagSet) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TagSet.Marshal(b, m, deterministic)
+}
+func (m *TagSet) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TagSet.Merge(m, src)
+}
+func (m *TagSet) XXX_Size() int {
+	return xxx_messageInfo_TagSet.Size(m)
+}
+func (m *TagSet) XXX_DiscardUnknown() {
+	xxx_messageInfo_TagSet.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TagSet proto.InternalMessageInfo
+
+func (m *TagSet) GetName() string {
 	if m != nil {
-		return m.DynamicHostname
+		return m.Name
 	}
 	return ""
 }
 
-func (m *Lsp) GetAuthentication() *Authentication {
+func (m *TagSet) GetTags() []uint32 {
 	if m != nil {
-		return m.Authentication
+		return m.Tags
 	}
 	return nil
 }
 
-func (m *Lsp) GetMtEntries() *MtEntries {
+type Conditions struct {
+	MatchPrefixSet        string   `protobuf:"bytes,1,opt,name=match_prefix_set,json=matchPrefixSet,proto3" json:"match_prefix_set,omitempty"`
+	MatchPrefixSetOptions string   `protobuf:"bytes,2,opt,name=match_prefix_set_options,json=matchPrefixSetOptions,proto3" json:"match_prefix_set_options,omitempty"`
+	MatchTagSet           string   `protobuf:"bytes,3,opt,name=match_tag_set,json=matchTagSet,proto3" json:"match_tag_set,omitempty"`
+	MatchTagSetOptions    string   `protobuf:"bytes,4,opt,name=match_tag_set_options,json=matchTagSetOptions,proto3" json:"match_tag_set_options,omitempty"`
+	Level                 string   `protobuf:"bytes,5,opt,name=level,proto3" json:"level,omitempty"`
+	RouteType             string   `protobuf:"bytes,6,opt,name=route_type,json=routeType,proto3" json:"route_type,omitempty"`
+	XXX_NoUnkeyedLiteral  struct{} `json:"-"`
+	XXX_unrecognized      []byte   `json:"-"`
+	XXX_sizecache         int32    `json:"-"`
+}
+
+func (m *Conditions) Reset()         { *m = Conditions{} }
+func (m *Conditions) String() string { return proto.CompactTextString(m) }
+func (*Conditions) ProtoMessage()    {}
+func (*Conditions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_07ca5a18eb6d27f6, []int{46}
+}
+
+func (m *Conditions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Conditions.Unmarshal(m, b)
+}
+func (m *Conditions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Conditions.Marshal(b, m, deterministic)
+}
+func (m *Conditions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Conditions.Merge(m, src)
+}
+func (m *Conditions) XXX_Size() int {
+	return xxx_messageInfo_Conditions.Size(m)
+}
+func (m *Conditions) XXX_DiscardUnknown() {
+	xxx_messageInfo_Conditions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Conditions proto.InternalMessageInfo
+
+func (m *Conditions) GetMatchPrefixSet() string {
 	if m != nil {
-		return m.MtEntries
+		return m.MatchPrefixSet
+	}
+	return ""
+}
+
+func (m *Conditions) GetMatchPrefixSetOptions() string {
+	if m != nil {
+		return m.MatchPrefixSetOptions
+	}
+	return ""
+}
+
+func (m *Conditions) GetMatchTagSet() string {
+	if m != nil {
+		return m.MatchTagSet
+	}
+	return ""
+}
+
+func (m *Conditions) GetMatchTagSetOptions() string {
+	if m != nil {
+		return m.MatchTagSetOptions
+	}
+	return ""
+}
+
+func (m *Conditions) GetLevel() string {
+	if m != nil {
+		return m.Level
+	}
+	return ""
+}
+
+func (m *Conditions) GetRouteType() string {
+	if m != nil {
+		return m.RouteType
+	}
+	return ""
+}
+
+type MetricAction struct {
+	Value                uint32   `protobuf:"varint,1,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MetricAction) Reset()         { *m = MetricAction{} }
+func (m *MetricAction) String() string { return proto.CompactTextString(m) }
+func (*MetricAction) ProtoMessage()    {}
+func (*MetricAction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_07ca5a18eb6d27f6, []int{47}
+}
+
+func (m *MetricAction) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MetricAction.Unmarshal(m, b)
+}
+func (m *MetricAction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MetricAction.Marshal(b, m, deterministic)
+}
+func (m *MetricAction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MetricAction.Merge(m, src)
+}
+func (m *MetricAction) XXX_Size() int {
+	return xxx_messageInfo_MetricAction.Size(m)
+}
+func (m *MetricAction) XXX_DiscardUnknown() {
+	xxx_messageInfo_MetricAction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MetricAction proto.InternalMessageInfo
+
+func (m *MetricAction) GetValue() uint32 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+type TagAction struct {
+	Value                uint32   `protobuf:"varint,1,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TagAction) Reset()         { *m = TagAction{} }
+func (m *TagAction) String() string { return proto.CompactTextString(m) }
+func (*TagAction) ProtoMessage()    {}
+func (*TagAction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_07ca5a18eb6d27f6, []int{48}
+}
+
+func (m *TagAction) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TagAction.Unmarshal(m, b)
+}
+func (m *TagAction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TagAction.Marshal(b, m, deterministic)
+}
+func (m *TagAction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TagAction.Merge(m, src)
+}
+func (m *TagAction) XXX_Size() int {
+	return xxx_messageInfo_TagAction.Size(m)
+}
+func (m *TagAction) XXX_DiscardUnknown() {
+	xxx_messageInfo_TagAction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TagAction proto.InternalMessageInfo
+
+func (m *TagAction) GetValue() uint32 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+type UpDownAction struct {
+	Value                bool     `protobuf:"varint,1,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpDownAction) Reset()         { *m = UpDownAction{} }
+func (m *UpDownAction) String() string { return proto.CompactTextString(m) }
+func (*UpDownAction) ProtoMessage()    {}
+func (*UpDownAction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_07ca5a18eb6d27f6, []int{49}
+}
+
+func (m *UpDownAction) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpDownAction.Unmarshal(m, b)
+}
+func (m *UpDownAction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpDownAction.Marshal(b, m, deterministic)
+}
+func (m *UpDownAction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpDownAction.Merge(m, src)
+}
+func (m *UpDownAction) XXX_Size() int {
+	return xxx_messageInfo_UpDownAction.Size(m)
+}
+func (m *UpDownAction) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpDownAction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpDownAction proto.InternalMessageInfo
+
+func (m *UpDownAction) GetValue() bool {
+	if m != nil {
+		return m.Value
+	}
+	return false
+}
+
+type Actions struct {
+	RouteDisposition     string        `protobuf:"bytes,1,opt,name=route_disposition,json=routeDisposition,proto3" json:"route_disposition,omitempty"`
+	SetMetric            *MetricAction `protobuf:"bytes,2,opt,name=set_metric,json=setMetric,proto3" json:"set_metric,omitempty"`
+	SetTag               *TagAction    `protobuf:"bytes,3,opt,name=set_tag,json=setTag,proto3" json:"set_tag,omitempty"`
+	SetUpDown            *UpDownAction `protobuf:"bytes,4,opt,name=set_up_down,json=setUpDown,proto3" json:"set_up_down,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *Actions) Reset()         { *m = Actions{} }
+func (m *Actions) String() string { return proto.CompactTextString(m) }
+func (*Actions) ProtoMessage()    {}
+func (*Actions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_07ca5a18eb6d27f6, []int{50}
+}
+
+func (m *Actions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Actions.Unmarshal(m, b)
+}
+func (m *Actions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Actions.Marshal(b, m, deterministic)
+}
+func (m *Actions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Actions.Merge(m, src)
+}
+func (m *Actions) XXX_Size() int {
+	return xxx_messageInfo_Actions.Size(m)
+}
+func (m *Actions) XXX_DiscardUnknown() {
+	xxx_messageInfo_Actions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Actions proto.InternalMessageInfo
+
+func (m *Actions) GetRouteDisposition() string {
+	if m != nil {
+		return m.RouteDisposition
+	}
+	return ""
+}
+
+func (m *Actions) GetSetMetric() *MetricAction {
+	if m != nil {
+		return m.SetMetric
 	}
 	return nil
 }
 
-func (m *Lsp) GetRouterCapabilities() *RouterCapabilities {
+func (m *Actions) GetSetTag() *TagAction {
 	if m != nil {
-		return m.RouterCapabilities
+		return m.SetTag
 	}
 	return nil
 }
 
-func (m *Lsp) GetNodeTags() *NodeTags {
+func (m *Actions) GetSetUpDown() *UpDownAction {
+	if m != nil {
+		return m.SetUpDown
+	}
+	return nil
+}
+
+type Statement struct {
+	Name                 string      `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Conditions           *Conditions `protobuf:"bytes,2,opt,name=conditions,proto3" json:"conditions,omitempty"`
+	Actions              *Actions    `protobuf:"bytes,3,opt,name=actions,proto3" json:"actions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *Statement) Reset()         { *m = Statement{} }
+func (m *Statement) String() string { return proto.CompactTextString(m) }
+func (*Statement) ProtoMessage()    {}
+func (*Statement) Descriptor() ([]byte, []int) {
+	return fileDescriptor_07ca5a18eb6d27f6, []int{51}
+}
+
+func (m *Statement) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Statement.Unmarshal(m, b)
+}
+func (m *Statement) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Statement.Marshal(b, m, deterministic)
+}
+func (m *Statement) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Statement.Merge(m, src)
+}
+func (m *Statement) XXX_Size() int {
+	return xxx_messageInfo_Statement.Size(m)
+}
+func (m *Statement) XXX_DiscardUnknown() {
+	xxx_messageInfo_Statement.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Statement proto.InternalMessageInfo
+
+func (m *Statement) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Statement) GetConditions() *Conditions {
 	if m != nil {
-		return m.NodeTags
+		return m.Conditions
 	}
 	return nil
 }
 
-func (m *Lsp) GetBinary() []byte {
+func (m *Statement) GetActions() *Actions {
 	if m != nil {
-		return m.Binary
+		return m.Actions
 	}
 	return nil
 }
 
-type Route struct {
-	Level                string     `protobuf:"bytes,1,opt,name=level,proto3" json:"level,omitempty"`
-	AddressFamily        string     `protobuf:"bytes,2,opt,name=address_family,json=addressFamily,proto3" json:"address_family,omitempty"`
-	Prefix               string     `protobuf:"bytes,3,opt,name=prefix,proto3" json:"prefix,omitempty"`
-	NextHops             []*NextHop `protobuf:"bytes,4,rep,name=next_hops,json=nextHops,proto3" json:"next_hops,omitempty"`
-	Metric               uint32     `protobuf:"varint,5,opt,name=metric,proto3" json:"metric,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
-	XXX_unrecognized     []byte     `json:"-"`
-	XXX_sizecache        int32      `json:"-"`
+type Policy struct {
+	Name                 string       `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Statements           []*Statement `protobuf:"bytes,2,rep,name=statements,proto3" json:"statements,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
 }
 
-func (m *Route) Reset()         { *m = Route{} }
-func (m *Route) String() string { return proto.CompactTextString(m) }
-func (*Route) ProtoMessage()    {}
-func (*Route) Descriptor() ([]byte, []int) {
-	return fileDescriptor_07ca5a18eb6d27f6, []int{22}
+func (m *Policy) Reset()         { *m = Policy{} }
+func (m *Policy) String() string { return proto.CompactTextString(m) }
+func (*Policy) ProtoMessage()    {}
+func (*Policy) Descriptor() ([]byte, []int) {
+	return fileDescriptor_07ca5a18eb6d27f6, []int{52}
 }
 
-func (m *Route) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Route.Unmarshal(m, b)
+func (m *Policy) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Policy.Unmarshal(m, b)
 }
-func (m *Route) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Route.Marshal(b, m, deterministic)
+func (m *Policy) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Policy.Marshal(b, m, deterministic)
 }
-func (m *Route) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Route.Merge(m, src)
+func (m *Policy) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Policy.Merge(m, src)
 }
-func (m *Route) XXX_Size() int {
-	return xxx_messageInfo_Route.Size(m)
+func (m *Policy) XXX_Size() int {
+	return xxx_messageInfo_Policy.Size(m)
 }
-func (m *Route) XXX_DiscardUnknown() {
-	xxx_messageInfo_Route.DiscardUnknown(m)
+func (m *Policy) XXX_DiscardUnknown() {
+	xxx_messageInfo_Policy.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Route proto.InternalMessageInfo
+var xxx_messageInfo_Policy proto.InternalMessageInfo
 
-func (m *Route) GetLevel() string {
+func (m *Policy) GetName() string {
 	if m != nil {
-		return m.Level
+		return m.Name
 	}
 	return ""
 }
 
-func (m *Route) GetAddressFamily() string {
+func (m *Policy) GetStatements() []*Statement {
 	if m != nil {
-		return m.AddressFamily
+		return m.Statements
 	}
-	return ""
+	return nil
 }
 
-func (m *Route) GetPrefix() string {
+type PolicyAssignment struct {
+	InstallPolicies      []string `protobuf:"bytes,1,rep,name=install_policies,json=installPolicies,proto3" json:"install_policies,omitempty"`
+	DefaultInstallPolicy string   `protobuf:"bytes,2,opt,name=default_install_policy,json=defaultInstallPolicy,proto3" json:"default_install_policy,omitempty"`
+	LeakPolicies         []string `protobuf:"bytes,3,rep,name=leak_policies,json=leakPolicies,proto3" json:"leak_policies,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PolicyAssignment) Reset()         { *m = PolicyAssignment{} }
+func (m *PolicyAssignment) String() string { return proto.CompactTextString(m) }
+func (*PolicyAssignment) ProtoMessage()    {}
+func (*PolicyAssignment) Descriptor() ([]byte, []int) {
+	return fileDescriptor_07ca5a18eb6d27f6, []int{53}
+}
+
+func (m *PolicyAssignment) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PolicyAssignment.Unmarshal(m, b)
+}
+func (m *PolicyAssignment) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PolicyAssignment.Marshal(b, m, deterministic)
+}
+func (m *PolicyAssignment) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PolicyAssignment.Merge(m, src)
+}
+func (m *PolicyAssignment) XXX_Size() int {
+	return xxx_messageInfo_PolicyAssignment.Size(m)
+}
+func (m *PolicyAssignment) XXX_DiscardUnknown() {
+	xxx_messageInfo_PolicyAssignment.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PolicyAssignment proto.InternalMessageInfo
+
+func (m *PolicyAssignment) GetInstallPolicies() []string {
 	if m != nil {
-		return m.Prefix
+		return m.InstallPolicies
 	}
-	return ""
+	return nil
 }
 
-func (m *Route) GetNextHops() []*NextHop {
+func (m *PolicyAssignment) GetDefaultInstallPolicy() string {
 	if m != nil {
-		return m.NextHops
+		return m.DefaultInstallPolicy
 	}
-	return nil
+	return ""
 }
 
-func (m *Route) GetMetric() uint32 {
+func (m *PolicyAssignment) GetLeakPolicies() []string {
 	if m != nil {
-		return m.Metric
+		return m.LeakPolicies
 	}
-	return 0
+	return nil
 }
 
 type Authentication struct {
@@ -1123,7 +2724,7 @@ func (m *Authentication) Reset()         { *m = Authentication{} }
 func (m *Authentication) String() string { return proto.CompactTextString(m) }
 func (*Authentication) ProtoMessage()    {}
 func (*Authentication) Descriptor() ([]byte, []int) {
-	return fileDescriptor_07ca5a18eb6d27f6, []int{23}
+	return fileDescriptor_07ca5a18eb6d27f6, []int{54}
 }
 
 func (m *Authentication) XXX_Unmarshal(b []byte) error {
@@ -1170,7 +2771,7 @@ func (m *Topology) Reset()         { *m = Topology{} }
 func (m *Topology) String() string { return proto.CompactTextString(m) }
 func (*Topology) ProtoMessage()    {}
 func (*Topology) Descriptor() ([]byte, []int) {
-	return fileDescriptor_07ca5a18eb6d27f6, []int{24}
+	return fileDescriptor_07ca5a18eb6d27f6, []int{55}
 }
 
 func (m *Topology) XXX_Unmarshal(b []byte) error {
@@ -1216,7 +2817,7 @@ func (m *MtEntries) Reset()         { *m = MtEntries{} }
 func (m *MtEntries) String() string { return proto.CompactTextString(m) }
 func (*MtEntries) ProtoMessage()    {}
 func (*MtEntries) Descriptor() ([]byte, []int) {
-	return fileDescriptor_07ca5a18eb6d27f6, []int{25}
+	return fileDescriptor_07ca5a18eb6d27f6, []int{56}
 }
 
 func (m *MtEntries) XXX_Unmarshal(b []byte) error {
@@ -1255,7 +2856,7 @@ func (m *RouterCapabilities) Reset()         { *m = RouterCapabilities{} }
 func (m *RouterCapabilities) String() string { return proto.CompactTextString(m) }
 func (*RouterCapabilities) ProtoMessage()    {}
 func (*RouterCapabilities) Descriptor() ([]byte, []int) {
-	return fileDescriptor_07ca5a18eb6d27f6, []int{26}
+	return fileDescriptor_07ca5a18eb6d27f6, []int{57}
 }
 
 func (m *RouterCapabilities) XXX_Unmarshal(b []byte) error {
@@ -1293,7 +2894,7 @@ func (m *NodeTags) Reset()         { *m = NodeTags{} }
 func (m *NodeTags) String() string { return proto.CompactTextString(m) }
 func (*NodeTags) ProtoMessage()    {}
 func (*NodeTags) Descriptor() ([]byte, []int) {
-	return fileDescriptor_07ca5a18eb6d27f6, []int{27}
+	return fileDescriptor_07ca5a18eb6d27f6, []int{58}
 }
 
 func (m *NodeTags) XXX_Unmarshal(b []byte) error {
@@ -1325,7 +2926,7 @@ func (m *Global) Reset()         { *m = Global{} }
 func (m *Global) String() string { return proto.CompactTextString(m) }
 func (*Global) ProtoMessage()    {}
 func (*Global) Descriptor() ([]byte, []int) {
-	return fileDescriptor_07ca5a18eb6d27f6, []int{28}
+	return fileDescriptor_07ca5a18eb6d27f6, []int{59}
 }
 
 func (m *Global) XXX_Unmarshal(b []byte) error {
@@ -1353,6 +2954,69 @@ func (m *Global) GetSystemId() string {
 	return ""
 }
 
+type Instance struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	SystemId             string   `protobuf:"bytes,2,opt,name=system_id,json=systemId,proto3" json:"system_id,omitempty"`
+	AreaAddresses        []string `protobuf:"bytes,3,rep,name=area_addresses,json=areaAddresses,proto3" json:"area_addresses,omitempty"`
+	Interfaces           []string `protobuf:"bytes,4,rep,name=interfaces,proto3" json:"interfaces,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Instance) Reset()         { *m = Instance{} }
+func (m *Instance) String() string { return proto.CompactTextString(m) }
+func (*Instance) ProtoMessage()    {}
+func (*Instance) Descriptor() ([]byte, []int) {
+	return fileDescriptor_07ca5a18eb6d27f6, []int{60}
+}
+
+func (m *Instance) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Instance.Unmarshal(m, b)
+}
+func (m *Instance) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Instance.Marshal(b, m, deterministic)
+}
+func (m *Instance) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Instance.Merge(m, src)
+}
+func (m *Instance) XXX_Size() int {
+	return xxx_messageInfo_Instance.Size(m)
+}
+func (m *Instance) XXX_DiscardUnknown() {
+	xxx_messageInfo_Instance.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Instance proto.InternalMessageInfo
+
+func (m *Instance) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Instance) GetSystemId() string {
+	if m != nil {
+		return m.SystemId
+	}
+	return ""
+}
+
+func (m *Instance) GetAreaAddresses() []string {
+	if m != nil {
+		return m.AreaAddresses
+	}
+	return nil
+}
+
+func (m *Instance) GetInterfaces() []string {
+	if m != nil {
+		return m.Interfaces
+	}
+	return nil
+}
+
 type NextHop struct {
 	OutgoingInterface    string   `protobuf:"bytes,1,opt,name=outgoing_interface,json=outgoingInterface,proto3" json:"outgoing_interface,omitempty"`
 	NextHop              string   `protobuf:"bytes,2,opt,name=next_hop,json=nextHop,proto3" json:"next_hop,omitempty"`
@@ -1365,39 +3029,324 @@ func (m *NextHop) Reset()         { *m = NextHop{} }
 func (m *NextHop) String() string { return proto.CompactTextString(m) }
 func (*NextHop) ProtoMessage()    {}
 func (*NextHop) Descriptor() ([]byte, []int) {
-	return fileDescriptor_07ca5a18eb6d27f6, []int{29}
+	return fileDescriptor_07ca5a18eb6d27f6, []int{61}
+}
+
+func (m *NextHop) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NextHop.Unmarshal(m, b)
+}
+func (m *NextHop) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NextHop.Marshal(b, m, deterministic)
+}
+func (m *NextHop) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NextHop.Merge(m, src)
+}
+func (m *NextHop) XXX_Size() int {
+	return xxx_messageInfo_NextHop.Size(m)
+}
+func (m *NextHop) XXX_DiscardUnknown() {
+	xxx_messageInfo_NextHop.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NextHop proto.InternalMessageInfo
+
+func (m *NextHop) GetOutgoingInterface() string {
+	if m != nil {
+		return m.OutgoingInterface
+	}
+	return ""
+}
+
+func (m *NextHop) GetNextHop() string {
+	if m != nil {
+		return m.NextHop
+	}
+	return ""
+}
+
+type TopologyGraph struct {
+	Level                string               `protobuf:"bytes,1,opt,name=level,proto3" json:"level,omitempty"`
+	Nodes                []*TopologyGraphNode `protobuf:"bytes,2,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	Edges                []*TopologyGraphEdge `protobuf:"bytes,3,rep,name=edges,proto3" json:"edges,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *TopologyGraph) Reset()         { *m = TopologyGraph{} }
+func (m *TopologyGraph) String() string { return proto.CompactTextString(m) }
+func (*TopologyGraph) ProtoMessage()    {}
+func (*TopologyGraph) Descriptor() ([]byte, []int) {
+	return fileDescriptor_07ca5a18eb6d27f6, []int{62}
+}
+
+func (m *TopologyGraph) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TopologyGraph.Unmarshal(m, b)
+}
+func (m *TopologyGraph) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TopologyGraph.Marshal(b, m, deterministic)
+}
+func (m *TopologyGraph) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TopologyGraph.Merge(m, src)
+}
+func (m *TopologyGraph) XXX_Size() int {
+	return xxx_messageInfo_TopologyGraph.Size(m)
+}
+func (m *TopologyGraph) XXX_DiscardUnknown() {
+	xxx_messageInfo_TopologyGraph.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TopologyGraph proto.InternalMessageInfo
+
+func (m *TopologyGraph) GetLevel() string {
+	if m != nil {
+		return m.Level
+	}
+	return ""
+}
+
+func (m *TopologyGraph) GetNodes() []*TopologyGraphNode {
+	if m != nil {
+		return m.Nodes
+	}
+	return nil
+}
+
+func (m *TopologyGraph) GetEdges() []*TopologyGraphEdge {
+	if m != nil {
+		return m.Edges
+	}
+	return nil
+}
+
+type TopologyGraphNode struct {
+	NodeId               string   `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	SystemId             string   `protobuf:"bytes,2,opt,name=system_id,json=systemId,proto3" json:"system_id,omitempty"`
+	Pseudonode           bool     `protobuf:"varint,3,opt,name=pseudonode,proto3" json:"pseudonode,omitempty"`
+	LspPresent           bool     `protobuf:"varint,4,opt,name=lsp_present,json=lspPresent,proto3" json:"lsp_present,omitempty"`
+	Hostname             string   `protobuf:"bytes,5,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	Overload             bool     `protobuf:"varint,6,opt,name=overload,proto3" json:"overload,omitempty"`
+	Attached             bool     `protobuf:"varint,7,opt,name=attached,proto3" json:"attached,omitempty"`
+	Ipv4TeRouterid       string   `protobuf:"bytes,8,opt,name=ipv4_te_routerid,json=ipv4TeRouterid,proto3" json:"ipv4_te_routerid,omitempty"`
+	Ipv4Addresses        []string `protobuf:"bytes,9,rep,name=ipv4_addresses,json=ipv4Addresses,proto3" json:"ipv4_addresses,omitempty"`
+	Ipv6Addresses        []string `protobuf:"bytes,10,rep,name=ipv6_addresses,json=ipv6Addresses,proto3" json:"ipv6_addresses,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TopologyGraphNode) Reset()         { *m = TopologyGraphNode{} }
+func (m *TopologyGraphNode) String() string { return proto.CompactTextString(m) }
+func (*TopologyGraphNode) ProtoMessage()    {}
+func (*TopologyGraphNode) Descriptor() ([]byte, []int) {
+	return fileDescriptor_07ca5a18eb6d27f6, []int{63}
+}
+
+func (m *TopologyGraphNode) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TopologyGraphNode.Unmarshal(m, b)
+}
+func (m *TopologyGraphNode) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TopologyGraphNode.Marshal(b, m, deterministic)
+}
+func (m *TopologyGraphNode) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TopologyGraphNode.Merge(m, src)
+}
+func (m *TopologyGraphNode) XXX_Size() int {
+	return xxx_messageInfo_TopologyGraphNode.Size(m)
+}
+func (m *TopologyGraphNode) XXX_DiscardUnknown() {
+	xxx_messageInfo_TopologyGraphNode.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TopologyGraphNode proto.InternalMessageInfo
+
+func (m *TopologyGraphNode) GetNodeId() string {
+	if m != nil {
+		return m.NodeId
+	}
+	return ""
+}
+
+func (m *TopologyGraphNode) GetSystemId() string {
+	if m != nil {
+		return m.SystemId
+	}
+	return ""
+}
+
+func (m *TopologyGraphNode) GetPseudonode() bool {
+	if m != nil {
+		return m.Pseudonode
+	}
+	return false
+}
+
+func (m *TopologyGraphNode) GetLspPresent() bool {
+	if m != nil {
+		return m.LspPresent
+	}
+	return false
+}
+
+func (m *TopologyGraphNode) GetHostname() string {
+	if m != nil {
+		return m.Hostname
+	}
+	return ""
+}
+
+func (m *TopologyGraphNode) GetOverload() bool {
+	if m != nil {
+		return m.Overload
+	}
+	return false
+}
+
+func (m *TopologyGraphNode) GetAttached() bool {
+	if m != nil {
+		return m.Attached
+	}
+	return false
+}
+
+func (m *TopologyGraphNode) GetIpv4TeRouterid() string {
+	if m != nil {
+		return m.Ipv4TeRouterid
+	}
+	return ""
+}
+
+func (m *TopologyGraphNode) GetIpv4Addresses() []string {
+	if m != nil {
+		return m.Ipv4Addresses
+	}
+	return nil
+}
+
+func (m *TopologyGraphNode) GetIpv6Addresses() []string {
+	if m != nil {
+		return m.Ipv6Addresses
+	}
+	return nil
+}
+
+type TopologyGraphEdge struct {
+	From                       string    `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To                         string    `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	Tlv                        string    `protobuf:"bytes,3,opt,name=tlv,proto3" json:"tlv,omitempty"`
+	Metric                     uint32    `protobuf:"varint,4,opt,name=metric,proto3" json:"metric,omitempty"`
+	AdminGroup                 uint32    `protobuf:"varint,5,opt,name=admin_group,json=adminGroup,proto3" json:"admin_group,omitempty"`
+	Ipv4InterfaceAddresses     []string  `protobuf:"bytes,6,rep,name=ipv4_interface_addresses,json=ipv4InterfaceAddresses,proto3" json:"ipv4_interface_addresses,omitempty"`
+	Ipv4NeighborAddresses      []string  `protobuf:"bytes,7,rep,name=ipv4_neighbor_addresses,json=ipv4NeighborAddresses,proto3" json:"ipv4_neighbor_addresses,omitempty"`
+	MaxLinkBandwidth           float32   `protobuf:"fixed32,8,opt,name=max_link_bandwidth,json=maxLinkBandwidth,proto3" json:"max_link_bandwidth,omitempty"`
+	MaxReservableLinkBandwidth float32   `protobuf:"fixed32,9,opt,name=max_reservable_link_bandwidth,json=maxReservableLinkBandwidth,proto3" json:"max_reservable_link_bandwidth,omitempty"`
+	UnreservedBandwidths       []float32 `protobuf:"fixed32,10,rep,packed,name=unreserved_bandwidths,json=unreservedBandwidths,proto3" json:"unreserved_bandwidths,omitempty"`
+	TeDefaultMetric            uint32    `protobuf:"varint,11,opt,name=te_default_metric,json=teDefaultMetric,proto3" json:"te_default_metric,omitempty"`
+	XXX_NoUnkeyedLiteral       struct{}  `json:"-"`
+	XXX_unrecognized           []byte    `json:"-"`
+	XXX_sizecache              int32     `json:"-"`
+}
+
+func (m *TopologyGraphEdge) Reset()         { *m = TopologyGraphEdge{} }
+func (m *TopologyGraphEdge) String() string { return proto.CompactTextString(m) }
+func (*TopologyGraphEdge) ProtoMessage()    {}
+func (*TopologyGraphEdge) Descriptor() ([]byte, []int) {
+	return fileDescriptor_07ca5a18eb6d27f6, []int{64}
+}
+
+func (m *TopologyGraphEdge) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TopologyGraphEdge.Unmarshal(m, b)
+}
+func (m *TopologyGraphEdge) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TopologyGraphEdge.Marshal(b, m, deterministic)
+}
+func (m *TopologyGraphEdge) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TopologyGraphEdge.Merge(m, src)
+}
+func (m *TopologyGraphEdge) XXX_Size() int {
+	return xxx_messageInfo_TopologyGraphEdge.Size(m)
+}
+func (m *TopologyGraphEdge) XXX_DiscardUnknown() {
+	xxx_messageInfo_TopologyGraphEdge.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TopologyGraphEdge proto.InternalMessageInfo
+
+func (m *TopologyGraphEdge) GetFrom() string {
+	if m != nil {
+		return m.From
+	}
+	return ""
+}
+
+func (m *TopologyGraphEdge) GetTo() string {
+	if m != nil {
+		return m.To
+	}
+	return ""
+}
+
+func (m *TopologyGraphEdge) GetTlv() string {
+	if m != nil {
+		return m.Tlv
+	}
+	return ""
 }
 
-func (m *NextHop) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_NextHop.Unmarshal(m, b)
+func (m *TopologyGraphEdge) GetMetric() uint32 {
+	if m != nil {
+		return m.Metric
+	}
+	return 0
 }
-func (m *NextHop) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_NextHop.Marshal(b, m, deterministic)
+
+func (m *TopologyGraphEdge) GetAdminGroup() uint32 {
+	if m != nil {
+		return m.AdminGroup
+	}
+	return 0
 }
-func (m *NextHop) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_NextHop.Merge(m, src)
+
+func (m *TopologyGraphEdge) GetIpv4InterfaceAddresses() []string {
+	if m != nil {
+		return m.Ipv4InterfaceAddresses
+	}
+	return nil
 }
-func (m *NextHop) XXX_Size() int {
-	return xxx_messageInfo_NextHop.Size(m)
+
+func (m *TopologyGraphEdge) GetIpv4NeighborAddresses() []string {
+	if m != nil {
+		return m.Ipv4NeighborAddresses
+	}
+	return nil
 }
-func (m *NextHop) XXX_DiscardUnknown() {
-	xxx_messageInfo_NextHop.DiscardUnknown(m)
+
+func (m *TopologyGraphEdge) GetMaxLinkBandwidth() float32 {
+	if m != nil {
+		return m.MaxLinkBandwidth
+	}
+	return 0
 }
 
-var xxx_messageInfo_NextHop proto.InternalMessageInfo
+func (m *TopologyGraphEdge) GetMaxReservableLinkBandwidth() float32 {
+	if m != nil {
+		return m.MaxReservableLinkBandwidth
+	}
+	return 0
+}
 
-func (m *NextHop) GetOutgoingInterface() string {
+func (m *TopologyGraphEdge) GetUnreservedBandwidths() []float32 {
 	if m != nil {
-		return m.OutgoingInterface
+		return m.UnreservedBandwidths
 	}
-	return ""
+	return nil
 }
 
-func (m *NextHop) GetNextHop() string {
+func (m *TopologyGraphEdge) GetTeDefaultMetric() uint32 {
 	if m != nil {
-		return m.NextHop
+		return m.TeDefaultMetric
 	}
-	return ""
+	return 0
 }
 
 func init() {
@@ -1417,105 +3366,229 @@ func init() {
 	proto.RegisterType((*DbLsGetResponse)(nil), "goisisapi.DbLsGetResponse")
 	proto.RegisterType((*DbLsMonitorRequest)(nil), "goisisapi.DbLsMonitorRequest")
 	proto.RegisterType((*DbLsMonitorResponse)(nil), "goisisapi.DbLsMonitorResponse")
+	proto.RegisterType((*DbLsExportRequest)(nil), "goisisapi.DbLsExportRequest")
+	proto.RegisterType((*DbLsExportResponse)(nil), "goisisapi.DbLsExportResponse")
 	proto.RegisterType((*DbRiGetRequest)(nil), "goisisapi.DbRiGetRequest")
 	proto.RegisterType((*DbRiGetResponse)(nil), "goisisapi.DbRiGetResponse")
 	proto.RegisterType((*DbRiMonitorRequest)(nil), "goisisapi.DbRiMonitorRequest")
 	proto.RegisterType((*DbRiMonitorResponse)(nil), "goisisapi.DbRiMonitorResponse")
+	proto.RegisterType((*TraceSetRequest)(nil), "goisisapi.TraceSetRequest")
+	proto.RegisterType((*TraceSetResponse)(nil), "goisisapi.TraceSetResponse")
+	proto.RegisterType((*TopologyGetRequest)(nil), "goisisapi.TopologyGetRequest")
+	proto.RegisterType((*TopologyGetResponse)(nil), "goisisapi.TopologyGetResponse")
+	proto.RegisterType((*InstanceListRequest)(nil), "goisisapi.InstanceListRequest")
+	proto.RegisterType((*InstanceListResponse)(nil), "goisisapi.InstanceListResponse")
+	proto.RegisterType((*PolicyGetRequest)(nil), "goisisapi.PolicyGetRequest")
+	proto.RegisterType((*PolicyGetResponse)(nil), "goisisapi.PolicyGetResponse")
+	proto.RegisterType((*DefinedSetAddRequest)(nil), "goisisapi.DefinedSetAddRequest")
+	proto.RegisterType((*DefinedSetAddResponse)(nil), "goisisapi.DefinedSetAddResponse")
+	proto.RegisterType((*DefinedSetDeleteRequest)(nil), "goisisapi.DefinedSetDeleteRequest")
+	proto.RegisterType((*DefinedSetDeleteResponse)(nil), "goisisapi.DefinedSetDeleteResponse")
+	proto.RegisterType((*PolicyAddRequest)(nil), "goisisapi.PolicyAddRequest")
+	proto.RegisterType((*PolicyAddResponse)(nil), "goisisapi.PolicyAddResponse")
+	proto.RegisterType((*PolicyDeleteRequest)(nil), "goisisapi.PolicyDeleteRequest")
+	proto.RegisterType((*PolicyDeleteResponse)(nil), "goisisapi.PolicyDeleteResponse")
+	proto.RegisterType((*PolicyAssignmentSetRequest)(nil), "goisisapi.PolicyAssignmentSetRequest")
+	proto.RegisterType((*PolicyAssignmentSetResponse)(nil), "goisisapi.PolicyAssignmentSetResponse")
 	proto.RegisterType((*Adjacency)(nil), "goisisapi.Adjacency")
 	proto.RegisterType((*Lsp)(nil), "goisisapi.Lsp")
 	proto.RegisterType((*Route)(nil), "goisisapi.Route")
+	proto.RegisterType((*Prefix)(nil), "goisisapi.Prefix")
+	proto.RegisterType((*PrefixSet)(nil), "goisisapi.PrefixSet")
+	proto.RegisterType((*TagSet)(nil), "goisisapi.TagSet")
+	proto.RegisterType((*Conditions)(nil), "goisisapi.Conditions")
+	proto.RegisterType((*MetricAction)(nil), "goisisapi.MetricAction")
+	proto.RegisterType((*TagAction)(nil), "goisisapi.TagAction")
+	proto.RegisterType((*UpDownAction)(nil), "goisisapi.UpDownAction")
+	proto.RegisterType((*Actions)(nil), "goisisapi.Actions")
+	proto.RegisterType((*Statement)(nil), "goisisapi.Statement")
+	proto.RegisterType((*Policy)(nil), "goisisapi.Policy")
+	proto.RegisterType((*PolicyAssignment)(nil), "goisisapi.PolicyAssignment")
 	proto.RegisterType((*Authentication)(nil), "goisisapi.Authentication")
 	proto.RegisterType((*Topology)(nil), "goisisapi.Topology")
 	proto.RegisterType((*MtEntries)(nil), "goisisapi.MtEntries")
 	proto.RegisterType((*RouterCapabilities)(nil), "goisisapi.RouterCapabilities")
 	proto.RegisterType((*NodeTags)(nil), "goisisapi.NodeTags")
 	proto.RegisterType((*Global)(nil), "goisisapi.Global")
+	proto.RegisterType((*Instance)(nil), "goisisapi.Instance")
 	proto.RegisterType((*NextHop)(nil), "goisisapi.NextHop")
+	proto.RegisterType((*TopologyGraph)(nil), "goisisapi.TopologyGraph")
+	proto.RegisterType((*TopologyGraphNode)(nil), "goisisapi.TopologyGraphNode")
+	proto.RegisterType((*TopologyGraphEdge)(nil), "goisisapi.TopologyGraphEdge")
 }
 
 func init() { proto.RegisterFile("goisis.proto", fileDescriptor_07ca5a18eb6d27f6) }
 
 var fileDescriptor_07ca5a18eb6d27f6 = []byte{
-	// 1256 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x56, 0x6d, 0x6f, 0xdb, 0x36,
-	0x10, 0x86, 0x9b, 0xc4, 0xb1, 0x2e, 0xf1, 0x1b, 0xdd, 0x76, 0x8a, 0xd7, 0x76, 0x9e, 0x8a, 0x02,
-	0xee, 0x8a, 0xbe, 0x2c, 0x1d, 0x3c, 0xec, 0xc3, 0xd0, 0x06, 0x6d, 0x97, 0x06, 0x4b, 0xb3, 0x4d,
-	0xc9, 0x87, 0x01, 0xc3, 0x20, 0xd0, 0x12, 0x63, 0x73, 0x95, 0x44, 0x4e, 0xa4, 0xdb, 0xf8, 0x67,
-	0xec, 0xd3, 0x7e, 0xc1, 0x7e, 0xe1, 0xfe, 0xc0, 0x40, 0x8a, 0x52, 0x28, 0x39, 0x2f, 0x05, 0xf6,
-	0x4d, 0x7c, 0xee, 0xb9, 0xe3, 0xc3, 0x3b, 0xea, 0x78, 0xb0, 0x3d, 0x63, 0x54, 0x50, 0xf1, 0x84,
-	0x67, 0x4c, 0x32, 0xe4, 0xe4, 0x2b, 0xcc, 0xa9, 0xd7, 0x85, 0xf6, 0x9b, 0x14, 0x4f, 0x63, 0xe2,
-	0x93, 0x3f, 0x17, 0x44, 0x48, 0x6f, 0x0c, 0x9d, 0x02, 0x10, 0x9c, 0xa5, 0x82, 0xa0, 0xdb, 0xd0,
-	0xcc, 0x88, 0x58, 0xc4, 0xd2, 0x6d, 0x8c, 0x1a, 0x63, 0xc7, 0x37, 0x2b, 0xaf, 0x07, 0x9d, 0xd7,
-	0x54, 0xd8, 0xbe, 0x0f, 0xa1, 0x5b, 0x22, 0xd7, 0x38, 0x4f, 0xe0, 0xf6, 0x41, 0x2a, 0x49, 0x76,
-	0x8a, 0x43, 0x52, 0x11, 0x80, 0xee, 0x80, 0x43, 0x0b, 0x8b, 0x71, 0x3a, 0x07, 0xbc, 0xaf, 0xe1,
-	0xb3, 0x15, 0xbf, 0x6b, 0xb6, 0xfa, 0xd6, 0x72, 0xa9, 0x0a, 0xbe, 0x66, 0xaf, 0x5d, 0x70, 0x57,
-	0x1d, 0xaf, 0xd9, 0xec, 0x16, 0x0c, 0xf6, 0xa2, 0x3f, 0x70, 0x48, 0xd2, 0x70, 0xb9, 0x4f, 0x64,
-	0x91, 0x99, 0xdb, 0x70, 0xb3, 0x0a, 0xe7, 0x61, 0x94, 0xb6, 0x12, 0x7f, 0xc7, 0x52, 0x2a, 0x59,
-	0xf6, 0x69, 0xda, 0x7c, 0x70, 0x57, 0x1d, 0x8d, 0xb6, 0x09, 0x6c, 0x61, 0x63, 0xa3, 0x44, 0xb8,
-	0x8d, 0xd1, 0xda, 0x78, 0x6b, 0xf7, 0xe6, 0x93, 0xb2, 0xe8, 0x4f, 0x4a, 0x4f, 0xdf, 0x26, 0xea,
-	0x82, 0x4e, 0x0f, 0x85, 0x25, 0xbb, 0x0f, 0xdd, 0x12, 0x31, 0x8a, 0xbf, 0x02, 0xa4, 0xa0, 0x9a,
-	0xd8, 0x9b, 0xb0, 0x11, 0x93, 0x0f, 0x24, 0x36, 0x42, 0xf3, 0x85, 0xf7, 0x1d, 0x0c, 0x2a, 0x5c,
-	0xa3, 0xcf, 0x83, 0xf5, 0x58, 0xf0, 0x42, 0x58, 0xc7, 0x12, 0x76, 0x28, 0xb8, 0xaf, 0x6d, 0xb9,
-	0x16, 0x9f, 0xd6, 0xb5, 0x18, 0xc4, 0x68, 0xf9, 0x45, 0x69, 0xf1, 0xe9, 0xa7, 0x68, 0x41, 0x0f,
-	0xa0, 0x83, 0xa3, 0x28, 0x23, 0x42, 0x04, 0xa7, 0x38, 0xa1, 0xf1, 0xd2, 0xbd, 0xa1, 0xcd, 0x6d,
-	0x83, 0xfe, 0xa0, 0x41, 0xef, 0x85, 0x92, 0x6c, 0x85, 0x34, 0x92, 0xc7, 0xd0, 0xcc, 0xd8, 0x42,
-	0x96, 0xd9, 0xec, 0x59, 0xa2, 0x7d, 0x65, 0xf0, 0x8d, 0xdd, 0xfb, 0xf7, 0x06, 0x38, 0x65, 0x7e,
-	0xaf, 0x2e, 0x22, 0xba, 0x0f, 0xed, 0x94, 0xd0, 0xd9, 0x7c, 0xca, 0xb2, 0x40, 0x2e, 0x39, 0x31,
-	0x92, 0xb6, 0x0b, 0xf0, 0x64, 0xc9, 0x89, 0x12, 0x5e, 0x92, 0xc4, 0x52, 0xd0, 0xc8, 0x5d, 0xcb,
-	0x85, 0x17, 0xe8, 0xb1, 0x02, 0xd1, 0x0b, 0xb8, 0x53, 0xd2, 0xc8, 0x99, 0x24, 0x69, 0x44, 0xa2,
-	0x20, 0xa4, 0x59, 0xb8, 0xa0, 0x32, 0xa0, 0x91, 0xbb, 0x3e, 0x6a, 0x8c, 0xdb, 0xfe, 0x4e, 0xc1,
-	0x79, 0x63, 0x28, 0xaf, 0x72, 0xc6, 0x41, 0x54, 0x11, 0x23, 0x52, 0x8e, 0xdd, 0x8d, 0xaa, 0x98,
-	0xe3, 0x94, 0x63, 0x95, 0xdb, 0x85, 0xc0, 0x33, 0xe2, 0x36, 0xf3, 0xdc, 0xea, 0x05, 0xba, 0x0b,
-	0x30, 0x67, 0x71, 0x14, 0x48, 0x9a, 0x90, 0xcc, 0xdd, 0xd4, 0x3b, 0x39, 0x0a, 0x39, 0x51, 0x00,
-	0x7a, 0x04, 0xfd, 0x32, 0x32, 0xcf, 0x28, 0xcb, 0xa8, 0x5c, 0xba, 0x2d, 0xcd, 0xea, 0x15, 0x86,
-	0x9f, 0x0d, 0x8e, 0xee, 0x01, 0xc4, 0x58, 0xc8, 0x05, 0x57, 0xc1, 0x5c, 0x47, 0xb3, 0x2c, 0x44,
-	0x29, 0x10, 0x12, 0x4b, 0xe2, 0x42, 0xae, 0x40, 0x2f, 0xbc, 0xbf, 0x9a, 0xb0, 0x76, 0x28, 0xf8,
-	0x25, 0xb5, 0x7f, 0x04, 0xfd, 0x88, 0x84, 0x4c, 0x67, 0x84, 0x25, 0x3c, 0x26, 0x92, 0x44, 0x3a,
-	0xd7, 0x2d, 0xbf, 0x67, 0x0c, 0xaf, 0x0a, 0x1c, 0xed, 0x40, 0x2b, 0xc3, 0x1f, 0x83, 0x08, 0x4b,
-	0x6c, 0x32, 0xbd, 0x99, 0xe1, 0x8f, 0xaf, 0xb1, 0xc4, 0xe8, 0x16, 0x34, 0x63, 0xc1, 0x8b, 0x6c,
-	0xaa, 0xf0, 0x82, 0x1f, 0x44, 0x68, 0x08, 0xad, 0x70, 0x4e, 0xc2, 0xf7, 0x62, 0x91, 0xe8, 0xa4,
-	0xb5, 0xfd, 0x72, 0x8d, 0x1e, 0x03, 0xca, 0x48, 0x82, 0x69, 0x4a, 0xd3, 0x59, 0x10, 0xd3, 0x53,
-	0xa2, 0x8f, 0xd5, 0xd4, 0xac, 0x7e, 0x69, 0x39, 0x34, 0x06, 0x15, 0x4a, 0xa8, 0x6b, 0x9c, 0x86,
-	0xc4, 0xe4, 0xb1, 0x5c, 0xab, 0xcc, 0x60, 0x29, 0x33, 0x3a, 0xd5, 0xf7, 0x30, 0xcf, 0x9f, 0x85,
-	0xa8, 0x8b, 0x42, 0xf9, 0x87, 0x6f, 0x02, 0x73, 0xa1, 0x89, 0x70, 0x9d, 0xd1, 0x9a, 0xba, 0x28,
-	0x0a, 0xdd, 0x2b, 0x40, 0x43, 0x9b, 0x58, 0x34, 0x28, 0x69, 0x93, 0x73, 0xda, 0x18, 0x7a, 0x3a,
-	0x9a, 0x24, 0x81, 0xbe, 0xd9, 0x19, 0x8d, 0xdc, 0x2d, 0x7d, 0x6a, 0xbd, 0xcb, 0x09, 0xf1, 0x0d,
-	0x6a, 0x98, 0x93, 0x0a, 0x73, 0xbb, 0x64, 0x4e, 0x2c, 0xe6, 0x53, 0x18, 0xe8, 0x07, 0x28, 0x64,
-	0x71, 0x20, 0x16, 0x9c, 0xb3, 0x4c, 0x92, 0x48, 0xb8, 0xed, 0xd1, 0xda, 0xb8, 0xed, 0xa3, 0xc2,
-	0x74, 0x5c, 0x5a, 0xd0, 0x43, 0xe8, 0x45, 0xcb, 0x14, 0x27, 0x34, 0x0c, 0xe6, 0x4c, 0xc8, 0x14,
-	0x27, 0xc4, 0xed, 0xe8, 0xd0, 0x5d, 0x83, 0xbf, 0x35, 0x30, 0xda, 0x83, 0x0e, 0x5e, 0xc8, 0x39,
-	0x49, 0x25, 0x0d, 0xb1, 0xa4, 0x2c, 0x75, 0xbb, 0xa3, 0xc6, 0x78, 0x6b, 0x77, 0xc7, 0xee, 0x7b,
-	0x15, 0x82, 0x5f, 0x73, 0x40, 0xcf, 0x01, 0x12, 0x19, 0x90, 0x54, 0x66, 0xaa, 0x6d, 0xf6, 0xb4,
-	0xbb, 0xdd, 0x36, 0xdf, 0xc9, 0x37, 0xb9, 0xcd, 0x77, 0x92, 0xe2, 0x13, 0x1d, 0xc1, 0x20, 0x3f,
-	0x75, 0x10, 0x62, 0x8e, 0xa7, 0x34, 0xa6, 0x52, 0x79, 0xf7, 0xb5, 0xf7, 0xdd, 0x7a, 0x9b, 0xc8,
-	0x5e, 0x59, 0x24, 0x1f, 0x65, 0x2b, 0x18, 0x7a, 0x06, 0x4e, 0xca, 0x22, 0x12, 0x48, 0x3c, 0x13,
-	0x2e, 0xd2, 0x51, 0x06, 0x56, 0x94, 0x23, 0x16, 0x91, 0x13, 0x3c, 0x13, 0x7e, 0x2b, 0x35, 0x5f,
-	0xea, 0x29, 0x9a, 0xd2, 0x14, 0x67, 0x4b, 0x77, 0x30, 0x6a, 0x8c, 0xb7, 0x7d, 0xb3, 0xf2, 0xfe,
-	0x69, 0xc0, 0x86, 0xde, 0xf4, 0x7f, 0x75, 0x44, 0x15, 0x9e, 0x67, 0xe4, 0x94, 0x9e, 0x99, 0xbf,
-	0xc1, 0xac, 0xd0, 0x53, 0x70, 0x52, 0x72, 0x26, 0x83, 0x39, 0xe3, 0xc2, 0x5d, 0xd7, 0x5d, 0x11,
-	0xd9, 0x42, 0xc9, 0x99, 0x7c, 0xcb, 0xb8, 0xdf, 0x4a, 0xf3, 0x0f, 0xad, 0x33, 0x21, 0x32, 0xa3,
-	0xa1, 0xf9, 0x49, 0xcc, 0xca, 0xe3, 0xd0, 0xa9, 0x16, 0x46, 0xdd, 0x93, 0x6a, 0x69, 0xf2, 0xee,
-	0x98, 0xab, 0x47, 0x55, 0x93, 0xee, 0x91, 0x8f, 0xa1, 0x86, 0x06, 0xef, 0x49, 0x71, 0x9c, 0x7e,
-	0xd5, 0xf2, 0x23, 0x51, 0x4d, 0xbe, 0x75, 0xc2, 0x38, 0x8b, 0xd9, 0x6c, 0x89, 0x06, 0xb0, 0x91,
-	0xe8, 0x06, 0xd9, 0xd0, 0xa2, 0xd6, 0x13, 0xd5, 0x0b, 0xab, 0xbf, 0xda, 0x8d, 0xfa, 0xaf, 0xe6,
-	0xbd, 0x04, 0xa7, 0xbc, 0x0c, 0xea, 0xda, 0xc8, 0x3c, 0xda, 0xf9, 0x6b, 0x6b, 0x97, 0xac, 0xd8,
-	0xca, 0xb7, 0x68, 0xea, 0x19, 0x5d, 0xbd, 0x10, 0xaa, 0x50, 0xa7, 0xb1, 0x2a, 0x7c, 0x2e, 0x26,
-	0x5f, 0x78, 0x00, 0xad, 0xa2, 0xec, 0xde, 0x03, 0x68, 0xee, 0xc7, 0x6c, 0x8a, 0x63, 0xf4, 0x39,
-	0x38, 0x62, 0x29, 0x24, 0x49, 0x0a, 0xf1, 0x8e, 0xdf, 0xca, 0x81, 0x83, 0xc8, 0x3b, 0x86, 0x4d,
-	0x53, 0x00, 0x95, 0x1b, 0xb6, 0x90, 0x33, 0xa6, 0x1a, 0x50, 0xfd, 0x2d, 0xea, 0x17, 0x96, 0x72,
-	0xce, 0x51, 0xed, 0xaf, 0x28, 0xab, 0x49, 0xe0, 0xa6, 0xa9, 0xe0, 0xee, 0xdf, 0x4d, 0x70, 0xf6,
-	0xf5, 0xb1, 0xf6, 0x38, 0x45, 0xdf, 0x43, 0x33, 0x1f, 0xc0, 0x90, 0x6b, 0x1d, 0xb6, 0x32, 0xcb,
-	0x0d, 0x77, 0x2e, 0xb0, 0x98, 0x17, 0xf5, 0x25, 0x6c, 0x9a, 0x99, 0x0a, 0xd9, 0xac, 0xea, 0x80,
-	0x36, 0x1c, 0x5e, 0x64, 0x32, 0x11, 0x7e, 0x85, 0x6e, 0x6d, 0x14, 0x44, 0x5f, 0x5a, 0xf4, 0x8b,
-	0xc7, 0xcb, 0xa1, 0x77, 0x15, 0xc5, 0x44, 0xfe, 0x0d, 0x7a, 0xf5, 0xc1, 0x0f, 0x5d, 0xe8, 0x57,
-	0x53, 0x7b, 0xff, 0x4a, 0x8e, 0x09, 0xfe, 0x13, 0x6c, 0xdb, 0xa3, 0x20, 0xba, 0x77, 0xd1, 0x60,
-	0x76, 0x3e, 0xf7, 0x0c, 0xbf, 0xb8, 0xd4, 0x6e, 0x02, 0xfe, 0x0e, 0xbd, 0xfa, 0x28, 0x58, 0x51,
-	0x7b, 0xc9, 0x80, 0x59, 0x51, 0x7b, 0xd9, 0x2c, 0xf9, 0xac, 0xa1, 0x0b, 0x95, 0xcf, 0x80, 0xd5,
-	0x42, 0x55, 0x26, 0xc5, 0x6a, 0xa1, 0xaa, 0x23, 0x23, 0x3a, 0x82, 0x2d, 0x6b, 0x0c, 0x44, 0x77,
-	0x6b, 0xd4, 0x9a, 0xac, 0x7b, 0x97, 0x99, 0xab, 0x8a, 0xf4, 0x24, 0x58, 0x53, 0x64, 0xcf, 0x8b,
-	0x35, 0x45, 0x95, 0xc1, 0x31, 0x57, 0x54, 0x4e, 0x79, 0x35, 0x45, 0xf5, 0x81, 0xb2, 0xa6, 0x68,
-	0x65, 0x38, 0x7c, 0xd6, 0x98, 0x36, 0xf5, 0xdb, 0xf5, 0xfc, 0xbf, 0x00, 0x00, 0x00, 0xff, 0xff,
-	0xeb, 0x09, 0x44, 0x2b, 0x67, 0x0d, 0x00, 0x00,
+	// 2680 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x59, 0xcd, 0x72, 0x1b, 0xc7,
+	0x11, 0x2e, 0x80, 0x24, 0x88, 0x6d, 0x12, 0x24, 0x30, 0x20, 0xa9, 0x15, 0xf4, 0x47, 0xaf, 0xec,
+	0x14, 0x6d, 0xfd, 0x58, 0xa2, 0x6d, 0x3a, 0xa9, 0x54, 0xca, 0x66, 0x44, 0x49, 0x66, 0x85, 0x92,
+	0x55, 0x43, 0xa6, 0x2a, 0x55, 0x29, 0xd7, 0xd6, 0x10, 0x3b, 0x04, 0x27, 0x5a, 0xec, 0xae, 0x77,
+	0x06, 0x14, 0x79, 0x4d, 0xe5, 0x9a, 0x43, 0x4e, 0xc9, 0x39, 0x87, 0x3c, 0x46, 0x8e, 0x79, 0x04,
+	0xbf, 0x48, 0x8e, 0xc9, 0x21, 0x35, 0x3f, 0xbb, 0x98, 0x5d, 0x2c, 0x00, 0x5a, 0xce, 0x09, 0x98,
+	0xee, 0xaf, 0x7b, 0xba, 0x7b, 0x7b, 0xa6, 0x7b, 0x66, 0x60, 0x75, 0x10, 0x33, 0xce, 0xf8, 0xe3,
+	0x24, 0x8d, 0x45, 0x8c, 0x1c, 0x3d, 0x22, 0x09, 0xf3, 0x1e, 0x40, 0xeb, 0x79, 0x44, 0x4e, 0x43,
+	0x8a, 0xe9, 0xf7, 0x23, 0xca, 0x05, 0xea, 0x41, 0x93, 0x45, 0x5c, 0x90, 0xa8, 0x4f, 0xdd, 0xda,
+	0x76, 0x6d, 0xc7, 0xc1, 0xf9, 0xd8, 0xdb, 0x81, 0xb5, 0x0c, 0xcc, 0x93, 0x38, 0xe2, 0x14, 0x6d,
+	0x41, 0x23, 0xa5, 0x7c, 0x14, 0x0a, 0x83, 0x35, 0x23, 0xef, 0x21, 0xac, 0x1d, 0x30, 0x7e, 0x5d,
+	0xbd, 0x1f, 0xc3, 0x7a, 0x8e, 0x9e, 0xa3, 0x18, 0xc3, 0xd6, 0x61, 0x24, 0x68, 0x7a, 0x46, 0xfa,
+	0xb4, 0x68, 0xf8, 0x6d, 0x70, 0x58, 0xc6, 0x31, 0x42, 0x63, 0x42, 0x61, 0xfa, 0x7a, 0x69, 0xfa,
+	0xa7, 0x70, 0x63, 0x42, 0xe7, 0x1c, 0x33, 0x8e, 0x2d, 0x91, 0x92, 0xa3, 0xef, 0x6f, 0xc7, 0x2e,
+	0xb8, 0x93, 0x4a, 0xe7, 0x18, 0xf2, 0x14, 0xba, 0xfb, 0xc1, 0x1f, 0x48, 0x9f, 0x46, 0xfd, 0xab,
+	0x97, 0x54, 0x5c, 0x27, 0xda, 0x5b, 0xb0, 0x51, 0x14, 0xd1, 0x53, 0x48, 0x9f, 0x72, 0xfa, 0xab,
+	0x38, 0x62, 0x22, 0x4e, 0x7f, 0xba, 0x4f, 0x18, 0xdc, 0x49, 0xa5, 0xc6, 0xa7, 0x3d, 0x58, 0x21,
+	0x86, 0xc7, 0x28, 0x77, 0x6b, 0xdb, 0x0b, 0x3b, 0x2b, 0xbb, 0x1b, 0x8f, 0xf3, 0xe4, 0x7c, 0x9c,
+	0x4b, 0x62, 0x1b, 0xa8, 0x92, 0xeb, 0xf4, 0x88, 0x5f, 0xd3, 0xdd, 0x0e, 0xac, 0xe7, 0x68, 0xe3,
+	0xe9, 0x0b, 0x40, 0x92, 0x54, 0x72, 0x72, 0x03, 0x96, 0x42, 0x7a, 0x41, 0x43, 0xa3, 0x41, 0x0f,
+	0x66, 0x3a, 0xf7, 0x0b, 0xe8, 0x16, 0xf4, 0x18, 0xbf, 0x3c, 0x58, 0x0c, 0x79, 0x92, 0x39, 0xb4,
+	0x66, 0x39, 0x74, 0xc4, 0x13, 0xac, 0x78, 0xde, 0x73, 0xe8, 0x48, 0xd1, 0xe7, 0x97, 0x49, 0x9c,
+	0x8a, 0xf7, 0xb7, 0xe0, 0x6b, 0x40, 0xb6, 0x9a, 0xd9, 0xc9, 0x82, 0x10, 0x2c, 0x06, 0x44, 0x10,
+	0xa5, 0x65, 0x15, 0xab, 0xff, 0x3a, 0x98, 0x98, 0xfd, 0x98, 0x60, 0x1a, 0xb4, 0x09, 0xe6, 0x50,
+	0x9a, 0x80, 0xd9, 0xb5, 0x82, 0xf9, 0x11, 0xac, 0x91, 0x20, 0x48, 0x29, 0xe7, 0xfe, 0x19, 0x19,
+	0xb2, 0xf0, 0xca, 0x38, 0xd4, 0x32, 0xd4, 0x17, 0x8a, 0x58, 0xb0, 0x60, 0xa1, 0x64, 0xc1, 0x57,
+	0xd0, 0x2d, 0x4c, 0x67, 0x5c, 0xde, 0x81, 0x46, 0x1a, 0x8f, 0x44, 0x9e, 0x46, 0x6d, 0x2b, 0xea,
+	0x58, 0x32, 0xb0, 0xe1, 0x7b, 0x7f, 0xaf, 0xc1, 0xfa, 0x49, 0x4a, 0xfa, 0xf4, 0x78, 0xec, 0xf2,
+	0x16, 0x34, 0xa8, 0x5a, 0xf8, 0xca, 0xdc, 0x26, 0x36, 0x23, 0x74, 0x17, 0x20, 0x4f, 0x73, 0xee,
+	0xd6, 0xb7, 0x17, 0x76, 0x1c, 0x6c, 0x51, 0x24, 0x3f, 0x60, 0x29, 0xed, 0x0b, 0x16, 0x47, 0xdc,
+	0x5d, 0xd0, 0xfc, 0x31, 0x05, 0xdd, 0x02, 0x27, 0x09, 0x46, 0xbe, 0xb8, 0x4a, 0x28, 0x77, 0x17,
+	0x15, 0xbb, 0x99, 0x04, 0xa3, 0x13, 0x39, 0x2e, 0x78, 0xb9, 0x54, 0xf2, 0xf2, 0x13, 0x68, 0x8f,
+	0x6d, 0x9c, 0xb3, 0x05, 0xbc, 0x00, 0x74, 0x12, 0x27, 0x71, 0x18, 0x0f, 0xec, 0x1d, 0xe0, 0xc7,
+	0xe7, 0x92, 0x0f, 0xdd, 0x82, 0x9e, 0x39, 0xc9, 0xf4, 0x04, 0x1a, 0x83, 0x94, 0x24, 0xe7, 0x3a,
+	0x2e, 0x2b, 0xbb, 0xae, 0x15, 0xf1, 0x5c, 0x8f, 0x04, 0x60, 0x83, 0xf3, 0x36, 0xa1, 0x7b, 0x68,
+	0x26, 0x3b, 0x62, 0x3c, 0xb3, 0xd4, 0x3b, 0x84, 0x8d, 0x22, 0xd9, 0x4c, 0xfc, 0x54, 0x6e, 0x3a,
+	0x9a, 0x9e, 0x7d, 0xd5, 0xae, 0x35, 0x47, 0x26, 0x83, 0xc7, 0x28, 0xef, 0x31, 0xb4, 0xdf, 0xc4,
+	0x21, 0xbb, 0xf6, 0x56, 0xf8, 0xdf, 0x1a, 0x74, 0x2c, 0x81, 0x39, 0x1e, 0x7f, 0x01, 0x2b, 0x49,
+	0x4a, 0xcf, 0xd8, 0xa5, 0xcf, 0xa9, 0xc8, 0xdc, 0xb6, 0xf7, 0xab, 0x37, 0x8a, 0x2b, 0xbf, 0x19,
+	0x24, 0xd9, 0x5f, 0x8e, 0x1e, 0x42, 0x53, 0x90, 0x81, 0x96, 0x59, 0x50, 0x32, 0x1d, 0x3b, 0x54,
+	0x64, 0x20, 0x05, 0x96, 0x85, 0xfa, 0xe5, 0xe8, 0x11, 0x34, 0x13, 0x69, 0x11, 0x33, 0x19, 0x53,
+	0x44, 0x6b, 0x63, 0x71, 0x0e, 0x41, 0xbf, 0x04, 0x20, 0x9c, 0xb3, 0x41, 0x34, 0xa4, 0x91, 0x50,
+	0x69, 0xb4, 0xb2, 0x7b, 0x6b, 0x42, 0x60, 0x3f, 0x87, 0x60, 0x0b, 0xee, 0xfd, 0xb5, 0x06, 0x1b,
+	0x07, 0xf4, 0x8c, 0x45, 0x34, 0x38, 0xa6, 0x62, 0x3f, 0x08, 0xb2, 0x98, 0x95, 0x3c, 0xad, 0xbd,
+	0x87, 0xa7, 0xf5, 0xb9, 0x9e, 0xce, 0x5a, 0xe5, 0x9f, 0xc2, 0x66, 0xc9, 0xb0, 0x39, 0x8b, 0xe0,
+	0x7b, 0xb8, 0x31, 0x16, 0x38, 0xa0, 0x21, 0x15, 0x79, 0x41, 0xbe, 0x37, 0xe9, 0x8c, 0x53, 0x30,
+	0xfb, 0x66, 0xc9, 0x6c, 0xe7, 0x7a, 0x36, 0xee, 0x82, 0x3b, 0x39, 0xe5, 0x1c, 0x33, 0xbf, 0xcb,
+	0x12, 0xd4, 0x0a, 0xb6, 0xfd, 0xc5, 0x6b, 0xf3, 0xbf, 0xf8, 0xac, 0x25, 0xfc, 0x00, 0x3a, 0x96,
+	0xfa, 0x39, 0xb6, 0xbc, 0x82, 0xae, 0x06, 0x17, 0xc3, 0xd5, 0x2b, 0x99, 0xe3, 0x5c, 0x73, 0xee,
+	0xc7, 0xb0, 0x51, 0x54, 0x37, 0x67, 0xfa, 0x11, 0xf4, 0xca, 0xc9, 0x69, 0xed, 0xc8, 0xc5, 0xbc,
+	0xae, 0xfd, 0xa8, 0xbc, 0x9e, 0x69, 0xe6, 0x17, 0x70, 0xab, 0x72, 0xda, 0x39, 0xd6, 0xfe, 0xbb,
+	0x0e, 0x4e, 0xde, 0x8e, 0xcc, 0xe9, 0x87, 0xee, 0x43, 0x2b, 0xa2, 0x6c, 0x70, 0x7e, 0x1a, 0xa7,
+	0x6a, 0xeb, 0x37, 0x36, 0xac, 0x66, 0x44, 0xb9, 0xfd, 0xcb, 0x52, 0x98, 0x83, 0xf8, 0x15, 0x67,
+	0x81, 0xc9, 0xaf, 0x5c, 0xf4, 0x58, 0x12, 0xd1, 0x57, 0x70, 0x3b, 0x87, 0xd1, 0x4b, 0x41, 0xa3,
+	0x80, 0x06, 0x7e, 0x9f, 0xa5, 0xfd, 0x11, 0x13, 0x3e, 0x0b, 0xdc, 0xc5, 0xed, 0xda, 0x4e, 0x0b,
+	0xdf, 0xcc, 0x30, 0xcf, 0x0d, 0xe4, 0x99, 0x46, 0x1c, 0x06, 0x05, 0x63, 0x78, 0x94, 0x10, 0x77,
+	0xa9, 0x68, 0xcc, 0x71, 0x94, 0x10, 0x59, 0x2c, 0x46, 0x9c, 0x0c, 0xa8, 0xdb, 0xd0, 0xc5, 0x42,
+	0x0d, 0xd0, 0x1d, 0x80, 0xf3, 0x38, 0x0c, 0x7c, 0xc1, 0x86, 0x34, 0x75, 0x97, 0xd5, 0x4c, 0x8e,
+	0xa4, 0x9c, 0x48, 0x02, 0x7a, 0x00, 0x9d, 0x5c, 0x73, 0x92, 0xb2, 0x38, 0x65, 0xe2, 0xca, 0x6d,
+	0x2a, 0x54, 0x3b, 0x63, 0xbc, 0x31, 0x74, 0x59, 0x29, 0x43, 0xc2, 0xc5, 0x28, 0x91, 0xca, 0x5c,
+	0x47, 0xa1, 0x2c, 0x8a, 0xb4, 0x80, 0x0b, 0x22, 0xa8, 0x0b, 0xda, 0x02, 0x35, 0xf0, 0xfe, 0xd2,
+	0x80, 0x85, 0x23, 0x9e, 0x4c, 0x29, 0x66, 0x0f, 0xa0, 0x13, 0xd0, 0x7e, 0xac, 0x22, 0x12, 0x0f,
+	0x93, 0x90, 0x0a, 0x1a, 0xa8, 0x58, 0x37, 0x71, 0xdb, 0x30, 0x9e, 0x65, 0x74, 0xb9, 0xc8, 0x53,
+	0xf2, 0xce, 0x57, 0xfd, 0x8f, 0x8e, 0xf4, 0x72, 0x4a, 0xde, 0x1d, 0x10, 0x41, 0xd0, 0x26, 0x34,
+	0x42, 0x9e, 0x64, 0xd1, 0x94, 0xea, 0x79, 0x72, 0x18, 0xc8, 0x2c, 0xea, 0x9f, 0xd3, 0xfe, 0x5b,
+	0x3e, 0x1a, 0xaa, 0xa0, 0xb5, 0x70, 0x3e, 0x46, 0x8f, 0x00, 0xa5, 0x74, 0x48, 0x58, 0xc4, 0xa2,
+	0x81, 0x1f, 0xb2, 0x33, 0xaa, 0xdc, 0x6a, 0x28, 0x54, 0x27, 0xe7, 0x1c, 0x19, 0x86, 0x54, 0xc5,
+	0x65, 0x62, 0xcb, 0x84, 0xd4, 0x71, 0xcc, 0xc7, 0x32, 0x32, 0x44, 0x88, 0x94, 0x9d, 0xaa, 0xee,
+	0x45, 0xc7, 0xcf, 0xa2, 0xc8, 0x44, 0x61, 0xc9, 0xc5, 0xe7, 0xbe, 0x69, 0x91, 0x28, 0x77, 0x1d,
+	0xb5, 0x2a, 0x5b, 0x92, 0xba, 0x9f, 0x11, 0x0d, 0x6c, 0xcf, 0x82, 0x41, 0x0e, 0xdb, 0x1b, 0xc3,
+	0x76, 0xa0, 0xad, 0xb4, 0x09, 0xea, 0xab, 0x7e, 0x28, 0x65, 0x81, 0xbb, 0xa2, 0xbc, 0x56, 0xb3,
+	0x9c, 0x50, 0x6c, 0xa8, 0x06, 0xb9, 0x57, 0x40, 0xae, 0xe6, 0xc8, 0x3d, 0x0b, 0xf9, 0x29, 0x74,
+	0xd5, 0xb9, 0xb2, 0x1f, 0x87, 0x3e, 0x1f, 0x25, 0xb2, 0x15, 0xa5, 0x01, 0x77, 0x5b, 0xdb, 0x0b,
+	0x3b, 0x2d, 0x8c, 0x32, 0xd6, 0x71, 0xce, 0x41, 0x1f, 0x43, 0x3b, 0xb8, 0x8a, 0xc8, 0x90, 0xf5,
+	0xfd, 0xf3, 0x98, 0x8b, 0x88, 0x0c, 0xa9, 0xbb, 0xa6, 0x54, 0xaf, 0x1b, 0xfa, 0x37, 0x86, 0x8c,
+	0xf6, 0x61, 0x8d, 0x8c, 0xc4, 0x39, 0x8d, 0x04, 0xeb, 0x13, 0xd9, 0x54, 0xb9, 0xeb, 0x6a, 0x2f,
+	0xb8, 0x69, 0x1f, 0x13, 0x0a, 0x00, 0x5c, 0x12, 0x40, 0x9f, 0x01, 0x0c, 0x85, 0x4f, 0x23, 0x91,
+	0xca, 0x2d, 0xad, 0xbd, 0x5d, 0x2b, 0xd5, 0xb2, 0x57, 0xe2, 0xb9, 0xe6, 0x61, 0x67, 0x98, 0xfd,
+	0x45, 0xaf, 0xa1, 0xab, 0xbd, 0xf6, 0xfb, 0x24, 0x21, 0xa7, 0x2c, 0x64, 0x42, 0x4a, 0x77, 0x94,
+	0xf4, 0x9d, 0x72, 0x73, 0x99, 0x3e, 0xb3, 0x40, 0x18, 0xa5, 0x13, 0x34, 0xf4, 0x04, 0x9c, 0x28,
+	0x0e, 0xa8, 0x2f, 0xc8, 0x80, 0xbb, 0x68, 0xbb, 0x56, 0x6a, 0x66, 0x5e, 0xc7, 0x01, 0x3d, 0x21,
+	0x03, 0x8e, 0x9b, 0x91, 0xf9, 0x27, 0x77, 0xa2, 0x53, 0x16, 0x91, 0xf4, 0xca, 0xed, 0xaa, 0x76,
+	0xdd, 0x8c, 0xbc, 0x7f, 0xd4, 0x61, 0x49, 0x4d, 0xfa, 0xd3, 0x7a, 0xec, 0x2d, 0x68, 0xe8, 0x12,
+	0x68, 0x56, 0x83, 0x19, 0xa1, 0x4f, 0xc1, 0x89, 0xe8, 0xa5, 0xf0, 0xcf, 0xe3, 0x24, 0x6b, 0x40,
+	0x90, 0x6d, 0x28, 0xbd, 0x14, 0xdf, 0xc4, 0x09, 0x6e, 0x46, 0xfa, 0x8f, 0xb2, 0x73, 0x48, 0x45,
+	0xca, 0xfa, 0x66, 0x91, 0x98, 0x91, 0xcc, 0x79, 0xb9, 0x61, 0xa5, 0x11, 0x09, 0xd5, 0xc2, 0x68,
+	0xe2, 0x7c, 0x2c, 0x73, 0x5e, 0x4e, 0x47, 0x53, 0x6b, 0x45, 0x58, 0x14, 0xa9, 0x93, 0xf4, 0x05,
+	0xbb, 0xa0, 0x6a, 0x3d, 0x34, 0xb1, 0x19, 0xa1, 0x1b, 0xb0, 0x3c, 0x4a, 0xfc, 0x20, 0x7e, 0x17,
+	0xa9, 0x2d, 0xa4, 0x89, 0x1b, 0xa3, 0xe4, 0x20, 0x7e, 0x17, 0xc9, 0x93, 0x8d, 0x8a, 0x2c, 0xa8,
+	0x9c, 0x53, 0xff, 0xbd, 0x37, 0xd0, 0xd0, 0x6d, 0x8a, 0x6c, 0xc3, 0x59, 0xe2, 0x1b, 0x77, 0xb3,
+	0x1e, 0x30, 0x31, 0xcc, 0x8f, 0xa1, 0x3d, 0x24, 0xfc, 0x6d, 0x48, 0xa3, 0x81, 0x38, 0xf7, 0x53,
+	0x12, 0x0d, 0xb2, 0x0d, 0x7b, 0x7d, 0x4c, 0xc7, 0x92, 0xec, 0xbd, 0x06, 0x27, 0x6f, 0x7c, 0xe4,
+	0x94, 0x2a, 0x71, 0xb5, 0x3e, 0xf5, 0x5f, 0x95, 0x72, 0x05, 0xa0, 0x55, 0x0d, 0x90, 0x96, 0xc5,
+	0x39, 0xc4, 0x7b, 0x02, 0x0d, 0xdd, 0x14, 0x55, 0x2a, 0xcb, 0x7c, 0xaa, 0x5b, 0x3e, 0xfd, 0xa7,
+	0x06, 0xf0, 0x2c, 0x8e, 0x02, 0xa6, 0xcf, 0x17, 0x3b, 0xd2, 0x76, 0xd1, 0x3f, 0xf7, 0xc7, 0x0d,
+	0x8e, 0x51, 0xb1, 0xa6, 0xe8, 0x63, 0x6b, 0xbf, 0x04, 0xb7, 0x8c, 0xf4, 0xe3, 0x44, 0x9f, 0x5b,
+	0xb4, 0xb7, 0x9b, 0x45, 0x89, 0x6f, 0x35, 0x13, 0x79, 0xd0, 0xd2, 0x82, 0xa6, 0x45, 0x32, 0xe9,
+	0xb2, 0xa2, 0x88, 0xc6, 0xfa, 0xa7, 0xb0, 0x59, 0xc0, 0xe4, 0x9a, 0xf5, 0x7e, 0x8a, 0x2c, 0x6c,
+	0xa6, 0x36, 0xcf, 0xdd, 0x25, 0x3b, 0x77, 0xef, 0x00, 0xa8, 0xb5, 0xa3, 0xcb, 0xa6, 0x2e, 0x46,
+	0x8e, 0xa2, 0xc8, 0x9a, 0xe9, 0x7d, 0x08, 0xab, 0xaf, 0x54, 0x72, 0xed, 0xab, 0xf3, 0x95, 0x54,
+	0x72, 0x41, 0xc2, 0x91, 0x0e, 0x5b, 0x0b, 0xeb, 0x81, 0xf7, 0x01, 0x38, 0x27, 0x64, 0x30, 0x13,
+	0xf2, 0x21, 0xac, 0xfe, 0x56, 0x25, 0x4e, 0x15, 0xaa, 0x99, 0xa1, 0x7e, 0xa8, 0xc1, 0xf2, 0xbe,
+	0x39, 0xc9, 0x3d, 0x80, 0x8e, 0xb6, 0x2c, 0x60, 0x3c, 0x89, 0xb9, 0x8a, 0xbf, 0x09, 0x75, 0x5b,
+	0x31, 0x0e, 0xc6, 0x74, 0xb4, 0x07, 0x20, 0xa3, 0x60, 0x96, 0x45, 0x5d, 0xad, 0xf6, 0x1b, 0xf6,
+	0x8e, 0x63, 0x39, 0x81, 0x1d, 0x4e, 0x85, 0x26, 0xa0, 0x47, 0xb0, 0x2c, 0xe5, 0x04, 0x19, 0xb8,
+	0x0b, 0x13, 0xdb, 0x54, 0xee, 0x13, 0x6e, 0x70, 0x2a, 0x4e, 0xc8, 0x00, 0x7d, 0x09, 0x2b, 0x12,
+	0x9e, 0xad, 0x88, 0xc5, 0x89, 0x79, 0x6c, 0x1f, 0xd5, 0x3c, 0x9a, 0xe0, 0xfd, 0xa9, 0x06, 0xce,
+	0xb1, 0x20, 0x82, 0xaa, 0x6e, 0xa9, 0x2a, 0xf7, 0xbe, 0x00, 0xe8, 0xe7, 0x69, 0x66, 0x3c, 0xd8,
+	0xb4, 0x34, 0x8f, 0x73, 0x10, 0x5b, 0x40, 0xf4, 0x10, 0x96, 0x49, 0x7e, 0x18, 0xae, 0x95, 0xb6,
+	0x0e, 0x13, 0x4a, 0x9c, 0x41, 0x3c, 0x0c, 0x0d, 0xdd, 0x8a, 0x55, 0x9a, 0xf0, 0x39, 0x00, 0xcf,
+	0x6c, 0xac, 0x3a, 0x6c, 0xe5, 0x0e, 0x60, 0x0b, 0xe7, 0xfd, 0xad, 0x06, 0xed, 0x72, 0x7f, 0x27,
+	0x97, 0xb8, 0xea, 0xff, 0xc2, 0xd0, 0x2f, 0xb5, 0xb6, 0xeb, 0x86, 0xfe, 0xc6, 0x90, 0xd1, 0xe7,
+	0xb0, 0x15, 0xd0, 0x33, 0x32, 0x0a, 0x85, 0x5f, 0x10, 0xc9, 0x76, 0xd1, 0x0d, 0xc3, 0x3d, 0xb4,
+	0xe4, 0xae, 0x64, 0x93, 0x15, 0x52, 0xf2, 0x76, 0xac, 0x5d, 0x5f, 0x05, 0xac, 0x4a, 0x62, 0xa6,
+	0xda, 0x4b, 0x60, 0xad, 0x58, 0xa9, 0x64, 0xe1, 0x2c, 0xd6, 0x2a, 0x9d, 0xf7, 0x3a, 0x0a, 0xa8,
+	0xc8, 0x52, 0x4d, 0xe3, 0x23, 0x28, 0x51, 0xfd, 0xb7, 0x34, 0xb3, 0xac, 0x53, 0xe4, 0xfc, 0x86,
+	0x5e, 0x79, 0x5f, 0x41, 0x33, 0x3b, 0x89, 0xa3, 0x2e, 0x2c, 0x0d, 0x55, 0xc7, 0xa8, 0x17, 0xc2,
+	0xe2, 0x50, 0x36, 0x87, 0xc5, 0xde, 0xa3, 0x5e, 0xee, 0x3d, 0xbc, 0xaf, 0xc1, 0xc9, 0xab, 0xa3,
+	0xac, 0xa3, 0x42, 0x6b, 0x63, 0x95, 0x07, 0xf2, 0x6c, 0x2a, 0x6c, 0xc1, 0xbc, 0x4f, 0x00, 0x4d,
+	0x56, 0x48, 0xb9, 0xde, 0xce, 0x42, 0xb9, 0xb7, 0x99, 0x55, 0xa9, 0x06, 0x1e, 0x40, 0x33, 0xab,
+	0x83, 0xde, 0x47, 0xd0, 0x78, 0x19, 0xc6, 0xa7, 0x24, 0x94, 0x9b, 0x37, 0xbf, 0xe2, 0x82, 0x0e,
+	0x33, 0xe3, 0x1d, 0xdc, 0xd4, 0x84, 0xc3, 0xc0, 0xfb, 0x63, 0x0d, 0x9a, 0xd9, 0x45, 0x40, 0x65,
+	0x16, 0x15, 0xa4, 0xeb, 0x45, 0x69, 0x55, 0x2a, 0x53, 0x4a, 0xac, 0x9e, 0x49, 0x7f, 0xb7, 0x96,
+	0xa4, 0x8e, 0x7b, 0xa6, 0xe2, 0x2d, 0xd0, 0x62, 0xf9, 0x16, 0xc8, 0x3b, 0x86, 0x65, 0x53, 0x16,
+	0xe5, 0x07, 0x8a, 0x47, 0x62, 0x10, 0xcb, 0xb6, 0xb0, 0x7c, 0x42, 0xe8, 0x64, 0x9c, 0xfc, 0x92,
+	0x57, 0x36, 0xa5, 0x59, 0xb1, 0x35, 0xc6, 0x2d, 0x9b, 0xba, 0xea, 0xfd, 0xb9, 0x06, 0xad, 0xc2,
+	0x35, 0xca, 0x94, 0x72, 0xbf, 0x0b, 0x4b, 0xb2, 0x65, 0xc8, 0x56, 0xc8, 0xed, 0x69, 0xb7, 0x30,
+	0x32, 0xb2, 0x58, 0x43, 0xa5, 0x0c, 0x0d, 0x06, 0x34, 0xbb, 0x8e, 0x98, 0x2a, 0xf3, 0x3c, 0x18,
+	0x50, 0xac, 0xa1, 0xde, 0x0f, 0x75, 0xe8, 0x4c, 0x28, 0x94, 0x05, 0x59, 0xb5, 0x35, 0xf9, 0xa7,
+	0x69, 0xc8, 0xe1, 0x61, 0x30, 0x3b, 0xee, 0xb2, 0xfc, 0x73, 0x3a, 0x0a, 0x62, 0x09, 0x56, 0x3b,
+	0x45, 0x13, 0x5b, 0x14, 0x79, 0x62, 0x97, 0x0d, 0x79, 0x92, 0x52, 0x2e, 0x4f, 0x7f, 0x8b, 0x1a,
+	0x10, 0xf2, 0xe4, 0x8d, 0xa6, 0xc8, 0xde, 0x22, 0x6f, 0x1c, 0xcd, 0xd5, 0x59, 0x36, 0x96, 0xbc,
+	0xf8, 0x82, 0xa6, 0x61, 0x4c, 0x82, 0xac, 0xef, 0xc8, 0xc6, 0x92, 0x47, 0x84, 0x20, 0xfd, 0x73,
+	0x1a, 0xa8, 0xae, 0xa3, 0x89, 0xf3, 0x71, 0x65, 0x67, 0xdc, 0xac, 0xec, 0x8c, 0xff, 0xaf, 0x1d,
+	0xb9, 0xf7, 0xaf, 0x05, 0xe8, 0x4c, 0x44, 0x5d, 0xe6, 0xf2, 0x59, 0x1a, 0x0f, 0xb3, 0x5c, 0x96,
+	0xff, 0xd1, 0x1a, 0xd4, 0x45, 0x6c, 0x82, 0x59, 0x17, 0x31, 0x6a, 0xc3, 0x82, 0x08, 0x2f, 0x4c,
+	0x41, 0x96, 0x7f, 0xad, 0x5e, 0x6c, 0xb1, 0xd0, 0x8b, 0xdd, 0x93, 0x37, 0xed, 0x43, 0x16, 0xf9,
+	0x83, 0x34, 0x1e, 0x25, 0xa6, 0x51, 0x03, 0x45, 0x7a, 0x29, 0x29, 0xe8, 0xe7, 0xe0, 0x2a, 0x97,
+	0xf2, 0x9c, 0xb5, 0xac, 0x6e, 0x28, 0xab, 0xb7, 0x24, 0x3f, 0xcf, 0xdc, 0xb1, 0x97, 0x7b, 0x70,
+	0x43, 0x49, 0xe6, 0x47, 0xc1, 0xb1, 0xe0, 0xb2, 0x12, 0xdc, 0x94, 0xec, 0xd7, 0x86, 0x3b, 0x96,
+	0x7b, 0x08, 0x68, 0x48, 0x2e, 0xfd, 0x90, 0x45, 0x6f, 0xfd, 0x53, 0x12, 0x05, 0xef, 0x58, 0x20,
+	0xce, 0x55, 0xc0, 0xeb, 0xb8, 0x3d, 0x24, 0x97, 0x47, 0x2c, 0x7a, 0xfb, 0xeb, 0x8c, 0x8e, 0xf6,
+	0xe1, 0x8e, 0x44, 0xcb, 0xcf, 0x9f, 0x5e, 0xc8, 0xab, 0xd9, 0xb2, 0xa0, 0xa3, 0x04, 0x7b, 0x43,
+	0x72, 0x89, 0x73, 0x4c, 0x51, 0xc5, 0x67, 0xb0, 0x39, 0x8a, 0xb4, 0x02, 0x1a, 0x8c, 0x25, 0xf5,
+	0x57, 0xa9, 0xe3, 0x8d, 0x31, 0x33, 0x97, 0xe1, 0xe8, 0x13, 0xe8, 0xc8, 0x9a, 0x6f, 0x2a, 0x82,
+	0x89, 0xed, 0x8a, 0x0a, 0xdf, 0xba, 0xa0, 0x07, 0x9a, 0xae, 0xab, 0xf7, 0xee, 0x3f, 0x57, 0xc1,
+	0x79, 0xa9, 0x16, 0xd2, 0x7e, 0xc2, 0xd0, 0xaf, 0xa0, 0xa1, 0xdf, 0x92, 0x90, 0x7d, 0x31, 0x5a,
+	0x78, 0xb2, 0xea, 0xdd, 0xac, 0xe0, 0x98, 0x7b, 0x88, 0xaf, 0x61, 0xd9, 0x3c, 0x01, 0x21, 0x1b,
+	0x55, 0x7c, 0x6b, 0xea, 0xf5, 0xaa, 0x58, 0x46, 0xc3, 0xef, 0x60, 0xbd, 0xf4, 0xaa, 0x85, 0x3e,
+	0x28, 0x5c, 0x9f, 0x56, 0xbd, 0xa2, 0xf5, 0xbc, 0x59, 0x10, 0xa3, 0xf9, 0xf7, 0xd0, 0x2e, 0xbf,
+	0x53, 0xa1, 0x4a, 0xb9, 0x92, 0xb5, 0xf7, 0x67, 0x62, 0x8c, 0xf2, 0x6f, 0x61, 0xd5, 0x7e, 0x9d,
+	0x42, 0x77, 0xab, 0xde, 0x83, 0xc6, 0xd7, 0xbb, 0xbd, 0x7b, 0x53, 0xf9, 0x46, 0xe1, 0x77, 0xd0,
+	0x2e, 0xbf, 0x40, 0x15, 0xac, 0x9d, 0xf2, 0xe6, 0xd5, 0xbb, 0x3f, 0x13, 0xa3, 0x95, 0x3f, 0xa9,
+	0xa9, 0x0f, 0xa5, 0x9f, 0x97, 0x8a, 0x1f, 0xaa, 0xf0, 0x40, 0xd5, 0xeb, 0x55, 0xb1, 0x8c, 0x81,
+	0xaf, 0x61, 0xc5, 0x7a, 0x45, 0x42, 0x77, 0x4a, 0xd0, 0x92, 0x59, 0x77, 0xa7, 0xb1, 0x73, 0x8b,
+	0x0e, 0x01, 0xc6, 0x6f, 0x42, 0xe8, 0x76, 0x09, 0x5f, 0x78, 0x71, 0xea, 0xdd, 0x99, 0xc2, 0xb5,
+	0xb2, 0x50, 0x3f, 0xf7, 0x94, 0x9c, 0xc3, 0x6c, 0xaa, 0x73, 0x98, 0x4d, 0x38, 0x87, 0x59, 0xb5,
+	0x73, 0x98, 0xcd, 0x74, 0x6e, 0xe2, 0x95, 0xe7, 0x49, 0x0d, 0x3d, 0x83, 0x66, 0xf6, 0x30, 0x82,
+	0xec, 0x79, 0x4b, 0x2f, 0x3a, 0xbd, 0x5b, 0x95, 0x3c, 0x63, 0xd4, 0x11, 0xac, 0x58, 0x2f, 0x1d,
+	0x05, 0xa3, 0x26, 0x5f, 0x52, 0x7a, 0x77, 0xa7, 0xb1, 0xc7, 0x19, 0x6b, 0xbf, 0x5f, 0x14, 0x32,
+	0xb6, 0xe2, 0xbd, 0xa3, 0x77, 0x6f, 0x2a, 0xdf, 0x28, 0x7c, 0x01, 0x4e, 0xfe, 0x28, 0x81, 0x26,
+	0x2f, 0x3d, 0x2d, 0xd3, 0x6e, 0x57, 0x33, 0x8d, 0x1e, 0x0c, 0xad, 0xc2, 0x25, 0x3a, 0xb2, 0x67,
+	0xae, 0xba, 0xf7, 0xef, 0x6d, 0x4f, 0x07, 0x8c, 0xd7, 0x7e, 0xf9, 0xd2, 0xbb, 0xb0, 0x9a, 0xa6,
+	0x5c, 0xc2, 0xf7, 0xee, 0xcf, 0xc4, 0x94, 0x1d, 0x97, 0xc6, 0x56, 0xdc, 0xf6, 0x06, 0xc1, 0x74,
+	0xc7, 0x6d, 0x23, 0xbf, 0x85, 0x55, 0xfb, 0x2a, 0xba, 0xf0, 0x45, 0x2a, 0xae, 0xbc, 0x7b, 0xf7,
+	0xa6, 0xf2, 0x8d, 0xc2, 0x00, 0xba, 0xe5, 0x43, 0x85, 0x4c, 0xc0, 0x8f, 0x66, 0x5c, 0x48, 0x5b,
+	0xb9, 0xf8, 0xb3, 0x79, 0x30, 0x3d, 0xcb, 0x69, 0x43, 0x5d, 0x95, 0x7d, 0xf6, 0xbf, 0x01, 0x00,
+	0xd5, 0x55, 0x6c, 0xe5, 0xad, 0x21, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1538,8 +3611,18 @@ type GoisisApiClient interface {
 	AdjacencyMonitor(ctx context.Context, in *AdjacencyMonitorRequest, opts ...grpc.CallOption) (GoisisApi_AdjacencyMonitorClient, error)
 	DbLsGet(ctx context.Context, in *DbLsGetRequest, opts ...grpc.CallOption) (*DbLsGetResponse, error)
 	DbLsMonitor(ctx context.Context, in *DbLsMonitorRequest, opts ...grpc.CallOption) (GoisisApi_DbLsMonitorClient, error)
+	DbLsExport(ctx context.Context, in *DbLsExportRequest, opts ...grpc.CallOption) (*DbLsExportResponse, error)
 	DbRiGet(ctx context.Context, in *DbRiGetRequest, opts ...grpc.CallOption) (*DbRiGetResponse, error)
 	DbRiMonitor(ctx context.Context, in *DbRiMonitorRequest, opts ...grpc.CallOption) (GoisisApi_DbRiMonitorClient, error)
+	TraceSet(ctx context.Context, in *TraceSetRequest, opts ...grpc.CallOption) (*TraceSetResponse, error)
+	TopologyGet(ctx context.Context, in *TopologyGetRequest, opts ...grpc.CallOption) (*TopologyGetResponse, error)
+	InstanceList(ctx context.Context, in *InstanceListRequest, opts ...grpc.CallOption) (*InstanceListResponse, error)
+	PolicyGet(ctx context.Context, in *PolicyGetRequest, opts ...grpc.CallOption) (*PolicyGetResponse, error)
+	DefinedSetAdd(ctx context.Context, in *DefinedSetAddRequest, opts ...grpc.CallOption) (*DefinedSetAddResponse, error)
+	DefinedSetDelete(ctx context.Context, in *DefinedSetDeleteRequest, opts ...grpc.CallOption) (*DefinedSetDeleteResponse, error)
+	PolicyAdd(ctx context.Context, in *PolicyAddRequest, opts ...grpc.CallOption) (*PolicyAddResponse, error)
+	PolicyDelete(ctx context.Context, in *PolicyDeleteRequest, opts ...grpc.CallOption) (*PolicyDeleteResponse, error)
+	PolicyAssignmentSet(ctx context.Context, in *PolicyAssignmentSetRequest, opts ...grpc.CallOption) (*PolicyAssignmentSetResponse, error)
 }
 
 type goisisApiClient struct {
@@ -1668,6 +3751,15 @@ func (x *goisisApiDbLsMonitorClient) Recv() (*DbLsMonitorResponse, error) {
 	return m, nil
 }
 
+func (c *goisisApiClient) DbLsExport(ctx context.Context, in *DbLsExportRequest, opts ...grpc.CallOption) (*DbLsExportResponse, error) {
+	out := new(DbLsExportResponse)
+	err := c.cc.Invoke(ctx, "/goisisapi.GoisisApi/DbLsExport", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *goisisApiClient) DbRiGet(ctx context.Context, in *DbRiGetRequest, opts ...grpc.CallOption) (*DbRiGetResponse, error) {
 	out := new(DbRiGetResponse)
 	err := c.cc.Invoke(ctx, "/goisisapi.GoisisApi/DbRiGet", in, out, opts...)
@@ -1709,6 +3801,87 @@ func (x *goisisApiDbRiMonitorClient) Recv() (*DbRiMonitorResponse, error) {
 	return m, nil
 }
 
+func (c *goisisApiClient) TraceSet(ctx context.Context, in *TraceSetRequest, opts ...grpc.CallOption) (*TraceSetResponse, error) {
+	out := new(TraceSetResponse)
+	err := c.cc.Invoke(ctx, "/goisisapi.GoisisApi/TraceSet", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goisisApiClient) TopologyGet(ctx context.Context, in *TopologyGetRequest, opts ...grpc.CallOption) (*TopologyGetResponse, error) {
+	out := new(TopologyGetResponse)
+	err := c.cc.Invoke(ctx, "/goisisapi.GoisisApi/TopologyGet", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goisisApiClient) InstanceList(ctx context.Context, in *InstanceListRequest, opts ...grpc.CallOption) (*InstanceListResponse, error) {
+	out := new(InstanceListResponse)
+	err := c.cc.Invoke(ctx, "/goisisapi.GoisisApi/InstanceList", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goisisApiClient) PolicyGet(ctx context.Context, in *PolicyGetRequest, opts ...grpc.CallOption) (*PolicyGetResponse, error) {
+	out := new(PolicyGetResponse)
+	err := c.cc.Invoke(ctx, "/goisisapi.GoisisApi/PolicyGet", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goisisApiClient) DefinedSetAdd(ctx context.Context, in *DefinedSetAddRequest, opts ...grpc.CallOption) (*DefinedSetAddResponse, error) {
+	out := new(DefinedSetAddResponse)
+	err := c.cc.Invoke(ctx, "/goisisapi.GoisisApi/DefinedSetAdd", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goisisApiClient) DefinedSetDelete(ctx context.Context, in *DefinedSetDeleteRequest, opts ...grpc.CallOption) (*DefinedSetDeleteResponse, error) {
+	out := new(DefinedSetDeleteResponse)
+	err := c.cc.Invoke(ctx, "/goisisapi.GoisisApi/DefinedSetDelete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goisisApiClient) PolicyAdd(ctx context.Context, in *PolicyAddRequest, opts ...grpc.CallOption) (*PolicyAddResponse, error) {
+	out := new(PolicyAddResponse)
+	err := c.cc.Invoke(ctx, "/goisisapi.GoisisApi/PolicyAdd", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goisisApiClient) PolicyDelete(ctx context.Context, in *PolicyDeleteRequest, opts ...grpc.CallOption) (*PolicyDeleteResponse, error) {
+	out := new(PolicyDeleteResponse)
+	err := c.cc.Invoke(ctx, "/goisisapi.GoisisApi/PolicyDelete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goisisApiClient) PolicyAssignmentSet(ctx context.Context, in *PolicyAssignmentSetRequest, opts ...grpc.CallOption) (*PolicyAssignmentSetResponse, error) {
+	out := new(PolicyAssignmentSetResponse)
+	err := c.cc.Invoke(ctx, "/goisisapi.GoisisApi/PolicyAssignmentSet", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // GoisisApiServer is the server API for GoisisApi service.
 type GoisisApiServer interface {
 	Enable(context.Context, *EnableRequest) (*EnableResponse, error)
@@ -1719,8 +3892,18 @@ type GoisisApiServer interface {
 	AdjacencyMonitor(*AdjacencyMonitorRequest, GoisisApi_AdjacencyMonitorServer) error
 	DbLsGet(context.Context, *DbLsGetRequest) (*DbLsGetResponse, error)
 	DbLsMonitor(*DbLsMonitorRequest, GoisisApi_DbLsMonitorServer) error
+	DbLsExport(context.Context, *DbLsExportRequest) (*DbLsExportResponse, error)
 	DbRiGet(context.Context, *DbRiGetRequest) (*DbRiGetResponse, error)
 	DbRiMonitor(*DbRiMonitorRequest, GoisisApi_DbRiMonitorServer) error
+	TraceSet(context.Context, *TraceSetRequest) (*TraceSetResponse, error)
+	TopologyGet(context.Context, *TopologyGetRequest) (*TopologyGetResponse, error)
+	InstanceList(context.Context, *InstanceListRequest) (*InstanceListResponse, error)
+	PolicyGet(context.Context, *PolicyGetRequest) (*PolicyGetResponse, error)
+	DefinedSetAdd(context.Context, *DefinedSetAddRequest) (*DefinedSetAddResponse, error)
+	DefinedSetDelete(context.Context, *DefinedSetDeleteRequest) (*DefinedSetDeleteResponse, error)
+	PolicyAdd(context.Context, *PolicyAddRequest) (*PolicyAddResponse, error)
+	PolicyDelete(context.Context, *PolicyDeleteRequest) (*PolicyDeleteResponse, error)
+	PolicyAssignmentSet(context.Context, *PolicyAssignmentSetRequest) (*PolicyAssignmentSetResponse, error)
 }
 
 func RegisterGoisisApiServer(s *grpc.Server, srv GoisisApiServer) {
@@ -1877,6 +4060,24 @@ func (x *goisisApiDbLsMonitorServer) Send(m *DbLsMonitorResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _GoisisApi_DbLsExport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DbLsExportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoisisApiServer).DbLsExport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/goisisapi.GoisisApi/DbLsExport",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoisisApiServer).DbLsExport(ctx, req.(*DbLsExportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _GoisisApi_DbRiGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DbRiGetRequest)
 	if err := dec(in); err != nil {
@@ -1916,6 +4117,168 @@ func (x *goisisApiDbRiMonitorServer) Send(m *DbRiMonitorResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _GoisisApi_TraceSet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TraceSetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoisisApiServer).TraceSet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/goisisapi.GoisisApi/TraceSet",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoisisApiServer).TraceSet(ctx, req.(*TraceSetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoisisApi_TopologyGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TopologyGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoisisApiServer).TopologyGet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/goisisapi.GoisisApi/TopologyGet",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoisisApiServer).TopologyGet(ctx, req.(*TopologyGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoisisApi_InstanceList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InstanceListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoisisApiServer).InstanceList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/goisisapi.GoisisApi/InstanceList",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoisisApiServer).InstanceList(ctx, req.(*InstanceListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoisisApi_PolicyGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PolicyGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoisisApiServer).PolicyGet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/goisisapi.GoisisApi/PolicyGet",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoisisApiServer).PolicyGet(ctx, req.(*PolicyGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoisisApi_DefinedSetAdd_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DefinedSetAddRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoisisApiServer).DefinedSetAdd(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/goisisapi.GoisisApi/DefinedSetAdd",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoisisApiServer).DefinedSetAdd(ctx, req.(*DefinedSetAddRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoisisApi_DefinedSetDelete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DefinedSetDeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoisisApiServer).DefinedSetDelete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/goisisapi.GoisisApi/DefinedSetDelete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoisisApiServer).DefinedSetDelete(ctx, req.(*DefinedSetDeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoisisApi_PolicyAdd_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PolicyAddRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoisisApiServer).PolicyAdd(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/goisisapi.GoisisApi/PolicyAdd",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoisisApiServer).PolicyAdd(ctx, req.(*PolicyAddRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoisisApi_PolicyDelete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PolicyDeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoisisApiServer).PolicyDelete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/goisisapi.GoisisApi/PolicyDelete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoisisApiServer).PolicyDelete(ctx, req.(*PolicyDeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoisisApi_PolicyAssignmentSet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PolicyAssignmentSetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoisisApiServer).PolicyAssignmentSet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/goisisapi.GoisisApi/PolicyAssignmentSet",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoisisApiServer).PolicyAssignmentSet(ctx, req.(*PolicyAssignmentSetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _GoisisApi_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "goisisapi.GoisisApi",
 	HandlerType: (*GoisisApiServer)(nil),
@@ -1944,10 +4307,50 @@ var _GoisisApi_serviceDesc = grpc.ServiceDesc{
 			MethodName: "DbLsGet",
 			Handler:    _GoisisApi_DbLsGet_Handler,
 		},
+		{
+			MethodName: "DbLsExport",
+			Handler:    _GoisisApi_DbLsExport_Handler,
+		},
 		{
 			MethodName: "DbRiGet",
 			Handler:    _GoisisApi_DbRiGet_Handler,
 		},
+		{
+			MethodName: "TraceSet",
+			Handler:    _GoisisApi_TraceSet_Handler,
+		},
+		{
+			MethodName: "TopologyGet",
+			Handler:    _GoisisApi_TopologyGet_Handler,
+		},
+		{
+			MethodName: "InstanceList",
+			Handler:    _GoisisApi_InstanceList_Handler,
+		},
+		{
+			MethodName: "PolicyGet",
+			Handler:    _GoisisApi_PolicyGet_Handler,
+		},
+		{
+			MethodName: "DefinedSetAdd",
+			Handler:    _GoisisApi_DefinedSetAdd_Handler,
+		},
+		{
+			MethodName: "DefinedSetDelete",
+			Handler:    _GoisisApi_DefinedSetDelete_Handler,
+		},
+		{
+			MethodName: "PolicyAdd",
+			Handler:    _GoisisApi_PolicyAdd_Handler,
+		},
+		{
+			MethodName: "PolicyDelete",
+			Handler:    _GoisisApi_PolicyDelete_Handler,
+		},
+		{
+			MethodName: "PolicyAssignmentSet",
+			Handler:    _GoisisApi_PolicyAssignmentSet_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -162,76 +162,2040 @@ func (m *DisableResponse) GetResult() string {
 	return ""
 }
 
+type InterfaceGetRequest struct {
+	Interface            string   `protobuf:"bytes,1,opt,name=interface,proto3" json:"interface,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *InterfaceGetRequest) Reset()         { *m = InterfaceGetRequest{} }
+func (m *InterfaceGetRequest) String() string { return proto.CompactTextString(m) }
+func (*InterfaceGetRequest) ProtoMessage()    {}
+func (*InterfaceGetRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fbe8e30501ec2189, []int{4}
+}
+
+func (m *InterfaceGetRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InterfaceGetRequest.Unmarshal(m, b)
+}
+func (m *InterfaceGetRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InterfaceGetRequest.Marshal(b, m, deterministic)
+}
+func (m *InterfaceGetRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InterfaceGetRequest.Merge(m, src)
+}
+func (m *InterfaceGetRequest) XXX_Size() int {
+	return xxx_messageInfo_InterfaceGetRequest.Size(m)
+}
+func (m *InterfaceGetRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_InterfaceGetRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InterfaceGetRequest proto.InternalMessageInfo
+
+func (m *InterfaceGetRequest) GetInterface() string {
+	if m != nil {
+		return m.Interface
+	}
+	return ""
+}
+
+type InterfaceGetResponse struct {
+	Interfaces           []*Interface `protobuf:"bytes,1,rep,name=interfaces,proto3" json:"interfaces,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *InterfaceGetResponse) Reset()         { *m = InterfaceGetResponse{} }
+func (m *InterfaceGetResponse) String() string { return proto.CompactTextString(m) }
+func (*InterfaceGetResponse) ProtoMessage()    {}
+func (*InterfaceGetResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fbe8e30501ec2189, []int{5}
+}
+
+func (m *InterfaceGetResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InterfaceGetResponse.Unmarshal(m, b)
+}
+func (m *InterfaceGetResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InterfaceGetResponse.Marshal(b, m, deterministic)
+}
+func (m *InterfaceGetResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InterfaceGetResponse.Merge(m, src)
+}
+func (m *InterfaceGetResponse) XXX_Size() int {
+	return xxx_messageInfo_InterfaceGetResponse.Size(m)
+}
+func (m *InterfaceGetResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_InterfaceGetResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InterfaceGetResponse proto.InternalMessageInfo
+
+func (m *InterfaceGetResponse) GetInterfaces() []*Interface {
+	if m != nil {
+		return m.Interfaces
+	}
+	return nil
+}
+
+type InterfaceMonitorRequest struct {
+	Interface            string   `protobuf:"bytes,1,opt,name=interface,proto3" json:"interface,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *InterfaceMonitorRequest) Reset()         { *m = InterfaceMonitorRequest{} }
+func (m *InterfaceMonitorRequest) String() string { return proto.CompactTextString(m) }
+func (*InterfaceMonitorRequest) ProtoMessage()    {}
+func (*InterfaceMonitorRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fbe8e30501ec2189, []int{6}
+}
+
+func (m *InterfaceMonitorRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InterfaceMonitorRequest.Unmarshal(m, b)
+}
+func (m *InterfaceMonitorRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InterfaceMonitorRequest.Marshal(b, m, deterministic)
+}
+func (m *InterfaceMonitorRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InterfaceMonitorRequest.Merge(m, src)
+}
+func (m *InterfaceMonitorRequest) XXX_Size() int {
+	return xxx_messageInfo_InterfaceMonitorRequest.Size(m)
+}
+func (m *InterfaceMonitorRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_InterfaceMonitorRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InterfaceMonitorRequest proto.InternalMessageInfo
+
+func (m *InterfaceMonitorRequest) GetInterface() string {
+	if m != nil {
+		return m.Interface
+	}
+	return ""
+}
+
+type InterfaceMonitorResponse struct {
+	Interfaces           []*Interface `protobuf:"bytes,1,rep,name=interfaces,proto3" json:"interfaces,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *InterfaceMonitorResponse) Reset()         { *m = InterfaceMonitorResponse{} }
+func (m *InterfaceMonitorResponse) String() string { return proto.CompactTextString(m) }
+func (*InterfaceMonitorResponse) ProtoMessage()    {}
+func (*InterfaceMonitorResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fbe8e30501ec2189, []int{7}
+}
+
+func (m *InterfaceMonitorResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InterfaceMonitorResponse.Unmarshal(m, b)
+}
+func (m *InterfaceMonitorResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InterfaceMonitorResponse.Marshal(b, m, deterministic)
+}
+func (m *InterfaceMonitorResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InterfaceMonitorResponse.Merge(m, src)
+}
+func (m *InterfaceMonitorResponse) XXX_Size() int {
+	return xxx_messageInfo_InterfaceMonitorResponse.Size(m)
+}
+func (m *InterfaceMonitorResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_InterfaceMonitorResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InterfaceMonitorResponse proto.InternalMessageInfo
+
+func (m *InterfaceMonitorResponse) GetInterfaces() []*Interface {
+	if m != nil {
+		return m.Interfaces
+	}
+	return nil
+}
+
+type NeighborGetRequest struct {
+	Interface            string   `protobuf:"bytes,1,opt,name=interface,proto3" json:"interface,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NeighborGetRequest) Reset()         { *m = NeighborGetRequest{} }
+func (m *NeighborGetRequest) String() string { return proto.CompactTextString(m) }
+func (*NeighborGetRequest) ProtoMessage()    {}
+func (*NeighborGetRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fbe8e30501ec2189, []int{8}
+}
+
+func (m *NeighborGetRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NeighborGetRequest.Unmarshal(m, b)
+}
+func (m *NeighborGetRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NeighborGetRequest.Marshal(b, m, deterministic)
+}
+func (m *NeighborGetRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NeighborGetRequest.Merge(m, src)
+}
+func (m *NeighborGetRequest) XXX_Size() int {
+	return xxx_messageInfo_NeighborGetRequest.Size(m)
+}
+func (m *NeighborGetRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_NeighborGetRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NeighborGetRequest proto.InternalMessageInfo
+
+func (m *NeighborGetRequest) GetInterface() string {
+	if m != nil {
+		return m.Interface
+	}
+	return ""
+}
+
+type NeighborGetResponse struct {
+	Neighbors            []*Neighbor `protobuf:"bytes,1,rep,name=neighbors,proto3" json:"neighbors,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *NeighborGetResponse) Reset()         { *m = NeighborGetResponse{} }
+func (m *NeighborGetResponse) String() string { return proto.CompactTextString(m) }
+func (*NeighborGetResponse) ProtoMessage()    {}
+func (*NeighborGetResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fbe8e30501ec2189, []int{9}
+}
+
+func (m *NeighborGetResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NeighborGetResponse.Unmarshal(m, b)
+}
+func (m *NeighborGetResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NeighborGetResponse.Marshal(b, m, deterministic)
+}
+func (m *NeighborGetResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NeighborGetResponse.Merge(m, src)
+}
+func (m *NeighborGetResponse) XXX_Size() int {
+	return xxx_messageInfo_NeighborGetResponse.Size(m)
+}
+func (m *NeighborGetResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_NeighborGetResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NeighborGetResponse proto.InternalMessageInfo
+
+func (m *NeighborGetResponse) GetNeighbors() []*Neighbor {
+	if m != nil {
+		return m.Neighbors
+	}
+	return nil
+}
+
+type NeighborMonitorRequest struct {
+	Interface            string   `protobuf:"bytes,1,opt,name=interface,proto3" json:"interface,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NeighborMonitorRequest) Reset()         { *m = NeighborMonitorRequest{} }
+func (m *NeighborMonitorRequest) String() string { return proto.CompactTextString(m) }
+func (*NeighborMonitorRequest) ProtoMessage()    {}
+func (*NeighborMonitorRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fbe8e30501ec2189, []int{10}
+}
+
+func (m *NeighborMonitorRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NeighborMonitorRequest.Unmarshal(m, b)
+}
+func (m *NeighborMonitorRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NeighborMonitorRequest.Marshal(b, m, deterministic)
+}
+func (m *NeighborMonitorRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NeighborMonitorRequest.Merge(m, src)
+}
+func (m *NeighborMonitorRequest) XXX_Size() int {
+	return xxx_messageInfo_NeighborMonitorRequest.Size(m)
+}
+func (m *NeighborMonitorRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_NeighborMonitorRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NeighborMonitorRequest proto.InternalMessageInfo
+
+func (m *NeighborMonitorRequest) GetInterface() string {
+	if m != nil {
+		return m.Interface
+	}
+	return ""
+}
+
+type NeighborMonitorResponse struct {
+	Neighbors            []*Neighbor `protobuf:"bytes,1,rep,name=neighbors,proto3" json:"neighbors,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *NeighborMonitorResponse) Reset()         { *m = NeighborMonitorResponse{} }
+func (m *NeighborMonitorResponse) String() string { return proto.CompactTextString(m) }
+func (*NeighborMonitorResponse) ProtoMessage()    {}
+func (*NeighborMonitorResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fbe8e30501ec2189, []int{11}
+}
+
+func (m *NeighborMonitorResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NeighborMonitorResponse.Unmarshal(m, b)
+}
+func (m *NeighborMonitorResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NeighborMonitorResponse.Marshal(b, m, deterministic)
+}
+func (m *NeighborMonitorResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NeighborMonitorResponse.Merge(m, src)
+}
+func (m *NeighborMonitorResponse) XXX_Size() int {
+	return xxx_messageInfo_NeighborMonitorResponse.Size(m)
+}
+func (m *NeighborMonitorResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_NeighborMonitorResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NeighborMonitorResponse proto.InternalMessageInfo
+
+func (m *NeighborMonitorResponse) GetNeighbors() []*Neighbor {
+	if m != nil {
+		return m.Neighbors
+	}
+	return nil
+}
+
+type VirtualLinkGetRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VirtualLinkGetRequest) Reset()         { *m = VirtualLinkGetRequest{} }
+func (m *VirtualLinkGetRequest) String() string { return proto.CompactTextString(m) }
+func (*VirtualLinkGetRequest) ProtoMessage()    {}
+func (*VirtualLinkGetRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fbe8e30501ec2189, []int{12}
+}
+
+func (m *VirtualLinkGetRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VirtualLinkGetRequest.Unmarshal(m, b)
+}
+func (m *VirtualLinkGetRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VirtualLinkGetRequest.Marshal(b, m, deterministic)
+}
+func (m *VirtualLinkGetRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VirtualLinkGetRequest.Merge(m, src)
+}
+func (m *VirtualLinkGetRequest) XXX_Size() int {
+	return xxx_messageInfo_VirtualLinkGetRequest.Size(m)
+}
+func (m *VirtualLinkGetRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_VirtualLinkGetRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VirtualLinkGetRequest proto.InternalMessageInfo
+
+type VirtualLinkGetResponse struct {
+	VirtualLinks         []*VirtualLink `protobuf:"bytes,1,rep,name=virtual_links,json=virtualLinks,proto3" json:"virtual_links,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *VirtualLinkGetResponse) Reset()         { *m = VirtualLinkGetResponse{} }
+func (m *VirtualLinkGetResponse) String() string { return proto.CompactTextString(m) }
+func (*VirtualLinkGetResponse) ProtoMessage()    {}
+func (*VirtualLinkGetResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fbe8e30501ec2189, []int{13}
+}
+
+func (m *VirtualLinkGetResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VirtualLinkGetResponse.Unmarshal(m, b)
+}
+func (m *VirtualLinkGetResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VirtualLinkGetResponse.Marshal(b, m, deterministic)
+}
+func (m *VirtualLinkGetResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VirtualLinkGetResponse.Merge(m, src)
+}
+func (m *VirtualLinkGetResponse) XXX_Size() int {
+	return xxx_messageInfo_VirtualLinkGetResponse.Size(m)
+}
+func (m *VirtualLinkGetResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_VirtualLinkGetResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VirtualLinkGetResponse proto.InternalMessageInfo
+
+func (m *VirtualLinkGetResponse) GetVirtualLinks() []*VirtualLink {
+	if m != nil {
+		return m.VirtualLinks
+	}
+	return nil
+}
+
+type VirtualLink struct {
+	TransitAreaId        string   `protobuf:"bytes,1,opt,name=transit_area_id,json=transitAreaId,proto3" json:"transit_area_id,omitempty"`
+	RouterId             string   `protobuf:"bytes,2,opt,name=router_id,json=routerId,proto3" json:"router_id,omitempty"`
+	State                string   `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+	NeighborState        string   `protobuf:"bytes,4,opt,name=neighbor_state,json=neighborState,proto3" json:"neighbor_state,omitempty"`
+	Address              string   `protobuf:"bytes,5,opt,name=address,proto3" json:"address,omitempty"`
+	NeighborAddress      string   `protobuf:"bytes,6,opt,name=neighbor_address,json=neighborAddress,proto3" json:"neighbor_address,omitempty"`
+	Cost                 uint32   `protobuf:"varint,7,opt,name=cost,proto3" json:"cost,omitempty"`
+	TransitInterface     string   `protobuf:"bytes,8,opt,name=transit_interface,json=transitInterface,proto3" json:"transit_interface,omitempty"`
+	TtlSecurity          bool     `protobuf:"varint,9,opt,name=ttl_security,json=ttlSecurity,proto3" json:"ttl_security,omitempty"`
+	TtlSecurityDrops     uint64   `protobuf:"varint,10,opt,name=ttl_security_drops,json=ttlSecurityDrops,proto3" json:"ttl_security_drops,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VirtualLink) Reset()         { *m = VirtualLink{} }
+func (m *VirtualLink) String() string { return proto.CompactTextString(m) }
+func (*VirtualLink) ProtoMessage()    {}
+func (*VirtualLink) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fbe8e30501ec2189, []int{14}
+}
+
+func (m *VirtualLink) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VirtualLink.Unmarshal(m, b)
+}
+func (m *VirtualLink) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VirtualLink.Marshal(b, m, deterministic)
+}
+func (m *VirtualLink) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VirtualLink.Merge(m, src)
+}
+func (m *VirtualLink) XXX_Size() int {
+	return xxx_messageInfo_VirtualLink.Size(m)
+}
+func (m *VirtualLink) XXX_DiscardUnknown() {
+	xxx_messageInfo_VirtualLink.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VirtualLink proto.InternalMessageInfo
+
+func (m *VirtualLink) GetTransitAreaId() string {
+	if m != nil {
+		return m.TransitAreaId
+	}
+	return ""
+}
+
+func (m *VirtualLink) GetRouterId() string {
+	if m != nil {
+		return m.RouterId
+	}
+	return ""
+}
+
+func (m *VirtualLink) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+func (m *VirtualLink) GetNeighborState() string {
+	if m != nil {
+		return m.NeighborState
+	}
+	return ""
+}
+
+func (m *VirtualLink) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *VirtualLink) GetNeighborAddress() string {
+	if m != nil {
+		return m.NeighborAddress
+	}
+	return ""
+}
+
+func (m *VirtualLink) GetCost() uint32 {
+	if m != nil {
+		return m.Cost
+	}
+	return 0
+}
+
+func (m *VirtualLink) GetTransitInterface() string {
+	if m != nil {
+		return m.TransitInterface
+	}
+	return ""
+}
+
+func (m *VirtualLink) GetTtlSecurity() bool {
+	if m != nil {
+		return m.TtlSecurity
+	}
+	return false
+}
+
+func (m *VirtualLink) GetTtlSecurityDrops() uint64 {
+	if m != nil {
+		return m.TtlSecurityDrops
+	}
+	return 0
+}
+
+type StubRouterGetRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StubRouterGetRequest) Reset()         { *m = StubRouterGetRequest{} }
+func (m *StubRouterGetRequest) String() string { return proto.CompactTextString(m) }
+func (*StubRouterGetRequest) ProtoMessage()    {}
+func (*StubRouterGetRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fbe8e30501ec2189, []int{15}
+}
+
+func (m *StubRouterGetRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StubRouterGetRequest.Unmarshal(m, b)
+}
+func (m *StubRouterGetRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StubRouterGetRequest.Marshal(b, m, deterministic)
+}
+func (m *StubRouterGetRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StubRouterGetRequest.Merge(m, src)
+}
+func (m *StubRouterGetRequest) XXX_Size() int {
+	return xxx_messageInfo_StubRouterGetRequest.Size(m)
+}
+func (m *StubRouterGetRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StubRouterGetRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StubRouterGetRequest proto.InternalMessageInfo
+
+type StubRouterGetResponse struct {
+	StubRouter           *StubRouter `protobuf:"bytes,1,opt,name=stub_router,json=stubRouter,proto3" json:"stub_router,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *StubRouterGetResponse) Reset()         { *m = StubRouterGetResponse{} }
+func (m *StubRouterGetResponse) String() string { return proto.CompactTextString(m) }
+func (*StubRouterGetResponse) ProtoMessage()    {}
+func (*StubRouterGetResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fbe8e30501ec2189, []int{16}
+}
+
+func (m *StubRouterGetResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StubRouterGetResponse.Unmarshal(m, b)
+}
+func (m *StubRouterGetResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StubRouterGetResponse.Marshal(b, m, deterministic)
+}
+func (m *StubRouterGetResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StubRouterGetResponse.Merge(m, src)
+}
+func (m *StubRouterGetResponse) XXX_Size() int {
+	return xxx_messageInfo_StubRouterGetResponse.Size(m)
+}
+func (m *StubRouterGetResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StubRouterGetResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StubRouterGetResponse proto.InternalMessageInfo
+
+func (m *StubRouterGetResponse) GetStubRouter() *StubRouter {
+	if m != nil {
+		return m.StubRouter
+	}
+	return nil
+}
+
+type StubRouter struct {
+	Active               bool     `protobuf:"varint,1,opt,name=active,proto3" json:"active,omitempty"`
+	Always               bool     `protobuf:"varint,2,opt,name=always,proto3" json:"always,omitempty"`
+	OnDemand             bool     `protobuf:"varint,3,opt,name=on_demand,json=onDemand,proto3" json:"on_demand,omitempty"`
+	OnStartupRemaining   uint32   `protobuf:"varint,4,opt,name=on_startup_remaining,json=onStartupRemaining,proto3" json:"on_startup_remaining,omitempty"`
+	WaitFor              string   `protobuf:"bytes,5,opt,name=wait_for,json=waitFor,proto3" json:"wait_for,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StubRouter) Reset()         { *m = StubRouter{} }
+func (m *StubRouter) String() string { return proto.CompactTextString(m) }
+func (*StubRouter) ProtoMessage()    {}
+func (*StubRouter) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fbe8e30501ec2189, []int{17}
+}
+
+func (m *StubRouter) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StubRouter.Unmarshal(m, b)
+}
+func (m *StubRouter) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StubRouter.Marshal(b, m, deterministic)
+}
+func (m *StubRouter) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StubRouter.Merge(m, src)
+}
+func (m *StubRouter) XXX_Size() int {
+	return xxx_messageInfo_StubRouter.Size(m)
+}
+func (m *StubRouter) XXX_DiscardUnknown() {
+	xxx_messageInfo_StubRouter.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StubRouter proto.InternalMessageInfo
+
+func (m *StubRouter) GetActive() bool {
+	if m != nil {
+		return m.Active
+	}
+	return false
+}
+
+func (m *StubRouter) GetAlways() bool {
+	if m != nil {
+		return m.Always
+	}
+	return false
+}
+
+func (m *StubRouter) GetOnDemand() bool {
+	if m != nil {
+		return m.OnDemand
+	}
+	return false
+}
+
+func (m *StubRouter) GetOnStartupRemaining() uint32 {
+	if m != nil {
+		return m.OnStartupRemaining
+	}
+	return 0
+}
+
+func (m *StubRouter) GetWaitFor() string {
+	if m != nil {
+		return m.WaitFor
+	}
+	return ""
+}
+
+type StubRouterEnableRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StubRouterEnableRequest) Reset()         { *m = StubRouterEnableRequest{} }
+func (m *StubRouterEnableRequest) String() string { return proto.CompactTextString(m) }
+func (*StubRouterEnableRequest) ProtoMessage()    {}
+func (*StubRouterEnableRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fbe8e30501ec2189, []int{18}
+}
+
+func (m *StubRouterEnableRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StubRouterEnableRequest.Unmarshal(m, b)
+}
+func (m *StubRouterEnableRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StubRouterEnableRequest.Marshal(b, m, deterministic)
+}
+func (m *StubRouterEnableRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StubRouterEnableRequest.Merge(m, src)
+}
+func (m *StubRouterEnableRequest) XXX_Size() int {
+	return xxx_messageInfo_StubRouterEnableRequest.Size(m)
+}
+func (m *StubRouterEnableRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StubRouterEnableRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StubRouterEnableRequest proto.InternalMessageInfo
+
+type StubRouterEnableResponse struct {
+	Result               string   `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StubRouterEnableResponse) Reset()         { *m = StubRouterEnableResponse{} }
+func (m *StubRouterEnableResponse) String() string { return proto.CompactTextString(m) }
+func (*StubRouterEnableResponse) ProtoMessage()    {}
+func (*StubRouterEnableResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fbe8e30501ec2189, []int{19}
+}
+
+func (m *StubRouterEnableResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StubRouterEnableResponse.Unmarshal(m, b)
+}
+func (m *StubRouterEnableResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StubRouterEnableResponse.Marshal(b, m, deterministic)
+}
+func (m *StubRouterEnableResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StubRouterEnableResponse.Merge(m, src)
+}
+func (m *StubRouterEnableResponse) XXX_Size() int {
+	return xxx_messageInfo_StubRouterEnableResponse.Size(m)
+}
+func (m *StubRouterEnableResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StubRouterEnableResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StubRouterEnableResponse proto.InternalMessageInfo
+
+func (m *StubRouterEnableResponse) GetResult() string {
+	if m != nil {
+		return m.Result
+	}
+	return ""
+}
+
+type StubRouterDisableRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StubRouterDisableRequest) Reset()         { *m = StubRouterDisableRequest{} }
+func (m *StubRouterDisableRequest) String() string { return proto.CompactTextString(m) }
+func (*StubRouterDisableRequest) ProtoMessage()    {}
+func (*StubRouterDisableRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fbe8e30501ec2189, []int{20}
+}
+
+func (m *StubRouterDisableRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StubRouterDisableRequest.Unmarshal(m, b)
+}
+func (m *StubRouterDisableRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StubRouterDisableRequest.Marshal(b, m, deterministic)
+}
+func (m *StubRouterDisableRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StubRouterDisableRequest.Merge(m, src)
+}
+func (m *StubRouterDisableRequest) XXX_Size() int {
+	return xxx_messageInfo_StubRouterDisableRequest.Size(m)
+}
+func (m *StubRouterDisableRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StubRouterDisableRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StubRouterDisableRequest proto.InternalMessageInfo
+
+type StubRouterDisableResponse struct {
+	Result               string   `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StubRouterDisableResponse) Reset()         { *m = StubRouterDisableResponse{} }
+func (m *StubRouterDisableResponse) String() string { return proto.CompactTextString(m) }
+func (*StubRouterDisableResponse) ProtoMessage()    {}
+func (*StubRouterDisableResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fbe8e30501ec2189, []int{21}
+}
+
+func (m *StubRouterDisableResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StubRouterDisableResponse.Unmarshal(m, b)
+}
+func (m *StubRouterDisableResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StubRouterDisableResponse.Marshal(b, m, deterministic)
+}
+func (m *StubRouterDisableResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StubRouterDisableResponse.Merge(m, src)
+}
+func (m *StubRouterDisableResponse) XXX_Size() int {
+	return xxx_messageInfo_StubRouterDisableResponse.Size(m)
+}
+func (m *StubRouterDisableResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StubRouterDisableResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StubRouterDisableResponse proto.InternalMessageInfo
+
+func (m *StubRouterDisableResponse) GetResult() string {
+	if m != nil {
+		return m.Result
+	}
+	return ""
+}
+
+type DbLsGetRequest struct {
+	AreaId               string   `protobuf:"bytes,1,opt,name=area_id,json=areaId,proto3" json:"area_id,omitempty"`
+	LsType               string   `protobuf:"bytes,2,opt,name=ls_type,json=lsType,proto3" json:"ls_type,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DbLsGetRequest) Reset()         { *m = DbLsGetRequest{} }
+func (m *DbLsGetRequest) String() string { return proto.CompactTextString(m) }
+func (*DbLsGetRequest) ProtoMessage()    {}
+func (*DbLsGetRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fbe8e30501ec2189, []int{22}
+}
+
+func (m *DbLsGetRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DbLsGetRequest.Unmarshal(m, b)
+}
+func (m *DbLsGetRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DbLsGetRequest.Marshal(b, m, deterministic)
+}
+func (m *DbLsGetRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DbLsGetRequest.Merge(m, src)
+}
+func (m *DbLsGetRequest) XXX_Size() int {
+	return xxx_messageInfo_DbLsGetRequest.Size(m)
+}
+func (m *DbLsGetRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DbLsGetRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DbLsGetRequest proto.InternalMessageInfo
+
+func (m *DbLsGetRequest) GetAreaId() string {
+	if m != nil {
+		return m.AreaId
+	}
+	return ""
+}
+
+func (m *DbLsGetRequest) GetLsType() string {
+	if m != nil {
+		return m.LsType
+	}
+	return ""
+}
+
+type DbLsGetResponse struct {
+	Lsas                 []*Lsa   `protobuf:"bytes,1,rep,name=lsas,proto3" json:"lsas,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DbLsGetResponse) Reset()         { *m = DbLsGetResponse{} }
+func (m *DbLsGetResponse) String() string { return proto.CompactTextString(m) }
+func (*DbLsGetResponse) ProtoMessage()    {}
+func (*DbLsGetResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fbe8e30501ec2189, []int{23}
+}
+
+func (m *DbLsGetResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DbLsGetResponse.Unmarshal(m, b)
+}
+func (m *DbLsGetResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DbLsGetResponse.Marshal(b, m, deterministic)
+}
+func (m *DbLsGetResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DbLsGetResponse.Merge(m, src)
+}
+func (m *DbLsGetResponse) XXX_Size() int {
+	return xxx_messageInfo_DbLsGetResponse.Size(m)
+}
+func (m *DbLsGetResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DbLsGetResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DbLsGetResponse proto.InternalMessageInfo
+
+func (m *DbLsGetResponse) GetLsas() []*Lsa {
+	if m != nil {
+		return m.Lsas
+	}
+	return nil
+}
+
+type DbLsMonitorRequest struct {
+	AreaId               string   `protobuf:"bytes,1,opt,name=area_id,json=areaId,proto3" json:"area_id,omitempty"`
+	LsType               string   `protobuf:"bytes,2,opt,name=ls_type,json=lsType,proto3" json:"ls_type,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DbLsMonitorRequest) Reset()         { *m = DbLsMonitorRequest{} }
+func (m *DbLsMonitorRequest) String() string { return proto.CompactTextString(m) }
+func (*DbLsMonitorRequest) ProtoMessage()    {}
+func (*DbLsMonitorRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fbe8e30501ec2189, []int{24}
+}
+
+func (m *DbLsMonitorRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DbLsMonitorRequest.Unmarshal(m, b)
+}
+func (m *DbLsMonitorRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DbLsMonitorRequest.Marshal(b, m, deterministic)
+}
+func (m *DbLsMonitorRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DbLsMonitorRequest.Merge(m, src)
+}
+func (m *DbLsMonitorRequest) XXX_Size() int {
+	return xxx_messageInfo_DbLsMonitorRequest.Size(m)
+}
+func (m *DbLsMonitorRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DbLsMonitorRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DbLsMonitorRequest proto.InternalMessageInfo
+
+func (m *DbLsMonitorRequest) GetAreaId() string {
+	if m != nil {
+		return m.AreaId
+	}
+	return ""
+}
+
+func (m *DbLsMonitorRequest) GetLsType() string {
+	if m != nil {
+		return m.LsType
+	}
+	return ""
+}
+
+type DbLsMonitorResponse struct {
+	Lsas                 []*Lsa   `protobuf:"bytes,1,rep,name=lsas,proto3" json:"lsas,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DbLsMonitorResponse) Reset()         { *m = DbLsMonitorResponse{} }
+func (m *DbLsMonitorResponse) String() string { return proto.CompactTextString(m) }
+func (*DbLsMonitorResponse) ProtoMessage()    {}
+func (*DbLsMonitorResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fbe8e30501ec2189, []int{25}
+}
+
+func (m *DbLsMonitorResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DbLsMonitorResponse.Unmarshal(m, b)
+}
+func (m *DbLsMonitorResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DbLsMonitorResponse.Marshal(b, m, deterministic)
+}
+func (m *DbLsMonitorResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DbLsMonitorResponse.Merge(m, src)
+}
+func (m *DbLsMonitorResponse) XXX_Size() int {
+	return xxx_messageInfo_DbLsMonitorResponse.Size(m)
+}
+func (m *DbLsMonitorResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DbLsMonitorResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DbLsMonitorResponse proto.InternalMessageInfo
+
+func (m *DbLsMonitorResponse) GetLsas() []*Lsa {
+	if m != nil {
+		return m.Lsas
+	}
+	return nil
+}
+
+type DbRiGetRequest struct {
+	AddressFamily        string   `protobuf:"bytes,1,opt,name=address_family,json=addressFamily,proto3" json:"address_family,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DbRiGetRequest) Reset()         { *m = DbRiGetRequest{} }
+func (m *DbRiGetRequest) String() string { return proto.CompactTextString(m) }
+func (*DbRiGetRequest) ProtoMessage()    {}
+func (*DbRiGetRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fbe8e30501ec2189, []int{26}
+}
+
+func (m *DbRiGetRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DbRiGetRequest.Unmarshal(m, b)
+}
+func (m *DbRiGetRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DbRiGetRequest.Marshal(b, m, deterministic)
+}
+func (m *DbRiGetRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DbRiGetRequest.Merge(m, src)
+}
+func (m *DbRiGetRequest) XXX_Size() int {
+	return xxx_messageInfo_DbRiGetRequest.Size(m)
+}
+func (m *DbRiGetRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DbRiGetRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DbRiGetRequest proto.InternalMessageInfo
+
+func (m *DbRiGetRequest) GetAddressFamily() string {
+	if m != nil {
+		return m.AddressFamily
+	}
+	return ""
+}
+
+type DbRiGetResponse struct {
+	Routes               []*Route `protobuf:"bytes,1,rep,name=routes,proto3" json:"routes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DbRiGetResponse) Reset()         { *m = DbRiGetResponse{} }
+func (m *DbRiGetResponse) String() string { return proto.CompactTextString(m) }
+func (*DbRiGetResponse) ProtoMessage()    {}
+func (*DbRiGetResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fbe8e30501ec2189, []int{27}
+}
+
+func (m *DbRiGetResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DbRiGetResponse.Unmarshal(m, b)
+}
+func (m *DbRiGetResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DbRiGetResponse.Marshal(b, m, deterministic)
+}
+func (m *DbRiGetResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DbRiGetResponse.Merge(m, src)
+}
+func (m *DbRiGetResponse) XXX_Size() int {
+	return xxx_messageInfo_DbRiGetResponse.Size(m)
+}
+func (m *DbRiGetResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DbRiGetResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DbRiGetResponse proto.InternalMessageInfo
+
+func (m *DbRiGetResponse) GetRoutes() []*Route {
+	if m != nil {
+		return m.Routes
+	}
+	return nil
+}
+
+type DbRiMonitorRequest struct {
+	AddressFamily        string   `protobuf:"bytes,1,opt,name=address_family,json=addressFamily,proto3" json:"address_family,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DbRiMonitorRequest) Reset()         { *m = DbRiMonitorRequest{} }
+func (m *DbRiMonitorRequest) String() string { return proto.CompactTextString(m) }
+func (*DbRiMonitorRequest) ProtoMessage()    {}
+func (*DbRiMonitorRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fbe8e30501ec2189, []int{28}
+}
+
+func (m *DbRiMonitorRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DbRiMonitorRequest.Unmarshal(m, b)
+}
+func (m *DbRiMonitorRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DbRiMonitorRequest.Marshal(b, m, deterministic)
+}
+func (m *DbRiMonitorRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DbRiMonitorRequest.Merge(m, src)
+}
+func (m *DbRiMonitorRequest) XXX_Size() int {
+	return xxx_messageInfo_DbRiMonitorRequest.Size(m)
+}
+func (m *DbRiMonitorRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DbRiMonitorRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DbRiMonitorRequest proto.InternalMessageInfo
+
+func (m *DbRiMonitorRequest) GetAddressFamily() string {
+	if m != nil {
+		return m.AddressFamily
+	}
+	return ""
+}
+
+type DbRiMonitorResponse struct {
+	Routes               []*Route `protobuf:"bytes,1,rep,name=routes,proto3" json:"routes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DbRiMonitorResponse) Reset()         { *m = DbRiMonitorResponse{} }
+func (m *DbRiMonitorResponse) String() string { return proto.CompactTextString(m) }
+func (*DbRiMonitorResponse) ProtoMessage()    {}
+func (*DbRiMonitorResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fbe8e30501ec2189, []int{29}
+}
+
+func (m *DbRiMonitorResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DbRiMonitorResponse.Unmarshal(m, b)
+}
+func (m *DbRiMonitorResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DbRiMonitorResponse.Marshal(b, m, deterministic)
+}
+func (m *DbRiMonitorResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DbRiMonitorResponse.Merge(m, src)
+}
+func (m *DbRiMonitorResponse) XXX_Size() int {
+	return xxx_messageInfo_DbRiMonitorResponse.Size(m)
+}
+func (m *DbRiMonitorResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DbRiMonitorResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DbRiMonitorResponse proto.InternalMessageInfo
+
+func (m *DbRiMonitorResponse) GetRoutes() []*Route {
+	if m != nil {
+		return m.Routes
+	}
+	return nil
+}
+
+type Interface struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	AreaId               string   `protobuf:"bytes,2,opt,name=area_id,json=areaId,proto3" json:"area_id,omitempty"`
+	NetworkType          string   `protobuf:"bytes,3,opt,name=network_type,json=networkType,proto3" json:"network_type,omitempty"`
+	State                string   `protobuf:"bytes,4,opt,name=state,proto3" json:"state,omitempty"`
+	Address              string   `protobuf:"bytes,5,opt,name=address,proto3" json:"address,omitempty"`
+	Cost                 uint32   `protobuf:"varint,6,opt,name=cost,proto3" json:"cost,omitempty"`
+	Priority             uint32   `protobuf:"varint,7,opt,name=priority,proto3" json:"priority,omitempty"`
+	Dr                   string   `protobuf:"bytes,8,opt,name=dr,proto3" json:"dr,omitempty"`
+	Bdr                  string   `protobuf:"bytes,9,opt,name=bdr,proto3" json:"bdr,omitempty"`
+	HelloInterval        uint32   `protobuf:"varint,10,opt,name=hello_interval,json=helloInterval,proto3" json:"hello_interval,omitempty"`
+	DeadInterval         uint32   `protobuf:"varint,11,opt,name=dead_interval,json=deadInterval,proto3" json:"dead_interval,omitempty"`
+	Passive              bool     `protobuf:"varint,12,opt,name=passive,proto3" json:"passive,omitempty"`
+	NeighborCount        uint32   `protobuf:"varint,13,opt,name=neighbor_count,json=neighborCount,proto3" json:"neighbor_count,omitempty"`
+	AdjacencyCount       uint32   `protobuf:"varint,14,opt,name=adjacency_count,json=adjacencyCount,proto3" json:"adjacency_count,omitempty"`
+	TtlSecurity          bool     `protobuf:"varint,15,opt,name=ttl_security,json=ttlSecurity,proto3" json:"ttl_security,omitempty"`
+	TtlSecurityDrops     uint64   `protobuf:"varint,16,opt,name=ttl_security_drops,json=ttlSecurityDrops,proto3" json:"ttl_security_drops,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Interface) Reset()         { *m = Interface{} }
+func (m *Interface) String() string { return proto.CompactTextString(m) }
+func (*Interface) ProtoMessage()    {}
+func (*Interface) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fbe8e30501ec2189, []int{30}
+}
+
+func (m *Interface) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Interface.Unmarshal(m, b)
+}
+func (m *Interface) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Interface.Marshal(b, m, deterministic)
+}
+func (m *Interface) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Interface.Merge(m, src)
+}
+func (m *Interface) XXX_Size() int {
+	return xxx_messageInfo_Interface.Size(m)
+}
+func (m *Interface) XXX_DiscardUnknown() {
+	xxx_messageInfo_Interface.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Interface proto.InternalMessageInfo
+
+func (m *Interface) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Interface) GetAreaId() string {
+	if m != nil {
+		return m.AreaId
+	}
+	return ""
+}
+
+func (m *Interface) GetNetworkType() string {
+	if m != nil {
+		return m.NetworkType
+	}
+	return ""
+}
+
+func (m *Interface) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+func (m *Interface) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *Interface) GetCost() uint32 {
+	if m != nil {
+		return m.Cost
+	}
+	return 0
+}
+
+func (m *Interface) GetPriority() uint32 {
+	if m != nil {
+		return m.Priority
+	}
+	return 0
+}
+
+func (m *Interface) GetDr() string {
+	if m != nil {
+		return m.Dr
+	}
+	return ""
+}
+
+func (m *Interface) GetBdr() string {
+	if m != nil {
+		return m.Bdr
+	}
+	return ""
+}
+
+func (m *Interface) GetHelloInterval() uint32 {
+	if m != nil {
+		return m.HelloInterval
+	}
+	return 0
+}
+
+func (m *Interface) GetDeadInterval() uint32 {
+	if m != nil {
+		return m.DeadInterval
+	}
+	return 0
+}
+
+func (m *Interface) GetPassive() bool {
+	if m != nil {
+		return m.Passive
+	}
+	return false
+}
+
+func (m *Interface) GetNeighborCount() uint32 {
+	if m != nil {
+		return m.NeighborCount
+	}
+	return 0
+}
+
+func (m *Interface) GetAdjacencyCount() uint32 {
+	if m != nil {
+		return m.AdjacencyCount
+	}
+	return 0
+}
+
+func (m *Interface) GetTtlSecurity() bool {
+	if m != nil {
+		return m.TtlSecurity
+	}
+	return false
+}
+
+func (m *Interface) GetTtlSecurityDrops() uint64 {
+	if m != nil {
+		return m.TtlSecurityDrops
+	}
+	return 0
+}
+
+type Neighbor struct {
+	Interface            string   `protobuf:"bytes,1,opt,name=interface,proto3" json:"interface,omitempty"`
+	AreaId               string   `protobuf:"bytes,2,opt,name=area_id,json=areaId,proto3" json:"area_id,omitempty"`
+	RouterId             string   `protobuf:"bytes,3,opt,name=router_id,json=routerId,proto3" json:"router_id,omitempty"`
+	Address              string   `protobuf:"bytes,4,opt,name=address,proto3" json:"address,omitempty"`
+	Priority             uint32   `protobuf:"varint,5,opt,name=priority,proto3" json:"priority,omitempty"`
+	State                string   `protobuf:"bytes,6,opt,name=state,proto3" json:"state,omitempty"`
+	Dr                   string   `protobuf:"bytes,7,opt,name=dr,proto3" json:"dr,omitempty"`
+	Bdr                  string   `protobuf:"bytes,8,opt,name=bdr,proto3" json:"bdr,omitempty"`
+	DeadTimer            uint32   `protobuf:"varint,9,opt,name=dead_timer,json=deadTimer,proto3" json:"dead_timer,omitempty"`
+	RetransmissionCount  uint32   `protobuf:"varint,10,opt,name=retransmission_count,json=retransmissionCount,proto3" json:"retransmission_count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Neighbor) Reset()         { *m = Neighbor{} }
+func (m *Neighbor) String() string { return proto.CompactTextString(m) }
+func (*Neighbor) ProtoMessage()    {}
+func (*Neighbor) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fbe8e30501ec2189, []int{31}
+}
+
+func (m *Neighbor) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Neighbor.Unmarshal(m, b)
+}
+func (m *Neighbor) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Neighbor.Marshal(b, m, deterministic)
+}
+func (m *Neighbor) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Neighbor.Merge(m, src)
+}
+func (m *Neighbor) XXX_Size() int {
+	return xxx_messageInfo_Neighbor.Size(m)
+}
+func (m *Neighbor) XXX_DiscardUnknown() {
+	xxx_messageInfo_Neighbor.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Neighbor proto.InternalMessageInfo
+
+func (m *Neighbor) GetInterface() string {
+	if m != nil {
+		return m.Interface
+	}
+	return ""
+}
+
+func (m *Neighbor) GetAreaId() string {
+	if m != nil {
+		return m.AreaId
+	}
+	return ""
+}
+
+func (m *Neighbor) GetRouterId() string {
+	if m != nil {
+		return m.RouterId
+	}
+	return ""
+}
+
+func (m *Neighbor) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *Neighbor) GetPriority() uint32 {
+	if m != nil {
+		return m.Priority
+	}
+	return 0
+}
+
+func (m *Neighbor) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+func (m *Neighbor) GetDr() string {
+	if m != nil {
+		return m.Dr
+	}
+	return ""
+}
+
+func (m *Neighbor) GetBdr() string {
+	if m != nil {
+		return m.Bdr
+	}
+	return ""
+}
+
+func (m *Neighbor) GetDeadTimer() uint32 {
+	if m != nil {
+		return m.DeadTimer
+	}
+	return 0
+}
+
+func (m *Neighbor) GetRetransmissionCount() uint32 {
+	if m != nil {
+		return m.RetransmissionCount
+	}
+	return 0
+}
+
+type Lsa struct {
+	AreaId               string   `protobuf:"bytes,1,opt,name=area_id,json=areaId,proto3" json:"area_id,omitempty"`
+	Interface            string   `protobuf:"bytes,2,opt,name=interface,proto3" json:"interface,omitempty"`
+	LsType               string   `protobuf:"bytes,3,opt,name=ls_type,json=lsType,proto3" json:"ls_type,omitempty"`
+	LinkStateId          string   `protobuf:"bytes,4,opt,name=link_state_id,json=linkStateId,proto3" json:"link_state_id,omitempty"`
+	AdvertisingRouter    string   `protobuf:"bytes,5,opt,name=advertising_router,json=advertisingRouter,proto3" json:"advertising_router,omitempty"`
+	Age                  uint32   `protobuf:"varint,6,opt,name=age,proto3" json:"age,omitempty"`
+	SequenceNumber       uint32   `protobuf:"varint,7,opt,name=sequence_number,json=sequenceNumber,proto3" json:"sequence_number,omitempty"`
+	Checksum             uint32   `protobuf:"varint,8,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	Length               uint32   `protobuf:"varint,9,opt,name=length,proto3" json:"length,omitempty"`
+	Detail               string   `protobuf:"bytes,10,opt,name=detail,proto3" json:"detail,omitempty"`
+	Binary               []byte   `protobuf:"bytes,11,opt,name=binary,proto3" json:"binary,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Lsa) Reset()         { *m = Lsa{} }
+func (m *Lsa) String() string { return proto.CompactTextString(m) }
+func (*Lsa) ProtoMessage()    {}
+func (*Lsa) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fbe8e30501ec2189, []int{32}
+}
+
+func (m *Lsa) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Lsa.Unmarshal(m, b)
+}
+func (m *Lsa) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Lsa.Marshal(b, m, deterministic)
+}
+func (m *Lsa) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Lsa.Merge(m, src)
+}
+func (m *Lsa) XXX_Size() int {
+	return xxx_messageInfo_Lsa.Size(m)
+}
+func (m *Lsa) XXX_DiscardUnknown() {
+	xxx_messageInfo_Lsa.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Lsa proto.InternalMessageInfo
+
+func (m *Lsa) GetAreaId() string {
+	if m != nil {
+		return m.AreaId
+	}
+	return ""
+}
+
+func (m *Lsa) GetInterface() string {
+	if m != nil {
+		return m.Interface
+	}
+	return ""
+}
+
+func (m *Lsa) GetLsType() string {
+	if m != nil {
+		return m.LsType
+	}
+	return ""
+}
+
+func (m *Lsa) GetLinkStateId() string {
+	if m != nil {
+		return m.LinkStateId
+	}
+	return ""
+}
+
+func (m *Lsa) GetAdvertisingRouter() string {
+	if m != nil {
+		return m.AdvertisingRouter
+	}
+	return ""
+}
+
+func (m *Lsa) GetAge() uint32 {
+	if m != nil {
+		return m.Age
+	}
+	return 0
+}
+
+func (m *Lsa) GetSequenceNumber() uint32 {
+	if m != nil {
+		return m.SequenceNumber
+	}
+	return 0
+}
+
+func (m *Lsa) GetChecksum() uint32 {
+	if m != nil {
+		return m.Checksum
+	}
+	return 0
+}
+
+func (m *Lsa) GetLength() uint32 {
+	if m != nil {
+		return m.Length
+	}
+	return 0
+}
+
+func (m *Lsa) GetDetail() string {
+	if m != nil {
+		return m.Detail
+	}
+	return ""
+}
+
+func (m *Lsa) GetBinary() []byte {
+	if m != nil {
+		return m.Binary
+	}
+	return nil
+}
+
+type Route struct {
+	AddressFamily        string     `protobuf:"bytes,1,opt,name=address_family,json=addressFamily,proto3" json:"address_family,omitempty"`
+	Prefix               string     `protobuf:"bytes,2,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	RouteType            string     `protobuf:"bytes,3,opt,name=route_type,json=routeType,proto3" json:"route_type,omitempty"`
+	AreaId               string     `protobuf:"bytes,4,opt,name=area_id,json=areaId,proto3" json:"area_id,omitempty"`
+	Metric               uint32     `protobuf:"varint,5,opt,name=metric,proto3" json:"metric,omitempty"`
+	Type2Metric          uint32     `protobuf:"varint,6,opt,name=type2_metric,json=type2Metric,proto3" json:"type2_metric,omitempty"`
+	NextHops             []*NextHop `protobuf:"bytes,7,rep,name=next_hops,json=nextHops,proto3" json:"next_hops,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *Route) Reset()         { *m = Route{} }
+func (m *Route) String() string { return proto.CompactTextString(m) }
+func (*Route) ProtoMessage()    {}
+func (*Route) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fbe8e30501ec2189, []int{33}
+}
+
+func (m *Route) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Route.Unmarshal(m, b)
+}
+func (m *Route) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Route.Marshal(b, m, deterministic)
+}
+func (m *Route) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Route.Merge(m, src)
+}
+func (m *Route) XXX_Size() int {
+	return xxx_messageInfo_Route.Size(m)
+}
+func (m *Route) XXX_DiscardUnknown() {
+	xxx_messageInfo_Route.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Route proto.InternalMessageInfo
+
+func (m *Route) GetAddressFamily() string {
+	if m != nil {
+		return m.AddressFamily
+	}
+	return ""
+}
+
+func (m *Route) GetPrefix() string {
+	if m != nil {
+		return m.Prefix
+	}
+	return ""
+}
+
+func (m *Route) GetRouteType() string {
+	if m != nil {
+		return m.RouteType
+	}
+	return ""
+}
+
+func (m *Route) GetAreaId() string {
+	if m != nil {
+		return m.AreaId
+	}
+	return ""
+}
+
+func (m *Route) GetMetric() uint32 {
+	if m != nil {
+		return m.Metric
+	}
+	return 0
+}
+
+func (m *Route) GetType2Metric() uint32 {
+	if m != nil {
+		return m.Type2Metric
+	}
+	return 0
+}
+
+func (m *Route) GetNextHops() []*NextHop {
+	if m != nil {
+		return m.NextHops
+	}
+	return nil
+}
+
+type NextHop struct {
+	OutgoingInterface    string   `protobuf:"bytes,1,opt,name=outgoing_interface,json=outgoingInterface,proto3" json:"outgoing_interface,omitempty"`
+	NextHop              string   `protobuf:"bytes,2,opt,name=next_hop,json=nextHop,proto3" json:"next_hop,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NextHop) Reset()         { *m = NextHop{} }
+func (m *NextHop) String() string { return proto.CompactTextString(m) }
+func (*NextHop) ProtoMessage()    {}
+func (*NextHop) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fbe8e30501ec2189, []int{34}
+}
+
+func (m *NextHop) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NextHop.Unmarshal(m, b)
+}
+func (m *NextHop) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NextHop.Marshal(b, m, deterministic)
+}
+func (m *NextHop) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NextHop.Merge(m, src)
+}
+func (m *NextHop) XXX_Size() int {
+	return xxx_messageInfo_NextHop.Size(m)
+}
+func (m *NextHop) XXX_DiscardUnknown() {
+	xxx_messageInfo_NextHop.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NextHop proto.InternalMessageInfo
+
+func (m *NextHop) GetOutgoingInterface() string {
+	if m != nil {
+		return m.OutgoingInterface
+	}
+	return ""
+}
+
+func (m *NextHop) GetNextHop() string {
+	if m != nil {
+		return m.NextHop
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*EnableRequest)(nil), "goospfapi.EnableRequest")
 	proto.RegisterType((*EnableResponse)(nil), "goospfapi.EnableResponse")
 	proto.RegisterType((*DisableRequest)(nil), "goospfapi.DisableRequest")
 	proto.RegisterType((*DisableResponse)(nil), "goospfapi.DisableResponse")
+	proto.RegisterType((*InterfaceGetRequest)(nil), "goospfapi.InterfaceGetRequest")
+	proto.RegisterType((*InterfaceGetResponse)(nil), "goospfapi.InterfaceGetResponse")
+	proto.RegisterType((*InterfaceMonitorRequest)(nil), "goospfapi.InterfaceMonitorRequest")
+	proto.RegisterType((*InterfaceMonitorResponse)(nil), "goospfapi.InterfaceMonitorResponse")
+	proto.RegisterType((*NeighborGetRequest)(nil), "goospfapi.NeighborGetRequest")
+	proto.RegisterType((*NeighborGetResponse)(nil), "goospfapi.NeighborGetResponse")
+	proto.RegisterType((*NeighborMonitorRequest)(nil), "goospfapi.NeighborMonitorRequest")
+	proto.RegisterType((*NeighborMonitorResponse)(nil), "goospfapi.NeighborMonitorResponse")
+	proto.RegisterType((*VirtualLinkGetRequest)(nil), "goospfapi.VirtualLinkGetRequest")
+	proto.RegisterType((*VirtualLinkGetResponse)(nil), "goospfapi.VirtualLinkGetResponse")
+	proto.RegisterType((*VirtualLink)(nil), "goospfapi.VirtualLink")
+	proto.RegisterType((*StubRouterGetRequest)(nil), "goospfapi.StubRouterGetRequest")
+	proto.RegisterType((*StubRouterGetResponse)(nil), "goospfapi.StubRouterGetResponse")
+	proto.RegisterType((*StubRouter)(nil), "goospfapi.StubRouter")
+	proto.RegisterType((*StubRouterEnableRequest)(nil), "goospfapi.StubRouterEnableRequest")
+	proto.RegisterType((*StubRouterEnableResponse)(nil), "goospfapi.StubRouterEnableResponse")
+	proto.RegisterType((*StubRouterDisableRequest)(nil), "goospfapi.StubRouterDisableRequest")
+	proto.RegisterType((*StubRouterDisableResponse)(nil), "goospfapi.StubRouterDisableResponse")
+	proto.RegisterType((*DbLsGetRequest)(nil), "goospfapi.DbLsGetRequest")
+	proto.RegisterType((*DbLsGetResponse)(nil), "goospfapi.DbLsGetResponse")
+	proto.RegisterType((*DbLsMonitorRequest)(nil), "goospfapi.DbLsMonitorRequest")
+	proto.RegisterType((*DbLsMonitorResponse)(nil), "goospfapi.DbLsMonitorResponse")
+	proto.RegisterType((*DbRiGetRequest)(nil), "goospfapi.DbRiGetRequest")
+	proto.RegisterType((*DbRiGetResponse)(nil), "goospfapi.DbRiGetResponse")
+	proto.RegisterType((*DbRiMonitorRequest)(nil), "goospfapi.DbRiMonitorRequest")
+	proto.RegisterType((*DbRiMonitorResponse)(nil), "goospfapi.DbRiMonitorResponse")
+	proto.RegisterType((*Interface)(nil), "goospfapi.Interface")
+	proto.RegisterType((*Neighbor)(nil), "goospfapi.Neighbor")
+	proto.RegisterType((*Lsa)(nil), "goospfapi.Lsa")
+	proto.RegisterType((*Route)(nil), "goospfapi.Route")
+	proto.RegisterType((*NextHop)(nil), "goospfapi.NextHop")
+}
+
+func init() { proto.RegisterFile("goospf.proto", fileDescriptor_fbe8e30501ec2189) }
+
+var fileDescriptor_fbe8e30501ec2189 = []byte{
+	// 1509 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x58, 0xdb, 0x72, 0x1b, 0x37,
+	0x12, 0x2d, 0x5e, 0xc4, 0x4b, 0xf3, 0x2a, 0x48, 0x96, 0x28, 0xee, 0xda, 0x96, 0xc6, 0xeb, 0x5d,
+	0xb9, 0x76, 0xd7, 0xb1, 0xe5, 0xc4, 0xae, 0x94, 0x2b, 0x95, 0x38, 0x51, 0x6c, 0xab, 0x4a, 0xb6,
+	0x53, 0x90, 0x9d, 0x87, 0xa4, 0x92, 0x29, 0x90, 0x03, 0x49, 0x88, 0x86, 0x33, 0x0c, 0x00, 0xca,
+	0xe6, 0xcf, 0xe4, 0x31, 0xbf, 0x92, 0x1f, 0x49, 0xe5, 0x21, 0x8f, 0xf9, 0x82, 0x14, 0x2e, 0x33,
+	0xc4, 0x0c, 0x49, 0x59, 0x4a, 0xde, 0xd8, 0xa7, 0xbb, 0x81, 0x46, 0x1f, 0xe0, 0x00, 0x43, 0x68,
+	0x9e, 0xc4, 0xb1, 0x18, 0x1f, 0xdf, 0x1d, 0xf3, 0x58, 0xc6, 0xa8, 0x6e, 0x2c, 0x32, 0x66, 0x5e,
+	0x07, 0x5a, 0x5f, 0x46, 0x64, 0x10, 0x52, 0x4c, 0x7f, 0x9c, 0x50, 0x21, 0xbd, 0x5d, 0x68, 0x27,
+	0x80, 0x18, 0xc7, 0x91, 0xa0, 0x68, 0x03, 0x2a, 0x9c, 0x8a, 0x49, 0x28, 0x7b, 0x85, 0xed, 0xc2,
+	0x6e, 0x1d, 0x5b, 0xcb, 0xeb, 0x42, 0x7b, 0x9f, 0x09, 0x37, 0xf7, 0x0e, 0x74, 0x52, 0xe4, 0x3d,
+	0xc9, 0x0f, 0x60, 0xed, 0x20, 0x92, 0x94, 0x1f, 0x93, 0x21, 0x7d, 0x46, 0xa5, 0x1d, 0x01, 0xfd,
+	0x13, 0xea, 0x2c, 0x81, 0x6d, 0xc6, 0x0c, 0xf0, 0x0e, 0x61, 0x3d, 0x9b, 0x64, 0x27, 0xf9, 0x10,
+	0x20, 0x0d, 0x12, 0xbd, 0xc2, 0x76, 0x69, 0xb7, 0xb1, 0xb7, 0x7e, 0x37, 0x5d, 0xe4, 0xdd, 0x34,
+	0x09, 0x3b, 0x71, 0xde, 0x23, 0xd8, 0x4c, 0x1d, 0x2f, 0xe2, 0x88, 0xc9, 0x98, 0x5f, 0xae, 0x8c,
+	0xaf, 0xa0, 0x37, 0x9f, 0xf8, 0xb7, 0x4a, 0xd9, 0x03, 0xf4, 0x92, 0xb2, 0x93, 0xd3, 0x41, 0xcc,
+	0x2f, 0xdd, 0x8c, 0xe7, 0xb0, 0x96, 0xc9, 0xb1, 0x05, 0xdc, 0x87, 0x7a, 0x64, 0xe1, 0x64, 0xfe,
+	0x35, 0x67, 0xfe, 0x24, 0x05, 0xcf, 0xa2, 0xbc, 0x87, 0xb0, 0x91, 0xc0, 0x57, 0xea, 0xc3, 0x21,
+	0x6c, 0xce, 0xe5, 0xfd, 0xf5, 0x2a, 0x36, 0xe1, 0xda, 0xd7, 0x8c, 0xcb, 0x09, 0x09, 0x0f, 0x59,
+	0x74, 0x36, 0x6b, 0x83, 0xf7, 0x06, 0x36, 0xf2, 0x0e, 0x3b, 0xcb, 0x63, 0x68, 0x9d, 0x1b, 0x8f,
+	0x1f, 0xb2, 0xe8, 0x2c, 0x99, 0x69, 0xc3, 0x99, 0xc9, 0xc9, 0xc4, 0xcd, 0xf3, 0x99, 0x21, 0xbc,
+	0xdf, 0x8a, 0xd0, 0x70, 0xbc, 0xe8, 0xdf, 0xd0, 0x91, 0x9c, 0x44, 0x82, 0x49, 0x9f, 0x70, 0x4a,
+	0x7c, 0x16, 0xd8, 0x15, 0xb7, 0x2c, 0xfc, 0x84, 0x53, 0x72, 0x10, 0xa0, 0x7f, 0x40, 0x9d, 0xc7,
+	0x13, 0x49, 0xb9, 0x8a, 0x28, 0xea, 0x88, 0x9a, 0x01, 0x0e, 0x02, 0xb4, 0x0e, 0x2b, 0x42, 0x12,
+	0x49, 0x7b, 0x25, 0xed, 0x30, 0x06, 0xba, 0x0d, 0xed, 0x64, 0x9d, 0xbe, 0x71, 0x97, 0xcd, 0xc8,
+	0x09, 0x7a, 0xa4, 0xc3, 0x7a, 0x50, 0x25, 0x41, 0xc0, 0xa9, 0x10, 0xbd, 0x15, 0xed, 0x4f, 0x4c,
+	0x74, 0x07, 0xba, 0xe9, 0x00, 0x49, 0x48, 0x45, 0x87, 0x74, 0x12, 0xfc, 0x89, 0x0d, 0x45, 0x50,
+	0x1e, 0xc6, 0x42, 0xf6, 0xaa, 0xdb, 0x85, 0xdd, 0x16, 0xd6, 0xbf, 0xd1, 0x7f, 0x61, 0x35, 0x59,
+	0xda, 0x8c, 0xce, 0x9a, 0xce, 0xef, 0x5a, 0x47, 0xba, 0x2f, 0xd1, 0x0e, 0x34, 0xa5, 0x0c, 0x7d,
+	0x41, 0x87, 0x13, 0xce, 0xe4, 0xb4, 0x57, 0xdf, 0x2e, 0xec, 0xd6, 0x70, 0x43, 0xca, 0xf0, 0xc8,
+	0x42, 0xe8, 0x7f, 0x80, 0xdc, 0x10, 0x3f, 0xe0, 0xf1, 0x58, 0xf4, 0x60, 0xbb, 0xb0, 0x5b, 0xc6,
+	0x5d, 0x27, 0x70, 0x5f, 0xe1, 0xde, 0x06, 0xac, 0x1f, 0xc9, 0xc9, 0x00, 0xeb, 0x1e, 0x39, 0xbc,
+	0xbe, 0x82, 0x6b, 0x39, 0xdc, 0xd2, 0xfa, 0x10, 0x1a, 0x42, 0x4e, 0x06, 0xbe, 0xe9, 0xaa, 0x66,
+	0xa1, 0xb1, 0x77, 0xcd, 0x21, 0x75, 0x96, 0x86, 0x41, 0xa4, 0xbf, 0xbd, 0x9f, 0x0b, 0x00, 0x33,
+	0x97, 0x92, 0x1e, 0x32, 0x94, 0xec, 0xdc, 0xec, 0xdc, 0x1a, 0xb6, 0x96, 0xc6, 0xc3, 0xb7, 0x64,
+	0x2a, 0x7a, 0x45, 0x8b, 0x6b, 0x4b, 0x11, 0x1b, 0x47, 0x7e, 0x40, 0x47, 0x24, 0x0a, 0x34, 0x7f,
+	0x35, 0x5c, 0x8b, 0xa3, 0x7d, 0x6d, 0xa3, 0x7b, 0xb0, 0x1e, 0x47, 0x8a, 0x3c, 0x2e, 0x27, 0x63,
+	0x9f, 0xd3, 0x11, 0x61, 0x11, 0x8b, 0x4e, 0x34, 0x91, 0x2d, 0x8c, 0xe2, 0xe8, 0xc8, 0xb8, 0x70,
+	0xe2, 0x41, 0x5b, 0x50, 0x7b, 0x4b, 0x98, 0xf4, 0x8f, 0x63, 0x9e, 0xd0, 0xa9, 0xec, 0xa7, 0x31,
+	0xf7, 0xb6, 0x60, 0x73, 0x56, 0x67, 0x56, 0x7e, 0xf7, 0xa0, 0x37, 0xef, 0x7a, 0x8f, 0x96, 0xf6,
+	0xdd, 0x9c, 0x9c, 0x24, 0x3f, 0x80, 0xad, 0x05, 0xbe, 0xf7, 0x0c, 0xf8, 0x39, 0xb4, 0xf7, 0x07,
+	0x87, 0xc2, 0x91, 0xa2, 0x4d, 0xa8, 0x66, 0x0f, 0x45, 0x85, 0x98, 0xd3, 0xb0, 0x09, 0xd5, 0x50,
+	0xf8, 0x72, 0x3a, 0xa6, 0xf6, 0x2c, 0x54, 0x42, 0xf1, 0x7a, 0x3a, 0xa6, 0xde, 0x47, 0xd0, 0x49,
+	0xc7, 0xb0, 0xd3, 0x79, 0x50, 0x0e, 0x05, 0x49, 0x4e, 0x69, 0xdb, 0x21, 0xf4, 0x50, 0x10, 0xac,
+	0x7d, 0xde, 0x53, 0x40, 0x2a, 0x2d, 0xa7, 0x43, 0x57, 0x9f, 0xfe, 0x63, 0x58, 0xcb, 0x8c, 0x73,
+	0x85, 0x12, 0x1e, 0xa9, 0xd5, 0x63, 0xe6, 0xac, 0xfe, 0x36, 0xb4, 0xed, 0xa9, 0xf3, 0x8f, 0xc9,
+	0x88, 0x85, 0xd3, 0x44, 0x19, 0x2c, 0xfa, 0x54, 0x83, 0xde, 0x63, 0xe8, 0xa4, 0x89, 0x76, 0xbe,
+	0x5d, 0xa8, 0xe8, 0x5d, 0x9c, 0xcc, 0xd8, 0x75, 0x66, 0xd4, 0x9c, 0x60, 0xeb, 0xf7, 0x1e, 0xab,
+	0x85, 0x63, 0x96, 0x5b, 0xf8, 0x25, 0x67, 0xfe, 0x14, 0xd6, 0x32, 0xc9, 0x57, 0x9e, 0xfd, 0xd7,
+	0x12, 0xd4, 0x67, 0x12, 0x80, 0xa0, 0x1c, 0x91, 0x51, 0xa2, 0xf8, 0xfa, 0xb7, 0x4b, 0x41, 0x31,
+	0x43, 0xc1, 0x0e, 0x34, 0x23, 0x2a, 0xdf, 0xc6, 0xfc, 0xcc, 0xf0, 0x60, 0x94, 0xaf, 0x61, 0x31,
+	0x45, 0xc6, 0x4c, 0x15, 0xcb, 0xae, 0x2a, 0x2e, 0x97, 0xbb, 0x44, 0xc3, 0x2a, 0x8e, 0x86, 0xf5,
+	0xa1, 0x36, 0xe6, 0x2c, 0xd6, 0x92, 0x64, 0xb4, 0x2d, 0xb5, 0x51, 0x1b, 0x8a, 0x01, 0xb7, 0x82,
+	0x56, 0x0c, 0x38, 0xea, 0x42, 0x69, 0x10, 0x70, 0xad, 0x5c, 0x75, 0xac, 0x7e, 0xaa, 0x3e, 0x9e,
+	0xd2, 0x30, 0x8c, 0x8d, 0xfe, 0x9d, 0x93, 0x50, 0xab, 0x55, 0x0b, 0xb7, 0x34, 0x7a, 0x60, 0x41,
+	0x74, 0x0b, 0x5a, 0x01, 0x25, 0xc1, 0x2c, 0xaa, 0xa1, 0xa3, 0x9a, 0x0a, 0x4c, 0x83, 0x7a, 0x50,
+	0x1d, 0x13, 0x21, 0x94, 0xb0, 0x34, 0xb5, 0x4a, 0x24, 0x66, 0x46, 0xe7, 0x87, 0xf1, 0x24, 0x92,
+	0xbd, 0x96, 0x99, 0x25, 0x41, 0xbf, 0x50, 0x20, 0xfa, 0x0f, 0x74, 0x48, 0xf0, 0x03, 0x19, 0xd2,
+	0x68, 0x38, 0xb5, 0x71, 0x6d, 0x1d, 0xd7, 0x4e, 0x61, 0x13, 0x98, 0x97, 0xe2, 0xce, 0x65, 0xa5,
+	0xb8, 0xbb, 0x44, 0x8a, 0x7f, 0x2a, 0x42, 0x2d, 0xb9, 0x7b, 0x2f, 0xbe, 0xdc, 0x97, 0xf3, 0x9d,
+	0xb9, 0xff, 0x4a, 0xb9, 0xfb, 0xcf, 0xe1, 0xb4, 0x9c, 0xe5, 0xd4, 0xe5, 0x6f, 0x25, 0xc7, 0x5f,
+	0xba, 0x3f, 0x2a, 0xee, 0xfe, 0x30, 0xac, 0x56, 0xf3, 0xac, 0xd6, 0x66, 0xac, 0x5e, 0x07, 0xd0,
+	0x74, 0x49, 0x36, 0xa2, 0x86, 0xee, 0x16, 0xae, 0x2b, 0xe4, 0xb5, 0x02, 0xd0, 0x7d, 0x58, 0xe7,
+	0x54, 0xdf, 0x6f, 0x23, 0x26, 0x04, 0x8b, 0x23, 0xdb, 0x6c, 0x43, 0xfd, 0x5a, 0xd6, 0xa7, 0x3b,
+	0xee, 0xfd, 0x52, 0x84, 0xd2, 0xa1, 0x20, 0xcb, 0x05, 0x27, 0xd3, 0xb4, 0xe2, 0x82, 0xa6, 0x25,
+	0x72, 0x54, 0x72, 0xe5, 0x08, 0x79, 0xd0, 0x52, 0x2f, 0x14, 0x73, 0xfb, 0xab, 0x51, 0x4d, 0x77,
+	0x1a, 0x0a, 0xd4, 0x97, 0xff, 0x41, 0x80, 0xfe, 0x0f, 0x88, 0x04, 0xe7, 0x94, 0x4b, 0x26, 0x58,
+	0x74, 0x92, 0xdc, 0x7e, 0xe6, 0x68, 0xac, 0x3a, 0x1e, 0x7b, 0xbd, 0x75, 0xa1, 0x44, 0x4e, 0xa8,
+	0x3d, 0x23, 0xea, 0xa7, 0xda, 0x57, 0x42, 0xe9, 0x46, 0x34, 0xa4, 0x7e, 0x34, 0x19, 0x0d, 0x28,
+	0xb7, 0x27, 0xa5, 0x9d, 0xc0, 0x2f, 0x35, 0xaa, 0xb8, 0x18, 0x9e, 0xd2, 0xe1, 0x99, 0x98, 0x8c,
+	0x74, 0x3b, 0x5b, 0x38, 0xb5, 0xd5, 0x9d, 0x10, 0xd2, 0xe8, 0x44, 0x9e, 0xda, 0x7e, 0x5a, 0x4b,
+	0xe1, 0x01, 0x95, 0x84, 0x99, 0x93, 0x53, 0xc7, 0xd6, 0x52, 0xf8, 0x80, 0x45, 0x84, 0x4f, 0xf5,
+	0x59, 0x69, 0x62, 0x6b, 0x79, 0xbf, 0x17, 0x60, 0x45, 0x57, 0x7a, 0x49, 0x0d, 0x53, 0x03, 0x8d,
+	0x39, 0x3d, 0x66, 0xef, 0x92, 0xfd, 0x66, 0x2c, 0x45, 0xb2, 0x6e, 0x85, 0xdb, 0x56, 0xb3, 0x03,
+	0x75, 0x67, 0x1d, 0xa6, 0xca, 0x19, 0xa6, 0x36, 0xa0, 0x32, 0xa2, 0x92, 0xb3, 0xa1, 0xdd, 0x6e,
+	0xd6, 0xd2, 0x87, 0x6a, 0x3a, 0xa6, 0x7b, 0xbe, 0xf5, 0x9a, 0x06, 0x36, 0x34, 0xf6, 0xc2, 0x84,
+	0x7c, 0xa0, 0x5e, 0xaf, 0xef, 0xa4, 0x7f, 0xaa, 0xce, 0x52, 0x55, 0x4b, 0x27, 0xca, 0xbc, 0x5e,
+	0xdf, 0xc9, 0xe7, 0xf1, 0x18, 0xd7, 0x22, 0xf3, 0x43, 0x78, 0x47, 0x50, 0xb5, 0xa0, 0x62, 0x31,
+	0x9e, 0xc8, 0x93, 0x58, 0x51, 0x98, 0x3f, 0x5e, 0xab, 0x89, 0x67, 0x26, 0xb5, 0x5b, 0x50, 0x4b,
+	0xa6, 0xb2, 0xeb, 0xae, 0xda, 0x51, 0xf7, 0xfe, 0xa8, 0x41, 0xfd, 0x99, 0x9e, 0xf4, 0xc9, 0x98,
+	0xa1, 0x4f, 0xa0, 0x62, 0x9e, 0x03, 0xa8, 0xe7, 0x94, 0x92, 0x79, 0x3c, 0xf4, 0xb7, 0x16, 0x78,
+	0xec, 0x55, 0xf0, 0x19, 0x54, 0xed, 0xed, 0x8f, 0xdc, 0xa8, 0xec, 0x6b, 0xa1, 0xdf, 0x5f, 0xe4,
+	0xb2, 0x23, 0xbc, 0x82, 0xa6, 0xfb, 0xf1, 0x85, 0x6e, 0x2c, 0xfa, 0xaa, 0x99, 0x5d, 0x9a, 0xfd,
+	0x9b, 0x4b, 0xfd, 0x76, 0xc0, 0xef, 0xa0, 0x9b, 0xff, 0x8c, 0x42, 0xde, 0xa2, 0xa4, 0xec, 0x9d,
+	0xd8, 0xbf, 0x75, 0x61, 0x8c, 0x19, 0xfc, 0x5e, 0x01, 0x1d, 0x42, 0xc3, 0xf9, 0x3e, 0x42, 0xd7,
+	0x17, 0x7c, 0x7e, 0x38, 0xd5, 0xde, 0x58, 0xe6, 0xb6, 0xc5, 0x7e, 0x03, 0x9d, 0xdc, 0xb7, 0x0e,
+	0xda, 0x59, 0x90, 0x92, 0x2b, 0xd5, 0xbb, 0x28, 0x24, 0xad, 0xf4, 0x0d, 0xb4, 0xb3, 0x1f, 0x38,
+	0x68, 0x7b, 0xf1, 0x17, 0x8c, 0x53, 0xef, 0xce, 0x05, 0x11, 0xb6, 0x64, 0x0c, 0xad, 0xcc, 0xfb,
+	0x1a, 0xdd, 0x5c, 0xf8, 0x84, 0x76, 0x06, 0xdd, 0x5e, 0x1e, 0x60, 0xc7, 0xfc, 0x16, 0xba, 0xf9,
+	0xe7, 0x69, 0x86, 0xb3, 0x25, 0xcf, 0xda, 0xfe, 0xad, 0x0b, 0x63, 0xec, 0xe0, 0xdf, 0xc3, 0xea,
+	0xdc, 0x5b, 0x15, 0x2d, 0xce, 0xcc, 0xed, 0xdb, 0x7f, 0x5d, 0x1c, 0xe4, 0x9c, 0x01, 0xf3, 0x24,
+	0xcd, 0x9e, 0x81, 0xcc, 0x53, 0xb7, 0xdf, 0x5f, 0xe4, 0xb2, 0x23, 0xbc, 0x84, 0x86, 0xf3, 0xaa,
+	0xcc, 0xec, 0xa9, 0xf9, 0x57, 0x6b, 0xff, 0xc6, 0x32, 0x77, 0xca, 0xbc, 0xae, 0x08, 0xb3, 0xf9,
+	0x8a, 0x30, 0x5b, 0x5a, 0x11, 0x66, 0x73, 0x15, 0x61, 0xb6, 0xb8, 0x22, 0xcc, 0x2e, 0xac, 0x68,
+	0xee, 0xc1, 0x78, 0xaf, 0x30, 0xa8, 0xe8, 0x7f, 0x88, 0x1e, 0xfc, 0x39, 0x00, 0x8b, 0x67, 0xa6,
+	0x09, 0x31, 0x12, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// GoospfApiClient is the client API for GoospfApi service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type GoospfApiClient interface {
+	Enable(ctx context.Context, in *EnableRequest, opts ...grpc.CallOption) (*EnableResponse, error)
+	Disable(ctx context.Context, in *DisableRequest, opts ...grpc.CallOption) (*DisableResponse, error)
+	InterfaceGet(ctx context.Context, in *InterfaceGetRequest, opts ...grpc.CallOption) (*InterfaceGetResponse, error)
+	InterfaceMonitor(ctx context.Context, in *InterfaceMonitorRequest, opts ...grpc.CallOption) (GoospfApi_InterfaceMonitorClient, error)
+	NeighborGet(ctx context.Context, in *NeighborGetRequest, opts ...grpc.CallOption) (*NeighborGetResponse, error)
+	NeighborMonitor(ctx context.Context, in *NeighborMonitorRequest, opts ...grpc.CallOption) (GoospfApi_NeighborMonitorClient, error)
+	VirtualLinkGet(ctx context.Context, in *VirtualLinkGetRequest, opts ...grpc.CallOption) (*VirtualLinkGetResponse, error)
+	StubRouterGet(ctx context.Context, in *StubRouterGetRequest, opts ...grpc.CallOption) (*StubRouterGetResponse, error)
+	StubRouterEnable(ctx context.Context, in *StubRouterEnableRequest, opts ...grpc.CallOption) (*StubRouterEnableResponse, error)
+	StubRouterDisable(ctx context.Context, in *StubRouterDisableRequest, opts ...grpc.CallOption) (*StubRouterDisableResponse, error)
+	DbLsGet(ctx context.Context, in *DbLsGetRequest, opts ...grpc.CallOption) (*DbLsGetResponse, error)
+	DbLsMonitor(ctx context.Context, in *DbLsMonitorRequest, opts ...grpc.CallOption) (GoospfApi_DbLsMonitorClient, error)
+	DbRiGet(ctx context.Context, in *DbRiGetRequest, opts ...grpc.CallOption) (*DbRiGetResponse, error)
+	DbRiMonitor(ctx context.Context, in *DbRiMonitorRequest, opts ...grpc.CallOption) (GoospfApi_DbRiMonitorClient, error)
+}
+
+type goospfApiClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewGoospfApiClient(cc *grpc.ClientConn) GoospfApiClient {
+	return &goospfApiClient{cc}
+}
+
+func (c *goospfApiClient) Enable(ctx context.Context, in *EnableRequest, opts ...grpc.CallOption) (*EnableResponse, error) {
+	out := new(EnableResponse)
+	err := c.cc.Invoke(ctx, "/goospfapi.GoospfApi/Enable", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goospfApiClient) Disable(ctx context.Context, in *DisableRequest, opts ...grpc.CallOption) (*DisableResponse, error) {
+	out := new(DisableResponse)
+	err := c.cc.Invoke(ctx, "/goospfapi.GoospfApi/Disable", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goospfApiClient) InterfaceGet(ctx context.Context, in *InterfaceGetRequest, opts ...grpc.CallOption) (*InterfaceGetResponse, error) {
+	out := new(InterfaceGetResponse)
+	err := c.cc.Invoke(ctx, "/goospfapi.GoospfApi/InterfaceGet", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goospfApiClient) InterfaceMonitor(ctx context.Context, in *InterfaceMonitorRequest, opts ...grpc.CallOption) (GoospfApi_InterfaceMonitorClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_GoospfApi_serviceDesc.Streams[0], "/goospfapi.GoospfApi/InterfaceMonitor", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &goospfApiInterfaceMonitorClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type GoospfApi_InterfaceMonitorClient interface {
+	Recv() (*InterfaceMonitorResponse, error)
+	grpc.ClientStream
+}
+
+type goospfApiInterfaceMonitorClient struct {
+	grpc.ClientStream
+}
+
+func (x *goospfApiInterfaceMonitorClient) Recv() (*InterfaceMonitorResponse, error) {
+	m := new(InterfaceMonitorResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *goospfApiClient) NeighborGet(ctx context.Context, in *NeighborGetRequest, opts ...grpc.CallOption) (*NeighborGetResponse, error) {
+	out := new(NeighborGetResponse)
+	err := c.cc.Invoke(ctx, "/goospfapi.GoospfApi/NeighborGet", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func init() { proto.RegisterFile("goospf.proto", fileDescriptor_fbe8e30501ec2189) }
+func (c *goospfApiClient) NeighborMonitor(ctx context.Context, in *NeighborMonitorRequest, opts ...grpc.CallOption) (GoospfApi_NeighborMonitorClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_GoospfApi_serviceDesc.Streams[1], "/goospfapi.GoospfApi/NeighborMonitor", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &goospfApiNeighborMonitorClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
 
-var fileDescriptor_fbe8e30501ec2189 = []byte{
-	// 163 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0x49, 0xcf, 0xcf, 0x2f,
-	0x2e, 0x48, 0xd3, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0xe2, 0x84, 0xf0, 0x12, 0x0b, 0x32, 0x95,
-	0xf8, 0xb9, 0x78, 0x5d, 0xf3, 0x12, 0x93, 0x72, 0x52, 0x83, 0x52, 0x0b, 0x4b, 0x53, 0x8b, 0x4b,
-	0x94, 0x34, 0xb8, 0xf8, 0x60, 0x02, 0xc5, 0x05, 0xf9, 0x79, 0xc5, 0xa9, 0x42, 0x62, 0x5c, 0x6c,
-	0x45, 0xa9, 0xc5, 0xa5, 0x39, 0x25, 0x12, 0x8c, 0x0a, 0x8c, 0x1a, 0x9c, 0x41, 0x50, 0x9e, 0x92,
-	0x00, 0x17, 0x9f, 0x4b, 0x66, 0x31, 0xb2, 0x5e, 0x4d, 0x2e, 0x7e, 0xb8, 0x08, 0x7e, 0xcd, 0x46,
-	0x3d, 0x8c, 0x5c, 0x9c, 0xee, 0x60, 0x57, 0x38, 0x16, 0x64, 0x0a, 0xd9, 0x72, 0xb1, 0x41, 0x2c,
-	0x15, 0x92, 0xd0, 0x83, 0xbb, 0x4d, 0x0f, 0xc5, 0x61, 0x52, 0x92, 0x58, 0x64, 0xa0, 0x96, 0x38,
-	0x70, 0xb1, 0x43, 0xed, 0x15, 0x42, 0x56, 0x85, 0xea, 0x3a, 0x29, 0x29, 0x6c, 0x52, 0x10, 0x13,
-	0x92, 0xd8, 0xc0, 0x01, 0x63, 0x0c, 0x08, 0x00, 0x00, 0xff, 0xff, 0x3f, 0x7a, 0xd5, 0xfc, 0x28,
-	0x01, 0x00, 0x00,
+type GoospfApi_NeighborMonitorClient interface {
+	Recv() (*NeighborMonitorResponse, error)
+	grpc.ClientStream
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+type goospfApiNeighborMonitorClient struct {
+	grpc.ClientStream
+}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+func (x *goospfApiNeighborMonitorClient) Recv() (*NeighborMonitorResponse, error) {
+	m := new(NeighborMonitorResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
 
-// GoospfApiClient is the client API for GoospfApi service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type GoospfApiClient interface {
-	Enable(ctx context.Context, in *EnableRequest, opts ...grpc.CallOption) (*EnableResponse, error)
-	Disable(ctx context.Context, in *DisableRequest, opts ...grpc.CallOption) (*DisableResponse, error)
+func (c *goospfApiClient) VirtualLinkGet(ctx context.Context, in *VirtualLinkGetRequest, opts ...grpc.CallOption) (*VirtualLinkGetResponse, error) {
+	out := new(VirtualLinkGetResponse)
+	err := c.cc.Invoke(ctx, "/goospfapi.GoospfApi/VirtualLinkGet", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-type goospfApiClient struct {
-	cc *grpc.ClientConn
+func (c *goospfApiClient) StubRouterGet(ctx context.Context, in *StubRouterGetRequest, opts ...grpc.CallOption) (*StubRouterGetResponse, error) {
+	out := new(StubRouterGetResponse)
+	err := c.cc.Invoke(ctx, "/goospfapi.GoospfApi/StubRouterGet", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func NewGoospfApiClient(cc *grpc.ClientConn) GoospfApiClient {
-	return &goospfApiClient{cc}
+func (c *goospfApiClient) StubRouterEnable(ctx context.Context, in *StubRouterEnableRequest, opts ...grpc.CallOption) (*StubRouterEnableResponse, error) {
+	out := new(StubRouterEnableResponse)
+	err := c.cc.Invoke(ctx, "/goospfapi.GoospfApi/StubRouterEnable", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func (c *goospfApiClient) Enable(ctx context.Context, in *EnableRequest, opts ...grpc.CallOption) (*EnableResponse, error) {
-	out := new(EnableResponse)
-	err := c.cc.Invoke(ctx, "/goospfapi.GoospfApi/Enable", in, out, opts...)
+func (c *goospfApiClient) StubRouterDisable(ctx context.Context, in *StubRouterDisableRequest, opts ...grpc.CallOption) (*StubRouterDisableResponse, error) {
+	out := new(StubRouterDisableResponse)
+	err := c.cc.Invoke(ctx, "/goospfapi.GoospfApi/StubRouterDisable", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *goospfApiClient) Disable(ctx context.Context, in *DisableRequest, opts ...grpc.CallOption) (*DisableResponse, error) {
-	out := new(DisableResponse)
-	err := c.cc.Invoke(ctx, "/goospfapi.GoospfApi/Disable", in, out, opts...)
+func (c *goospfApiClient) DbLsGet(ctx context.Context, in *DbLsGetRequest, opts ...grpc.CallOption) (*DbLsGetResponse, error) {
+	out := new(DbLsGetResponse)
+	err := c.cc.Invoke(ctx, "/goospfapi.GoospfApi/DbLsGet", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goospfApiClient) DbLsMonitor(ctx context.Context, in *DbLsMonitorRequest, opts ...grpc.CallOption) (GoospfApi_DbLsMonitorClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_GoospfApi_serviceDesc.Streams[2], "/goospfapi.GoospfApi/DbLsMonitor", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &goospfApiDbLsMonitorClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type GoospfApi_DbLsMonitorClient interface {
+	Recv() (*DbLsMonitorResponse, error)
+	grpc.ClientStream
+}
+
+type goospfApiDbLsMonitorClient struct {
+	grpc.ClientStream
+}
+
+func (x *goospfApiDbLsMonitorClient) Recv() (*DbLsMonitorResponse, error) {
+	m := new(DbLsMonitorResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *goospfApiClient) DbRiGet(ctx context.Context, in *DbRiGetRequest, opts ...grpc.CallOption) (*DbRiGetResponse, error) {
+	out := new(DbRiGetResponse)
+	err := c.cc.Invoke(ctx, "/goospfapi.GoospfApi/DbRiGet", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
+func (c *goospfApiClient) DbRiMonitor(ctx context.Context, in *DbRiMonitorRequest, opts ...grpc.CallOption) (GoospfApi_DbRiMonitorClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_GoospfApi_serviceDesc.Streams[3], "/goospfapi.GoospfApi/DbRiMonitor", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &goospfApiDbRiMonitorClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type GoospfApi_DbRiMonitorClient interface {
+	Recv() (*DbRiMonitorResponse, error)
+	grpc.ClientStream
+}
+
+type goospfApiDbRiMonitorClient struct {
+	grpc.ClientStream
+}
+
+func (x *goospfApiDbRiMonitorClient) Recv() (*DbRiMonitorResponse, error) {
+	m := new(DbRiMonitorResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // GoospfApiServer is the server API for GoospfApi service.
 type GoospfApiServer interface {
 	Enable(context.Context, *EnableRequest) (*EnableResponse, error)
 	Disable(context.Context, *DisableRequest) (*DisableResponse, error)
+	InterfaceGet(context.Context, *InterfaceGetRequest) (*InterfaceGetResponse, error)
+	InterfaceMonitor(*InterfaceMonitorRequest, GoospfApi_InterfaceMonitorServer) error
+	NeighborGet(context.Context, *NeighborGetRequest) (*NeighborGetResponse, error)
+	NeighborMonitor(*NeighborMonitorRequest, GoospfApi_NeighborMonitorServer) error
+	VirtualLinkGet(context.Context, *VirtualLinkGetRequest) (*VirtualLinkGetResponse, error)
+	StubRouterGet(context.Context, *StubRouterGetRequest) (*StubRouterGetResponse, error)
+	StubRouterEnable(context.Context, *StubRouterEnableRequest) (*StubRouterEnableResponse, error)
+	StubRouterDisable(context.Context, *StubRouterDisableRequest) (*StubRouterDisableResponse, error)
+	DbLsGet(context.Context, *DbLsGetRequest) (*DbLsGetResponse, error)
+	DbLsMonitor(*DbLsMonitorRequest, GoospfApi_DbLsMonitorServer) error
+	DbRiGet(context.Context, *DbRiGetRequest) (*DbRiGetResponse, error)
+	DbRiMonitor(*DbRiMonitorRequest, GoospfApi_DbRiMonitorServer) error
 }
 
 func RegisterGoospfApiServer(s *grpc.Server, srv GoospfApiServer) {
@@ -274,6 +2238,234 @@ func _GoospfApi_Disable_Handler(srv interface{}, ctx context.Context, dec func(i
 	return interceptor(ctx, in, info, handler)
 }
 
+func _GoospfApi_InterfaceGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InterfaceGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoospfApiServer).InterfaceGet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/goospfapi.GoospfApi/InterfaceGet",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoospfApiServer).InterfaceGet(ctx, req.(*InterfaceGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoospfApi_InterfaceMonitor_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(InterfaceMonitorRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GoospfApiServer).InterfaceMonitor(m, &goospfApiInterfaceMonitorServer{stream})
+}
+
+type GoospfApi_InterfaceMonitorServer interface {
+	Send(*InterfaceMonitorResponse) error
+	grpc.ServerStream
+}
+
+type goospfApiInterfaceMonitorServer struct {
+	grpc.ServerStream
+}
+
+func (x *goospfApiInterfaceMonitorServer) Send(m *InterfaceMonitorResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _GoospfApi_NeighborGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NeighborGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoospfApiServer).NeighborGet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/goospfapi.GoospfApi/NeighborGet",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoospfApiServer).NeighborGet(ctx, req.(*NeighborGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoospfApi_NeighborMonitor_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(NeighborMonitorRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GoospfApiServer).NeighborMonitor(m, &goospfApiNeighborMonitorServer{stream})
+}
+
+type GoospfApi_NeighborMonitorServer interface {
+	Send(*NeighborMonitorResponse) error
+	grpc.ServerStream
+}
+
+type goospfApiNeighborMonitorServer struct {
+	grpc.ServerStream
+}
+
+func (x *goospfApiNeighborMonitorServer) Send(m *NeighborMonitorResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _GoospfApi_VirtualLinkGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VirtualLinkGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoospfApiServer).VirtualLinkGet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/goospfapi.GoospfApi/VirtualLinkGet",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoospfApiServer).VirtualLinkGet(ctx, req.(*VirtualLinkGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoospfApi_StubRouterGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StubRouterGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoospfApiServer).StubRouterGet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/goospfapi.GoospfApi/StubRouterGet",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoospfApiServer).StubRouterGet(ctx, req.(*StubRouterGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoospfApi_StubRouterEnable_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StubRouterEnableRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoospfApiServer).StubRouterEnable(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/goospfapi.GoospfApi/StubRouterEnable",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoospfApiServer).StubRouterEnable(ctx, req.(*StubRouterEnableRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoospfApi_StubRouterDisable_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StubRouterDisableRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoospfApiServer).StubRouterDisable(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/goospfapi.GoospfApi/StubRouterDisable",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoospfApiServer).StubRouterDisable(ctx, req.(*StubRouterDisableRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoospfApi_DbLsGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DbLsGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoospfApiServer).DbLsGet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/goospfapi.GoospfApi/DbLsGet",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoospfApiServer).DbLsGet(ctx, req.(*DbLsGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoospfApi_DbLsMonitor_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DbLsMonitorRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GoospfApiServer).DbLsMonitor(m, &goospfApiDbLsMonitorServer{stream})
+}
+
+type GoospfApi_DbLsMonitorServer interface {
+	Send(*DbLsMonitorResponse) error
+	grpc.ServerStream
+}
+
+type goospfApiDbLsMonitorServer struct {
+	grpc.ServerStream
+}
+
+func (x *goospfApiDbLsMonitorServer) Send(m *DbLsMonitorResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _GoospfApi_DbRiGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DbRiGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoospfApiServer).DbRiGet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/goospfapi.GoospfApi/DbRiGet",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoospfApiServer).DbRiGet(ctx, req.(*DbRiGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoospfApi_DbRiMonitor_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DbRiMonitorRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GoospfApiServer).DbRiMonitor(m, &goospfApiDbRiMonitorServer{stream})
+}
+
+type GoospfApi_DbRiMonitorServer interface {
+	Send(*DbRiMonitorResponse) error
+	grpc.ServerStream
+}
+
+type goospfApiDbRiMonitorServer struct {
+	grpc.ServerStream
+}
+
+func (x *goospfApiDbRiMonitorServer) Send(m *DbRiMonitorResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _GoospfApi_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "goospfapi.GoospfApi",
 	HandlerType: (*GoospfApiServer)(nil),
@@ -286,7 +2478,60 @@ var _GoospfApi_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Disable",
 			Handler:    _GoospfApi_Disable_Handler,
 		},
+		{
+			MethodName: "InterfaceGet",
+			Handler:    _GoospfApi_InterfaceGet_Handler,
+		},
+		{
+			MethodName: "NeighborGet",
+			Handler:    _GoospfApi_NeighborGet_Handler,
+		},
+		{
+			MethodName: "VirtualLinkGet",
+			Handler:    _GoospfApi_VirtualLinkGet_Handler,
+		},
+		{
+			MethodName: "StubRouterGet",
+			Handler:    _GoospfApi_StubRouterGet_Handler,
+		},
+		{
+			MethodName: "StubRouterEnable",
+			Handler:    _GoospfApi_StubRouterEnable_Handler,
+		},
+		{
+			MethodName: "StubRouterDisable",
+			Handler:    _GoospfApi_StubRouterDisable_Handler,
+		},
+		{
+			MethodName: "DbLsGet",
+			Handler:    _GoospfApi_DbLsGet_Handler,
+		},
+		{
+			MethodName: "DbRiGet",
+			Handler:    _GoospfApi_DbRiGet_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "InterfaceMonitor",
+			Handler:       _GoospfApi_InterfaceMonitor_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "NeighborMonitor",
+			Handler:       _GoospfApi_NeighborMonitor_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "DbLsMonitor",
+			Handler:       _GoospfApi_DbLsMonitor_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "DbRiMonitor",
+			Handler:       _GoospfApi_DbRiMonitor_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "goospf.proto",
 }
@@ -18,8 +18,10 @@
 package main
 
 import (
+	"encoding/binary"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"os"
 	"os/signal"
 	"sync"
@@ -33,6 +35,8 @@ import (
 
 	api "github.com/m-asama/golsr/api/isis"
 	"github.com/m-asama/golsr/internal/pkg/isis/config"
+	"github.com/m-asama/golsr/internal/pkg/kernel"
+	"github.com/m-asama/golsr/pkg/bgpls"
 	"github.com/m-asama/golsr/pkg/isis/server"
 )
 
@@ -42,16 +46,28 @@ func main() {
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGTERM)
 	var opts struct {
-		ConfigFile    string `short:"f" long:"config-file" description:"specifying a config file"`
-		ConfigType    string `short:"t" long:"config-type" description:"specifying config type (toml, yaml, json)" default:"toml"`
-		LogLevel      string `short:"l" long:"log-level" description:"specifying log level"`
-		LogPlain      bool   `short:"p" long:"log-plain" description:"use plain format for logging (json by default)"`
-		UseSyslog     string `short:"s" long:"syslog" description:"use syslogd"`
-		Facility      string `long:"syslog-facility" description:"specify syslog facility"`
-		DisableStdlog bool   `long:"disable-stdlog" description:"disable standard logging"`
-		GrpcHosts     string `long:"api-hosts" description:"specify the hosts that goisisd listens on" default:":50052"`
-		Dry           bool   `short:"d" long:"dry-run" description:"check configuration"`
-		Version       bool   `long:"version" description:"show version number"`
+		ConfigFile         string `short:"f" long:"config-file" description:"specifying a config file"`
+		ConfigType         string `short:"t" long:"config-type" description:"specifying config type (toml, yaml, json)" default:"toml"`
+		LogLevel           string `short:"l" long:"log-level" description:"specifying log level"`
+		LogPlain           bool   `short:"p" long:"log-plain" description:"use plain format for logging (json by default)"`
+		UseSyslog          string `short:"s" long:"syslog" description:"use syslogd"`
+		Facility           string `long:"syslog-facility" description:"specify syslog facility"`
+		DisableStdlog      bool   `long:"disable-stdlog" description:"disable standard logging"`
+		GrpcHosts          string `long:"api-hosts" description:"specify the hosts that goisisd listens on" default:":50052"`
+		TracePcapFile      string `long:"trace-pcap-file" description:"enable trace and write traced frames to a pcap file"`
+		LsdbExportFile     string `long:"lsdb-export-file" description:"export the LSDB to a file periodically"`
+		LsdbExportInterval int    `long:"lsdb-export-interval" description:"LSDB export interval in seconds" default:"60"`
+		BgplsPeer          string `long:"bgpls-peer" description:"advertise the LSDB to a BGP-LS peer (address[:port])"`
+		BgplsLocalAs       uint32 `long:"bgpls-local-as" description:"BGP-LS local AS number"`
+		BgplsPeerAs        uint32 `long:"bgpls-peer-as" description:"BGP-LS peer AS number (local AS by default)"`
+		BgplsRouterId      string `long:"bgpls-router-id" description:"BGP-LS router id and next hop"`
+		BgplsId            uint32 `long:"bgpls-id" description:"BGP-LS identifier (router id by default)"`
+		EnableFib          bool   `long:"enable-fib" description:"install routes into the kernel"`
+		Netns              string `long:"netns" description:"run in the named network namespace"`
+		Vrf                string `long:"vrf" description:"use the interfaces of and install routes into the VRF"`
+		Instance           string `long:"instance" description:"instance the trace, LSDB export and BGP-LS options apply to" default:"default"`
+		Dry                bool   `short:"d" long:"dry-run" description:"check configuration"`
+		Version            bool   `long:"version" description:"show version number"`
 	}
 	_, err := flags.Parse(&opts)
 	if err != nil {
@@ -89,10 +105,22 @@ func main() {
 		log.SetFormatter(&log.JSONFormatter{})
 	}
 
+	if opts.Netns != "" {
+		provider, err := kernel.NewNetnsProvider(opts.Netns)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"Topic": "Kernel",
+				"Key":   opts.Netns,
+				"Error": err,
+			}).Fatal("netns setup failed")
+		}
+		kernel.SetDefaultProvider(provider)
+	}
+
 	if opts.Dry {
-		configCh := make(chan *config.IsisConfig)
-		go config.Serve(opts.ConfigFile, opts.ConfigType, configCh)
-		c := <-configCh
+		instancesCh := make(chan []*config.Instance)
+		go config.ServeInstances(opts.ConfigFile, opts.ConfigType, instancesCh)
+		c := <-instancesCh
 		if opts.LogLevel == "debug" {
 			pretty.Println(c)
 		}
@@ -103,21 +131,76 @@ func main() {
 
 	log.Info("goisisd started")
 
-	isisServer := server.NewIsisServer(opts.ConfigFile, opts.ConfigType)
+	instances := server.NewIsisInstances(opts.ConfigFile, opts.ConfigType)
+	instances.SetVrf(opts.Vrf)
+	instances.SetFib(opts.EnableFib)
+	instances.SetSetup(func(name string, isisServer *server.IsisServer) error {
+		if opts.Netns != "" {
+			isisServer.SetTransport(server.NewNetnsPacketTransport(opts.Netns))
+		}
+		if name != opts.Instance {
+			return nil
+		}
+		if opts.TracePcapFile != "" {
+			err := isisServer.SetTracePcapFile(opts.TracePcapFile)
+			if err != nil {
+				log.WithFields(log.Fields{
+					"Topic": "Trace",
+					"Key":   opts.TracePcapFile,
+					"Error": err,
+				}).Warn("pcap file open failed")
+				return err
+			}
+		}
+		if opts.LsdbExportFile != "" {
+			err := isisServer.SetLsdbExport(opts.LsdbExportFile, opts.LsdbExportInterval)
+			if err != nil {
+				log.WithFields(log.Fields{
+					"Topic": "Export",
+					"Key":   opts.LsdbExportFile,
+					"Error": err,
+				}).Warn("lsdb export setup failed")
+				return err
+			}
+		}
+		if opts.BgplsPeer != "" {
+			var routerId uint32
+			if ip := net.ParseIP(opts.BgplsRouterId).To4(); ip != nil {
+				routerId = binary.BigEndian.Uint32(ip)
+			}
+			err := isisServer.SetBgpls(&bgpls.SpeakerConfig{
+				PeerAddress: opts.BgplsPeer,
+				LocalAs:     opts.BgplsLocalAs,
+				PeerAs:      opts.BgplsPeerAs,
+				RouterId:    routerId,
+			}, opts.BgplsId)
+			if err != nil {
+				log.WithFields(log.Fields{
+					"Topic": "BgpLs",
+					"Key":   opts.BgplsPeer,
+					"Error": err,
+				}).Warn("bgp-ls setup failed")
+				return err
+			}
+		}
+		return nil
+	})
 	wg.Add(1)
-	go isisServer.Serve(&wg)
+	go instances.Serve(&wg)
 
 	var grpcOpts []grpc.ServerOption
-	apiServer := server.NewApiServer(isisServer, grpc.NewServer(grpcOpts...), opts.GrpcHosts)
+	apiServer := server.NewInstancesApiServer(instances, grpc.NewServer(grpcOpts...), opts.GrpcHosts)
 	wg.Add(1)
 	go apiServer.Serve(&wg)
 
 	<-sigCh
 
 	log.Info("goisisd stoping")
-	apiServer.Disable(context.Background(), &api.DisableRequest{})
+	for _, name := range instances.Names() {
+		apiServer.Disable(context.Background(), &api.DisableRequest{Instance: name})
+	}
 	apiServer.Exit()
-	isisServer.Exit()
+	instances.Exit()
 
 	wg.Wait()
 	log.Info("goisisd terminated")
@@ -0,0 +1,198 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/jessevdk/go-flags"
+	"github.com/kr/pretty"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+
+	isisapi "github.com/m-asama/golsr/api/isis"
+	ospfapi "github.com/m-asama/golsr/api/ospf"
+	isisconfig "github.com/m-asama/golsr/internal/pkg/isis/config"
+	"github.com/m-asama/golsr/internal/pkg/kernel"
+	ospfconfig "github.com/m-asama/golsr/internal/pkg/ospf/config"
+	isisserver "github.com/m-asama/golsr/pkg/isis/server"
+	ospfserver "github.com/m-asama/golsr/pkg/ospf/server"
+)
+
+var version = "master"
+
+func main() {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGTERM)
+	var opts struct {
+		IsisConfigFile string `long:"isis-config-file" description:"specifying an IS-IS config file"`
+		OspfConfigFile string `long:"ospf-config-file" description:"specifying an OSPF config file"`
+		ConfigType     string `short:"t" long:"config-type" description:"specifying config type (toml, yaml, json)" default:"toml"`
+		LogLevel       string `short:"l" long:"log-level" description:"specifying log level"`
+		LogPlain       bool   `short:"p" long:"log-plain" description:"use plain format for logging (json by default)"`
+		UseSyslog      string `short:"s" long:"syslog" description:"use syslogd"`
+		Facility       string `long:"syslog-facility" description:"specify syslog facility"`
+		DisableStdlog  bool   `long:"disable-stdlog" description:"disable standard logging"`
+		GrpcHosts      string `long:"api-hosts" description:"specify the hosts that golsrd listens on" default:":50052"`
+		EnableFib      bool   `long:"enable-fib" description:"install routes into the kernel"`
+		Dry            bool   `short:"d" long:"dry-run" description:"check configuration"`
+		Version        bool   `long:"version" description:"show version number"`
+	}
+	_, err := flags.Parse(&opts)
+	if err != nil {
+		os.Exit(1)
+	}
+
+	if opts.Version {
+		fmt.Println("golsrd version", version)
+		os.Exit(0)
+	}
+
+	switch opts.LogLevel {
+	case "debug":
+		log.SetLevel(log.DebugLevel)
+		log.SetReportCaller(true)
+	case "info":
+		log.SetLevel(log.InfoLevel)
+	default:
+		log.SetLevel(log.InfoLevel)
+	}
+
+	if opts.DisableStdlog {
+		log.SetOutput(ioutil.Discard)
+	} else {
+		log.SetOutput(os.Stdout)
+	}
+
+	if opts.LogPlain {
+		if opts.DisableStdlog {
+			log.SetFormatter(&log.TextFormatter{
+				DisableColors: true,
+			})
+		}
+	} else {
+		log.SetFormatter(&log.JSONFormatter{})
+	}
+
+	if opts.Dry {
+		if opts.IsisConfigFile != "" {
+			instancesCh := make(chan []*isisconfig.Instance)
+			go isisconfig.ServeInstances(opts.IsisConfigFile, opts.ConfigType, instancesCh)
+			c := <-instancesCh
+			if opts.LogLevel == "debug" {
+				pretty.Println(c)
+			}
+		}
+		if opts.OspfConfigFile != "" {
+			configCh := make(chan *ospfconfig.OspfConfig)
+			go ospfconfig.Serve(opts.OspfConfigFile, opts.ConfigType, configCh)
+			c := <-configCh
+			if opts.LogLevel == "debug" {
+				pretty.Println(c)
+			}
+		}
+		os.Exit(0)
+	}
+
+	var wg sync.WaitGroup
+
+	log.Info("golsrd started")
+
+	// Both protocols share one watch of the kernel and one FIB, in which
+	// the route of the lower preference wins.
+	manager := kernel.NewManager(kernel.DefaultProvider())
+	kernel.SetDefaultProvider(manager)
+	managerDoneCh := make(chan struct{})
+	go manager.Serve(managerDoneCh)
+
+	var grpcOpts []grpc.ServerOption
+	grpcServer := grpc.NewServer(grpcOpts...)
+
+	var isisInstances *isisserver.IsisInstances
+	var isisApiServer *isisserver.ApiServer
+	if opts.IsisConfigFile != "" {
+		isisInstances = isisserver.NewIsisInstances(opts.IsisConfigFile, opts.ConfigType)
+		isisInstances.SetKernelProvider(manager)
+		isisInstances.SetSetup(func(name string, isisServer *isisserver.IsisServer) error {
+			// each instance owns the routes it adds to the manager
+			isisServer.SetKernelProvider(manager.NewClient())
+			if opts.EnableFib {
+				isisServer.SetFib(true)
+			}
+			return nil
+		})
+		wg.Add(1)
+		go isisInstances.Serve(&wg)
+		isisApiServer = isisserver.NewInstancesApiServer(isisInstances, grpcServer, opts.GrpcHosts)
+	}
+
+	var ospfServer *ospfserver.OspfServer
+	var ospfApiServer *ospfserver.ApiServer
+	if opts.OspfConfigFile != "" {
+		ospfServer = ospfserver.NewOspfServer(opts.OspfConfigFile, opts.ConfigType)
+		ospfServer.SetKernelProvider(manager.NewClient())
+		if opts.EnableFib {
+			ospfServer.SetFib(true)
+		}
+		wg.Add(1)
+		go ospfServer.Serve(&wg)
+		ospfApiServer = ospfserver.NewApiServer(ospfServer, grpcServer, opts.GrpcHosts)
+	}
+
+	// The services are registered on the same grpc server, which only
+	// one of them has to serve.
+	switch {
+	case isisApiServer != nil:
+		wg.Add(1)
+		go isisApiServer.Serve(&wg)
+	case ospfApiServer != nil:
+		wg.Add(1)
+		go ospfApiServer.Serve(&wg)
+	default:
+		log.Warn("neither IS-IS nor OSPF config file specified")
+	}
+
+	<-sigCh
+
+	log.Info("golsrd stoping")
+	if isisApiServer != nil {
+		for _, name := range isisInstances.Names() {
+			isisApiServer.Disable(context.Background(), &isisapi.DisableRequest{Instance: name})
+		}
+	}
+	if ospfApiServer != nil {
+		ospfApiServer.Disable(context.Background(), &ospfapi.DisableRequest{})
+	}
+	grpcServer.Stop()
+	if isisInstances != nil {
+		isisInstances.Exit()
+	}
+	if ospfServer != nil {
+		ospfServer.Exit()
+	}
+
+	wg.Wait()
+	close(managerDoneCh)
+	log.Info("golsrd terminated")
+}
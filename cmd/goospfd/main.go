@@ -50,6 +50,7 @@ func main() {
 		Facility      string `long:"syslog-facility" description:"specify syslog facility"`
 		DisableStdlog bool   `long:"disable-stdlog" description:"disable standard logging"`
 		GrpcHosts     string `long:"api-hosts" description:"specify the hosts that goospfd listens on" default:":50052"`
+		EnableFib     bool   `long:"enable-fib" description:"install routes into the kernel"`
 		Dry           bool   `short:"d" long:"dry-run" description:"check configuration"`
 		Version       bool   `long:"version" description:"show version number"`
 	}
@@ -104,6 +105,9 @@ func main() {
 	log.Info("goospfd started")
 
 	ospfServer := server.NewOspfServer(opts.ConfigFile, opts.ConfigType)
+	if opts.EnableFib {
+		ospfServer.SetFib(true)
+	}
 	wg.Add(1)
 	go ospfServer.Serve(&wg)
 
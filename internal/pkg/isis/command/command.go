@@ -35,6 +35,7 @@ import (
 var globalOpts struct {
 	Host         string
 	Port         int
+	Instance     string
 	Debug        bool
 	Quiet        bool
 	Json         bool
@@ -104,6 +105,8 @@ func NewRootCmd() *cobra.Command {
 
 	rootCmd.PersistentFlags().StringVarP(&globalOpts.Host, "host", "u", "127.0.0.1", "host")
 	rootCmd.PersistentFlags().IntVarP(&globalOpts.Port, "port", "p", 50052, "port")
+	rootCmd.PersistentFlags().StringVarP(&globalOpts.Instance, "instance", "", "",
+		"instance name (required if goisisd runs several)")
 	rootCmd.PersistentFlags().BoolVarP(&globalOpts.Json, "json", "j", false, "use json format to output format")
 	rootCmd.PersistentFlags().BoolVarP(&globalOpts.Debug, "debug", "d", false, "use debug")
 	rootCmd.PersistentFlags().BoolVarP(&globalOpts.Quiet, "quiet", "q", false, "use quiet")
@@ -114,7 +117,7 @@ func NewRootCmd() *cobra.Command {
 	enableCmd := &cobra.Command{
 		Use: "enable",
 		Run: func(cmd *cobra.Command, args []string) {
-			response, _ := client.Enable(ctx, &api.EnableRequest{})
+			response, _ := client.Enable(ctx, &api.EnableRequest{Instance: globalOpts.Instance})
 			fmt.Println(response.Result)
 		},
 	}
@@ -123,12 +126,15 @@ func NewRootCmd() *cobra.Command {
 	disableCmd := &cobra.Command{
 		Use: "disable",
 		Run: func(cmd *cobra.Command, args []string) {
-			response, _ := client.Disable(ctx, &api.DisableRequest{})
+			response, _ := client.Disable(ctx, &api.DisableRequest{Instance: globalOpts.Instance})
 			fmt.Println(response.Result)
 		},
 	}
 	rootCmd.AddCommand(disableCmd)
 
+	instanceCmd := NewInstanceCmd()
+	rootCmd.AddCommand(instanceCmd)
+
 	interfaceCmd := NewInterfaceCmd()
 	rootCmd.AddCommand(interfaceCmd)
 
@@ -138,5 +144,20 @@ func NewRootCmd() *cobra.Command {
 	routeCmd := NewRouteCmd()
 	rootCmd.AddCommand(routeCmd)
 
+	topologyCmd := NewTopologyCmd()
+	rootCmd.AddCommand(topologyCmd)
+
+	traceCmd := NewTraceCmd()
+	rootCmd.AddCommand(traceCmd)
+
+	policyCmd := NewPolicyCmd()
+	rootCmd.AddCommand(policyCmd)
+
+	decodeCmd := NewDecodeCmd()
+	rootCmd.AddCommand(decodeCmd)
+
+	simulateCmd := NewSimulateCmd()
+	rootCmd.AddCommand(simulateCmd)
+
 	return rootCmd
 }
@@ -18,8 +18,11 @@
 package command
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"os"
 
 	"github.com/spf13/cobra"
 
@@ -49,24 +52,72 @@ func NewDbLinkstateCmd() *cobra.Command {
 				return
 			}
 			stream, _ := client.DbLsMonitor(ctx, &api.DbLsMonitorRequest{
-				Level: args[0],
+				Level:    args[0],
+				Instance: globalOpts.Instance,
 			})
+			lsps := make([]*api.Lsp, 0)
 			for {
 				r, err := stream.Recv()
 				if err == io.EOF {
 					break
 				} else if err != nil {
-					return
+					exitWithError(err)
+				}
+				if globalOpts.Json {
+					lsps = append(lsps, r.Lsps...)
+					continue
 				}
 				for _, lsp := range r.Lsps {
 					printLsp(lsp)
 				}
 			}
+			if globalOpts.Json {
+				printJson(lsps)
+			}
 		},
 	}
 	return dbLinkstateCmd
 }
 
+var dbExportOpts struct {
+	Output string
+}
+
+func NewDbExportCmd() *cobra.Command {
+	dbExportCmd := &cobra.Command{
+		Use:   "export [<level>]",
+		Short: "export the LSDB (all levels by default) as JSON",
+		Run: func(cmd *cobra.Command, args []string) {
+			level := "all"
+			if len(args) == 1 {
+				level = args[0]
+			}
+			response, err := client.DbLsExport(ctx, &api.DbLsExportRequest{
+				Level:    level,
+				Instance: globalOpts.Instance,
+			})
+			if err != nil {
+				exitWithError(err)
+			}
+			if response.Result != "" {
+				exitWithError(errors.New(response.Result))
+			}
+			if dbExportOpts.Output == "" {
+				os.Stdout.Write(response.Data)
+				fmt.Println()
+				return
+			}
+			err = ioutil.WriteFile(dbExportOpts.Output, response.Data, 0644)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+	dbExportCmd.Flags().StringVarP(&dbExportOpts.Output, "output", "o", "",
+		"file to write the export to (stdout by default)")
+	return dbExportCmd
+}
+
 func NewDatabaseCmd() *cobra.Command {
 	databaseCmd := &cobra.Command{
 		Use: "database",
@@ -77,5 +128,8 @@ func NewDatabaseCmd() *cobra.Command {
 	dbLinkstateCmd := NewDbLinkstateCmd()
 	databaseCmd.AddCommand(dbLinkstateCmd)
 
+	dbExportCmd := NewDbExportCmd()
+	databaseCmd.AddCommand(dbExportCmd)
+
 	return databaseCmd
 }
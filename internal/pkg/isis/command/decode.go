@@ -0,0 +1,236 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	api "github.com/m-asama/golsr/api/isis"
+	"github.com/m-asama/golsr/internal/pkg/isis/config"
+	"github.com/m-asama/golsr/internal/pkg/pcap"
+	"github.com/m-asama/golsr/pkg/isis/packet"
+	"github.com/m-asama/golsr/pkg/isis/server"
+)
+
+var decodeOpts struct {
+	Lsdb bool
+	Root string
+}
+
+type decodedPdu struct {
+	Frame       int      `json:"frame"`
+	Time        string   `json:"time"`
+	Source      string   `json:"source"`
+	Destination string   `json:"destination"`
+	PduType     string   `json:"pdu_type"`
+	Lsp         *api.Lsp `json:"lsp,omitempty"`
+}
+
+func parseSystemId(systemId string) ([packet.SYSTEM_ID_LENGTH]byte, error) {
+	validSystemId := regexp.MustCompile(`^[0-9A-Fa-f]{4}\.[0-9A-Fa-f]{4}\.[0-9A-Fa-f]{4}$`)
+	if !validSystemId.MatchString(systemId) {
+		return [packet.SYSTEM_ID_LENGTH]byte{}, errors.New("system-id invalid: " + systemId)
+	}
+	return config.ParseSystemId(systemId), nil
+}
+
+// isisFrame strips the link layer and LLC headers and returns the IS-IS
+// PDU with its source and destination addresses if the frame carries one.
+func isisFrame(linkType uint32, data []byte) ([]byte, []byte, []byte, bool) {
+	var src, dst, payload []byte
+	switch linkType {
+	case pcap.LINKTYPE_ETHERNET:
+		if len(data) < 14 {
+			return nil, nil, nil, false
+		}
+		dst = data[0:6]
+		src = data[6:12]
+		offset := 12
+		if binary.BigEndian.Uint16(data[offset:offset+2]) == 0x8100 {
+			offset += 4
+			if len(data) < offset+2 {
+				return nil, nil, nil, false
+			}
+		}
+		length := int(binary.BigEndian.Uint16(data[offset : offset+2]))
+		offset += 2
+		if length > 1500 {
+			return nil, nil, nil, false
+		}
+		if offset+length < len(data) {
+			data = data[0 : offset+length]
+		}
+		payload = data[offset:]
+	case pcap.LINKTYPE_LINUX_SLL:
+		if len(data) < 16 {
+			return nil, nil, nil, false
+		}
+		// 0x0004 is ETH_P_802_2
+		if binary.BigEndian.Uint16(data[14:16]) != 0x0004 {
+			return nil, nil, nil, false
+		}
+		src = data[6:12]
+		dst = make([]byte, 6)
+		payload = data[16:]
+	default:
+		return nil, nil, nil, false
+	}
+	if len(payload) < len(packet.Llc) || !bytes.Equal(payload[0:len(packet.Llc)], packet.Llc) {
+		return nil, nil, nil, false
+	}
+	return payload[len(packet.Llc):], src, dst, true
+}
+
+func printJson(v interface{}) {
+	j, _ := json.Marshal(v)
+	fmt.Println(string(j))
+}
+
+func printRoutes(routes []*api.Route) {
+	if globalOpts.Json {
+		printJson(routes)
+		return
+	}
+	fmt.Printf("LV %-30s %5s %-8s %-30s\n", "PREFIX", "DIST", "I/F", "NEXTHOP")
+	for _, route := range routes {
+		printRoute(route)
+	}
+}
+
+func printDecodedPdu(frame int, t time.Time, src, dst []byte, pdu packet.IsisPdu) {
+	if globalOpts.Json {
+		d := &decodedPdu{
+			Frame:       frame,
+			Time:        t.Format(time.RFC3339Nano),
+			Source:      fmt.Sprintf("%x", src),
+			Destination: fmt.Sprintf("%x", dst),
+			PduType:     pdu.PduType().String(),
+		}
+		if lsp, ok := pdu.(*packet.LsPdu); ok {
+			d.Lsp = server.NewApiLsp(lsp)
+		}
+		printJson(d)
+		return
+	}
+	fmt.Printf("Frame %d %s %x > %x\n", frame, t.Format(time.RFC3339Nano), src, dst)
+	fmt.Printf("%s\n", pdu.String())
+}
+
+func decode(filename string) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	r, err := pcap.NewReader(f)
+	if err != nil {
+		return err
+	}
+
+	printPdus := !decodeOpts.Lsdb && decodeOpts.Root == ""
+	db := server.NewOfflineLsdb()
+	if decodeOpts.Root != "" {
+		systemId, err := parseSystemId(decodeOpts.Root)
+		if err != nil {
+			return err
+		}
+		db.SetSystemId(systemId)
+	}
+
+	for frame := 1; ; frame++ {
+		t, data, err := r.ReadPacket()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		payload, src, dst, ok := isisFrame(r.LinkType(), data)
+		if !ok {
+			continue
+		}
+		pdu, err := packet.DecodePduFromBytes(payload)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Frame %d: %s\n", frame, err)
+			continue
+		}
+		if printPdus {
+			printDecodedPdu(frame, t, src, dst, pdu)
+		}
+		if lsp, ok := pdu.(*packet.LsPdu); ok {
+			db.AddLsp(lsp)
+		}
+	}
+
+	if decodeOpts.Lsdb {
+		lsps := make([]*api.Lsp, 0)
+		for _, level := range server.ISIS_LEVEL_ALL {
+			lsps = append(lsps, db.ApiLsps(level)...)
+		}
+		if globalOpts.Json {
+			printJson(lsps)
+		} else {
+			for _, lsp := range lsps {
+				printLsp(lsp)
+			}
+		}
+	}
+
+	if decodeOpts.Root != "" {
+		routes := make([]*api.Route, 0)
+		for _, level := range server.ISIS_LEVEL_ALL {
+			routes = append(routes, db.Spf(level)...)
+		}
+		printRoutes(routes)
+	}
+
+	return nil
+}
+
+func NewDecodeCmd() *cobra.Command {
+	decodeCmd := &cobra.Command{
+		Use:   "decode <file.pcap>",
+		Short: "decode IS-IS PDUs in a pcap file without goisisd",
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 1 {
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
+			err := decode(args[0])
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+	decodeCmd.Flags().BoolVarP(&decodeOpts.Lsdb, "lsdb", "l", false,
+		"print the LSDB reconstructed from the LSPs in the capture")
+	decodeCmd.Flags().StringVarP(&decodeOpts.Root, "root", "r", "",
+		"run SPF on the reconstructed LSDB from this system-id (xxxx.xxxx.xxxx)")
+	return decodeCmd
+}
@@ -0,0 +1,50 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	api "github.com/m-asama/golsr/api/isis"
+)
+
+func NewInstanceCmd() *cobra.Command {
+	instanceCmd := &cobra.Command{
+		Use: "instance",
+		Run: func(cmd *cobra.Command, args []string) {
+			response, err := client.InstanceList(ctx, &api.InstanceListRequest{})
+			if err != nil {
+				exitWithError(err)
+			}
+			if globalOpts.Json {
+				printJson(response.Instances)
+				return
+			}
+			fmt.Printf("%-16s %-14s %-20s %s\n", "NAME", "SYSTEM-ID", "AREA", "INTERFACES")
+			for _, instance := range response.Instances {
+				fmt.Printf("%-16s %-14s %-20s %s\n", instance.Name, instance.SystemId,
+					strings.Join(instance.AreaAddresses, ","),
+					strings.Join(instance.Interfaces, ","))
+			}
+		},
+	}
+	return instanceCmd
+}
@@ -47,7 +47,7 @@ func NewIfEnableCmd() *cobra.Command {
 			if len(args) != 1 {
 				return
 			}
-			request := &api.InterfaceEnableRequest{Interface: args[0]}
+			request := &api.InterfaceEnableRequest{Interface: args[0], Instance: globalOpts.Instance}
 			response, _ := client.InterfaceEnable(ctx, request)
 			fmt.Println(response.Result)
 		},
@@ -62,7 +62,7 @@ func NewIfDisableCmd() *cobra.Command {
 			if len(args) != 1 {
 				return
 			}
-			request := &api.InterfaceDisableRequest{Interface: args[0]}
+			request := &api.InterfaceDisableRequest{Interface: args[0], Instance: globalOpts.Instance}
 			response, _ := client.InterfaceDisable(ctx, request)
 			fmt.Println(response.Result)
 		},
@@ -80,13 +80,14 @@ func NewIfAdjacencyCmd() *cobra.Command {
 			}
 			stream, _ := client.AdjacencyMonitor(ctx, &api.AdjacencyMonitorRequest{
 				Interface: ifname,
+				Instance:  globalOpts.Instance,
 			})
 			for {
 				r, err := stream.Recv()
 				if err == io.EOF {
 					break
 				} else if err != nil {
-					return
+					exitWithError(err)
 				}
 				for _, adj := range r.Adjacencies {
 					printAdjacency(adj)
@@ -0,0 +1,243 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	api "github.com/m-asama/golsr/api/isis"
+	"github.com/m-asama/golsr/internal/pkg/isis/config"
+	"github.com/m-asama/golsr/pkg/isis/server"
+)
+
+var policyOpts struct {
+	ConfigType           string
+	InstallPolicies      []string
+	DefaultInstallPolicy string
+	LeakPolicies         []string
+}
+
+func printStatement(stmt *api.Statement) {
+	fmt.Printf("  statement %s\n", stmt.Name)
+	if c := stmt.Conditions; c != nil {
+		if c.MatchPrefixSet != "" {
+			fmt.Printf("    match-prefix-set %s %s\n", c.MatchPrefixSet, c.MatchPrefixSetOptions)
+		}
+		if c.MatchTagSet != "" {
+			fmt.Printf("    match-tag-set %s %s\n", c.MatchTagSet, c.MatchTagSetOptions)
+		}
+		if c.Level != "" {
+			fmt.Printf("    level %s\n", c.Level)
+		}
+		if c.RouteType != "" {
+			fmt.Printf("    route-type %s\n", c.RouteType)
+		}
+	}
+	if a := stmt.Actions; a != nil {
+		if a.SetMetric != nil {
+			fmt.Printf("    set-metric %d\n", a.SetMetric.Value)
+		}
+		if a.SetTag != nil {
+			fmt.Printf("    set-tag %d\n", a.SetTag.Value)
+		}
+		if a.SetUpDown != nil {
+			fmt.Printf("    set-up-down %t\n", a.SetUpDown.Value)
+		}
+		fmt.Printf("    %s\n", a.RouteDisposition)
+	}
+}
+
+func printPolicies(response *api.PolicyGetResponse) {
+	for _, ps := range response.PrefixSets {
+		fmt.Printf("prefix-set %s\n", ps.Name)
+		for _, prefix := range ps.Prefixes {
+			fmt.Printf("  %s %s\n", prefix.IpPrefix, prefix.MasklengthRange)
+		}
+	}
+	for _, ts := range response.TagSets {
+		tags := make([]string, 0)
+		for _, tag := range ts.Tags {
+			tags = append(tags, fmt.Sprintf("%d", tag))
+		}
+		fmt.Printf("tag-set %s\n", ts.Name)
+		fmt.Printf("  %s\n", strings.Join(tags, ","))
+	}
+	for _, policy := range response.Policies {
+		fmt.Printf("policy %s\n", policy.Name)
+		for _, stmt := range policy.Statements {
+			printStatement(stmt)
+		}
+	}
+	if assignment := response.Assignment; assignment != nil {
+		fmt.Printf("install-policy-list     : %s\n", strings.Join(assignment.InstallPolicies, ","))
+		fmt.Printf("default-install-policy  : %s\n", assignment.DefaultInstallPolicy)
+		fmt.Printf("leak-policy-list        : %s\n", strings.Join(assignment.LeakPolicies, ","))
+	}
+}
+
+func NewPolicyAddCmd() *cobra.Command {
+	policyAddCmd := &cobra.Command{
+		Use:  "add <file>",
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			data, err := ioutil.ReadFile(args[0])
+			if err != nil {
+				exitWithError(err)
+			}
+			c, err := config.Parse(data, policyOpts.ConfigType)
+			if err != nil {
+				exitWithError(err)
+			}
+			if len(c.DefinedSets.PrefixSets) > 0 || len(c.DefinedSets.TagSets) > 0 {
+				request := &api.DefinedSetAddRequest{
+					Instance: globalOpts.Instance,
+				}
+				for _, ps := range c.DefinedSets.PrefixSets {
+					request.PrefixSets = append(request.PrefixSets, server.NewApiPrefixSet(ps))
+				}
+				for _, ts := range c.DefinedSets.TagSets {
+					request.TagSets = append(request.TagSets, server.NewApiTagSet(ts))
+				}
+				response, err := client.DefinedSetAdd(ctx, request)
+				if err != nil {
+					exitWithError(err)
+				}
+				fmt.Println(response.Result)
+			}
+			if len(c.PolicyDefinitions) > 0 {
+				request := &api.PolicyAddRequest{
+					Instance: globalOpts.Instance,
+				}
+				for _, pd := range c.PolicyDefinitions {
+					request.Policies = append(request.Policies, server.NewApiPolicy(pd))
+				}
+				response, err := client.PolicyAdd(ctx, request)
+				if err != nil {
+					exitWithError(err)
+				}
+				fmt.Println(response.Result)
+			}
+		},
+	}
+	policyAddCmd.Flags().StringVarP(&policyOpts.ConfigType, "config-type", "t", "toml",
+		"file format: toml, yaml or json")
+	return policyAddCmd
+}
+
+func NewPolicyDeleteCmd() *cobra.Command {
+	policyDeleteCmd := &cobra.Command{
+		Use:       "delete {prefix-set|tag-set|policy} <name>...",
+		Args:      cobra.MinimumNArgs(2),
+		ValidArgs: []string{"prefix-set", "tag-set", "policy"},
+		Run: func(cmd *cobra.Command, args []string) {
+			var result string
+			switch args[0] {
+			case "prefix-set", "tag-set":
+				request := &api.DefinedSetDeleteRequest{
+					Instance: globalOpts.Instance,
+				}
+				if args[0] == "prefix-set" {
+					request.PrefixSets = args[1:]
+				} else {
+					request.TagSets = args[1:]
+				}
+				response, err := client.DefinedSetDelete(ctx, request)
+				if err != nil {
+					exitWithError(err)
+				}
+				result = response.Result
+			case "policy":
+				response, err := client.PolicyDelete(ctx, &api.PolicyDeleteRequest{
+					Policies: args[1:],
+					Instance: globalOpts.Instance,
+				})
+				if err != nil {
+					exitWithError(err)
+				}
+				result = response.Result
+			default:
+				exitWithError(fmt.Errorf("unknown kind %s", args[0]))
+			}
+			fmt.Println(result)
+		},
+	}
+	return policyDeleteCmd
+}
+
+func NewPolicyAssignmentCmd() *cobra.Command {
+	policyAssignmentCmd := &cobra.Command{
+		Use: "assignment",
+		Run: func(cmd *cobra.Command, args []string) {
+			response, err := client.PolicyAssignmentSet(ctx, &api.PolicyAssignmentSetRequest{
+				Assignment: &api.PolicyAssignment{
+					InstallPolicies:      policyOpts.InstallPolicies,
+					DefaultInstallPolicy: policyOpts.DefaultInstallPolicy,
+					LeakPolicies:         policyOpts.LeakPolicies,
+				},
+				Instance: globalOpts.Instance,
+			})
+			if err != nil {
+				exitWithError(err)
+			}
+			fmt.Println(response.Result)
+		},
+	}
+	policyAssignmentCmd.Flags().StringSliceVarP(&policyOpts.InstallPolicies, "install", "i", nil,
+		"policies applied to the routes installed into the kernel")
+	policyAssignmentCmd.Flags().StringVar(&policyOpts.DefaultInstallPolicy, "default-install", "",
+		"accept-route or reject-route for the routes no install policy decides (accept-route by default)")
+	policyAssignmentCmd.Flags().StringSliceVarP(&policyOpts.LeakPolicies, "leak", "l", nil,
+		"policies applied to the routes leaked between the levels")
+	return policyAssignmentCmd
+}
+
+func NewPolicyCmd() *cobra.Command {
+	policyCmd := &cobra.Command{
+		Use: "policy",
+		Run: func(cmd *cobra.Command, args []string) {
+			response, err := client.PolicyGet(ctx, &api.PolicyGetRequest{Instance: globalOpts.Instance})
+			if err != nil {
+				exitWithError(err)
+			}
+			if response.Result != "" {
+				fmt.Println(response.Result)
+				return
+			}
+			if globalOpts.Json {
+				printJson(response)
+				return
+			}
+			printPolicies(response)
+		},
+	}
+
+	policyAddCmd := NewPolicyAddCmd()
+	policyCmd.AddCommand(policyAddCmd)
+
+	policyDeleteCmd := NewPolicyDeleteCmd()
+	policyCmd.AddCommand(policyDeleteCmd)
+
+	policyAssignmentCmd := NewPolicyAssignmentCmd()
+	policyCmd.AddCommand(policyAssignmentCmd)
+
+	return policyCmd
+}
@@ -28,6 +28,11 @@ import (
 
 func printRoute(route *api.Route) {
 	//fmt.Printf("Level             : %s\n", route.Level)
+	if route.Active {
+		fmt.Printf("*")
+	} else {
+		fmt.Printf(" ")
+	}
 	switch route.Level {
 	case "level-1":
 		fmt.Printf("L1 ")
@@ -36,12 +41,18 @@ func printRoute(route *api.Route) {
 	default:
 		fmt.Printf("L? ")
 	}
+	if route.External {
+		fmt.Printf("E ")
+	} else {
+		fmt.Printf("I ")
+	}
 	fmt.Printf("%-30s ", route.Prefix)
+	fmt.Printf("%4d ", route.Preference)
 	fmt.Printf("%5d ", route.Metric)
 	first := true
 	for _, nh := range route.NextHops {
 		if !first {
-			fmt.Printf("                                        ")
+			fmt.Printf("                                                ")
 		}
 		fmt.Printf("%-8s %-30s\n", nh.OutgoingInterface, nh.NextHop)
 		if first {
@@ -60,14 +71,15 @@ func NewRouteCmd() *cobra.Command {
 			stream, _ := client.DbRiMonitor(ctx, &api.DbRiMonitorRequest{
 				Level:         args[0],
 				AddressFamily: args[1],
+				Instance:      globalOpts.Instance,
 			})
-			fmt.Printf("LV %-30s %5s %-8s %-30s\n", "PREFIX", "DIST", "I/F", "NEXTHOP")
+			fmt.Printf(" LV T %-30s %4s %5s %-8s %-30s\n", "PREFIX", "PREF", "DIST", "I/F", "NEXTHOP")
 			for {
 				r, err := stream.Recv()
 				if err == io.EOF {
 					break
 				} else if err != nil {
-					return
+					exitWithError(err)
 				}
 				for _, route := range r.Routes {
 					printRoute(route)
@@ -0,0 +1,236 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	api "github.com/m-asama/golsr/api/isis"
+	"github.com/m-asama/golsr/pkg/isis/packet"
+	"github.com/m-asama/golsr/pkg/isis/server"
+)
+
+var simulateOpts struct {
+	Root      string
+	Level     string
+	FailLinks []string
+	SetMetric []string
+}
+
+// parseNodeId accepts a system-id optionally followed by a pseudonode id,
+// e.g. 0000.0000.0001 or 0000.0000.0001.02.
+func parseNodeId(nodeId string) ([packet.NEIGHBOUR_ID_LENGTH]byte, error) {
+	var id [packet.NEIGHBOUR_ID_LENGTH]byte
+	validNodeId := regexp.MustCompile(`^([0-9A-Fa-f]{4}\.[0-9A-Fa-f]{4}\.[0-9A-Fa-f]{4})(\.([0-9A-Fa-f]{2}))?$`)
+	m := validNodeId.FindStringSubmatch(nodeId)
+	if m == nil {
+		return id, errors.New("node-id invalid: " + nodeId)
+	}
+	systemId, err := parseSystemId(m[1])
+	if err != nil {
+		return id, err
+	}
+	copy(id[0:packet.SYSTEM_ID_LENGTH], systemId[0:packet.SYSTEM_ID_LENGTH])
+	if m[3] != "" {
+		pnId, _ := strconv.ParseUint(m[3], 16, 8)
+		id[packet.NEIGHBOUR_ID_LENGTH-1] = byte(pnId)
+	}
+	return id, nil
+}
+
+func parseLink(link string, n int) ([][packet.NEIGHBOUR_ID_LENGTH]byte, []string, error) {
+	fields := strings.Split(link, ",")
+	if len(fields) != n {
+		return nil, nil, errors.New("link invalid: " + link)
+	}
+	ids := make([][packet.NEIGHBOUR_ID_LENGTH]byte, 0)
+	for _, field := range fields[0:2] {
+		id, err := parseNodeId(strings.TrimSpace(field))
+		if err != nil {
+			return nil, nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, fields[2:], nil
+}
+
+// loadLsps reads an LSDB export as written by "goisis database export"
+// or --lsdb-export-file, a JSON array of LSPs as printed by
+// "goisis -j database linkstate" (the binary field is used) or raw LSP
+// PDUs concatenated back to back.
+func loadLsps(data []byte) ([]*packet.LsPdu, error) {
+	lsps := make([]*packet.LsPdu, 0)
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		var probe map[string]json.RawMessage
+		if err := json.Unmarshal(trimmed, &probe); err != nil {
+			return nil, err
+		}
+		if _, ok := probe["version"]; ok {
+			return server.ParseLsdbExport(trimmed)
+		}
+	}
+	if len(trimmed) > 0 && (trimmed[0] == '[' || trimmed[0] == '{') {
+		apiLsps := make([]*api.Lsp, 0)
+		if trimmed[0] == '{' {
+			apiLsp := &api.Lsp{}
+			if err := json.Unmarshal(trimmed, apiLsp); err != nil {
+				return nil, err
+			}
+			apiLsps = append(apiLsps, apiLsp)
+		} else if err := json.Unmarshal(trimmed, &apiLsps); err != nil {
+			return nil, err
+		}
+		for _, apiLsp := range apiLsps {
+			if len(apiLsp.Binary) == 0 {
+				return nil, errors.New("lsp " + apiLsp.LspId + " has no binary")
+			}
+			lsp, err := decodeLsp(apiLsp.Binary)
+			if err != nil {
+				return nil, err
+			}
+			lsps = append(lsps, lsp)
+		}
+		return lsps, nil
+	}
+	for len(data) > 0 {
+		if len(data) < 10 {
+			return nil, errors.New("raw lsp truncated")
+		}
+		length := int(binary.BigEndian.Uint16(data[8:10]))
+		if length < 10 || length > len(data) {
+			return nil, errors.New("raw lsp length invalid")
+		}
+		lsp, err := decodeLsp(data[0:length])
+		if err != nil {
+			return nil, err
+		}
+		lsps = append(lsps, lsp)
+		data = data[length:]
+	}
+	return lsps, nil
+}
+
+func decodeLsp(data []byte) (*packet.LsPdu, error) {
+	pdu, err := packet.DecodePduFromBytes(data)
+	if err != nil {
+		return nil, err
+	}
+	lsp, ok := pdu.(*packet.LsPdu)
+	if !ok {
+		return nil, errors.New("not a lsp: " + pdu.PduType().String())
+	}
+	return lsp, nil
+}
+
+func simulate(filename string) error {
+	systemId, err := parseSystemId(simulateOpts.Root)
+	if err != nil {
+		return err
+	}
+	var levels []server.IsisLevel
+	switch simulateOpts.Level {
+	case "all":
+		levels = server.ISIS_LEVEL_ALL
+	case "level-1":
+		levels = []server.IsisLevel{server.ISIS_LEVEL_1}
+	case "level-2":
+		levels = []server.IsisLevel{server.ISIS_LEVEL_2}
+	default:
+		return errors.New("level invalid: " + simulateOpts.Level)
+	}
+
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	lsps, err := loadLsps(data)
+	if err != nil {
+		return err
+	}
+	db := server.NewOfflineLsdb()
+	db.SetSystemId(systemId)
+	for _, lsp := range lsps {
+		if _, err := db.AddLsp(lsp); err != nil {
+			return err
+		}
+	}
+
+	for _, link := range simulateOpts.FailLinks {
+		ids, _, err := parseLink(link, 2)
+		if err != nil {
+			return err
+		}
+		db.FailLink(ids[0], ids[1])
+	}
+	for _, link := range simulateOpts.SetMetric {
+		ids, rest, err := parseLink(link, 3)
+		if err != nil {
+			return err
+		}
+		metric, err := strconv.ParseUint(strings.TrimSpace(rest[0]), 10, 32)
+		if err != nil {
+			return errors.New("metric invalid: " + link)
+		}
+		db.SetMetric(ids[0], ids[1], uint32(metric))
+	}
+
+	routes := make([]*api.Route, 0)
+	for _, level := range levels {
+		routes = append(routes, db.Spf(level)...)
+	}
+	printRoutes(routes)
+	return nil
+}
+
+func NewSimulateCmd() *cobra.Command {
+	simulateCmd := &cobra.Command{
+		Use:   "simulate <lsdb-dump>",
+		Short: "run SPF on an LSDB dump without goisisd",
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 1 || simulateOpts.Root == "" {
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
+			err := simulate(args[0])
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+	simulateCmd.Flags().StringVarP(&simulateOpts.Root, "root", "r", "",
+		"system-id (xxxx.xxxx.xxxx) to run SPF from")
+	simulateCmd.Flags().StringVarP(&simulateOpts.Level, "level", "l", "all",
+		"level to run SPF on (all, level-1 or level-2)")
+	simulateCmd.Flags().StringArrayVar(&simulateOpts.FailLinks, "fail-link", nil,
+		"fail the link between two nodes in both directions (A,B)")
+	simulateCmd.Flags().StringArrayVar(&simulateOpts.SetMetric, "set-metric", nil,
+		"set the metric advertised by A towards B (A,B,METRIC)")
+	return simulateCmd
+}
@@ -0,0 +1,254 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	api "github.com/m-asama/golsr/api/isis"
+)
+
+var topologyOpts struct {
+	Format string
+}
+
+func dotEscape(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	return strings.Replace(s, `"`, `\"`, -1)
+}
+
+func dotQuote(s string) string {
+	return `"` + dotEscape(s) + `"`
+}
+
+// Node ids are prefixed with the level as a node may appear in both
+// level-1 and level-2 graphs.
+func topologyNodeKey(graph *api.TopologyGraph, nodeId string) string {
+	return graph.Level + "/" + nodeId
+}
+
+func printTopologyDot(graphs []*api.TopologyGraph) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "digraph \"isis\" {\n")
+	for _, graph := range graphs {
+		fmt.Fprintf(&b, "\tsubgraph %s {\n", dotQuote("cluster_"+graph.Level))
+		fmt.Fprintf(&b, "\t\tlabel=%s;\n", dotQuote(graph.Level))
+		for _, node := range graph.Nodes {
+			label := node.NodeId
+			if node.Hostname != "" {
+				label = dotEscape(node.Hostname) + `\n` + node.NodeId
+			}
+			attrs := []string{`label="` + label + `"`}
+			if node.Pseudonode {
+				attrs = append(attrs, "shape=box", "style=dashed")
+			}
+			if node.Overload {
+				attrs = append(attrs, "color=red")
+			}
+			if !node.LspPresent {
+				attrs = append(attrs, "color=gray")
+			}
+			fmt.Fprintf(&b, "\t\t%s [%s];\n",
+				dotQuote(topologyNodeKey(graph, node.NodeId)), strings.Join(attrs, ", "))
+		}
+		for _, edge := range graph.Edges {
+			attrs := []string{"label=" + dotQuote(strconv.FormatUint(uint64(edge.Metric), 10))}
+			if edge.TeDefaultMetric != 0 {
+				attrs = append(attrs, "te_metric="+dotQuote(strconv.FormatUint(uint64(edge.TeDefaultMetric), 10)))
+			}
+			if edge.MaxLinkBandwidth != 0 {
+				attrs = append(attrs, "bandwidth="+dotQuote(strconv.FormatFloat(float64(edge.MaxLinkBandwidth), 'g', -1, 32)))
+			}
+			fmt.Fprintf(&b, "\t\t%s -> %s [%s];\n",
+				dotQuote(topologyNodeKey(graph, edge.From)),
+				dotQuote(topologyNodeKey(graph, edge.To)),
+				strings.Join(attrs, ", "))
+		}
+		fmt.Fprintf(&b, "\t}\n")
+	}
+	fmt.Fprintf(&b, "}\n")
+	os.Stdout.Write(b.Bytes())
+}
+
+type graphmlKey struct {
+	Id       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphmlData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+type graphmlNode struct {
+	Id   string        `xml:"id,attr"`
+	Data []graphmlData `xml:"data"`
+}
+
+type graphmlEdge struct {
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphmlData `xml:"data"`
+}
+
+type graphmlGraph struct {
+	Id          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphmlNode `xml:"node"`
+	Edges       []graphmlEdge `xml:"edge"`
+}
+
+type graphml struct {
+	XMLName xml.Name       `xml:"graphml"`
+	Xmlns   string         `xml:"xmlns,attr"`
+	Keys    []graphmlKey   `xml:"key"`
+	Graphs  []graphmlGraph `xml:"graph"`
+}
+
+var graphmlKeys = []graphmlKey{
+	{"node_id", "node", "node_id", "string"},
+	{"system_id", "node", "system_id", "string"},
+	{"hostname", "node", "hostname", "string"},
+	{"pseudonode", "node", "pseudonode", "boolean"},
+	{"lsp_present", "node", "lsp_present", "boolean"},
+	{"overload", "node", "overload", "boolean"},
+	{"attached", "node", "attached", "boolean"},
+	{"ipv4_te_routerid", "node", "ipv4_te_routerid", "string"},
+	{"ipv4_addresses", "node", "ipv4_addresses", "string"},
+	{"ipv6_addresses", "node", "ipv6_addresses", "string"},
+	{"tlv", "edge", "tlv", "string"},
+	{"metric", "edge", "metric", "long"},
+	{"admin_group", "edge", "admin_group", "long"},
+	{"ipv4_interface_addresses", "edge", "ipv4_interface_addresses", "string"},
+	{"ipv4_neighbor_addresses", "edge", "ipv4_neighbor_addresses", "string"},
+	{"max_link_bandwidth", "edge", "max_link_bandwidth", "float"},
+	{"max_reservable_link_bandwidth", "edge", "max_reservable_link_bandwidth", "float"},
+	{"unreserved_bandwidths", "edge", "unreserved_bandwidths", "string"},
+	{"te_default_metric", "edge", "te_default_metric", "long"},
+}
+
+func formatFloats(fs []float32) string {
+	ss := make([]string, 0)
+	for _, f := range fs {
+		ss = append(ss, strconv.FormatFloat(float64(f), 'g', -1, 32))
+	}
+	return strings.Join(ss, " ")
+}
+
+func printTopologyGraphml(graphs []*api.TopologyGraph) {
+	doc := graphml{
+		Xmlns:  "http://graphml.graphdrawing.org/xmlns",
+		Keys:   graphmlKeys,
+		Graphs: make([]graphmlGraph, 0),
+	}
+	for _, graph := range graphs {
+		g := graphmlGraph{
+			Id:          graph.Level,
+			EdgeDefault: "directed",
+		}
+		for _, node := range graph.Nodes {
+			g.Nodes = append(g.Nodes, graphmlNode{
+				Id: topologyNodeKey(graph, node.NodeId),
+				Data: []graphmlData{
+					{"node_id", node.NodeId},
+					{"system_id", node.SystemId},
+					{"hostname", node.Hostname},
+					{"pseudonode", strconv.FormatBool(node.Pseudonode)},
+					{"lsp_present", strconv.FormatBool(node.LspPresent)},
+					{"overload", strconv.FormatBool(node.Overload)},
+					{"attached", strconv.FormatBool(node.Attached)},
+					{"ipv4_te_routerid", node.Ipv4TeRouterid},
+					{"ipv4_addresses", strings.Join(node.Ipv4Addresses, " ")},
+					{"ipv6_addresses", strings.Join(node.Ipv6Addresses, " ")},
+				},
+			})
+		}
+		for _, edge := range graph.Edges {
+			g.Edges = append(g.Edges, graphmlEdge{
+				Source: topologyNodeKey(graph, edge.From),
+				Target: topologyNodeKey(graph, edge.To),
+				Data: []graphmlData{
+					{"tlv", edge.Tlv},
+					{"metric", strconv.FormatUint(uint64(edge.Metric), 10)},
+					{"admin_group", strconv.FormatUint(uint64(edge.AdminGroup), 10)},
+					{"ipv4_interface_addresses", strings.Join(edge.Ipv4InterfaceAddresses, " ")},
+					{"ipv4_neighbor_addresses", strings.Join(edge.Ipv4NeighborAddresses, " ")},
+					{"max_link_bandwidth", strconv.FormatFloat(float64(edge.MaxLinkBandwidth), 'g', -1, 32)},
+					{"max_reservable_link_bandwidth", strconv.FormatFloat(float64(edge.MaxReservableLinkBandwidth), 'g', -1, 32)},
+					{"unreserved_bandwidths", formatFloats(edge.UnreservedBandwidths)},
+					{"te_default_metric", strconv.FormatUint(uint64(edge.TeDefaultMetric), 10)},
+				},
+			})
+		}
+		doc.Graphs = append(doc.Graphs, g)
+	}
+	x, _ := xml.MarshalIndent(doc, "", "  ")
+	fmt.Print(xml.Header)
+	fmt.Println(string(x))
+}
+
+func NewTopologyCmd() *cobra.Command {
+	topologyCmd := &cobra.Command{
+		Use:   "topology [<level>]",
+		Short: "show the IS reachability graph (all levels by default)",
+		Run: func(cmd *cobra.Command, args []string) {
+			level := "all"
+			if len(args) == 1 {
+				level = args[0]
+			}
+			format := topologyOpts.Format
+			if globalOpts.Json {
+				format = "json"
+			}
+			if format != "dot" && format != "graphml" && format != "json" {
+				exitWithError(errors.New("format invalid: " + format))
+			}
+			response, err := client.TopologyGet(ctx, &api.TopologyGetRequest{
+				Level:    level,
+				Instance: globalOpts.Instance,
+			})
+			if err != nil {
+				exitWithError(err)
+			}
+			if response.Result != "" {
+				exitWithError(errors.New(response.Result))
+			}
+			switch format {
+			case "dot":
+				printTopologyDot(response.Graphs)
+			case "graphml":
+				printTopologyGraphml(response.Graphs)
+			case "json":
+				printJson(response.Graphs)
+			}
+		},
+	}
+	topologyCmd.Flags().StringVarP(&topologyOpts.Format, "format", "f", "dot",
+		"output format (dot, graphml or json)")
+	return topologyCmd
+}
@@ -0,0 +1,89 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	api "github.com/m-asama/golsr/api/isis"
+)
+
+var traceOpts struct {
+	Interfaces []string
+	Directions []string
+	PduTypes   []string
+}
+
+func NewTraceEnableCmd() *cobra.Command {
+	traceEnableCmd := &cobra.Command{
+		Use: "enable",
+		Run: func(cmd *cobra.Command, args []string) {
+			request := &api.TraceSetRequest{
+				Enable:     true,
+				Interfaces: traceOpts.Interfaces,
+				Directions: traceOpts.Directions,
+				PduTypes:   traceOpts.PduTypes,
+				Instance:   globalOpts.Instance,
+			}
+			response, err := client.TraceSet(ctx, request)
+			if err != nil {
+				exitWithError(err)
+			}
+			fmt.Println(response.Result)
+		},
+	}
+	traceEnableCmd.Flags().StringSliceVarP(&traceOpts.Interfaces, "interface", "i", nil,
+		"interfaces to trace (all by default)")
+	traceEnableCmd.Flags().StringSliceVarP(&traceOpts.Directions, "direction", "r", nil,
+		"directions to trace: recv, send (all by default)")
+	traceEnableCmd.Flags().StringSliceVarP(&traceOpts.PduTypes, "pdu-type", "t", nil,
+		"pdu types to trace: iih, lsp, csnp, psnp, l1-lsp, ... (all by default)")
+	return traceEnableCmd
+}
+
+func NewTraceDisableCmd() *cobra.Command {
+	traceDisableCmd := &cobra.Command{
+		Use: "disable",
+		Run: func(cmd *cobra.Command, args []string) {
+			response, err := client.TraceSet(ctx, &api.TraceSetRequest{Enable: false, Instance: globalOpts.Instance})
+			if err != nil {
+				exitWithError(err)
+			}
+			fmt.Println(response.Result)
+		},
+	}
+	return traceDisableCmd
+}
+
+func NewTraceCmd() *cobra.Command {
+	traceCmd := &cobra.Command{
+		Use: "trace",
+		Run: func(cmd *cobra.Command, args []string) {
+		},
+	}
+
+	traceEnableCmd := NewTraceEnableCmd()
+	traceCmd.AddCommand(traceEnableCmd)
+
+	traceDisableCmd := NewTraceDisableCmd()
+	traceCmd.AddCommand(traceDisableCmd)
+
+	return traceCmd
+}
@@ -18,8 +18,10 @@
 package config
 
 import (
+	"bytes"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
+	"io/ioutil"
 	"os"
 	"os/signal"
 	"path/filepath"
@@ -40,7 +42,40 @@ func detectConfigFileType(path, def string) string {
 	}
 }
 
+// Parse reads a config in format (toml, yaml or json) from data and fills
+// in the defaults. The result is not validated; Serve does that before
+// handing a config to the server.
+func Parse(data []byte, format string) (*IsisConfig, error) {
+	c := &IsisConfig{}
+	v := viper.New()
+	v.SetConfigType(format)
+	if err := v.ReadConfig(bytes.NewBuffer(data)); err != nil {
+		return nil, err
+	}
+	if err := v.UnmarshalExact(c); err != nil {
+		return nil, err
+	}
+	c.fillDefaults()
+	return c, nil
+}
+
 func Serve(path, format string, configCh chan *IsisConfig) {
+	serve(path, format, func(data []byte, format string) error {
+		c, err := Parse(data, format)
+		if err != nil {
+			return err
+		}
+		if err = c.validate(); err != nil {
+			return err
+		}
+		configCh <- c
+		return nil
+	})
+}
+
+// serve reads the config file at path and hands it to handle, then does
+// it again each time SIGHUP is received. It exits if the first read fails.
+func serve(path, format string, handle func(data []byte, format string) error) {
 
 	//log.Info("ReadConfigfileServe started")
 
@@ -52,19 +87,12 @@ func Serve(path, format string, configCh chan *IsisConfig) {
 
 	cnt := 0
 	for {
-		c := &IsisConfig{}
-		v := viper.New()
-		v.SetConfigFile(path)
-		v.SetConfigType(format)
+		var data []byte
 		var err error
-		if err = v.ReadInConfig(); err != nil {
+		if data, err = ioutil.ReadFile(path); err != nil {
 			goto ERROR
 		}
-		if err = v.UnmarshalExact(c); err != nil {
-			goto ERROR
-		}
-		c.fillDefaults()
-		if err = c.validate(); err != nil {
+		if err = handle(data, format); err != nil {
 			goto ERROR
 		}
 		if cnt == 0 {
@@ -87,7 +115,6 @@ func Serve(path, format string, configCh chan *IsisConfig) {
 		*/
 
 		cnt++
-		configCh <- c
 		goto NEXT
 	ERROR:
 		if cnt == 0 {
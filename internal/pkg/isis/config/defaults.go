@@ -300,6 +300,10 @@ func (config *IsisConfig) fillDefaults() {
 	// preference
 	// overload
 	// overload-max-metric
+	// defined-sets
+	// policy-definitions
+	// apply-policy
+	config.fillPolicyDefaults()
 	// topologies
 	for _, topo := range config.Topologies {
 		topo.fillDefaults()
@@ -309,3 +313,46 @@ func (config *IsisConfig) fillDefaults() {
 		iface.fillDefaults(config)
 	}
 }
+
+func (config *Conditions) fillDefaults() {
+	// match-prefix-set-options
+	if config.Config.MatchPrefixSetOptions == nil {
+		matchPrefixSetOptions := "any"
+		config.Config.MatchPrefixSetOptions = &matchPrefixSetOptions
+	}
+	// match-tag-set-options
+	if config.Config.MatchTagSetOptions == nil {
+		matchTagSetOptions := "any"
+		config.Config.MatchTagSetOptions = &matchTagSetOptions
+	}
+}
+
+func (config *Actions) fillDefaults() {
+	// route-disposition
+	if config.Config.RouteDisposition == nil {
+		routeDisposition := "none"
+		config.Config.RouteDisposition = &routeDisposition
+	}
+}
+
+func (config *PolicyDefinition) fillDefaults() {
+	for _, stmt := range config.Statements {
+		stmt.Conditions.fillDefaults()
+		stmt.Actions.fillDefaults()
+	}
+}
+
+func (config *ApplyPolicy) fillDefaults() {
+	// default-install-policy
+	if config.Config.DefaultInstallPolicy == nil {
+		defaultInstallPolicy := "accept-route"
+		config.Config.DefaultInstallPolicy = &defaultInstallPolicy
+	}
+}
+
+func (config *IsisConfig) fillPolicyDefaults() {
+	for _, pd := range config.PolicyDefinitions {
+		pd.fillDefaults()
+	}
+	config.ApplyPolicy.fillDefaults()
+}
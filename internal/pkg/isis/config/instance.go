@@ -0,0 +1,117 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/spf13/viper"
+)
+
+// DEFAULT_INSTANCE_NAME is the name of the instance of a config file
+// without instances.
+const DEFAULT_INSTANCE_NAME = "default"
+
+// Instance is one of the IS-IS instances of a config file. Its keys are
+// those of a config file without instances plus its name and the VRF
+// the interfaces of which it uses and into the table of which it
+// installs the routes, the one given to the daemon if not set.
+type Instance struct {
+	Name       *string `mapstructure:"name"`
+	Vrf        *string `mapstructure:"vrf"`
+	IsisConfig `mapstructure:",squash"`
+}
+
+type InstancesConfig struct {
+	Instances []*Instance `mapstructure:"instances"`
+}
+
+// ParseInstances reads a config in format from data which is either a
+// list of named instances or, as Parse reads, a single one which is
+// named DEFAULT_INSTANCE_NAME. The defaults are filled in but the result
+// is not validated.
+func ParseInstances(data []byte, format string) ([]*Instance, error) {
+	v := viper.New()
+	v.SetConfigType(format)
+	if err := v.ReadConfig(bytes.NewBuffer(data)); err != nil {
+		return nil, err
+	}
+	if !v.IsSet("instances") {
+		c, err := Parse(data, format)
+		if err != nil {
+			return nil, err
+		}
+		name := DEFAULT_INSTANCE_NAME
+		return []*Instance{&Instance{Name: &name, IsisConfig: *c}}, nil
+	}
+	c := &InstancesConfig{}
+	if err := v.UnmarshalExact(c); err != nil {
+		return nil, err
+	}
+	for _, instance := range c.Instances {
+		instance.fillDefaults()
+	}
+	return c.Instances, nil
+}
+
+func validateInstances(instances []*Instance) error {
+	if len(instances) == 0 {
+		return errors.New("no instance defined")
+	}
+	names := make(map[string]bool)
+	interfaces := make(map[string]string)
+	for _, instance := range instances {
+		if instance.Name == nil || *instance.Name == "" {
+			return errors.New("instance name not defined")
+		}
+		name := *instance.Name
+		if names[name] {
+			return errors.New("instance " + name + " defined twice")
+		}
+		names[name] = true
+		if err := instance.validate(); err != nil {
+			return errors.New("instance " + name + ": " + err.Error())
+		}
+		for _, iface := range instance.Interfaces {
+			ifName := *iface.Config.Name
+			if other, ok := interfaces[ifName]; ok {
+				return errors.New("interface " + ifName + " claimed by instances " +
+					other + " and " + name)
+			}
+			interfaces[ifName] = name
+		}
+	}
+	return nil
+}
+
+// ServeInstances is Serve for a config file which may have several
+// instances.
+func ServeInstances(path, format string, instancesCh chan []*Instance) {
+	serve(path, format, func(data []byte, format string) error {
+		instances, err := ParseInstances(data, format)
+		if err != nil {
+			return err
+		}
+		if err = validateInstances(instances); err != nil {
+			return err
+		}
+		instancesCh <- instances
+		return nil
+	})
+}
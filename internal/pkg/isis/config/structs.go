@@ -166,6 +166,91 @@ type OverloadMaxMetric struct {
 	Config OverloadMaxMetricConfig `mapstructure:"config" json:"config,omitempty"`
 }
 
+type PrefixConfig struct {
+	IpPrefix        *string `mapstructure:"ip-prefix"`
+	MasklengthRange *string `mapstructure:"masklength-range"`
+}
+
+type Prefix struct {
+	Config PrefixConfig `mapstructure:"config" json:"config,omitempty"`
+}
+
+type PrefixSetConfig struct {
+	PrefixSetName *string `mapstructure:"prefix-set-name"`
+}
+
+type PrefixSet struct {
+	Config   PrefixSetConfig `mapstructure:"config" json:"config,omitempty"`
+	Prefixes []*Prefix       `mapstructure:"prefixes"`
+}
+
+type TagSetConfig struct {
+	TagSetName *string   `mapstructure:"tag-set-name"`
+	Tag        []*uint32 `mapstructure:"tag-list"`
+}
+
+type TagSet struct {
+	Config TagSetConfig `mapstructure:"config" json:"config,omitempty"`
+}
+
+type DefinedSets struct {
+	PrefixSets []*PrefixSet `mapstructure:"prefix-sets"`
+	TagSets    []*TagSet    `mapstructure:"tag-sets"`
+}
+
+type ConditionsConfig struct {
+	MatchPrefixSet        *string `mapstructure:"match-prefix-set"`
+	MatchPrefixSetOptions *string `mapstructure:"match-prefix-set-options"`
+	MatchTagSet           *string `mapstructure:"match-tag-set"`
+	MatchTagSetOptions    *string `mapstructure:"match-tag-set-options"`
+	Level                 *string `mapstructure:"level"`
+	RouteType             *string `mapstructure:"route-type"`
+}
+
+type Conditions struct {
+	Config ConditionsConfig `mapstructure:"config" json:"config,omitempty"`
+}
+
+type ActionsConfig struct {
+	RouteDisposition *string `mapstructure:"route-disposition"`
+	SetMetric        *uint32 `mapstructure:"set-metric"`
+	SetTag           *uint32 `mapstructure:"set-tag"`
+	SetUpDown        *bool   `mapstructure:"set-up-down"`
+}
+
+type Actions struct {
+	Config ActionsConfig `mapstructure:"config" json:"config,omitempty"`
+}
+
+type StatementConfig struct {
+	Name *string `mapstructure:"name"`
+}
+
+type Statement struct {
+	Config     StatementConfig `mapstructure:"config" json:"config,omitempty"`
+	Conditions Conditions      `mapstructure:"conditions"`
+	Actions    Actions         `mapstructure:"actions"`
+}
+
+type PolicyDefinitionConfig struct {
+	Name *string `mapstructure:"name"`
+}
+
+type PolicyDefinition struct {
+	Config     PolicyDefinitionConfig `mapstructure:"config" json:"config,omitempty"`
+	Statements []*Statement           `mapstructure:"statements"`
+}
+
+type ApplyPolicyConfig struct {
+	InstallPolicy        []*string `mapstructure:"install-policy-list"`
+	DefaultInstallPolicy *string   `mapstructure:"default-install-policy"`
+	LeakPolicy           []*string `mapstructure:"leak-policy-list"`
+}
+
+type ApplyPolicy struct {
+	Config ApplyPolicyConfig `mapstructure:"config" json:"config,omitempty"`
+}
+
 type FastRerouteConfig struct {
 }
 
@@ -404,30 +489,73 @@ type Config struct {
 }
 
 type IsisConfig struct {
-	Config            Config            `mapstructure:"config" json:"config,omitempty"`
-	GracefulRestart   GracefulRestart   `mapstructure:"graceful-restart"`
-	Nsr               Nsr               `mapstructure:"nsr"`
-	NodeTags          []*NodeTag        `mapstructure:"node-tags"`
-	MetricType        MetricType        `mapstructure:"metric-type"`
-	DefaultMetric     DefaultMetric     `mapstructure:"default-metric"`
-	AutoCost          AutoCost          `mapstructure:"auto-cost"`
-	Authentication    Authentication    `mapstructure:"authentication"`
-	AddressFamilies   []*AddressFamily  `mapstructure:"address-families"`
-	Mpls              Mpls              `mapstructure:"mpls"`
-	SpfControl        SpfControl        `mapstructure:"spf-control"`
-	FastReroute       FastReroute       `mapstructure:"fast-reroute"`
-	Preference        Preference        `mapstructure:"preference"`
-	Overload          Overload          `mapstructure:"overload"`
-	OverloadMaxMetric OverloadMaxMetric `mapstructure:"overload-max-metric"`
-	Topologies        []*Topology       `mapstructure:"topologies"`
-	Interfaces        []*Interface      `mapstructure:"interfaces"`
+	Config            Config              `mapstructure:"config" json:"config,omitempty"`
+	GracefulRestart   GracefulRestart     `mapstructure:"graceful-restart"`
+	Nsr               Nsr                 `mapstructure:"nsr"`
+	NodeTags          []*NodeTag          `mapstructure:"node-tags"`
+	MetricType        MetricType          `mapstructure:"metric-type"`
+	DefaultMetric     DefaultMetric       `mapstructure:"default-metric"`
+	AutoCost          AutoCost            `mapstructure:"auto-cost"`
+	Authentication    Authentication      `mapstructure:"authentication"`
+	AddressFamilies   []*AddressFamily    `mapstructure:"address-families"`
+	Mpls              Mpls                `mapstructure:"mpls"`
+	SpfControl        SpfControl          `mapstructure:"spf-control"`
+	FastReroute       FastReroute         `mapstructure:"fast-reroute"`
+	Preference        Preference          `mapstructure:"preference"`
+	Overload          Overload            `mapstructure:"overload"`
+	OverloadMaxMetric OverloadMaxMetric   `mapstructure:"overload-max-metric"`
+	DefinedSets       DefinedSets         `mapstructure:"defined-sets"`
+	PolicyDefinitions []*PolicyDefinition `mapstructure:"policy-definitions"`
+	ApplyPolicy       ApplyPolicy         `mapstructure:"apply-policy"`
+	Topologies        []*Topology         `mapstructure:"topologies"`
+	Interfaces        []*Interface        `mapstructure:"interfaces"`
 }
 
 func NewIsisConfig() *IsisConfig {
 	config := &IsisConfig{}
 	config.NodeTags = make([]*NodeTag, 0)
 	config.AddressFamilies = make([]*AddressFamily, 0)
+	config.DefinedSets.PrefixSets = make([]*PrefixSet, 0)
+	config.DefinedSets.TagSets = make([]*TagSet, 0)
+	config.PolicyDefinitions = make([]*PolicyDefinition, 0)
 	config.Topologies = make([]*Topology, 0)
 	config.Interfaces = make([]*Interface, 0)
 	return config
 }
+
+// CopyPolicies replaces the defined-sets, policy-definitions and
+// apply-policy of config with copies, so that filling in their defaults
+// leaves the config config was copied from alone.
+func (config *IsisConfig) CopyPolicies() {
+	prefixSets := make([]*PrefixSet, 0, len(config.DefinedSets.PrefixSets))
+	for _, ps := range config.DefinedSets.PrefixSets {
+		nps := *ps
+		nps.Prefixes = make([]*Prefix, 0, len(ps.Prefixes))
+		for _, prefix := range ps.Prefixes {
+			nprefix := *prefix
+			nps.Prefixes = append(nps.Prefixes, &nprefix)
+		}
+		prefixSets = append(prefixSets, &nps)
+	}
+	config.DefinedSets.PrefixSets = prefixSets
+	tagSets := make([]*TagSet, 0, len(config.DefinedSets.TagSets))
+	for _, ts := range config.DefinedSets.TagSets {
+		nts := *ts
+		nts.Config.Tag = append([]*uint32{}, ts.Config.Tag...)
+		tagSets = append(tagSets, &nts)
+	}
+	config.DefinedSets.TagSets = tagSets
+	policies := make([]*PolicyDefinition, 0, len(config.PolicyDefinitions))
+	for _, pd := range config.PolicyDefinitions {
+		npd := *pd
+		npd.Statements = make([]*Statement, 0, len(pd.Statements))
+		for _, stmt := range pd.Statements {
+			nstmt := *stmt
+			npd.Statements = append(npd.Statements, &nstmt)
+		}
+		policies = append(policies, &npd)
+	}
+	config.PolicyDefinitions = policies
+	config.ApplyPolicy.Config.InstallPolicy = append([]*string{}, config.ApplyPolicy.Config.InstallPolicy...)
+	config.ApplyPolicy.Config.LeakPolicy = append([]*string{}, config.ApplyPolicy.Config.LeakPolicy...)
+}
@@ -22,6 +22,8 @@ import (
 	"fmt"
 	"github.com/spf13/viper"
 	"testing"
+
+	"github.com/m-asama/golsr/internal/pkg/kernel"
 )
 
 func TestConfig(t *testing.T) {
@@ -81,3 +83,109 @@ func TestConfig(t *testing.T) {
 	//t.Fatalf("%s", ss)
 	//t.Fatalf("xx = %s", *c.Interfaces[0].AddressFamilies[0].Config.AddressFamily)
 }
+
+func TestValidateInterface(t *testing.T) {
+	fake := kernel.NewFakeProvider()
+	fake.SetInterface(&kernel.Interface{
+		IfIndex: 2,
+		Name:    "eth0",
+		IfType:  kernel.IF_TYPE_BROADCAST,
+		Up:      true,
+	})
+	saved := kernel.DefaultProvider()
+	kernel.SetDefaultProvider(fake)
+	defer kernel.SetDefaultProvider(saved)
+
+	text := `
+[config]
+  system-id = "0000.0000.0001"
+  area-address-list = ["49.0001"]
+
+[[interfaces]]
+  [interfaces.config]
+    name = "%s"
+`
+	c, err := Parse([]byte(fmt.Sprintf(text, "eth0")), "toml")
+	if err != nil {
+		t.Fatalf("failed Parse: %#v", err)
+	}
+	if *c.Interfaces[0].Config.InterfaceType != "broadcast" {
+		t.Fatalf("failed interface-type: %s", *c.Interfaces[0].Config.InterfaceType)
+	}
+	if err = c.validate(); err != nil {
+		t.Fatalf("failed validate: %#v", err)
+	}
+	c, err = Parse([]byte(fmt.Sprintf(text, "eth1")), "toml")
+	if err != nil {
+		t.Fatalf("failed Parse: %#v", err)
+	}
+	if err = c.validate(); err == nil {
+		t.Fatalf("failed validate: missing interface accepted")
+	}
+}
+
+func TestValidateInstances(t *testing.T) {
+	fake := kernel.NewFakeProvider()
+	for i, name := range []string{"eth0", "eth1"} {
+		fake.SetInterface(&kernel.Interface{
+			IfIndex: i + 2,
+			Name:    name,
+			IfType:  kernel.IF_TYPE_POINTTOPOINT,
+			Up:      true,
+		})
+	}
+	saved := kernel.DefaultProvider()
+	kernel.SetDefaultProvider(fake)
+	defer kernel.SetDefaultProvider(saved)
+
+	single := `
+[config]
+  system-id = "0000.0000.0001"
+  area-address-list = ["49.0001"]
+`
+	instances, err := ParseInstances([]byte(single), "toml")
+	if err != nil {
+		t.Fatalf("failed ParseInstances: %#v", err)
+	}
+	if len(instances) != 1 || *instances[0].Name != DEFAULT_INSTANCE_NAME {
+		t.Fatalf("failed single instance: %#v", instances)
+	}
+
+	text := `
+[[instances]]
+  name = "a"
+  [instances.config]
+    system-id = "0000.0000.0001"
+    area-address-list = ["49.0001"]
+  [[instances.interfaces]]
+    [instances.interfaces.config]
+      name = "eth0"
+
+[[instances]]
+  name = "b"
+  [instances.config]
+    system-id = "0000.0000.0002"
+    area-address-list = ["49.0002"]
+  [[instances.interfaces]]
+    [instances.interfaces.config]
+      name = "%s"
+`
+	instances, err = ParseInstances([]byte(fmt.Sprintf(text, "eth1")), "toml")
+	if err != nil {
+		t.Fatalf("failed ParseInstances: %#v", err)
+	}
+	if len(instances) != 2 || *instances[1].Name != "b" ||
+		*instances[1].Interfaces[0].Config.InterfaceType != "point-to-point" {
+		t.Fatalf("failed instances: %#v", instances)
+	}
+	if err = validateInstances(instances); err != nil {
+		t.Fatalf("failed validateInstances: %#v", err)
+	}
+	instances, err = ParseInstances([]byte(fmt.Sprintf(text, "eth0")), "toml")
+	if err != nil {
+		t.Fatalf("failed ParseInstances: %#v", err)
+	}
+	if err = validateInstances(instances); err == nil {
+		t.Fatalf("failed validateInstances: interface claimed twice accepted")
+	}
+}
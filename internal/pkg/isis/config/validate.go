@@ -19,6 +19,8 @@ package config
 
 import (
 	"errors"
+	"fmt"
+	"net"
 	"regexp"
 
 	"github.com/m-asama/golsr/internal/pkg/kernel"
@@ -112,9 +114,182 @@ func (config *IsisConfig) validate() error {
 			return err
 		}
 	}
+	err = config.validatePolicies()
+	if err != nil {
+		return err
+	}
 	return err
 }
 
+// ParsePrefix returns the prefix of config and the range of the prefix
+// lengths it matches. Without masklength-range it matches only the prefix
+// length of ip-prefix.
+func (config *Prefix) ParsePrefix() (*net.IPNet, int, int, error) {
+	if config.Config.IpPrefix == nil {
+		return nil, 0, 0, errors.New("ip-prefix not defined")
+	}
+	_, ipnet, err := net.ParseCIDR(*config.Config.IpPrefix)
+	if err != nil {
+		return nil, 0, 0, errors.New("ip-prefix invalid")
+	}
+	ones, bits := ipnet.Mask.Size()
+	if config.Config.MasklengthRange == nil {
+		return ipnet, ones, ones, nil
+	}
+	var min, max int
+	var rest string
+	n, _ := fmt.Sscanf(*config.Config.MasklengthRange, "%d..%d%s", &min, &max, &rest)
+	if n != 2 || min < ones || min > max || max > bits {
+		return nil, 0, 0, errors.New("masklength-range invalid")
+	}
+	return ipnet, min, max, nil
+}
+
+func (config *PrefixSet) validate() error {
+	if config.Config.PrefixSetName == nil {
+		return errors.New("prefix-set-name not defined")
+	}
+	for _, prefix := range config.Prefixes {
+		if _, _, _, err := prefix.ParsePrefix(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (config *TagSet) validate() error {
+	if config.Config.TagSetName == nil {
+		return errors.New("tag-set-name not defined")
+	}
+	for _, tag := range config.Config.Tag {
+		if tag == nil {
+			return errors.New("tag-list invalid")
+		}
+	}
+	return nil
+}
+
+func (config *Statement) validate(prefixSets, tagSets map[string]bool) error {
+	if config.Config.Name == nil {
+		return errors.New("statement name not defined")
+	}
+	conditions := &config.Conditions.Config
+	if conditions.MatchPrefixSet != nil && !prefixSets[*conditions.MatchPrefixSet] {
+		return errors.New("match-prefix-set not defined")
+	}
+	if conditions.MatchPrefixSetOptions == nil ||
+		(*conditions.MatchPrefixSetOptions != "any" &&
+			*conditions.MatchPrefixSetOptions != "invert") {
+		return errors.New("match-prefix-set-options invalid")
+	}
+	if conditions.MatchTagSet != nil && !tagSets[*conditions.MatchTagSet] {
+		return errors.New("match-tag-set not defined")
+	}
+	if conditions.MatchTagSetOptions == nil ||
+		(*conditions.MatchTagSetOptions != "any" &&
+			*conditions.MatchTagSetOptions != "invert") {
+		return errors.New("match-tag-set-options invalid")
+	}
+	if conditions.Level != nil &&
+		*conditions.Level != "level-1" &&
+		*conditions.Level != "level-2" {
+		return errors.New("level invalid")
+	}
+	if conditions.RouteType != nil &&
+		*conditions.RouteType != "internal" &&
+		*conditions.RouteType != "external" {
+		return errors.New("route-type invalid")
+	}
+	actions := &config.Actions.Config
+	if actions.RouteDisposition == nil ||
+		(*actions.RouteDisposition != "none" &&
+			*actions.RouteDisposition != "accept-route" &&
+			*actions.RouteDisposition != "reject-route") {
+		return errors.New("route-disposition invalid")
+	}
+	return nil
+}
+
+func (config *PolicyDefinition) validate(prefixSets, tagSets map[string]bool) error {
+	if config.Config.Name == nil {
+		return errors.New("policy name not defined")
+	}
+	names := make(map[string]bool)
+	for _, stmt := range config.Statements {
+		if err := stmt.validate(prefixSets, tagSets); err != nil {
+			return err
+		}
+		if names[*stmt.Config.Name] {
+			return errors.New("statement name duplicated")
+		}
+		names[*stmt.Config.Name] = true
+	}
+	return nil
+}
+
+func validatePolicyList(policies []*string, definitions map[string]bool) error {
+	for _, policy := range policies {
+		if policy == nil || !definitions[*policy] {
+			return errors.New("policy not defined")
+		}
+	}
+	return nil
+}
+
+func (config *IsisConfig) validatePolicies() error {
+	prefixSets := make(map[string]bool)
+	for _, ps := range config.DefinedSets.PrefixSets {
+		if err := ps.validate(); err != nil {
+			return err
+		}
+		if prefixSets[*ps.Config.PrefixSetName] {
+			return errors.New("prefix-set-name duplicated")
+		}
+		prefixSets[*ps.Config.PrefixSetName] = true
+	}
+	tagSets := make(map[string]bool)
+	for _, ts := range config.DefinedSets.TagSets {
+		if err := ts.validate(); err != nil {
+			return err
+		}
+		if tagSets[*ts.Config.TagSetName] {
+			return errors.New("tag-set-name duplicated")
+		}
+		tagSets[*ts.Config.TagSetName] = true
+	}
+	definitions := make(map[string]bool)
+	for _, pd := range config.PolicyDefinitions {
+		if err := pd.validate(prefixSets, tagSets); err != nil {
+			return err
+		}
+		if definitions[*pd.Config.Name] {
+			return errors.New("policy name duplicated")
+		}
+		definitions[*pd.Config.Name] = true
+	}
+	applyPolicy := &config.ApplyPolicy.Config
+	if err := validatePolicyList(applyPolicy.InstallPolicy, definitions); err != nil {
+		return err
+	}
+	if err := validatePolicyList(applyPolicy.LeakPolicy, definitions); err != nil {
+		return err
+	}
+	if applyPolicy.DefaultInstallPolicy == nil ||
+		(*applyPolicy.DefaultInstallPolicy != "accept-route" &&
+			*applyPolicy.DefaultInstallPolicy != "reject-route") {
+		return errors.New("default-install-policy invalid")
+	}
+	return nil
+}
+
+// ValidatePolicies fills in the defaults of and validates the defined-sets,
+// policy-definitions and apply-policy of config. The API uses it to check
+// the policies it changes without the rest of the config.
+func (config *IsisConfig) ValidatePolicies() error {
+	config.fillPolicyDefaults()
+	return config.validatePolicies()
+}
+
 func validateSystemId(systemId string) error {
 	validSystemId := regexp.MustCompile(`^[0-9A-Fa-f]{4}\.[0-9A-Fa-f]{4}\.[0-9A-Fa-f]{4}$`)
 	if !validSystemId.MatchString(systemId) {
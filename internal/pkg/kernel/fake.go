@@ -0,0 +1,300 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kernel
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// FakeProvider is a Provider kept in memory. Interfaces are set by the
+// test and watchers are notified of every change; routes are recorded and
+// can be inspected with Ipv4Routes and Ipv6Routes.
+type FakeProvider struct {
+	lock       sync.Mutex
+	interfaces []*Interface
+	vrfs       []*Vrf
+	ipv4Routes map[string]*Ipv4Route
+	ipv6Routes map[string]*Ipv6Route
+	watchers   []chan struct{}
+}
+
+func NewFakeProvider() *FakeProvider {
+	return &FakeProvider{
+		interfaces: make([]*Interface, 0),
+		vrfs:       make([]*Vrf, 0),
+		ipv4Routes: make(map[string]*Ipv4Route),
+		ipv6Routes: make(map[string]*Ipv6Route),
+		watchers:   make([]chan struct{}, 0),
+	}
+}
+
+func copyInterface(iface *Interface) *Interface {
+	c := *iface
+	c.HardwareAddr = append([]byte{}, iface.HardwareAddr...)
+	c.Ipv4Addresses = make([]*Ipv4Address, 0)
+	for _, addr := range iface.Ipv4Addresses {
+		a := *addr
+		c.Ipv4Addresses = append(c.Ipv4Addresses, &a)
+	}
+	c.Ipv6Addresses = make([]*Ipv6Address, 0)
+	for _, addr := range iface.Ipv6Addresses {
+		a := *addr
+		c.Ipv6Addresses = append(c.Ipv6Addresses, &a)
+	}
+	return &c
+}
+
+func (provider *FakeProvider) notify() {
+	for _, watcher := range provider.watchers {
+		select {
+		case watcher <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// SetInterface adds iface or replaces the interface of the same name.
+func (provider *FakeProvider) SetInterface(iface *Interface) {
+	provider.lock.Lock()
+	defer provider.lock.Unlock()
+	iface = copyInterface(iface)
+	for i, tmp := range provider.interfaces {
+		if tmp.Name == iface.Name {
+			provider.interfaces[i] = iface
+			provider.notify()
+			return
+		}
+	}
+	provider.interfaces = append(provider.interfaces, iface)
+	provider.notify()
+}
+
+// RemoveInterface removes the interface name.
+func (provider *FakeProvider) RemoveInterface(name string) {
+	provider.lock.Lock()
+	defer provider.lock.Unlock()
+	interfaces := make([]*Interface, 0)
+	for _, iface := range provider.interfaces {
+		if iface.Name != name {
+			interfaces = append(interfaces, iface)
+		}
+	}
+	provider.interfaces = interfaces
+	provider.notify()
+}
+
+// SetVrf adds vrf or replaces the VRF of the same name. Interfaces are
+// put into it by setting their MasterIndex to its IfIndex.
+func (provider *FakeProvider) SetVrf(vrf *Vrf) {
+	provider.lock.Lock()
+	defer provider.lock.Unlock()
+	v := *vrf
+	for i, tmp := range provider.vrfs {
+		if tmp.Name == v.Name {
+			provider.vrfs[i] = &v
+			provider.notify()
+			return
+		}
+	}
+	provider.vrfs = append(provider.vrfs, &v)
+	provider.notify()
+}
+
+// SetMaster enslaves the interface name to the device of ifindex master,
+// or releases it if master is 0.
+func (provider *FakeProvider) SetMaster(name string, master int) error {
+	provider.lock.Lock()
+	defer provider.lock.Unlock()
+	for _, iface := range provider.interfaces {
+		if iface.Name == name {
+			iface.MasterIndex = master
+			provider.notify()
+			return nil
+		}
+	}
+	return errors.New("FakeProvider.SetMaster: no such interface " + name)
+}
+
+// SetUp sets the operational state of the interface name.
+func (provider *FakeProvider) SetUp(name string, up bool) error {
+	provider.lock.Lock()
+	defer provider.lock.Unlock()
+	for _, iface := range provider.interfaces {
+		if iface.Name == name {
+			iface.Up = up
+			provider.notify()
+			return nil
+		}
+	}
+	return errors.New("FakeProvider.SetUp: no such interface " + name)
+}
+
+func (provider *FakeProvider) Status() (*KernelStatus, error) {
+	provider.lock.Lock()
+	defer provider.lock.Unlock()
+	status := &KernelStatus{
+		Interfaces: make([]*Interface, 0),
+		Vrfs:       make([]*Vrf, 0),
+	}
+	for _, iface := range provider.interfaces {
+		status.Interfaces = append(status.Interfaces, copyInterface(iface))
+	}
+	for _, vrf := range provider.vrfs {
+		v := *vrf
+		status.Vrfs = append(status.Vrfs, &v)
+	}
+	status.resolveVrfs()
+	return status, nil
+}
+
+func (provider *FakeProvider) Links() ([]string, error) {
+	provider.lock.Lock()
+	defer provider.lock.Unlock()
+	links := make([]string, 0)
+	for _, iface := range provider.interfaces {
+		links = append(links, iface.Name)
+	}
+	for _, vrf := range provider.vrfs {
+		links = append(links, vrf.Name)
+	}
+	return links, nil
+}
+
+func (provider *FakeProvider) Watch(statusCh chan<- *KernelStatus, doneCh <-chan struct{}) {
+	changedCh := make(chan struct{}, 1)
+	provider.lock.Lock()
+	provider.watchers = append(provider.watchers, changedCh)
+	provider.lock.Unlock()
+	defer func() {
+		provider.lock.Lock()
+		defer provider.lock.Unlock()
+		watchers := make([]chan struct{}, 0)
+		for _, watcher := range provider.watchers {
+			if watcher != changedCh {
+				watchers = append(watchers, watcher)
+			}
+		}
+		provider.watchers = watchers
+	}()
+	for {
+		status, _ := provider.Status()
+		select {
+		case statusCh <- status:
+		case <-doneCh:
+			return
+		}
+		select {
+		case <-changedCh:
+		case <-doneCh:
+			return
+		}
+	}
+}
+
+// ipv4RouteKey and ipv6RouteKey tell the routes apart the way the kernel
+// does, by table, prefix and priority, which is the metric: a route added
+// with another metric does not replace the one installed.
+func ipv4RouteKey(route *Ipv4Route) string {
+	return fmt.Sprintf("%08x/%08x/%d/%d", route.Table, route.Prefix, route.PrefixLength, route.Metric)
+}
+
+func ipv6RouteKey(route *Ipv6Route) string {
+	return fmt.Sprintf("%08x/%08x%08x%08x%08x/%d/%d", route.Table,
+		route.Prefix[0], route.Prefix[1], route.Prefix[2], route.Prefix[3], route.PrefixLength, route.Metric)
+}
+
+func (provider *FakeProvider) AddIpv4Route(route *Ipv4Route) error {
+	provider.lock.Lock()
+	defer provider.lock.Unlock()
+	if len(route.NextHops) == 0 {
+		return errors.New("FakeProvider.AddIpv4Route: no next hop")
+	}
+	r := *route
+	r.NextHops = append([]*Ipv4NextHop{}, route.NextHops...)
+	provider.ipv4Routes[ipv4RouteKey(route)] = &r
+	return nil
+}
+
+func (provider *FakeProvider) DeleteIpv4Route(route *Ipv4Route) error {
+	provider.lock.Lock()
+	defer provider.lock.Unlock()
+	if r, ok := provider.ipv4Routes[ipv4RouteKey(route)]; !ok || r.Protocol != route.Protocol {
+		return errors.New("FakeProvider.DeleteIpv4Route: no such route")
+	}
+	delete(provider.ipv4Routes, ipv4RouteKey(route))
+	return nil
+}
+
+func (provider *FakeProvider) AddIpv6Route(route *Ipv6Route) error {
+	provider.lock.Lock()
+	defer provider.lock.Unlock()
+	if len(route.NextHops) == 0 {
+		return errors.New("FakeProvider.AddIpv6Route: no next hop")
+	}
+	r := *route
+	r.NextHops = append([]*Ipv6NextHop{}, route.NextHops...)
+	provider.ipv6Routes[ipv6RouteKey(route)] = &r
+	return nil
+}
+
+func (provider *FakeProvider) DeleteIpv6Route(route *Ipv6Route) error {
+	provider.lock.Lock()
+	defer provider.lock.Unlock()
+	if r, ok := provider.ipv6Routes[ipv6RouteKey(route)]; !ok || r.Protocol != route.Protocol {
+		return errors.New("FakeProvider.DeleteIpv6Route: no such route")
+	}
+	delete(provider.ipv6Routes, ipv6RouteKey(route))
+	return nil
+}
+
+// Ipv4Routes returns the installed IPv4 routes ordered by table and
+// prefix.
+func (provider *FakeProvider) Ipv4Routes() []*Ipv4Route {
+	provider.lock.Lock()
+	defer provider.lock.Unlock()
+	keys := make([]string, 0)
+	for k := range provider.ipv4Routes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	routes := make([]*Ipv4Route, 0)
+	for _, k := range keys {
+		routes = append(routes, provider.ipv4Routes[k])
+	}
+	return routes
+}
+
+// Ipv6Routes returns the installed IPv6 routes ordered by table and
+// prefix.
+func (provider *FakeProvider) Ipv6Routes() []*Ipv6Route {
+	provider.lock.Lock()
+	defer provider.lock.Unlock()
+	keys := make([]string, 0)
+	for k := range provider.ipv6Routes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	routes := make([]*Ipv6Route, 0)
+	for _, k := range keys {
+		routes = append(routes, provider.ipv6Routes[k])
+	}
+	return routes
+}
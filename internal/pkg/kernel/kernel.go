@@ -18,13 +18,9 @@
 package kernel
 
 import (
-	"encoding/binary"
-	"net"
+	"sync"
 
 	log "github.com/sirupsen/logrus"
-
-	"github.com/vishvananda/netlink"
-	"golang.org/x/sys/unix"
 )
 
 type IfType int
@@ -58,138 +54,174 @@ type Interface struct {
 	Up            bool
 	Ipv4Addresses []*Ipv4Address
 	Ipv6Addresses []*Ipv6Address
+	// MasterIndex is the ifindex of the device the interface is
+	// enslaved to, such as a bridge or a VRF, or 0.
+	MasterIndex int
+	// Vrf is the name of the VRF the interface belongs to, or empty for
+	// the default VRF.
+	Vrf string
+}
+
+type Vrf struct {
+	IfIndex int
+	Name    string
+	Table   int
 }
 
 type KernelStatus struct {
 	Interfaces []*Interface
+	Vrfs       []*Vrf
 }
 
-func NewIpv4Address(addr *netlink.Addr) *Ipv4Address {
-	if len(addr.IP) != 4 {
-		return nil
+// Interface returns the interface name or nil.
+func (status *KernelStatus) Interface(name string) *Interface {
+	for _, iface := range status.Interfaces {
+		if iface.Name == name {
+			return iface
+		}
 	}
-	ipv4Address := &Ipv4Address{}
-	ipv4Address.Address = binary.BigEndian.Uint32(addr.IP[0:4])
-	ipv4Address.PrefixLength, _ = addr.Mask.Size()
-	ipv4Address.ScopeHost = (addr.Scope == unix.RT_SCOPE_HOST)
-	return ipv4Address
+	return nil
 }
 
-func NewIpv6Address(addr *netlink.Addr) *Ipv6Address {
-	if len(addr.IP) != 16 {
-		return nil
-	}
-	ipv6Address := &Ipv6Address{}
-	ipv6Address.Address[0] = binary.BigEndian.Uint32(addr.IP[0:4])
-	ipv6Address.Address[1] = binary.BigEndian.Uint32(addr.IP[4:8])
-	ipv6Address.Address[2] = binary.BigEndian.Uint32(addr.IP[8:12])
-	ipv6Address.Address[3] = binary.BigEndian.Uint32(addr.IP[12:16])
-	ipv6Address.PrefixLength, _ = addr.Mask.Size()
-	ipv6Address.ScopeLink = (addr.Scope == unix.RT_SCOPE_LINK)
-	ipv6Address.ScopeHost = (addr.Scope == unix.RT_SCOPE_HOST)
-	return ipv6Address
-}
-
-func ifType(flags net.Flags) IfType {
-	var ifType IfType
-	if (flags & net.FlagLoopback) != 0 {
-		ifType = IF_TYPE_LOOPBACK
-	}
-	if (flags & net.FlagBroadcast) != 0 {
-		ifType = IF_TYPE_BROADCAST
-	}
-	if (flags & net.FlagPointToPoint) != 0 {
-		ifType = IF_TYPE_POINTTOPOINT
+// Vrf returns the VRF name or nil.
+func (status *KernelStatus) Vrf(name string) *Vrf {
+	for _, vrf := range status.Vrfs {
+		if vrf.Name == name {
+			return vrf
+		}
 	}
-	return ifType
+	return nil
 }
 
-func NewInterface(attrs *netlink.LinkAttrs) *Interface {
-	if attrs == nil {
-		return nil
+// resolveVrfs sets Vrf of the interfaces enslaved to a VRF device.
+func (status *KernelStatus) resolveVrfs() {
+	for _, iface := range status.Interfaces {
+		iface.Vrf = ""
+		for _, vrf := range status.Vrfs {
+			if iface.MasterIndex != 0 && iface.MasterIndex == vrf.IfIndex {
+				iface.Vrf = vrf.Name
+			}
+		}
 	}
-	ifType := ifType(attrs.Flags)
-	if ifType == 0 {
-		return nil
+}
+
+type RouteProtocol int
+
+const (
+	ROUTE_PROTOCOL_ISIS RouteProtocol = 187
+	ROUTE_PROTOCOL_OSPF RouteProtocol = 188
+)
+
+func (protocol RouteProtocol) String() string {
+	switch protocol {
+	case ROUTE_PROTOCOL_ISIS:
+		return "ROUTE_PROTOCOL_ISIS"
+	case ROUTE_PROTOCOL_OSPF:
+		return "ROUTE_PROTOCOL_OSPF"
 	}
-	iface := &Interface{}
-	iface.IfIndex = attrs.Index
-	iface.Name = attrs.Name
-	iface.IfType = ifType
-	iface.HardwareAddr = attrs.HardwareAddr
-	iface.Mtu = attrs.MTU
-	iface.Up = ((attrs.Flags & net.FlagUp) != 0)
-	iface.Ipv4Addresses = make([]*Ipv4Address, 0)
-	iface.Ipv6Addresses = make([]*Ipv6Address, 0)
-	return iface
+	return "ROUTE_PROTOCOL_UNKNOWN"
 }
 
-func NewKernelStatus() *KernelStatus {
-	status := &KernelStatus{}
-	status.Interfaces = make([]*Interface, 0)
+type Ipv4NextHop struct {
+	Address uint32
+	IfIndex int
+}
+
+// Table of a route is the routing table it is installed into, 0 being
+// the main table. Distance is the administrative distance a Manager
+// chooses between the routes of the protocols to the same prefix by, the
+// lower the better; it is not installed.
+type Ipv4Route struct {
+	Table        int
+	Protocol     RouteProtocol
+	Prefix       uint32
+	PrefixLength int
+	Metric       uint32
+	Distance     uint8
+	NextHops     []*Ipv4NextHop
+}
+
+type Ipv6NextHop struct {
+	Address [4]uint32
+	IfIndex int
+}
+
+type Ipv6Route struct {
+	Table        int
+	Protocol     RouteProtocol
+	Prefix       [4]uint32
+	PrefixLength int
+	Metric       uint32
+	Distance     uint8
+	NextHops     []*Ipv6NextHop
+}
+
+// Provider is what the daemons need from the kernel: the interfaces with
+// their addresses, notifications when they change and a FIB to program.
+// NewNetlinkProvider talks to the host; NewFakeProvider keeps everything
+// in memory for tests.
+type Provider interface {
+	Status() (*KernelStatus, error)
+	// Links returns the names of all the links, including the ones
+	// Status leaves out for not being broadcast, point-to-point or
+	// loopback.
+	Links() ([]string, error)
+	// Watch sends the current status to statusCh and then a new one
+	// each time a link or an address changes, until doneCh is closed.
+	Watch(statusCh chan<- *KernelStatus, doneCh <-chan struct{})
+	// AddIpv4Route installs route, replacing the one of the same
+	// protocol and prefix if any.
+	AddIpv4Route(route *Ipv4Route) error
+	DeleteIpv4Route(route *Ipv4Route) error
+	AddIpv6Route(route *Ipv6Route) error
+	DeleteIpv6Route(route *Ipv6Route) error
+}
+
+var defaultProvider Provider = NewNetlinkProvider()
+var defaultProviderLock sync.RWMutex
 
-	links, err := netlink.LinkList()
+// DefaultProvider returns the provider used by the functions of this
+// package, which is the host's netlink unless SetDefaultProvider was
+// called.
+func DefaultProvider() Provider {
+	defaultProviderLock.RLock()
+	defer defaultProviderLock.RUnlock()
+	return defaultProvider
+}
+
+// SetDefaultProvider replaces the provider used by the functions of this
+// package, such as the one config validation relies on.
+func SetDefaultProvider(provider Provider) {
+	defaultProviderLock.Lock()
+	defer defaultProviderLock.Unlock()
+	defaultProvider = provider
+}
+
+func NewKernelStatus() *KernelStatus {
+	status, err := DefaultProvider().Status()
 	if err != nil {
+		log.WithFields(log.Fields{
+			"Topic": "Kernel",
+			"Error": err,
+		}).Warn("Can't get kernel status")
 		return nil
 	}
-	for _, link := range links {
-		iface := NewInterface(link.Attrs())
-		if iface == nil {
-			continue
-		}
-		addr4s, err := netlink.AddrList(link, unix.AF_INET)
-		if err != nil {
-			return nil
-		}
-		for _, addr4 := range addr4s {
-			ipv4Address := NewIpv4Address(&addr4)
-			if ipv4Address != nil {
-				iface.Ipv4Addresses = append(iface.Ipv4Addresses, ipv4Address)
-			}
-		}
-		addr6s, err := netlink.AddrList(link, unix.AF_INET6)
-		if err != nil {
-			return nil
-		}
-		for _, addr6 := range addr6s {
-			ipv6Address := NewIpv6Address(&addr6)
-			if ipv6Address != nil {
-				iface.Ipv6Addresses = append(iface.Ipv6Addresses, ipv6Address)
-			}
-		}
-		status.Interfaces = append(status.Interfaces, iface)
-	}
-
 	return status
 }
 
 func Serve(status chan<- *KernelStatus) {
 	log.Debugf("enter")
 	defer log.Debugf("exit")
-	addrCh := make(chan netlink.AddrUpdate)
-	addrDone := make(chan struct{})
-	netlink.AddrSubscribe(addrCh, addrDone)
-	linkCh := make(chan netlink.LinkUpdate)
-	linkDone := make(chan struct{})
-	netlink.LinkSubscribe(linkCh, linkDone)
-	status <- NewKernelStatus()
-	for {
-		select {
-		case <-addrCh:
-		case <-linkCh:
-		}
-		status <- NewKernelStatus()
-	}
+	DefaultProvider().Watch(status, make(chan struct{}))
 }
 
 func IfaceExists(name string) bool {
-	links, err := netlink.LinkList()
+	links, err := DefaultProvider().Links()
 	if err != nil {
 		return false
 	}
 	for _, link := range links {
-		attrs := link.Attrs()
-		if attrs.Name == name {
+		if link == name {
 			return true
 		}
 	}
@@ -197,15 +229,12 @@ func IfaceExists(name string) bool {
 }
 
 func IfaceType(name string) IfType {
-	links, err := netlink.LinkList()
+	status, err := DefaultProvider().Status()
 	if err != nil {
 		return IfType(0)
 	}
-	for _, link := range links {
-		attrs := link.Attrs()
-		if attrs.Name == name {
-			return ifType(attrs.Flags)
-		}
+	if iface := status.Interface(name); iface != nil {
+		return iface.IfType
 	}
 	return IfType(0)
 }
@@ -21,6 +21,7 @@ import (
 	"bytes"
 	"fmt"
 	"testing"
+	"time"
 )
 
 func TestKernel(t *testing.T) {
@@ -42,3 +43,282 @@ func TestKernel(t *testing.T) {
 	}
 	//t.Fatalf("\n%s", b.String())
 }
+
+func TestFakeProvider(t *testing.T) {
+	fake := NewFakeProvider()
+	fake.SetInterface(&Interface{
+		IfIndex: 2,
+		Name:    "eth0",
+		IfType:  IF_TYPE_BROADCAST,
+		Up:      true,
+		Ipv4Addresses: []*Ipv4Address{
+			&Ipv4Address{Address: 0x0a000001, PrefixLength: 24},
+		},
+	})
+
+	statusCh := make(chan *KernelStatus)
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+	go fake.Watch(statusCh, doneCh)
+	status := <-statusCh
+	if iface := status.Interface("eth0"); iface == nil || !iface.Up ||
+		len(iface.Ipv4Addresses) != 1 {
+		t.Fatalf("failed initial status: %#v", status)
+	}
+	if err := fake.SetUp("eth0", false); err != nil {
+		t.Fatalf("failed SetUp: %#v", err)
+	}
+	select {
+	case status = <-statusCh:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("failed Watch: no status after SetUp")
+	}
+	if iface := status.Interface("eth0"); iface == nil || iface.Up {
+		t.Fatalf("failed status after SetUp: %#v", status)
+	}
+	if err := fake.SetUp("eth1", true); err == nil {
+		t.Fatalf("failed SetUp: unknown interface accepted")
+	}
+
+	route := &Ipv4Route{
+		Protocol:     ROUTE_PROTOCOL_ISIS,
+		Prefix:       0xc0a80002,
+		PrefixLength: 32,
+		Metric:       20,
+		NextHops:     []*Ipv4NextHop{&Ipv4NextHop{Address: 0x0a000002, IfIndex: 2}},
+	}
+	if err := fake.AddIpv4Route(route); err != nil {
+		t.Fatalf("failed AddIpv4Route: %#v", err)
+	}
+	route.NextHops = []*Ipv4NextHop{&Ipv4NextHop{Address: 0x0a000003, IfIndex: 2}}
+	if err := fake.AddIpv4Route(route); err != nil {
+		t.Fatalf("failed AddIpv4Route: %#v", err)
+	}
+	if routes := fake.Ipv4Routes(); len(routes) != 1 || routes[0].NextHops[0].Address != 0x0a000003 {
+		t.Fatalf("failed Ipv4Routes: %#v", routes)
+	}
+	// like the kernel, a route of another metric is added beside it
+	route.Metric = 30
+	if err := fake.AddIpv4Route(route); err != nil {
+		t.Fatalf("failed AddIpv4Route: %#v", err)
+	}
+	if routes := fake.Ipv4Routes(); len(routes) != 2 {
+		t.Fatalf("failed Ipv4Routes after metric change: %#v", routes)
+	}
+	if err := fake.DeleteIpv4Route(route); err != nil {
+		t.Fatalf("failed DeleteIpv4Route: %#v", err)
+	}
+	route.Metric = 20
+	if err := fake.DeleteIpv4Route(route); err != nil {
+		t.Fatalf("failed DeleteIpv4Route: %#v", err)
+	}
+	if routes := fake.Ipv4Routes(); len(routes) != 0 {
+		t.Fatalf("failed Ipv4Routes after delete: %#v", routes)
+	}
+	if err := fake.DeleteIpv4Route(route); err == nil {
+		t.Fatalf("failed DeleteIpv4Route: unknown route accepted")
+	}
+}
+
+func TestFakeProviderVrf(t *testing.T) {
+	fake := NewFakeProvider()
+	fake.SetVrf(&Vrf{IfIndex: 10, Name: "blue", Table: 100})
+	fake.SetInterface(&Interface{IfIndex: 2, Name: "eth0", IfType: IF_TYPE_BROADCAST})
+	status, _ := fake.Status()
+	if iface := status.Interface("eth0"); iface == nil || iface.Vrf != "" {
+		t.Fatalf("failed default vrf: %#v", iface)
+	}
+	if err := fake.SetMaster("eth0", 10); err != nil {
+		t.Fatalf("failed SetMaster: %#v", err)
+	}
+	status, _ = fake.Status()
+	if iface := status.Interface("eth0"); iface == nil || iface.Vrf != "blue" {
+		t.Fatalf("failed vrf: %#v", iface)
+	}
+	if vrf := status.Vrf("blue"); vrf == nil || vrf.Table != 100 {
+		t.Fatalf("failed Vrf: %#v", vrf)
+	}
+}
+
+func TestIfaceExists(t *testing.T) {
+	fake := NewFakeProvider()
+	fake.SetVrf(&Vrf{IfIndex: 10, Name: "blue", Table: 100})
+	fake.SetInterface(&Interface{IfIndex: 2, Name: "eth0", IfType: IF_TYPE_BROADCAST})
+	provider := DefaultProvider()
+	SetDefaultProvider(fake)
+	defer SetDefaultProvider(provider)
+	// the VRF device is not in the status but is a link all the same
+	if !IfaceExists("eth0") || !IfaceExists("blue") {
+		t.Fatalf("failed IfaceExists: existing link not found")
+	}
+	if IfaceExists("eth1") {
+		t.Fatalf("failed IfaceExists: unknown link found")
+	}
+}
+
+func TestManager(t *testing.T) {
+	fake := NewFakeProvider()
+	fake.SetInterface(&Interface{IfIndex: 2, Name: "eth0", IfType: IF_TYPE_BROADCAST, Up: true})
+	manager := NewManager(fake)
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+	go manager.Serve(doneCh)
+
+	// every watcher gets the status from the one watch of fake
+	statusChs := []chan *KernelStatus{make(chan *KernelStatus), make(chan *KernelStatus)}
+	for _, statusCh := range statusChs {
+		go manager.Watch(statusCh, doneCh)
+		if status := <-statusCh; status.Interface("eth0") == nil {
+			t.Fatalf("failed initial status: %#v", status)
+		}
+	}
+	watchers := func() int {
+		fake.lock.Lock()
+		defer fake.lock.Unlock()
+		return len(fake.watchers)
+	}
+	for i := 0; watchers() != 1; i++ {
+		if i == 50 {
+			t.Fatalf("failed watchers of fake: %d", watchers())
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	fake.SetUp("eth0", false)
+	for _, statusCh := range statusChs {
+		// a status may still be on its way from before SetUp
+		for down := false; !down; {
+			select {
+			case status := <-statusCh:
+				iface := status.Interface("eth0")
+				down = iface != nil && !iface.Up
+			case <-time.After(5 * time.Second):
+				t.Fatalf("failed Watch: no status after SetUp")
+			}
+		}
+	}
+
+	isisClient := manager.NewClient()
+	ospfClient := manager.NewClient()
+	isis := &Ipv4Route{
+		Protocol:     ROUTE_PROTOCOL_ISIS,
+		Prefix:       0xc0a80002,
+		PrefixLength: 32,
+		Metric:       20,
+		Distance:     115,
+		NextHops:     []*Ipv4NextHop{&Ipv4NextHop{Address: 0x0a000002, IfIndex: 2}},
+	}
+	ospf := &Ipv4Route{
+		Protocol:     ROUTE_PROTOCOL_OSPF,
+		Prefix:       0xc0a80002,
+		PrefixLength: 32,
+		Metric:       30,
+		Distance:     110,
+		NextHops:     []*Ipv4NextHop{&Ipv4NextHop{Address: 0x0a000003, IfIndex: 2}},
+	}
+	if err := isisClient.AddIpv4Route(isis); err != nil {
+		t.Fatalf("failed AddIpv4Route: %#v", err)
+	}
+	if err := ospfClient.AddIpv4Route(ospf); err != nil {
+		t.Fatalf("failed AddIpv4Route: %#v", err)
+	}
+	if routes := fake.Ipv4Routes(); len(routes) != 1 || routes[0].Protocol != ROUTE_PROTOCOL_OSPF {
+		t.Fatalf("failed Ipv4Routes: %#v", routes)
+	}
+	// the IS-IS route wins once its distance is the lower
+	isis.Distance = 100
+	if err := isisClient.AddIpv4Route(isis); err != nil {
+		t.Fatalf("failed AddIpv4Route: %#v", err)
+	}
+	if routes := fake.Ipv4Routes(); len(routes) != 1 || routes[0].Protocol != ROUTE_PROTOCOL_ISIS {
+		t.Fatalf("failed Ipv4Routes after distance change: %#v", routes)
+	}
+	// and the OSPF one is installed again when it is withdrawn
+	if err := isisClient.DeleteIpv4Route(isis); err != nil {
+		t.Fatalf("failed DeleteIpv4Route: %#v", err)
+	}
+	if routes := fake.Ipv4Routes(); len(routes) != 1 || routes[0].Protocol != ROUTE_PROTOCOL_OSPF {
+		t.Fatalf("failed Ipv4Routes after delete: %#v", routes)
+	}
+	if err := isisClient.DeleteIpv4Route(isis); err == nil {
+		t.Fatalf("failed DeleteIpv4Route: unknown route accepted")
+	}
+	if err := ospfClient.DeleteIpv4Route(ospf); err != nil {
+		t.Fatalf("failed DeleteIpv4Route: %#v", err)
+	}
+	if routes := fake.Ipv4Routes(); len(routes) != 0 {
+		t.Fatalf("failed Ipv4Routes after delete: %#v", routes)
+	}
+}
+
+func TestManagerClients(t *testing.T) {
+	fake := NewFakeProvider()
+	fake.SetInterface(&Interface{IfIndex: 2, Name: "eth0", IfType: IF_TYPE_BROADCAST, Up: true})
+	manager := NewManager(fake)
+	c1 := manager.NewClient()
+	c2 := manager.NewClient()
+
+	// two instances of the same protocol add routes to the same prefix
+	r1 := &Ipv4Route{
+		Protocol:     ROUTE_PROTOCOL_ISIS,
+		Prefix:       0xc0a80002,
+		PrefixLength: 32,
+		Metric:       20,
+		Distance:     115,
+		NextHops:     []*Ipv4NextHop{&Ipv4NextHop{Address: 0x0a000002, IfIndex: 2}},
+	}
+	r2 := &Ipv4Route{
+		Protocol:     ROUTE_PROTOCOL_ISIS,
+		Prefix:       0xc0a80002,
+		PrefixLength: 32,
+		Metric:       30,
+		Distance:     115,
+		NextHops:     []*Ipv4NextHop{&Ipv4NextHop{Address: 0x0a000003, IfIndex: 2}},
+	}
+	if err := c2.AddIpv4Route(r2); err != nil {
+		t.Fatalf("failed AddIpv4Route: %#v", err)
+	}
+	if err := c1.AddIpv4Route(r1); err != nil {
+		t.Fatalf("failed AddIpv4Route: %#v", err)
+	}
+	// the older client wins the tie
+	if routes := fake.Ipv4Routes(); len(routes) != 1 || routes[0].Metric != 20 {
+		t.Fatalf("failed Ipv4Routes: %#v", routes)
+	}
+	// and its withdrawal leaves the route of the other client installed
+	if err := c1.DeleteIpv4Route(r1); err != nil {
+		t.Fatalf("failed DeleteIpv4Route: %#v", err)
+	}
+	if routes := fake.Ipv4Routes(); len(routes) != 1 || routes[0].Metric != 30 {
+		t.Fatalf("failed Ipv4Routes after delete: %#v", routes)
+	}
+	if err := c1.DeleteIpv4Route(r1); err == nil {
+		t.Fatalf("failed DeleteIpv4Route: route of another client deleted")
+	}
+	if routes := fake.Ipv4Routes(); len(routes) != 1 || routes[0].Metric != 30 {
+		t.Fatalf("failed Ipv4Routes after second delete: %#v", routes)
+	}
+	if err := c2.DeleteIpv4Route(r2); err != nil {
+		t.Fatalf("failed DeleteIpv4Route: %#v", err)
+	}
+	if routes := fake.Ipv4Routes(); len(routes) != 0 {
+		t.Fatalf("failed Ipv4Routes after delete: %#v", routes)
+	}
+
+	r6 := &Ipv6Route{
+		Protocol:     ROUTE_PROTOCOL_ISIS,
+		Prefix:       [4]uint32{0x20010db8, 0, 0, 1},
+		PrefixLength: 128,
+		Metric:       20,
+		Distance:     115,
+		NextHops:     []*Ipv6NextHop{&Ipv6NextHop{IfIndex: 2}},
+	}
+	if err := c1.AddIpv6Route(r6); err != nil {
+		t.Fatalf("failed AddIpv6Route: %#v", err)
+	}
+	if err := c2.DeleteIpv6Route(r6); err == nil {
+		t.Fatalf("failed DeleteIpv6Route: route of another client deleted")
+	}
+	if routes := fake.Ipv6Routes(); len(routes) != 1 {
+		t.Fatalf("failed Ipv6Routes: %#v", routes)
+	}
+}
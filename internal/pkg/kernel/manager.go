@@ -0,0 +1,355 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kernel
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Manager is a Provider shared by the protocols of a daemon. It watches
+// the provider under it once for all of them and, of the routes they add
+// to the same prefix, installs only the one of the lowest Distance, the
+// lower protocol number and then the older client breaking a tie. Each
+// protocol instance adds its routes through its own client returned by
+// NewClient, so that instances of the same protocol do not replace each
+// other's routes; the routes added to manager itself belong to client 0.
+type Manager struct {
+	provider Provider
+	lock     sync.Mutex
+	watchers []chan struct{}
+	clients  int
+	// ipv4Ribs are the routes added, by prefix and then by client, and
+	// ipv4Fib the ones of them installed, by prefix.
+	ipv4Ribs map[string]map[int]*Ipv4Route
+	ipv4Fib  map[string]*Ipv4Route
+	ipv6Ribs map[string]map[int]*Ipv6Route
+	ipv6Fib  map[string]*Ipv6Route
+}
+
+func NewManager(provider Provider) *Manager {
+	return &Manager{
+		provider: provider,
+		watchers: make([]chan struct{}, 0),
+		ipv4Ribs: make(map[string]map[int]*Ipv4Route),
+		ipv4Fib:  make(map[string]*Ipv4Route),
+		ipv6Ribs: make(map[string]map[int]*Ipv6Route),
+		ipv6Fib:  make(map[string]*Ipv6Route),
+	}
+}
+
+// ManagerClient is the Provider a protocol instance gets from a Manager.
+// It shares the status and the watch of the manager and owns the routes
+// added through it.
+type ManagerClient struct {
+	manager *Manager
+	id      int
+}
+
+// NewClient returns a new client of manager.
+func (manager *Manager) NewClient() *ManagerClient {
+	manager.lock.Lock()
+	defer manager.lock.Unlock()
+	manager.clients++
+	return &ManagerClient{
+		manager: manager,
+		id:      manager.clients,
+	}
+}
+
+func (client *ManagerClient) Status() (*KernelStatus, error) {
+	return client.manager.Status()
+}
+
+func (client *ManagerClient) Links() ([]string, error) {
+	return client.manager.Links()
+}
+
+func (client *ManagerClient) Watch(statusCh chan<- *KernelStatus, doneCh <-chan struct{}) {
+	client.manager.Watch(statusCh, doneCh)
+}
+
+func (client *ManagerClient) AddIpv4Route(route *Ipv4Route) error {
+	return client.manager.addIpv4Route(client.id, route)
+}
+
+func (client *ManagerClient) DeleteIpv4Route(route *Ipv4Route) error {
+	return client.manager.deleteIpv4Route(client.id, route)
+}
+
+func (client *ManagerClient) AddIpv6Route(route *Ipv6Route) error {
+	return client.manager.addIpv6Route(client.id, route)
+}
+
+func (client *ManagerClient) DeleteIpv6Route(route *Ipv6Route) error {
+	return client.manager.deleteIpv6Route(client.id, route)
+}
+
+// Serve watches the provider under manager and notifies the watchers of
+// every change until doneCh is closed.
+func (manager *Manager) Serve(doneCh <-chan struct{}) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	statusCh := make(chan *KernelStatus)
+	go manager.provider.Watch(statusCh, doneCh)
+	for {
+		select {
+		case <-statusCh:
+			manager.notify()
+		case <-doneCh:
+			return
+		}
+	}
+}
+
+func (manager *Manager) notify() {
+	manager.lock.Lock()
+	defer manager.lock.Unlock()
+	for _, watcher := range manager.watchers {
+		select {
+		case watcher <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (manager *Manager) Status() (*KernelStatus, error) {
+	return manager.provider.Status()
+}
+
+func (manager *Manager) Links() ([]string, error) {
+	return manager.provider.Links()
+}
+
+// Watch sends the current status to statusCh and then a new one each time
+// Serve is notified of a change, so that the watchers share the one watch
+// of the provider under manager.
+func (manager *Manager) Watch(statusCh chan<- *KernelStatus, doneCh <-chan struct{}) {
+	changedCh := make(chan struct{}, 1)
+	manager.lock.Lock()
+	manager.watchers = append(manager.watchers, changedCh)
+	manager.lock.Unlock()
+	defer func() {
+		manager.lock.Lock()
+		defer manager.lock.Unlock()
+		watchers := make([]chan struct{}, 0)
+		for _, watcher := range manager.watchers {
+			if watcher != changedCh {
+				watchers = append(watchers, watcher)
+			}
+		}
+		manager.watchers = watchers
+	}()
+	for {
+		status, err := manager.provider.Status()
+		if err != nil {
+			log.WithFields(log.Fields{
+				"Topic": "Kernel",
+				"Error": err,
+			}).Warn("Can't get kernel status")
+		} else {
+			select {
+			case statusCh <- status:
+			case <-doneCh:
+				return
+			}
+		}
+		select {
+		case <-changedCh:
+		case <-doneCh:
+			return
+		}
+	}
+}
+
+func ipv4PrefixKey(route *Ipv4Route) string {
+	return fmt.Sprintf("%08x/%08x/%d", route.Table, route.Prefix, route.PrefixLength)
+}
+
+func ipv6PrefixKey(route *Ipv6Route) string {
+	return fmt.Sprintf("%08x/%08x%08x%08x%08x/%d", route.Table,
+		route.Prefix[0], route.Prefix[1], route.Prefix[2], route.Prefix[3], route.PrefixLength)
+}
+
+func betterIpv4Route(a *Ipv4Route, aId int, b *Ipv4Route, bId int) bool {
+	if a.Distance != b.Distance {
+		return a.Distance < b.Distance
+	}
+	if a.Protocol != b.Protocol {
+		return a.Protocol < b.Protocol
+	}
+	return aId < bId
+}
+
+func betterIpv6Route(a *Ipv6Route, aId int, b *Ipv6Route, bId int) bool {
+	if a.Distance != b.Distance {
+		return a.Distance < b.Distance
+	}
+	if a.Protocol != b.Protocol {
+		return a.Protocol < b.Protocol
+	}
+	return aId < bId
+}
+
+// selectIpv4Route installs the best of the routes to the prefix of key in
+// place of the one installed, if they differ.
+func (manager *Manager) selectIpv4Route(key string) error {
+	var best *Ipv4Route
+	bestId := 0
+	for id, route := range manager.ipv4Ribs[key] {
+		if best == nil || betterIpv4Route(route, id, best, bestId) {
+			best = route
+			bestId = id
+		}
+	}
+	installed, ok := manager.ipv4Fib[key]
+	if ok && reflect.DeepEqual(installed, best) {
+		return nil
+	}
+	// the kernel keeps a route of another metric beside the installed
+	// one instead of replacing it
+	if ok && (best == nil || installed.Protocol != best.Protocol || installed.Metric != best.Metric) {
+		if err := manager.provider.DeleteIpv4Route(installed); err != nil {
+			log.WithFields(log.Fields{
+				"Topic": "Fib",
+				"Route": key,
+				"Error": err,
+			}).Warn("Can't delete route")
+		}
+		delete(manager.ipv4Fib, key)
+	}
+	if best == nil {
+		return nil
+	}
+	if err := manager.provider.AddIpv4Route(best); err != nil {
+		return err
+	}
+	manager.ipv4Fib[key] = best
+	return nil
+}
+
+// selectIpv6Route installs the best of the routes to the prefix of key in
+// place of the one installed, if they differ.
+func (manager *Manager) selectIpv6Route(key string) error {
+	var best *Ipv6Route
+	bestId := 0
+	for id, route := range manager.ipv6Ribs[key] {
+		if best == nil || betterIpv6Route(route, id, best, bestId) {
+			best = route
+			bestId = id
+		}
+	}
+	installed, ok := manager.ipv6Fib[key]
+	if ok && reflect.DeepEqual(installed, best) {
+		return nil
+	}
+	// the kernel keeps a route of another metric beside the installed
+	// one instead of replacing it
+	if ok && (best == nil || installed.Protocol != best.Protocol || installed.Metric != best.Metric) {
+		if err := manager.provider.DeleteIpv6Route(installed); err != nil {
+			log.WithFields(log.Fields{
+				"Topic": "Fib",
+				"Route": key,
+				"Error": err,
+			}).Warn("Can't delete route")
+		}
+		delete(manager.ipv6Fib, key)
+	}
+	if best == nil {
+		return nil
+	}
+	if err := manager.provider.AddIpv6Route(best); err != nil {
+		return err
+	}
+	manager.ipv6Fib[key] = best
+	return nil
+}
+
+func (manager *Manager) AddIpv4Route(route *Ipv4Route) error {
+	return manager.addIpv4Route(0, route)
+}
+
+func (manager *Manager) DeleteIpv4Route(route *Ipv4Route) error {
+	return manager.deleteIpv4Route(0, route)
+}
+
+func (manager *Manager) addIpv4Route(id int, route *Ipv4Route) error {
+	manager.lock.Lock()
+	defer manager.lock.Unlock()
+	key := ipv4PrefixKey(route)
+	if _, ok := manager.ipv4Ribs[key]; !ok {
+		manager.ipv4Ribs[key] = make(map[int]*Ipv4Route)
+	}
+	r := *route
+	r.NextHops = append([]*Ipv4NextHop{}, route.NextHops...)
+	manager.ipv4Ribs[key][id] = &r
+	return manager.selectIpv4Route(key)
+}
+
+func (manager *Manager) deleteIpv4Route(id int, route *Ipv4Route) error {
+	manager.lock.Lock()
+	defer manager.lock.Unlock()
+	key := ipv4PrefixKey(route)
+	if _, ok := manager.ipv4Ribs[key][id]; !ok {
+		return errors.New("Manager.DeleteIpv4Route: no such route")
+	}
+	delete(manager.ipv4Ribs[key], id)
+	if len(manager.ipv4Ribs[key]) == 0 {
+		delete(manager.ipv4Ribs, key)
+	}
+	return manager.selectIpv4Route(key)
+}
+
+func (manager *Manager) AddIpv6Route(route *Ipv6Route) error {
+	return manager.addIpv6Route(0, route)
+}
+
+func (manager *Manager) DeleteIpv6Route(route *Ipv6Route) error {
+	return manager.deleteIpv6Route(0, route)
+}
+
+func (manager *Manager) addIpv6Route(id int, route *Ipv6Route) error {
+	manager.lock.Lock()
+	defer manager.lock.Unlock()
+	key := ipv6PrefixKey(route)
+	if _, ok := manager.ipv6Ribs[key]; !ok {
+		manager.ipv6Ribs[key] = make(map[int]*Ipv6Route)
+	}
+	r := *route
+	r.NextHops = append([]*Ipv6NextHop{}, route.NextHops...)
+	manager.ipv6Ribs[key][id] = &r
+	return manager.selectIpv6Route(key)
+}
+
+func (manager *Manager) deleteIpv6Route(id int, route *Ipv6Route) error {
+	manager.lock.Lock()
+	defer manager.lock.Unlock()
+	key := ipv6PrefixKey(route)
+	if _, ok := manager.ipv6Ribs[key][id]; !ok {
+		return errors.New("Manager.DeleteIpv6Route: no such route")
+	}
+	delete(manager.ipv6Ribs[key], id)
+	if len(manager.ipv6Ribs[key]) == 0 {
+		delete(manager.ipv6Ribs, key)
+	}
+	return manager.selectIpv6Route(key)
+}
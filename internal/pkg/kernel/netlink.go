@@ -0,0 +1,343 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kernel
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+	"runtime"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+	"golang.org/x/sys/unix"
+)
+
+func NewIpv4Address(addr *netlink.Addr) *Ipv4Address {
+	if len(addr.IP) != 4 {
+		return nil
+	}
+	ipv4Address := &Ipv4Address{}
+	ipv4Address.Address = binary.BigEndian.Uint32(addr.IP[0:4])
+	ipv4Address.PrefixLength, _ = addr.Mask.Size()
+	ipv4Address.ScopeHost = (addr.Scope == unix.RT_SCOPE_HOST)
+	return ipv4Address
+}
+
+func NewIpv6Address(addr *netlink.Addr) *Ipv6Address {
+	if len(addr.IP) != 16 {
+		return nil
+	}
+	ipv6Address := &Ipv6Address{}
+	ipv6Address.Address[0] = binary.BigEndian.Uint32(addr.IP[0:4])
+	ipv6Address.Address[1] = binary.BigEndian.Uint32(addr.IP[4:8])
+	ipv6Address.Address[2] = binary.BigEndian.Uint32(addr.IP[8:12])
+	ipv6Address.Address[3] = binary.BigEndian.Uint32(addr.IP[12:16])
+	ipv6Address.PrefixLength, _ = addr.Mask.Size()
+	ipv6Address.ScopeLink = (addr.Scope == unix.RT_SCOPE_LINK)
+	ipv6Address.ScopeHost = (addr.Scope == unix.RT_SCOPE_HOST)
+	return ipv6Address
+}
+
+func ifType(flags net.Flags) IfType {
+	var ifType IfType
+	if (flags & net.FlagLoopback) != 0 {
+		ifType = IF_TYPE_LOOPBACK
+	}
+	if (flags & net.FlagBroadcast) != 0 {
+		ifType = IF_TYPE_BROADCAST
+	}
+	if (flags & net.FlagPointToPoint) != 0 {
+		ifType = IF_TYPE_POINTTOPOINT
+	}
+	return ifType
+}
+
+func NewInterface(attrs *netlink.LinkAttrs) *Interface {
+	if attrs == nil {
+		return nil
+	}
+	ifType := ifType(attrs.Flags)
+	if ifType == 0 {
+		return nil
+	}
+	iface := &Interface{}
+	iface.IfIndex = attrs.Index
+	iface.Name = attrs.Name
+	iface.IfType = ifType
+	iface.HardwareAddr = attrs.HardwareAddr
+	iface.Mtu = attrs.MTU
+	iface.Up = ((attrs.Flags & net.FlagUp) != 0)
+	iface.MasterIndex = attrs.MasterIndex
+	iface.Ipv4Addresses = make([]*Ipv4Address, 0)
+	iface.Ipv6Addresses = make([]*Ipv6Address, 0)
+	return iface
+}
+
+type netlinkProvider struct {
+	ns     netns.NsHandle
+	handle *netlink.Handle
+}
+
+// NewNetlinkProvider returns the Provider of the host's network stack.
+func NewNetlinkProvider() Provider {
+	return &netlinkProvider{
+		ns:     netns.None(),
+		handle: &netlink.Handle{},
+	}
+}
+
+// NewNetnsProvider returns the Provider of the network namespace name,
+// as created by "ip netns add".
+func NewNetnsProvider(name string) (Provider, error) {
+	ns, err := netns.GetFromName(name)
+	if err != nil {
+		return nil, err
+	}
+	handle, err := netlink.NewHandleAt(ns)
+	if err != nil {
+		ns.Close()
+		return nil, err
+	}
+	return &netlinkProvider{
+		ns:     ns,
+		handle: handle,
+	}, nil
+}
+
+// InNamespace calls f with the calling thread switched to the network
+// namespace name, so that the sockets f opens belong to it. They stay in
+// it after InNamespace returns.
+func InNamespace(name string, f func() error) error {
+	runtime.LockOSThread()
+	origin, err := netns.Get()
+	if err != nil {
+		runtime.UnlockOSThread()
+		return err
+	}
+	defer origin.Close()
+	ns, err := netns.GetFromName(name)
+	if err != nil {
+		runtime.UnlockOSThread()
+		return err
+	}
+	defer ns.Close()
+	if err = netns.Set(ns); err != nil {
+		runtime.UnlockOSThread()
+		return err
+	}
+	ferr := f()
+	if err = netns.Set(origin); err != nil {
+		// leave the thread locked so that it is thrown away with
+		// the goroutine instead of running others in the namespace
+		return err
+	}
+	runtime.UnlockOSThread()
+	return ferr
+}
+
+func (provider *netlinkProvider) Status() (*KernelStatus, error) {
+	status := &KernelStatus{}
+	status.Interfaces = make([]*Interface, 0)
+	status.Vrfs = make([]*Vrf, 0)
+
+	links, err := provider.handle.LinkList()
+	if err != nil {
+		return nil, err
+	}
+	for _, link := range links {
+		if vrf, ok := link.(*netlink.Vrf); ok {
+			status.Vrfs = append(status.Vrfs, &Vrf{
+				IfIndex: vrf.Attrs().Index,
+				Name:    vrf.Attrs().Name,
+				Table:   int(vrf.Table),
+			})
+			continue
+		}
+		iface := NewInterface(link.Attrs())
+		if iface == nil {
+			continue
+		}
+		addr4s, err := provider.handle.AddrList(link, unix.AF_INET)
+		if err != nil {
+			return nil, err
+		}
+		for _, addr4 := range addr4s {
+			ipv4Address := NewIpv4Address(&addr4)
+			if ipv4Address != nil {
+				iface.Ipv4Addresses = append(iface.Ipv4Addresses, ipv4Address)
+			}
+		}
+		addr6s, err := provider.handle.AddrList(link, unix.AF_INET6)
+		if err != nil {
+			return nil, err
+		}
+		for _, addr6 := range addr6s {
+			ipv6Address := NewIpv6Address(&addr6)
+			if ipv6Address != nil {
+				iface.Ipv6Addresses = append(iface.Ipv6Addresses, ipv6Address)
+			}
+		}
+		status.Interfaces = append(status.Interfaces, iface)
+	}
+	status.resolveVrfs()
+
+	return status, nil
+}
+
+func (provider *netlinkProvider) Links() ([]string, error) {
+	links, err := provider.handle.LinkList()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0)
+	for _, link := range links {
+		names = append(names, link.Attrs().Name)
+	}
+	return names, nil
+}
+
+func (provider *netlinkProvider) sendStatus(statusCh chan<- *KernelStatus) {
+	status, err := provider.Status()
+	if err != nil {
+		log.WithFields(log.Fields{
+			"Topic": "Kernel",
+			"Error": err,
+		}).Warn("Can't get kernel status")
+		return
+	}
+	statusCh <- status
+}
+
+func (provider *netlinkProvider) Watch(statusCh chan<- *KernelStatus, doneCh <-chan struct{}) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	addrCh := make(chan netlink.AddrUpdate)
+	addrDone := make(chan struct{})
+	defer close(addrDone)
+	netlink.AddrSubscribeAt(provider.ns, addrCh, addrDone)
+	linkCh := make(chan netlink.LinkUpdate)
+	linkDone := make(chan struct{})
+	defer close(linkDone)
+	netlink.LinkSubscribeAt(provider.ns, linkCh, linkDone)
+	provider.sendStatus(statusCh)
+	for {
+		select {
+		case <-doneCh:
+			return
+		case <-addrCh:
+		case <-linkCh:
+		}
+		provider.sendStatus(statusCh)
+	}
+}
+
+func uint32ToIp(addr uint32) net.IP {
+	ip := make(net.IP, 4)
+	binary.BigEndian.PutUint32(ip, addr)
+	return ip
+}
+
+func uint32ArrayToIp(addr [4]uint32) net.IP {
+	ip := make(net.IP, 16)
+	for i := 0; i < 4; i++ {
+		binary.BigEndian.PutUint32(ip[i*4:i*4+4], addr[i])
+	}
+	return ip
+}
+
+func newNetlinkRoute(table int, protocol RouteProtocol, dst *net.IPNet, metric uint32, gws []net.IP, ifIndexes []int) *netlink.Route {
+	route := &netlink.Route{
+		Table:    table,
+		Dst:      dst,
+		Protocol: netlink.RouteProtocol(protocol),
+		Priority: int(metric),
+	}
+	if len(gws) == 1 {
+		route.Gw = gws[0]
+		route.LinkIndex = ifIndexes[0]
+		return route
+	}
+	for i, gw := range gws {
+		route.MultiPath = append(route.MultiPath, &netlink.NexthopInfo{
+			LinkIndex: ifIndexes[i],
+			Gw:        gw,
+		})
+	}
+	return route
+}
+
+func newNetlinkIpv4Route(route *Ipv4Route) *netlink.Route {
+	dst := &net.IPNet{
+		IP:   uint32ToIp(route.Prefix),
+		Mask: net.CIDRMask(route.PrefixLength, 32),
+	}
+	gws := make([]net.IP, 0)
+	ifIndexes := make([]int, 0)
+	for _, nh := range route.NextHops {
+		gws = append(gws, uint32ToIp(nh.Address))
+		ifIndexes = append(ifIndexes, nh.IfIndex)
+	}
+	return newNetlinkRoute(route.Table, route.Protocol, dst, route.Metric, gws, ifIndexes)
+}
+
+func newNetlinkIpv6Route(route *Ipv6Route) *netlink.Route {
+	dst := &net.IPNet{
+		IP:   uint32ArrayToIp(route.Prefix),
+		Mask: net.CIDRMask(route.PrefixLength, 128),
+	}
+	gws := make([]net.IP, 0)
+	ifIndexes := make([]int, 0)
+	for _, nh := range route.NextHops {
+		gws = append(gws, uint32ArrayToIp(nh.Address))
+		ifIndexes = append(ifIndexes, nh.IfIndex)
+	}
+	return newNetlinkRoute(route.Table, route.Protocol, dst, route.Metric, gws, ifIndexes)
+}
+
+func (provider *netlinkProvider) AddIpv4Route(route *Ipv4Route) error {
+	if len(route.NextHops) == 0 {
+		return errors.New("netlinkProvider.AddIpv4Route: no next hop")
+	}
+	return provider.handle.RouteReplace(newNetlinkIpv4Route(route))
+}
+
+func (provider *netlinkProvider) DeleteIpv4Route(route *Ipv4Route) error {
+	nlroute := newNetlinkIpv4Route(route)
+	nlroute.Gw = nil
+	nlroute.LinkIndex = 0
+	nlroute.MultiPath = nil
+	return provider.handle.RouteDel(nlroute)
+}
+
+func (provider *netlinkProvider) AddIpv6Route(route *Ipv6Route) error {
+	if len(route.NextHops) == 0 {
+		return errors.New("netlinkProvider.AddIpv6Route: no next hop")
+	}
+	return provider.handle.RouteReplace(newNetlinkIpv6Route(route))
+}
+
+func (provider *netlinkProvider) DeleteIpv6Route(route *Ipv6Route) error {
+	nlroute := newNetlinkIpv6Route(route)
+	nlroute.Gw = nil
+	nlroute.LinkIndex = 0
+	nlroute.MultiPath = nil
+	return provider.handle.RouteDel(nlroute)
+}
@@ -61,6 +61,11 @@ func exitWithError(err error) {
 	os.Exit(1)
 }
 
+func printJson(v interface{}) {
+	j, _ := json.Marshal(v)
+	fmt.Println(string(j))
+}
+
 func newClient(ctx context.Context) (api.GoospfApiClient, error) {
 	grpcOpts := []grpc.DialOption{grpc.WithTimeout(time.Second), grpc.WithBlock()}
 	grpcOpts = append(grpcOpts, grpc.WithInsecure())
@@ -129,16 +134,23 @@ func NewRootCmd() *cobra.Command {
 	}
 	rootCmd.AddCommand(disableCmd)
 
-	/*
-		interfaceCmd := NewInterfaceCmd()
-		rootCmd.AddCommand(interfaceCmd)
+	interfaceCmd := NewInterfaceCmd()
+	rootCmd.AddCommand(interfaceCmd)
+
+	neighborCmd := NewNeighborCmd()
+	rootCmd.AddCommand(neighborCmd)
+
+	virtualLinkCmd := NewVirtualLinkCmd()
+	rootCmd.AddCommand(virtualLinkCmd)
+
+	stubRouterCmd := NewStubRouterCmd()
+	rootCmd.AddCommand(stubRouterCmd)
 
-		databaseCmd := NewDatabaseCmd()
-		rootCmd.AddCommand(databaseCmd)
+	databaseCmd := NewDatabaseCmd()
+	rootCmd.AddCommand(databaseCmd)
 
-		routeCmd := NewRouteCmd()
-		rootCmd.AddCommand(routeCmd)
-	*/
+	routeCmd := NewRouteCmd()
+	rootCmd.AddCommand(routeCmd)
 
 	return rootCmd
 }
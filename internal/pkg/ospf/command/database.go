@@ -0,0 +1,120 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	api "github.com/m-asama/golsr/api/ospf"
+)
+
+var dbLinkstateOpts struct {
+	Detail bool
+}
+
+// lsdbName is the name of the LSDB the LSA is in: the area, the link or
+// the AS.
+func lsdbName(lsa *api.Lsa) string {
+	switch {
+	case lsa.Interface != "":
+		return "Link " + lsa.Interface + " (Area " + lsa.AreaId + ")"
+	case lsa.AreaId != "":
+		return "Area " + lsa.AreaId
+	}
+	return "AS"
+}
+
+func printLsa(lsa *api.Lsa) {
+	if dbLinkstateOpts.Detail {
+		fmt.Printf("%s", lsa.Detail)
+		fmt.Printf("\n")
+		return
+	}
+	fmt.Printf("%-17s %-15s %-15s %4d 0x%08x 0x%04x\n", lsa.LsType, lsa.LinkStateId,
+		lsa.AdvertisingRouter, lsa.Age, lsa.SequenceNumber, lsa.Checksum)
+}
+
+func NewDbLinkstateCmd() *cobra.Command {
+	dbLinkstateCmd := &cobra.Command{
+		Use:   "linkstate [<area-id> [<ls-type>]]",
+		Short: "show the LSAs of the area and of the LS type (all by default)",
+		Run: func(cmd *cobra.Command, args []string) {
+			areaId, lsType := "all", "all"
+			if len(args) > 0 {
+				areaId = args[0]
+			}
+			if len(args) > 1 {
+				lsType = args[1]
+			}
+			stream, err := client.DbLsMonitor(ctx, &api.DbLsMonitorRequest{
+				AreaId: areaId,
+				LsType: lsType,
+			})
+			if err != nil {
+				exitWithError(err)
+			}
+			lsas := make([]*api.Lsa, 0)
+			for {
+				r, err := stream.Recv()
+				if err == io.EOF {
+					break
+				} else if err != nil {
+					exitWithError(err)
+				}
+				if globalOpts.Json {
+					lsas = append(lsas, r.Lsas...)
+					continue
+				}
+				if len(r.Lsas) == 0 {
+					continue
+				}
+				fmt.Printf("%s\n", lsdbName(r.Lsas[0]))
+				if !dbLinkstateOpts.Detail {
+					fmt.Printf("%-17s %-15s %-15s %4s %-10s %-6s\n", "TYPE", "LINK-STATE-ID",
+						"ADV-ROUTER", "AGE", "SEQUENCE", "CHKSUM")
+				}
+				for _, lsa := range r.Lsas {
+					printLsa(lsa)
+				}
+				fmt.Printf("\n")
+			}
+			if globalOpts.Json {
+				printJson(lsas)
+			}
+		},
+	}
+	dbLinkstateCmd.Flags().BoolVarP(&dbLinkstateOpts.Detail, "detail", "", false,
+		"show the contents of the LSAs")
+	return dbLinkstateCmd
+}
+
+func NewDatabaseCmd() *cobra.Command {
+	databaseCmd := &cobra.Command{
+		Use: "database",
+		Run: func(cmd *cobra.Command, args []string) {
+		},
+	}
+
+	dbLinkstateCmd := NewDbLinkstateCmd()
+	databaseCmd.AddCommand(dbLinkstateCmd)
+
+	return databaseCmd
+}
@@ -0,0 +1,86 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	api "github.com/m-asama/golsr/api/ospf"
+)
+
+func printInterface(iface *api.Interface) {
+	fmt.Printf("Name                      : %s\n", iface.Name)
+	fmt.Printf("AreaId                    : %s\n", iface.AreaId)
+	fmt.Printf("NetworkType               : %s\n", iface.NetworkType)
+	fmt.Printf("State                     : %s\n", iface.State)
+	fmt.Printf("Address                   : %s\n", iface.Address)
+	fmt.Printf("Cost                      : %d\n", iface.Cost)
+	fmt.Printf("Priority                  : %d\n", iface.Priority)
+	fmt.Printf("Dr                        : %s\n", iface.Dr)
+	fmt.Printf("Bdr                       : %s\n", iface.Bdr)
+	fmt.Printf("HelloInterval             : %d\n", iface.HelloInterval)
+	fmt.Printf("DeadInterval              : %d\n", iface.DeadInterval)
+	fmt.Printf("Passive                   : %t\n", iface.Passive)
+	fmt.Printf("NeighborCount             : %d\n", iface.NeighborCount)
+	fmt.Printf("AdjacencyCount            : %d\n", iface.AdjacencyCount)
+	fmt.Printf("TtlSecurity               : %t\n", iface.TtlSecurity)
+	fmt.Printf("TtlSecurityDrops          : %d\n", iface.TtlSecurityDrops)
+	fmt.Printf("\n")
+}
+
+func NewInterfaceCmd() *cobra.Command {
+	interfaceCmd := &cobra.Command{
+		Use:   "interface [<interface>]",
+		Short: "show the interfaces (all by default)",
+		Run: func(cmd *cobra.Command, args []string) {
+			ifname := "all"
+			if len(args) > 0 {
+				ifname = args[0]
+			}
+			stream, err := client.InterfaceMonitor(ctx, &api.InterfaceMonitorRequest{
+				Interface: ifname,
+			})
+			if err != nil {
+				exitWithError(err)
+			}
+			interfaces := make([]*api.Interface, 0)
+			for {
+				r, err := stream.Recv()
+				if err == io.EOF {
+					break
+				} else if err != nil {
+					exitWithError(err)
+				}
+				if globalOpts.Json {
+					interfaces = append(interfaces, r.Interfaces...)
+					continue
+				}
+				for _, iface := range r.Interfaces {
+					printInterface(iface)
+				}
+			}
+			if globalOpts.Json {
+				printJson(interfaces)
+			}
+		},
+	}
+	return interfaceCmd
+}
@@ -0,0 +1,80 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	api "github.com/m-asama/golsr/api/ospf"
+)
+
+func printNeighbor(nbr *api.Neighbor) {
+	fmt.Printf("Interface                 : %s\n", nbr.Interface)
+	fmt.Printf("AreaId                    : %s\n", nbr.AreaId)
+	fmt.Printf("RouterId                  : %s\n", nbr.RouterId)
+	fmt.Printf("Address                   : %s\n", nbr.Address)
+	fmt.Printf("Priority                  : %d\n", nbr.Priority)
+	fmt.Printf("State                     : %s\n", nbr.State)
+	fmt.Printf("Dr                        : %s\n", nbr.Dr)
+	fmt.Printf("Bdr                       : %s\n", nbr.Bdr)
+	fmt.Printf("DeadTimer                 : %d\n", nbr.DeadTimer)
+	fmt.Printf("RetransmissionCount       : %d\n", nbr.RetransmissionCount)
+	fmt.Printf("\n")
+}
+
+func NewNeighborCmd() *cobra.Command {
+	neighborCmd := &cobra.Command{
+		Use:   "neighbor [<interface>]",
+		Short: "show the neighbors on the interface (all by default)",
+		Run: func(cmd *cobra.Command, args []string) {
+			ifname := "all"
+			if len(args) > 0 {
+				ifname = args[0]
+			}
+			stream, err := client.NeighborMonitor(ctx, &api.NeighborMonitorRequest{
+				Interface: ifname,
+			})
+			if err != nil {
+				exitWithError(err)
+			}
+			neighbors := make([]*api.Neighbor, 0)
+			for {
+				r, err := stream.Recv()
+				if err == io.EOF {
+					break
+				} else if err != nil {
+					exitWithError(err)
+				}
+				if globalOpts.Json {
+					neighbors = append(neighbors, r.Neighbors...)
+					continue
+				}
+				for _, nbr := range r.Neighbors {
+					printNeighbor(nbr)
+				}
+			}
+			if globalOpts.Json {
+				printJson(neighbors)
+			}
+		},
+	}
+	return neighborCmd
+}
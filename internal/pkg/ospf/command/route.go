@@ -0,0 +1,101 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	api "github.com/m-asama/golsr/api/ospf"
+)
+
+// routeTypeName abbreviates the route type as in the route table.
+func routeTypeName(routeType string) string {
+	switch routeType {
+	case "ROUTE_TYPE_INTRA_AREA":
+		return "O"
+	case "ROUTE_TYPE_INTER_AREA":
+		return "IA"
+	case "ROUTE_TYPE_EXTERNAL_1":
+		return "E1"
+	case "ROUTE_TYPE_EXTERNAL_2":
+		return "E2"
+	}
+	return "?"
+}
+
+func printRoute(route *api.Route) {
+	fmt.Printf("%-2s ", routeTypeName(route.RouteType))
+	fmt.Printf("%-30s ", route.Prefix)
+	fmt.Printf("%-15s ", route.AreaId)
+	fmt.Printf("%5d ", route.Metric)
+	if len(route.NextHops) == 0 {
+		fmt.Printf("\n")
+	}
+	for i, nh := range route.NextHops {
+		if i > 0 {
+			fmt.Printf("%s", strings.Repeat(" ", 56))
+		}
+		fmt.Printf("%-8s %-30s\n", nh.OutgoingInterface, nh.NextHop)
+	}
+}
+
+func NewRouteCmd() *cobra.Command {
+	routeCmd := &cobra.Command{
+		Use:   "route [<address-family>]",
+		Short: "show the routes of the address family (all by default)",
+		Run: func(cmd *cobra.Command, args []string) {
+			addressFamily := "all"
+			if len(args) > 0 {
+				addressFamily = args[0]
+			}
+			stream, err := client.DbRiMonitor(ctx, &api.DbRiMonitorRequest{
+				AddressFamily: addressFamily,
+			})
+			if err != nil {
+				exitWithError(err)
+			}
+			routes := make([]*api.Route, 0)
+			if !globalOpts.Json {
+				fmt.Printf("%-2s %-30s %-15s %5s %-8s %-30s\n", "TY", "PREFIX", "AREA", "DIST", "I/F", "NEXTHOP")
+			}
+			for {
+				r, err := stream.Recv()
+				if err == io.EOF {
+					break
+				} else if err != nil {
+					exitWithError(err)
+				}
+				if globalOpts.Json {
+					routes = append(routes, r.Routes...)
+					continue
+				}
+				for _, route := range r.Routes {
+					printRoute(route)
+				}
+			}
+			if globalOpts.Json {
+				printJson(routes)
+			}
+		},
+	}
+	return routeCmd
+}
@@ -0,0 +1,77 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	api "github.com/m-asama/golsr/api/ospf"
+)
+
+func printStubRouter(stubRouter *api.StubRouter) {
+	fmt.Printf("Active                    : %t\n", stubRouter.Active)
+	fmt.Printf("Always                    : %t\n", stubRouter.Always)
+	fmt.Printf("OnDemand                  : %t\n", stubRouter.OnDemand)
+	fmt.Printf("OnStartupRemaining        : %d\n", stubRouter.OnStartupRemaining)
+	fmt.Printf("WaitFor                   : %s\n", stubRouter.WaitFor)
+}
+
+func NewStubRouterCmd() *cobra.Command {
+	stubRouterCmd := &cobra.Command{
+		Use: "stub-router",
+		Run: func(cmd *cobra.Command, args []string) {
+			response, err := client.StubRouterGet(ctx, &api.StubRouterGetRequest{})
+			if err != nil {
+				exitWithError(err)
+			}
+			if globalOpts.Json {
+				printJson(response.StubRouter)
+				return
+			}
+			printStubRouter(response.StubRouter)
+		},
+	}
+
+	enableCmd := &cobra.Command{
+		Use: "enable",
+		Run: func(cmd *cobra.Command, args []string) {
+			response, err := client.StubRouterEnable(ctx, &api.StubRouterEnableRequest{})
+			if err != nil {
+				exitWithError(err)
+			}
+			fmt.Println(response.Result)
+		},
+	}
+	stubRouterCmd.AddCommand(enableCmd)
+
+	disableCmd := &cobra.Command{
+		Use: "disable",
+		Run: func(cmd *cobra.Command, args []string) {
+			response, err := client.StubRouterDisable(ctx, &api.StubRouterDisableRequest{})
+			if err != nil {
+				exitWithError(err)
+			}
+			fmt.Println(response.Result)
+		},
+	}
+	stubRouterCmd.AddCommand(disableCmd)
+
+	return stubRouterCmd
+}
@@ -0,0 +1,60 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	api "github.com/m-asama/golsr/api/ospf"
+)
+
+func printVirtualLink(vlink *api.VirtualLink) {
+	fmt.Printf("TransitAreaId             : %s\n", vlink.TransitAreaId)
+	fmt.Printf("RouterId                  : %s\n", vlink.RouterId)
+	fmt.Printf("State                     : %s\n", vlink.State)
+	fmt.Printf("NeighborState             : %s\n", vlink.NeighborState)
+	fmt.Printf("Address                   : %s\n", vlink.Address)
+	fmt.Printf("NeighborAddress           : %s\n", vlink.NeighborAddress)
+	fmt.Printf("Cost                      : %d\n", vlink.Cost)
+	fmt.Printf("TransitInterface          : %s\n", vlink.TransitInterface)
+	fmt.Printf("TtlSecurity               : %t\n", vlink.TtlSecurity)
+	fmt.Printf("TtlSecurityDrops          : %d\n", vlink.TtlSecurityDrops)
+	fmt.Printf("\n")
+}
+
+func NewVirtualLinkCmd() *cobra.Command {
+	virtualLinkCmd := &cobra.Command{
+		Use: "virtual-link",
+		Run: func(cmd *cobra.Command, args []string) {
+			response, err := client.VirtualLinkGet(ctx, &api.VirtualLinkGetRequest{})
+			if err != nil {
+				exitWithError(err)
+			}
+			if globalOpts.Json {
+				printJson(response.VirtualLinks)
+				return
+			}
+			for _, vlink := range response.VirtualLinks {
+				printVirtualLink(vlink)
+			}
+		},
+	}
+	return virtualLinkCmd
+}
@@ -18,6 +18,8 @@
 package config
 
 import (
+	"bytes"
+
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 	"os"
@@ -40,6 +42,20 @@ func detectConfigFileType(path, def string) string {
 	}
 }
 
+func Parse(data []byte, format string) (*OspfConfig, error) {
+	c := &OspfConfig{}
+	v := viper.New()
+	v.SetConfigType(format)
+	if err := v.ReadConfig(bytes.NewBuffer(data)); err != nil {
+		return nil, err
+	}
+	if err := v.UnmarshalExact(c); err != nil {
+		return nil, err
+	}
+	c.fillDefaults()
+	return c, nil
+}
+
 func Serve(path, format string, configCh chan *OspfConfig) {
 
 	//log.Info("ReadConfigfileServe started")
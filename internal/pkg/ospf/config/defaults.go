@@ -17,7 +17,207 @@
 
 package config
 
-import ()
+import (
+	"github.com/m-asama/golsr/internal/pkg/kernel"
+)
+
+func (config *Interface) fillDefaults() {
+	ifaceType := kernel.IfType(0)
+	if config.Config.Name != nil {
+		ifaceType = kernel.IfaceType(*config.Config.Name)
+	}
+	// interface-type
+	if config.Config.InterfaceType == nil {
+		interfaceType := "point-to-point"
+		if ifaceType == kernel.IF_TYPE_BROADCAST {
+			interfaceType = "broadcast"
+		}
+		config.Config.InterfaceType = &interfaceType
+	}
+	// passive
+	if config.Config.Passive == nil {
+		passive := false
+		if ifaceType == kernel.IF_TYPE_LOOPBACK {
+			passive = true
+		}
+		config.Config.Passive = &passive
+	}
+	// priority
+	if config.Config.Priority == nil {
+		priority := uint8(1)
+		config.Config.Priority = &priority
+	}
+	// hello-interval
+	if config.Config.HelloInterval == nil {
+		helloInterval := uint16(10)
+		config.Config.HelloInterval = &helloInterval
+	}
+	// dead-interval
+	if config.Config.DeadInterval == nil {
+		deadInterval := uint32(*config.Config.HelloInterval) * 4
+		config.Config.DeadInterval = &deadInterval
+	}
+	// retransmit-interval
+	if config.Config.RetransmitInterval == nil {
+		retransmitInterval := uint16(5)
+		config.Config.RetransmitInterval = &retransmitInterval
+	}
+	// transmit-delay
+	if config.Config.TransmitDelay == nil {
+		transmitDelay := uint16(1)
+		config.Config.TransmitDelay = &transmitDelay
+	}
+	// enable
+	if config.Config.Enable == nil {
+		enable := true
+		config.Config.Enable = &enable
+	}
+	// cost
+	if config.Config.Cost == nil {
+		cost := uint16(10)
+		if ifaceType == kernel.IF_TYPE_LOOPBACK {
+			cost = 0
+		}
+		config.Config.Cost = &cost
+	}
+	// mtu-ignore
+	if config.Config.MtuIgnore == nil {
+		mtuIgnore := false
+		config.Config.MtuIgnore = &mtuIgnore
+	}
+	for _, nbr := range config.StaticNeighbors {
+		nbr.fillDefaults()
+	}
+	config.TtlSecurity.fillDefaults()
+	config.Authentication.fillDefaults()
+}
+
+func (config *TtlSecurity) fillDefaults() {
+	// enable
+	if config.Config.Enable == nil {
+		enable := false
+		config.Config.Enable = &enable
+	}
+	// hops
+	if config.Config.Hops == nil {
+		hops := uint8(1)
+		config.Config.Hops = &hops
+	}
+}
+
+func (config *StaticNeighbor) fillDefaults() {
+	// poll-interval
+	if config.Config.PollInterval == nil {
+		pollInterval := uint16(120)
+		config.Config.PollInterval = &pollInterval
+	}
+	// priority
+	if config.Config.Priority == nil {
+		priority := uint8(1)
+		config.Config.Priority = &priority
+	}
+}
+
+func (config *Authentication) fillDefaults() {
+	// ospfv2-auth-trailer-rfc
+	if config.Config.Ospfv2AuthTrailerRfc == nil {
+		ospfv2AuthTrailerRfc := "rfc5709"
+		config.Config.Ospfv2AuthTrailerRfc = &ospfv2AuthTrailerRfc
+	}
+}
+
+func (config *Area) fillDefaults() {
+	// area-type
+	if config.Config.AreaType == nil {
+		areaType := "normal"
+		config.Config.AreaType = &areaType
+	}
+	// summary
+	if config.Config.Summary == nil {
+		summary := true
+		config.Config.Summary = &summary
+	}
+	// default-cost
+	if config.Config.DefaultCost == nil {
+		defaultCost := uint32(1)
+		config.Config.DefaultCost = &defaultCost
+	}
+	for _, r := range config.Ranges {
+		r.fillDefaults()
+	}
+	for _, iface := range config.Interfaces {
+		iface.fillDefaults()
+	}
+	for _, vlink := range config.VirtualLinks {
+		vlink.fillDefaults()
+	}
+}
+
+func (config *VirtualLink) fillDefaults() {
+	// hello-interval
+	if config.Config.HelloInterval == nil {
+		helloInterval := uint16(10)
+		config.Config.HelloInterval = &helloInterval
+	}
+	// dead-interval
+	if config.Config.DeadInterval == nil {
+		deadInterval := uint32(*config.Config.HelloInterval) * 4
+		config.Config.DeadInterval = &deadInterval
+	}
+	// retransmit-interval
+	if config.Config.RetransmitInterval == nil {
+		retransmitInterval := uint16(5)
+		config.Config.RetransmitInterval = &retransmitInterval
+	}
+	// transmit-delay
+	if config.Config.TransmitDelay == nil {
+		transmitDelay := uint16(1)
+		config.Config.TransmitDelay = &transmitDelay
+	}
+	// enable
+	if config.Config.Enable == nil {
+		enable := true
+		config.Config.Enable = &enable
+	}
+	config.TtlSecurity.fillDefaults()
+	config.Authentication.fillDefaults()
+}
+
+func (config *Range) fillDefaults() {
+	// advertise
+	if config.Config.Advertise == nil {
+		advertise := true
+		config.Config.Advertise = &advertise
+	}
+}
 
 func (config *OspfConfig) fillDefaults() {
+	// address-family
+	if config.Config.AddressFamily == nil {
+		addressFamily := "ipv4"
+		config.Config.AddressFamily = &addressFamily
+	}
+	// enable
+	if config.Config.Enable == nil {
+		enable := true
+		config.Config.Enable = &enable
+	}
+	for _, area := range config.Areas {
+		area.fillDefaults()
+	}
+	config.StubRouter.fillDefaults()
+}
+
+func (config *StubRouter) fillDefaults() {
+	// always
+	if config.Config.Always == nil {
+		always := false
+		config.Config.Always = &always
+	}
+	// on-startup, the longest the router waits for the condition of
+	// wait-for
+	if config.Config.WaitFor != nil && config.Config.OnStartup == nil {
+		onStartup := uint32(600)
+		config.Config.OnStartup = &onStartup
+	}
 }
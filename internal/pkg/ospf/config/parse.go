@@ -17,4 +17,90 @@
 
 package config
 
-import ()
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+	"strconv"
+	"time"
+)
+
+// ParseRouterId parses a router ID in dotted decimal.
+func ParseRouterId(routerIdStr string) (uint32, error) {
+	ip := net.ParseIP(routerIdStr).To4()
+	if ip == nil {
+		return 0, errors.New("router-id " + routerIdStr + " invalid")
+	}
+	return binary.BigEndian.Uint32(ip), nil
+}
+
+// ParseAreaId parses an area ID either in dotted decimal or as a
+// decimal number.
+func ParseAreaId(areaIdStr string) (uint32, error) {
+	if ip := net.ParseIP(areaIdStr).To4(); ip != nil {
+		return binary.BigEndian.Uint32(ip), nil
+	}
+	areaId, err := strconv.ParseUint(areaIdStr, 10, 32)
+	if err != nil {
+		return 0, errors.New("area-id " + areaIdStr + " invalid")
+	}
+	return uint32(areaId), nil
+}
+
+// ParseIpv4Prefix parses an IPv4 prefix in CIDR notation. The host bits
+// are cleared.
+func ParseIpv4Prefix(prefixStr string) (uint32, uint8, error) {
+	ip, ipNet, err := net.ParseCIDR(prefixStr)
+	if err != nil || ip.To4() == nil {
+		return 0, 0, errors.New("prefix " + prefixStr + " invalid")
+	}
+	prefixLength, _ := ipNet.Mask.Size()
+	return binary.BigEndian.Uint32(ipNet.IP.To4()), uint8(prefixLength), nil
+}
+
+// ParseIpv6Prefix parses an IPv6 prefix in CIDR notation. The host bits
+// are cleared.
+func ParseIpv6Prefix(prefixStr string) ([4]uint32, uint8, error) {
+	var prefix [4]uint32
+	ip, ipNet, err := net.ParseCIDR(prefixStr)
+	if err != nil || ip.To4() != nil {
+		return prefix, 0, errors.New("prefix " + prefixStr + " invalid")
+	}
+	for i := 0; i < 4; i++ {
+		prefix[i] = binary.BigEndian.Uint32(ipNet.IP[4*i : 4*i+4])
+	}
+	prefixLength, _ := ipNet.Mask.Size()
+	return prefix, uint8(prefixLength), nil
+}
+
+// ParseIpv4Address parses an IPv4 address in dotted decimal.
+func ParseIpv4Address(addressStr string) (uint32, error) {
+	ip := net.ParseIP(addressStr)
+	if ip == nil || ip.To4() == nil {
+		return 0, errors.New("address " + addressStr + " invalid")
+	}
+	return binary.BigEndian.Uint32(ip.To4()), nil
+}
+
+// ParseIpv6Address parses an IPv6 address.
+func ParseIpv6Address(addressStr string) ([4]uint32, error) {
+	var address [4]uint32
+	ip := net.ParseIP(addressStr)
+	if ip == nil || ip.To4() != nil {
+		return address, errors.New("address " + addressStr + " invalid")
+	}
+	for i := 0; i < 4; i++ {
+		address[i] = binary.BigEndian.Uint32(ip[4*i : 4*i+4])
+	}
+	return address, nil
+}
+
+// ParseKeyTime parses a start or end time of the lifetime of a key in
+// RFC 3339 format.
+func ParseKeyTime(timeStr string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, timeStr)
+	if err != nil {
+		return t, errors.New("time " + timeStr + " invalid")
+	}
+	return t, nil
+}
@@ -89,7 +89,9 @@ type DatabaseControl struct {
 }
 
 type StubRouterConfig struct {
-	Always *bool `mapstructure:"always"`
+	Always    *bool   `mapstructure:"always"`
+	OnStartup *uint32 `mapstructure:"on-startup"`
+	WaitFor   *string `mapstructure:"wait-for"`
 }
 
 type StubRouter struct {
@@ -168,6 +170,31 @@ type Authentication struct {
 	Config AuthenticationConfig `mapstructure:"config" json:"config,omitempty"`
 }
 
+// ietf-key-chain (RFC 8177)
+
+type KeyChainKeyConfig struct {
+	KeyId           *uint32 `mapstructure:"key-id"`
+	KeyString       *string `mapstructure:"key-string"`
+	CryptoAlgorithm *string `mapstructure:"crypto-algorithm"`
+	SendStartTime   *string `mapstructure:"send-start-time"`
+	SendEndTime     *string `mapstructure:"send-end-time"`
+	AcceptStartTime *string `mapstructure:"accept-start-time"`
+	AcceptEndTime   *string `mapstructure:"accept-end-time"`
+}
+
+type KeyChainKey struct {
+	Config KeyChainKeyConfig `mapstructure:"config" json:"config,omitempty"`
+}
+
+type KeyChainConfig struct {
+	Name *string `mapstructure:"name"`
+}
+
+type KeyChain struct {
+	Config KeyChainConfig `mapstructure:"config" json:"config,omitempty"`
+	Keys   []*KeyChainKey `mapstructure:"keys"`
+}
+
 type VirtualLinkConfig struct {
 	RransitAreaId      *string `mapstructure:"transit-area-id"`
 	RouterId           *string `mapstructure:"router-id"`
@@ -351,6 +378,10 @@ type OspfConfig struct {
 	NodeTags        []*NodeTag      `mapstructure:"node-tags"`
 	Areas           []*Area         `mapstructure:"areas"`
 	Topologies      []*Topology     `mapstructure:"topologies"`
+
+	// KeyChains are the key chains the authentication of the interfaces
+	// and virtual links refers to.
+	KeyChains []*KeyChain `mapstructure:"key-chains"`
 }
 
 func NewOspfConfig() *OspfConfig {
@@ -358,5 +389,6 @@ func NewOspfConfig() *OspfConfig {
 	config.NodeTags = make([]*NodeTag, 0)
 	config.Areas = make([]*Area, 0)
 	config.Topologies = make([]*Topology, 0)
+	config.KeyChains = make([]*KeyChain, 0)
 	return config
 }
@@ -17,8 +17,393 @@
 
 package config
 
-import ()
+import (
+	"errors"
+
+	"github.com/m-asama/golsr/internal/pkg/kernel"
+)
+
+func (config *Interface) validate() error {
+	if config.Config.Name == nil {
+		return errors.New("interface name not defined")
+	}
+	if !kernel.IfaceExists(*config.Config.Name) {
+		return errors.New("interface not exists")
+	}
+	switch *config.Config.InterfaceType {
+	case "broadcast", "point-to-point", "non-broadcast", "point-to-multipoint":
+	default:
+		return errors.New("interface-type " + *config.Config.InterfaceType + " not supported")
+	}
+	identifiers := make(map[string]bool)
+	for _, nbr := range config.StaticNeighbors {
+		if nbr.Config.Identifier == nil {
+			return errors.New("static-neighbor identifier not defined")
+		}
+		if identifiers[*nbr.Config.Identifier] {
+			return errors.New("static-neighbor " + *nbr.Config.Identifier + " defined twice")
+		}
+		identifiers[*nbr.Config.Identifier] = true
+		if *nbr.Config.PollInterval == 0 {
+			return errors.New("static-neighbor poll-interval invalid")
+		}
+	}
+	if *config.Config.HelloInterval == 0 {
+		return errors.New("hello-interval invalid")
+	}
+	if *config.Config.DeadInterval <= uint32(*config.Config.HelloInterval) {
+		return errors.New("dead-interval invalid")
+	}
+	if *config.Config.RetransmitInterval == 0 {
+		return errors.New("retransmit-interval invalid")
+	}
+	return config.TtlSecurity.validate()
+}
+
+func (config *TtlSecurity) validate() error {
+	if *config.Config.Hops == 0 || *config.Config.Hops == 255 {
+		return errors.New("ttl-security hops invalid")
+	}
+	return nil
+}
+
+func (config *VirtualLink) validate() error {
+	if config.Config.RransitAreaId == nil {
+		return errors.New("virtual-link transit-area-id not defined")
+	}
+	transitAreaId, err := ParseAreaId(*config.Config.RransitAreaId)
+	if err != nil {
+		return err
+	}
+	if transitAreaId == 0 {
+		return errors.New("virtual-link transit-area-id invalid")
+	}
+	if config.Config.RouterId == nil {
+		return errors.New("virtual-link router-id not defined")
+	}
+	_, err = ParseRouterId(*config.Config.RouterId)
+	if err != nil {
+		return err
+	}
+	if *config.Config.HelloInterval == 0 {
+		return errors.New("hello-interval invalid")
+	}
+	if *config.Config.DeadInterval <= uint32(*config.Config.HelloInterval) {
+		return errors.New("dead-interval invalid")
+	}
+	if *config.Config.RetransmitInterval == 0 {
+		return errors.New("retransmit-interval invalid")
+	}
+	return config.TtlSecurity.validate()
+}
+
+// validateAddressFamily checks the parameters that depend on the OSPF
+// version: OSPFv2 has no instance ID, the dead interval of OSPFv3 is 16
+// bits long and the static neighbors are of the address family.
+func (config *Interface) validateAddressFamily(addressFamily string) error {
+	if addressFamily == "ipv4" {
+		if config.Config.InstanceId != nil && *config.Config.InstanceId != 0 {
+			return errors.New("instance-id not supported for address-family ipv4")
+		}
+		for _, nbr := range config.StaticNeighbors {
+			if _, err := ParseIpv4Address(*nbr.Config.Identifier); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if *config.Config.DeadInterval > 0xffff {
+		return errors.New("dead-interval invalid")
+	}
+	// the neighbors of OSPFv3 are identified by their link-local
+	// addresses
+	for _, nbr := range config.StaticNeighbors {
+		if _, err := ParseIpv6Address(*nbr.Config.Identifier); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateAddressFamily checks the ranges are prefixes of the address
+// family and the area types it supports: NSSA and virtual links are
+// OSPFv2 only.
+func (config *Area) validateAddressFamily(addressFamily string) error {
+	var err error
+	for _, r := range config.Ranges {
+		if addressFamily == "ipv4" {
+			_, _, err = ParseIpv4Prefix(*r.Config.Prefix)
+		} else {
+			_, _, err = ParseIpv6Prefix(*r.Config.Prefix)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if addressFamily == "ipv6" && *config.Config.AreaType == "nssa" {
+		return errors.New("area-type nssa not supported for address-family ipv6")
+	}
+	if addressFamily == "ipv6" && len(config.VirtualLinks) > 0 {
+		return errors.New("virtual-links not supported for address-family ipv6")
+	}
+	for _, iface := range config.Interfaces {
+		err = iface.validateAddressFamily(addressFamily)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (config *Area) validate() error {
+	if config.Config.AreaId == nil {
+		return errors.New("area-id not defined")
+	}
+	areaId, err := ParseAreaId(*config.Config.AreaId)
+	if err != nil {
+		return err
+	}
+	switch *config.Config.AreaType {
+	case "normal":
+	case "stub", "nssa":
+		if areaId == 0 {
+			return errors.New("area-type " + *config.Config.AreaType + " invalid for the backbone")
+		}
+	default:
+		return errors.New("area-type " + *config.Config.AreaType + " not supported")
+	}
+	for _, r := range config.Ranges {
+		if r.Config.Prefix == nil {
+			return errors.New("range prefix not defined")
+		}
+	}
+	for _, iface := range config.Interfaces {
+		err = iface.validate()
+		if err != nil {
+			return err
+		}
+	}
+	if areaId != 0 && len(config.VirtualLinks) > 0 {
+		return errors.New("virtual-links defined in area " + *config.Config.AreaId)
+	}
+	for _, vlink := range config.VirtualLinks {
+		err = vlink.validate()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cryptoAlgorithmValid tells whether algorithm is supported for the
+// address family. OSPFv2 also takes a simple password and keyed MD5.
+func cryptoAlgorithmValid(algorithm, addressFamily string) bool {
+	switch algorithm {
+	case "hmac-sha-1", "hmac-sha-256", "hmac-sha-384", "hmac-sha-512":
+		return true
+	case "cleartext", "md5":
+		return addressFamily == "ipv4"
+	}
+	return false
+}
+
+// keyIdValid tells whether a key of the algorithm can have the key ID in
+// the address family: the key ID of OSPFv2 is 8 bits long and the SA ID
+// of OSPFv3 16 bits.
+func keyIdValid(keyId uint32, algorithm, addressFamily string) bool {
+	if addressFamily == "ipv4" {
+		return algorithm == "cleartext" || keyId <= 0xff
+	}
+	return keyId <= 0xffff
+}
+
+func (config *KeyChain) validate() error {
+	if config.Config.Name == nil {
+		return errors.New("key-chain name not defined")
+	}
+	keyIds := make(map[uint32]bool)
+	for _, key := range config.Keys {
+		if key.Config.KeyId == nil {
+			return errors.New("key-chain " + *config.Config.Name + " key-id not defined")
+		}
+		if keyIds[*key.Config.KeyId] {
+			return errors.New("key-chain " + *config.Config.Name + " key-id defined twice")
+		}
+		keyIds[*key.Config.KeyId] = true
+		if key.Config.KeyString == nil {
+			return errors.New("key-chain " + *config.Config.Name + " key-string not defined")
+		}
+		if key.Config.CryptoAlgorithm == nil {
+			return errors.New("key-chain " + *config.Config.Name + " crypto-algorithm not defined")
+		}
+		for _, t := range []*string{key.Config.SendStartTime, key.Config.SendEndTime,
+			key.Config.AcceptStartTime, key.Config.AcceptEndTime} {
+			if t == nil {
+				continue
+			}
+			if _, err := ParseKeyTime(*t); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validate checks the authentication of the address family refers to a
+// key chain defined or has a key of a supported algorithm.
+func (config *Authentication) validate(addressFamily string, keyChains map[string]*KeyChain) error {
+	keyChain, keyId, key, algorithm := config.Config.Ospfv2KeyChain, config.Config.Ospfv2KeyId,
+		config.Config.Ospfv2Key, config.Config.Ospfv2CryptoAlgorithm
+	if addressFamily == "ipv6" {
+		keyChain, key, algorithm = config.Config.Ospfv3KeyChain, config.Config.Ospfv3Key,
+			config.Config.Ospfv3CryptoAlgorithm
+		keyId = nil
+		if config.Config.Ospfv3SaId != nil {
+			saId := uint32(*config.Config.Ospfv3SaId)
+			keyId = &saId
+		}
+	} else {
+		switch *config.Config.Ospfv2AuthTrailerRfc {
+		case "rfc5709", "rfc7474":
+		default:
+			return errors.New("ospfv2-auth-trailer-rfc " + *config.Config.Ospfv2AuthTrailerRfc + " not supported")
+		}
+	}
+	if keyChain != nil {
+		if key != nil {
+			return errors.New("authentication key-chain and key both defined")
+		}
+		chain, ok := keyChains[*keyChain]
+		if !ok {
+			return errors.New("key-chain " + *keyChain + " not defined")
+		}
+		for _, k := range chain.Keys {
+			if *k.Config.CryptoAlgorithm == "cleartext" ||
+				!cryptoAlgorithmValid(*k.Config.CryptoAlgorithm, addressFamily) {
+				return errors.New("key-chain " + *keyChain + " crypto-algorithm " +
+					*k.Config.CryptoAlgorithm + " not supported")
+			}
+			if addressFamily == "ipv4" && *config.Config.Ospfv2AuthTrailerRfc == "rfc7474" &&
+				*k.Config.CryptoAlgorithm == "md5" {
+				return errors.New("key-chain " + *keyChain + " crypto-algorithm md5 not supported for rfc7474")
+			}
+			if !keyIdValid(*k.Config.KeyId, "", addressFamily) {
+				return errors.New("key-chain " + *keyChain + " key-id invalid")
+			}
+		}
+		return nil
+	}
+	if key == nil {
+		return nil
+	}
+	if algorithm == nil {
+		return errors.New("authentication crypto-algorithm not defined")
+	}
+	if !cryptoAlgorithmValid(*algorithm, addressFamily) {
+		return errors.New("authentication crypto-algorithm " + *algorithm + " not supported")
+	}
+	if addressFamily == "ipv4" && *config.Config.Ospfv2AuthTrailerRfc == "rfc7474" &&
+		*algorithm == "md5" {
+		return errors.New("authentication crypto-algorithm md5 not supported for rfc7474")
+	}
+	if *algorithm == "cleartext" {
+		if len(*key) > 8 {
+			return errors.New("authentication key too long")
+		}
+		return nil
+	}
+	if keyId == nil {
+		return errors.New("authentication key-id not defined")
+	}
+	if !keyIdValid(*keyId, *algorithm, addressFamily) {
+		return errors.New("authentication key-id invalid")
+	}
+	return nil
+}
 
 func (config *OspfConfig) validate() error {
+	var err error
+	switch *config.Config.AddressFamily {
+	case "ipv4", "ipv6":
+	default:
+		return errors.New("address-family " + *config.Config.AddressFamily + " not supported")
+	}
+	if config.Config.ExplicitRouterId != nil {
+		_, err = ParseRouterId(*config.Config.ExplicitRouterId)
+		if err != nil {
+			return err
+		}
+	}
+	if config.StubRouter.Config.WaitFor != nil {
+		switch *config.StubRouter.Config.WaitFor {
+		case "adjacencies":
+		default:
+			return errors.New("stub-router wait-for " + *config.StubRouter.Config.WaitFor + " not supported")
+		}
+	}
+	keyChains := make(map[string]*KeyChain)
+	for _, keyChain := range config.KeyChains {
+		err = keyChain.validate()
+		if err != nil {
+			return err
+		}
+		if _, ok := keyChains[*keyChain.Config.Name]; ok {
+			return errors.New("key-chain " + *keyChain.Config.Name + " defined twice")
+		}
+		keyChains[*keyChain.Config.Name] = keyChain
+	}
+	areaIds := make(map[uint32]bool)
+	ifNames := make(map[string]bool)
+	for _, area := range config.Areas {
+		err = area.validate()
+		if err != nil {
+			return err
+		}
+		err = area.validateAddressFamily(*config.Config.AddressFamily)
+		if err != nil {
+			return err
+		}
+		areaId, _ := ParseAreaId(*area.Config.AreaId)
+		if areaIds[areaId] {
+			return errors.New("area " + *area.Config.AreaId + " defined twice")
+		}
+		areaIds[areaId] = true
+		for _, iface := range area.Interfaces {
+			if ifNames[*iface.Config.Name] {
+				return errors.New("interface " + *iface.Config.Name + " defined twice")
+			}
+			ifNames[*iface.Config.Name] = true
+			err = iface.Authentication.validate(*config.Config.AddressFamily, keyChains)
+			if err != nil {
+				return err
+			}
+		}
+		for _, vlink := range area.VirtualLinks {
+			err = vlink.Authentication.validate(*config.Config.AddressFamily, keyChains)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	// the transit areas of the virtual links are to be areas through
+	// which the backbone can be reached
+	for _, area := range config.Areas {
+		for _, vlink := range area.VirtualLinks {
+			transitAreaId, _ := ParseAreaId(*vlink.Config.RransitAreaId)
+			var transit *Area
+			for _, tmp := range config.Areas {
+				areaId, _ := ParseAreaId(*tmp.Config.AreaId)
+				if areaId == transitAreaId {
+					transit = tmp
+				}
+			}
+			if transit == nil {
+				return errors.New("virtual-link transit area " + *vlink.Config.RransitAreaId + " not defined")
+			}
+			if *transit.Config.AreaType != "normal" {
+				return errors.New("virtual-link transit area " + *vlink.Config.RransitAreaId + " not a normal area")
+			}
+		}
+	}
 	return nil
 }
@@ -0,0 +1,166 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pcap
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+const (
+	MAGIC_NUMBER      = 0xa1b2c3d4
+	MAGIC_NUMBER_NSEC = 0xa1b23c4d
+	VERSION_MAJOR     = 2
+	VERSION_MINOR     = 4
+	SNAPLEN           = 65535
+
+	LINKTYPE_ETHERNET  = 1
+	LINKTYPE_LINUX_SLL = 113
+)
+
+const (
+	globalHeaderLength = 24
+	recordHeaderLength = 16
+)
+
+type Writer struct {
+	w        io.Writer
+	linkType uint32
+	lock     sync.Mutex
+}
+
+func NewWriter(w io.Writer, linkType uint32) (*Writer, error) {
+	if w == nil {
+		return nil, errors.New("NewWriter: writer nil")
+	}
+	hdr := make([]byte, globalHeaderLength)
+	binary.LittleEndian.PutUint32(hdr[0:4], MAGIC_NUMBER)
+	binary.LittleEndian.PutUint16(hdr[4:6], VERSION_MAJOR)
+	binary.LittleEndian.PutUint16(hdr[6:8], VERSION_MINOR)
+	// thiszone and sigfigs are always zero
+	binary.LittleEndian.PutUint32(hdr[16:20], SNAPLEN)
+	binary.LittleEndian.PutUint32(hdr[20:24], linkType)
+	if _, err := w.Write(hdr); err != nil {
+		return nil, err
+	}
+	writer := &Writer{
+		w:        w,
+		linkType: linkType,
+	}
+	return writer, nil
+}
+
+func (writer *Writer) LinkType() uint32 {
+	return writer.linkType
+}
+
+func (writer *Writer) WritePacket(t time.Time, data []byte) error {
+	caplen := len(data)
+	if caplen > SNAPLEN {
+		caplen = SNAPLEN
+	}
+	rec := make([]byte, recordHeaderLength+caplen)
+	binary.LittleEndian.PutUint32(rec[0:4], uint32(t.Unix()))
+	binary.LittleEndian.PutUint32(rec[4:8], uint32(t.Nanosecond()/1000))
+	binary.LittleEndian.PutUint32(rec[8:12], uint32(caplen))
+	binary.LittleEndian.PutUint32(rec[12:16], uint32(len(data)))
+	copy(rec[recordHeaderLength:], data[0:caplen])
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	_, err := writer.w.Write(rec)
+	return err
+}
+
+// Ieee8023Frame builds an IEEE 802.3 frame with a length field, which is
+// how IS-IS PDUs appear on a broadcast circuit.
+func Ieee8023Frame(dst, src, payload []byte) []byte {
+	frame := make([]byte, 14+len(payload))
+	copy(frame[0:6], dst)
+	copy(frame[6:12], src)
+	binary.BigEndian.PutUint16(frame[12:14], uint16(len(payload)))
+	copy(frame[14:], payload)
+	return frame
+}
+
+type Reader struct {
+	r        io.Reader
+	order    binary.ByteOrder
+	nsec     bool
+	linkType uint32
+}
+
+func NewReader(r io.Reader) (*Reader, error) {
+	if r == nil {
+		return nil, errors.New("NewReader: reader nil")
+	}
+	hdr := make([]byte, globalHeaderLength)
+	if _, err := io.ReadFull(r, hdr); err != nil {
+		return nil, err
+	}
+	reader := &Reader{
+		r: r,
+	}
+	switch {
+	case binary.LittleEndian.Uint32(hdr[0:4]) == MAGIC_NUMBER:
+		reader.order = binary.LittleEndian
+	case binary.BigEndian.Uint32(hdr[0:4]) == MAGIC_NUMBER:
+		reader.order = binary.BigEndian
+	case binary.LittleEndian.Uint32(hdr[0:4]) == MAGIC_NUMBER_NSEC:
+		reader.order = binary.LittleEndian
+		reader.nsec = true
+	case binary.BigEndian.Uint32(hdr[0:4]) == MAGIC_NUMBER_NSEC:
+		reader.order = binary.BigEndian
+		reader.nsec = true
+	default:
+		return nil, errors.New("NewReader: unknown magic number (pcapng is not supported)")
+	}
+	reader.linkType = reader.order.Uint32(hdr[20:24])
+	return reader, nil
+}
+
+func (reader *Reader) LinkType() uint32 {
+	return reader.linkType
+}
+
+// ReadPacket returns io.EOF after the last record.
+func (reader *Reader) ReadPacket() (time.Time, []byte, error) {
+	rec := make([]byte, recordHeaderLength)
+	if _, err := io.ReadFull(reader.r, rec); err != nil {
+		return time.Time{}, nil, err
+	}
+	sec := int64(reader.order.Uint32(rec[0:4]))
+	frac := int64(reader.order.Uint32(rec[4:8]))
+	caplen := reader.order.Uint32(rec[8:12])
+	if caplen > SNAPLEN*4 {
+		return time.Time{}, nil, errors.New("ReadPacket: caplen too large")
+	}
+	data := make([]byte, caplen)
+	if _, err := io.ReadFull(reader.r, data); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return time.Time{}, nil, err
+	}
+	if !reader.nsec {
+		frac *= 1000
+	}
+	return time.Unix(sec, frac), data, nil
+}
@@ -0,0 +1,71 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pcap
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestWriterReader(t *testing.T) {
+	var err error
+	var b bytes.Buffer
+
+	w, err := NewWriter(&b, LINKTYPE_ETHERNET)
+	if err != nil {
+		t.Fatalf("failed NewWriter: %#v", err)
+	}
+
+	t1 := time.Unix(1546300800, 123456000)
+	d1 := Ieee8023Frame([]byte{0x01, 0x80, 0xc2, 0x00, 0x00, 0x14},
+		[]byte{0x00, 0x11, 0x22, 0x33, 0x44, 0x55},
+		[]byte{0xfe, 0xfe, 0x03, 0x83})
+	err = w.WritePacket(t1, d1)
+	if err != nil {
+		t.Fatalf("failed WritePacket: %#v", err)
+	}
+
+	r, err := NewReader(&b)
+	if err != nil {
+		t.Fatalf("failed NewReader: %#v", err)
+	}
+	if r.LinkType() != LINKTYPE_ETHERNET {
+		t.Fatalf("failed LinkType: %d", r.LinkType())
+	}
+
+	t2, d2, err := r.ReadPacket()
+	if err != nil {
+		t.Fatalf("failed ReadPacket: %#v", err)
+	}
+	if !t1.Equal(t2) {
+		t.Fatalf("failed !Equal %s %s", t1, t2)
+	}
+	if !bytes.Equal(d1, d2) {
+		t.Fatalf("failed !bytes.Equal")
+	}
+	if d2[12] != 0x00 || d2[13] != 0x04 {
+		t.Fatalf("failed length field %02x%02x", d2[12], d2[13])
+	}
+
+	_, _, err = r.ReadPacket()
+	if err != io.EOF {
+		t.Fatalf("failed io.EOF: %#v", err)
+	}
+}
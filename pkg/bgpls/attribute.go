@@ -0,0 +1,135 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bgpls
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+)
+
+const (
+	NODE_FLAG_BIT_OVERLOAD uint8 = 0x80
+	NODE_FLAG_BIT_ATTACHED uint8 = 0x40
+)
+
+// Attribute is the BGP-LS Attribute (RFC 7752 3.3), a list of node, link
+// or prefix attribute TLVs.
+type Attribute struct {
+	Tlvs []*Tlv
+}
+
+func NewAttribute() *Attribute {
+	return &Attribute{
+		Tlvs: make([]*Tlv, 0),
+	}
+}
+
+func (attr *Attribute) Tlv(tlvType TlvType) *Tlv {
+	for _, tlv := range attr.Tlvs {
+		if tlv.Type == tlvType {
+			return tlv
+		}
+	}
+	return nil
+}
+
+func (attr *Attribute) add(tlvType TlvType, value []byte) {
+	attr.Tlvs = append(attr.Tlvs, NewTlv(tlvType, value))
+}
+
+func (attr *Attribute) AddNodeFlagBits(flags uint8) {
+	attr.add(TLV_TYPE_NODE_FLAG_BITS, []byte{flags})
+}
+
+func (attr *Attribute) AddNodeName(name string) {
+	attr.add(TLV_TYPE_NODE_NAME, []byte(name))
+}
+
+func (attr *Attribute) AddIsisAreaIdentifier(areaAddress []byte) {
+	attr.add(TLV_TYPE_ISIS_AREA_IDENTIFIER, areaAddress)
+}
+
+func (attr *Attribute) AddIpv4RouterIdOfLocalNode(routerId uint32) {
+	attr.add(TLV_TYPE_IPV4_ROUTER_ID_OF_LOCAL_NODE, uint32Value(routerId))
+}
+
+func (attr *Attribute) AddAdministrativeGroup(adminGroup uint32) {
+	attr.add(TLV_TYPE_ADMINISTRATIVE_GROUP, uint32Value(adminGroup))
+}
+
+func (attr *Attribute) AddMaximumLinkBandwidth(bandwidth float32) {
+	attr.add(TLV_TYPE_MAXIMUM_LINK_BANDWIDTH, uint32Value(math.Float32bits(bandwidth)))
+}
+
+func (attr *Attribute) AddMaxReservableLinkBandwidth(bandwidth float32) {
+	attr.add(TLV_TYPE_MAX_RESERVABLE_LINK_BANDWIDTH, uint32Value(math.Float32bits(bandwidth)))
+}
+
+func (attr *Attribute) AddUnreservedBandwidth(bandwidths [8]float32) {
+	value := make([]byte, 0)
+	for _, bandwidth := range bandwidths {
+		value = append(value, uint32Value(math.Float32bits(bandwidth))...)
+	}
+	attr.add(TLV_TYPE_UNRESERVED_BANDWIDTH, value)
+}
+
+func (attr *Attribute) AddTeDefaultMetric(metric uint32) {
+	attr.add(TLV_TYPE_TE_DEFAULT_METRIC, uint32Value(metric))
+}
+
+// AddIgpMetric encodes metric in 1 octet for IS-IS narrow metrics and in
+// 3 octets for wide metrics (RFC 7752 3.3.2.4).
+func (attr *Attribute) AddIgpMetric(metric uint32, wide bool) {
+	if !wide {
+		attr.add(TLV_TYPE_IGP_METRIC, []byte{byte(metric & 0x3f)})
+		return
+	}
+	attr.add(TLV_TYPE_IGP_METRIC, uint32Value(metric & 0xffffff)[1:4])
+}
+
+func (attr *Attribute) AddPrefixMetric(metric uint32) {
+	attr.add(TLV_TYPE_PREFIX_METRIC, uint32Value(metric))
+}
+
+func (attr *Attribute) Serialize() []byte {
+	var b bytes.Buffer
+	for _, tlv := range attr.Tlvs {
+		b.Write(tlv.Serialize())
+	}
+	return b.Bytes()
+}
+
+func DecodeAttribute(data []byte) (*Attribute, error) {
+	tlvs, err := DecodeTlvs(data)
+	if err != nil {
+		return nil, err
+	}
+	return &Attribute{
+		Tlvs: tlvs,
+	}, nil
+}
+
+// Uint32 returns the value of a 4 octet TLV, or 0 if tlvType is absent.
+func (attr *Attribute) Uint32(tlvType TlvType) uint32 {
+	tlv := attr.Tlv(tlvType)
+	if tlv == nil || len(tlv.Value) != 4 {
+		return 0
+	}
+	return binary.BigEndian.Uint32(tlv.Value)
+}
@@ -0,0 +1,420 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bgpls
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	BGP_PORT               = 179
+	BGP_VERSION            = 4
+	BGP_HEADER_LENGTH      = 19
+	BGP_MAX_MESSAGE_LENGTH = 4096
+	AS_TRANS               = 23456
+)
+
+type MessageType uint8
+
+const (
+	_ MessageType = iota
+	MESSAGE_TYPE_OPEN
+	MESSAGE_TYPE_UPDATE
+	MESSAGE_TYPE_NOTIFICATION
+	MESSAGE_TYPE_KEEPALIVE
+)
+
+func (messageType MessageType) String() string {
+	switch messageType {
+	case MESSAGE_TYPE_OPEN:
+		return "MESSAGE_TYPE_OPEN"
+	case MESSAGE_TYPE_UPDATE:
+		return "MESSAGE_TYPE_UPDATE"
+	case MESSAGE_TYPE_NOTIFICATION:
+		return "MESSAGE_TYPE_NOTIFICATION"
+	case MESSAGE_TYPE_KEEPALIVE:
+		return "MESSAGE_TYPE_KEEPALIVE"
+	}
+	return fmt.Sprintf("MessageType(%d)", messageType)
+}
+
+const (
+	PATH_ATTR_FLAG_OPTIONAL        = 0x80
+	PATH_ATTR_FLAG_TRANSITIVE      = 0x40
+	PATH_ATTR_FLAG_EXTENDED_LENGTH = 0x10
+)
+
+const (
+	PATH_ATTR_TYPE_ORIGIN          = 1
+	PATH_ATTR_TYPE_AS_PATH         = 2
+	PATH_ATTR_TYPE_LOCAL_PREF      = 5
+	PATH_ATTR_TYPE_MP_REACH_NLRI   = 14
+	PATH_ATTR_TYPE_MP_UNREACH_NLRI = 15
+	PATH_ATTR_TYPE_BGP_LS          = 29
+)
+
+const (
+	CAPABILITY_CODE_MULTIPROTOCOL = 1
+	CAPABILITY_CODE_FOUR_OCTET_AS = 65
+)
+
+const (
+	NOTIFICATION_CODE_MESSAGE_HEADER_ERROR = 1
+	NOTIFICATION_CODE_OPEN_MESSAGE_ERROR   = 2
+	NOTIFICATION_CODE_UPDATE_MESSAGE_ERROR = 3
+	NOTIFICATION_CODE_HOLD_TIMER_EXPIRED   = 4
+	NOTIFICATION_CODE_FSM_ERROR            = 5
+	NOTIFICATION_CODE_CEASE                = 6
+)
+
+const (
+	NOTIFICATION_SUBCODE_UNSUPPORTED_VERSION_NUMBER = 1
+	NOTIFICATION_SUBCODE_BAD_PEER_AS                = 2
+	NOTIFICATION_SUBCODE_ADMINISTRATIVE_SHUTDOWN    = 2
+)
+
+func serializeMessage(messageType MessageType, body []byte) ([]byte, error) {
+	length := BGP_HEADER_LENGTH + len(body)
+	if length > BGP_MAX_MESSAGE_LENGTH {
+		return nil, errors.New("serializeMessage: message too long")
+	}
+	data := make([]byte, length)
+	for i := 0; i < 16; i++ {
+		data[i] = 0xff
+	}
+	binary.BigEndian.PutUint16(data[16:18], uint16(length))
+	data[18] = byte(messageType)
+	copy(data[BGP_HEADER_LENGTH:], body)
+	return data, nil
+}
+
+// ReadMessage reads one BGP message from r and returns its type and the
+// octets following the header.
+func ReadMessage(r io.Reader) (MessageType, []byte, error) {
+	header := make([]byte, BGP_HEADER_LENGTH)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, nil, err
+	}
+	for i := 0; i < 16; i++ {
+		if header[i] != 0xff {
+			return 0, nil, errors.New("ReadMessage: marker invalid")
+		}
+	}
+	length := int(binary.BigEndian.Uint16(header[16:18]))
+	if length < BGP_HEADER_LENGTH || length > BGP_MAX_MESSAGE_LENGTH {
+		return 0, nil, errors.New("ReadMessage: length invalid")
+	}
+	body := make([]byte, length-BGP_HEADER_LENGTH)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+	return MessageType(header[18]), body, nil
+}
+
+type OpenMessage struct {
+	Version       uint8
+	MyAs          uint32
+	HoldTime      uint16
+	BgpIdentifier uint32
+	BgpLs         bool
+	FourOctetAs   bool
+}
+
+func (open *OpenMessage) Serialize() ([]byte, error) {
+	var caps bytes.Buffer
+	if open.BgpLs {
+		caps.Write([]byte{CAPABILITY_CODE_MULTIPROTOCOL, 4, AFI_BGP_LS >> 8, AFI_BGP_LS & 0xff, 0, SAFI_BGP_LS})
+	}
+	if open.FourOctetAs {
+		caps.Write([]byte{CAPABILITY_CODE_FOUR_OCTET_AS, 4})
+		caps.Write(uint32Value(open.MyAs))
+	}
+	body := make([]byte, 10)
+	body[0] = open.Version
+	myAs := open.MyAs
+	if myAs > 0xffff {
+		myAs = AS_TRANS
+	}
+	binary.BigEndian.PutUint16(body[1:3], uint16(myAs))
+	binary.BigEndian.PutUint16(body[3:5], open.HoldTime)
+	binary.BigEndian.PutUint32(body[5:9], open.BgpIdentifier)
+	if caps.Len() > 0 {
+		body[9] = byte(2 + caps.Len())
+		body = append(body, 2, byte(caps.Len()))
+		body = append(body, caps.Bytes()...)
+	}
+	return serializeMessage(MESSAGE_TYPE_OPEN, body)
+}
+
+func DecodeOpenMessage(body []byte) (*OpenMessage, error) {
+	if len(body) < 10 || len(body) != 10+int(body[9]) {
+		return nil, errors.New("DecodeOpenMessage: length invalid")
+	}
+	open := &OpenMessage{
+		Version:       body[0],
+		MyAs:          uint32(binary.BigEndian.Uint16(body[1:3])),
+		HoldTime:      binary.BigEndian.Uint16(body[3:5]),
+		BgpIdentifier: binary.BigEndian.Uint32(body[5:9]),
+	}
+	params := body[10:]
+	for len(params) > 0 {
+		if len(params) < 2 || len(params) < 2+int(params[1]) {
+			return nil, errors.New("DecodeOpenMessage: parameter length invalid")
+		}
+		paramType := params[0]
+		caps := params[2 : 2+int(params[1])]
+		params = params[2+int(params[1]):]
+		if paramType != 2 {
+			continue
+		}
+		for len(caps) > 0 {
+			if len(caps) < 2 || len(caps) < 2+int(caps[1]) {
+				return nil, errors.New("DecodeOpenMessage: capability length invalid")
+			}
+			value := caps[2 : 2+int(caps[1])]
+			switch caps[0] {
+			case CAPABILITY_CODE_MULTIPROTOCOL:
+				if len(value) == 4 &&
+					binary.BigEndian.Uint16(value[0:2]) == AFI_BGP_LS && value[3] == SAFI_BGP_LS {
+					open.BgpLs = true
+				}
+			case CAPABILITY_CODE_FOUR_OCTET_AS:
+				if len(value) == 4 {
+					open.FourOctetAs = true
+					open.MyAs = binary.BigEndian.Uint32(value)
+				}
+			}
+			caps = caps[2+int(caps[1]):]
+		}
+	}
+	return open, nil
+}
+
+func SerializeKeepaliveMessage() []byte {
+	data, _ := serializeMessage(MESSAGE_TYPE_KEEPALIVE, nil)
+	return data
+}
+
+type NotificationMessage struct {
+	Code    uint8
+	Subcode uint8
+	Data    []byte
+}
+
+func (notification *NotificationMessage) Serialize() ([]byte, error) {
+	body := append([]byte{notification.Code, notification.Subcode}, notification.Data...)
+	return serializeMessage(MESSAGE_TYPE_NOTIFICATION, body)
+}
+
+func DecodeNotificationMessage(body []byte) (*NotificationMessage, error) {
+	if len(body) < 2 {
+		return nil, errors.New("DecodeNotificationMessage: length invalid")
+	}
+	return &NotificationMessage{
+		Code:    body[0],
+		Subcode: body[1],
+		Data:    body[2:],
+	}, nil
+}
+
+func (notification *NotificationMessage) String() string {
+	return fmt.Sprintf("notification %d/%d", notification.Code, notification.Subcode)
+}
+
+// UpdateMessage carries BGP-LS NLRIs only. Reach and Unreach are sent in
+// MP_REACH_NLRI and MP_UNREACH_NLRI, all Reach NLRIs share Attribute.
+type UpdateMessage struct {
+	AsPath    []uint32
+	FourOctet bool
+	LocalPref *uint32
+	NextHop   uint32
+	Reach     []*Nlri
+	Unreach   []*Nlri
+	Attribute *Attribute
+}
+
+func serializePathAttribute(flags, attrType uint8, value []byte) []byte {
+	if len(value) > 0xff {
+		flags |= PATH_ATTR_FLAG_EXTENDED_LENGTH
+		data := []byte{flags, attrType, byte(len(value) >> 8), byte(len(value))}
+		return append(data, value...)
+	}
+	return append([]byte{flags, attrType, byte(len(value))}, value...)
+}
+
+func serializeNlris(nlris []*Nlri) ([]byte, error) {
+	var b bytes.Buffer
+	for _, nlri := range nlris {
+		data, err := nlri.Serialize()
+		if err != nil {
+			return nil, err
+		}
+		b.Write(data)
+	}
+	return b.Bytes(), nil
+}
+
+func (update *UpdateMessage) Serialize() ([]byte, error) {
+	var attrs bytes.Buffer
+	if len(update.Unreach) > 0 {
+		nlris, err := serializeNlris(update.Unreach)
+		if err != nil {
+			return nil, err
+		}
+		value := append([]byte{AFI_BGP_LS >> 8, AFI_BGP_LS & 0xff, SAFI_BGP_LS}, nlris...)
+		attrs.Write(serializePathAttribute(PATH_ATTR_FLAG_OPTIONAL, PATH_ATTR_TYPE_MP_UNREACH_NLRI, value))
+	}
+	if len(update.Reach) > 0 {
+		attrs.Write(serializePathAttribute(PATH_ATTR_FLAG_TRANSITIVE, PATH_ATTR_TYPE_ORIGIN, []byte{0}))
+		var asPath []byte
+		if len(update.AsPath) > 0 {
+			asPath = []byte{2, byte(len(update.AsPath))}
+			for _, as := range update.AsPath {
+				if update.FourOctet {
+					asPath = append(asPath, uint32Value(as)...)
+				} else {
+					if as > 0xffff {
+						as = AS_TRANS
+					}
+					asPath = append(asPath, byte(as>>8), byte(as))
+				}
+			}
+		}
+		attrs.Write(serializePathAttribute(PATH_ATTR_FLAG_TRANSITIVE, PATH_ATTR_TYPE_AS_PATH, asPath))
+		if update.LocalPref != nil {
+			attrs.Write(serializePathAttribute(PATH_ATTR_FLAG_TRANSITIVE, PATH_ATTR_TYPE_LOCAL_PREF, uint32Value(*update.LocalPref)))
+		}
+		nlris, err := serializeNlris(update.Reach)
+		if err != nil {
+			return nil, err
+		}
+		value := []byte{AFI_BGP_LS >> 8, AFI_BGP_LS & 0xff, SAFI_BGP_LS, 4}
+		value = append(value, uint32Value(update.NextHop)...)
+		value = append(value, 0)
+		value = append(value, nlris...)
+		attrs.Write(serializePathAttribute(PATH_ATTR_FLAG_OPTIONAL, PATH_ATTR_TYPE_MP_REACH_NLRI, value))
+		if update.Attribute != nil {
+			attrs.Write(serializePathAttribute(PATH_ATTR_FLAG_OPTIONAL, PATH_ATTR_TYPE_BGP_LS, update.Attribute.Serialize()))
+		}
+	}
+	body := make([]byte, 4)
+	binary.BigEndian.PutUint16(body[2:4], uint16(attrs.Len()))
+	body = append(body, attrs.Bytes()...)
+	return serializeMessage(MESSAGE_TYPE_UPDATE, body)
+}
+
+func decodeNlris(data []byte) ([]*Nlri, error) {
+	nlris := make([]*Nlri, 0)
+	for len(data) > 0 {
+		nlri, n, err := DecodeNlri(data)
+		if err != nil {
+			return nil, err
+		}
+		nlris = append(nlris, nlri)
+		data = data[n:]
+	}
+	return nlris, nil
+}
+
+// DecodeUpdateMessage decodes the BGP-LS parts of an UPDATE. Other
+// address families are ignored.
+func DecodeUpdateMessage(body []byte) (*UpdateMessage, error) {
+	if len(body) < 4 {
+		return nil, errors.New("DecodeUpdateMessage: length invalid")
+	}
+	withdrawnLength := int(binary.BigEndian.Uint16(body[0:2]))
+	if len(body) < 4+withdrawnLength {
+		return nil, errors.New("DecodeUpdateMessage: withdrawn routes length invalid")
+	}
+	attrsLength := int(binary.BigEndian.Uint16(body[2+withdrawnLength : 4+withdrawnLength]))
+	attrs := body[4+withdrawnLength:]
+	if len(attrs) < attrsLength {
+		return nil, errors.New("DecodeUpdateMessage: path attribute length invalid")
+	}
+	attrs = attrs[0:attrsLength]
+	update := &UpdateMessage{
+		Reach:   make([]*Nlri, 0),
+		Unreach: make([]*Nlri, 0),
+	}
+	for len(attrs) > 0 {
+		if len(attrs) < 3 {
+			return nil, errors.New("DecodeUpdateMessage: path attribute truncated")
+		}
+		flags := attrs[0]
+		attrType := attrs[1]
+		var length, offset int
+		if flags&PATH_ATTR_FLAG_EXTENDED_LENGTH != 0 {
+			if len(attrs) < 4 {
+				return nil, errors.New("DecodeUpdateMessage: path attribute truncated")
+			}
+			length = int(binary.BigEndian.Uint16(attrs[2:4]))
+			offset = 4
+		} else {
+			length = int(attrs[2])
+			offset = 3
+		}
+		if len(attrs) < offset+length {
+			return nil, errors.New("DecodeUpdateMessage: path attribute length invalid")
+		}
+		value := attrs[offset : offset+length]
+		attrs = attrs[offset+length:]
+		switch attrType {
+		case PATH_ATTR_TYPE_LOCAL_PREF:
+			if len(value) == 4 {
+				localPref := binary.BigEndian.Uint32(value)
+				update.LocalPref = &localPref
+			}
+		case PATH_ATTR_TYPE_MP_REACH_NLRI:
+			if len(value) < 5 || binary.BigEndian.Uint16(value[0:2]) != AFI_BGP_LS || value[2] != SAFI_BGP_LS {
+				continue
+			}
+			nhLength := int(value[3])
+			if len(value) < 5+nhLength {
+				return nil, errors.New("DecodeUpdateMessage: next hop length invalid")
+			}
+			if nhLength == 4 {
+				update.NextHop = binary.BigEndian.Uint32(value[4:8])
+			}
+			nlris, err := decodeNlris(value[5+nhLength:])
+			if err != nil {
+				return nil, err
+			}
+			update.Reach = nlris
+		case PATH_ATTR_TYPE_MP_UNREACH_NLRI:
+			if len(value) < 3 || binary.BigEndian.Uint16(value[0:2]) != AFI_BGP_LS || value[2] != SAFI_BGP_LS {
+				continue
+			}
+			nlris, err := decodeNlris(value[3:])
+			if err != nil {
+				return nil, err
+			}
+			update.Unreach = nlris
+		case PATH_ATTR_TYPE_BGP_LS:
+			attribute, err := DecodeAttribute(value)
+			if err != nil {
+				return nil, err
+			}
+			update.Attribute = attribute
+		}
+	}
+	return update, nil
+}
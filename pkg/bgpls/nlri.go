@@ -0,0 +1,393 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bgpls
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+const (
+	AFI_BGP_LS  = 16388
+	SAFI_BGP_LS = 71
+)
+
+type NlriType uint16
+
+const (
+	_ NlriType = iota
+	NLRI_TYPE_NODE
+	NLRI_TYPE_LINK
+	NLRI_TYPE_IPV4_PREFIX
+	NLRI_TYPE_IPV6_PREFIX
+)
+
+func (nlriType NlriType) String() string {
+	switch nlriType {
+	case NLRI_TYPE_NODE:
+		return "NLRI_TYPE_NODE"
+	case NLRI_TYPE_LINK:
+		return "NLRI_TYPE_LINK"
+	case NLRI_TYPE_IPV4_PREFIX:
+		return "NLRI_TYPE_IPV4_PREFIX"
+	case NLRI_TYPE_IPV6_PREFIX:
+		return "NLRI_TYPE_IPV6_PREFIX"
+	}
+	return fmt.Sprintf("NlriType(%d)", nlriType)
+}
+
+type ProtocolId uint8
+
+const (
+	_ ProtocolId = iota
+	PROTOCOL_ID_ISIS_LEVEL1
+	PROTOCOL_ID_ISIS_LEVEL2
+	PROTOCOL_ID_OSPFV2
+	PROTOCOL_ID_DIRECT
+	PROTOCOL_ID_STATIC
+	PROTOCOL_ID_OSPFV3
+)
+
+func (protocolId ProtocolId) String() string {
+	switch protocolId {
+	case PROTOCOL_ID_ISIS_LEVEL1:
+		return "PROTOCOL_ID_ISIS_LEVEL1"
+	case PROTOCOL_ID_ISIS_LEVEL2:
+		return "PROTOCOL_ID_ISIS_LEVEL2"
+	case PROTOCOL_ID_OSPFV2:
+		return "PROTOCOL_ID_OSPFV2"
+	case PROTOCOL_ID_DIRECT:
+		return "PROTOCOL_ID_DIRECT"
+	case PROTOCOL_ID_STATIC:
+		return "PROTOCOL_ID_STATIC"
+	case PROTOCOL_ID_OSPFV3:
+		return "PROTOCOL_ID_OSPFV3"
+	}
+	return fmt.Sprintf("ProtocolId(%d)", protocolId)
+}
+
+type TlvType uint16
+
+const (
+	TLV_TYPE_LOCAL_NODE_DESCRIPTORS        TlvType = 256
+	TLV_TYPE_REMOTE_NODE_DESCRIPTORS       TlvType = 257
+	TLV_TYPE_LINK_LOCAL_REMOTE_IDENTIFIERS TlvType = 258
+	TLV_TYPE_IPV4_INTERFACE_ADDRESS        TlvType = 259
+	TLV_TYPE_IPV4_NEIGHBOR_ADDRESS         TlvType = 260
+	TLV_TYPE_IPV6_INTERFACE_ADDRESS        TlvType = 261
+	TLV_TYPE_IPV6_NEIGHBOR_ADDRESS         TlvType = 262
+	TLV_TYPE_MULTI_TOPOLOGY_ID             TlvType = 263
+	TLV_TYPE_OSPF_ROUTE_TYPE               TlvType = 264
+	TLV_TYPE_IP_REACHABILITY_INFORMATION   TlvType = 265
+	TLV_TYPE_AUTONOMOUS_SYSTEM             TlvType = 512
+	TLV_TYPE_BGP_LS_IDENTIFIER             TlvType = 513
+	TLV_TYPE_OSPF_AREA_ID                  TlvType = 514
+	TLV_TYPE_IGP_ROUTER_ID                 TlvType = 515
+	TLV_TYPE_NODE_FLAG_BITS                TlvType = 1024
+	TLV_TYPE_NODE_NAME                     TlvType = 1026
+	TLV_TYPE_ISIS_AREA_IDENTIFIER          TlvType = 1027
+	TLV_TYPE_IPV4_ROUTER_ID_OF_LOCAL_NODE  TlvType = 1028
+	TLV_TYPE_IPV6_ROUTER_ID_OF_LOCAL_NODE  TlvType = 1029
+	TLV_TYPE_ADMINISTRATIVE_GROUP          TlvType = 1088
+	TLV_TYPE_MAXIMUM_LINK_BANDWIDTH        TlvType = 1089
+	TLV_TYPE_MAX_RESERVABLE_LINK_BANDWIDTH TlvType = 1090
+	TLV_TYPE_UNRESERVED_BANDWIDTH          TlvType = 1091
+	TLV_TYPE_TE_DEFAULT_METRIC             TlvType = 1092
+	TLV_TYPE_IGP_METRIC                    TlvType = 1095
+	TLV_TYPE_PREFIX_METRIC                 TlvType = 1155
+)
+
+func (tlvType TlvType) String() string {
+	switch tlvType {
+	case TLV_TYPE_LOCAL_NODE_DESCRIPTORS:
+		return "TLV_TYPE_LOCAL_NODE_DESCRIPTORS"
+	case TLV_TYPE_REMOTE_NODE_DESCRIPTORS:
+		return "TLV_TYPE_REMOTE_NODE_DESCRIPTORS"
+	case TLV_TYPE_LINK_LOCAL_REMOTE_IDENTIFIERS:
+		return "TLV_TYPE_LINK_LOCAL_REMOTE_IDENTIFIERS"
+	case TLV_TYPE_IPV4_INTERFACE_ADDRESS:
+		return "TLV_TYPE_IPV4_INTERFACE_ADDRESS"
+	case TLV_TYPE_IPV4_NEIGHBOR_ADDRESS:
+		return "TLV_TYPE_IPV4_NEIGHBOR_ADDRESS"
+	case TLV_TYPE_IPV6_INTERFACE_ADDRESS:
+		return "TLV_TYPE_IPV6_INTERFACE_ADDRESS"
+	case TLV_TYPE_IPV6_NEIGHBOR_ADDRESS:
+		return "TLV_TYPE_IPV6_NEIGHBOR_ADDRESS"
+	case TLV_TYPE_MULTI_TOPOLOGY_ID:
+		return "TLV_TYPE_MULTI_TOPOLOGY_ID"
+	case TLV_TYPE_OSPF_ROUTE_TYPE:
+		return "TLV_TYPE_OSPF_ROUTE_TYPE"
+	case TLV_TYPE_IP_REACHABILITY_INFORMATION:
+		return "TLV_TYPE_IP_REACHABILITY_INFORMATION"
+	case TLV_TYPE_AUTONOMOUS_SYSTEM:
+		return "TLV_TYPE_AUTONOMOUS_SYSTEM"
+	case TLV_TYPE_BGP_LS_IDENTIFIER:
+		return "TLV_TYPE_BGP_LS_IDENTIFIER"
+	case TLV_TYPE_OSPF_AREA_ID:
+		return "TLV_TYPE_OSPF_AREA_ID"
+	case TLV_TYPE_IGP_ROUTER_ID:
+		return "TLV_TYPE_IGP_ROUTER_ID"
+	case TLV_TYPE_NODE_FLAG_BITS:
+		return "TLV_TYPE_NODE_FLAG_BITS"
+	case TLV_TYPE_NODE_NAME:
+		return "TLV_TYPE_NODE_NAME"
+	case TLV_TYPE_ISIS_AREA_IDENTIFIER:
+		return "TLV_TYPE_ISIS_AREA_IDENTIFIER"
+	case TLV_TYPE_IPV4_ROUTER_ID_OF_LOCAL_NODE:
+		return "TLV_TYPE_IPV4_ROUTER_ID_OF_LOCAL_NODE"
+	case TLV_TYPE_IPV6_ROUTER_ID_OF_LOCAL_NODE:
+		return "TLV_TYPE_IPV6_ROUTER_ID_OF_LOCAL_NODE"
+	case TLV_TYPE_ADMINISTRATIVE_GROUP:
+		return "TLV_TYPE_ADMINISTRATIVE_GROUP"
+	case TLV_TYPE_MAXIMUM_LINK_BANDWIDTH:
+		return "TLV_TYPE_MAXIMUM_LINK_BANDWIDTH"
+	case TLV_TYPE_MAX_RESERVABLE_LINK_BANDWIDTH:
+		return "TLV_TYPE_MAX_RESERVABLE_LINK_BANDWIDTH"
+	case TLV_TYPE_UNRESERVED_BANDWIDTH:
+		return "TLV_TYPE_UNRESERVED_BANDWIDTH"
+	case TLV_TYPE_TE_DEFAULT_METRIC:
+		return "TLV_TYPE_TE_DEFAULT_METRIC"
+	case TLV_TYPE_IGP_METRIC:
+		return "TLV_TYPE_IGP_METRIC"
+	case TLV_TYPE_PREFIX_METRIC:
+		return "TLV_TYPE_PREFIX_METRIC"
+	}
+	return fmt.Sprintf("TlvType(%d)", tlvType)
+}
+
+type Tlv struct {
+	Type  TlvType
+	Value []byte
+}
+
+func NewTlv(tlvType TlvType, value []byte) *Tlv {
+	return &Tlv{
+		Type:  tlvType,
+		Value: value,
+	}
+}
+
+func (tlv *Tlv) Serialize() []byte {
+	data := make([]byte, 4+len(tlv.Value))
+	binary.BigEndian.PutUint16(data[0:2], uint16(tlv.Type))
+	binary.BigEndian.PutUint16(data[2:4], uint16(len(tlv.Value)))
+	copy(data[4:], tlv.Value)
+	return data
+}
+
+func DecodeTlvs(data []byte) ([]*Tlv, error) {
+	tlvs := make([]*Tlv, 0)
+	for len(data) > 0 {
+		if len(data) < 4 {
+			return nil, errors.New("DecodeTlvs: tlv truncated")
+		}
+		length := int(binary.BigEndian.Uint16(data[2:4]))
+		if len(data) < 4+length {
+			return nil, errors.New("DecodeTlvs: tlv length invalid")
+		}
+		value := make([]byte, length)
+		copy(value, data[4:4+length])
+		tlvs = append(tlvs, NewTlv(TlvType(binary.BigEndian.Uint16(data[0:2])), value))
+		data = data[4+length:]
+	}
+	return tlvs, nil
+}
+
+func uint32Value(v uint32) []byte {
+	value := make([]byte, 4)
+	binary.BigEndian.PutUint32(value, v)
+	return value
+}
+
+// NodeDescriptor identifies a node (RFC 7752 3.2.1.4). For IS-IS the
+// IGP router id is the 6 octet system id, or the 7 octet system id and
+// pseudonode id for a pseudonode.
+type NodeDescriptor struct {
+	AsNumber    uint32
+	BgpLsId     uint32
+	IgpRouterId []byte
+}
+
+func (desc *NodeDescriptor) serialize(tlvType TlvType) []byte {
+	var b bytes.Buffer
+	b.Write(NewTlv(TLV_TYPE_AUTONOMOUS_SYSTEM, uint32Value(desc.AsNumber)).Serialize())
+	b.Write(NewTlv(TLV_TYPE_BGP_LS_IDENTIFIER, uint32Value(desc.BgpLsId)).Serialize())
+	b.Write(NewTlv(TLV_TYPE_IGP_ROUTER_ID, desc.IgpRouterId).Serialize())
+	return NewTlv(tlvType, b.Bytes()).Serialize()
+}
+
+func (desc *NodeDescriptor) decodeFromBytes(data []byte) error {
+	tlvs, err := DecodeTlvs(data)
+	if err != nil {
+		return err
+	}
+	for _, tlv := range tlvs {
+		switch tlv.Type {
+		case TLV_TYPE_AUTONOMOUS_SYSTEM:
+			if len(tlv.Value) != 4 {
+				return errors.New("NodeDescriptor.decodeFromBytes: autonomous system length invalid")
+			}
+			desc.AsNumber = binary.BigEndian.Uint32(tlv.Value)
+		case TLV_TYPE_BGP_LS_IDENTIFIER:
+			if len(tlv.Value) != 4 {
+				return errors.New("NodeDescriptor.decodeFromBytes: bgp-ls identifier length invalid")
+			}
+			desc.BgpLsId = binary.BigEndian.Uint32(tlv.Value)
+		case TLV_TYPE_IGP_ROUTER_ID:
+			desc.IgpRouterId = tlv.Value
+		}
+	}
+	return nil
+}
+
+// LinkDescriptor holds the link descriptors IS-IS can fill in. A zero
+// address means the descriptor is not present.
+type LinkDescriptor struct {
+	Ipv4InterfaceAddress uint32
+	Ipv4NeighborAddress  uint32
+}
+
+// PrefixDescriptor holds the IP Reachability Information of a prefix.
+// Prefix is 4 or 16 octets long and only the first PrefixLength bits are
+// encoded.
+type PrefixDescriptor struct {
+	Prefix       []byte
+	PrefixLength uint8
+}
+
+type Nlri struct {
+	NlriType   NlriType
+	ProtocolId ProtocolId
+	Identifier uint64
+	LocalNode  NodeDescriptor
+	RemoteNode NodeDescriptor
+	Link       LinkDescriptor
+	Prefix     PrefixDescriptor
+}
+
+func (nlri *Nlri) String() string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s %s local %d/%d/%x", nlri.NlriType, nlri.ProtocolId,
+		nlri.LocalNode.AsNumber, nlri.LocalNode.BgpLsId, nlri.LocalNode.IgpRouterId)
+	switch nlri.NlriType {
+	case NLRI_TYPE_LINK:
+		fmt.Fprintf(&b, " remote %d/%d/%x", nlri.RemoteNode.AsNumber, nlri.RemoteNode.BgpLsId, nlri.RemoteNode.IgpRouterId)
+		fmt.Fprintf(&b, " if %08x neigh %08x", nlri.Link.Ipv4InterfaceAddress, nlri.Link.Ipv4NeighborAddress)
+	case NLRI_TYPE_IPV4_PREFIX, NLRI_TYPE_IPV6_PREFIX:
+		fmt.Fprintf(&b, " prefix %x/%d", nlri.Prefix.Prefix, nlri.Prefix.PrefixLength)
+	}
+	return b.String()
+}
+
+func (nlri *Nlri) Serialize() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte(byte(nlri.ProtocolId))
+	identifier := make([]byte, 8)
+	binary.BigEndian.PutUint64(identifier, nlri.Identifier)
+	b.Write(identifier)
+	b.Write(nlri.LocalNode.serialize(TLV_TYPE_LOCAL_NODE_DESCRIPTORS))
+	switch nlri.NlriType {
+	case NLRI_TYPE_NODE:
+	case NLRI_TYPE_LINK:
+		b.Write(nlri.RemoteNode.serialize(TLV_TYPE_REMOTE_NODE_DESCRIPTORS))
+		if nlri.Link.Ipv4InterfaceAddress != 0 {
+			b.Write(NewTlv(TLV_TYPE_IPV4_INTERFACE_ADDRESS, uint32Value(nlri.Link.Ipv4InterfaceAddress)).Serialize())
+		}
+		if nlri.Link.Ipv4NeighborAddress != 0 {
+			b.Write(NewTlv(TLV_TYPE_IPV4_NEIGHBOR_ADDRESS, uint32Value(nlri.Link.Ipv4NeighborAddress)).Serialize())
+		}
+	case NLRI_TYPE_IPV4_PREFIX, NLRI_TYPE_IPV6_PREFIX:
+		plen := int(nlri.Prefix.PrefixLength)
+		if plen > len(nlri.Prefix.Prefix)*8 {
+			return nil, errors.New("Nlri.Serialize: prefix length invalid")
+		}
+		value := make([]byte, 1+(plen+7)/8)
+		value[0] = nlri.Prefix.PrefixLength
+		copy(value[1:], nlri.Prefix.Prefix)
+		if plen%8 != 0 {
+			value[len(value)-1] &= byte(0xff << uint(8-plen%8))
+		}
+		b.Write(NewTlv(TLV_TYPE_IP_REACHABILITY_INFORMATION, value).Serialize())
+	default:
+		return nil, errors.New("Nlri.Serialize: nlri type invalid")
+	}
+	data := make([]byte, 4+b.Len())
+	binary.BigEndian.PutUint16(data[0:2], uint16(nlri.NlriType))
+	binary.BigEndian.PutUint16(data[2:4], uint16(b.Len()))
+	copy(data[4:], b.Bytes())
+	return data, nil
+}
+
+// DecodeNlri decodes the NLRI at the head of data and returns it with
+// the number of octets it occupied.
+func DecodeNlri(data []byte) (*Nlri, int, error) {
+	if len(data) < 4 {
+		return nil, 0, errors.New("DecodeNlri: nlri truncated")
+	}
+	length := int(binary.BigEndian.Uint16(data[2:4]))
+	if len(data) < 4+length || length < 9 {
+		return nil, 0, errors.New("DecodeNlri: nlri length invalid")
+	}
+	nlri := &Nlri{
+		NlriType:   NlriType(binary.BigEndian.Uint16(data[0:2])),
+		ProtocolId: ProtocolId(data[4]),
+		Identifier: binary.BigEndian.Uint64(data[5:13]),
+	}
+	tlvs, err := DecodeTlvs(data[13 : 4+length])
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, tlv := range tlvs {
+		switch tlv.Type {
+		case TLV_TYPE_LOCAL_NODE_DESCRIPTORS:
+			err = nlri.LocalNode.decodeFromBytes(tlv.Value)
+		case TLV_TYPE_REMOTE_NODE_DESCRIPTORS:
+			err = nlri.RemoteNode.decodeFromBytes(tlv.Value)
+		case TLV_TYPE_IPV4_INTERFACE_ADDRESS:
+			if len(tlv.Value) != 4 {
+				return nil, 0, errors.New("DecodeNlri: ipv4 interface address length invalid")
+			}
+			nlri.Link.Ipv4InterfaceAddress = binary.BigEndian.Uint32(tlv.Value)
+		case TLV_TYPE_IPV4_NEIGHBOR_ADDRESS:
+			if len(tlv.Value) != 4 {
+				return nil, 0, errors.New("DecodeNlri: ipv4 neighbor address length invalid")
+			}
+			nlri.Link.Ipv4NeighborAddress = binary.BigEndian.Uint32(tlv.Value)
+		case TLV_TYPE_IP_REACHABILITY_INFORMATION:
+			if len(tlv.Value) < 1 || len(tlv.Value) != 1+(int(tlv.Value[0])+7)/8 {
+				return nil, 0, errors.New("DecodeNlri: ip reachability information length invalid")
+			}
+			size := 4
+			if nlri.NlriType == NLRI_TYPE_IPV6_PREFIX {
+				size = 16
+			}
+			if int(tlv.Value[0]) > size*8 {
+				return nil, 0, errors.New("DecodeNlri: prefix length invalid")
+			}
+			nlri.Prefix.PrefixLength = tlv.Value[0]
+			nlri.Prefix.Prefix = make([]byte, size)
+			copy(nlri.Prefix.Prefix, tlv.Value[1:])
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+	return nlri, 4 + length, nil
+}
@@ -0,0 +1,183 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bgpls
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestNodeNlri(t *testing.T) {
+	var err error
+
+	d1 := []byte{
+		0x00, 0x01, 0x00, 0x27,
+		0x02,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x01, 0x00, 0x00, 0x1a,
+		0x02, 0x00, 0x00, 0x04, 0x00, 0x00, 0xfd, 0xe8,
+		0x02, 0x01, 0x00, 0x04, 0x00, 0x00, 0x00, 0x00,
+		0x02, 0x03, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01,
+	}
+
+	nlri := &Nlri{
+		NlriType:   NLRI_TYPE_NODE,
+		ProtocolId: PROTOCOL_ID_ISIS_LEVEL2,
+		LocalNode: NodeDescriptor{
+			AsNumber:    65000,
+			IgpRouterId: []byte{0, 0, 0, 0, 0, 1},
+		},
+	}
+	d2, err := nlri.Serialize()
+	if err != nil {
+		t.Fatalf("failed Serialize: %#v", err)
+	}
+	if !bytes.Equal(d1, d2) {
+		t.Fatalf("failed !Equal\n%x\n%x", d1, d2)
+	}
+
+	n, length, err := DecodeNlri(d1)
+	if err != nil {
+		t.Fatalf("failed DecodeNlri: %#v", err)
+	}
+	if length != len(d1) || n.NlriType != NLRI_TYPE_NODE || n.ProtocolId != PROTOCOL_ID_ISIS_LEVEL2 ||
+		n.LocalNode.AsNumber != 65000 || !bytes.Equal(n.LocalNode.IgpRouterId, nlri.LocalNode.IgpRouterId) {
+		t.Fatalf("failed DecodeNlri: %s", n)
+	}
+}
+
+func TestPrefixNlri(t *testing.T) {
+	nlri := &Nlri{
+		NlriType:   NLRI_TYPE_IPV4_PREFIX,
+		ProtocolId: PROTOCOL_ID_ISIS_LEVEL1,
+		LocalNode: NodeDescriptor{
+			AsNumber:    65000,
+			BgpLsId:     1,
+			IgpRouterId: []byte{0, 0, 0, 0, 0, 2},
+		},
+		Prefix: PrefixDescriptor{
+			Prefix:       []byte{10, 0, 1, 0xff},
+			PrefixLength: 20,
+		},
+	}
+	data, err := nlri.Serialize()
+	if err != nil {
+		t.Fatalf("failed Serialize: %#v", err)
+	}
+	// type 265, length 4, prefix length 20, 10.0.0.0 truncated to 3 octets
+	if !bytes.Equal(data[len(data)-8:], []byte{0x01, 0x09, 0x00, 0x04, 20, 10, 0, 0}) {
+		t.Fatalf("failed ip reachability information: %x", data)
+	}
+	n, _, err := DecodeNlri(data)
+	if err != nil {
+		t.Fatalf("failed DecodeNlri: %#v", err)
+	}
+	if n.Prefix.PrefixLength != 20 || !bytes.Equal(n.Prefix.Prefix, []byte{10, 0, 0, 0}) {
+		t.Fatalf("failed DecodeNlri: %s", n)
+	}
+}
+
+func TestUpdateMessage(t *testing.T) {
+	link := &Nlri{
+		NlriType:   NLRI_TYPE_LINK,
+		ProtocolId: PROTOCOL_ID_ISIS_LEVEL2,
+		LocalNode: NodeDescriptor{
+			AsNumber:    65000,
+			IgpRouterId: []byte{0, 0, 0, 0, 0, 1},
+		},
+		RemoteNode: NodeDescriptor{
+			AsNumber:    65000,
+			IgpRouterId: []byte{0, 0, 0, 0, 0, 2, 1},
+		},
+		Link: LinkDescriptor{
+			Ipv4InterfaceAddress: 0x0a000001,
+		},
+	}
+	attr := NewAttribute()
+	attr.AddIgpMetric(10, true)
+	attr.AddTeDefaultMetric(100)
+	localPref := uint32(100)
+	update := &UpdateMessage{
+		LocalPref: &localPref,
+		NextHop:   0x0a000001,
+		Reach:     []*Nlri{link},
+		Attribute: attr,
+	}
+	data, err := update.Serialize()
+	if err != nil {
+		t.Fatalf("failed Serialize: %#v", err)
+	}
+	messageType, body, err := ReadMessage(bytes.NewReader(data))
+	if err != nil || messageType != MESSAGE_TYPE_UPDATE {
+		t.Fatalf("failed ReadMessage: %s %#v", messageType, err)
+	}
+	u, err := DecodeUpdateMessage(body)
+	if err != nil {
+		t.Fatalf("failed DecodeUpdateMessage: %#v", err)
+	}
+	if len(u.Reach) != 1 || len(u.Unreach) != 0 || u.NextHop != 0x0a000001 {
+		t.Fatalf("failed DecodeUpdateMessage: %#v", u)
+	}
+	if u.Reach[0].String() != link.String() {
+		t.Fatalf("failed Reach\n%s\n%s", u.Reach[0], link)
+	}
+	if u.LocalPref == nil || *u.LocalPref != 100 {
+		t.Fatalf("failed LocalPref")
+	}
+	if u.Attribute == nil || !bytes.Equal(u.Attribute.Tlv(TLV_TYPE_IGP_METRIC).Value, []byte{0, 0, 10}) ||
+		u.Attribute.Uint32(TLV_TYPE_TE_DEFAULT_METRIC) != 100 {
+		t.Fatalf("failed Attribute: %#v", u.Attribute)
+	}
+
+	withdraw := &UpdateMessage{
+		Unreach: []*Nlri{link},
+	}
+	data, err = withdraw.Serialize()
+	if err != nil {
+		t.Fatalf("failed Serialize: %#v", err)
+	}
+	u, err = DecodeUpdateMessage(data[BGP_HEADER_LENGTH:])
+	if err != nil {
+		t.Fatalf("failed DecodeUpdateMessage: %#v", err)
+	}
+	if len(u.Reach) != 0 || len(u.Unreach) != 1 || u.Unreach[0].String() != link.String() {
+		t.Fatalf("failed withdraw: %#v", u)
+	}
+}
+
+func TestOpenMessage(t *testing.T) {
+	open := &OpenMessage{
+		Version:       BGP_VERSION,
+		MyAs:          4200000000,
+		HoldTime:      90,
+		BgpIdentifier: 0x0a000001,
+		BgpLs:         true,
+		FourOctetAs:   true,
+	}
+	data, err := open.Serialize()
+	if err != nil {
+		t.Fatalf("failed Serialize: %#v", err)
+	}
+	o, err := DecodeOpenMessage(data[BGP_HEADER_LENGTH:])
+	if err != nil {
+		t.Fatalf("failed DecodeOpenMessage: %#v", err)
+	}
+	if *o != *open {
+		t.Fatalf("failed DecodeOpenMessage: %#v", o)
+	}
+}
@@ -0,0 +1,450 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bgpls
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type SessionState uint8
+
+const (
+	_ SessionState = iota
+	SESSION_STATE_IDLE
+	SESSION_STATE_CONNECT
+	SESSION_STATE_OPEN_SENT
+	SESSION_STATE_OPEN_CONFIRM
+	SESSION_STATE_ESTABLISHED
+)
+
+func (state SessionState) String() string {
+	switch state {
+	case SESSION_STATE_IDLE:
+		return "SESSION_STATE_IDLE"
+	case SESSION_STATE_CONNECT:
+		return "SESSION_STATE_CONNECT"
+	case SESSION_STATE_OPEN_SENT:
+		return "SESSION_STATE_OPEN_SENT"
+	case SESSION_STATE_OPEN_CONFIRM:
+		return "SESSION_STATE_OPEN_CONFIRM"
+	case SESSION_STATE_ESTABLISHED:
+		return "SESSION_STATE_ESTABLISHED"
+	}
+	return fmt.Sprintf("SessionState(%d)", state)
+}
+
+const (
+	DEFAULT_HOLD_TIME     = 90
+	DEFAULT_CONNECT_RETRY = 5 * time.Second
+	DEFAULT_LOCAL_PREF    = 100
+)
+
+// Route is a BGP-LS NLRI and the BGP-LS Attribute advertised with it.
+type Route struct {
+	Nlri      *Nlri
+	Attribute *Attribute
+}
+
+type SpeakerConfig struct {
+	PeerAddress  string
+	LocalAs      uint32
+	PeerAs       uint32
+	RouterId     uint32
+	NextHop      uint32
+	HoldTime     uint16
+	ConnectRetry time.Duration
+}
+
+// Speaker is a BGP-LS speaker with a single, actively opened peering. It
+// only advertises; whatever the peer sends besides KEEPALIVE and
+// NOTIFICATION is discarded. The table given to SetRoutes is compared
+// with what has been advertised and only the difference is sent.
+type Speaker struct {
+	config   SpeakerConfig
+	lock     sync.RWMutex
+	state    SessionState
+	routes   map[string]*Route
+	notifyCh chan struct{}
+	exitCh   chan struct{}
+}
+
+type speakerRecv struct {
+	messageType MessageType
+	body        []byte
+	err         error
+}
+
+func NewSpeaker(config *SpeakerConfig) (*Speaker, error) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	if config.PeerAddress == "" {
+		return nil, errors.New("NewSpeaker: peer address invalid")
+	}
+	if config.LocalAs == 0 {
+		return nil, errors.New("NewSpeaker: local as invalid")
+	}
+	if config.RouterId == 0 {
+		return nil, errors.New("NewSpeaker: router id invalid")
+	}
+	speaker := &Speaker{
+		config:   *config,
+		state:    SESSION_STATE_IDLE,
+		routes:   make(map[string]*Route),
+		notifyCh: make(chan struct{}, 1),
+		exitCh:   make(chan struct{}),
+	}
+	if speaker.config.PeerAs == 0 {
+		speaker.config.PeerAs = speaker.config.LocalAs
+	}
+	if speaker.config.NextHop == 0 {
+		speaker.config.NextHop = speaker.config.RouterId
+	}
+	if speaker.config.HoldTime == 0 {
+		speaker.config.HoldTime = DEFAULT_HOLD_TIME
+	}
+	if speaker.config.ConnectRetry == 0 {
+		speaker.config.ConnectRetry = DEFAULT_CONNECT_RETRY
+	}
+	if _, _, err := net.SplitHostPort(speaker.config.PeerAddress); err != nil {
+		speaker.config.PeerAddress = net.JoinHostPort(speaker.config.PeerAddress, strconv.Itoa(BGP_PORT))
+	}
+	return speaker, nil
+}
+
+func (speaker *Speaker) State() SessionState {
+	speaker.lock.RLock()
+	defer speaker.lock.RUnlock()
+	return speaker.state
+}
+
+func (speaker *Speaker) setState(state SessionState) {
+	speaker.lock.Lock()
+	defer speaker.lock.Unlock()
+	if speaker.state != state {
+		log.WithFields(log.Fields{
+			"Topic": "BgpLs",
+			"Key":   speaker.config.PeerAddress,
+			"State": state,
+		}).Info("session state changed")
+	}
+	speaker.state = state
+}
+
+// SetRoutes replaces the table to be advertised.
+func (speaker *Speaker) SetRoutes(routes []*Route) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	table := make(map[string]*Route)
+	for _, route := range routes {
+		key, err := route.Nlri.Serialize()
+		if err != nil {
+			log.WithFields(log.Fields{
+				"Topic": "BgpLs",
+				"Key":   route.Nlri.String(),
+				"Error": err,
+			}).Warn("nlri ignored")
+			continue
+		}
+		table[string(key)] = route
+	}
+	speaker.lock.Lock()
+	speaker.routes = table
+	speaker.lock.Unlock()
+	select {
+	case speaker.notifyCh <- struct{}{}:
+	default:
+	}
+}
+
+func (speaker *Speaker) Serve(wg *sync.WaitGroup) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	defer wg.Done()
+	for {
+		speaker.setState(SESSION_STATE_CONNECT)
+		conn, err := net.DialTimeout("tcp", speaker.config.PeerAddress, speaker.config.ConnectRetry)
+		if err == nil {
+			err = speaker.session(conn)
+		}
+		if err != nil {
+			log.WithFields(log.Fields{
+				"Topic": "BgpLs",
+				"Key":   speaker.config.PeerAddress,
+				"Error": err,
+			}).Info("session closed")
+		}
+		speaker.setState(SESSION_STATE_IDLE)
+		select {
+		case <-speaker.exitCh:
+			goto EXIT
+		case <-time.After(speaker.config.ConnectRetry):
+		}
+	}
+EXIT:
+}
+
+func (speaker *Speaker) Exit() {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	close(speaker.exitCh)
+}
+
+func (speaker *Speaker) notify(conn net.Conn, code, subcode uint8) {
+	notification := &NotificationMessage{
+		Code:    code,
+		Subcode: subcode,
+	}
+	data, _ := notification.Serialize()
+	conn.Write(data)
+}
+
+// session runs a session on conn until it ends, closing conn then so
+// that the reader stops as well.
+func (speaker *Speaker) session(conn net.Conn) error {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	recvCh := make(chan *speakerRecv)
+	doneCh := make(chan struct{})
+	defer func() {
+		close(doneCh)
+		conn.Close()
+	}()
+	go func() {
+		for {
+			messageType, body, err := ReadMessage(conn)
+			select {
+			case recvCh <- &speakerRecv{messageType, body, err}:
+			case <-doneCh:
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	open := &OpenMessage{
+		Version:       BGP_VERSION,
+		MyAs:          speaker.config.LocalAs,
+		HoldTime:      speaker.config.HoldTime,
+		BgpIdentifier: speaker.config.RouterId,
+		BgpLs:         true,
+		FourOctetAs:   true,
+	}
+	data, err := open.Serialize()
+	if err != nil {
+		return err
+	}
+	if _, err := conn.Write(data); err != nil {
+		return err
+	}
+	speaker.setState(SESSION_STATE_OPEN_SENT)
+
+	holdTime := time.Duration(speaker.config.HoldTime) * time.Second
+	holdTimer := time.NewTimer(4 * time.Minute)
+	defer holdTimer.Stop()
+	var keepaliveCh <-chan time.Time
+	var fourOctet bool
+	advertised := make(map[string][]byte)
+	for {
+		select {
+		case <-speaker.exitCh:
+			speaker.notify(conn, NOTIFICATION_CODE_CEASE, NOTIFICATION_SUBCODE_ADMINISTRATIVE_SHUTDOWN)
+			return nil
+		case <-holdTimer.C:
+			speaker.notify(conn, NOTIFICATION_CODE_HOLD_TIMER_EXPIRED, 0)
+			return errors.New("hold timer expired")
+		case <-keepaliveCh:
+			if _, err := conn.Write(SerializeKeepaliveMessage()); err != nil {
+				return err
+			}
+		case <-speaker.notifyCh:
+			if speaker.State() != SESSION_STATE_ESTABLISHED {
+				continue
+			}
+			if err := speaker.advertise(conn, advertised, fourOctet); err != nil {
+				return err
+			}
+		case recv := <-recvCh:
+			if recv.err != nil {
+				return recv.err
+			}
+			switch recv.messageType {
+			case MESSAGE_TYPE_OPEN:
+				if speaker.State() != SESSION_STATE_OPEN_SENT {
+					speaker.notify(conn, NOTIFICATION_CODE_FSM_ERROR, 0)
+					return errors.New("unexpected open")
+				}
+				peerOpen, err := DecodeOpenMessage(recv.body)
+				if err != nil {
+					speaker.notify(conn, NOTIFICATION_CODE_OPEN_MESSAGE_ERROR, 0)
+					return err
+				}
+				if peerOpen.Version != BGP_VERSION {
+					speaker.notify(conn, NOTIFICATION_CODE_OPEN_MESSAGE_ERROR, NOTIFICATION_SUBCODE_UNSUPPORTED_VERSION_NUMBER)
+					return errors.New("unsupported version")
+				}
+				if peerOpen.MyAs != speaker.config.PeerAs {
+					speaker.notify(conn, NOTIFICATION_CODE_OPEN_MESSAGE_ERROR, NOTIFICATION_SUBCODE_BAD_PEER_AS)
+					return errors.New(fmt.Sprintf("bad peer as %d", peerOpen.MyAs))
+				}
+				if !peerOpen.BgpLs {
+					log.WithFields(log.Fields{
+						"Topic": "BgpLs",
+						"Key":   speaker.config.PeerAddress,
+					}).Warn("peer did not advertise the bgp-ls capability")
+				}
+				fourOctet = peerOpen.FourOctetAs
+				if peerOpen.HoldTime < speaker.config.HoldTime {
+					holdTime = time.Duration(peerOpen.HoldTime) * time.Second
+				}
+				if holdTime > 0 {
+					keepalive := time.NewTicker(holdTime / 3)
+					defer keepalive.Stop()
+					keepaliveCh = keepalive.C
+				}
+				if _, err := conn.Write(SerializeKeepaliveMessage()); err != nil {
+					return err
+				}
+				speaker.setState(SESSION_STATE_OPEN_CONFIRM)
+			case MESSAGE_TYPE_KEEPALIVE:
+				if speaker.State() == SESSION_STATE_OPEN_CONFIRM {
+					speaker.setState(SESSION_STATE_ESTABLISHED)
+					if err := speaker.advertise(conn, advertised, fourOctet); err != nil {
+						return err
+					}
+				}
+			case MESSAGE_TYPE_NOTIFICATION:
+				notification, err := DecodeNotificationMessage(recv.body)
+				if err != nil {
+					return err
+				}
+				return errors.New("received " + notification.String())
+			case MESSAGE_TYPE_UPDATE:
+			default:
+				speaker.notify(conn, NOTIFICATION_CODE_MESSAGE_HEADER_ERROR, 3)
+				return errors.New("bad message type " + recv.messageType.String())
+			}
+			if holdTime > 0 {
+				holdTimer.Reset(holdTime)
+			} else {
+				holdTimer.Stop()
+			}
+		}
+	}
+}
+
+// advertise sends what differs between the current table and advertised
+// and brings advertised up to date.
+func (speaker *Speaker) advertise(conn net.Conn, advertised map[string][]byte, fourOctet bool) error {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	speaker.lock.RLock()
+	routes := speaker.routes
+	speaker.lock.RUnlock()
+
+	unreach := make([]*Nlri, 0)
+	unreachKeys := make([]string, 0)
+	length := 0
+	flush := func() error {
+		if len(unreach) == 0 {
+			return nil
+		}
+		update := &UpdateMessage{
+			Unreach: unreach,
+		}
+		data, err := update.Serialize()
+		if err != nil {
+			return err
+		}
+		if _, err := conn.Write(data); err != nil {
+			return err
+		}
+		for _, key := range unreachKeys {
+			delete(advertised, key)
+		}
+		unreach = make([]*Nlri, 0)
+		unreachKeys = make([]string, 0)
+		length = 0
+		return nil
+	}
+	for key := range advertised {
+		if _, ok := routes[key]; ok {
+			continue
+		}
+		nlri, _, err := DecodeNlri([]byte(key))
+		if err != nil {
+			return err
+		}
+		if length+len(key) > BGP_MAX_MESSAGE_LENGTH-BGP_HEADER_LENGTH-4-7 {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+		unreach = append(unreach, nlri)
+		unreachKeys = append(unreachKeys, key)
+		length += len(key)
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+
+	for key, route := range routes {
+		attr := []byte{}
+		if route.Attribute != nil {
+			attr = route.Attribute.Serialize()
+		}
+		if a, ok := advertised[key]; ok && bytes.Equal(a, attr) {
+			continue
+		}
+		update := &UpdateMessage{
+			FourOctet: fourOctet,
+			NextHop:   speaker.config.NextHop,
+			Reach:     []*Nlri{route.Nlri},
+			Attribute: route.Attribute,
+		}
+		if speaker.config.PeerAs == speaker.config.LocalAs {
+			localPref := uint32(DEFAULT_LOCAL_PREF)
+			update.LocalPref = &localPref
+		} else {
+			update.AsPath = []uint32{speaker.config.LocalAs}
+		}
+		data, err := update.Serialize()
+		if err != nil {
+			log.WithFields(log.Fields{
+				"Topic": "BgpLs",
+				"Key":   route.Nlri.String(),
+				"Error": err,
+			}).Warn("nlri not advertised")
+			continue
+		}
+		if _, err := conn.Write(data); err != nil {
+			return err
+		}
+		advertised[key] = attr
+	}
+	return nil
+}
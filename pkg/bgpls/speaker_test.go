@@ -0,0 +1,99 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bgpls
+
+import (
+	"net"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+// rejectSession accepts a session of the speaker and ends it with an OPEN
+// of a bad AS.
+func rejectSession(t *testing.T, ln net.Listener) {
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatalf("failed Accept: %#v", err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	messageType, _, err := ReadMessage(conn)
+	if err != nil || messageType != MESSAGE_TYPE_OPEN {
+		t.Fatalf("failed OPEN: %s %#v", messageType, err)
+	}
+	open := &OpenMessage{
+		Version:       BGP_VERSION,
+		MyAs:          65001,
+		HoldTime:      90,
+		BgpIdentifier: 0x0a000002,
+		BgpLs:         true,
+		FourOctetAs:   true,
+	}
+	data, _ := open.Serialize()
+	conn.Write(data)
+	// the speaker answers with a NOTIFICATION and closes the session
+	for {
+		if _, _, err := ReadMessage(conn); err != nil {
+			return
+		}
+	}
+}
+
+func TestSpeakerReconnect(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed Listen: %#v", err)
+	}
+	defer ln.Close()
+	speaker, err := NewSpeaker(&SpeakerConfig{
+		PeerAddress:  ln.Addr().String(),
+		LocalAs:      65000,
+		RouterId:     0x0a000001,
+		ConnectRetry: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("failed NewSpeaker: %#v", err)
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go speaker.Serve(&wg)
+	defer func() {
+		speaker.Exit()
+		wg.Wait()
+	}()
+
+	rejectSession(t, ln)
+	settled := func(limit int) int {
+		n := runtime.NumGoroutine()
+		for i := 0; n > limit && i < 50; i++ {
+			time.Sleep(20 * time.Millisecond)
+			n = runtime.NumGoroutine()
+		}
+		return n
+	}
+	base := settled(0)
+	for i := 0; i < 10; i++ {
+		rejectSession(t, ln)
+	}
+	// the readers of the ended sessions are gone
+	if n := settled(base); n > base {
+		t.Fatalf("failed reconnect: %d goroutines after 10 sessions, %d before", n, base)
+	}
+}
@@ -0,0 +1,592 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package harness runs several IsisServer instances in one process,
+// connected by in-memory links, so that scenarios such as DIS election,
+// link failures, partitions and LSP purges can be tested without network
+// namespaces or privileges.
+package harness
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	api "github.com/m-asama/golsr/api/isis"
+	"github.com/m-asama/golsr/internal/pkg/isis/config"
+	"github.com/m-asama/golsr/internal/pkg/kernel"
+	"github.com/m-asama/golsr/pkg/isis/server"
+)
+
+const (
+	DEFAULT_AREA_ADDRESS = "49.0001"
+	DEFAULT_LEVEL_TYPE   = "level-all"
+	POLL_INTERVAL        = 100 * time.Millisecond
+)
+
+type routerPort struct {
+	link    *Link
+	port    *Port
+	ifIndex int
+	name    string
+	hwaddr  []byte
+	address uint32
+	plen    int
+	up      bool
+}
+
+type routerState struct {
+	router   *Router
+	isis     *server.IsisServer
+	configCh chan *config.IsisConfig
+	kernelCh chan *kernel.KernelStatus
+	kernel   *kernel.FakeProvider
+	doneCh   chan struct{}
+	ports    []*routerPort
+	wg       sync.WaitGroup
+	running  bool
+}
+
+// Network is a running Topology.
+type Network struct {
+	topology *Topology
+	memory   *server.MemoryNetwork
+	routers  map[string]*routerState
+	lock     sync.Mutex
+}
+
+// Start validates topology and starts a server for each of its routers.
+// Every router gets a loopback lo and one interface per port, named eth0,
+// eth1, ... in the order the ports appear in the topology.
+func Start(topology *Topology) (*Network, error) {
+	if err := topology.validate(); err != nil {
+		return nil, err
+	}
+	network := &Network{
+		topology: topology,
+		memory:   server.NewMemoryNetwork(),
+		routers:  make(map[string]*routerState),
+	}
+	for ri, router := range topology.Routers {
+		network.routers[router.Name] = &routerState{
+			router:   router,
+			configCh: make(chan *config.IsisConfig),
+			kernelCh: make(chan *kernel.KernelStatus),
+		}
+		for li, link := range topology.Links {
+			for pi, port := range link.Ports {
+				if port.Router != router.Name {
+					continue
+				}
+				if err := network.addPort(ri, li, pi); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	for _, router := range topology.Routers {
+		if err := network.startRouter(network.routers[router.Name]); err != nil {
+			network.Stop()
+			return nil, err
+		}
+	}
+	return network, nil
+}
+
+func (network *Network) addPort(ri, li, pi int) error {
+	link := network.topology.Links[li]
+	state := network.routers[link.Ports[pi].Router]
+	port := &routerPort{
+		link:    link,
+		port:    link.Ports[pi],
+		ifIndex: len(state.ports) + 2,
+		name:    fmt.Sprintf("eth%d", len(state.ports)),
+		hwaddr:  []byte{0x02, 0x00, byte(ri + 1), byte((li + 1) >> 8), byte(li + 1), byte(pi + 1)},
+		up:      true,
+	}
+	if link.Prefix != "" {
+		_, ipnet, err := net.ParseCIDR(link.Prefix)
+		if err != nil {
+			return err
+		}
+		plen, _ := ipnet.Mask.Size()
+		port.address = binary.BigEndian.Uint32(ipnet.IP.To4()) + uint32(pi+1)
+		port.plen = plen
+	}
+	state.ports = append(state.ports, port)
+	return nil
+}
+
+func (network *Network) startRouter(state *routerState) error {
+	cfg, err := config.Parse([]byte(state.configText()), "toml")
+	if err != nil {
+		return errors.New(state.router.Name + ": " + err.Error())
+	}
+	state.kernel = kernel.NewFakeProvider()
+	for _, iface := range state.kernelStatus().Interfaces {
+		state.kernel.SetInterface(iface)
+	}
+	state.doneCh = make(chan struct{})
+	state.isis = server.NewIsisServer("", "")
+	state.isis.SetKernelProvider(state.kernel)
+	state.isis.SetFib(true)
+	state.isis.SetTransport(network.memory.TransportFactory(state.router.Name))
+	for _, port := range state.ports {
+		if port.up {
+			network.memory.Connect(port.link.Name, state.router.Name, port.name)
+		}
+	}
+	state.wg.Add(1)
+	go state.isis.ServeWith(&state.wg, state.configCh, state.kernelCh)
+	go state.kernel.Watch(state.kernelCh, state.doneCh)
+	state.configCh <- cfg
+	state.running = true
+	return nil
+}
+
+func (state *routerState) levelType(port *Port) string {
+	if port != nil && port.LevelType != "" {
+		return port.LevelType
+	}
+	if state.router.LevelType != "" {
+		return state.router.LevelType
+	}
+	return DEFAULT_LEVEL_TYPE
+}
+
+func (state *routerState) configText() string {
+	var b strings.Builder
+	router := state.router
+	areas := router.areaAddresses()
+	fmt.Fprintf(&b, "[config]\n")
+	fmt.Fprintf(&b, "  system-id = %q\n", router.SystemId)
+	fmt.Fprintf(&b, "  area-address-list = [\"%s\"]\n", strings.Join(areas, "\", \""))
+	fmt.Fprintf(&b, "  level-type = %q\n", state.levelType(nil))
+	if router.LspLifetime != 0 {
+		fmt.Fprintf(&b, "  lsp-lifetime = %d\n", router.LspLifetime)
+	}
+	if router.LspRefresh != 0 {
+		fmt.Fprintf(&b, "  lsp-refresh = %d\n", router.LspRefresh)
+	}
+	fmt.Fprintf(&b, "\n[[address-families]]\n")
+	fmt.Fprintf(&b, "  [address-families.config]\n")
+	fmt.Fprintf(&b, "    address-family = \"ipv4\"\n")
+	for _, port := range state.ports {
+		fmt.Fprintf(&b, "\n[[interfaces]]\n")
+		fmt.Fprintf(&b, "  [interfaces.config]\n")
+		fmt.Fprintf(&b, "    name = %q\n", port.name)
+		fmt.Fprintf(&b, "    interface-type = %q\n", port.link.linkType())
+		fmt.Fprintf(&b, "    level-type = %q\n", state.levelType(port.port))
+		fmt.Fprintf(&b, "  [interfaces.hello-interval.config]\n")
+		fmt.Fprintf(&b, "    value = 1\n")
+		if port.port.Priority != 0 {
+			fmt.Fprintf(&b, "  [interfaces.priority.config]\n")
+			fmt.Fprintf(&b, "    value = %d\n", port.port.Priority)
+		}
+		if port.port.Metric != 0 {
+			fmt.Fprintf(&b, "  [interfaces.metric.config]\n")
+			fmt.Fprintf(&b, "    value = %d\n", port.port.Metric)
+		}
+	}
+	fmt.Fprintf(&b, "\n[[interfaces]]\n")
+	fmt.Fprintf(&b, "  [interfaces.config]\n")
+	fmt.Fprintf(&b, "    name = \"lo\"\n")
+	fmt.Fprintf(&b, "    passive = true\n")
+	if router.Policies != "" {
+		fmt.Fprintf(&b, "\n%s\n", router.Policies)
+	}
+	return b.String()
+}
+
+func (state *routerState) kernelStatus() *kernel.KernelStatus {
+	lo := &kernel.Interface{
+		IfIndex:       1,
+		Name:          "lo",
+		IfType:        kernel.IF_TYPE_LOOPBACK,
+		Mtu:           65536,
+		Up:            true,
+		Ipv4Addresses: make([]*kernel.Ipv4Address, 0),
+		Ipv6Addresses: make([]*kernel.Ipv6Address, 0),
+	}
+	if state.router.Loopback != "" {
+		ip, ipnet, _ := net.ParseCIDR(state.router.Loopback)
+		plen, _ := ipnet.Mask.Size()
+		if ip.To4() != nil {
+			lo.Ipv4Addresses = append(lo.Ipv4Addresses, &kernel.Ipv4Address{
+				Address:      binary.BigEndian.Uint32(ip.To4()),
+				PrefixLength: plen,
+			})
+		}
+	}
+	k := &kernel.KernelStatus{
+		Interfaces: []*kernel.Interface{lo},
+	}
+	for _, port := range state.ports {
+		iface := &kernel.Interface{
+			IfIndex:       port.ifIndex,
+			Name:          port.name,
+			IfType:        kernel.IF_TYPE_BROADCAST,
+			HardwareAddr:  append([]byte{}, port.hwaddr...),
+			Mtu:           1500,
+			Up:            port.up,
+			Ipv4Addresses: make([]*kernel.Ipv4Address, 0),
+			Ipv6Addresses: make([]*kernel.Ipv6Address, 0),
+		}
+		if port.link.linkType() == "point-to-point" {
+			iface.IfType = kernel.IF_TYPE_POINTTOPOINT
+		}
+		if port.plen != 0 {
+			iface.Ipv4Addresses = append(iface.Ipv4Addresses, &kernel.Ipv4Address{
+				Address:      port.address,
+				PrefixLength: port.plen,
+			})
+		}
+		k.Interfaces = append(k.Interfaces, iface)
+	}
+	return k
+}
+
+// Stop stops all running routers.
+func (network *Network) Stop() {
+	network.lock.Lock()
+	defer network.lock.Unlock()
+	for _, state := range network.routers {
+		network.stopRouter(state)
+	}
+}
+
+func (network *Network) stopRouter(state *routerState) {
+	if !state.running {
+		return
+	}
+	for _, port := range state.ports {
+		network.memory.Disconnect(state.router.Name, port.name)
+	}
+	state.isis.Exit()
+	state.wg.Wait()
+	close(state.doneCh)
+	state.running = false
+}
+
+// StopRouter stops router as if it crashed: its neighbours notice only
+// when their hold timers expire and its LSPs age out of their LSDBs.
+func (network *Network) StopRouter(router string) error {
+	network.lock.Lock()
+	defer network.lock.Unlock()
+	state, ok := network.routers[router]
+	if !ok {
+		return errors.New("no such router: " + router)
+	}
+	network.stopRouter(state)
+	return nil
+}
+
+// SetLinkUp brings every interface attached to link down or up again, as
+// if the carrier was lost or restored.
+func (network *Network) SetLinkUp(link string, up bool) error {
+	network.lock.Lock()
+	defer network.lock.Unlock()
+	if network.topology.link(link) == nil {
+		return errors.New("no such link: " + link)
+	}
+	for _, state := range network.routers {
+		for _, port := range state.ports {
+			if port.link.Name != link || port.up == up {
+				continue
+			}
+			port.up = up
+			if !state.running {
+				continue
+			}
+			if up {
+				network.memory.Connect(link, state.router.Name, port.name)
+			} else {
+				network.memory.Disconnect(state.router.Name, port.name)
+			}
+			state.kernel.SetUp(port.name, up)
+		}
+	}
+	return nil
+}
+
+// Server returns the server of router.
+func (network *Network) Server(router string) *server.IsisServer {
+	network.lock.Lock()
+	defer network.lock.Unlock()
+	if state, ok := network.routers[router]; ok {
+		return state.isis
+	}
+	return nil
+}
+
+// Fib returns the IPv4 routes router has installed into its kernel.
+func (network *Network) Fib(router string) []*kernel.Ipv4Route {
+	network.lock.Lock()
+	defer network.lock.Unlock()
+	if state, ok := network.routers[router]; ok && state.kernel != nil {
+		return state.kernel.Ipv4Routes()
+	}
+	return nil
+}
+
+// Running reports whether router has not been stopped.
+func (network *Network) Running(router string) bool {
+	network.lock.Lock()
+	defer network.lock.Unlock()
+	state, ok := network.routers[router]
+	return ok && state.running
+}
+
+// Interface returns the name of the interface of router on link.
+func (network *Network) Interface(router, link string) string {
+	network.lock.Lock()
+	defer network.lock.Unlock()
+	if state, ok := network.routers[router]; ok {
+		for _, port := range state.ports {
+			if port.link.Name == link {
+				return port.name
+			}
+		}
+	}
+	return ""
+}
+
+// Adjacencies returns the adjacencies of router in the Up state.
+func (network *Network) Adjacencies(router string) []*api.Adjacency {
+	isis := network.Server(router)
+	if isis == nil {
+		return nil
+	}
+	adjacencies := make([]*api.Adjacency, 0)
+	for _, adjacency := range isis.ApiAdjacencies() {
+		if adjacency.State == "ADJ_3WAY_STATE_UP" {
+			adjacencies = append(adjacencies, adjacency)
+		}
+	}
+	return adjacencies
+}
+
+// Lsps returns the LSDB of level of router, purged LSPs included.
+func (network *Network) Lsps(router string, level server.IsisLevel) []*api.Lsp {
+	isis := network.Server(router)
+	if isis == nil {
+		return nil
+	}
+	return isis.ApiLsps(level)
+}
+
+// Lsp returns the LSP lspId (such as 0000000000010000) in the LSDB
+// of level of router.
+func (network *Network) Lsp(router string, level server.IsisLevel, lspId string) *api.Lsp {
+	for _, lsp := range network.Lsps(router, level) {
+		if lsp.LspId == lspId {
+			return lsp
+		}
+	}
+	return nil
+}
+
+// Routes returns the routes of level computed by router.
+func (network *Network) Routes(router string, level server.IsisLevel) []*api.Route {
+	isis := network.Server(router)
+	if isis == nil {
+		return nil
+	}
+	return isis.ApiRoutes(level)
+}
+
+// Route returns the route to prefix (such as 192.168.0.1/32) of level
+// computed by router.
+func (network *Network) Route(router string, level server.IsisLevel, prefix string) *api.Route {
+	for _, route := range network.Routes(router, level) {
+		if route.Prefix == prefix {
+			return route
+		}
+	}
+	return nil
+}
+
+// Dis returns the name of the running router which is the DIS of level
+// on link.
+func (network *Network) Dis(link string, level server.IsisLevel) string {
+	for _, port := range network.topology.link(link).Ports {
+		if !network.Running(port.Router) {
+			continue
+		}
+		isis := network.Server(port.Router)
+		name := network.Interface(port.Router, link)
+		if isis.Designated(name, level) {
+			return port.Router
+		}
+	}
+	return ""
+}
+
+// WaitFor polls cond until it holds or timeout passes.
+func (network *Network) WaitFor(timeout time.Duration, cond func() bool) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		if cond() {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errors.New("timed out")
+		}
+		time.Sleep(POLL_INTERVAL)
+	}
+}
+
+// WaitConverged waits until every router has all the adjacencies the
+// topology calls for and the routers running a level share the same LSDB
+// for it, on two consecutive polls so that SPF has had a chance to run.
+func (network *Network) WaitConverged(timeout time.Duration) error {
+	stable := false
+	return network.WaitFor(timeout, func() bool {
+		converged := network.converged()
+		if converged && stable {
+			return true
+		}
+		stable = converged
+		return false
+	})
+}
+
+func (network *Network) converged() bool {
+	network.lock.Lock()
+	states := make([]*routerState, 0)
+	for _, router := range network.topology.Routers {
+		if state := network.routers[router.Name]; state.running {
+			states = append(states, state)
+		}
+	}
+	network.lock.Unlock()
+	for _, state := range states {
+		if len(network.Adjacencies(state.router.Name)) < network.expectedAdjacencies(state) {
+			return false
+		}
+	}
+	for _, level := range server.ISIS_LEVEL_ALL {
+		digests := make(map[string]string)
+		for _, state := range states {
+			if !state.hasLevel(level) {
+				continue
+			}
+			digest := lsdbDigest(network.Lsps(state.router.Name, level))
+			if level == server.ISIS_LEVEL_1 {
+				// areas have LSDBs of their own
+				key := strings.Join(state.router.areaAddresses(), ",")
+				if prev, ok := digests[key]; ok && prev != digest {
+					return false
+				}
+				digests[key] = digest
+				continue
+			}
+			if prev, ok := digests[""]; ok && prev != digest {
+				return false
+			}
+			digests[""] = digest
+		}
+	}
+	return true
+}
+
+func (state *routerState) hasLevel(level server.IsisLevel) bool {
+	levelType := state.levelType(nil)
+	return levelType == DEFAULT_LEVEL_TYPE || levelType == level.String2()
+}
+
+func lsdbDigest(lsps []*api.Lsp) string {
+	entries := make([]string, 0)
+	for _, lsp := range lsps {
+		if lsp.RemainingLifetime == 0 {
+			continue
+		}
+		entries = append(entries, fmt.Sprintf("%s/%d", lsp.LspId, lsp.Sequence))
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, " ")
+}
+
+// expectedAdjacencies counts the adjacencies state should have on its
+// links that are up: one per neighbour on a point-to-point link and one
+// per neighbour and shared level on a LAN.
+func (network *Network) expectedAdjacencies(state *routerState) int {
+	network.lock.Lock()
+	defer network.lock.Unlock()
+	count := 0
+	for _, port := range state.ports {
+		if !port.up {
+			continue
+		}
+		for _, peer := range port.link.Ports {
+			if peer == port.port {
+				continue
+			}
+			peerState := network.routers[peer.Router]
+			if !peerState.running {
+				continue
+			}
+			levels := 0
+			for _, level := range server.ISIS_LEVEL_ALL {
+				if !portHasLevel(state, port.port, level) || !portHasLevel(peerState, peer, level) {
+					continue
+				}
+				if level == server.ISIS_LEVEL_1 && !sameArea(state.router, peerState.router) {
+					continue
+				}
+				levels++
+			}
+			if levels == 0 {
+				continue
+			}
+			if port.link.linkType() == "point-to-point" {
+				count++
+			} else {
+				count += levels
+			}
+		}
+	}
+	return count
+}
+
+func portHasLevel(state *routerState, port *Port, level server.IsisLevel) bool {
+	levelType := state.levelType(port)
+	return (levelType == DEFAULT_LEVEL_TYPE || levelType == level.String2()) && state.hasLevel(level)
+}
+
+func sameArea(a, b *Router) bool {
+	for _, x := range a.areaAddresses() {
+		for _, y := range b.areaAddresses() {
+			if x == y {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (router *Router) areaAddresses() []string {
+	if len(router.AreaAddresses) == 0 {
+		return []string{DEFAULT_AREA_ADDRESS}
+	}
+	return router.AreaAddresses
+}
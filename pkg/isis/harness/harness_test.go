@@ -0,0 +1,385 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package harness
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/m-asama/golsr/internal/pkg/isis/config"
+	"github.com/m-asama/golsr/internal/pkg/kernel"
+	"github.com/m-asama/golsr/pkg/isis/server"
+)
+
+const testTimeout = 30 * time.Second
+
+func startTest(t *testing.T, topology *Topology) *Network {
+	network, err := Start(topology)
+	if err != nil {
+		t.Fatalf("failed Start: %#v", err)
+	}
+	if err := network.WaitConverged(testTimeout); err != nil {
+		network.Stop()
+		t.Fatalf("failed WaitConverged: %#v", err)
+	}
+	return network
+}
+
+func TestParseTopology(t *testing.T) {
+	topology, err := ParseTopology([]byte(`
+routers:
+  - name: r1
+    system-id: 0000.0000.0001
+    loopback: 192.168.0.1/32
+  - name: r2
+    system-id: 0000.0000.0002
+    level-type: level-2
+links:
+  - name: l1
+    prefix: 10.0.1.0/24
+    ports:
+      - router: r1
+        metric: 20
+      - router: r2
+`), "yaml")
+	if err != nil {
+		t.Fatalf("failed ParseTopology: %#v", err)
+	}
+	if len(topology.Routers) != 2 || topology.Routers[1].LevelType != "level-2" ||
+		topology.Routers[0].Loopback != "192.168.0.1/32" {
+		t.Fatalf("failed routers: %#v", topology.Routers)
+	}
+	link := topology.link("l1")
+	if link == nil || link.linkType() != "point-to-point" || link.Ports[0].Metric != 20 {
+		t.Fatalf("failed links: %#v", topology.Links)
+	}
+	if err := topology.validate(); err != nil {
+		t.Fatalf("failed validate: %#v", err)
+	}
+	link.Ports[1].Router = "r3"
+	if err := topology.validate(); err == nil {
+		t.Fatalf("failed validate: unknown router accepted")
+	}
+}
+
+func TestDisElection(t *testing.T) {
+	network := startTest(t, &Topology{
+		Routers: []*Router{
+			{Name: "r1", SystemId: "0000.0000.0001", LevelType: "level-2"},
+			{Name: "r2", SystemId: "0000.0000.0002", LevelType: "level-2"},
+			{Name: "r3", SystemId: "0000.0000.0003", LevelType: "level-2"},
+		},
+		Links: []*Link{
+			{Name: "lan", Prefix: "10.0.0.0/24", Ports: []*Port{
+				{Router: "r1", Priority: 100},
+				{Router: "r2"},
+				{Router: "r3"},
+			}},
+		},
+	})
+	defer network.Stop()
+
+	// the highest priority wins
+	if dis := network.Dis("lan", server.ISIS_LEVEL_2); dis != "r1" {
+		t.Fatalf("failed DIS: %q", dis)
+	}
+	if len(network.Adjacencies("r2")) != 2 {
+		t.Fatalf("failed adjacencies: %#v", network.Adjacencies("r2"))
+	}
+	// the pseudonode LSP of the DIS is in every LSDB
+	for _, router := range []string{"r1", "r2", "r3"} {
+		found := false
+		for _, lsp := range network.Lsps(router, server.ISIS_LEVEL_2) {
+			if strings.HasPrefix(lsp.LspId, "000000000001") && lsp.LspId[12:14] != "00" &&
+				lsp.RemainingLifetime != 0 {
+				found = true
+			}
+		}
+		if !found {
+			t.Fatalf("failed pseudonode lsp: %s", router)
+		}
+	}
+
+	// among equal priorities the highest SNPA wins
+	network.StopRouter("r1")
+	err := network.WaitFor(testTimeout, func() bool {
+		return network.Dis("lan", server.ISIS_LEVEL_2) == "r3"
+	})
+	if err != nil {
+		t.Fatalf("failed DIS after r1 stopped: %q", network.Dis("lan", server.ISIS_LEVEL_2))
+	}
+}
+
+func TestLinkFailure(t *testing.T) {
+	// r1 reaches r3 directly with metric 50 or through r2 with 10 + 10
+	network := startTest(t, &Topology{
+		Routers: []*Router{
+			{Name: "r1", SystemId: "0000.0000.0001", LevelType: "level-2", Loopback: "192.168.0.1/32"},
+			{Name: "r2", SystemId: "0000.0000.0002", LevelType: "level-2", Loopback: "192.168.0.2/32"},
+			{Name: "r3", SystemId: "0000.0000.0003", LevelType: "level-2", Loopback: "192.168.0.3/32"},
+		},
+		Links: []*Link{
+			{Name: "r1r2", Prefix: "10.0.12.0/24", Ports: []*Port{{Router: "r1"}, {Router: "r2"}}},
+			{Name: "r2r3", Prefix: "10.0.23.0/24", Ports: []*Port{{Router: "r2"}, {Router: "r3"}}},
+			{Name: "r1r3", Prefix: "10.0.13.0/24", Ports: []*Port{{Router: "r1", Metric: 50}, {Router: "r3", Metric: 50}}},
+		},
+	})
+	defer network.Stop()
+
+	route := network.Route("r1", server.ISIS_LEVEL_2, "192.168.0.3/32")
+	if route == nil || route.Metric != 30 || len(route.NextHops) != 1 ||
+		route.NextHops[0].OutgoingInterface != network.Interface("r1", "r1r2") {
+		t.Fatalf("failed route before failure: %#v", route)
+	}
+
+	network.SetLinkUp("r2r3", false)
+	err := network.WaitFor(testTimeout, func() bool {
+		route = network.Route("r1", server.ISIS_LEVEL_2, "192.168.0.3/32")
+		return route != nil && route.Metric == 60
+	})
+	if err != nil {
+		t.Fatalf("failed reroute: %#v", route)
+	}
+	if route.NextHops[0].OutgoingInterface != network.Interface("r1", "r1r3") {
+		t.Fatalf("failed reroute next hop: %#v", route.NextHops[0])
+	}
+	// r1r3 is r1's second port, eth1 with ifindex 3
+	err = network.WaitFor(testTimeout, func() bool {
+		for _, fib := range network.Fib("r1") {
+			if fib.Prefix == 0xc0a80003 && fib.PrefixLength == 32 && fib.Metric == 60 &&
+				len(fib.NextHops) == 1 && fib.NextHops[0].IfIndex == 3 {
+				return true
+			}
+		}
+		return false
+	})
+	if err != nil {
+		t.Fatalf("failed reroute fib: %#v", network.Fib("r1"))
+	}
+
+	network.SetLinkUp("r2r3", true)
+	err = network.WaitFor(testTimeout, func() bool {
+		route = network.Route("r1", server.ISIS_LEVEL_2, "192.168.0.3/32")
+		return route != nil && route.Metric == 30
+	})
+	if err != nil {
+		t.Fatalf("failed restore: %#v", route)
+	}
+}
+
+func TestPartition(t *testing.T) {
+	network := startTest(t, &Topology{
+		Routers: []*Router{
+			{Name: "r1", SystemId: "0000.0000.0001", LevelType: "level-2", Loopback: "192.168.0.1/32"},
+			{Name: "r2", SystemId: "0000.0000.0002", LevelType: "level-2", Loopback: "192.168.0.2/32"},
+			{Name: "r3", SystemId: "0000.0000.0003", LevelType: "level-2", Loopback: "192.168.0.3/32"},
+		},
+		Links: []*Link{
+			{Name: "r1r2", Prefix: "10.0.12.0/24", Ports: []*Port{{Router: "r1"}, {Router: "r2"}}},
+			{Name: "r2r3", Prefix: "10.0.23.0/24", Ports: []*Port{{Router: "r2"}, {Router: "r3"}}},
+		},
+	})
+	defer network.Stop()
+
+	if route := network.Route("r1", server.ISIS_LEVEL_2, "192.168.0.3/32"); route == nil || route.Metric != 30 {
+		t.Fatalf("failed route before partition: %#v", route)
+	}
+
+	network.SetLinkUp("r2r3", false)
+	err := network.WaitFor(testTimeout, func() bool {
+		return network.Route("r1", server.ISIS_LEVEL_2, "192.168.0.3/32") == nil &&
+			network.Route("r3", server.ISIS_LEVEL_2, "192.168.0.1/32") == nil
+	})
+	if err != nil {
+		t.Fatalf("failed partition: %#v", network.Routes("r1", server.ISIS_LEVEL_2))
+	}
+	if route := network.Route("r1", server.ISIS_LEVEL_2, "192.168.0.2/32"); route == nil {
+		t.Fatalf("failed route on the same side")
+	}
+}
+
+func TestLspPurge(t *testing.T) {
+	network := startTest(t, &Topology{
+		Routers: []*Router{
+			{Name: "r1", SystemId: "0000.0000.0001", LevelType: "level-2", Loopback: "192.168.0.1/32"},
+			{Name: "r2", SystemId: "0000.0000.0002", LevelType: "level-2", Loopback: "192.168.0.2/32",
+				LspLifetime: 8, LspRefresh: 4},
+		},
+		Links: []*Link{
+			{Name: "r1r2", Prefix: "10.0.12.0/24", Ports: []*Port{{Router: "r1"}, {Router: "r2"}}},
+		},
+	})
+	defer network.Stop()
+
+	const lspId = "0000000000020000"
+	if lsp := network.Lsp("r1", server.ISIS_LEVEL_2, lspId); lsp == nil || lsp.RemainingLifetime == 0 {
+		t.Fatalf("failed lsp before stop: %#v", lsp)
+	}
+
+	// r2's LSP is refreshed no more and ages out of r1's LSDB
+	network.StopRouter("r2")
+	err := network.WaitFor(testTimeout, func() bool {
+		lsp := network.Lsp("r1", server.ISIS_LEVEL_2, lspId)
+		return lsp != nil && lsp.RemainingLifetime == 0
+	})
+	if err != nil {
+		t.Fatalf("failed purge: %#v", network.Lsp("r1", server.ISIS_LEVEL_2, lspId))
+	}
+	err = network.WaitFor(testTimeout, func() bool {
+		return network.Route("r1", server.ISIS_LEVEL_2, "192.168.0.2/32") == nil &&
+			len(network.Adjacencies("r1")) == 0
+	})
+	if err != nil {
+		t.Fatalf("failed routes after purge: %#v", network.Routes("r1", server.ISIS_LEVEL_2))
+	}
+}
+
+func fibRoute(routes []*kernel.Ipv4Route, prefix uint32, prefixLength int) *kernel.Ipv4Route {
+	for _, route := range routes {
+		if route.Prefix == prefix && route.PrefixLength == prefixLength {
+			return route
+		}
+	}
+	return nil
+}
+
+func TestLeaking(t *testing.T) {
+	network := startTest(t, &Topology{
+		Routers: []*Router{
+			{Name: "r1", SystemId: "0000.0000.0001", LevelType: "level-1", Loopback: "192.168.0.1/32"},
+			{Name: "r2", SystemId: "0000.0000.0002", Loopback: "192.168.0.2/32",
+				Policies: `
+[[defined-sets.prefix-sets]]
+  [defined-sets.prefix-sets.config]
+    prefix-set-name = "r3"
+  [[defined-sets.prefix-sets.prefixes]]
+    [defined-sets.prefix-sets.prefixes.config]
+      ip-prefix = "192.168.0.3/32"
+
+[[policy-definitions]]
+  [policy-definitions.config]
+    name = "leak-r3"
+  [[policy-definitions.statements]]
+    [policy-definitions.statements.config]
+      name = "r3"
+    [policy-definitions.statements.conditions.config]
+      match-prefix-set = "r3"
+      level = "level-2"
+    [policy-definitions.statements.actions.config]
+      route-disposition = "accept-route"
+      set-tag = 100
+
+[apply-policy.config]
+  leak-policy-list = ["leak-r3"]
+`},
+			{Name: "r3", SystemId: "0000.0000.0003", LevelType: "level-2", Loopback: "192.168.0.3/32",
+				AreaAddresses: []string{"49.0002"}},
+			{Name: "r4", SystemId: "0000.0000.0004", LevelType: "level-2", Loopback: "192.168.0.4/32",
+				AreaAddresses: []string{"49.0002"}},
+		},
+		Links: []*Link{
+			{Name: "r1r2", Prefix: "10.0.12.0/24", Ports: []*Port{{Router: "r1"}, {Router: "r2"}}},
+			{Name: "r2r3", Prefix: "10.0.23.0/24", Ports: []*Port{{Router: "r2"}, {Router: "r3"}}},
+			{Name: "r3r4", Prefix: "10.0.34.0/24", Ports: []*Port{{Router: "r3"}, {Router: "r4"}}},
+		},
+	})
+	defer network.Stop()
+
+	// level 1 routes go up unless rejected
+	err := network.WaitFor(testTimeout, func() bool {
+		route := network.Route("r4", server.ISIS_LEVEL_2, "192.168.0.1/32")
+		return route != nil && route.Metric == 40
+	})
+	if err != nil {
+		t.Fatalf("failed leaking up: %#v", network.Routes("r4", server.ISIS_LEVEL_2))
+	}
+
+	// level 2 routes come down with the up/down bit only if accepted
+	err = network.WaitFor(testTimeout, func() bool {
+		route := network.Route("r1", server.ISIS_LEVEL_1, "192.168.0.3/32")
+		return route != nil && route.UpDown && len(route.Tags) == 1 && route.Tags[0] == 100 &&
+			fibRoute(network.Fib("r1"), 0xc0a80003, 32) != nil
+	})
+	if err != nil {
+		t.Fatalf("failed leaking down: %#v", network.Routes("r1", server.ISIS_LEVEL_1))
+	}
+	if route := network.Route("r1", server.ISIS_LEVEL_1, "192.168.0.4/32"); route != nil {
+		t.Fatalf("failed leaking down: not accepted route leaked %#v", route)
+	}
+	if route := network.Route("r4", server.ISIS_LEVEL_2, "192.168.0.3/32"); route == nil || route.UpDown {
+		t.Fatalf("failed leaking down: leaked back up %#v", route)
+	}
+	// r2's own advertisement does not shadow the level 2 route
+	if fibRoute(network.Fib("r2"), 0xc0a80003, 32) == nil {
+		t.Fatalf("failed route on r2: %#v", network.Fib("r2"))
+	}
+
+	// an install policy keeps a route out of the kernel only, and the leak
+	// policy withdrawn takes the leaked route back out of level 1
+	c, err := config.Parse([]byte(`
+[[defined-sets.prefix-sets]]
+  [defined-sets.prefix-sets.config]
+    prefix-set-name = "r4"
+  [[defined-sets.prefix-sets.prefixes]]
+    [defined-sets.prefix-sets.prefixes.config]
+      ip-prefix = "192.168.0.4/30"
+      masklength-range = "32..32"
+
+[[policy-definitions]]
+  [policy-definitions.config]
+    name = "no-r4"
+  [[policy-definitions.statements]]
+    [policy-definitions.statements.config]
+      name = "r4"
+    [policy-definitions.statements.conditions.config]
+      match-prefix-set = "r4"
+      level = "level-2"
+    [policy-definitions.statements.actions.config]
+      route-disposition = "reject-route"
+`), "toml")
+	if err != nil {
+		t.Fatalf("failed Parse: %#v", err)
+	}
+	r2 := network.Server("r2")
+	if err := r2.AddDefinedSets(c.DefinedSets.PrefixSets, nil); err != nil {
+		t.Fatalf("failed AddDefinedSets: %#v", err)
+	}
+	if err := r2.AddPolicies(c.PolicyDefinitions); err != nil {
+		t.Fatalf("failed AddPolicies: %#v", err)
+	}
+	if err := r2.DeleteDefinedSets([]string{"r3"}, nil); err == nil {
+		t.Fatalf("failed DeleteDefinedSets: set in use deleted")
+	}
+	noR4 := "no-r4"
+	if err := r2.SetPolicyAssignment([]*string{&noR4}, nil, nil); err != nil {
+		t.Fatalf("failed SetPolicyAssignment: %#v", err)
+	}
+	err = network.WaitFor(testTimeout, func() bool {
+		return network.Route("r1", server.ISIS_LEVEL_1, "192.168.0.3/32") == nil &&
+			fibRoute(network.Fib("r2"), 0xc0a80004, 32) == nil
+	})
+	if err != nil {
+		t.Fatalf("failed policy change: %#v", network.Fib("r2"))
+	}
+	if network.Route("r2", server.ISIS_LEVEL_2, "192.168.0.4/32") == nil ||
+		fibRoute(network.Fib("r2"), 0xc0a80003, 32) == nil ||
+		fibRoute(network.Fib("r2"), 0xc0a80001, 32) == nil {
+		t.Fatalf("failed policy change: %#v", network.Fib("r2"))
+	}
+}
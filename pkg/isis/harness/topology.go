@@ -0,0 +1,171 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package harness
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/spf13/viper"
+)
+
+// Topology describes an emulated network. It is written in code or read
+// from YAML with ParseTopology:
+//
+//	routers:
+//	  - name: r1
+//	    system-id: 0000.0000.0001
+//	    area-addresses: [49.0001]
+//	    loopback: 192.168.0.1/32
+//	links:
+//	  - name: lan1
+//	    type: broadcast
+//	    prefix: 10.0.1.0/24
+//	    ports:
+//	      - router: r1
+//	        priority: 100
+//	      - router: r2
+//	        metric: 20
+type Topology struct {
+	Routers []*Router `mapstructure:"routers"`
+	Links   []*Link   `mapstructure:"links"`
+}
+
+// Router is an IsisServer instance. LevelType defaults to level-all and
+// AreaAddresses to 49.0001. A zero LspLifetime or LspRefresh keeps the
+// server's default. Policies, the defined-sets, policy-definitions and
+// apply-policy of the config in TOML, is added to the config as is.
+type Router struct {
+	Name          string   `mapstructure:"name"`
+	SystemId      string   `mapstructure:"system-id"`
+	AreaAddresses []string `mapstructure:"area-addresses"`
+	LevelType     string   `mapstructure:"level-type"`
+	Loopback      string   `mapstructure:"loopback"`
+	LspLifetime   uint16   `mapstructure:"lsp-lifetime"`
+	LspRefresh    uint16   `mapstructure:"lsp-refresh"`
+	Policies      string   `mapstructure:"policies"`
+}
+
+// Link is a point-to-point link or a LAN segment. Type defaults to
+// point-to-point for two ports and broadcast otherwise. Port n gets host
+// address n+1 of Prefix.
+type Link struct {
+	Name   string  `mapstructure:"name"`
+	Type   string  `mapstructure:"type"`
+	Prefix string  `mapstructure:"prefix"`
+	Ports  []*Port `mapstructure:"ports"`
+}
+
+// Port attaches a router to a link. A zero Metric or Priority keeps the
+// server's default and LevelType defaults to that of the router.
+type Port struct {
+	Router    string `mapstructure:"router"`
+	Metric    uint32 `mapstructure:"metric"`
+	Priority  uint8  `mapstructure:"priority"`
+	LevelType string `mapstructure:"level-type"`
+}
+
+// ParseTopology reads a Topology in format (yaml, toml or json).
+func ParseTopology(data []byte, format string) (*Topology, error) {
+	topology := &Topology{}
+	v := viper.New()
+	v.SetConfigType(format)
+	if err := v.ReadConfig(bytes.NewBuffer(data)); err != nil {
+		return nil, err
+	}
+	if err := v.UnmarshalExact(topology); err != nil {
+		return nil, err
+	}
+	return topology, nil
+}
+
+func (topology *Topology) router(name string) *Router {
+	for _, router := range topology.Routers {
+		if router.Name == name {
+			return router
+		}
+	}
+	return nil
+}
+
+func (topology *Topology) link(name string) *Link {
+	for _, link := range topology.Links {
+		if link.Name == name {
+			return link
+		}
+	}
+	return nil
+}
+
+func (link *Link) linkType() string {
+	if link.Type != "" {
+		return link.Type
+	}
+	if len(link.Ports) == 2 {
+		return "point-to-point"
+	}
+	return "broadcast"
+}
+
+func (topology *Topology) validate() error {
+	names := make(map[string]bool)
+	for _, router := range topology.Routers {
+		if router.Name == "" || names[router.Name] {
+			return errors.New(fmt.Sprintf("router name invalid: %q", router.Name))
+		}
+		names[router.Name] = true
+		if router.SystemId == "" {
+			return errors.New("system-id not defined: " + router.Name)
+		}
+		if router.Loopback != "" {
+			if _, _, err := net.ParseCIDR(router.Loopback); err != nil {
+				return err
+			}
+		}
+	}
+	links := make(map[string]bool)
+	for _, link := range topology.Links {
+		if link.Name == "" || links[link.Name] {
+			return errors.New(fmt.Sprintf("link name invalid: %q", link.Name))
+		}
+		links[link.Name] = true
+		if link.linkType() != "broadcast" && link.linkType() != "point-to-point" {
+			return errors.New("link type invalid: " + link.Name)
+		}
+		if link.linkType() == "point-to-point" && len(link.Ports) != 2 {
+			return errors.New("point-to-point link needs two ports: " + link.Name)
+		}
+		if link.Prefix != "" {
+			_, ipnet, err := net.ParseCIDR(link.Prefix)
+			if err != nil {
+				return err
+			}
+			if ipnet.IP.To4() == nil {
+				return errors.New("link prefix not ipv4: " + link.Name)
+			}
+		}
+		for _, port := range link.Ports {
+			if topology.router(port.Router) == nil {
+				return errors.New("no such router: " + port.Router)
+			}
+		}
+	}
+	return nil
+}
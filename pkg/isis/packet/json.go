@@ -0,0 +1,217 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package packet
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+)
+
+/*
+	JSON schema
+
+	Every TLV is an object with "code" (the TLV code as a number) and
+	"name" (TlvCode.String(), informational only) followed by its own
+	fields. IDs are written as xxxx.xxxx.xxxx (system id), with .nn
+	(neighbour id) and -nn (LSP id) appended, addresses in their usual
+	text form and opaque octets as hex strings. TLVs this package does
+	not decode keep their value as hex.
+*/
+
+type tlvJson struct {
+	Code TlvCode `json:"code"`
+	Name string  `json:"name"`
+}
+
+func newTlvJson(code TlvCode) tlvJson {
+	return tlvJson{
+		Code: code,
+		Name: code.String(),
+	}
+}
+
+func (j *tlvJson) check(code TlvCode) error {
+	if j.Code != code {
+		return errors.New(fmt.Sprintf("tlvJson.check: code mismatch %d %d", j.Code, code))
+	}
+	return nil
+}
+
+// DecodeTlvFromJson creates the TLV named by the "code" member of data and
+// fills it from data.
+func DecodeTlvFromJson(data []byte) (IsisTlv, error) {
+	var j tlvJson
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, err
+	}
+	tlv, err := NewTlv(j.Code)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, tlv); err != nil {
+		return nil, err
+	}
+	return tlv, nil
+}
+
+func jsonHex(b []byte) string {
+	return hex.EncodeToString(b)
+}
+
+func jsonParseHex(s string) ([]byte, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, errors.New("jsonParseHex: invalid hex " + s)
+	}
+	return b, nil
+}
+
+func jsonSystemId(systemId [SYSTEM_ID_LENGTH]byte) string {
+	return fmt.Sprintf("%02x%02x.%02x%02x.%02x%02x",
+		systemId[0], systemId[1], systemId[2], systemId[3], systemId[4], systemId[5])
+}
+
+func jsonNeighbourId(neighbourId [NEIGHBOUR_ID_LENGTH]byte) string {
+	var systemId [SYSTEM_ID_LENGTH]byte
+	copy(systemId[:], neighbourId[0:SYSTEM_ID_LENGTH])
+	return fmt.Sprintf("%s.%02x", jsonSystemId(systemId), neighbourId[SYSTEM_ID_LENGTH])
+}
+
+func jsonLspId(lspId [LSP_ID_LENGTH]byte) string {
+	var neighbourId [NEIGHBOUR_ID_LENGTH]byte
+	copy(neighbourId[:], lspId[0:NEIGHBOUR_ID_LENGTH])
+	return fmt.Sprintf("%s-%02x", jsonNeighbourId(neighbourId), lspId[NEIGHBOUR_ID_LENGTH])
+}
+
+var jsonIdRegexp = regexp.MustCompile(
+	`^([0-9A-Fa-f]{4})\.([0-9A-Fa-f]{4})\.([0-9A-Fa-f]{4})(\.([0-9A-Fa-f]{2}))?(-([0-9A-Fa-f]{2}))?$`)
+
+// jsonParseId parses any of the three ID forms into length octets.
+func jsonParseId(s string, length int) ([]byte, error) {
+	m := jsonIdRegexp.FindStringSubmatch(s)
+	if m == nil {
+		return nil, errors.New("jsonParseId: invalid id " + s)
+	}
+	hs := m[1] + m[2] + m[3]
+	switch length {
+	case SYSTEM_ID_LENGTH:
+		if m[4] != "" || m[6] != "" {
+			return nil, errors.New("jsonParseId: invalid system id " + s)
+		}
+	case NEIGHBOUR_ID_LENGTH:
+		if m[4] == "" || m[6] != "" {
+			return nil, errors.New("jsonParseId: invalid neighbour id " + s)
+		}
+		hs += m[5]
+	case LSP_ID_LENGTH:
+		if m[4] == "" || m[6] == "" {
+			return nil, errors.New("jsonParseId: invalid lsp id " + s)
+		}
+		hs += m[5] + m[7]
+	}
+	return hex.DecodeString(hs)
+}
+
+func jsonParseSystemId(s string) ([SYSTEM_ID_LENGTH]byte, error) {
+	var systemId [SYSTEM_ID_LENGTH]byte
+	b, err := jsonParseId(s, SYSTEM_ID_LENGTH)
+	if err != nil {
+		return systemId, err
+	}
+	copy(systemId[:], b)
+	return systemId, nil
+}
+
+func jsonParseNeighbourId(s string) ([NEIGHBOUR_ID_LENGTH]byte, error) {
+	var neighbourId [NEIGHBOUR_ID_LENGTH]byte
+	b, err := jsonParseId(s, NEIGHBOUR_ID_LENGTH)
+	if err != nil {
+		return neighbourId, err
+	}
+	copy(neighbourId[:], b)
+	return neighbourId, nil
+}
+
+func jsonParseLspId(s string) ([LSP_ID_LENGTH]byte, error) {
+	var lspId [LSP_ID_LENGTH]byte
+	b, err := jsonParseId(s, LSP_ID_LENGTH)
+	if err != nil {
+		return lspId, err
+	}
+	copy(lspId[:], b)
+	return lspId, nil
+}
+
+func jsonIpv4(address uint32) string {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, address)
+	return net.IP(b).String()
+}
+
+func jsonParseIpv4(s string) (uint32, error) {
+	ip := net.ParseIP(s).To4()
+	if ip == nil {
+		return 0, errors.New("jsonParseIpv4: invalid address " + s)
+	}
+	return binary.BigEndian.Uint32(ip), nil
+}
+
+func jsonIpv6(address [4]uint32) string {
+	b := make([]byte, 16)
+	for i := 0; i < 4; i++ {
+		binary.BigEndian.PutUint32(b[i*4:i*4+4], address[i])
+	}
+	return net.IP(b).String()
+}
+
+func jsonParseIpv6(s string) ([4]uint32, error) {
+	var address [4]uint32
+	ip := net.ParseIP(s)
+	if ip == nil || ip.To4() != nil {
+		return address, errors.New("jsonParseIpv6: invalid address " + s)
+	}
+	for i := 0; i < 4; i++ {
+		address[i] = binary.BigEndian.Uint32(ip[i*4 : i*4+4])
+	}
+	return address, nil
+}
+
+func jsonHexList(bs [][]byte) []string {
+	ss := make([]string, 0)
+	for _, b := range bs {
+		ss = append(ss, jsonHex(b))
+	}
+	return ss
+}
+
+func jsonParseHexList(ss []string) ([][]byte, error) {
+	bs := make([][]byte, 0)
+	for _, s := range ss {
+		b, err := jsonParseHex(s)
+		if err != nil {
+			return nil, err
+		}
+		bs = append(bs, b)
+	}
+	return bs, nil
+}
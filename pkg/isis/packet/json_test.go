@@ -0,0 +1,159 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package packet
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestTlvJson(t *testing.T) {
+	tlvs := [][]byte{
+		// Area Addresses
+		{0x01, 0x04, 0x03, 0x49, 0x00, 0x01},
+		// IS Neighbours (LSPs)
+		{0x02, 0x0c, 0x00, 0x0a, 0x80, 0x80, 0x80, 0x36, 0xd3, 0x64, 0x2f, 0x27, 0xad, 0x00},
+		// Partition Designated Level 2 IS
+		{0x04, 0x06, 0x36, 0xd3, 0x64, 0x2f, 0x27, 0xad},
+		// IS Neighbours (Hellos)
+		{0x06, 0x06, 0x52, 0x54, 0x00, 0x12, 0x34, 0x56},
+		// Padding
+		{0x08, 0x03, 0x00, 0x00, 0x00},
+		// LSP Entries
+		{0x09, 0x10, 0x04, 0xb0, 0x36, 0xd3, 0x64, 0x2f, 0x27, 0xad, 0x00, 0x00, 0x00, 0x00, 0x00, 0x09, 0xc5, 0x13},
+		// Authentication Information
+		{0x0a, 0x05, 0x01, 0x70, 0x61, 0x73, 0x73},
+		// LSP Buffer Size
+		{0x0e, 0x02, 0x05, 0xd4},
+		// IP Internal Reachability Information
+		{0x80, 0x0c, 0x0a, 0x80, 0x80, 0x80, 0xc0, 0xa8, 0x01, 0x00, 0xff, 0xff, 0xff, 0x00},
+		// Protocols Supported
+		{0x81, 0x02, 0xcc, 0x8e},
+		// IP External Reachability Information
+		{0x82, 0x0c, 0x4a, 0x80, 0x80, 0x80, 0xc0, 0xa8, 0x02, 0x00, 0xff, 0xff, 0xff, 0x00},
+		// Inter-Domain Routing Protocol Information
+		{0x83, 0x03, 0x01, 0xaa, 0xbb},
+		// IP Interface Address
+		{0x84, 0x08, 0xc0, 0xa8, 0x01, 0x01, 0xc0, 0xa8, 0x02, 0x01},
+		// Traffic Engineering Router ID
+		{0x86, 0x04, 0xc0, 0xa8, 0x03, 0x01},
+		// Extended IP Reachability
+		{0x87, 0x08, 0x00, 0x00, 0x00, 0x0a, 0x18, 0xc0, 0xa8, 0x0d},
+		// Dynamic hostname
+		{0x89, 0x04, 0x74, 0x65, 0x73, 0x74},
+		// Extended IS Reachability
+		{0x16, 0x2d, 0x36, 0xd3, 0x64, 0x2f, 0x27, 0xad, 0x00, 0x00, 0x00, 0x0a, 0x22,
+			0x03, 0x04, 0x00, 0x00, 0x00, 0x01,
+			0x06, 0x04, 0xc0, 0xa8, 0x01, 0x01,
+			0x08, 0x04, 0xc0, 0xa8, 0x01, 0x02,
+			0x09, 0x04, 0x4c, 0xee, 0x6b, 0x28,
+			0x12, 0x04, 0x00, 0x00, 0x00, 0x0a,
+			0x63, 0x02, 0xaa, 0xbb},
+		// Point-to-Point Three-Way Adjacency
+		{0xf0, 0x0f, 0x00, 0x00, 0x00, 0x00, 0x01, 0x36, 0xd3, 0x64, 0x2f, 0x27, 0xad, 0x00, 0x00, 0x00, 0x02},
+		// IPv6 Reachability
+		{0xec, 0x0e, 0x00, 0x00, 0x00, 0x0a, 0x00, 0x40, 0x20, 0x01, 0x0d, 0xb8, 0x00, 0x00, 0x00, 0x13},
+		// IPv6 Interface Address
+		{0xe8, 0x10, 0x20, 0x01, 0x0d, 0xb8, 0x00, 0x00, 0x00, 0x13, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+		// unknown
+		{0xfa, 0x02, 0x01, 0x02},
+	}
+
+	for _, d1 := range tlvs {
+		t1, err := NewTlv(TlvCode(d1[0]))
+		if err != nil {
+			t.Fatalf("failed NewTlv: %#v", err)
+		}
+		err = t1.DecodeFromBytes(d1)
+		if err != nil {
+			t.Fatalf("failed DecodeFromBytes %s: %#v", t1.TlvCode(), err)
+		}
+		j1, err := json.Marshal(t1)
+		if err != nil {
+			t.Fatalf("failed Marshal %s: %#v", t1.TlvCode(), err)
+		}
+		t2, err := DecodeTlvFromJson(j1)
+		if err != nil {
+			t.Fatalf("failed DecodeTlvFromJson %s: %#v", t1.TlvCode(), err)
+		}
+		d2, err := t2.Serialize()
+		if err != nil {
+			t.Fatalf("failed Serialize %s: %#v", t1.TlvCode(), err)
+		}
+		if !bytes.Equal(d1, d2) {
+			t.Fatalf("failed !Equal %s\n%x\n%x\n%s", t1.TlvCode(), d1, d2, j1)
+		}
+	}
+}
+
+func TestTlvJsonSchema(t *testing.T) {
+	t1, _ := NewDynamicHostnameTlv()
+	t1.SetDynamicHostname([]byte("test"))
+	j1, err := json.Marshal(t1)
+	if err != nil {
+		t.Fatalf("failed Marshal: %#v", err)
+	}
+	if string(j1) != `{"code":137,"name":"TLV_CODE_DYNAMIC_HOSTNAME","dynamic_hostname":"test"}` {
+		t.Fatalf("failed schema: %s", j1)
+	}
+
+	_, err = DecodeTlvFromJson([]byte(`{"code":22,"neighbours":[{"neighbour_id":"0000.0000.0001"}]}`))
+	if err == nil {
+		t.Fatalf("failed neighbour id without pseudonode id accepted")
+	}
+}
+
+func TestLsPduJson(t *testing.T) {
+	d1 := []byte{
+		0x83, 0x1b, 0x01, 0x00, 0x14, 0x01, 0x00, 0x00, 0x00, 0x97, 0x04, 0x8b, 0x8e, 0x7f, 0x0f, 0x71, 0x20, 0x20, 0x00, 0x00, 0x00, 0x00, 0x00, 0x09, 0xc5, 0x13, 0x03,
+		0x81, 0x02, 0xcc, 0x8e,
+		0x01, 0x02, 0x01, 0x01,
+		0x89, 0x08, 0x66, 0x72, 0x72, 0x74, 0x65, 0x73, 0x74, 0x33,
+		0x86, 0x04, 0xc0, 0xa8, 0x03, 0x01,
+		0x16, 0x16, 0x36, 0xd3, 0x64, 0x2f, 0x27, 0xad, 0x00, 0x00, 0x00, 0x0a, 0x00, 0x12, 0x16, 0xbb, 0x16, 0xa8, 0xe9, 0x00, 0x00, 0x00, 0x0a, 0x00,
+		0x84, 0x04, 0xc0, 0xa8, 0x03, 0x01,
+		0x87, 0x18, 0x00, 0x00, 0x00, 0x0a, 0x18, 0xc0, 0xa8, 0x0d, 0x00, 0x00, 0x00, 0x0a, 0x18, 0xc0, 0xa8, 0x22, 0x00, 0x00, 0x00, 0x0a, 0x18, 0xc0, 0xa8, 0x03,
+		0xec, 0x2a, 0x00, 0x00, 0x00, 0x0a, 0x00, 0x40, 0x20, 0x01, 0x0d, 0xb8, 0x00, 0x00, 0x00, 0x13, 0x00, 0x00, 0x00, 0x0a, 0x00, 0x40, 0x20, 0x01, 0x0d, 0xb8, 0x00, 0x00, 0x00, 0x34, 0x00, 0x00, 0x00, 0x0a, 0x00, 0x40, 0x20, 0x01, 0x0d, 0xb8, 0x00, 0x00, 0x00, 0x03,
+	}
+
+	p1, err := DecodePduFromBytes(d1)
+	if err != nil {
+		t.Fatalf("failed DecodePduFromBytes: %#v", err)
+	}
+
+	j1, err := json.Marshal(p1)
+	if err != nil {
+		t.Fatalf("failed Marshal: %#v", err)
+	}
+
+	p2 := &LsPdu{}
+	err = json.Unmarshal(j1, p2)
+	if err != nil {
+		t.Fatalf("failed Unmarshal: %#v", err)
+	}
+
+	d2, err := p2.Serialize()
+	if err != nil {
+		t.Fatalf("failed Serialize: %#v", err)
+	}
+
+	if !bytes.Equal(d1, d2) {
+		t.Fatalf("failed !bytes.Equal\n%s", j1)
+	}
+}
@@ -67,7 +67,7 @@ func (iih *IihPdu) String() string {
 	b.WriteString(iih.base.StringFixed())
 	fmt.Fprintf(&b, "CircuitType                     %s\n", iih.CircuitType.String())
 	fmt.Fprintf(&b, "sourceId                        ")
-	for t := range iih.sourceId {
+	for _, t := range iih.sourceId {
 		fmt.Fprintf(&b, "%02x", t)
 	}
 	fmt.Fprintf(&b, "\n")
@@ -76,7 +76,7 @@ func (iih *IihPdu) String() string {
 		iih.base.pduType == PDU_TYPE_LEVEL2_LAN_IIHP {
 		fmt.Fprintf(&b, "Priority                        %d\n", iih.Priority)
 		fmt.Fprintf(&b, "lanId                           ")
-		for t := range iih.lanId {
+		for _, t := range iih.lanId {
 			fmt.Fprintf(&b, "%02x", t)
 		}
 		fmt.Fprintf(&b, "\n")
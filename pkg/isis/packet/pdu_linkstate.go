@@ -20,6 +20,7 @@ package packet
 import (
 	"bytes"
 	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -68,7 +69,7 @@ func (ls *LsPdu) String() string {
 	b.WriteString(ls.base.StringFixed())
 	fmt.Fprintf(&b, "RemainingLifetime               %d\n", ls.RemainingLifetime)
 	fmt.Fprintf(&b, "lspId                           ")
-	for t := range ls.lspId {
+	for _, t := range ls.lspId {
 		fmt.Fprintf(&b, "%02x", t)
 	}
 	fmt.Fprintf(&b, "\n")
@@ -215,6 +216,85 @@ func (ls *LsPdu) Serialize() ([]byte, error) {
 	return data, nil
 }
 
+type lsPduJson struct {
+	PduType               PduType           `json:"pdu_type"`
+	RemainingLifetime     uint16            `json:"remaining_lifetime"`
+	LspId                 string            `json:"lsp_id"`
+	SequenceNumber        uint32            `json:"sequence_number"`
+	Checksum              uint16            `json:"checksum"`
+	PartitionRepairFlag   bool              `json:"partition_repair_flag"`
+	AttachedDefaultMetric bool              `json:"attached_default_metric"`
+	AttachedDelayMetric   bool              `json:"attached_delay_metric"`
+	AttachedExpenseMetric bool              `json:"attached_expense_metric"`
+	AttachedErrorMetric   bool              `json:"attached_error_metric"`
+	LSPDBOverloadFlag     bool              `json:"lspdb_overload_flag"`
+	IsType                IsType            `json:"is_type"`
+	Tlvs                  []json.RawMessage `json:"tlvs"`
+}
+
+func (ls *LsPdu) MarshalJSON() ([]byte, error) {
+	j := lsPduJson{
+		PduType:               ls.base.pduType,
+		RemainingLifetime:     ls.RemainingLifetime,
+		LspId:                 jsonLspId(ls.lspId),
+		SequenceNumber:        ls.SequenceNumber,
+		Checksum:              ls.Checksum,
+		PartitionRepairFlag:   ls.PartitionRepairFlag,
+		AttachedDefaultMetric: ls.AttachedDefaultMetric,
+		AttachedDelayMetric:   ls.AttachedDealyMetric,
+		AttachedExpenseMetric: ls.AttachedExpenseMetric,
+		AttachedErrorMetric:   ls.AttachedErrorMetric,
+		LSPDBOverloadFlag:     ls.LSPDBOverloadFlag,
+		IsType:                ls.IsType,
+		Tlvs:                  make([]json.RawMessage, 0),
+	}
+	for _, tlv := range ls.base.tlvs {
+		tlvJson, err := json.Marshal(tlv)
+		if err != nil {
+			return nil, err
+		}
+		j.Tlvs = append(j.Tlvs, json.RawMessage(tlvJson))
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON keeps the checksum as written. Call SetChecksum after
+// editing an LSP by hand.
+func (ls *LsPdu) UnmarshalJSON(data []byte) error {
+	var j lsPduJson
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	lstmp, err := NewLsPdu(j.PduType)
+	if err != nil {
+		return err
+	}
+	lspId, err := jsonParseLspId(j.LspId)
+	if err != nil {
+		return err
+	}
+	lstmp.RemainingLifetime = j.RemainingLifetime
+	lstmp.lspId = lspId
+	lstmp.SequenceNumber = j.SequenceNumber
+	lstmp.Checksum = j.Checksum
+	lstmp.PartitionRepairFlag = j.PartitionRepairFlag
+	lstmp.AttachedDefaultMetric = j.AttachedDefaultMetric
+	lstmp.AttachedDealyMetric = j.AttachedDelayMetric
+	lstmp.AttachedExpenseMetric = j.AttachedExpenseMetric
+	lstmp.AttachedErrorMetric = j.AttachedErrorMetric
+	lstmp.LSPDBOverloadFlag = j.LSPDBOverloadFlag
+	lstmp.IsType = j.IsType
+	for _, tlvJson := range j.Tlvs {
+		tlv, err := DecodeTlvFromJson(tlvJson)
+		if err != nil {
+			return err
+		}
+		lstmp.base.AddTlv(tlv)
+	}
+	*ls = *lstmp
+	return nil
+}
+
 func (ls *LsPdu) BaseValid() bool {
 	return ls.base.valid()
 }
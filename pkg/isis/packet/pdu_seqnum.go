@@ -63,19 +63,19 @@ func (sn *SnPdu) String() string {
 	var b bytes.Buffer
 	b.WriteString(sn.base.StringFixed())
 	fmt.Fprintf(&b, "sourceId                        ")
-	for t := range sn.sourceId {
+	for _, t := range sn.sourceId {
 		fmt.Fprintf(&b, "%02x", t)
 	}
 	fmt.Fprintf(&b, "\n")
 	if sn.base.pduType == PDU_TYPE_LEVEL1_CSNP ||
 		sn.base.pduType == PDU_TYPE_LEVEL2_CSNP {
 		fmt.Fprintf(&b, "startLspId                      ")
-		for t := range sn.startLspId {
+		for _, t := range sn.startLspId {
 			fmt.Fprintf(&b, "%02x", t)
 		}
 		fmt.Fprintf(&b, "\n")
 		fmt.Fprintf(&b, "endLspId                        ")
-		for t := range sn.endLspId {
+		for _, t := range sn.endLspId {
 			fmt.Fprintf(&b, "%02x", t)
 		}
 		fmt.Fprintf(&b, "\n")
@@ -141,11 +141,19 @@ func (sn *SnPdu) SetSourceId(sourceId [NEIGHBOUR_ID_LENGTH]byte) error {
 	return nil
 }
 
+func (sn *SnPdu) StartLspId() [LSP_ID_LENGTH]byte {
+	return sn.startLspId
+}
+
 func (sn *SnPdu) SetStartLspId(startLspId [LSP_ID_LENGTH]byte) error {
 	sn.startLspId = startLspId
 	return nil
 }
 
+func (sn *SnPdu) EndLspId() [LSP_ID_LENGTH]byte {
+	return sn.endLspId
+}
+
 func (sn *SnPdu) SetEndLspId(endLspId [LSP_ID_LENGTH]byte) error {
 	sn.endLspId = endLspId
 	return nil
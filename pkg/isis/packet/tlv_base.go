@@ -19,6 +19,8 @@ package packet
 
 import (
 	"bytes"
+	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"fmt"
 )
@@ -163,3 +165,96 @@ func (tlv *unknownTlv) Serialize() ([]byte, error) {
 	}
 	return data, nil
 }
+
+type unknownTlvJson struct {
+	tlvJson
+	Value string `json:"value"`
+}
+
+func (tlv *unknownTlv) MarshalJSON() ([]byte, error) {
+	j := unknownTlvJson{
+		tlvJson: newTlvJson(tlv.base.code),
+		Value:   jsonHex(tlv.base.value),
+	}
+	return json.Marshal(j)
+}
+
+func (tlv *unknownTlv) UnmarshalJSON(data []byte) error {
+	var j unknownTlvJson
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	value, err := jsonParseHex(j.Value)
+	if err != nil {
+		return err
+	}
+	if len(value) > 255 {
+		return errors.New("unknownTlv.UnmarshalJSON: value too long")
+	}
+	tlv.base.code = j.Code
+	tlv.base.length = uint8(len(value))
+	tlv.base.value = value
+	return nil
+}
+
+const (
+	SUBTLV_CODE_32BIT_ADMINISTRATIVE_TAG = 1
+)
+
+// decodeSubtlvs splits the sub-TLVs of a prefix of TLV 135 and 236 into
+// their type, length and value octets.
+func decodeSubtlvs(data []byte) ([][]byte, error) {
+	subtlvs := make([][]byte, 0)
+	i := 0
+	for i < len(data) {
+		if i+2 > len(data) {
+			return nil, errors.New("decodeSubtlvs: size invalid")
+		}
+		stlvl := int(data[i+1])
+		if i+2+stlvl > len(data) {
+			return nil, errors.New("decodeSubtlvs: size invalid")
+		}
+		stlv := make([]byte, 2+stlvl)
+		copy(stlv, data[i:i+2+stlvl])
+		subtlvs = append(subtlvs, stlv)
+		i += 2 + stlvl
+	}
+	return subtlvs, nil
+}
+
+func subtlvsTags(subtlvs [][]byte) []uint32 {
+	tags := make([]uint32, 0)
+	for _, stlv := range subtlvs {
+		if stlv[0] != SUBTLV_CODE_32BIT_ADMINISTRATIVE_TAG {
+			continue
+		}
+		for i := 2; i+4 <= len(stlv); i += 4 {
+			tags = append(tags, binary.BigEndian.Uint32(stlv[i:i+4]))
+		}
+	}
+	return tags
+}
+
+func subtlvsSetTags(subtlvs [][]byte, tags []uint32) [][]byte {
+	stlvs := make([][]byte, 0)
+	for _, stlv := range subtlvs {
+		if stlv[0] != SUBTLV_CODE_32BIT_ADMINISTRATIVE_TAG {
+			stlvs = append(stlvs, stlv)
+		}
+	}
+	for len(tags) > 0 {
+		n := len(tags)
+		if n > 63 {
+			n = 63
+		}
+		stlv := make([]byte, 2+n*4)
+		stlv[0] = SUBTLV_CODE_32BIT_ADMINISTRATIVE_TAG
+		stlv[1] = uint8(n * 4)
+		for i, tag := range tags[:n] {
+			binary.BigEndian.PutUint32(stlv[2+i*4:6+i*4], tag)
+		}
+		stlvs = append(stlvs, stlv)
+		tags = tags[n:]
+	}
+	return stlvs
+}
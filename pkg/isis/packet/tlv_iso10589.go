@@ -20,6 +20,7 @@ package packet
 import (
 	"bytes"
 	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"fmt"
 )
@@ -163,6 +164,41 @@ func (tlv *areaAddressesTlv) Serialize() ([]byte, error) {
 	return data, nil
 }
 
+type areaAddressesTlvJson struct {
+	tlvJson
+	AreaAddresses []string `json:"area_addresses"`
+}
+
+func (tlv *areaAddressesTlv) MarshalJSON() ([]byte, error) {
+	j := areaAddressesTlvJson{
+		tlvJson:       newTlvJson(tlv.base.code),
+		AreaAddresses: jsonHexList(tlv.areaAddresses),
+	}
+	return json.Marshal(j)
+}
+
+func (tlv *areaAddressesTlv) UnmarshalJSON(data []byte) error {
+	var j areaAddressesTlvJson
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if err := j.check(TLV_CODE_AREA_ADDRESSES); err != nil {
+		return err
+	}
+	areaAddresses, err := jsonParseHexList(j.AreaAddresses)
+	if err != nil {
+		return err
+	}
+	tlv.base.code = TLV_CODE_AREA_ADDRESSES
+	tlv.areaAddresses = make([][]byte, 0)
+	for _, areaAddress := range areaAddresses {
+		if err := tlv.AddAreaAddress(areaAddress); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 /*
 	Intermediate System Neighbours (LSPs)
 	code - 2
@@ -399,6 +435,88 @@ func (tlv *isNeighboursLspTlv) Serialize() ([]byte, error) {
 	return data, nil
 }
 
+type isNeighboursLspNeighbourJson struct {
+	NeighbourId            string     `json:"neighbour_id"`
+	DefaultMetric          uint8      `json:"default_metric"`
+	DefaultMetricType      MetricType `json:"default_metric_type"`
+	DelayMetric            uint8      `json:"delay_metric"`
+	DelayMetricSupported   bool       `json:"delay_metric_supported"`
+	DelayMetricType        MetricType `json:"delay_metric_type"`
+	ExpenseMetric          uint8      `json:"expense_metric"`
+	ExpenseMetricSupported bool       `json:"expense_metric_supported"`
+	ExpenseMetricType      MetricType `json:"expense_metric_type"`
+	ErrorMetric            uint8      `json:"error_metric"`
+	ErrorMetricSupported   bool       `json:"error_metric_supported"`
+	ErrorMetricType        MetricType `json:"error_metric_type"`
+}
+
+type isNeighboursLspTlvJson struct {
+	tlvJson
+	VirtualFlag bool                           `json:"virtual_flag"`
+	Neighbours  []isNeighboursLspNeighbourJson `json:"neighbours"`
+}
+
+func (tlv *isNeighboursLspTlv) MarshalJSON() ([]byte, error) {
+	j := isNeighboursLspTlvJson{
+		tlvJson:     newTlvJson(tlv.base.code),
+		VirtualFlag: tlv.VirtualFlag,
+		Neighbours:  make([]isNeighboursLspNeighbourJson, 0),
+	}
+	for _, ntmp := range tlv.neighbours {
+		j.Neighbours = append(j.Neighbours, isNeighboursLspNeighbourJson{
+			NeighbourId:            jsonNeighbourId(ntmp.neighbourId),
+			DefaultMetric:          ntmp.DefaultMetric,
+			DefaultMetricType:      ntmp.DefaultMetricType,
+			DelayMetric:            ntmp.DelayMetric,
+			DelayMetricSupported:   ntmp.DelayMetricSupported,
+			DelayMetricType:        ntmp.DelayMetricType,
+			ExpenseMetric:          ntmp.ExpenseMetric,
+			ExpenseMetricSupported: ntmp.ExpenseMetricSupported,
+			ExpenseMetricType:      ntmp.ExpenseMetricType,
+			ErrorMetric:            ntmp.ErrorMetric,
+			ErrorMetricSupported:   ntmp.ErrorMetricSupported,
+			ErrorMetricType:        ntmp.ErrorMetricType,
+		})
+	}
+	return json.Marshal(j)
+}
+
+func (tlv *isNeighboursLspTlv) UnmarshalJSON(data []byte) error {
+	var j isNeighboursLspTlvJson
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if err := j.check(TLV_CODE_IS_NEIGHBOURS_LSP); err != nil {
+		return err
+	}
+	tlv.base.code = TLV_CODE_IS_NEIGHBOURS_LSP
+	tlv.base.length = 1
+	tlv.VirtualFlag = j.VirtualFlag
+	tlv.neighbours = make([]isNeighboursLspNeighbour, 0)
+	for _, ntmp := range j.Neighbours {
+		neighbourId, err := jsonParseNeighbourId(ntmp.NeighbourId)
+		if err != nil {
+			return err
+		}
+		neighbour, _ := NewIsNeighboursLspNeighbour(neighbourId)
+		neighbour.DefaultMetric = ntmp.DefaultMetric
+		neighbour.DefaultMetricType = ntmp.DefaultMetricType
+		neighbour.DelayMetric = ntmp.DelayMetric
+		neighbour.DelayMetricSupported = ntmp.DelayMetricSupported
+		neighbour.DelayMetricType = ntmp.DelayMetricType
+		neighbour.ExpenseMetric = ntmp.ExpenseMetric
+		neighbour.ExpenseMetricSupported = ntmp.ExpenseMetricSupported
+		neighbour.ExpenseMetricType = ntmp.ExpenseMetricType
+		neighbour.ErrorMetric = ntmp.ErrorMetric
+		neighbour.ErrorMetricSupported = ntmp.ErrorMetricSupported
+		neighbour.ErrorMetricType = ntmp.ErrorMetricType
+		if err := tlv.AddNeighbour(neighbour); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 /*
 	End System Neighbours
 	code - 3
@@ -493,6 +611,36 @@ func (tlv *partitionDesignatedL2IsTlv) Serialize() ([]byte, error) {
 	return data, nil
 }
 
+type partitionDesignatedL2IsTlvJson struct {
+	tlvJson
+	DesignatedL2IsId string `json:"designated_l2_is_id"`
+}
+
+func (tlv *partitionDesignatedL2IsTlv) MarshalJSON() ([]byte, error) {
+	j := partitionDesignatedL2IsTlvJson{
+		tlvJson:          newTlvJson(tlv.base.code),
+		DesignatedL2IsId: jsonSystemId(tlv.designatedL2IsId),
+	}
+	return json.Marshal(j)
+}
+
+func (tlv *partitionDesignatedL2IsTlv) UnmarshalJSON(data []byte) error {
+	var j partitionDesignatedL2IsTlvJson
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if err := j.check(TLV_CODE_PARTITION_DESIGNATED_L2_IS); err != nil {
+		return err
+	}
+	designatedL2IsId, err := jsonParseSystemId(j.DesignatedL2IsId)
+	if err != nil {
+		return err
+	}
+	tlv.base.code = TLV_CODE_PARTITION_DESIGNATED_L2_IS
+	tlv.base.length = SYSTEM_ID_LENGTH
+	return tlv.SetDesignatedL2IsId(designatedL2IsId)
+}
+
 /*
 	Prefix Neighbours
 	code - 5
@@ -645,6 +793,50 @@ func (tlv *isNeighboursHelloTlv) Serialize() ([]byte, error) {
 	return data, nil
 }
 
+type isNeighboursHelloTlvJson struct {
+	tlvJson
+	LanAddresses []string `json:"lan_addresses"`
+}
+
+func (tlv *isNeighboursHelloTlv) MarshalJSON() ([]byte, error) {
+	j := isNeighboursHelloTlvJson{
+		tlvJson:      newTlvJson(tlv.base.code),
+		LanAddresses: make([]string, 0),
+	}
+	for _, lanAddress := range tlv.lanAddresses {
+		j.LanAddresses = append(j.LanAddresses, jsonHex(lanAddress[:]))
+	}
+	return json.Marshal(j)
+}
+
+func (tlv *isNeighboursHelloTlv) UnmarshalJSON(data []byte) error {
+	var j isNeighboursHelloTlvJson
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if err := j.check(TLV_CODE_IS_NEIGHBOURS_HELLO); err != nil {
+		return err
+	}
+	tlv.base.code = TLV_CODE_IS_NEIGHBOURS_HELLO
+	tlv.base.length = 0
+	tlv.lanAddresses = make([][SYSTEM_ID_LENGTH]byte, 0)
+	for _, latmp := range j.LanAddresses {
+		b, err := jsonParseHex(latmp)
+		if err != nil {
+			return err
+		}
+		if len(b) != SYSTEM_ID_LENGTH {
+			return errors.New("isNeighboursHelloTlv.UnmarshalJSON: lan address invalid " + latmp)
+		}
+		var lanAddress [SYSTEM_ID_LENGTH]byte
+		copy(lanAddress[:], b)
+		if err := tlv.AddLanAddress(lanAddress); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 /*
 	Intermediate System Neighbours (variable length)
 	code - 7
@@ -720,6 +912,31 @@ func (tlv *paddingTlv) Serialize() ([]byte, error) {
 	return data, nil
 }
 
+type paddingTlvJson struct {
+	tlvJson
+	Length uint8 `json:"length"`
+}
+
+func (tlv *paddingTlv) MarshalJSON() ([]byte, error) {
+	j := paddingTlvJson{
+		tlvJson: newTlvJson(tlv.base.code),
+		Length:  tlv.base.length,
+	}
+	return json.Marshal(j)
+}
+
+func (tlv *paddingTlv) UnmarshalJSON(data []byte) error {
+	var j paddingTlvJson
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if err := j.check(TLV_CODE_PADDING); err != nil {
+		return err
+	}
+	tlv.base.code = TLV_CODE_PADDING
+	return tlv.SetLength(j.Length)
+}
+
 /*
 	LSP Entries
 	code - 9
@@ -887,6 +1104,61 @@ func (tlv *lspEntriesTlv) Serialize() ([]byte, error) {
 	return data, nil
 }
 
+type lspEntriesLspEntryJson struct {
+	RemainingLifetime uint16 `json:"remaining_lifetime"`
+	LspId             string `json:"lsp_id"`
+	LspSeqNum         uint32 `json:"lsp_seq_num"`
+	Checksum          uint16 `json:"checksum"`
+}
+
+type lspEntriesTlvJson struct {
+	tlvJson
+	LspEntries []lspEntriesLspEntryJson `json:"lsp_entries"`
+}
+
+func (tlv *lspEntriesTlv) MarshalJSON() ([]byte, error) {
+	j := lspEntriesTlvJson{
+		tlvJson:    newTlvJson(tlv.base.code),
+		LspEntries: make([]lspEntriesLspEntryJson, 0),
+	}
+	for _, ltmp := range tlv.lspEntries {
+		j.LspEntries = append(j.LspEntries, lspEntriesLspEntryJson{
+			RemainingLifetime: ltmp.RemainingLifetime,
+			LspId:             jsonLspId(ltmp.lspId),
+			LspSeqNum:         ltmp.LspSeqNum,
+			Checksum:          ltmp.Checksum,
+		})
+	}
+	return json.Marshal(j)
+}
+
+func (tlv *lspEntriesTlv) UnmarshalJSON(data []byte) error {
+	var j lspEntriesTlvJson
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if err := j.check(TLV_CODE_LSP_ENTRIES); err != nil {
+		return err
+	}
+	tlv.base.code = TLV_CODE_LSP_ENTRIES
+	tlv.base.length = 0
+	tlv.lspEntries = make([]lspEntriesLspEntry, 0)
+	for _, ltmp := range j.LspEntries {
+		lspId, err := jsonParseLspId(ltmp.LspId)
+		if err != nil {
+			return err
+		}
+		lspEntry, _ := NewLspEntriesLspEntry(lspId)
+		lspEntry.RemainingLifetime = ltmp.RemainingLifetime
+		lspEntry.LspSeqNum = ltmp.LspSeqNum
+		lspEntry.Checksum = ltmp.Checksum
+		if err := tlv.AddLspEntry(lspEntry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 /*
 	Authentication Information
 	code - 10
@@ -957,6 +1229,43 @@ func (tlv *authInfoTlv) Serialize() ([]byte, error) {
 	return data, nil
 }
 
+type authInfoTlvJson struct {
+	tlvJson
+	AuthType  AuthType `json:"auth_type"`
+	AuthValue string   `json:"auth_value"`
+}
+
+func (tlv *authInfoTlv) MarshalJSON() ([]byte, error) {
+	j := authInfoTlvJson{
+		tlvJson:   newTlvJson(tlv.base.code),
+		AuthType:  tlv.AuthType,
+		AuthValue: jsonHex(tlv.authValue),
+	}
+	return json.Marshal(j)
+}
+
+func (tlv *authInfoTlv) UnmarshalJSON(data []byte) error {
+	var j authInfoTlvJson
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if err := j.check(TLV_CODE_AUTH_INFO); err != nil {
+		return err
+	}
+	authValue, err := jsonParseHex(j.AuthValue)
+	if err != nil {
+		return err
+	}
+	if 1+len(authValue) > 255 {
+		return errors.New("authInfoTlv.UnmarshalJSON: auth value too long")
+	}
+	tlv.base.code = TLV_CODE_AUTH_INFO
+	tlv.base.length = uint8(1 + len(authValue))
+	tlv.AuthType = j.AuthType
+	tlv.authValue = authValue
+	return nil
+}
+
 /*
 	originatingLSPBufferSize
 	code - 14
@@ -1013,3 +1322,30 @@ func (tlv *lspBuffSizeTlv) Serialize() ([]byte, error) {
 	}
 	return data, nil
 }
+
+type lspBuffSizeTlvJson struct {
+	tlvJson
+	LspBufferSize uint16 `json:"lsp_buffer_size"`
+}
+
+func (tlv *lspBuffSizeTlv) MarshalJSON() ([]byte, error) {
+	j := lspBuffSizeTlvJson{
+		tlvJson:       newTlvJson(tlv.base.code),
+		LspBufferSize: tlv.LspBufferSize,
+	}
+	return json.Marshal(j)
+}
+
+func (tlv *lspBuffSizeTlv) UnmarshalJSON(data []byte) error {
+	var j lspBuffSizeTlvJson
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if err := j.check(TLV_CODE_LSP_BUFF_SIZE); err != nil {
+		return err
+	}
+	tlv.base.code = TLV_CODE_LSP_BUFF_SIZE
+	tlv.base.length = 2
+	tlv.LspBufferSize = j.LspBufferSize
+	return nil
+}
@@ -20,6 +20,7 @@ package packet
 import (
 	"bytes"
 	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"fmt"
 )
@@ -218,6 +219,81 @@ func (tlv *ipInternalReachInfoTlv) Serialize() ([]byte, error) {
 	return data, nil
 }
 
+type ipReachInfoIpSubnetJson struct {
+	IpAddress              string     `json:"ip_address"`
+	SubnetMask             string     `json:"subnet_mask"`
+	DefaultMetric          uint8      `json:"default_metric"`
+	DefaultMetricType      MetricType `json:"default_metric_type"`
+	DelayMetric            uint8      `json:"delay_metric"`
+	DelayMetricSupported   bool       `json:"delay_metric_supported"`
+	ExpenseMetric          uint8      `json:"expense_metric"`
+	ExpenseMetricSupported bool       `json:"expense_metric_supported"`
+	ErrorMetric            uint8      `json:"error_metric"`
+	ErrorMetricSupported   bool       `json:"error_metric_supported"`
+}
+
+type ipInternalReachInfoTlvJson struct {
+	tlvJson
+	IpSubnets []ipReachInfoIpSubnetJson `json:"ip_subnets"`
+}
+
+func (tlv *ipInternalReachInfoTlv) MarshalJSON() ([]byte, error) {
+	j := ipInternalReachInfoTlvJson{
+		tlvJson:   newTlvJson(tlv.base.code),
+		IpSubnets: make([]ipReachInfoIpSubnetJson, 0),
+	}
+	for _, istmp := range tlv.ipSubnets {
+		j.IpSubnets = append(j.IpSubnets, ipReachInfoIpSubnetJson{
+			IpAddress:              jsonIpv4(istmp.IpAddress),
+			SubnetMask:             jsonIpv4(istmp.SubnetMask),
+			DefaultMetric:          istmp.DefaultMetric,
+			DefaultMetricType:      istmp.DefaultMetricType,
+			DelayMetric:            istmp.DelayMetric,
+			DelayMetricSupported:   istmp.DelayMetricSupported,
+			ExpenseMetric:          istmp.ExpenseMetric,
+			ExpenseMetricSupported: istmp.ExpenseMetricSupported,
+			ErrorMetric:            istmp.ErrorMetric,
+			ErrorMetricSupported:   istmp.ErrorMetricSupported,
+		})
+	}
+	return json.Marshal(j)
+}
+
+func (tlv *ipInternalReachInfoTlv) UnmarshalJSON(data []byte) error {
+	var j ipInternalReachInfoTlvJson
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if err := j.check(TLV_CODE_IP_INTERNAL_REACH_INFO); err != nil {
+		return err
+	}
+	tlv.base.code = TLV_CODE_IP_INTERNAL_REACH_INFO
+	tlv.base.length = 0
+	tlv.ipSubnets = make([]ipInternalReachInfoIpSubnet, 0)
+	for _, istmp := range j.IpSubnets {
+		ipSubnet, _ := NewIpInternalReachInfoIpSubnet()
+		var err error
+		if ipSubnet.IpAddress, err = jsonParseIpv4(istmp.IpAddress); err != nil {
+			return err
+		}
+		if ipSubnet.SubnetMask, err = jsonParseIpv4(istmp.SubnetMask); err != nil {
+			return err
+		}
+		ipSubnet.DefaultMetric = istmp.DefaultMetric
+		ipSubnet.DefaultMetricType = istmp.DefaultMetricType
+		ipSubnet.DelayMetric = istmp.DelayMetric
+		ipSubnet.DelayMetricSupported = istmp.DelayMetricSupported
+		ipSubnet.ExpenseMetric = istmp.ExpenseMetric
+		ipSubnet.ExpenseMetricSupported = istmp.ExpenseMetricSupported
+		ipSubnet.ErrorMetric = istmp.ErrorMetric
+		ipSubnet.ErrorMetricSupported = istmp.ErrorMetricSupported
+		if err := tlv.AddIpSubnet(ipSubnet); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 /*
 	Protocols Supported
 	code - 129
@@ -333,6 +409,38 @@ func (tlv *protocolsSupportedTlv) Serialize() ([]byte, error) {
 	return data, nil
 }
 
+type protocolsSupportedTlvJson struct {
+	tlvJson
+	NlpIds []NlpId `json:"nlp_ids"`
+}
+
+func (tlv *protocolsSupportedTlv) MarshalJSON() ([]byte, error) {
+	j := protocolsSupportedTlvJson{
+		tlvJson: newTlvJson(tlv.base.code),
+		NlpIds:  tlv.ProtocolsSupported(),
+	}
+	return json.Marshal(j)
+}
+
+func (tlv *protocolsSupportedTlv) UnmarshalJSON(data []byte) error {
+	var j protocolsSupportedTlvJson
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if err := j.check(TLV_CODE_PROTOCOLS_SUPPORTED); err != nil {
+		return err
+	}
+	tlv.base.code = TLV_CODE_PROTOCOLS_SUPPORTED
+	tlv.base.length = 0
+	tlv.nlpIds = make([]NlpId, 0)
+	for _, nlpId := range j.NlpIds {
+		if err := tlv.AddNlpId(nlpId); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 /*
 	IP External Reachability Information
 	code - 130
@@ -402,6 +510,15 @@ func NewIpExternalReachInfoTlv() (*ipExternalReachInfoTlv, error) {
 	return &tlv, nil
 }
 
+func (tlv *ipExternalReachInfoTlv) IpSubnets() []*ipExternalReachInfoIpSubnet {
+	subnets := make([]*ipExternalReachInfoIpSubnet, 0)
+	for _, s := range tlv.ipSubnets {
+		subnet := s
+		subnets = append(subnets, &subnet)
+	}
+	return subnets
+}
+
 func (tlv *ipExternalReachInfoTlv) AddIpSubnet(ipSubnet *ipExternalReachInfoIpSubnet) error {
 	length := 0
 	for _, istmp := range tlv.ipSubnets {
@@ -518,6 +635,68 @@ func (tlv *ipExternalReachInfoTlv) Serialize() ([]byte, error) {
 	return data, nil
 }
 
+type ipExternalReachInfoTlvJson struct {
+	tlvJson
+	IpSubnets []ipReachInfoIpSubnetJson `json:"ip_subnets"`
+}
+
+func (tlv *ipExternalReachInfoTlv) MarshalJSON() ([]byte, error) {
+	j := ipExternalReachInfoTlvJson{
+		tlvJson:   newTlvJson(tlv.base.code),
+		IpSubnets: make([]ipReachInfoIpSubnetJson, 0),
+	}
+	for _, istmp := range tlv.ipSubnets {
+		j.IpSubnets = append(j.IpSubnets, ipReachInfoIpSubnetJson{
+			IpAddress:              jsonIpv4(istmp.IpAddress),
+			SubnetMask:             jsonIpv4(istmp.SubnetMask),
+			DefaultMetric:          istmp.DefaultMetric,
+			DefaultMetricType:      istmp.DefaultMetricType,
+			DelayMetric:            istmp.DelayMetric,
+			DelayMetricSupported:   istmp.DelayMetricSupported,
+			ExpenseMetric:          istmp.ExpenseMetric,
+			ExpenseMetricSupported: istmp.ExpenseMetricSupported,
+			ErrorMetric:            istmp.ErrorMetric,
+			ErrorMetricSupported:   istmp.ErrorMetricSupported,
+		})
+	}
+	return json.Marshal(j)
+}
+
+func (tlv *ipExternalReachInfoTlv) UnmarshalJSON(data []byte) error {
+	var j ipExternalReachInfoTlvJson
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if err := j.check(TLV_CODE_IP_EXTERNAL_REACH_INFO); err != nil {
+		return err
+	}
+	tlv.base.code = TLV_CODE_IP_EXTERNAL_REACH_INFO
+	tlv.base.length = 0
+	tlv.ipSubnets = make([]ipExternalReachInfoIpSubnet, 0)
+	for _, istmp := range j.IpSubnets {
+		ipSubnet, _ := NewIpExternalReachInfoIpSubnet()
+		var err error
+		if ipSubnet.IpAddress, err = jsonParseIpv4(istmp.IpAddress); err != nil {
+			return err
+		}
+		if ipSubnet.SubnetMask, err = jsonParseIpv4(istmp.SubnetMask); err != nil {
+			return err
+		}
+		ipSubnet.DefaultMetric = istmp.DefaultMetric
+		ipSubnet.DefaultMetricType = istmp.DefaultMetricType
+		ipSubnet.DelayMetric = istmp.DelayMetric
+		ipSubnet.DelayMetricSupported = istmp.DelayMetricSupported
+		ipSubnet.ExpenseMetric = istmp.ExpenseMetric
+		ipSubnet.ExpenseMetricSupported = istmp.ExpenseMetricSupported
+		ipSubnet.ErrorMetric = istmp.ErrorMetric
+		ipSubnet.ErrorMetricSupported = istmp.ErrorMetricSupported
+		if err := tlv.AddIpSubnet(ipSubnet); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 /*
 	Inter-Domain Routing Protocol Information
 	code - 131
@@ -599,6 +778,41 @@ func (tlv *interDomainRoutingProtoInfoTlv) Serialize() ([]byte, error) {
 	return data, nil
 }
 
+type interDomainRoutingProtoInfoTlvJson struct {
+	tlvJson
+	InterDomainInfoType InterDomainInfoType `json:"inter_domain_info_type"`
+	ExternalInfo        string              `json:"external_info"`
+}
+
+func (tlv *interDomainRoutingProtoInfoTlv) MarshalJSON() ([]byte, error) {
+	j := interDomainRoutingProtoInfoTlvJson{
+		tlvJson:             newTlvJson(tlv.base.code),
+		InterDomainInfoType: tlv.InterDomainInfoType,
+		ExternalInfo:        jsonHex(tlv.ExternalInfo),
+	}
+	return json.Marshal(j)
+}
+
+func (tlv *interDomainRoutingProtoInfoTlv) UnmarshalJSON(data []byte) error {
+	var j interDomainRoutingProtoInfoTlvJson
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if err := j.check(TLV_CODE_INTER_DOMAIN_ROUTING_PROTO_INFO); err != nil {
+		return err
+	}
+	externalInfo, err := jsonParseHex(j.ExternalInfo)
+	if err != nil {
+		return err
+	}
+	if 1+len(externalInfo) > 255 {
+		return errors.New("interDomainRoutingProtoInfoTlv.UnmarshalJSON: external info too long")
+	}
+	tlv.base.code = TLV_CODE_INTER_DOMAIN_ROUTING_PROTO_INFO
+	tlv.InterDomainInfoType = j.InterDomainInfoType
+	return tlv.SetExternalInfo(externalInfo)
+}
+
 /*
 	IP Interface Address
 	code - 132
@@ -644,7 +858,7 @@ func (tlv *ipInterfaceAddressTlv) AddIpAddress(ipAddress uint32) error {
 			return nil
 		}
 	}
-	length := len(tlv.ipAddresses)
+	length := len(tlv.ipAddresses) * 4
 	if length+4 > 255 {
 		return errors.New("ipInterfaceAddressTlv.AddIpAddress: size over")
 	}
@@ -716,6 +930,45 @@ func (tlv *ipInterfaceAddressTlv) Serialize() ([]byte, error) {
 	return data, nil
 }
 
+type ipInterfaceAddressTlvJson struct {
+	tlvJson
+	IpAddresses []string `json:"ip_addresses"`
+}
+
+func (tlv *ipInterfaceAddressTlv) MarshalJSON() ([]byte, error) {
+	j := ipInterfaceAddressTlvJson{
+		tlvJson:     newTlvJson(tlv.base.code),
+		IpAddresses: make([]string, 0),
+	}
+	for _, iatmp := range tlv.ipAddresses {
+		j.IpAddresses = append(j.IpAddresses, jsonIpv4(iatmp))
+	}
+	return json.Marshal(j)
+}
+
+func (tlv *ipInterfaceAddressTlv) UnmarshalJSON(data []byte) error {
+	var j ipInterfaceAddressTlvJson
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if err := j.check(TLV_CODE_IP_INTERFACE_ADDRESS); err != nil {
+		return err
+	}
+	tlv.base.code = TLV_CODE_IP_INTERFACE_ADDRESS
+	tlv.base.length = 0
+	tlv.ipAddresses = make([]uint32, 0)
+	for _, iatmp := range j.IpAddresses {
+		ipAddress, err := jsonParseIpv4(iatmp)
+		if err != nil {
+			return err
+		}
+		if err := tlv.AddIpAddress(ipAddress); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 /*
 	Authentication Information
 	code - 133
@@ -19,6 +19,8 @@ package packet
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 )
 
@@ -48,6 +50,12 @@ func NewDynamicHostnameTlv() (*dynamicHostnameTlv, error) {
 	return &tlv, nil
 }
 
+func (tlv *dynamicHostnameTlv) DynamicHostname() []byte {
+	dynamicHostname := make([]byte, len(tlv.dynamicHostname))
+	copy(dynamicHostname, tlv.dynamicHostname)
+	return dynamicHostname
+}
+
 func (tlv *dynamicHostnameTlv) SetDynamicHostname(dynamicHostname []byte) error {
 	dhtmp := make([]byte, len(dynamicHostname))
 	copy(dhtmp, dynamicHostname)
@@ -93,3 +101,31 @@ func (tlv *dynamicHostnameTlv) Serialize() ([]byte, error) {
 	}
 	return data, nil
 }
+
+type dynamicHostnameTlvJson struct {
+	tlvJson
+	DynamicHostname string `json:"dynamic_hostname"`
+}
+
+func (tlv *dynamicHostnameTlv) MarshalJSON() ([]byte, error) {
+	j := dynamicHostnameTlvJson{
+		tlvJson:         newTlvJson(tlv.base.code),
+		DynamicHostname: string(tlv.dynamicHostname),
+	}
+	return json.Marshal(j)
+}
+
+func (tlv *dynamicHostnameTlv) UnmarshalJSON(data []byte) error {
+	var j dynamicHostnameTlvJson
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if err := j.check(TLV_CODE_DYNAMIC_HOSTNAME); err != nil {
+		return err
+	}
+	if len(j.DynamicHostname) > 255 {
+		return errors.New("dynamicHostnameTlv.UnmarshalJSON: hostname too long")
+	}
+	tlv.base.code = TLV_CODE_DYNAMIC_HOSTNAME
+	return tlv.SetDynamicHostname([]byte(j.DynamicHostname))
+}
@@ -20,6 +20,7 @@ package packet
 import (
 	"bytes"
 	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"fmt"
 )
@@ -135,3 +136,47 @@ func (tlv *p2p3wayAdjacencyTlv) Serialize() ([]byte, error) {
 	}
 	return data, nil
 }
+
+type p2p3wayAdjacencyTlvJson struct {
+	tlvJson
+	Adj3wayState           Adj3wayState `json:"adj_3way_state"`
+	ExtLocalCircuitId      uint32       `json:"ext_local_circuit_id"`
+	NeighbourSystemId      string       `json:"neighbour_system_id,omitempty"`
+	NeighExtLocalCircuitId uint32       `json:"neigh_ext_local_circuit_id"`
+}
+
+func (tlv *p2p3wayAdjacencyTlv) MarshalJSON() ([]byte, error) {
+	j := p2p3wayAdjacencyTlvJson{
+		tlvJson:                newTlvJson(tlv.base.code),
+		Adj3wayState:           tlv.Adj3wayState,
+		ExtLocalCircuitId:      tlv.ExtLocalCircuitId,
+		NeighExtLocalCircuitId: tlv.NeighExtLocalCircuitId,
+	}
+	if tlv.Adj3wayState != ADJ_3WAY_STATE_DOWN {
+		j.NeighbourSystemId = jsonSystemId(tlv.neighbourSystemId)
+	}
+	return json.Marshal(j)
+}
+
+func (tlv *p2p3wayAdjacencyTlv) UnmarshalJSON(data []byte) error {
+	var j p2p3wayAdjacencyTlvJson
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if err := j.check(TLV_CODE_P2P_3WAY_ADJ); err != nil {
+		return err
+	}
+	tlv.base.code = TLV_CODE_P2P_3WAY_ADJ
+	tlv.base.length = 5
+	tlv.Adj3wayState = j.Adj3wayState
+	tlv.ExtLocalCircuitId = j.ExtLocalCircuitId
+	tlv.NeighExtLocalCircuitId = j.NeighExtLocalCircuitId
+	if j.NeighbourSystemId != "" {
+		neighbourSystemId, err := jsonParseSystemId(j.NeighbourSystemId)
+		if err != nil {
+			return err
+		}
+		return tlv.SetNeighbourSystemId(neighbourSystemId)
+	}
+	return nil
+}
@@ -20,6 +20,7 @@ package packet
 import (
 	"bytes"
 	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
@@ -93,8 +94,8 @@ func (neighbour *extendedIsReachabilityNeighbour) SetLengthOfSubtlvs() {
 	if neighbour.adminGroupSubTlv != nil {
 		length += 2 + 4
 	}
-	length += 2 + 4*len(neighbour.ipv4InterfaceAddressSubtlvs)
-	length += 2 + 4*len(neighbour.ipv4NeighbourAddressSubtlvs)
+	length += (2 + 4) * len(neighbour.ipv4InterfaceAddressSubtlvs)
+	length += (2 + 4) * len(neighbour.ipv4NeighbourAddressSubtlvs)
 	if neighbour.maximumLinkBandwidthSubTlv != nil {
 		length += 2 + 4
 	}
@@ -125,6 +126,12 @@ func (neighbour *extendedIsReachabilityNeighbour) AdminGroupSubTlv() *uint32 {
 	return neighbour.adminGroupSubTlv
 }
 
+func (neighbour *extendedIsReachabilityNeighbour) Ipv4InterfaceAddressSubTlvs() []uint32 {
+	ipv4InterfaceAddressSubtlvs := make([]uint32, len(neighbour.ipv4InterfaceAddressSubtlvs))
+	copy(ipv4InterfaceAddressSubtlvs, neighbour.ipv4InterfaceAddressSubtlvs)
+	return ipv4InterfaceAddressSubtlvs
+}
+
 func (neighbour *extendedIsReachabilityNeighbour) AddIpv4InterfaceAddressSubTlv(ipv4IfAddrSubTlv uint32) {
 	for _, ifatmp := range neighbour.ipv4InterfaceAddressSubtlvs {
 		if ipv4IfAddrSubTlv == ifatmp {
@@ -152,6 +159,12 @@ func (neighbour *extendedIsReachabilityNeighbour) RemoveIpv4InterfaceAddressSubT
 	}
 }
 
+func (neighbour *extendedIsReachabilityNeighbour) Ipv4NeighbourAddressSubTlvs() []uint32 {
+	ipv4NeighbourAddressSubtlvs := make([]uint32, len(neighbour.ipv4NeighbourAddressSubtlvs))
+	copy(ipv4NeighbourAddressSubtlvs, neighbour.ipv4NeighbourAddressSubtlvs)
+	return ipv4NeighbourAddressSubtlvs
+}
+
 func (neighbour *extendedIsReachabilityNeighbour) AddIpv4NeighbourAddressSubTlv(ipv4NeighAddrSubTlv uint32) {
 	for _, natmp := range neighbour.ipv4NeighbourAddressSubtlvs {
 		if ipv4NeighAddrSubTlv == natmp {
@@ -456,7 +469,10 @@ func (tlv *extendedIsReachabilityTlv) DecodeFromBytes(data []byte) error {
 					errstr += "unknownSubtlvs size invalid"
 					return errors.New(errstr)
 				}
-				neigh.unknownSubtlvs = append(neigh.unknownSubtlvs, subTlvValue)
+				// kept with its type and length as Serialize writes it back as is
+				subTlv := make([]byte, 2+subTlvLength)
+				copy(subTlv, tlv.base.value[i+11+j:i+11+j+2+subTlvLength])
+				neigh.unknownSubtlvs = append(neigh.unknownSubtlvs, subTlv)
 			}
 			j += 2 + subTlvLength
 		}
@@ -551,6 +567,101 @@ func (tlv *extendedIsReachabilityTlv) Serialize() ([]byte, error) {
 	return data, nil
 }
 
+type extendedIsReachabilityNeighbourJson struct {
+	NeighbourId                     string      `json:"neighbour_id"`
+	DefaultMetric                   uint32      `json:"default_metric"`
+	AdminGroup                      *uint32     `json:"admin_group,omitempty"`
+	Ipv4InterfaceAddresses          []string    `json:"ipv4_interface_addresses"`
+	Ipv4NeighbourAddresses          []string    `json:"ipv4_neighbour_addresses"`
+	MaximumLinkBandwidth            *float32    `json:"maximum_link_bandwidth,omitempty"`
+	MaximumReservableLinkBandwidth  *float32    `json:"maximum_reservable_link_bandwidth,omitempty"`
+	UnreservedBandwidth             *[8]float32 `json:"unreserved_bandwidth,omitempty"`
+	TrafficEngineeringDefaultMetric *uint32     `json:"traffic_engineering_default_metric,omitempty"`
+	UnknownSubtlvs                  []string    `json:"unknown_subtlvs"`
+}
+
+type extendedIsReachabilityTlvJson struct {
+	tlvJson
+	Neighbours []extendedIsReachabilityNeighbourJson `json:"neighbours"`
+}
+
+func (tlv *extendedIsReachabilityTlv) MarshalJSON() ([]byte, error) {
+	j := extendedIsReachabilityTlvJson{
+		tlvJson:    newTlvJson(tlv.base.code),
+		Neighbours: make([]extendedIsReachabilityNeighbourJson, 0),
+	}
+	for _, ntmp := range tlv.neighbours {
+		neighbour := extendedIsReachabilityNeighbourJson{
+			NeighbourId:                     jsonNeighbourId(ntmp.neighbourId),
+			DefaultMetric:                   ntmp.DefaultMetric,
+			AdminGroup:                      ntmp.adminGroupSubTlv,
+			Ipv4InterfaceAddresses:          make([]string, 0),
+			Ipv4NeighbourAddresses:          make([]string, 0),
+			MaximumLinkBandwidth:            ntmp.maximumLinkBandwidthSubTlv,
+			MaximumReservableLinkBandwidth:  ntmp.maximumReservableLinkBandwidthSubTlv,
+			UnreservedBandwidth:             ntmp.unreservedBandwidthSubTlv,
+			TrafficEngineeringDefaultMetric: ntmp.trafficEngineeringDefaultMetric,
+			UnknownSubtlvs:                  jsonHexList(ntmp.unknownSubtlvs),
+		}
+		for _, iatmp := range ntmp.ipv4InterfaceAddressSubtlvs {
+			neighbour.Ipv4InterfaceAddresses = append(neighbour.Ipv4InterfaceAddresses, jsonIpv4(iatmp))
+		}
+		for _, natmp := range ntmp.ipv4NeighbourAddressSubtlvs {
+			neighbour.Ipv4NeighbourAddresses = append(neighbour.Ipv4NeighbourAddresses, jsonIpv4(natmp))
+		}
+		j.Neighbours = append(j.Neighbours, neighbour)
+	}
+	return json.Marshal(j)
+}
+
+func (tlv *extendedIsReachabilityTlv) UnmarshalJSON(data []byte) error {
+	var j extendedIsReachabilityTlvJson
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if err := j.check(TLV_CODE_EXTENDED_IS_REACHABILITY); err != nil {
+		return err
+	}
+	tlv.base.code = TLV_CODE_EXTENDED_IS_REACHABILITY
+	tlv.base.length = 0
+	tlv.neighbours = make([]extendedIsReachabilityNeighbour, 0)
+	for _, ntmp := range j.Neighbours {
+		neighbourId, err := jsonParseNeighbourId(ntmp.NeighbourId)
+		if err != nil {
+			return err
+		}
+		neighbour, _ := NewExtendedIsReachabilityNeighbour(neighbourId)
+		neighbour.DefaultMetric = ntmp.DefaultMetric & 0x00ffffff
+		neighbour.adminGroupSubTlv = ntmp.AdminGroup
+		for _, iatmp := range ntmp.Ipv4InterfaceAddresses {
+			ipv4Address, err := jsonParseIpv4(iatmp)
+			if err != nil {
+				return err
+			}
+			neighbour.ipv4InterfaceAddressSubtlvs = append(neighbour.ipv4InterfaceAddressSubtlvs, ipv4Address)
+		}
+		for _, natmp := range ntmp.Ipv4NeighbourAddresses {
+			ipv4Address, err := jsonParseIpv4(natmp)
+			if err != nil {
+				return err
+			}
+			neighbour.ipv4NeighbourAddressSubtlvs = append(neighbour.ipv4NeighbourAddressSubtlvs, ipv4Address)
+		}
+		neighbour.maximumLinkBandwidthSubTlv = ntmp.MaximumLinkBandwidth
+		neighbour.maximumReservableLinkBandwidthSubTlv = ntmp.MaximumReservableLinkBandwidth
+		neighbour.unreservedBandwidthSubTlv = ntmp.UnreservedBandwidth
+		neighbour.trafficEngineeringDefaultMetric = ntmp.TrafficEngineeringDefaultMetric
+		if neighbour.unknownSubtlvs, err = jsonParseHexList(ntmp.UnknownSubtlvs); err != nil {
+			return err
+		}
+		neighbour.SetLengthOfSubtlvs()
+		if err := tlv.AddNeighbour(neighbour); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 /*
 	Traffic Engineering Router ID
 	code - 134
@@ -611,6 +722,37 @@ func (tlv *trafficEngineeringRouterIdTlv) Serialize() ([]byte, error) {
 	return data, nil
 }
 
+type trafficEngineeringRouterIdTlvJson struct {
+	tlvJson
+	RouterId string `json:"router_id"`
+}
+
+func (tlv *trafficEngineeringRouterIdTlv) MarshalJSON() ([]byte, error) {
+	j := trafficEngineeringRouterIdTlvJson{
+		tlvJson:  newTlvJson(tlv.base.code),
+		RouterId: jsonIpv4(tlv.RouterId),
+	}
+	return json.Marshal(j)
+}
+
+func (tlv *trafficEngineeringRouterIdTlv) UnmarshalJSON(data []byte) error {
+	var j trafficEngineeringRouterIdTlvJson
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if err := j.check(TLV_CODE_TRAFFIC_ENGINEERING_ROUTER_ID); err != nil {
+		return err
+	}
+	routerId, err := jsonParseIpv4(j.RouterId)
+	if err != nil {
+		return err
+	}
+	tlv.base.code = TLV_CODE_TRAFFIC_ENGINEERING_ROUTER_ID
+	tlv.base.length = 4
+	tlv.RouterId = routerId
+	return nil
+}
+
 /*
 	Extended IP Reachability
 	code - 135
@@ -657,6 +799,29 @@ func (ipv4Prefix *extendedIpReachabilityIpv4Prefix) PrefixLength() uint8 {
 	return ipv4Prefix.prefixLength
 }
 
+// Tags returns the 32-bit administrative tags of the sub-TLV 1 of RFC 5130.
+func (ipv4Prefix *extendedIpReachabilityIpv4Prefix) Tags() []uint32 {
+	return subtlvsTags(ipv4Prefix.unknownSubtlvs)
+}
+
+// SetTags replaces the 32-bit administrative tags of the sub-TLV 1 of
+// RFC 5130 and updates SubtlvsPresence.
+func (ipv4Prefix *extendedIpReachabilityIpv4Prefix) SetTags(tags []uint32) {
+	ipv4Prefix.unknownSubtlvs = subtlvsSetTags(ipv4Prefix.unknownSubtlvs, tags)
+	ipv4Prefix.SubtlvsPresence = len(ipv4Prefix.unknownSubtlvs) > 0
+}
+
+func (ipv4Prefix *extendedIpReachabilityIpv4Prefix) length() int {
+	length := 5 + (int(ipv4Prefix.prefixLength)+7)/8
+	if ipv4Prefix.SubtlvsPresence {
+		length += 1
+		for _, stlv := range ipv4Prefix.unknownSubtlvs {
+			length += len(stlv)
+		}
+	}
+	return length
+}
+
 type extendedIpReachabilityTlv struct {
 	base         tlvBase
 	ipv4Prefixes []extendedIpReachabilityIpv4Prefix
@@ -676,11 +841,7 @@ func NewExtendedIpReachabilityTlv() (*extendedIpReachabilityTlv, error) {
 func (tlv *extendedIpReachabilityTlv) SetLength() {
 	length := 0
 	for _, ptmp := range tlv.ipv4Prefixes {
-		pocts := (int(ptmp.prefixLength) + 7) / 8
-		length += 5 + pocts
-		for _, tlvtmp := range ptmp.unknownSubtlvs {
-			length += len(tlvtmp)
-		}
+		length += ptmp.length()
 	}
 	tlv.base.length = uint8(length)
 }
@@ -703,13 +864,11 @@ func (tlv *extendedIpReachabilityTlv) AddIpv4Prefix(ipv4Prefix *extendedIpReacha
 	ipv4Prefixes := make([]extendedIpReachabilityIpv4Prefix, 0)
 	for _, ptmp := range tlv.ipv4Prefixes {
 		if ipv4Prefix.ipv4Prefix != ptmp.ipv4Prefix || ipv4Prefix.prefixLength != ptmp.prefixLength {
-			pocts := (int(ptmp.prefixLength) + 7) / 8
-			length += 5 + pocts
+			length += ptmp.length()
 			ipv4Prefixes = append(ipv4Prefixes, ptmp)
 		}
 	}
-	pocts := (int(ipv4Prefix.prefixLength) + 7) / 8
-	if length+5+pocts > 255 {
+	if length+ipv4Prefix.length() > 255 {
 		return errors.New("extendedIpReachabilityTlv.AddIpv4Prefix: tlv size over")
 	}
 	ipv4Prefixes = append(ipv4Prefixes, *ipv4Prefix)
@@ -725,8 +884,7 @@ func (tlv *extendedIpReachabilityTlv) RemoveIpv4Prefix(ipv4Prefix uint32, prefix
 		if ipv4Prefix == ptmp.ipv4Prefix && prefixLength == ptmp.prefixLength {
 			ptmp.extendedIpReachabilityTlv = nil
 		} else {
-			pocts := (int(ptmp.prefixLength) + 7) / 8
-			length += 5 + pocts
+			length += ptmp.length()
 			ipv4Prefixes = append(ipv4Prefixes, ptmp)
 		}
 	}
@@ -788,20 +946,19 @@ func (tlv *extendedIpReachabilityTlv) DecodeFromBytes(data []byte) error {
 		ipv4Prefix.SubtlvsPresence = ((tlv.base.value[i+4] & 0x40) == 0x40)
 		j := 0
 		if ipv4Prefix.SubtlvsPresence {
-			for i+5+pocts+j < len(tlv.base.value) {
-				if i+5+pocts+j+2 > len(tlv.base.value) {
-					return errors.New("extendedIpReachabilityTlv.DecodeFromBytes: size invalid")
-				}
-
-				stlvl := int(tlv.base.value[i+5+pocts+j+2])
-				if i+5+pocts+j+2+stlvl > len(tlv.base.value) {
-					return errors.New("extendedIpReachabilityTlv.DecodeFromBytes: size invalid")
-				}
-				stlv := make([]byte, 2+stlvl)
-				copy(stlv, tlv.base.value[i+5+pocts+j:i+5+pocts+j+2+stlvl])
-				ipv4Prefix.unknownSubtlvs = append(ipv4Prefix.unknownSubtlvs, stlv)
-				j += 2 + stlvl
+			if i+5+pocts+1 > len(tlv.base.value) {
+				return errors.New("extendedIpReachabilityTlv.DecodeFromBytes: size invalid")
+			}
+			stlvsl := int(tlv.base.value[i+5+pocts])
+			if i+5+pocts+1+stlvsl > len(tlv.base.value) {
+				return errors.New("extendedIpReachabilityTlv.DecodeFromBytes: size invalid")
 			}
+			stlvs, err := decodeSubtlvs(tlv.base.value[i+5+pocts+1 : i+5+pocts+1+stlvsl])
+			if err != nil {
+				return err
+			}
+			ipv4Prefix.unknownSubtlvs = stlvs
+			j = 1 + stlvsl
 		}
 		ipv4Prefixes = append(ipv4Prefixes, *ipv4Prefix)
 		i += 5 + pocts + j
@@ -831,9 +988,13 @@ func (tlv *extendedIpReachabilityTlv) Serialize() ([]byte, error) {
 		pocts := (int(ptmp.prefixLength) + 7) / 8
 		copy(value[i+5:i+5+pocts], ip4p[0:pocts])
 		j := 0
-		for _, ukstlv := range ptmp.unknownSubtlvs {
-			copy(value[i+5+pocts+j:], ukstlv)
-			j += len(ukstlv)
+		if ptmp.SubtlvsPresence {
+			j = 1
+			for _, ukstlv := range ptmp.unknownSubtlvs {
+				copy(value[i+5+pocts+j:], ukstlv)
+				j += len(ukstlv)
+			}
+			value[i+5+pocts] = uint8(j - 1)
 		}
 		i += 5 + pocts + j
 	}
@@ -848,3 +1009,71 @@ func (tlv *extendedIpReachabilityTlv) Serialize() ([]byte, error) {
 	}
 	return data, nil
 }
+
+type extendedIpReachabilityIpv4PrefixJson struct {
+	Ipv4Prefix        string   `json:"ipv4_prefix"`
+	PrefixLength      uint8    `json:"prefix_length"`
+	MetricInformation uint32   `json:"metric_information"`
+	UpDownBit         bool     `json:"up_down_bit"`
+	SubtlvsPresence   bool     `json:"subtlvs_presence"`
+	UnknownSubtlvs    []string `json:"unknown_subtlvs"`
+}
+
+type extendedIpReachabilityTlvJson struct {
+	tlvJson
+	Ipv4Prefixes []extendedIpReachabilityIpv4PrefixJson `json:"ipv4_prefixes"`
+}
+
+func (tlv *extendedIpReachabilityTlv) MarshalJSON() ([]byte, error) {
+	j := extendedIpReachabilityTlvJson{
+		tlvJson:      newTlvJson(tlv.base.code),
+		Ipv4Prefixes: make([]extendedIpReachabilityIpv4PrefixJson, 0),
+	}
+	for _, ptmp := range tlv.ipv4Prefixes {
+		j.Ipv4Prefixes = append(j.Ipv4Prefixes, extendedIpReachabilityIpv4PrefixJson{
+			Ipv4Prefix:        jsonIpv4(ptmp.ipv4Prefix),
+			PrefixLength:      ptmp.prefixLength,
+			MetricInformation: ptmp.MetricInformation,
+			UpDownBit:         ptmp.UpDownBit,
+			SubtlvsPresence:   ptmp.SubtlvsPresence,
+			UnknownSubtlvs:    jsonHexList(ptmp.unknownSubtlvs),
+		})
+	}
+	return json.Marshal(j)
+}
+
+func (tlv *extendedIpReachabilityTlv) UnmarshalJSON(data []byte) error {
+	var j extendedIpReachabilityTlvJson
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if err := j.check(TLV_CODE_EXTENDED_IP_REACHABILITY); err != nil {
+		return err
+	}
+	tlv.base.code = TLV_CODE_EXTENDED_IP_REACHABILITY
+	tlv.ipv4Prefixes = make([]extendedIpReachabilityIpv4Prefix, 0)
+	length := 0
+	for _, ptmp := range j.Ipv4Prefixes {
+		ipv4Prefix, err := jsonParseIpv4(ptmp.Ipv4Prefix)
+		if err != nil {
+			return err
+		}
+		if ptmp.PrefixLength > 32 {
+			return errors.New("extendedIpReachabilityTlv.UnmarshalJSON: prefix length invalid")
+		}
+		prefix, _ := NewExtendedIpReachabilityIpv4Prefix(ipv4Prefix, ptmp.PrefixLength)
+		prefix.MetricInformation = ptmp.MetricInformation
+		prefix.UpDownBit = ptmp.UpDownBit
+		prefix.SubtlvsPresence = ptmp.SubtlvsPresence
+		if prefix.unknownSubtlvs, err = jsonParseHexList(ptmp.UnknownSubtlvs); err != nil {
+			return err
+		}
+		length += prefix.length()
+		if length > 255 {
+			return errors.New("extendedIpReachabilityTlv.UnmarshalJSON: tlv size over")
+		}
+		tlv.ipv4Prefixes = append(tlv.ipv4Prefixes, *prefix)
+	}
+	tlv.SetLength()
+	return nil
+}
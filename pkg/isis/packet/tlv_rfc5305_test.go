@@ -99,3 +99,65 @@ func TestExtendedIpReachabilityTlv(t *testing.T) {
 		t.Fatalf("failed !Equal")
 	}
 }
+
+func TestExtendedIpReachabilityTlvTags(t *testing.T) {
+	var err error
+	p1 := []byte{0x87, 0x19,
+		0x00, 0x00, 0x00, 0x0a, 0x58, 0xc0, 0xa8, 0x01, 0x08, 0x01, 0x04, 0x00, 0x00, 0x00, 0x64, 0x80, 0x00,
+		0x00, 0x00, 0x00, 0x0a, 0x18, 0xc0, 0xa8, 0x02,
+	}
+
+	t1, err := NewExtendedIpReachabilityTlv()
+	if err != nil {
+		t.Fatalf("failed NewExtendedIpReachabilityTlv: %#v", err)
+	}
+
+	err = t1.DecodeFromBytes(p1)
+	if err != nil {
+		t.Fatalf("failed DecodeFromBytes: %#v", err)
+	}
+
+	prefixes := t1.Ipv4Prefixes()
+	if tags := prefixes[0].Tags(); len(tags) != 1 || tags[0] != 100 {
+		t.Fatalf("failed Tags: %v", tags)
+	}
+	if tags := prefixes[1].Tags(); len(tags) != 0 {
+		t.Fatalf("failed Tags: %v", tags)
+	}
+
+	p2, err := t1.Serialize()
+	if err != nil {
+		t.Fatalf("failed Serialize: %#v", err)
+	}
+
+	if !bytes.Equal(p1, p2) {
+		t.Fatalf("failed !Equal")
+	}
+
+	t2, err := NewExtendedIpReachabilityTlv()
+	if err != nil {
+		t.Fatalf("failed NewExtendedIpReachabilityTlv: %#v", err)
+	}
+	prefix, _ := NewExtendedIpReachabilityIpv4Prefix(0xc0a80200, 24)
+	prefix.MetricInformation = 10
+	prefix.SetTags([]uint32{100, 200})
+	if err = t2.AddIpv4Prefix(prefix); err != nil {
+		t.Fatalf("failed AddIpv4Prefix: %#v", err)
+	}
+
+	p3, err := t2.Serialize()
+	if err != nil {
+		t.Fatalf("failed Serialize: %#v", err)
+	}
+
+	t3, err := NewExtendedIpReachabilityTlv()
+	if err != nil {
+		t.Fatalf("failed NewExtendedIpReachabilityTlv: %#v", err)
+	}
+	if err = t3.DecodeFromBytes(p3); err != nil {
+		t.Fatalf("failed DecodeFromBytes: %#v", err)
+	}
+	if tags := t3.Ipv4Prefixes()[0].Tags(); len(tags) != 2 || tags[0] != 100 || tags[1] != 200 {
+		t.Fatalf("failed Tags: %v", tags)
+	}
+}
@@ -20,6 +20,7 @@ package packet
 import (
 	"bytes"
 	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"fmt"
 )
@@ -72,6 +73,29 @@ func (ipv6Prefix *ipv6ReachabilityIpv6Prefix) PrefixLength() uint8 {
 	return ipv6Prefix.prefixLength
 }
 
+// Tags returns the 32-bit administrative tags of the sub-TLV 1 of RFC 5130.
+func (ipv6Prefix *ipv6ReachabilityIpv6Prefix) Tags() []uint32 {
+	return subtlvsTags(ipv6Prefix.unknownSubtlvs)
+}
+
+// SetTags replaces the 32-bit administrative tags of the sub-TLV 1 of
+// RFC 5130 and updates SubtlvsPresence.
+func (ipv6Prefix *ipv6ReachabilityIpv6Prefix) SetTags(tags []uint32) {
+	ipv6Prefix.unknownSubtlvs = subtlvsSetTags(ipv6Prefix.unknownSubtlvs, tags)
+	ipv6Prefix.SubtlvsPresence = len(ipv6Prefix.unknownSubtlvs) > 0
+}
+
+func (ipv6Prefix *ipv6ReachabilityIpv6Prefix) length() int {
+	length := 6 + (int(ipv6Prefix.prefixLength)+7)/8
+	if ipv6Prefix.SubtlvsPresence {
+		length += 1
+		for _, stlv := range ipv6Prefix.unknownSubtlvs {
+			length += len(stlv)
+		}
+	}
+	return length
+}
+
 type ipv6ReachabilityTlv struct {
 	base         tlvBase
 	ipv6Prefixes []ipv6ReachabilityIpv6Prefix
@@ -91,11 +115,7 @@ func NewIpv6ReachabilityTlv() (*ipv6ReachabilityTlv, error) {
 func (tlv *ipv6ReachabilityTlv) SetLength() {
 	length := 0
 	for _, ptmp := range tlv.ipv6Prefixes {
-		pocts := (int(ptmp.prefixLength) + 7) / 8
-		length += 6 + pocts
-		for _, tlvtmp := range ptmp.unknownSubtlvs {
-			length += len(tlvtmp)
-		}
+		length += ptmp.length()
 	}
 	tlv.base.length = uint8(length)
 }
@@ -121,13 +141,11 @@ func (tlv *ipv6ReachabilityTlv) AddIpv6Prefix(ipv6Prefix *ipv6ReachabilityIpv6Pr
 			ipv6Prefix.ipv6Prefix[2] != ptmp.ipv6Prefix[2] ||
 			ipv6Prefix.ipv6Prefix[3] != ptmp.ipv6Prefix[3] ||
 			ipv6Prefix.prefixLength != ptmp.prefixLength {
-			pocts := (int(ptmp.prefixLength) + 7) / 8
-			length += 5 + pocts
+			length += ptmp.length()
 			ipv6Prefixes = append(ipv6Prefixes, ptmp)
 		}
 	}
-	pocts := (int(ipv6Prefix.prefixLength) + 7) / 8
-	if length+6+pocts > 255 {
+	if length+ipv6Prefix.length() > 255 {
 		return errors.New("ipv6ReachabilityTlv.AddIpv6Prefix: tlv size over")
 	}
 	ipv6Prefixes = append(ipv6Prefixes, *ipv6Prefix)
@@ -147,8 +165,7 @@ func (tlv *ipv6ReachabilityTlv) RemoveIpv6Prefix(ipv6Prefix [4]uint32, prefixLen
 			prefixLength == ptmp.prefixLength {
 			ptmp.ipv6ReachabilityTlv = nil
 		} else {
-			pocts := (int(ptmp.prefixLength) + 7) / 8
-			length += 6 + pocts
+			length += ptmp.length()
 			ipv6Prefixes = append(ipv6Prefixes, ptmp)
 		}
 	}
@@ -222,19 +239,19 @@ func (tlv *ipv6ReachabilityTlv) DecodeFromBytes(data []byte) error {
 		ipv6Prefix.SubtlvsPresence = ((tlv.base.value[i+4] & 0x20) == 0x20)
 		j := 0
 		if ipv6Prefix.SubtlvsPresence {
-			for i+6+pocts+j < len(tlv.base.value) {
-				if i+6+pocts+j+2 > len(tlv.base.value) {
-					return errors.New("ipv6ReachabilityTlv.DecodeFromBytes: size invalid")
-				}
-				stlvl := int(tlv.base.value[i+6+pocts+j+2])
-				if i+6+pocts+j+2+stlvl > len(tlv.base.value) {
-					return errors.New("ipv6ReachabilityTlv.DecodeFromBytes: size invalid")
-				}
-				stlv := make([]byte, 2+stlvl)
-				copy(stlv, tlv.base.value[i+6+pocts+j:i+6+pocts+j+2+stlvl])
-				ipv6Prefix.unknownSubtlvs = append(ipv6Prefix.unknownSubtlvs, stlv)
-				j += 2 + stlvl
+			if i+6+pocts+1 > len(tlv.base.value) {
+				return errors.New("ipv6ReachabilityTlv.DecodeFromBytes: size invalid")
+			}
+			stlvsl := int(tlv.base.value[i+6+pocts])
+			if i+6+pocts+1+stlvsl > len(tlv.base.value) {
+				return errors.New("ipv6ReachabilityTlv.DecodeFromBytes: size invalid")
 			}
+			stlvs, err := decodeSubtlvs(tlv.base.value[i+6+pocts+1 : i+6+pocts+1+stlvsl])
+			if err != nil {
+				return err
+			}
+			ipv6Prefix.unknownSubtlvs = stlvs
+			j = 1 + stlvsl
 		}
 		ipv6Prefixes = append(ipv6Prefixes, *ipv6Prefix)
 		i += 6 + pocts + j
@@ -270,9 +287,13 @@ func (tlv *ipv6ReachabilityTlv) Serialize() ([]byte, error) {
 		pocts := (int(ptmp.prefixLength) + 7) / 8
 		copy(value[i+6:i+6+pocts], ip6p[0:pocts])
 		j := 0
-		for _, ukstlv := range ptmp.unknownSubtlvs {
-			copy(value[i+6+pocts+j:], ukstlv)
-			j += len(ukstlv)
+		if ptmp.SubtlvsPresence {
+			j = 1
+			for _, ukstlv := range ptmp.unknownSubtlvs {
+				copy(value[i+6+pocts+j:], ukstlv)
+				j += len(ukstlv)
+			}
+			value[i+6+pocts] = uint8(j - 1)
 		}
 		i += 6 + pocts + j
 	}
@@ -288,6 +309,77 @@ func (tlv *ipv6ReachabilityTlv) Serialize() ([]byte, error) {
 	return data, nil
 }
 
+type ipv6ReachabilityIpv6PrefixJson struct {
+	Ipv6Prefix          string   `json:"ipv6_prefix"`
+	PrefixLength        uint8    `json:"prefix_length"`
+	Metric              uint32   `json:"metric"`
+	UpDownBit           bool     `json:"up_down_bit"`
+	ExternalOriginalBit bool     `json:"external_original_bit"`
+	SubtlvsPresence     bool     `json:"subtlvs_presence"`
+	UnknownSubtlvs      []string `json:"unknown_subtlvs"`
+}
+
+type ipv6ReachabilityTlvJson struct {
+	tlvJson
+	Ipv6Prefixes []ipv6ReachabilityIpv6PrefixJson `json:"ipv6_prefixes"`
+}
+
+func (tlv *ipv6ReachabilityTlv) MarshalJSON() ([]byte, error) {
+	j := ipv6ReachabilityTlvJson{
+		tlvJson:      newTlvJson(tlv.base.code),
+		Ipv6Prefixes: make([]ipv6ReachabilityIpv6PrefixJson, 0),
+	}
+	for _, ptmp := range tlv.ipv6Prefixes {
+		j.Ipv6Prefixes = append(j.Ipv6Prefixes, ipv6ReachabilityIpv6PrefixJson{
+			Ipv6Prefix:          jsonIpv6(ptmp.ipv6Prefix),
+			PrefixLength:        ptmp.prefixLength,
+			Metric:              ptmp.Metric,
+			UpDownBit:           ptmp.UpDownBit,
+			ExternalOriginalBit: ptmp.ExternalOriginalBit,
+			SubtlvsPresence:     ptmp.SubtlvsPresence,
+			UnknownSubtlvs:      jsonHexList(ptmp.unknownSubtlvs),
+		})
+	}
+	return json.Marshal(j)
+}
+
+func (tlv *ipv6ReachabilityTlv) UnmarshalJSON(data []byte) error {
+	var j ipv6ReachabilityTlvJson
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if err := j.check(TLV_CODE_IPV6_REACHABILITY); err != nil {
+		return err
+	}
+	tlv.base.code = TLV_CODE_IPV6_REACHABILITY
+	tlv.ipv6Prefixes = make([]ipv6ReachabilityIpv6Prefix, 0)
+	length := 0
+	for _, ptmp := range j.Ipv6Prefixes {
+		ipv6Prefix, err := jsonParseIpv6(ptmp.Ipv6Prefix)
+		if err != nil {
+			return err
+		}
+		if ptmp.PrefixLength > 128 {
+			return errors.New("ipv6ReachabilityTlv.UnmarshalJSON: prefix length invalid")
+		}
+		prefix, _ := NewIpv6ReachabilityIpv6Prefix(ipv6Prefix, ptmp.PrefixLength)
+		prefix.Metric = ptmp.Metric
+		prefix.UpDownBit = ptmp.UpDownBit
+		prefix.ExternalOriginalBit = ptmp.ExternalOriginalBit
+		prefix.SubtlvsPresence = ptmp.SubtlvsPresence
+		if prefix.unknownSubtlvs, err = jsonParseHexList(ptmp.UnknownSubtlvs); err != nil {
+			return err
+		}
+		length += prefix.length()
+		if length > 255 {
+			return errors.New("ipv6ReachabilityTlv.UnmarshalJSON: tlv size over")
+		}
+		tlv.ipv6Prefixes = append(tlv.ipv6Prefixes, *prefix)
+	}
+	tlv.SetLength()
+	return nil
+}
+
 /*
 	IPv6 Interface Address
 	code - 232
@@ -337,7 +429,7 @@ func (tlv *ipv6InterfaceAddressTlv) AddIpv6Address(ipv6Address [4]uint32) error
 			return nil
 		}
 	}
-	length := len(tlv.ipv6Addresses)
+	length := len(tlv.ipv6Addresses) * 16
 	if length+16 > 255 {
 		return errors.New("ipv6InterfaceAddressTlv.AddIpv6Address: size over")
 	}
@@ -358,7 +450,7 @@ func (tlv *ipv6InterfaceAddressTlv) RemoveIpv6Address(ipv6Address [4]uint32) err
 		}
 	}
 	tlv.ipv6Addresses = ipv6Addresses
-	tlv.base.length = uint8(len(tlv.ipv6Addresses) * 4)
+	tlv.base.length = uint8(len(tlv.ipv6Addresses) * 16)
 	return nil
 }
 
@@ -426,3 +518,42 @@ func (tlv *ipv6InterfaceAddressTlv) Serialize() ([]byte, error) {
 	}
 	return data, nil
 }
+
+type ipv6InterfaceAddressTlvJson struct {
+	tlvJson
+	Ipv6Addresses []string `json:"ipv6_addresses"`
+}
+
+func (tlv *ipv6InterfaceAddressTlv) MarshalJSON() ([]byte, error) {
+	j := ipv6InterfaceAddressTlvJson{
+		tlvJson:       newTlvJson(tlv.base.code),
+		Ipv6Addresses: make([]string, 0),
+	}
+	for _, iatmp := range tlv.ipv6Addresses {
+		j.Ipv6Addresses = append(j.Ipv6Addresses, jsonIpv6(iatmp))
+	}
+	return json.Marshal(j)
+}
+
+func (tlv *ipv6InterfaceAddressTlv) UnmarshalJSON(data []byte) error {
+	var j ipv6InterfaceAddressTlvJson
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if err := j.check(TLV_CODE_IPV6_INTERFACE_ADDRESS); err != nil {
+		return err
+	}
+	tlv.base.code = TLV_CODE_IPV6_INTERFACE_ADDRESS
+	tlv.base.length = 0
+	tlv.ipv6Addresses = make([][4]uint32, 0)
+	for _, iatmp := range j.Ipv6Addresses {
+		ipv6Address, err := jsonParseIpv6(iatmp)
+		if err != nil {
+			return err
+		}
+		if err := tlv.AddIpv6Address(ipv6Address); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -49,6 +49,42 @@ func TestIpv6ReachabilityTlv(t *testing.T) {
 	}
 }
 
+func TestIpv6ReachabilityTlvTags(t *testing.T) {
+	var err error
+	p1 := []byte{0xec, 0x1f,
+		0x00, 0x00, 0x00, 0x0a, 0xa0, 0x40, 0x20, 0x01, 0x0d, 0xb8, 0x00, 0x00, 0x00, 0x01,
+		0x08, 0x01, 0x04, 0x00, 0x00, 0x00, 0x64, 0x80, 0x00,
+		0x00, 0x00, 0x00, 0x0a, 0x00, 0x10, 0x20, 0x01,
+	}
+
+	t1, err := NewIpv6ReachabilityTlv()
+	if err != nil {
+		t.Fatalf("failed NewIpv6ReachabilityTlv: %#v", err)
+	}
+
+	err = t1.DecodeFromBytes(p1)
+	if err != nil {
+		t.Fatalf("failed DecodeFromBytes: %#v", err)
+	}
+
+	prefixes := t1.Ipv6Prefixes()
+	if tags := prefixes[0].Tags(); len(tags) != 1 || tags[0] != 100 {
+		t.Fatalf("failed Tags: %v", tags)
+	}
+	if !prefixes[0].UpDownBit {
+		t.Fatalf("failed UpDownBit")
+	}
+
+	p2, err := t1.Serialize()
+	if err != nil {
+		t.Fatalf("failed Serialize: %#v", err)
+	}
+
+	if !bytes.Equal(p1, p2) {
+		t.Fatalf("failed !Equal")
+	}
+}
+
 func TestIpv6InterfaceAddressTlv(t *testing.T) {
 	var err error
 	p1 := []byte{0xe8, 0x20,
@@ -19,6 +19,7 @@ package server
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"net"
 	"sort"
@@ -30,16 +31,20 @@ import (
 	"google.golang.org/grpc"
 
 	api "github.com/m-asama/golsr/api/isis"
+	"github.com/m-asama/golsr/internal/pkg/isis/config"
 	"github.com/m-asama/golsr/internal/pkg/util"
 	"github.com/m-asama/golsr/pkg/isis/packet"
 )
 
 type ApiServer struct {
+	instances  *IsisInstances
 	isisServer *IsisServer
 	grpcServer *grpc.Server
 	hosts      string
 }
 
+// NewApiServer serves the API of the single server i, which is the
+// instance named config.DEFAULT_INSTANCE_NAME.
 func NewApiServer(i *IsisServer, g *grpc.Server, hosts string) *ApiServer {
 	log.Debugf("enter")
 	defer log.Debugf("exit")
@@ -53,6 +58,31 @@ func NewApiServer(i *IsisServer, g *grpc.Server, hosts string) *ApiServer {
 	return s
 }
 
+// NewInstancesApiServer serves the API of every instance of instances.
+// Requests select one by its name.
+func NewInstancesApiServer(instances *IsisInstances, g *grpc.Server, hosts string) *ApiServer {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	grpc.EnableTracing = false
+	s := &ApiServer{
+		instances:  instances,
+		grpcServer: g,
+		hosts:      hosts,
+	}
+	api.RegisterGoisisApiServer(g, s)
+	return s
+}
+
+func (s *ApiServer) instance(name string) (*IsisServer, error) {
+	if s.instances != nil {
+		return s.instances.Instance(name)
+	}
+	if name != "" && name != config.DEFAULT_INSTANCE_NAME {
+		return nil, errors.New("instance " + name + " not found")
+	}
+	return s.isisServer, nil
+}
+
 func (s *ApiServer) Serve(wg *sync.WaitGroup) {
 	log.Debugf("enter")
 	defer log.Debugf("exit")
@@ -96,10 +126,17 @@ func (s *ApiServer) Enable(ctx context.Context, in *api.EnableRequest) (*api.Ena
 	log.Debugf("enter")
 	defer log.Debugf("exit")
 	response := &api.EnableResponse{}
-	if s.isisServer.enable() {
+	isisServer, err := s.instance(in.Instance)
+	if err != nil {
+		response.Result = err.Error()
+		return response, nil
+	}
+	isisServer.lock.Lock()
+	defer isisServer.lock.Unlock()
+	if isisServer.enable() {
 		response.Result = "already enabled"
 	} else {
-		s.isisServer.SetEnable()
+		isisServer.SetEnable()
 		response.Result = "enabled"
 	}
 	return response, nil
@@ -109,8 +146,15 @@ func (s *ApiServer) Disable(ctx context.Context, in *api.DisableRequest) (*api.D
 	log.Debugf("enter")
 	defer log.Debugf("exit")
 	response := &api.DisableResponse{}
-	if s.isisServer.enable() {
-		s.isisServer.SetDisable()
+	isisServer, err := s.instance(in.Instance)
+	if err != nil {
+		response.Result = err.Error()
+		return response, nil
+	}
+	isisServer.lock.Lock()
+	defer isisServer.lock.Unlock()
+	if isisServer.enable() {
+		isisServer.SetDisable()
 		response.Result = "disabled"
 	} else {
 		response.Result = "already disabled"
@@ -122,8 +166,15 @@ func (s *ApiServer) InterfaceEnable(ctx context.Context, in *api.InterfaceEnable
 	log.Debugf("enter")
 	defer log.Debugf("exit")
 	response := &api.InterfaceEnableResponse{}
+	isisServer, err := s.instance(in.Instance)
+	if err != nil {
+		response.Result = err.Error()
+		return response, nil
+	}
+	isisServer.lock.Lock()
+	defer isisServer.lock.Unlock()
 	found := false
-	for _, iface := range s.isisServer.circuitDb {
+	for _, iface := range isisServer.circuitDb {
 		if iface.name == in.Interface {
 			found = true
 			if iface.enable() {
@@ -144,8 +195,15 @@ func (s *ApiServer) InterfaceDisable(ctx context.Context, in *api.InterfaceDisab
 	log.Debugf("enter")
 	defer log.Debugf("exit")
 	response := &api.InterfaceDisableResponse{}
+	isisServer, err := s.instance(in.Instance)
+	if err != nil {
+		response.Result = err.Error()
+		return response, nil
+	}
+	isisServer.lock.Lock()
+	defer isisServer.lock.Unlock()
 	found := false
-	for _, iface := range s.isisServer.circuitDb {
+	for _, iface := range isisServer.circuitDb {
 		if iface.name == in.Interface {
 			found = true
 			if iface.enable() {
@@ -171,27 +229,19 @@ func (s *ApiServer) AdjacencyGet(ctx context.Context, in *api.AdjacencyGetReques
 func (s *ApiServer) AdjacencyMonitor(in *api.AdjacencyMonitorRequest, stream api.GoisisApi_AdjacencyMonitorServer) error {
 	log.Debugf("enter")
 	defer log.Debugf("exit")
-	s.isisServer.lock.RLock()
-	defer s.isisServer.lock.RUnlock()
-	for _, iface := range s.isisServer.circuitDb {
+	isisServer, err := s.instance(in.Instance)
+	if err != nil {
+		return err
+	}
+	isisServer.lock.RLock()
+	defer isisServer.lock.RUnlock()
+	for _, iface := range isisServer.circuitDb {
 		if in.Interface != "all" && iface.name != in.Interface {
 			continue
 		}
 		adjacencies := make([]*api.Adjacency, 0)
 		for _, adj := range iface.adjacencyDb {
-			adjacency := &api.Adjacency{
-				Interface:                 iface.name,
-				NeighborType:              adj.adjType.String(),
-				NeighborSysid:             fmt.Sprintf("%x", adj.systemId),
-				NeighborExtendedCircuitId: adj.extendedCircuitId,
-				NeighborSnpa:              fmt.Sprintf("%x", adj.lanAddress),
-				Usage:                     adj.adjUsage.String(),
-				HoldTimer:                 uint32(adj.holdingTime),
-				NeighborPriority:          uint32(adj.priority),
-				Lastuptime:                0,
-				State:                     adj.adjState.String(),
-			}
-			adjacencies = append(adjacencies, adjacency)
+			adjacencies = append(adjacencies, newApiAdjacency(iface, adj))
 		}
 		r := &api.AdjacencyMonitorResponse{
 			Adjacencies: adjacencies,
@@ -233,16 +283,43 @@ func fillLsp(apiLsp *api.Lsp, packetLsp *packet.LsPdu) {
 		}
 	}
 	apiLsp.Ipv6Addresses = make([]string, 0)
+	teRouterIdTlv, _ := packetLsp.TrafficEngineeringRouterIdTlv()
+	if teRouterIdTlv != nil {
+		apiLsp.Ipv4TeRouterid = util.Ipv4Uint32ToString(teRouterIdTlv.RouterId)
+	}
+	apiLsp.ProtocolSupporteds = make([]uint32, 0)
+	protocolsSupportedTlv, _ := packetLsp.ProtocolsSupportedTlv()
+	if protocolsSupportedTlv != nil {
+		for _, nlpId := range protocolsSupportedTlv.ProtocolsSupported() {
+			apiLsp.ProtocolSupporteds = append(apiLsp.ProtocolSupporteds, uint32(nlpId))
+		}
+	}
+	dynamicHostnameTlv, _ := packetLsp.DynamicHostnameTlv()
+	if dynamicHostnameTlv != nil {
+		apiLsp.DynamicHostname = string(dynamicHostnameTlv.DynamicHostname())
+	}
+	apiLsp.Binary, _ = packetLsp.Serialize()
+}
+
+func NewApiLsp(packetLsp *packet.LsPdu) *api.Lsp {
+	apiLsp := &api.Lsp{}
+	fillLsp(apiLsp, packetLsp)
+	return apiLsp
 }
 
 func (s *ApiServer) DbLsMonitor(in *api.DbLsMonitorRequest, stream api.GoisisApi_DbLsMonitorServer) error {
 	log.Debugf("enter")
 	defer log.Debugf("exit")
-	s.isisServer.lock.RLock()
-	defer s.isisServer.lock.RUnlock()
+	isisServer, err := s.instance(in.Instance)
+	if err != nil {
+		return err
+	}
+	// encoding the LSPs updates their length fields
+	isisServer.lock.Lock()
+	defer isisServer.lock.Unlock()
 	if in.Level == "level-1" || in.Level == "all" {
 		lsps := make([]*api.Lsp, 0)
-		for _, lsptmp := range s.isisServer.lsDb[ISIS_LEVEL_1] {
+		for _, lsptmp := range isisServer.lsDb[ISIS_LEVEL_1] {
 			lsp := &api.Lsp{}
 			fillLsp(lsp, lsptmp.pdu)
 			lsps = append(lsps, lsp)
@@ -255,7 +332,7 @@ func (s *ApiServer) DbLsMonitor(in *api.DbLsMonitorRequest, stream api.GoisisApi
 	}
 	if in.Level == "level-2" || in.Level == "all" {
 		lsps := make([]*api.Lsp, 0)
-		for _, lsptmp := range s.isisServer.lsDb[ISIS_LEVEL_2] {
+		for _, lsptmp := range isisServer.lsDb[ISIS_LEVEL_2] {
 			lsp := &api.Lsp{}
 			fillLsp(lsp, lsptmp.pdu)
 			lsps = append(lsps, lsp)
@@ -269,6 +346,36 @@ func (s *ApiServer) DbLsMonitor(in *api.DbLsMonitorRequest, stream api.GoisisApi
 	return nil
 }
 
+func (s *ApiServer) DbLsExport(ctx context.Context, in *api.DbLsExportRequest) (*api.DbLsExportResponse, error) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	response := &api.DbLsExportResponse{}
+	isisServer, err := s.instance(in.Instance)
+	if err != nil {
+		response.Result = err.Error()
+		return response, nil
+	}
+	var levels []IsisLevel
+	switch in.Level {
+	case "", "all":
+		levels = ISIS_LEVEL_ALL
+	case "level-1":
+		levels = []IsisLevel{ISIS_LEVEL_1}
+	case "level-2":
+		levels = []IsisLevel{ISIS_LEVEL_2}
+	default:
+		response.Result = "level invalid"
+		return response, nil
+	}
+	data, err := isisServer.ExportLsdb(levels)
+	if err != nil {
+		response.Result = err.Error()
+		return response, nil
+	}
+	response.Data = data
+	return response, nil
+}
+
 func (s *ApiServer) DbRiGet(ctx context.Context, in *api.DbRiGetRequest) (*api.DbRiGetResponse, error) {
 	log.Debugf("enter")
 	defer log.Debugf("exit")
@@ -292,6 +399,9 @@ func (keys SpfIdKeys) Less(i, j int) bool {
 func fillRoute4(apiRoute *api.Route, ipv4Ri *Ipv4Ri) {
 	apiRoute.Prefix = fmt.Sprintf("%s/%d", util.Ipv4Uint32ToString(ipv4Ri.prefixAddress), ipv4Ri.prefixLength)
 	apiRoute.Metric = ipv4Ri.metric
+	apiRoute.External = ipv4Ri.external
+	apiRoute.UpDown = ipv4Ri.down
+	apiRoute.Tags = ipv4Ri.tags
 	nhs := make([]*api.NextHop, 0)
 	for _, nh := range ipv4Ri.nexthops {
 		apiNh := &api.NextHop{}
@@ -305,6 +415,9 @@ func fillRoute4(apiRoute *api.Route, ipv4Ri *Ipv4Ri) {
 func fillRoute6(apiRoute *api.Route, ipv6Ri *Ipv6Ri) {
 	apiRoute.Prefix = fmt.Sprintf("%s/%d", util.Ipv6Uint32ArrayToString(ipv6Ri.prefixAddress), ipv6Ri.prefixLength)
 	apiRoute.Metric = ipv6Ri.metric
+	apiRoute.External = ipv6Ri.external
+	apiRoute.UpDown = ipv6Ri.down
+	apiRoute.Tags = ipv6Ri.tags
 	nhs := make([]*api.NextHop, 0)
 	for _, nh := range ipv6Ri.nexthops {
 		apiNh := &api.NextHop{}
@@ -318,8 +431,12 @@ func fillRoute6(apiRoute *api.Route, ipv6Ri *Ipv6Ri) {
 func (s *ApiServer) DbRiMonitor(in *api.DbRiMonitorRequest, stream api.GoisisApi_DbRiMonitorServer) error {
 	log.Debugf("enter")
 	defer log.Debugf("exit")
-	s.isisServer.lock.RLock()
-	defer s.isisServer.lock.RUnlock()
+	isisServer, err := s.instance(in.Instance)
+	if err != nil {
+		return err
+	}
+	isisServer.lock.RLock()
+	defer isisServer.lock.RUnlock()
 
 	keyMap := make(map[[SPF_ID_KEY_LENGTH]byte]bool)
 	keyArray := make([][SPF_ID_KEY_LENGTH]byte, 0)
@@ -329,12 +446,12 @@ func (s *ApiServer) DbRiMonitor(in *api.DbRiMonitorRequest, stream api.GoisisApi
 			(in.Level == "level-1" && level == ISIS_LEVEL_1) ||
 			(in.Level == "level-2" && level == ISIS_LEVEL_2) {
 			if in.AddressFamily == "all" || in.AddressFamily == "ipv4" {
-				for k, _ := range s.isisServer.ipv4RiDb[level] {
+				for k, _ := range isisServer.ipv4RiDb[level] {
 					keyMap[k] = true
 				}
 			}
 			if in.AddressFamily == "all" || in.AddressFamily == "ipv6" {
-				for k, _ := range s.isisServer.ipv6RiDb[level] {
+				for k, _ := range isisServer.ipv6RiDb[level] {
 					keyMap[k] = true
 				}
 			}
@@ -346,24 +463,30 @@ func (s *ApiServer) DbRiMonitor(in *api.DbRiMonitorRequest, stream api.GoisisApi
 	}
 	sort.Sort(SpfIdKeys(keyArray))
 
+	ipv4Rib := isisServer.ipv4Rib()
+	ipv6Rib := isisServer.ipv6Rib()
 	for _, k := range keyArray {
 		routes := make([]*api.Route, 0)
 		for _, level := range ISIS_LEVEL_ALL {
 			if in.Level == "all" ||
 				(in.Level == "level-1" && level == ISIS_LEVEL_1) ||
 				(in.Level == "level-2" && level == ISIS_LEVEL_2) {
-				if v, ok := s.isisServer.ipv4RiDb[level][k]; ok {
+				if v, ok := isisServer.ipv4RiDb[level][k]; ok {
 					route := &api.Route{}
 					route.Level = level.String2()
 					route.AddressFamily = "ipv4"
 					fillRoute4(route, v)
+					route.Preference = uint32(isisServer.preference(v.external))
+					route.Active = ipv4Rib[k] == v
 					routes = append(routes, route)
 				}
-				if v, ok := s.isisServer.ipv6RiDb[level][k]; ok {
+				if v, ok := isisServer.ipv6RiDb[level][k]; ok {
 					route := &api.Route{}
 					route.Level = level.String2()
 					route.AddressFamily = "ipv6"
 					fillRoute6(route, v)
+					route.Preference = uint32(isisServer.preference(v.external))
+					route.Active = ipv6Rib[k] == v
 					routes = append(routes, route)
 				}
 			}
@@ -376,3 +499,373 @@ func (s *ApiServer) DbRiMonitor(in *api.DbRiMonitorRequest, stream api.GoisisApi
 
 	return nil
 }
+
+func (s *ApiServer) TopologyGet(ctx context.Context, in *api.TopologyGetRequest) (*api.TopologyGetResponse, error) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	response := &api.TopologyGetResponse{
+		Graphs: make([]*api.TopologyGraph, 0),
+	}
+	isisServer, err := s.instance(in.Instance)
+	if err != nil {
+		response.Result = err.Error()
+		return response, nil
+	}
+	var levels []IsisLevel
+	switch in.Level {
+	case "", "all":
+		levels = ISIS_LEVEL_ALL
+	case "level-1":
+		levels = []IsisLevel{ISIS_LEVEL_1}
+	case "level-2":
+		levels = []IsisLevel{ISIS_LEVEL_2}
+	default:
+		response.Result = "level invalid"
+		return response, nil
+	}
+	for _, level := range levels {
+		response.Graphs = append(response.Graphs, isisServer.topologyGraph(level))
+	}
+	return response, nil
+}
+
+func (s *ApiServer) TraceSet(ctx context.Context, in *api.TraceSetRequest) (*api.TraceSetResponse, error) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	response := &api.TraceSetResponse{}
+	isisServer, err := s.instance(in.Instance)
+	if err != nil {
+		response.Result = err.Error()
+		return response, nil
+	}
+	err = isisServer.trace.set(in.Enable, in.Interfaces, in.Directions, in.PduTypes)
+	if err != nil {
+		response.Result = err.Error()
+		return response, nil
+	}
+	if in.Enable {
+		response.Result = "trace enabled"
+	} else {
+		response.Result = "trace disabled"
+	}
+	return response, nil
+}
+
+func (s *ApiServer) InstanceList(ctx context.Context, in *api.InstanceListRequest) (*api.InstanceListResponse, error) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	response := &api.InstanceListResponse{
+		Instances: make([]*api.Instance, 0),
+	}
+	names := []string{config.DEFAULT_INSTANCE_NAME}
+	if s.instances != nil {
+		names = s.instances.Names()
+	}
+	for _, name := range names {
+		isisServer, err := s.instance(name)
+		if err != nil {
+			continue
+		}
+		response.Instances = append(response.Instances, isisServer.apiInstance(name))
+	}
+	return response, nil
+}
+
+// NewApiPrefixSet, NewApiTagSet and NewApiPolicy convert the policy config
+// to their API messages.
+func NewApiPrefixSet(ps *config.PrefixSet) *api.PrefixSet {
+	apiPs := &api.PrefixSet{
+		Name:     apiString(ps.Config.PrefixSetName),
+		Prefixes: make([]*api.Prefix, 0),
+	}
+	for _, prefix := range ps.Prefixes {
+		apiPrefix := &api.Prefix{}
+		if prefix.Config.IpPrefix != nil {
+			apiPrefix.IpPrefix = *prefix.Config.IpPrefix
+		}
+		if prefix.Config.MasklengthRange != nil {
+			apiPrefix.MasklengthRange = *prefix.Config.MasklengthRange
+		}
+		apiPs.Prefixes = append(apiPs.Prefixes, apiPrefix)
+	}
+	return apiPs
+}
+
+func NewApiTagSet(ts *config.TagSet) *api.TagSet {
+	apiTs := &api.TagSet{
+		Name: apiString(ts.Config.TagSetName),
+		Tags: make([]uint32, 0),
+	}
+	for _, tag := range ts.Config.Tag {
+		if tag != nil {
+			apiTs.Tags = append(apiTs.Tags, *tag)
+		}
+	}
+	return apiTs
+}
+
+func apiString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func apiStrings(ss []*string) []string {
+	list := make([]string, 0)
+	for _, s := range ss {
+		list = append(list, *s)
+	}
+	return list
+}
+
+func NewApiPolicy(pd *config.PolicyDefinition) *api.Policy {
+	apiPd := &api.Policy{
+		Name:       apiString(pd.Config.Name),
+		Statements: make([]*api.Statement, 0),
+	}
+	for _, stmt := range pd.Statements {
+		c := stmt.Conditions.Config
+		a := stmt.Actions.Config
+		apiStmt := &api.Statement{
+			Name: apiString(stmt.Config.Name),
+			Conditions: &api.Conditions{
+				MatchPrefixSet:        apiString(c.MatchPrefixSet),
+				MatchPrefixSetOptions: apiString(c.MatchPrefixSetOptions),
+				MatchTagSet:           apiString(c.MatchTagSet),
+				MatchTagSetOptions:    apiString(c.MatchTagSetOptions),
+				Level:                 apiString(c.Level),
+				RouteType:             apiString(c.RouteType),
+			},
+			Actions: &api.Actions{
+				RouteDisposition: apiString(a.RouteDisposition),
+			},
+		}
+		if a.SetMetric != nil {
+			apiStmt.Actions.SetMetric = &api.MetricAction{Value: *a.SetMetric}
+		}
+		if a.SetTag != nil {
+			apiStmt.Actions.SetTag = &api.TagAction{Value: *a.SetTag}
+		}
+		if a.SetUpDown != nil {
+			apiStmt.Actions.SetUpDown = &api.UpDownAction{Value: *a.SetUpDown}
+		}
+		apiPd.Statements = append(apiPd.Statements, apiStmt)
+	}
+	return apiPd
+}
+
+func configString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func configStrings(ss []string) []*string {
+	list := make([]*string, 0)
+	for _, s := range ss {
+		s := s
+		list = append(list, &s)
+	}
+	return list
+}
+
+func configPrefixSet(apiPs *api.PrefixSet) *config.PrefixSet {
+	ps := &config.PrefixSet{
+		Prefixes: make([]*config.Prefix, 0),
+	}
+	ps.Config.PrefixSetName = configString(apiPs.Name)
+	for _, apiPrefix := range apiPs.Prefixes {
+		prefix := &config.Prefix{}
+		prefix.Config.IpPrefix = configString(apiPrefix.IpPrefix)
+		prefix.Config.MasklengthRange = configString(apiPrefix.MasklengthRange)
+		ps.Prefixes = append(ps.Prefixes, prefix)
+	}
+	return ps
+}
+
+func configTagSet(apiTs *api.TagSet) *config.TagSet {
+	ts := &config.TagSet{}
+	ts.Config.TagSetName = configString(apiTs.Name)
+	ts.Config.Tag = make([]*uint32, 0)
+	for _, tag := range apiTs.Tags {
+		tag := tag
+		ts.Config.Tag = append(ts.Config.Tag, &tag)
+	}
+	return ts
+}
+
+func configPolicy(apiPd *api.Policy) *config.PolicyDefinition {
+	pd := &config.PolicyDefinition{
+		Statements: make([]*config.Statement, 0),
+	}
+	pd.Config.Name = configString(apiPd.Name)
+	for _, apiStmt := range apiPd.Statements {
+		stmt := &config.Statement{}
+		stmt.Config.Name = configString(apiStmt.Name)
+		if c := apiStmt.Conditions; c != nil {
+			stmt.Conditions.Config.MatchPrefixSet = configString(c.MatchPrefixSet)
+			stmt.Conditions.Config.MatchPrefixSetOptions = configString(c.MatchPrefixSetOptions)
+			stmt.Conditions.Config.MatchTagSet = configString(c.MatchTagSet)
+			stmt.Conditions.Config.MatchTagSetOptions = configString(c.MatchTagSetOptions)
+			stmt.Conditions.Config.Level = configString(c.Level)
+			stmt.Conditions.Config.RouteType = configString(c.RouteType)
+		}
+		if a := apiStmt.Actions; a != nil {
+			stmt.Actions.Config.RouteDisposition = configString(a.RouteDisposition)
+			if a.SetMetric != nil {
+				stmt.Actions.Config.SetMetric = &a.SetMetric.Value
+			}
+			if a.SetTag != nil {
+				stmt.Actions.Config.SetTag = &a.SetTag.Value
+			}
+			if a.SetUpDown != nil {
+				stmt.Actions.Config.SetUpDown = &a.SetUpDown.Value
+			}
+		}
+		pd.Statements = append(pd.Statements, stmt)
+	}
+	return pd
+}
+
+func (s *ApiServer) PolicyGet(ctx context.Context, in *api.PolicyGetRequest) (*api.PolicyGetResponse, error) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	response := &api.PolicyGetResponse{
+		PrefixSets: make([]*api.PrefixSet, 0),
+		TagSets:    make([]*api.TagSet, 0),
+		Policies:   make([]*api.Policy, 0),
+	}
+	isisServer, err := s.instance(in.Instance)
+	if err != nil {
+		response.Result = err.Error()
+		return response, nil
+	}
+	isisServer.lock.RLock()
+	defer isisServer.lock.RUnlock()
+	c := isisServer.config
+	for _, ps := range c.DefinedSets.PrefixSets {
+		response.PrefixSets = append(response.PrefixSets, NewApiPrefixSet(ps))
+	}
+	for _, ts := range c.DefinedSets.TagSets {
+		response.TagSets = append(response.TagSets, NewApiTagSet(ts))
+	}
+	for _, pd := range c.PolicyDefinitions {
+		response.Policies = append(response.Policies, NewApiPolicy(pd))
+	}
+	response.Assignment = &api.PolicyAssignment{
+		InstallPolicies:      apiStrings(c.ApplyPolicy.Config.InstallPolicy),
+		DefaultInstallPolicy: apiString(c.ApplyPolicy.Config.DefaultInstallPolicy),
+		LeakPolicies:         apiStrings(c.ApplyPolicy.Config.LeakPolicy),
+	}
+	return response, nil
+}
+
+func (s *ApiServer) DefinedSetAdd(ctx context.Context, in *api.DefinedSetAddRequest) (*api.DefinedSetAddResponse, error) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	response := &api.DefinedSetAddResponse{}
+	isisServer, err := s.instance(in.Instance)
+	if err != nil {
+		response.Result = err.Error()
+		return response, nil
+	}
+	prefixSets := make([]*config.PrefixSet, 0)
+	for _, apiPs := range in.PrefixSets {
+		prefixSets = append(prefixSets, configPrefixSet(apiPs))
+	}
+	tagSets := make([]*config.TagSet, 0)
+	for _, apiTs := range in.TagSets {
+		tagSets = append(tagSets, configTagSet(apiTs))
+	}
+	err = isisServer.AddDefinedSets(prefixSets, tagSets)
+	if err != nil {
+		response.Result = err.Error()
+		return response, nil
+	}
+	response.Result = "defined sets added"
+	return response, nil
+}
+
+func (s *ApiServer) DefinedSetDelete(ctx context.Context, in *api.DefinedSetDeleteRequest) (*api.DefinedSetDeleteResponse, error) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	response := &api.DefinedSetDeleteResponse{}
+	isisServer, err := s.instance(in.Instance)
+	if err != nil {
+		response.Result = err.Error()
+		return response, nil
+	}
+	err = isisServer.DeleteDefinedSets(in.PrefixSets, in.TagSets)
+	if err != nil {
+		response.Result = err.Error()
+		return response, nil
+	}
+	response.Result = "defined sets deleted"
+	return response, nil
+}
+
+func (s *ApiServer) PolicyAdd(ctx context.Context, in *api.PolicyAddRequest) (*api.PolicyAddResponse, error) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	response := &api.PolicyAddResponse{}
+	isisServer, err := s.instance(in.Instance)
+	if err != nil {
+		response.Result = err.Error()
+		return response, nil
+	}
+	policies := make([]*config.PolicyDefinition, 0)
+	for _, apiPd := range in.Policies {
+		policies = append(policies, configPolicy(apiPd))
+	}
+	err = isisServer.AddPolicies(policies)
+	if err != nil {
+		response.Result = err.Error()
+		return response, nil
+	}
+	response.Result = "policies added"
+	return response, nil
+}
+
+func (s *ApiServer) PolicyDelete(ctx context.Context, in *api.PolicyDeleteRequest) (*api.PolicyDeleteResponse, error) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	response := &api.PolicyDeleteResponse{}
+	isisServer, err := s.instance(in.Instance)
+	if err != nil {
+		response.Result = err.Error()
+		return response, nil
+	}
+	err = isisServer.DeletePolicies(in.Policies)
+	if err != nil {
+		response.Result = err.Error()
+		return response, nil
+	}
+	response.Result = "policies deleted"
+	return response, nil
+}
+
+func (s *ApiServer) PolicyAssignmentSet(ctx context.Context, in *api.PolicyAssignmentSetRequest) (*api.PolicyAssignmentSetResponse, error) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	response := &api.PolicyAssignmentSetResponse{}
+	isisServer, err := s.instance(in.Instance)
+	if err != nil {
+		response.Result = err.Error()
+		return response, nil
+	}
+	if in.Assignment == nil {
+		response.Result = "assignment not specified"
+		return response, nil
+	}
+	err = isisServer.SetPolicyAssignment(configStrings(in.Assignment.InstallPolicies),
+		configString(in.Assignment.DefaultInstallPolicy),
+		configStrings(in.Assignment.LeakPolicies))
+	if err != nil {
+		response.Result = err.Error()
+		return response, nil
+	}
+	response.Result = "policy assignment set"
+	return response, nil
+}
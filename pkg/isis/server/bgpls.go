@@ -0,0 +1,277 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/binary"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/m-asama/golsr/internal/pkg/util"
+	"github.com/m-asama/golsr/pkg/bgpls"
+	"github.com/m-asama/golsr/pkg/isis/packet"
+)
+
+func bgplsProtocolId(level IsisLevel) bgpls.ProtocolId {
+	if level == ISIS_LEVEL_1 {
+		return bgpls.PROTOCOL_ID_ISIS_LEVEL1
+	}
+	return bgpls.PROTOCOL_ID_ISIS_LEVEL2
+}
+
+// bgplsIgpRouterId returns the IGP Router-ID of a node: the system id
+// alone, or the system id and pseudonode id for a pseudonode.
+func bgplsIgpRouterId(id [packet.NEIGHBOUR_ID_LENGTH]byte) []byte {
+	if id[packet.NEIGHBOUR_ID_LENGTH-1] == 0 {
+		return append([]byte{}, id[0:packet.SYSTEM_ID_LENGTH]...)
+	}
+	return append([]byte{}, id[:]...)
+}
+
+func bgplsIpv4Prefix(prefix uint32) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, prefix)
+	return b
+}
+
+func bgplsIpv6Prefix(prefix [4]uint32) []byte {
+	b := make([]byte, 16)
+	for i, p := range prefix {
+		binary.BigEndian.PutUint32(b[i*4:i*4+4], p)
+	}
+	return b
+}
+
+// newBgplsRoutes converts the LSPs of one level into BGP-LS Node, Link
+// and Prefix NLRIs (RFC 7752). Fragments of a node are merged into a
+// single Node NLRI and purged LSPs are skipped as in newTopologyGraph.
+func newBgplsRoutes(level IsisLevel, lsps []*packet.LsPdu, asNumber, bgplsId uint32) []*bgpls.Route {
+	log.Debugf("enter: %s", level)
+	defer log.Debugf("exit: %s", level)
+	routes := make([]*bgpls.Route, 0)
+	nodeDescriptor := func(id [packet.NEIGHBOUR_ID_LENGTH]byte) bgpls.NodeDescriptor {
+		return bgpls.NodeDescriptor{
+			AsNumber:    asNumber,
+			BgpLsId:     bgplsId,
+			IgpRouterId: bgplsIgpRouterId(id),
+		}
+	}
+	nodes := make(map[[packet.NEIGHBOUR_ID_LENGTH]byte]*bgpls.Attribute)
+	nodeIds := make([][packet.NEIGHBOUR_ID_LENGTH]byte, 0)
+	for _, lsp := range lsps {
+		if lsp.RemainingLifetime == 0 {
+			continue
+		}
+		lspId := lsp.LspId()
+		var id [packet.NEIGHBOUR_ID_LENGTH]byte
+		copy(id[:], lspId[0:packet.NEIGHBOUR_ID_LENGTH])
+		attr, ok := nodes[id]
+		if !ok {
+			attr = bgpls.NewAttribute()
+			nodes[id] = attr
+			nodeIds = append(nodeIds, id)
+		}
+		if lspId[packet.NEIGHBOUR_ID_LENGTH] == 0 && id[packet.NEIGHBOUR_ID_LENGTH-1] == 0 {
+			var flags uint8
+			if lsp.LSPDBOverloadFlag {
+				flags |= bgpls.NODE_FLAG_BIT_OVERLOAD
+			}
+			if lsp.AttachedDefaultMetric {
+				flags |= bgpls.NODE_FLAG_BIT_ATTACHED
+			}
+			attr.AddNodeFlagBits(flags)
+			areaAddressesTlv, _ := lsp.AreaAddressesTlv()
+			if areaAddressesTlv != nil {
+				for _, areaAddress := range areaAddressesTlv.AreaAddresses() {
+					attr.AddIsisAreaIdentifier(areaAddress)
+				}
+			}
+		}
+		dynamicHostnameTlv, _ := lsp.DynamicHostnameTlv()
+		if dynamicHostnameTlv != nil {
+			attr.AddNodeName(string(dynamicHostnameTlv.DynamicHostname()))
+		}
+		teRouterIdTlv, _ := lsp.TrafficEngineeringRouterIdTlv()
+		if teRouterIdTlv != nil {
+			attr.AddIpv4RouterIdOfLocalNode(teRouterIdTlv.RouterId)
+		}
+
+		isNeighboursLspTlvs, _ := lsp.IsNeighboursLspTlvs()
+		for _, tlv := range isNeighboursLspTlvs {
+			for _, n := range tlv.Neighbours() {
+				linkAttr := bgpls.NewAttribute()
+				linkAttr.AddIgpMetric(uint32(n.DefaultMetric), false)
+				routes = append(routes, &bgpls.Route{
+					Nlri: &bgpls.Nlri{
+						NlriType:   bgpls.NLRI_TYPE_LINK,
+						ProtocolId: bgplsProtocolId(level),
+						LocalNode:  nodeDescriptor(id),
+						RemoteNode: nodeDescriptor(n.NeighbourId()),
+					},
+					Attribute: linkAttr,
+				})
+			}
+		}
+		extendedIsReachabilityTlvs, _ := lsp.ExtendedIsReachabilityTlvs()
+		for _, tlv := range extendedIsReachabilityTlvs {
+			for _, n := range tlv.Neighbours() {
+				nlri := &bgpls.Nlri{
+					NlriType:   bgpls.NLRI_TYPE_LINK,
+					ProtocolId: bgplsProtocolId(level),
+					LocalNode:  nodeDescriptor(id),
+					RemoteNode: nodeDescriptor(n.NeighbourId()),
+				}
+				if addrs := n.Ipv4InterfaceAddressSubTlvs(); len(addrs) > 0 {
+					nlri.Link.Ipv4InterfaceAddress = addrs[0]
+				}
+				if addrs := n.Ipv4NeighbourAddressSubTlvs(); len(addrs) > 0 {
+					nlri.Link.Ipv4NeighborAddress = addrs[0]
+				}
+				linkAttr := bgpls.NewAttribute()
+				linkAttr.AddIgpMetric(n.DefaultMetric, true)
+				if n.AdminGroupSubTlv() != nil {
+					linkAttr.AddAdministrativeGroup(*n.AdminGroupSubTlv())
+				}
+				if n.MaximumLinkBandwidthSubTlv() != nil {
+					linkAttr.AddMaximumLinkBandwidth(*n.MaximumLinkBandwidthSubTlv())
+				}
+				if n.MaximumReservableLinkBandwidthSubTlv() != nil {
+					linkAttr.AddMaxReservableLinkBandwidth(*n.MaximumReservableLinkBandwidthSubTlv())
+				}
+				if n.UnreservedBandwidthSubTlv() != nil {
+					linkAttr.AddUnreservedBandwidth(*n.UnreservedBandwidthSubTlv())
+				}
+				if n.TrafficEngineeringDefaultMetric() != nil {
+					linkAttr.AddTeDefaultMetric(*n.TrafficEngineeringDefaultMetric())
+				}
+				routes = append(routes, &bgpls.Route{
+					Nlri:      nlri,
+					Attribute: linkAttr,
+				})
+			}
+		}
+
+		ipInternalReachInfoTlvs, _ := lsp.IpInternalReachInfoTlvs()
+		for _, tlv := range ipInternalReachInfoTlvs {
+			for _, s := range tlv.IpSubnets() {
+				prefixAttr := bgpls.NewAttribute()
+				prefixAttr.AddPrefixMetric(uint32(s.DefaultMetric))
+				routes = append(routes, &bgpls.Route{
+					Nlri: &bgpls.Nlri{
+						NlriType:   bgpls.NLRI_TYPE_IPV4_PREFIX,
+						ProtocolId: bgplsProtocolId(level),
+						LocalNode:  nodeDescriptor(id),
+						Prefix: bgpls.PrefixDescriptor{
+							Prefix:       bgplsIpv4Prefix(s.IpAddress & s.SubnetMask),
+							PrefixLength: util.Snmask42plen(s.SubnetMask),
+						},
+					},
+					Attribute: prefixAttr,
+				})
+			}
+		}
+		extendedIpReachabilityTlvs, _ := lsp.ExtendedIpReachabilityTlvs()
+		for _, tlv := range extendedIpReachabilityTlvs {
+			for _, p := range tlv.Ipv4Prefixes() {
+				prefixAttr := bgpls.NewAttribute()
+				prefixAttr.AddPrefixMetric(p.MetricInformation)
+				routes = append(routes, &bgpls.Route{
+					Nlri: &bgpls.Nlri{
+						NlriType:   bgpls.NLRI_TYPE_IPV4_PREFIX,
+						ProtocolId: bgplsProtocolId(level),
+						LocalNode:  nodeDescriptor(id),
+						Prefix: bgpls.PrefixDescriptor{
+							Prefix:       bgplsIpv4Prefix(p.Ipv4Prefix()),
+							PrefixLength: p.PrefixLength(),
+						},
+					},
+					Attribute: prefixAttr,
+				})
+			}
+		}
+		ipv6ReachabilityTlvs, _ := lsp.Ipv6ReachabilityTlvs()
+		for _, tlv := range ipv6ReachabilityTlvs {
+			for _, p := range tlv.Ipv6Prefixes() {
+				prefixAttr := bgpls.NewAttribute()
+				prefixAttr.AddPrefixMetric(p.Metric)
+				routes = append(routes, &bgpls.Route{
+					Nlri: &bgpls.Nlri{
+						NlriType:   bgpls.NLRI_TYPE_IPV6_PREFIX,
+						ProtocolId: bgplsProtocolId(level),
+						LocalNode:  nodeDescriptor(id),
+						Prefix: bgpls.PrefixDescriptor{
+							Prefix:       bgplsIpv6Prefix(p.Ipv6Prefix()),
+							PrefixLength: p.PrefixLength(),
+						},
+					},
+					Attribute: prefixAttr,
+				})
+			}
+		}
+	}
+	for _, id := range nodeIds {
+		routes = append(routes, &bgpls.Route{
+			Nlri: &bgpls.Nlri{
+				NlriType:   bgpls.NLRI_TYPE_NODE,
+				ProtocolId: bgplsProtocolId(level),
+				LocalNode:  nodeDescriptor(id),
+			},
+			Attribute: nodes[id],
+		})
+	}
+	return routes
+}
+
+// SetBgpls makes the server advertise its LSDB to a BGP-LS peer. The
+// BGP-LS Identifier defaults to the router id.
+func (isis *IsisServer) SetBgpls(config *bgpls.SpeakerConfig, bgplsId uint32) error {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	speaker, err := bgpls.NewSpeaker(config)
+	if err != nil {
+		return err
+	}
+	if bgplsId == 0 {
+		bgplsId = config.RouterId
+	}
+	isis.bgpls = speaker
+	isis.bgplsAs = config.LocalAs
+	isis.bgplsId = bgplsId
+	return nil
+}
+
+// bgplsUpdate hands the current LSDB of both levels to the speaker, which
+// sends the peer only what changed since the last call.
+func (isis *IsisServer) bgplsUpdate() {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	if isis.bgpls == nil {
+		return
+	}
+	routes := make([]*bgpls.Route, 0)
+	isis.lock.RLock()
+	for _, level := range ISIS_LEVEL_ALL {
+		lsps := make([]*packet.LsPdu, 0)
+		for _, ls := range isis.lsDb[level] {
+			lsps = append(lsps, ls.pdu)
+		}
+		routes = append(routes, newBgplsRoutes(level, lsps, isis.bgplsAs, isis.bgplsId)...)
+	}
+	isis.lock.RUnlock()
+	isis.bgpls.SetRoutes(routes)
+}
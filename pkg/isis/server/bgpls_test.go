@@ -0,0 +1,180 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/m-asama/golsr/pkg/bgpls"
+	"github.com/m-asama/golsr/pkg/isis/packet"
+)
+
+type bgplsTestPeer struct {
+	t    *testing.T
+	conn net.Conn
+}
+
+func (peer *bgplsTestPeer) read() (bgpls.MessageType, []byte) {
+	peer.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	messageType, body, err := bgpls.ReadMessage(peer.conn)
+	if err != nil {
+		peer.t.Fatalf("failed ReadMessage: %#v", err)
+	}
+	return messageType, body
+}
+
+func (peer *bgplsTestPeer) update() *bgpls.UpdateMessage {
+	for {
+		messageType, body := peer.read()
+		if messageType == bgpls.MESSAGE_TYPE_KEEPALIVE {
+			continue
+		}
+		if messageType != bgpls.MESSAGE_TYPE_UPDATE {
+			peer.t.Fatalf("failed message type: %s", messageType)
+		}
+		update, err := bgpls.DecodeUpdateMessage(body)
+		if err != nil {
+			peer.t.Fatalf("failed DecodeUpdateMessage: %#v", err)
+		}
+		return update
+	}
+}
+
+func TestBgpls(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed Listen: %#v", err)
+	}
+	defer ln.Close()
+
+	isis := NewIsisServer("", "")
+	err = isis.SetBgpls(&bgpls.SpeakerConfig{
+		PeerAddress:  ln.Addr().String(),
+		LocalAs:      65000,
+		RouterId:     0x0a000001,
+		ConnectRetry: 100 * time.Millisecond,
+	}, 0)
+	if err != nil {
+		t.Fatalf("failed SetBgpls: %#v", err)
+	}
+	a := newTestLsp(t, 1, 1, []byte{2}, 0x0a000100, "a")
+	b := newTestLsp(t, 2, 1, []byte{1}, 0x0a000200, "b")
+	for _, lsp := range []*packet.LsPdu{a, b} {
+		ls, _ := NewLs(lsp, false, nil)
+		isis.lsDb[ISIS_LEVEL_2] = append(isis.lsDb[ISIS_LEVEL_2], ls)
+	}
+	isis.bgplsUpdate()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go isis.bgpls.Serve(&wg)
+	defer func() {
+		isis.bgpls.Exit()
+		wg.Wait()
+	}()
+
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatalf("failed Accept: %#v", err)
+	}
+	defer conn.Close()
+	peer := &bgplsTestPeer{t, conn}
+
+	messageType, body := peer.read()
+	if messageType != bgpls.MESSAGE_TYPE_OPEN {
+		t.Fatalf("failed OPEN: %s", messageType)
+	}
+	open, err := bgpls.DecodeOpenMessage(body)
+	if err != nil || open.MyAs != 65000 || !open.BgpLs || open.BgpIdentifier != 0x0a000001 {
+		t.Fatalf("failed OPEN: %#v %#v", open, err)
+	}
+	peerOpen := &bgpls.OpenMessage{
+		Version:       bgpls.BGP_VERSION,
+		MyAs:          65000,
+		HoldTime:      90,
+		BgpIdentifier: 0x0a000002,
+		BgpLs:         true,
+		FourOctetAs:   true,
+	}
+	data, _ := peerOpen.Serialize()
+	conn.Write(data)
+	conn.Write(bgpls.SerializeKeepaliveMessage())
+	if messageType, _ := peer.read(); messageType != bgpls.MESSAGE_TYPE_KEEPALIVE {
+		t.Fatalf("failed KEEPALIVE: %s", messageType)
+	}
+
+	// 2 nodes, 2 links and 2 prefixes
+	reach := make(map[string]*bgpls.UpdateMessage)
+	for len(reach) < 6 {
+		update := peer.update()
+		if len(update.Reach) != 1 || update.LocalPref == nil || update.NextHop != 0x0a000001 {
+			t.Fatalf("failed update: %#v", update)
+		}
+		reach[update.Reach[0].String()] = update
+	}
+	for key, update := range reach {
+		nlri := update.Reach[0]
+		if nlri.ProtocolId != bgpls.PROTOCOL_ID_ISIS_LEVEL2 || nlri.LocalNode.AsNumber != 65000 ||
+			nlri.LocalNode.BgpLsId != 0x0a000001 {
+			t.Fatalf("failed nlri: %s", key)
+		}
+		switch nlri.NlriType {
+		case bgpls.NLRI_TYPE_NODE:
+			name := update.Attribute.Tlv(bgpls.TLV_TYPE_NODE_NAME)
+			if name == nil || (string(name.Value) != "a" && string(name.Value) != "b") {
+				t.Fatalf("failed node name: %s", key)
+			}
+		case bgpls.NLRI_TYPE_LINK:
+			metric := update.Attribute.Tlv(bgpls.TLV_TYPE_IGP_METRIC)
+			if metric == nil || len(metric.Value) != 3 || metric.Value[2] != 10 {
+				t.Fatalf("failed igp metric: %s", key)
+			}
+		case bgpls.NLRI_TYPE_IPV4_PREFIX:
+			if nlri.Prefix.PrefixLength != 24 || update.Attribute.Uint32(bgpls.TLV_TYPE_PREFIX_METRIC) != 10 {
+				t.Fatalf("failed prefix: %s", key)
+			}
+		default:
+			t.Fatalf("failed nlri type: %s", key)
+		}
+	}
+
+	// b loses its adjacency to a
+	b2 := newTestLsp(t, 2, 2, []byte{}, 0x0a000200, "b")
+	ls, _ := NewLs(b2, false, nil)
+	isis.lock.Lock()
+	isis.lsDb[ISIS_LEVEL_2][1] = ls
+	isis.lock.Unlock()
+	isis.bgplsUpdate()
+
+	update := peer.update()
+	if len(update.Reach) != 0 || len(update.Unreach) != 1 {
+		t.Fatalf("failed withdraw: %#v", update)
+	}
+	nlri := update.Unreach[0]
+	if nlri.NlriType != bgpls.NLRI_TYPE_LINK ||
+		string(nlri.LocalNode.IgpRouterId) != string([]byte{0, 0, 0, 0, 0, 2}) ||
+		string(nlri.RemoteNode.IgpRouterId) != string([]byte{0, 0, 0, 0, 0, 1}) {
+		t.Fatalf("failed withdraw: %s", nlri)
+	}
+	if isis.bgpls.State() != bgpls.SESSION_STATE_ESTABLISHED {
+		t.Fatalf("failed State: %s", isis.bgpls.State())
+	}
+}
@@ -22,7 +22,6 @@ import (
 	"errors"
 	"math"
 	"sort"
-	"syscall"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -41,6 +40,13 @@ const (
 	CIRCUIT_CH_MSG_EXIT
 )
 
+// circuitChSend is a message for the goroutine of a circuit which waits
+// on ch.
+type circuitChSend struct {
+	ch  chan CircuitChMsg
+	msg CircuitChMsg
+}
+
 type CircuitChState uint8
 
 const (
@@ -55,7 +61,7 @@ type Circuit struct {
 	ifConfig *config.Interface
 
 	name                   string
-	fd                     int
+	transport              Transport
 	localCircuitId         uint8
 	extendedLocalCircuitId uint32
 	isReachabilities       [ISIS_LEVEL_NUM][]*IsReachability
@@ -64,8 +70,8 @@ type Circuit struct {
 
 	adjacencyDb []*Adjacency
 
-	snSenderCh        chan *packet.SnPdu
-	lsSenderCh        chan *packet.LsPdu
+	snSenderCh        chan *circuitFrame
+	lsSenderCh        chan *circuitFrame
 	p2pIihSenderCh    chan CircuitChMsg
 	p2pIihSenderState CircuitChState
 	l1lIihSenderCh    chan CircuitChMsg
@@ -132,14 +138,13 @@ func NewCircuit(isis *IsisServer, ifKernel *kernel.Interface, ifConfig *config.I
 		ifKernel:               ifKernel,
 		ifConfig:               ifConfig,
 		name:                   ifKernel.Name,
-		fd:                     -1,
 		localCircuitId:         localCircuitId,
 		extendedLocalCircuitId: extendedLocalCircuitId,
 		uptime:                 uptime,
 		downtime:               downtime,
 		adjacencyDb:            make([]*Adjacency, 0),
-		snSenderCh:             make(chan *packet.SnPdu, 8),
-		lsSenderCh:             make(chan *packet.LsPdu, 8),
+		snSenderCh:             make(chan *circuitFrame, 8),
+		lsSenderCh:             make(chan *circuitFrame, 8),
 		p2pIihSenderCh:         make(chan CircuitChMsg),
 		p2pIihSenderState:      CIRCUIT_CH_STATE_SUSPENDED,
 		l1lIihSenderCh:         make(chan CircuitChMsg),
@@ -172,17 +177,23 @@ func (circuit *Circuit) Serve() {
 	go circuit.receiver()
 }
 
+// Exit stops the goroutines of the circuit, which must have been removed
+// from the circuitDb. The caller holds isis.lock.
 func (circuit *Circuit) Exit() {
 	log.Debugf("enter: %s", circuit.name)
 	defer log.Debugf("exit: %s", circuit.name)
 	close(circuit.snSenderCh)
 	close(circuit.lsSenderCh)
-	circuit.p2pIihSenderCh <- CIRCUIT_CH_MSG_EXIT
-	circuit.l1lIihSenderCh <- CIRCUIT_CH_MSG_EXIT
-	circuit.l2lIihSenderCh <- CIRCUIT_CH_MSG_EXIT
-	circuit.l1lCsnSenderCh <- CIRCUIT_CH_MSG_EXIT
-	circuit.l2lCsnSenderCh <- CIRCUIT_CH_MSG_EXIT
-	circuit.receiverCh <- CIRCUIT_CH_MSG_EXIT
+	if circuit.transport != nil {
+		circuit.transport.Close()
+		circuit.transport = nil
+	}
+	circuit.isis.circuitChSend(circuit.p2pIihSenderCh, CIRCUIT_CH_MSG_EXIT)
+	circuit.isis.circuitChSend(circuit.l1lIihSenderCh, CIRCUIT_CH_MSG_EXIT)
+	circuit.isis.circuitChSend(circuit.l2lIihSenderCh, CIRCUIT_CH_MSG_EXIT)
+	circuit.isis.circuitChSend(circuit.l1lCsnSenderCh, CIRCUIT_CH_MSG_EXIT)
+	circuit.isis.circuitChSend(circuit.l2lCsnSenderCh, CIRCUIT_CH_MSG_EXIT)
+	circuit.isis.circuitChSend(circuit.receiverCh, CIRCUIT_CH_MSG_EXIT)
 }
 
 func (circuit *Circuit) SetEnable() {
@@ -442,11 +453,11 @@ func (circuit *Circuit) snSender() {
 	log.Debugf("enter: %s", circuit.name)
 	defer log.Debugf("exit: %s", circuit.name)
 	for {
-		snp, ok := <-circuit.snSenderCh
+		frame, ok := <-circuit.snSenderCh
 		if !ok {
 			return
 		}
-		circuit.sendPdu(snp)
+		circuit.sendFrame(frame)
 	}
 }
 
@@ -454,12 +465,12 @@ func (circuit *Circuit) lsSender() {
 	log.Debugf("enter: %s", circuit.name)
 	defer log.Debugf("exit: %s", circuit.name)
 	for {
-		lsp, ok := <-circuit.lsSenderCh
+		frame, ok := <-circuit.lsSenderCh
 		if !ok {
 			return
 		}
-		circuit.sendPdu(lsp)
-		time.Sleep(time.Millisecond * time.Duration(circuit.lspPacingInterval()))
+		circuit.sendFrame(frame)
+		time.Sleep(frame.pacing)
 	}
 }
 
@@ -474,8 +485,10 @@ func (circuit *Circuit) p2pIihSender() {
 			switch msg {
 			case CIRCUIT_CH_MSG_START:
 				log.Debugf("%s: CIRCUIT_CH_MSG_START", circuit.name)
+				circuit.isis.lock.RLock()
 				circuit.sendIih(packet.PDU_TYPE_P2P_IIHP)
 				timer.Reset(time.Second * circuit.sendP2pIihInterval())
+				circuit.isis.lock.RUnlock()
 			case CIRCUIT_CH_MSG_STOP:
 				log.Debugf("%s: CIRCUIT_CH_MSG_STOP", circuit.name)
 				timer.Stop()
@@ -485,8 +498,10 @@ func (circuit *Circuit) p2pIihSender() {
 			}
 		case <-timer.C:
 			log.Debugf("%s: timer.C", circuit.name)
+			circuit.isis.lock.RLock()
 			circuit.sendIih(packet.PDU_TYPE_P2P_IIHP)
 			timer.Reset(time.Second * circuit.sendP2pIihInterval())
+			circuit.isis.lock.RUnlock()
 		}
 	}
 EXIT:
@@ -503,12 +518,14 @@ func (circuit *Circuit) l1lIihSender() {
 			switch msg {
 			case CIRCUIT_CH_MSG_START:
 				log.Debugf("%s: CIRCUIT_CH_MSG_START", circuit.name)
+				circuit.isis.lock.RLock()
 				d := time.Second * circuit.sendL1lIihInterval() * 2
 				if circuit.uptime != nil &&
 					circuit.uptime.Add(d).Before(time.Now()) {
 					circuit.sendIih(packet.PDU_TYPE_LEVEL1_LAN_IIHP)
 				}
 				timer.Reset(time.Second * circuit.sendL1lIihInterval())
+				circuit.isis.lock.RUnlock()
 			case CIRCUIT_CH_MSG_STOP:
 				log.Debugf("%s: CIRCUIT_CH_MSG_STOP", circuit.name)
 				timer.Stop()
@@ -518,8 +535,10 @@ func (circuit *Circuit) l1lIihSender() {
 			}
 		case <-timer.C:
 			log.Debugf("%s: timer.C", circuit.name)
+			circuit.isis.lock.RLock()
 			circuit.sendIih(packet.PDU_TYPE_LEVEL1_LAN_IIHP)
 			timer.Reset(time.Second * circuit.sendL1lIihInterval())
+			circuit.isis.lock.RUnlock()
 		}
 	}
 EXIT:
@@ -536,12 +555,14 @@ func (circuit *Circuit) l2lIihSender() {
 			switch msg {
 			case CIRCUIT_CH_MSG_START:
 				log.Debugf("%s: CIRCUIT_CH_MSG_START", circuit.name)
+				circuit.isis.lock.RLock()
 				d := time.Second * circuit.sendL2lIihInterval() * 2
 				if circuit.uptime != nil &&
 					circuit.uptime.Add(d).Before(time.Now()) {
 					circuit.sendIih(packet.PDU_TYPE_LEVEL2_LAN_IIHP)
 				}
 				timer.Reset(time.Second * circuit.sendL2lIihInterval())
+				circuit.isis.lock.RUnlock()
 			case CIRCUIT_CH_MSG_STOP:
 				log.Debugf("%s: CIRCUIT_CH_MSG_STOP", circuit.name)
 				timer.Stop()
@@ -551,8 +572,10 @@ func (circuit *Circuit) l2lIihSender() {
 			}
 		case <-timer.C:
 			log.Debugf("%s: timer.C", circuit.name)
+			circuit.isis.lock.RLock()
 			circuit.sendIih(packet.PDU_TYPE_LEVEL2_LAN_IIHP)
 			timer.Reset(time.Second * circuit.sendL2lIihInterval())
+			circuit.isis.lock.RUnlock()
 		}
 	}
 EXIT:
@@ -569,7 +592,9 @@ func (circuit *Circuit) l1lCsnSender() {
 			switch msg {
 			case CIRCUIT_CH_MSG_START:
 				log.Debugf("%s: CIRCUIT_CH_MSG_START", circuit.name)
+				circuit.isis.lock.RLock()
 				timer.Reset(time.Second * circuit.sendCsnInterval())
+				circuit.isis.lock.RUnlock()
 			case CIRCUIT_CH_MSG_STOP:
 				log.Debugf("%s: CIRCUIT_CH_MSG_STOP", circuit.name)
 				timer.Stop()
@@ -579,8 +604,10 @@ func (circuit *Circuit) l1lCsnSender() {
 			}
 		case <-timer.C:
 			log.Debugf("%s: timer.C", circuit.name)
+			circuit.isis.lock.RLock()
 			circuit.sendCsn(packet.PDU_TYPE_LEVEL1_CSNP)
 			timer.Reset(time.Second * circuit.sendCsnInterval())
+			circuit.isis.lock.RUnlock()
 		}
 	}
 EXIT:
@@ -597,7 +624,9 @@ func (circuit *Circuit) l2lCsnSender() {
 			switch msg {
 			case CIRCUIT_CH_MSG_START:
 				log.Debugf("%s: CIRCUIT_CH_MSG_START", circuit.name)
+				circuit.isis.lock.RLock()
 				timer.Reset(time.Second * circuit.sendCsnInterval())
+				circuit.isis.lock.RUnlock()
 			case CIRCUIT_CH_MSG_STOP:
 				log.Debugf("%s: CIRCUIT_CH_MSG_STOP", circuit.name)
 				timer.Stop()
@@ -607,84 +636,95 @@ func (circuit *Circuit) l2lCsnSender() {
 			}
 		case <-timer.C:
 			log.Debugf("%s: timer.C", circuit.name)
+			circuit.isis.lock.RLock()
 			circuit.sendCsn(packet.PDU_TYPE_LEVEL2_CSNP)
 			timer.Reset(time.Second * circuit.sendCsnInterval())
+			circuit.isis.lock.RUnlock()
 		}
 	}
 EXIT:
 }
 
-type receiverMessage struct {
-	from [packet.SYSTEM_ID_LENGTH]byte
-	pdu  packet.IsisPdu
-}
-
 func (circuit *Circuit) receiver() {
 	log.Debugf("enter: %s", circuit.name)
 	defer log.Debugf("exit: %s", circuit.name)
-	buf := make([]byte, 10240)
-	recvCh := make(chan *receiverMessage)
 	for {
-		select {
-		case msg := <-(circuit.receiverCh):
-			switch msg {
-			case CIRCUIT_CH_MSG_START:
-				log.Debugf("%s: CIRCUIT_CH_MSG_START", circuit.name)
-				go func() {
-					for circuit.receiverState == CIRCUIT_CH_STATE_RUNNING {
-						n, from, err := syscall.Recvfrom(circuit.fd, buf, 0)
-						if err != nil {
-							break
-						}
-						fromll := from.(*syscall.SockaddrLinklayer)
-						var fromb [packet.SYSTEM_ID_LENGTH]byte
-						copy(fromb[0:packet.SYSTEM_ID_LENGTH],
-							fromll.Addr[0:packet.SYSTEM_ID_LENGTH])
-						llc := 0
-						if bytes.Equal(buf[0:3], packet.Llc) {
-							llc = 3
-						}
-						pdu, err := packet.DecodePduFromBytes(buf[0+llc : n])
-						if err != nil {
-							break
-						}
-						recvCh <- &receiverMessage{from: fromb, pdu: pdu}
-					}
-				}()
-			case CIRCUIT_CH_MSG_STOP:
-				log.Debugf("%s: CIRCUIT_CH_MSG_STOP", circuit.name)
-			case CIRCUIT_CH_MSG_EXIT:
-				log.Debugf("%s: CIRCUIT_CH_MSG_EXIT", circuit.name)
-				goto EXIT
-			}
-		case rcvMsg := <-recvCh:
-			log.Debugf("%s: recvCh", circuit.name)
-			if circuit.receiverState != CIRCUIT_CH_STATE_RUNNING {
-				log.Debugf("%s: discard", circuit.name)
+		msg := <-(circuit.receiverCh)
+		switch msg {
+		case CIRCUIT_CH_MSG_START:
+			log.Debugf("%s: CIRCUIT_CH_MSG_START", circuit.name)
+			circuit.isis.lock.RLock()
+			transport := circuit.transport
+			circuit.isis.lock.RUnlock()
+			if transport == nil {
 				break
 			}
-			switch rcvMsg.pdu.PduType() {
-			case packet.PDU_TYPE_LEVEL1_LAN_IIHP, packet.PDU_TYPE_LEVEL2_LAN_IIHP:
-				iihPdu := rcvMsg.pdu.(*packet.IihPdu)
-				circuit.receiveBcastIih(iihPdu, rcvMsg.from)
-			case packet.PDU_TYPE_P2P_IIHP:
-				iihPdu := rcvMsg.pdu.(*packet.IihPdu)
-				circuit.receiveP2pIih(iihPdu, rcvMsg.from)
-			case packet.PDU_TYPE_LEVEL1_LSP, packet.PDU_TYPE_LEVEL2_LSP:
-				lsPdu := rcvMsg.pdu.(*packet.LsPdu)
-				circuit.receiveLs(lsPdu, rcvMsg.from)
-			case packet.PDU_TYPE_LEVEL1_CSNP, packet.PDU_TYPE_LEVEL2_CSNP,
-				packet.PDU_TYPE_LEVEL1_PSNP, packet.PDU_TYPE_LEVEL2_PSNP:
-				snPdu := rcvMsg.pdu.(*packet.SnPdu)
-				circuit.receiveSn(snPdu, rcvMsg.from)
-			default:
-				log.Debugf("%s: unknown pdu", circuit.name)
-			}
+			go circuit.receive(transport)
+		case CIRCUIT_CH_MSG_STOP:
+			log.Debugf("%s: CIRCUIT_CH_MSG_STOP", circuit.name)
+		case CIRCUIT_CH_MSG_EXIT:
+			log.Debugf("%s: CIRCUIT_CH_MSG_EXIT", circuit.name)
+			goto EXIT
 		}
 	}
 EXIT:
 }
 
+// receive handles the pdus arriving on transport until it is closed.
+func (circuit *Circuit) receive(transport Transport) {
+	buf := make([]byte, 10240)
+	for {
+		n, from, err := transport.Recv(buf)
+		if err != nil {
+			break
+		}
+		var fromb [packet.SYSTEM_ID_LENGTH]byte
+		copy(fromb[0:packet.SYSTEM_ID_LENGTH],
+			from[0:packet.SYSTEM_ID_LENGTH])
+		llc := 0
+		if bytes.Equal(buf[0:3], packet.Llc) {
+			llc = 3
+		}
+		pdu, err := packet.DecodePduFromBytes(buf[0+llc : n])
+		if err != nil {
+			break
+		}
+		circuit.isis.lock.Lock()
+		circuit.isis.trace.pdu(circuit.name, TRACE_DIRECTION_RECV, pdu,
+			buf[0+llc:n], fromb[:], circuit.dst(pdu))
+		circuit.receivePdu(pdu, fromb)
+		circuit.isis.lock.Unlock()
+	}
+}
+
+// receivePdu dispatches pdu received from the SNPA from. The caller holds
+// isis.lock.
+func (circuit *Circuit) receivePdu(pdu packet.IsisPdu, from [packet.SYSTEM_ID_LENGTH]byte) {
+	log.Debugf("enter: %s", circuit.name)
+	defer log.Debugf("exit: %s", circuit.name)
+	if circuit.receiverState != CIRCUIT_CH_STATE_RUNNING {
+		log.Debugf("%s: discard", circuit.name)
+		return
+	}
+	switch pdu.PduType() {
+	case packet.PDU_TYPE_LEVEL1_LAN_IIHP, packet.PDU_TYPE_LEVEL2_LAN_IIHP:
+		iihPdu := pdu.(*packet.IihPdu)
+		circuit.receiveBcastIih(iihPdu, from)
+	case packet.PDU_TYPE_P2P_IIHP:
+		iihPdu := pdu.(*packet.IihPdu)
+		circuit.receiveP2pIih(iihPdu, from)
+	case packet.PDU_TYPE_LEVEL1_LSP, packet.PDU_TYPE_LEVEL2_LSP:
+		lsPdu := pdu.(*packet.LsPdu)
+		circuit.receiveLs(lsPdu, from)
+	case packet.PDU_TYPE_LEVEL1_CSNP, packet.PDU_TYPE_LEVEL2_CSNP,
+		packet.PDU_TYPE_LEVEL1_PSNP, packet.PDU_TYPE_LEVEL2_PSNP:
+		snPdu := pdu.(*packet.SnPdu)
+		circuit.receiveSn(snPdu, from)
+	default:
+		log.Debugf("%s: unknown pdu", circuit.name)
+	}
+}
+
 func (circuit *Circuit) dst(pdu packet.IsisPdu) []byte {
 	log.Debugf("enter: %s", circuit.name)
 	defer log.Debugf("exit: %s", circuit.name)
@@ -706,41 +746,72 @@ func (circuit *Circuit) dst(pdu packet.IsisPdu) []byte {
 	return dst
 }
 
-func (circuit *Circuit) sendPdu(pdu packet.IsisPdu) {
-	log.Debugf("enter: %s", circuit.name)
-	defer log.Debugf("exit: %s", circuit.name)
+// circuitFrame is a pdu encoded for the transport of a circuit, which is
+// sent without isis.lock held.
+type circuitFrame struct {
+	transport Transport
+	name      string
+	data      []byte
+	bcast     bool
+	src       []byte
+	dst       []byte
+	pacing    time.Duration
+}
+
+// newFrame encodes pdu for the current transport. The caller holds
+// isis.lock, as the LSPs of the LSDB are encoded in place.
+func (circuit *Circuit) newFrame(pdu packet.IsisPdu) (*circuitFrame, error) {
 	data, err := pdu.Serialize()
 	if err != nil {
-		log.Infof("Serialize failed")
-		return
-	}
-	buflen := len(data)
-	if circuit.kernelBcast() {
+		return nil, err
+	}
+	return &circuitFrame{
+		transport: circuit.transport,
+		name:      circuit.name,
+		data:      data,
+		bcast:     circuit.kernelBcast(),
+		src:       circuit.kernelHardwareAddr(),
+		dst:       circuit.dst(pdu),
+	}, nil
+}
+
+func (circuit *Circuit) sendFrame(frame *circuitFrame) {
+	log.Debugf("enter: %s", frame.name)
+	defer log.Debugf("exit: %s", frame.name)
+	buflen := len(frame.data)
+	if frame.bcast {
 		buflen += 3
 	}
 	buf := make([]byte, buflen)
-	if circuit.kernelBcast() {
+	if frame.bcast {
 		copy(buf[0:3], packet.Llc)
-		copy(buf[3:], data)
+		copy(buf[3:], frame.data)
 	} else {
-		copy(buf, data)
-	}
-	var dad [8]byte
-	dst := circuit.dst(pdu)
-	copy(dad[0:6], dst[0:6])
-	dad[6] = 0x0
-	dad[7] = 0x0
-	dstaddr := syscall.SockaddrLinklayer{
-		Protocol: htons(uint16(len(buf))),
-		Ifindex:  circuit.ifKernel.IfIndex,
-		Halen:    uint8(6),
-		Addr:     dad,
-	}
-	err = syscall.Sendto(circuit.fd, buf, 0, &dstaddr)
+		copy(buf, frame.data)
+	}
+	if frame.transport == nil {
+		log.Infof("transport not opened")
+		return
+	}
+	err := frame.transport.Send(buf, frame.dst)
 	if err != nil {
-		log.Infof("Sendto failed")
+		log.Infof("Send failed")
 		return
 	}
+	circuit.isis.trace.frame(frame.name, TRACE_DIRECTION_SEND,
+		frame.data, frame.src, frame.dst)
+}
+
+// sendPdu sends pdu right away. The caller holds isis.lock.
+func (circuit *Circuit) sendPdu(pdu packet.IsisPdu) {
+	log.Debugf("enter: %s", circuit.name)
+	defer log.Debugf("exit: %s", circuit.name)
+	frame, err := circuit.newFrame(pdu)
+	if err != nil {
+		log.Infof("Serialize failed")
+		return
+	}
+	circuit.sendFrame(frame)
 }
 
 func (circuit *Circuit) changed() bool {
@@ -757,6 +828,9 @@ func (circuit *Circuit) changed() bool {
 	return changed
 }
 
+// handleStateTransition starts and stops the goroutines of the circuit as
+// its state requires. The caller holds isis.lock; the goroutines are told
+// once it is released.
 func (circuit *Circuit) handleStateTransition() {
 	p2pIihSenderStateOld := circuit.p2pIihSenderState
 	l1lIihSenderStateOld := circuit.l1lIihSenderState
@@ -771,25 +845,25 @@ func (circuit *Circuit) handleStateTransition() {
 		circuit.l2lCsnSenderState == CIRCUIT_CH_STATE_SUSPENDED &&
 		circuit.receiverState == CIRCUIT_CH_STATE_SUSPENDED) &&
 		circuit.ready() {
-		log.Debugf("%s: socket open", circuit.name)
-		fd, err := isisSocket(circuit.ifKernel)
+		log.Debugf("%s: transport open", circuit.name)
+		transport, err := circuit.isis.transport(circuit.ifKernel)
 		if err != nil {
-			s := "%s: socketPacket failed: %v"
+			s := "%s: transport open failed: %v"
 			log.Infof(s, circuit.name, err)
 		}
-		circuit.fd = fd
+		circuit.transport = transport
 	}
 	switch circuit.p2pIihSenderState {
 	case CIRCUIT_CH_STATE_RUNNING:
 		if !circuit.ready() || circuit.configBcast() {
-			circuit.p2pIihSenderCh <- CIRCUIT_CH_MSG_STOP
+			circuit.isis.circuitChSend(circuit.p2pIihSenderCh, CIRCUIT_CH_MSG_STOP)
 			circuit.p2pIihSenderState = CIRCUIT_CH_STATE_SUSPENDED
 			log.Debugf("%s: p2pIihSenderState RUNNING -> SUSPENDED",
 				circuit.name)
 		}
 	case CIRCUIT_CH_STATE_SUSPENDED:
 		if circuit.ready() && !circuit.configBcast() {
-			circuit.p2pIihSenderCh <- CIRCUIT_CH_MSG_START
+			circuit.isis.circuitChSend(circuit.p2pIihSenderCh, CIRCUIT_CH_MSG_START)
 			circuit.p2pIihSenderState = CIRCUIT_CH_STATE_RUNNING
 			log.Debugf("%s: p2pIihSenderState SUSPENDED -> RUNNING",
 				circuit.name)
@@ -798,14 +872,14 @@ func (circuit *Circuit) handleStateTransition() {
 	switch circuit.l1lIihSenderState {
 	case CIRCUIT_CH_STATE_RUNNING:
 		if !circuit.ready() || !circuit.configBcast() || !circuit.level1() {
-			circuit.l1lIihSenderCh <- CIRCUIT_CH_MSG_STOP
+			circuit.isis.circuitChSend(circuit.l1lIihSenderCh, CIRCUIT_CH_MSG_STOP)
 			circuit.l1lIihSenderState = CIRCUIT_CH_STATE_SUSPENDED
 			log.Debugf("%s: l1lIihSenderState RUNNING -> SUSPENDED",
 				circuit.name)
 		}
 	case CIRCUIT_CH_STATE_SUSPENDED:
 		if circuit.ready() && circuit.configBcast() && circuit.level1() {
-			circuit.l1lIihSenderCh <- CIRCUIT_CH_MSG_START
+			circuit.isis.circuitChSend(circuit.l1lIihSenderCh, CIRCUIT_CH_MSG_START)
 			circuit.l1lIihSenderState = CIRCUIT_CH_STATE_RUNNING
 			log.Debugf("%s: l1lIihSenderState SUSPENDED -> RUNNING",
 				circuit.name)
@@ -814,14 +888,14 @@ func (circuit *Circuit) handleStateTransition() {
 	switch circuit.l2lIihSenderState {
 	case CIRCUIT_CH_STATE_RUNNING:
 		if !circuit.ready() || !circuit.configBcast() || !circuit.level2() {
-			circuit.l2lIihSenderCh <- CIRCUIT_CH_MSG_STOP
+			circuit.isis.circuitChSend(circuit.l2lIihSenderCh, CIRCUIT_CH_MSG_STOP)
 			circuit.l2lIihSenderState = CIRCUIT_CH_STATE_SUSPENDED
 			log.Debugf("%s: l2lIihSenderState RUNNING -> SUSPENDED",
 				circuit.name)
 		}
 	case CIRCUIT_CH_STATE_SUSPENDED:
 		if circuit.ready() && circuit.configBcast() && circuit.level2() {
-			circuit.l2lIihSenderCh <- CIRCUIT_CH_MSG_START
+			circuit.isis.circuitChSend(circuit.l2lIihSenderCh, CIRCUIT_CH_MSG_START)
 			circuit.l2lIihSenderState = CIRCUIT_CH_STATE_RUNNING
 			log.Debugf("%s: l2lIihSenderState SUSPENDED -> RUNNING",
 				circuit.name)
@@ -830,14 +904,14 @@ func (circuit *Circuit) handleStateTransition() {
 	switch circuit.l1lCsnSenderState {
 	case CIRCUIT_CH_STATE_RUNNING:
 		if !circuit.ready() || !circuit.configBcast() || !circuit.designated(ISIS_LEVEL_1) {
-			circuit.l1lCsnSenderCh <- CIRCUIT_CH_MSG_STOP
+			circuit.isis.circuitChSend(circuit.l1lCsnSenderCh, CIRCUIT_CH_MSG_STOP)
 			circuit.l1lCsnSenderState = CIRCUIT_CH_STATE_SUSPENDED
 			log.Debugf("%s: l1lCsnSenderState RUNNING -> SUSPENDED",
 				circuit.name)
 		}
 	case CIRCUIT_CH_STATE_SUSPENDED:
 		if circuit.ready() && circuit.configBcast() && circuit.designated(ISIS_LEVEL_1) {
-			circuit.l1lCsnSenderCh <- CIRCUIT_CH_MSG_START
+			circuit.isis.circuitChSend(circuit.l1lCsnSenderCh, CIRCUIT_CH_MSG_START)
 			circuit.l1lCsnSenderState = CIRCUIT_CH_STATE_RUNNING
 			log.Debugf("%s: l1lCsnSenderState SUSPENDED -> RUNNING",
 				circuit.name)
@@ -846,14 +920,14 @@ func (circuit *Circuit) handleStateTransition() {
 	switch circuit.l2lCsnSenderState {
 	case CIRCUIT_CH_STATE_RUNNING:
 		if !circuit.ready() || !circuit.configBcast() || !circuit.designated(ISIS_LEVEL_2) {
-			circuit.l2lCsnSenderCh <- CIRCUIT_CH_MSG_STOP
+			circuit.isis.circuitChSend(circuit.l2lCsnSenderCh, CIRCUIT_CH_MSG_STOP)
 			circuit.l2lCsnSenderState = CIRCUIT_CH_STATE_SUSPENDED
 			log.Debugf("%s: l2lCsnSenderState RUNNING -> SUSPENDED",
 				circuit.name)
 		}
 	case CIRCUIT_CH_STATE_SUSPENDED:
 		if circuit.ready() && circuit.configBcast() && circuit.designated(ISIS_LEVEL_2) {
-			circuit.l2lCsnSenderCh <- CIRCUIT_CH_MSG_START
+			circuit.isis.circuitChSend(circuit.l2lCsnSenderCh, CIRCUIT_CH_MSG_START)
 			circuit.l2lCsnSenderState = CIRCUIT_CH_STATE_RUNNING
 			log.Debugf("%s: l2lCsnSenderState SUSPENDED -> RUNNING",
 				circuit.name)
@@ -862,14 +936,14 @@ func (circuit *Circuit) handleStateTransition() {
 	switch circuit.receiverState {
 	case CIRCUIT_CH_STATE_RUNNING:
 		if !circuit.ready() {
-			circuit.receiverCh <- CIRCUIT_CH_MSG_STOP
+			circuit.isis.circuitChSend(circuit.receiverCh, CIRCUIT_CH_MSG_STOP)
 			circuit.receiverState = CIRCUIT_CH_STATE_SUSPENDED
 			log.Debugf("%s: receiverState RUNNING -> SUSPENDED",
 				circuit.name)
 		}
 	case CIRCUIT_CH_STATE_SUSPENDED:
 		if circuit.ready() {
-			circuit.receiverCh <- CIRCUIT_CH_MSG_START
+			circuit.isis.circuitChSend(circuit.receiverCh, CIRCUIT_CH_MSG_START)
 			circuit.receiverState = CIRCUIT_CH_STATE_RUNNING
 			log.Debugf("%s: receiverState SUSPENDED -> RUNNING",
 				circuit.name)
@@ -882,8 +956,11 @@ func (circuit *Circuit) handleStateTransition() {
 		l2lCsnSenderStateOld == CIRCUIT_CH_STATE_RUNNING ||
 		receiverStateOld == CIRCUIT_CH_STATE_RUNNING) &&
 		!circuit.ready() {
-		log.Debugf("%s: socket close", circuit.name)
-		syscall.Close(circuit.fd)
+		log.Debugf("%s: transport close", circuit.name)
+		if circuit.transport != nil {
+			circuit.transport.Close()
+			circuit.transport = nil
+		}
 	}
 }
 
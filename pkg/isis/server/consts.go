@@ -22,6 +22,8 @@ import (
 )
 
 const (
-	MAX_PATH_METRIC   = 0xfe000000
-	ZERO_AGE_LIFETIME = time.Minute * 1
+	MAX_PATH_METRIC    = 0xfe000000
+	MAX_NARROW_METRIC  = 63
+	ZERO_AGE_LIFETIME  = time.Minute * 1
+	DEFAULT_PREFERENCE = 115
 )
@@ -216,10 +216,17 @@ func (l *spfDistance) Add(r *spfDistance) *spfDistance {
 	return l
 }
 
+// external of a prefix triple tells whether the prefix is reached by
+// an external reachability, which loses to an internal one of the same
+// distance. down and tags are the up/down bit and the tags of the
+// reachability the distance comes from.
 type spfTriple struct {
 	id          *spfId
 	distance    *spfDistance
 	adjacencies []*Adjacency
+	external    bool
+	down        bool
+	tags        []uint32
 }
 
 func NewSpfTriple(id *spfId, distance *spfDistance) *spfTriple {
@@ -326,10 +333,14 @@ type Ipv4Nh struct {
 }
 
 type Ipv4Ri struct {
+	level         IsisLevel
 	prefixAddress uint32
 	prefixLength  uint8
 	nexthops      []*Ipv4Nh
 	metric        uint32
+	external      bool
+	down          bool
+	tags          []uint32
 }
 
 type Ipv6Nh struct {
@@ -338,16 +349,86 @@ type Ipv6Nh struct {
 }
 
 type Ipv6Ri struct {
+	level         IsisLevel
 	prefixAddress [4]uint32
 	prefixLength  uint8
 	nexthops      []*Ipv6Nh
 	metric        uint32
+	external      bool
+	down          bool
+	tags          []uint32
+}
+
+type spfAdjacency struct {
+	adjacency *Adjacency
+	metric    uint32
+}
+
+// spfSource is what the route calculation needs to know about the world.
+// IsisServer answers from its circuits and LSDB, OfflineLsdb from LSPs only.
+type spfSource interface {
+	spfRootId() [packet.NEIGHBOUR_ID_LENGTH]byte
+	spfAdjacencies(level IsisLevel) []*spfAdjacency
+	spfReachabilities(level IsisLevel, nodeId [packet.NEIGHBOUR_ID_LENGTH]byte) *Reachabilities
+}
+
+func (isis *IsisServer) spfRootId() [packet.NEIGHBOUR_ID_LENGTH]byte {
+	rootId := [packet.NEIGHBOUR_ID_LENGTH]byte{}
+	copy(rootId[0:packet.SYSTEM_ID_LENGTH], isis.systemId[0:packet.SYSTEM_ID_LENGTH])
+	return rootId
+}
+
+func (isis *IsisServer) spfAdjacencies(level IsisLevel) []*spfAdjacency {
+	log.Debugf("enter: %s", level)
+	defer log.Debugf("exit: %s", level)
+	adjacencies := make([]*spfAdjacency, 0)
+	for _, circuit := range isis.circuitDb {
+		for _, adjacency := range circuit.adjacencyDb {
+			if adjacency.adjState != packet.ADJ_3WAY_STATE_UP {
+				continue
+			}
+			if !adjacency.level(level) {
+				continue
+			}
+			adjacencies = append(adjacencies, &spfAdjacency{
+				adjacency: adjacency,
+				metric:    circuit.metric(level),
+			})
+		}
+	}
+	return adjacencies
+}
+
+func (isis *IsisServer) spfReachabilities(level IsisLevel, nodeId [packet.NEIGHBOUR_ID_LENGTH]byte) *Reachabilities {
+	return isis.getReachabilities(level, nodeId)
 }
 
 func (isis *IsisServer) spf(level IsisLevel, cancelSpfCh, doneSpfCh chan struct{}) {
 	log.Debugf("enter: %s", level)
 	defer log.Debugf("exit: %s", level)
 
+	isis.lock.RLock()
+	paths := spfCalc(level, isis)
+	isis.lock.RUnlock()
+
+	select {
+	case <-cancelSpfCh:
+		log.Debugf("INSERT FIB CANCELED: %s", level)
+		goto CANCEL
+	default:
+	}
+
+	log.Debugf("INSERT FIB HERE: %s", level)
+	isis.updateRiDb(level, paths)
+
+CANCEL:
+	doneSpfCh <- struct{}{}
+}
+
+func spfCalc(level IsisLevel, src spfSource) *spfTriples {
+	log.Debugf("enter: %s", level)
+	defer log.Debugf("exit: %s", level)
+
 	// rfc1195 p.55 Step0
 	paths := NewSpfTriples()
 	tent := NewSpfTriples()
@@ -356,37 +437,28 @@ func (isis *IsisServer) spf(level IsisLevel, cancelSpfCh, doneSpfCh chan struct{
 	var r *Reachabilities
 	step := 0
 
-	isis.debugPrint(level, paths, tent, &step, "before Step0 1)")
+	debugPrint(level, paths, tent, &step, "before Step0 1)")
 
 	// rfc1195 p.55 Step0 1)
-	selfId := [packet.NEIGHBOUR_ID_LENGTH]byte{}
-	copy(selfId[0:packet.SYSTEM_ID_LENGTH], isis.systemId[0:packet.SYSTEM_ID_LENGTH])
-	self := NewSpfTriple(NewSpfIdNode(selfId), NewSpfDistance(0, 0))
+	self := NewSpfTriple(NewSpfIdNode(src.spfRootId()), NewSpfDistance(0, 0))
 	paths.addTriple(self)
 
-	isis.debugPrint(level, paths, tent, &step, "before Step0 2)")
+	debugPrint(level, paths, tent, &step, "before Step0 2)")
 
 	// rfc1195 p.55 Step0 2)
-	for _, circuit := range isis.circuitDb {
-		for _, adjacency := range circuit.adjacencyDb {
-			if adjacency.adjState != packet.ADJ_3WAY_STATE_UP {
-				continue
-			}
-			if !adjacency.level(level) {
-				continue
-			}
-			d := NewSpfDistance(circuit.metric(level), 0)
-			nodeId := [packet.NEIGHBOUR_ID_LENGTH]byte{}
-			copy(nodeId[0:packet.SYSTEM_ID_LENGTH], adjacency.systemId[0:packet.SYSTEM_ID_LENGTH])
-			triple := tent.findOrNewTriple(NewSpfIdNode(nodeId))
-			if triple.distance.Equal(d) {
-				triple.addAdjacency(adjacency)
-				tent.addTriple(triple)
-			} else if !triple.distance.Less(d) {
-				triple.distance = d
-				triple.adjacencies = []*Adjacency{adjacency}
-				tent.addTriple(triple)
-			}
+	for _, sa := range src.spfAdjacencies(level) {
+		adjacency := sa.adjacency
+		d := NewSpfDistance(sa.metric, 0)
+		nodeId := [packet.NEIGHBOUR_ID_LENGTH]byte{}
+		copy(nodeId[0:packet.SYSTEM_ID_LENGTH], adjacency.systemId[0:packet.SYSTEM_ID_LENGTH])
+		triple := tent.findOrNewTriple(NewSpfIdNode(nodeId))
+		if triple.distance.Equal(d) {
+			triple.addAdjacency(adjacency)
+			tent.addTriple(triple)
+		} else if !triple.distance.Less(d) {
+			triple.distance = d
+			triple.adjacencies = []*Adjacency{adjacency}
+			tent.addTriple(triple)
 		}
 	}
 	// rfc1195 p.55 Step0 8) 9)
@@ -394,13 +466,13 @@ func (isis *IsisServer) spf(level IsisLevel, cancelSpfCh, doneSpfCh chan struct{
 	goto STEP2
 
 STEP1:
-	isis.debugPrint(level, paths, tent, &step, "STEP1")
+	debugPrint(level, paths, tent, &step, "STEP1")
 	// rfc1195 p.56 Step1
 	if tmp == nil || tmp.id.idType != SPF_ID_TYPE_NODE {
 		panic("")
 	}
 	log.Debugf("%s: tmp.id.nodeId = %x", level, tmp.id.nodeId)
-	r = isis.getReachabilities(level, tmp.id.nodeId)
+	r = src.spfReachabilities(level, tmp.id.nodeId)
 	if r == nil {
 		log.Debugf("Reachabilities nil: %s", level)
 		goto STEP2
@@ -445,13 +517,18 @@ STEP1:
 			continue
 		}
 		triple := tent.findOrNewTriple(node)
-		if triple.distance.Equal(d) {
+		if triple.distance.Equal(d) && triple.external == isr.external {
 			for _, adj := range tmp.adjacencies {
 				triple.addAdjacency(adj)
 			}
 			tent.addTriple(triple)
+		} else if triple.distance.Equal(d) && isr.external {
+			continue
 		} else if !triple.distance.Less(d) {
 			triple.distance = d
+			triple.external = isr.external
+			triple.down = isr.down
+			triple.tags = isr.tags
 			triple.adjacencies = []*Adjacency{}
 			for _, adj := range tmp.adjacencies {
 				triple.addAdjacency(adj)
@@ -470,13 +547,18 @@ STEP1:
 			continue
 		}
 		triple := tent.findOrNewTriple(node)
-		if triple.distance.Equal(d) {
+		if triple.distance.Equal(d) && triple.external == isr.external {
 			for _, adj := range tmp.adjacencies {
 				triple.addAdjacency(adj)
 			}
 			tent.addTriple(triple)
+		} else if triple.distance.Equal(d) && isr.external {
+			continue
 		} else if !triple.distance.Less(d) {
 			triple.distance = d
+			triple.external = isr.external
+			triple.down = isr.down
+			triple.tags = isr.tags
 			triple.adjacencies = []*Adjacency{}
 			for _, adj := range tmp.adjacencies {
 				triple.addAdjacency(adj)
@@ -486,7 +568,7 @@ STEP1:
 	}
 
 STEP2:
-	isis.debugPrint(level, paths, tent, &step, "STEP2")
+	debugPrint(level, paths, tent, &step, "STEP2")
 	// rfc1195 p.57 Step2
 	if len(tent.triples) == 0 {
 		goto DONE
@@ -511,23 +593,12 @@ STEP2:
 		goto STEP1
 	}
 DONE:
-	isis.debugPrint(level, paths, tent, &step, "DONE")
-
-	select {
-	case <-cancelSpfCh:
-		log.Debugf("INSERT FIB CANCELED: %s", level)
-		goto CANCEL
-	default:
-	}
-
-	log.Debugf("INSERT FIB HERE: %s", level)
-	isis.updateRiDb(level, paths)
+	debugPrint(level, paths, tent, &step, "DONE")
 
-CANCEL:
-	doneSpfCh <- struct{}{}
+	return paths
 }
 
-func (isis *IsisServer) debugPrint(level IsisLevel, paths, tent *spfTriples, step *int, label string) {
+func debugPrint(level IsisLevel, paths, tent *spfTriples, step *int, label string) {
 	log.Debugf("%s: STEP%d paths: %s", level, (*step), label)
 	for _, triple := range paths.triples {
 		log.Debugf("%s:     %s (%d, %d)",
@@ -540,7 +611,9 @@ func (isis *IsisServer) debugPrint(level IsisLevel, paths, tent *spfTriples, ste
 			for _, v6 := range adj.ipv6Addresses {
 				log.Debugf("%s:         %08x:%08x:%08x:%08x", level, v6[0], v6[1], v6[2], v6[3])
 			}
-			log.Debugf("%s:         %s", level, adj.circuit.name)
+			if adj.circuit != nil {
+				log.Debugf("%s:         %s", level, adj.circuit.name)
+			}
 		}
 	}
 	log.Debugf("%s: STEP%d tent:", level, (*step))
@@ -555,22 +628,36 @@ func (isis *IsisServer) debugPrint(level IsisLevel, paths, tent *spfTriples, ste
 			for _, v6 := range adj.ipv6Addresses {
 				log.Debugf("%s:         %08x:%08x:%08x:%08x", level, v6[0], v6[1], v6[2], v6[3])
 			}
-			log.Debugf("%s:         %s", level, adj.circuit.name)
+			if adj.circuit != nil {
+				log.Debugf("%s:         %s", level, adj.circuit.name)
+			}
 		}
 	}
 	(*step)++
 }
 
 func (isis *IsisServer) updateRiDb(level IsisLevel, paths *spfTriples) {
+	ipv4RiDb, ipv6RiDb := newRiDbs(level, paths)
+	isis.lock.Lock()
+	isis.ipv4RiDb[level] = ipv4RiDb
+	isis.ipv6RiDb[level] = ipv6RiDb
+	isis.lock.Unlock()
+}
+
+func newRiDbs(level IsisLevel, paths *spfTriples) (map[[SPF_ID_KEY_LENGTH]byte]*Ipv4Ri, map[[SPF_ID_KEY_LENGTH]byte]*Ipv6Ri) {
 	ipv4RiDb := make(map[[SPF_ID_KEY_LENGTH]byte]*Ipv4Ri)
 	ipv6RiDb := make(map[[SPF_ID_KEY_LENGTH]byte]*Ipv6Ri)
 	for _, triple := range paths.triples {
 		if triple.id.idType == SPF_ID_TYPE_IPV4 {
 			ipv4Ri := &Ipv4Ri{
+				level:         level,
 				prefixAddress: triple.id.ipv4PrefixAddress,
 				prefixLength:  triple.id.ipv4PrefixLength,
 				nexthops:      make([]*Ipv4Nh, 0),
 				metric:        triple.distance.internal,
+				external:      triple.external,
+				down:          triple.down,
+				tags:          triple.tags,
 			}
 			for _, adj := range triple.adjacencies {
 				var nha *uint32
@@ -593,6 +680,7 @@ func (isis *IsisServer) updateRiDb(level IsisLevel, paths *spfTriples) {
 		}
 		if triple.id.idType == SPF_ID_TYPE_IPV6 {
 			ipv6Ri := &Ipv6Ri{
+				level: level,
 				prefixAddress: [4]uint32{
 					triple.id.ipv6PrefixAddress[0],
 					triple.id.ipv6PrefixAddress[1],
@@ -602,6 +690,9 @@ func (isis *IsisServer) updateRiDb(level IsisLevel, paths *spfTriples) {
 				prefixLength: triple.id.ipv6PrefixLength,
 				nexthops:     make([]*Ipv6Nh, 0),
 				metric:       triple.distance.internal,
+				external:     triple.external,
+				down:         triple.down,
+				tags:         triple.tags,
 			}
 			for _, adj := range triple.adjacencies {
 				var nha *[4]uint32
@@ -623,10 +714,7 @@ func (isis *IsisServer) updateRiDb(level IsisLevel, paths *spfTriples) {
 			ipv6RiDb[triple.id.key()] = ipv6Ri
 		}
 	}
-	isis.lock.Lock()
-	isis.ipv4RiDb[level] = ipv4RiDb
-	isis.ipv6RiDb[level] = ipv6RiDb
-	isis.lock.Unlock()
+	return ipv4RiDb, ipv6RiDb
 }
 
 func (isis *IsisServer) routeCalculator(level IsisLevel, doCh chan struct{}, doneCh chan struct{}) {
@@ -664,10 +752,11 @@ func (isis *IsisServer) decisionChSend(msg *DecisionChMsg) {
 	go func() {
 		decisionChSendCountLock.Lock()
 		decisionChSendCount++
+		count := decisionChSendCount
 		decisionChSendCountLock.Unlock()
-		log.Debugf("decisionChSend[%d]: begin", decisionChSendCount)
+		log.Debugf("decisionChSend[%d]: begin", count)
 		isis.decisionCh <- msg
-		log.Debugf("decisionChSend[%d]: end", decisionChSendCount)
+		log.Debugf("decisionChSend[%d]: end", count)
 	}()
 }
 
@@ -714,6 +803,17 @@ func (isis *IsisServer) decisionProcess() {
 					goto REDO
 				}
 			}
+			isis.bgplsUpdate()
+			isis.fibUpdate()
+			isis.lock.RLock()
+			levelAll := isis.levelAll()
+			isis.lock.RUnlock()
+			if levelAll {
+				// the routes leaked between the levels follow them
+				isis.updateChSend(&UpdateChMsg{
+					msgType: UPDATE_CH_MSG_TYPE_ROUTES_CHANGED,
+				})
+			}
 		case DECISION_CH_MSG_TYPE_EXIT:
 			goto EXIT
 		}
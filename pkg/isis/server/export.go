@@ -0,0 +1,237 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/m-asama/golsr/pkg/isis/packet"
+)
+
+const LSDB_EXPORT_VERSION = 1
+
+/*
+	LSDB export
+
+	{
+	  "version": 1,
+	  "system_id": "xxxx.xxxx.xxxx",
+	  "time": "RFC 3339",
+	  "lsps": [
+	    { "level": "level-1", "binary": "<base64>", "pdu": { LsPdu } },
+	    ...
+	  ]
+	}
+
+	binary is the LSP as it is stored in the LSDB and pdu is the same LSP
+	decoded (see pkg/isis/packet/json.go). On import binary wins, pdu is
+	only used when binary is absent, so that an LSP can be written or
+	edited by hand.
+*/
+
+type LsdbExport struct {
+	Version  int              `json:"version"`
+	SystemId string           `json:"system_id"`
+	Time     time.Time        `json:"time"`
+	Lsps     []*LsdbExportLsp `json:"lsps"`
+}
+
+type LsdbExportLsp struct {
+	Level  string        `json:"level"`
+	Binary []byte        `json:"binary,omitempty"`
+	Pdu    *packet.LsPdu `json:"pdu,omitempty"`
+}
+
+func newLsdbExportLsp(level IsisLevel, pdu *packet.LsPdu) (*LsdbExportLsp, error) {
+	binary, err := pdu.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	return &LsdbExportLsp{
+		Level:  level.String2(),
+		Binary: binary,
+		Pdu:    pdu,
+	}, nil
+}
+
+// Lsp returns the LSP carried by the entry and checks that it belongs to
+// the level the entry claims.
+func (e *LsdbExportLsp) Lsp() (*packet.LsPdu, error) {
+	var lsp *packet.LsPdu
+	if len(e.Binary) > 0 {
+		pdu, err := packet.DecodePduFromBytes(e.Binary)
+		if err != nil {
+			return nil, err
+		}
+		var ok bool
+		lsp, ok = pdu.(*packet.LsPdu)
+		if !ok {
+			return nil, errors.New("LsdbExportLsp.Lsp: not a lsp " + pdu.PduType().String())
+		}
+	} else if e.Pdu != nil {
+		lsp = e.Pdu
+		if err := lsp.SetChecksum(); err != nil {
+			return nil, err
+		}
+	} else {
+		return nil, errors.New("LsdbExportLsp.Lsp: neither binary nor pdu")
+	}
+	var pduType packet.PduType
+	switch e.Level {
+	case ISIS_LEVEL_1.String2():
+		pduType = packet.PDU_TYPE_LEVEL1_LSP
+	case ISIS_LEVEL_2.String2():
+		pduType = packet.PDU_TYPE_LEVEL2_LSP
+	default:
+		return nil, errors.New("LsdbExportLsp.Lsp: level invalid " + e.Level)
+	}
+	if lsp.PduType() != pduType {
+		return nil, errors.New("LsdbExportLsp.Lsp: level mismatch " + e.Level)
+	}
+	return lsp, nil
+}
+
+// ParseLsdbExport returns the LSPs of an export in file order.
+func ParseLsdbExport(data []byte) ([]*packet.LsPdu, error) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	var export LsdbExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, err
+	}
+	if export.Version != LSDB_EXPORT_VERSION {
+		return nil, errors.New(fmt.Sprintf("ParseLsdbExport: version %d not supported", export.Version))
+	}
+	lsps := make([]*packet.LsPdu, 0)
+	for _, e := range export.Lsps {
+		lsp, err := e.Lsp()
+		if err != nil {
+			return nil, err
+		}
+		lsps = append(lsps, lsp)
+	}
+	return lsps, nil
+}
+
+// ExportLsdb returns the LSDB of levels as an indented LsdbExport.
+func (isis *IsisServer) ExportLsdb(levels []IsisLevel) ([]byte, error) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	// encoding the LSPs updates their length fields
+	isis.lock.Lock()
+	defer isis.lock.Unlock()
+	export := &LsdbExport{
+		Version: LSDB_EXPORT_VERSION,
+		SystemId: fmt.Sprintf("%02x%02x.%02x%02x.%02x%02x",
+			isis.systemId[0], isis.systemId[1], isis.systemId[2],
+			isis.systemId[3], isis.systemId[4], isis.systemId[5]),
+		Time: time.Now(),
+		Lsps: make([]*LsdbExportLsp, 0),
+	}
+	for _, level := range levels {
+		for _, ls := range isis.lsDb[level] {
+			e, err := newLsdbExportLsp(level, ls.pdu)
+			if err != nil {
+				return nil, err
+			}
+			export.Lsps = append(export.Lsps, e)
+		}
+	}
+	return json.MarshalIndent(export, "", "  ")
+}
+
+// ExportLsdbFile writes the whole LSDB to filename. The file is replaced
+// atomically so that a reader never sees a partial export.
+func (isis *IsisServer) ExportLsdbFile(filename string) error {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	data, err := isis.ExportLsdb(ISIS_LEVEL_ALL)
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(filename), filepath.Base(filename)+".")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Close()
+	} else {
+		tmp.Close()
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), filename)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
+
+func (isis *IsisServer) lsdbEmpty() bool {
+	isis.lock.RLock()
+	defer isis.lock.RUnlock()
+	for _, level := range ISIS_LEVEL_ALL {
+		if len(isis.lsDb[level]) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// SetLsdbExport makes the periodic process export the LSDB to filename
+// every interval seconds.
+func (isis *IsisServer) SetLsdbExport(filename string, interval int) error {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	if interval <= 0 {
+		return errors.New("IsisServer.SetLsdbExport: interval invalid")
+	}
+	isis.lsdbExportFile = filename
+	isis.lsdbExportInterval = interval
+	return nil
+}
+
+func (isis *IsisServer) lsdbExportWalk(counter int) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	// the first tick comes before any LSP is originated or received and
+	// would replace the last export with an empty LSDB
+	if isis.lsdbExportFile == "" || counter == 0 || counter%isis.lsdbExportInterval != 0 {
+		return
+	}
+	if isis.lsdbEmpty() {
+		return
+	}
+	err := isis.ExportLsdbFile(isis.lsdbExportFile)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"Topic": "Export",
+			"Key":   isis.lsdbExportFile,
+			"Error": err,
+		}).Warn("lsdb export failed")
+	}
+}
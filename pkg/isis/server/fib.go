@@ -0,0 +1,243 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+	"reflect"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/m-asama/golsr/internal/pkg/kernel"
+)
+
+// SetKernelProvider replaces where interfaces are read from and routes are
+// installed to. It must be called before Serve.
+func (isis *IsisServer) SetKernelProvider(provider kernel.Provider) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	isis.provider = provider
+	if status, err := provider.Status(); err == nil {
+		isis.kernel = status
+	}
+}
+
+// SetVrf restricts the circuits to the interfaces enslaved to the VRF
+// device name and installs the routes into its table. An empty name is
+// the default VRF. It must be called before Serve.
+func (isis *IsisServer) SetVrf(name string) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	isis.vrf = name
+}
+
+// eligible reports whether iface may be a circuit, that is whether it is
+// in our VRF.
+func (isis *IsisServer) eligible(iface *kernel.Interface) bool {
+	return iface.Vrf == isis.vrf
+}
+
+// fibTable returns the table the routes are installed into, or -1 if our
+// VRF does not exist.
+func (isis *IsisServer) fibTable() int {
+	if isis.vrf == "" {
+		return 0
+	}
+	if vrf := isis.kernel.Vrf(isis.vrf); vrf != nil {
+		return vrf.Table
+	}
+	return -1
+}
+
+// SetFib enables or disables installing the routes into the kernel, which
+// is disabled by default.
+func (isis *IsisServer) SetFib(enable bool) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	isis.fibLock.Lock()
+	defer isis.fibLock.Unlock()
+	isis.fibEnable = enable
+}
+
+func ipv4FibKey(route *kernel.Ipv4Route) string {
+	return fmt.Sprintf("%d/%08x/%d", route.Table, route.Prefix, route.PrefixLength)
+}
+
+func ipv6FibKey(route *kernel.Ipv6Route) string {
+	return fmt.Sprintf("%d/%08x%08x%08x%08x/%d", route.Table,
+		route.Prefix[0], route.Prefix[1], route.Prefix[2], route.Prefix[3], route.PrefixLength)
+}
+
+func newFibIpv4Route(table int, ri *Ipv4Ri) *kernel.Ipv4Route {
+	route := &kernel.Ipv4Route{
+		Table:        table,
+		Protocol:     kernel.ROUTE_PROTOCOL_ISIS,
+		Prefix:       ri.prefixAddress,
+		PrefixLength: int(ri.prefixLength),
+		Metric:       ri.metric,
+		NextHops:     make([]*kernel.Ipv4NextHop, 0),
+	}
+	for _, nh := range ri.nexthops {
+		if nh.nexthopInterface == nil {
+			continue
+		}
+		route.NextHops = append(route.NextHops, &kernel.Ipv4NextHop{
+			Address: nh.nexthopAddress,
+			IfIndex: nh.nexthopInterface.ifKernel.IfIndex,
+		})
+	}
+	return route
+}
+
+func newFibIpv6Route(table int, ri *Ipv6Ri) *kernel.Ipv6Route {
+	route := &kernel.Ipv6Route{
+		Table:        table,
+		Protocol:     kernel.ROUTE_PROTOCOL_ISIS,
+		Prefix:       ri.prefixAddress,
+		PrefixLength: int(ri.prefixLength),
+		Metric:       ri.metric,
+		NextHops:     make([]*kernel.Ipv6NextHop, 0),
+	}
+	for _, nh := range ri.nexthops {
+		if nh.nexthopInterface == nil {
+			continue
+		}
+		route.NextHops = append(route.NextHops, &kernel.Ipv6NextHop{
+			Address: nh.nexthopAddress,
+			IfIndex: nh.nexthopInterface.ifKernel.IfIndex,
+		})
+	}
+	return route
+}
+
+// fibRoutes returns the routes to be installed, the best of the local
+// RIB the install policies accept with the metric they set. Prefixes
+// without next hop, which are our own, are left out.
+func (isis *IsisServer) fibRoutes() (map[string]*kernel.Ipv4Route, map[string]*kernel.Ipv6Route) {
+	ipv4Routes := make(map[string]*kernel.Ipv4Route)
+	ipv6Routes := make(map[string]*kernel.Ipv6Route)
+	isis.lock.RLock()
+	defer isis.lock.RUnlock()
+	table := isis.fibTable()
+	if table < 0 {
+		log.WithFields(log.Fields{
+			"Topic": "Fib",
+			"Vrf":   isis.vrf,
+		}).Warn("No such vrf")
+		return ipv4Routes, ipv6Routes
+	}
+	for _, ri := range isis.ipv4Rib() {
+		route := newFibIpv4Route(table, ri)
+		if len(route.NextHops) == 0 {
+			continue
+		}
+		policyRoute := newPolicyRouteIpv4(ri)
+		if isis.installPolicy(policyRoute) != POLICY_ACTION_ACCEPT_ROUTE {
+			continue
+		}
+		route.Metric = policyRoute.metric
+		route.Distance = isis.preference(ri.external)
+		ipv4Routes[ipv4FibKey(route)] = route
+	}
+	for _, ri := range isis.ipv6Rib() {
+		route := newFibIpv6Route(table, ri)
+		if len(route.NextHops) == 0 {
+			continue
+		}
+		policyRoute := newPolicyRouteIpv6(ri)
+		if isis.installPolicy(policyRoute) != POLICY_ACTION_ACCEPT_ROUTE {
+			continue
+		}
+		route.Metric = policyRoute.metric
+		route.Distance = isis.preference(ri.external)
+		ipv6Routes[ipv6FibKey(route)] = route
+	}
+	return ipv4Routes, ipv6Routes
+}
+
+// fibUpdate brings the routes installed in the kernel in line with the
+// result of the last SPF run.
+func (isis *IsisServer) fibUpdate() {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	isis.fibLock.Lock()
+	defer isis.fibLock.Unlock()
+	ipv4Routes := make(map[string]*kernel.Ipv4Route)
+	ipv6Routes := make(map[string]*kernel.Ipv6Route)
+	if isis.fibEnable {
+		ipv4Routes, ipv6Routes = isis.fibRoutes()
+	}
+	for key, route := range isis.fibIpv4Routes {
+		// the kernel keeps a route of another metric beside the
+		// installed one instead of replacing it
+		if r, ok := ipv4Routes[key]; ok && r.Metric == route.Metric {
+			continue
+		}
+		if err := isis.provider.DeleteIpv4Route(route); err != nil {
+			log.WithFields(log.Fields{
+				"Topic": "Fib",
+				"Route": key,
+				"Error": err,
+			}).Warn("Can't delete route")
+		}
+		delete(isis.fibIpv4Routes, key)
+	}
+	for key, route := range ipv4Routes {
+		if installed, ok := isis.fibIpv4Routes[key]; ok && reflect.DeepEqual(installed, route) {
+			continue
+		}
+		if err := isis.provider.AddIpv4Route(route); err != nil {
+			log.WithFields(log.Fields{
+				"Topic": "Fib",
+				"Route": key,
+				"Error": err,
+			}).Warn("Can't add route")
+			continue
+		}
+		isis.fibIpv4Routes[key] = route
+	}
+	for key, route := range isis.fibIpv6Routes {
+		// the kernel keeps a route of another metric beside the
+		// installed one instead of replacing it
+		if r, ok := ipv6Routes[key]; ok && r.Metric == route.Metric {
+			continue
+		}
+		if err := isis.provider.DeleteIpv6Route(route); err != nil {
+			log.WithFields(log.Fields{
+				"Topic": "Fib",
+				"Route": key,
+				"Error": err,
+			}).Warn("Can't delete route")
+		}
+		delete(isis.fibIpv6Routes, key)
+	}
+	for key, route := range ipv6Routes {
+		if installed, ok := isis.fibIpv6Routes[key]; ok && reflect.DeepEqual(installed, route) {
+			continue
+		}
+		if err := isis.provider.AddIpv6Route(route); err != nil {
+			log.WithFields(log.Fields{
+				"Topic": "Fib",
+				"Route": key,
+				"Error": err,
+			}).Warn("Can't add route")
+			continue
+		}
+		isis.fibIpv6Routes[key] = route
+	}
+}
@@ -0,0 +1,146 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"testing"
+
+	"github.com/m-asama/golsr/internal/pkg/isis/config"
+	"github.com/m-asama/golsr/internal/pkg/kernel"
+)
+
+func TestFibVrf(t *testing.T) {
+	fake := kernel.NewFakeProvider()
+	fake.SetVrf(&kernel.Vrf{IfIndex: 10, Name: "blue", Table: 100})
+	fake.SetInterface(&kernel.Interface{
+		IfIndex:     2,
+		Name:        "eth0",
+		IfType:      kernel.IF_TYPE_POINTTOPOINT,
+		Up:          true,
+		MasterIndex: 10,
+	})
+	fake.SetInterface(&kernel.Interface{
+		IfIndex: 3,
+		Name:    "eth1",
+		IfType:  kernel.IF_TYPE_POINTTOPOINT,
+		Up:      true,
+	})
+	isis := NewIsisServer("", "")
+	isis.SetKernelProvider(fake)
+	isis.SetVrf("blue")
+	isis.SetFib(true)
+
+	// only the interfaces in the VRF may be circuits
+	eth0 := isis.getIfKernelByName("eth0")
+	if eth0 == nil || eth0.Vrf != "blue" {
+		t.Fatalf("failed eth0: %#v", eth0)
+	}
+	if eth1 := isis.getIfKernelByName("eth1"); eth1 != nil {
+		t.Fatalf("failed eth1: %#v", eth1)
+	}
+
+	var key [SPF_ID_KEY_LENGTH]byte
+	isis.ipv4RiDb[ISIS_LEVEL_2][key] = &Ipv4Ri{
+		prefixAddress: 0xc0a80002,
+		prefixLength:  32,
+		metric:        20,
+		nexthops: []*Ipv4Nh{
+			&Ipv4Nh{nexthopAddress: 0x0a000002, nexthopInterface: &Circuit{ifKernel: eth0}},
+		},
+	}
+	isis.fibUpdate()
+	routes := fake.Ipv4Routes()
+	if len(routes) != 1 || routes[0].Table != 100 || routes[0].NextHops[0].IfIndex != 2 ||
+		routes[0].Distance != DEFAULT_PREFERENCE {
+		t.Fatalf("failed routes: %#v", routes)
+	}
+
+	// a new metric replaces the route installed
+	isis.ipv4RiDb[ISIS_LEVEL_2][key].metric = 30
+	isis.fibUpdate()
+	if routes := fake.Ipv4Routes(); len(routes) != 1 || routes[0].Metric != 30 {
+		t.Fatalf("failed routes after metric change: %#v", routes)
+	}
+
+	isis.SetFib(false)
+	isis.fibUpdate()
+	if routes := fake.Ipv4Routes(); len(routes) != 0 {
+		t.Fatalf("failed routes after SetFib(false): %#v", routes)
+	}
+}
+
+func TestFibRib(t *testing.T) {
+	fake := kernel.NewFakeProvider()
+	fake.SetInterface(&kernel.Interface{IfIndex: 2, Name: "eth0", IfType: kernel.IF_TYPE_POINTTOPOINT, Up: true})
+	fake.SetInterface(&kernel.Interface{IfIndex: 3, Name: "eth1", IfType: kernel.IF_TYPE_POINTTOPOINT, Up: true})
+	isis := NewIsisServer("", "")
+	isis.SetKernelProvider(fake)
+	isis.SetFib(true)
+	isis.config = &config.IsisConfig{}
+	eth0 := isis.getIfKernelByName("eth0")
+	eth1 := isis.getIfKernelByName("eth1")
+
+	key := NewSpfIdIpv4(0xc0a80000, 24).key()
+	l1 := &Ipv4Ri{
+		level:         ISIS_LEVEL_1,
+		prefixAddress: 0xc0a80000,
+		prefixLength:  24,
+		metric:        30,
+		nexthops: []*Ipv4Nh{
+			&Ipv4Nh{nexthopAddress: 0x0a000002, nexthopInterface: &Circuit{ifKernel: eth0}},
+		},
+	}
+	l2 := &Ipv4Ri{
+		level:         ISIS_LEVEL_2,
+		prefixAddress: 0xc0a80000,
+		prefixLength:  24,
+		metric:        10,
+		nexthops: []*Ipv4Nh{
+			&Ipv4Nh{nexthopAddress: 0x0a000102, nexthopInterface: &Circuit{ifKernel: eth1}},
+		},
+	}
+	isis.ipv4RiDb[ISIS_LEVEL_1][key] = l1
+	isis.ipv4RiDb[ISIS_LEVEL_2][key] = l2
+
+	// the level 1 route wins over the level 2 one in spite of its metric
+	isis.fibUpdate()
+	routes := fake.Ipv4Routes()
+	if len(routes) != 1 || routes[0].NextHops[0].IfIndex != 2 || routes[0].Metric != 30 {
+		t.Fatalf("failed routes: %#v", routes)
+	}
+
+	// unless it is external and its preference is the higher
+	l1.external = true
+	external := uint8(200)
+	isis.config.Preference.Config.External = &external
+	isis.fibUpdate()
+	routes = fake.Ipv4Routes()
+	if len(routes) != 1 || routes[0].NextHops[0].IfIndex != 3 ||
+		routes[0].Distance != DEFAULT_PREFERENCE {
+		t.Fatalf("failed routes with external preference: %#v", routes)
+	}
+
+	// and the preference of the route installed follows the config
+	internal := uint8(100)
+	isis.config.Preference.Config.Internal = &internal
+	isis.fibUpdate()
+	routes = fake.Ipv4Routes()
+	if len(routes) != 1 || routes[0].NextHops[0].IfIndex != 3 || routes[0].Distance != 100 {
+		t.Fatalf("failed routes with internal preference: %#v", routes)
+	}
+}
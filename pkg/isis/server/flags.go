@@ -83,8 +83,6 @@ func (isis *IsisServer) setSrmFlagOtherThan(ls *Ls, circuit *Circuit) {
 func (isis *IsisServer) setSrmFlagForCircuit(circuit *Circuit) {
 	log.Debugf("enter: circuit=%s", circuit.name)
 	defer log.Debugf("exit: circuit=%s", circuit.name)
-	isis.lock.Lock()
-	defer isis.lock.Unlock()
 	for _, level := range ISIS_LEVEL_ALL {
 		for _, lstmp := range isis.lsDb[level] {
 			isis.setSrmFlag(lstmp, circuit)
@@ -0,0 +1,273 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"errors"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/m-asama/golsr/internal/pkg/isis/config"
+	"github.com/m-asama/golsr/internal/pkg/kernel"
+)
+
+type isisInstance struct {
+	isis     *IsisServer
+	vrf      string
+	configCh chan *config.IsisConfig
+	kernelCh chan *kernel.KernelStatus
+	doneCh   chan struct{}
+	wg       sync.WaitGroup
+}
+
+// IsisInstances runs an IsisServer for each instance of a config file,
+// starting and stopping them as instances are added to and removed from
+// it.
+type IsisInstances struct {
+	isisCh     chan IsisChMsg
+	configFile string
+	configType string
+	provider   kernel.Provider
+	vrf        string
+	fib        bool
+	setup      func(name string, isis *IsisServer) error
+	instances  map[string]*isisInstance
+	names      []string
+	lock       sync.RWMutex
+}
+
+func NewIsisInstances(configFile, configType string) *IsisInstances {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	return &IsisInstances{
+		isisCh:     make(chan IsisChMsg),
+		configFile: configFile,
+		configType: configType,
+		provider:   kernel.DefaultProvider(),
+		instances:  make(map[string]*isisInstance),
+		names:      make([]string, 0),
+	}
+}
+
+// SetKernelProvider sets the provider of every instance. It must be
+// called before Serve.
+func (instances *IsisInstances) SetKernelProvider(provider kernel.Provider) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	instances.provider = provider
+}
+
+// SetVrf sets the VRF of the instances which do not have one in the config
+// file. It must be called before Serve.
+func (instances *IsisInstances) SetVrf(name string) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	instances.vrf = name
+}
+
+// SetFib makes every instance install its routes into the table of its
+// VRF, which then can not be shared by two instances as the routes of one
+// would replace those of the other. It must be called before Serve.
+func (instances *IsisInstances) SetFib(enable bool) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	instances.fib = enable
+}
+
+// SetSetup sets the function called on the server of an instance before
+// it is started, to apply the settings which are not in the config file.
+// The instance is not started if it returns an error.
+func (instances *IsisInstances) SetSetup(setup func(name string, isis *IsisServer) error) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	instances.setup = setup
+}
+
+func (instances *IsisInstances) Serve(wg *sync.WaitGroup) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	defer wg.Done()
+
+	instancesCh := make(chan []*config.Instance)
+	if instances.configFile != "" {
+		go config.ServeInstances(instances.configFile, instances.configType, instancesCh)
+	}
+
+	for {
+		select {
+		case msg := <-instances.isisCh:
+			switch msg {
+			case ISIS_CH_MSG_EXIT:
+				log.Debugf("ISIS_CH_MSG_EXIT")
+				instances.update(nil)
+				goto EXIT
+			}
+		case c := <-instancesCh:
+			instances.update(c)
+		}
+	}
+EXIT:
+}
+
+func (instances *IsisInstances) Exit() {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	instances.isisCh <- ISIS_CH_MSG_EXIT
+}
+
+func (instances *IsisInstances) update(configs []*config.Instance) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	instances.lock.Lock()
+	defer instances.lock.Unlock()
+	if err := instances.validateTables(configs); err != nil {
+		log.WithFields(log.Fields{
+			"Topic": "Instance",
+			"Error": err,
+		}).Warn("Can't update instances")
+		return
+	}
+	removed := make(map[string]bool)
+	for name := range instances.instances {
+		removed[name] = true
+	}
+	names := make([]string, 0)
+	for _, c := range configs {
+		name := *c.Name
+		delete(removed, name)
+		vrf := instances.instanceVrf(c)
+		instance, ok := instances.instances[name]
+		if ok && instance.vrf != vrf {
+			// the circuits and the routes of the old VRF are let go
+			instances.stop(name)
+			ok = false
+		}
+		if !ok {
+			instance = instances.start(name, vrf)
+			if instance == nil {
+				continue
+			}
+		}
+		cfg := c.IsisConfig
+		instance.configCh <- &cfg
+		names = append(names, name)
+	}
+	for name := range removed {
+		instances.stop(name)
+	}
+	instances.names = names
+}
+
+// instanceVrf returns the VRF of the instance of c.
+func (instances *IsisInstances) instanceVrf(c *config.Instance) string {
+	if c.Vrf != nil {
+		return *c.Vrf
+	}
+	return instances.vrf
+}
+
+// validateTables checks that no two instances of configs install their
+// routes into the same table.
+func (instances *IsisInstances) validateTables(configs []*config.Instance) error {
+	if !instances.fib {
+		return nil
+	}
+	vrfs := make(map[string]string)
+	for _, c := range configs {
+		vrf := instances.instanceVrf(c)
+		if other, ok := vrfs[vrf]; ok {
+			return errors.New("instances " + other + " and " + *c.Name +
+				" install routes into the same table")
+		}
+		vrfs[vrf] = *c.Name
+	}
+	return nil
+}
+
+func (instances *IsisInstances) stop(name string) {
+	log.WithFields(log.Fields{
+		"Topic":    "Instance",
+		"Instance": name,
+	}).Info("Stop instance")
+	instance := instances.instances[name]
+	instance.isis.Exit()
+	instance.wg.Wait()
+	close(instance.doneCh)
+	delete(instances.instances, name)
+}
+
+func (instances *IsisInstances) start(name, vrf string) *isisInstance {
+	log.WithFields(log.Fields{
+		"Topic":    "Instance",
+		"Instance": name,
+	}).Info("Start instance")
+	instance := &isisInstance{
+		isis:     NewIsisServer("", ""),
+		vrf:      vrf,
+		configCh: make(chan *config.IsisConfig),
+		kernelCh: make(chan *kernel.KernelStatus),
+		doneCh:   make(chan struct{}),
+	}
+	instance.isis.SetKernelProvider(instances.provider)
+	instance.isis.SetVrf(vrf)
+	if instances.fib {
+		instance.isis.SetFib(true)
+	}
+	if instances.setup != nil {
+		if err := instances.setup(name, instance.isis); err != nil {
+			log.WithFields(log.Fields{
+				"Topic":    "Instance",
+				"Instance": name,
+				"Error":    err,
+			}).Warn("Can't start instance")
+			return nil
+		}
+	}
+	instance.wg.Add(1)
+	go instance.isis.ServeWith(&instance.wg, instance.configCh, instance.kernelCh)
+	go instances.provider.Watch(instance.kernelCh, instance.doneCh)
+	instances.instances[name] = instance
+	return instance
+}
+
+// Names returns the names of the running instances in the order of the
+// config file.
+func (instances *IsisInstances) Names() []string {
+	instances.lock.RLock()
+	defer instances.lock.RUnlock()
+	return append([]string{}, instances.names...)
+}
+
+// Instance returns the server of the instance name. An empty name selects
+// the only instance, if there is just one.
+func (instances *IsisInstances) Instance(name string) (*IsisServer, error) {
+	instances.lock.RLock()
+	defer instances.lock.RUnlock()
+	if name == "" {
+		if len(instances.names) != 1 {
+			return nil, errors.New("instance not specified")
+		}
+		name = instances.names[0]
+	}
+	instance, ok := instances.instances[name]
+	if !ok {
+		return nil, errors.New("instance " + name + " not found")
+	}
+	return instance.isis, nil
+}
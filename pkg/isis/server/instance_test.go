@@ -0,0 +1,78 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/m-asama/golsr/internal/pkg/isis/config"
+	"github.com/m-asama/golsr/internal/pkg/kernel"
+)
+
+func TestInstancesFibTables(t *testing.T) {
+	text := `
+[[instances]]
+  name = "a"
+  [instances.config]
+    system-id = "0000.0000.0001"
+    area-address-list = ["49.0001"]
+
+[[instances]]
+  name = "b"
+  %s
+  [instances.config]
+    system-id = "0000.0000.0002"
+    area-address-list = ["49.0002"]
+`
+	instances := NewIsisInstances("", "")
+	instances.SetKernelProvider(kernel.NewFakeProvider())
+	instances.SetFib(true)
+	defer instances.update(nil)
+
+	// both would install their routes into the main table
+	configs, err := config.ParseInstances([]byte(fmt.Sprintf(text, "")), "toml")
+	if err != nil {
+		t.Fatalf("failed ParseInstances: %#v", err)
+	}
+	if err := instances.validateTables(configs); err == nil {
+		t.Fatalf("failed validateTables: shared table accepted")
+	}
+	instances.update(configs)
+	if names := instances.Names(); len(names) != 0 {
+		t.Fatalf("failed instances started: %v", names)
+	}
+
+	configs, err = config.ParseInstances([]byte(fmt.Sprintf(text, `vrf = "red"`)), "toml")
+	if err != nil {
+		t.Fatalf("failed ParseInstances: %#v", err)
+	}
+	instances.update(configs)
+	if names := instances.Names(); len(names) != 2 {
+		t.Fatalf("failed instances not started: %v", names)
+	}
+	for name, vrf := range map[string]string{"a": "", "b": "red"} {
+		isis, err := instances.Instance(name)
+		if err != nil {
+			t.Fatalf("failed Instance: %#v", err)
+		}
+		if isis.vrf != vrf {
+			t.Fatalf("failed vrf of %s: %q", name, isis.vrf)
+		}
+	}
+}
@@ -28,6 +28,7 @@ import (
 
 	"github.com/m-asama/golsr/internal/pkg/isis/config"
 	"github.com/m-asama/golsr/internal/pkg/kernel"
+	"github.com/m-asama/golsr/pkg/bgpls"
 	"github.com/m-asama/golsr/pkg/isis/packet"
 )
 
@@ -145,6 +146,12 @@ type IsisServer struct {
 	configType string
 	config     *config.IsisConfig
 	kernel     *kernel.KernelStatus
+	provider   kernel.Provider
+	vrf        string
+
+	// the policies have been changed over the API and are kept when the
+	// config file is reloaded
+	apiPolicies bool
 
 	systemId           [packet.SYSTEM_ID_LENGTH]byte
 	areaAddresses      [][]byte
@@ -157,6 +164,25 @@ type IsisServer struct {
 	ipv6RiDb  [ISIS_LEVEL_NUM]map[[SPF_ID_KEY_LENGTH]byte]*Ipv6Ri
 	circuitDb map[int]*Circuit
 
+	// the messages for the goroutines of the circuits, sent by the
+	// update process as they may be waiting for the lock
+	circuitChSends []*circuitChSend
+
+	trace     *Trace
+	transport TransportFactory
+
+	lsdbExportFile     string
+	lsdbExportInterval int
+
+	bgpls   *bgpls.Speaker
+	bgplsAs uint32
+	bgplsId uint32
+
+	fibEnable     bool
+	fibIpv4Routes map[string]*kernel.Ipv4Route
+	fibIpv6Routes map[string]*kernel.Ipv6Route
+	fibLock       sync.Mutex
+
 	lock sync.RWMutex
 }
 
@@ -171,8 +197,13 @@ func NewIsisServer(configFile, configType string) *IsisServer {
 		configType:    configType,
 		config:        config.NewIsisConfig(),
 		kernel:        kernel.NewKernelStatus(),
+		provider:      kernel.DefaultProvider(),
 		areaAddresses: make([][]byte, 0),
 		circuitDb:     make(map[int]*Circuit),
+		trace:         NewTrace(),
+		transport:     NewPacketTransport,
+		fibIpv4Routes: make(map[string]*kernel.Ipv4Route),
+		fibIpv6Routes: make(map[string]*kernel.Ipv6Route),
 	}
 	for _, level := range ISIS_LEVEL_ALL {
 		isis.isReachabilities[level] = make([]*IsReachability, 0)
@@ -188,6 +219,32 @@ func NewIsisServer(configFile, configType string) *IsisServer {
 func (isis *IsisServer) Serve(wg *sync.WaitGroup) {
 	log.Debugf("enter")
 	defer log.Debugf("exit")
+
+	configCh := make(chan *config.IsisConfig)
+	if isis.configFile != "" {
+		go config.Serve(isis.configFile, isis.configType, configCh)
+	}
+
+	kernelCh := make(chan *kernel.KernelStatus)
+	kernelDoneCh := make(chan struct{})
+	defer close(kernelDoneCh)
+	go isis.provider.Watch(kernelCh, kernelDoneCh)
+
+	isis.serve(wg, configCh, isis.configFile != "", kernelCh)
+}
+
+// ServeWith runs the server on the configs and interface lists received
+// from configCh and kernelCh in place of the config file and the host's
+// interfaces. Nothing is processed until one of each has been received.
+func (isis *IsisServer) ServeWith(wg *sync.WaitGroup, configCh chan *config.IsisConfig, kernelCh chan *kernel.KernelStatus) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	isis.serve(wg, configCh, true, kernelCh)
+}
+
+func (isis *IsisServer) serve(wg *sync.WaitGroup, configCh chan *config.IsisConfig, waitConfig bool, kernelCh chan *kernel.KernelStatus) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
 	defer wg.Done()
 
 	log.Debugf("")
@@ -195,17 +252,13 @@ func (isis *IsisServer) Serve(wg *sync.WaitGroup) {
 	var updateWg sync.WaitGroup
 
 	sigCh := make(chan os.Signal, 1)
-	configCh := make(chan *config.IsisConfig)
-	if isis.configFile != "" {
+	if waitConfig {
 		updateWg.Add(1)
-		go config.Serve(isis.configFile, isis.configType, configCh)
 	} else {
 		signal.Notify(sigCh, syscall.SIGHUP)
 	}
 
-	kernelCh := make(chan *kernel.KernelStatus)
 	updateWg.Add(1)
-	go kernel.Serve(kernelCh)
 
 	periodicCh := make(chan struct{})
 	go isis.periodic(periodicCh)
@@ -213,6 +266,12 @@ func (isis *IsisServer) Serve(wg *sync.WaitGroup) {
 	go isis.decisionProcess()
 	go isis.updateProcess(&updateWg)
 
+	var bgplsWg sync.WaitGroup
+	if isis.bgpls != nil {
+		bgplsWg.Add(1)
+		go isis.bgpls.Serve(&bgplsWg)
+	}
+
 	configReady := false
 	kernelReady := false
 	for {
@@ -226,16 +285,27 @@ func (isis *IsisServer) Serve(wg *sync.WaitGroup) {
 			case ISIS_CH_MSG_EXIT:
 				log.Debugf("ISIS_CH_MSG_EXIT")
 				periodicCh <- struct{}{}
+				isis.trace.closePcap()
+				if isis.bgpls != nil {
+					isis.bgpls.Exit()
+					bgplsWg.Wait()
+				}
+				isis.SetFib(false)
+				isis.fibUpdate()
 				goto EXIT
 			}
 		case c := <-configCh:
+			isis.lock.Lock()
 			isis.handleConfigChanged(c)
+			isis.lock.Unlock()
 			if !configReady {
 				updateWg.Done()
 				configReady = true
 			}
 		case k := <-kernelCh:
+			isis.lock.Lock()
 			isis.handleKernelChanged(k)
+			isis.lock.Unlock()
 			if !kernelReady {
 				updateWg.Done()
 				kernelReady = true
@@ -251,6 +321,20 @@ func (isis *IsisServer) Exit() {
 	isis.isisCh <- ISIS_CH_MSG_EXIT
 }
 
+func (isis *IsisServer) SetTracePcapFile(filename string) error {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	return isis.trace.openPcap(filename)
+}
+
+// SetTransport replaces how circuits send and receive frames. It must be
+// called before Serve.
+func (isis *IsisServer) SetTransport(factory TransportFactory) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	isis.transport = factory
+}
+
 func (isis *IsisServer) SetEnable() {
 	log.Debugf("enter")
 	defer log.Debugf("exit")
@@ -393,7 +477,7 @@ func (isis *IsisServer) getIfKernelByName(name string) *kernel.Interface {
 	log.Debugf("enter")
 	defer log.Debugf("exit")
 	for _, ifKernel := range isis.kernel.Interfaces {
-		if ifKernel.Name == name {
+		if ifKernel.Name == name && isis.eligible(ifKernel) {
 			return ifKernel
 		}
 	}
@@ -429,6 +513,15 @@ func (isis *IsisServer) addCircuit(name string, ifConfig *config.Interface) {
 	isis.circuitDb[ifKernel.IfIndex] = circuit
 }
 
+// circuitChSend queues msg to be sent on ch once isis.lock, which the
+// caller holds, is released.
+func (isis *IsisServer) circuitChSend(ch chan CircuitChMsg, msg CircuitChMsg) {
+	isis.circuitChSends = append(isis.circuitChSends, &circuitChSend{
+		ch:  ch,
+		msg: msg,
+	})
+}
+
 func (isis *IsisServer) removeCircuit(name string) {
 	log.Debugf("enter: %s", name)
 	defer log.Debugf("exit: %s", name)
@@ -442,8 +535,8 @@ func (isis *IsisServer) removeCircuit(name string) {
 		return
 	}
 	circuit.SetDisable()
-	circuit.Exit()
 	delete(isis.circuitDb, circuit.ifKernel.IfIndex)
+	circuit.Exit()
 }
 
 func (isis *IsisServer) handleConfigChanged(newConfig *config.IsisConfig) {
@@ -476,6 +569,12 @@ func (isis *IsisServer) handleConfigChanged(newConfig *config.IsisConfig) {
 			}
 		}
 	}
+	if isis.apiPolicies {
+		// the policies changed over the API outlive the config file
+		newConfig.DefinedSets = isis.config.DefinedSets
+		newConfig.PolicyDefinitions = isis.config.PolicyDefinitions
+		newConfig.ApplyPolicy = isis.config.ApplyPolicy
+	}
 	isis.config = newConfig
 	isis.updateChSend(&UpdateChMsg{
 		msgType: UPDATE_CH_MSG_TYPE_CONFIG_CHANGED,
@@ -490,6 +589,9 @@ func (isis *IsisServer) handleKernelChanged(newKernel *kernel.KernelStatus) {
 		removed[*iface.Config.Name] = iface
 	}
 	for _, iface := range newKernel.Interfaces {
+		if !isis.eligible(iface) {
+			continue
+		}
 		if _, ok := removed[iface.Name]; ok {
 			delete(removed, iface.Name)
 		}
@@ -498,6 +600,20 @@ func (isis *IsisServer) handleKernelChanged(newKernel *kernel.KernelStatus) {
 		log.Debugf("remove: %s", name)
 		isis.removeCircuit(name)
 	}
+	isis.kernel = newKernel
+	for _, iface := range isis.config.Interfaces {
+		if _, ok := removed[*iface.Config.Name]; ok {
+			continue
+		}
+		ifKernel := isis.getIfKernelByName(*iface.Config.Name)
+		if _, ok := isis.circuitDb[ifKernel.IfIndex]; ok {
+			continue
+		}
+		// appeared after configured or recreated with a new index
+		log.Debugf("add: %s", *iface.Config.Name)
+		isis.removeCircuit(*iface.Config.Name)
+		isis.addCircuit(*iface.Config.Name, iface)
+	}
 	for _, tmp := range isis.circuitDb {
 		for _, iface := range newKernel.Interfaces {
 			if tmp.ifKernel.IfIndex == iface.IfIndex {
@@ -511,7 +627,6 @@ func (isis *IsisServer) handleKernelChanged(newKernel *kernel.KernelStatus) {
 			}
 		}
 	}
-	isis.kernel = newKernel
 	isis.updateChSend(&UpdateChMsg{
 		msgType: UPDATE_CH_MSG_TYPE_KERNEL_CHANGED,
 	})
@@ -0,0 +1,126 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	log "github.com/sirupsen/logrus"
+)
+
+// leakSource returns the level whose routes are leaked into level, or
+// false if we leak nothing into it: only a level 1 and 2 router leaks, and
+// IPv4 routes into level 1 only where the wide metrics carry the up/down
+// bit. The routes going up into level 2 need none.
+func (isis *IsisServer) leakSource(level IsisLevel, ipv4 bool) (IsisLevel, bool) {
+	if !isis.levelAll() {
+		return level, false
+	}
+	if ipv4 && level == ISIS_LEVEL_1 && !isis.wide(level) {
+		return level, false
+	}
+	if level == ISIS_LEVEL_1 {
+		return ISIS_LEVEL_2, true
+	}
+	return ISIS_LEVEL_1, true
+}
+
+// leakMetric caps metric at the largest one a leaked prefix is still
+// used with (RFC 5305 4).
+func leakMetric(metric uint32) uint32 {
+	if metric > MAX_PATH_METRIC {
+		return MAX_PATH_METRIC
+	}
+	return metric
+}
+
+// leakedIpv4Reachabilities returns the IPv4 routes of the other level the
+// leak policies let into level. Our own prefixes, which have no next hop,
+// are advertised anyway, and routes which came down from level 2 never go
+// back up.
+func (isis *IsisServer) leakedIpv4Reachabilities(level IsisLevel) []*Ipv4Reachability {
+	log.Debugf("enter: %s", level)
+	defer log.Debugf("exit: %s", level)
+	leaked := make([]*Ipv4Reachability, 0)
+	from, ok := isis.leakSource(level, true)
+	if !ok {
+		return leaked
+	}
+	for _, ri := range isis.ipv4RiDb[from] {
+		if len(ri.nexthops) == 0 || (level == ISIS_LEVEL_2 && ri.down) {
+			continue
+		}
+		route := newPolicyRouteIpv4(ri)
+		if isis.leakPolicy(level, route) != POLICY_ACTION_ACCEPT_ROUTE {
+			continue
+		}
+		leaked = append(leaked, &Ipv4Reachability{
+			ipv4Prefix:   ri.prefixAddress,
+			prefixLength: ri.prefixLength,
+			metric:       leakMetric(route.metric),
+			lspNumber:    -1,
+			wideMetric:   isis.wide(level),
+			down:         route.down,
+			tags:         route.tags,
+			leaked:       true,
+		})
+	}
+	return leaked
+}
+
+// leakedIpv6Reachabilities returns the IPv6 routes of the other level the
+// leak policies let into level, as leakedIpv4Reachabilities does.
+func (isis *IsisServer) leakedIpv6Reachabilities(level IsisLevel) []*Ipv6Reachability {
+	log.Debugf("enter: %s", level)
+	defer log.Debugf("exit: %s", level)
+	leaked := make([]*Ipv6Reachability, 0)
+	from, ok := isis.leakSource(level, false)
+	if !ok {
+		return leaked
+	}
+	for _, ri := range isis.ipv6RiDb[from] {
+		if len(ri.nexthops) == 0 || (level == ISIS_LEVEL_2 && ri.down) {
+			continue
+		}
+		route := newPolicyRouteIpv6(ri)
+		if isis.leakPolicy(level, route) != POLICY_ACTION_ACCEPT_ROUTE {
+			continue
+		}
+		leaked = append(leaked, &Ipv6Reachability{
+			ipv6Prefix:   ri.prefixAddress,
+			prefixLength: ri.prefixLength,
+			metric:       leakMetric(route.metric),
+			lspNumber:    -1,
+			down:         route.down,
+			external:     ri.external,
+			tags:         route.tags,
+			leaked:       true,
+		})
+	}
+	return leaked
+}
+
+func tagsEqual(l, r []uint32) bool {
+	if len(l) != len(r) {
+		return false
+	}
+	for i := range l {
+		if l[i] != r[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -34,7 +34,13 @@ func (circuit *Circuit) sendLs(lsp *packet.LsPdu) {
 		return
 	}
 
-	circuit.lsSenderCh <- lsp
+	frame, err := circuit.newFrame(lsp)
+	if err != nil {
+		log.Infof("Serialize failed")
+		return
+	}
+	frame.pacing = time.Millisecond * time.Duration(circuit.lspPacingInterval())
+	circuit.lsSenderCh <- frame
 }
 
 func (circuit *Circuit) receiveLs(pdu *packet.LsPdu, lanAddress [packet.SYSTEM_ID_LENGTH]byte) {
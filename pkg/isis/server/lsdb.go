@@ -82,6 +82,8 @@ func (isis *IsisServer) lspLevel(lsp *packet.LsPdu) (IsisLevel, error) {
 	return 0, errors.New("level invalid")
 }
 
+// insertLsp adds lsp to the LSDB in place of the one with the same LSP ID.
+// The callers of the functions of the LSDB hold isis.lock.
 func (isis *IsisServer) insertLsp(lsp *packet.LsPdu, origin bool, generated *time.Time) *Ls {
 	log.Debugf("enter: lspid=%x origin=%s generated=%s", lsp.LspId(), origin, generated)
 	defer log.Debugf("exit: lspid=%x origin=%s generated=%s", lsp.LspId(), origin, generated)
@@ -89,8 +91,6 @@ func (isis *IsisServer) insertLsp(lsp *packet.LsPdu, origin bool, generated *tim
 	if err != nil {
 		return nil
 	}
-	isis.lock.Lock()
-	defer isis.lock.Unlock()
 	lsDb := make([]*Ls, 0)
 	for _, lstmp := range isis.lsDb[level] {
 		ll := lstmp.pdu.LspId()
@@ -108,17 +108,13 @@ func (isis *IsisServer) insertLsp(lsp *packet.LsPdu, origin bool, generated *tim
 	return ls
 }
 
-func (isis *IsisServer) deleteLsp(ls *Ls, hasLock bool) {
+func (isis *IsisServer) deleteLsp(ls *Ls) {
 	log.Debugf("enter: lspid=%x", ls.pdu.LspId())
 	defer log.Debugf("exit: lspid=%x", ls.pdu.LspId())
 	level, err := isis.lspLevel(ls.pdu)
 	if err != nil {
 		return
 	}
-	if !hasLock {
-		isis.lock.Lock()
-		defer isis.lock.Unlock()
-	}
 	lsDb := make([]*Ls, 0)
 	for _, lstmp := range isis.lsDb[level] {
 		if lstmp != ls {
@@ -134,8 +130,6 @@ func (isis *IsisServer) deleteLsp(ls *Ls, hasLock bool) {
 func (isis *IsisServer) lookupLsp(level IsisLevel, lspId [packet.LSP_ID_LENGTH]byte) *Ls {
 	log.Debugf("enter: level=%s lspid=%x", level, lspId)
 	defer log.Debugf("exit: level=%s lspid=%x", level, lspId)
-	isis.lock.RLock()
-	defer isis.lock.RUnlock()
 	for _, lstmp := range isis.lsDb[level] {
 		ll := lstmp.pdu.LspId()
 		if bytes.Equal(ll[:], lspId[:]) {
@@ -148,8 +142,6 @@ func (isis *IsisServer) lookupLsp(level IsisLevel, lspId [packet.LSP_ID_LENGTH]b
 func (isis *IsisServer) originLss(level IsisLevel, nodeId uint8) []*Ls {
 	log.Debugf("enter")
 	defer log.Debugf("exit")
-	isis.lock.RLock()
-	defer isis.lock.RUnlock()
 	lss := make([]*Ls, 0)
 	for _, lstmp := range isis.lsDb[level] {
 		ll := lstmp.pdu.LspId()
@@ -167,9 +159,6 @@ func (isis *IsisServer) originLss(level IsisLevel, nodeId uint8) []*Ls {
 func (isis *IsisServer) getReachabilities(level IsisLevel, neighId [packet.NEIGHBOUR_ID_LENGTH]byte) *Reachabilities {
 	log.Debugf("enter: level=%s neighid=%x", level, neighId)
 	defer log.Debugf("exit: level=%s neighid=%x", level, neighId)
-	isis.lock.RLock()
-	defer isis.lock.RUnlock()
-	r := NewReachabilities()
 	lss := make([]*Ls, 0)
 	for _, lstmp := range isis.lsDb[level] {
 		log.Debugf("%s: cand %x", level, lstmp.pdu.LspId())
@@ -179,6 +168,11 @@ func (isis *IsisServer) getReachabilities(level IsisLevel, neighId [packet.NEIGH
 			lss = append(lss, lstmp)
 		}
 	}
+	return lssReachabilities(level, lss)
+}
+
+func lssReachabilities(level IsisLevel, lss []*Ls) *Reachabilities {
+	r := NewReachabilities()
 	sort.Sort(Lss(lss))
 	for _, ls := range lss {
 		log.Debugf("%s: do %x", level, ls.pdu.LspId())
@@ -214,6 +208,8 @@ func (isis *IsisServer) getReachabilities(level IsisLevel, neighId [packet.NEIGH
 				i4r.ipv4Prefix = n.Ipv4Prefix()
 				i4r.prefixLength = n.PrefixLength()
 				i4r.metric = n.MetricInformation
+				i4r.down = n.UpDownBit
+				i4r.tags = n.Tags()
 				r.addIpv4Reachability(i4r)
 				log.Debugf("%s: add ipv4 wide %x/%d", level, i4r.ipv4Prefix, i4r.prefixLength)
 			}
@@ -230,6 +226,18 @@ func (isis *IsisServer) getReachabilities(level IsisLevel, neighId [packet.NEIGH
 				log.Debugf("%s: add ipv4 old %x/%d", level, i4r.ipv4Prefix, i4r.prefixLength)
 			}
 		}
+		extiptlvs, _ := ls.pdu.IpExternalReachInfoTlvs()
+		for _, tlv := range extiptlvs {
+			for _, n := range tlv.IpSubnets() {
+				i4r := &Ipv4Reachability{}
+				i4r.ipv4Prefix = n.IpAddress
+				i4r.prefixLength = util.Snmask42plen(n.SubnetMask)
+				i4r.metric = uint32(n.DefaultMetric)
+				i4r.external = true
+				r.addIpv4Reachability(i4r)
+				log.Debugf("%s: add ipv4 external %x/%d", level, i4r.ipv4Prefix, i4r.prefixLength)
+			}
+		}
 		ip6tlvs, _ := ls.pdu.Ipv6ReachabilityTlvs()
 		for _, tlv := range ip6tlvs {
 			for _, n := range tlv.Ipv6Prefixes() {
@@ -237,6 +245,9 @@ func (isis *IsisServer) getReachabilities(level IsisLevel, neighId [packet.NEIGH
 				i6r.ipv6Prefix = n.Ipv6Prefix()
 				i6r.prefixLength = n.PrefixLength()
 				i6r.metric = n.Metric
+				i6r.external = n.ExternalOriginalBit
+				i6r.down = n.UpDownBit
+				i6r.tags = n.Tags()
 				r.addIpv6Reachability(i6r)
 				log.Debugf("%s: add ipv6 %x:%x:%x:%x/%d", level,
 					i6r.ipv6Prefix[0], i6r.ipv6Prefix[1], i6r.ipv6Prefix[2], i6r.ipv6Prefix[3],
@@ -0,0 +1,354 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+
+	api "github.com/m-asama/golsr/api/isis"
+	"github.com/m-asama/golsr/internal/pkg/util"
+	"github.com/m-asama/golsr/pkg/isis/packet"
+)
+
+type offlineLink [2][packet.NEIGHBOUR_ID_LENGTH]byte
+
+type offlineAdjacencyKey struct {
+	level    IsisLevel
+	systemId [packet.SYSTEM_ID_LENGTH]byte
+}
+
+// OfflineLsdb is a link state database which is not attached to any
+// circuit. It is filled from captured or dumped LSPs and runs the same
+// route calculation as IsisServer, seen from an arbitrary root. What-if
+// edits are applied on top of the LSPs and never modify them.
+type OfflineLsdb struct {
+	systemId    [packet.SYSTEM_ID_LENGTH]byte
+	lsDb        [ISIS_LEVEL_NUM][]*Ls
+	adjacencies map[offlineAdjacencyKey]*Adjacency
+	failedLinks map[offlineLink]bool
+	metrics     map[offlineLink]uint32
+}
+
+func NewOfflineLsdb() *OfflineLsdb {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	db := &OfflineLsdb{
+		adjacencies: make(map[offlineAdjacencyKey]*Adjacency),
+		failedLinks: make(map[offlineLink]bool),
+		metrics:     make(map[offlineLink]uint32),
+	}
+	for _, level := range ISIS_LEVEL_ALL {
+		db.lsDb[level] = make([]*Ls, 0)
+	}
+	return db
+}
+
+func (db *OfflineLsdb) SetSystemId(systemId [packet.SYSTEM_ID_LENGTH]byte) {
+	db.systemId = systemId
+}
+
+// AddLsp keeps the newest instance of each LSP as a receiving IS would.
+// It returns false if lsp was older than the one already stored.
+func (db *OfflineLsdb) AddLsp(lsp *packet.LsPdu) (bool, error) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	var level IsisLevel
+	switch lsp.PduType() {
+	case packet.PDU_TYPE_LEVEL1_LSP:
+		level = ISIS_LEVEL_1
+	case packet.PDU_TYPE_LEVEL2_LSP:
+		level = ISIS_LEVEL_2
+	default:
+		return false, errors.New("OfflineLsdb.AddLsp: pduType invalid")
+	}
+	lspId := lsp.LspId()
+	lsDb := make([]*Ls, 0)
+	for _, lstmp := range db.lsDb[level] {
+		ll := lstmp.pdu.LspId()
+		if !bytes.Equal(ll[:], lspId[:]) {
+			lsDb = append(lsDb, lstmp)
+			continue
+		}
+		// iso10589 p.35 7.3.16.1
+		if lstmp.pdu.SequenceNumber > lsp.SequenceNumber {
+			return false, nil
+		}
+		if lstmp.pdu.SequenceNumber == lsp.SequenceNumber &&
+			!(lsp.RemainingLifetime == 0 && lstmp.pdu.RemainingLifetime != 0) {
+			return false, nil
+		}
+	}
+	ls, _ := NewLs(lsp, false, nil)
+	lsDb = append(lsDb, ls)
+	sort.Sort(Lss(lsDb))
+	db.lsDb[level] = lsDb
+	return true, nil
+}
+
+// ImportLsdb adds every LSP of an LsdbExport.
+func (db *OfflineLsdb) ImportLsdb(data []byte) error {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	lsps, err := ParseLsdbExport(data)
+	if err != nil {
+		return err
+	}
+	for _, lsp := range lsps {
+		if _, err := db.AddLsp(lsp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FailLink removes the adjacency between a and b in both directions.
+func (db *OfflineLsdb) FailLink(a, b [packet.NEIGHBOUR_ID_LENGTH]byte) {
+	db.failedLinks[offlineLink{a, b}] = true
+	db.failedLinks[offlineLink{b, a}] = true
+}
+
+// SetMetric overrides the metric advertised by from towards to.
+func (db *OfflineLsdb) SetMetric(from, to [packet.NEIGHBOUR_ID_LENGTH]byte, metric uint32) {
+	db.metrics[offlineLink{from, to}] = metric
+}
+
+func (db *OfflineLsdb) Lsps(level IsisLevel) []*packet.LsPdu {
+	lsps := make([]*packet.LsPdu, 0)
+	for _, ls := range db.lsDb[level] {
+		lsps = append(lsps, ls.pdu)
+	}
+	return lsps
+}
+
+func (db *OfflineLsdb) ApiLsps(level IsisLevel) []*api.Lsp {
+	lsps := make([]*api.Lsp, 0)
+	for _, ls := range db.lsDb[level] {
+		lsps = append(lsps, NewApiLsp(ls.pdu))
+	}
+	return lsps
+}
+
+// Spf returns routes ordered as DbRiMonitor does. Next hops carry the
+// neighbour's hostname (or system id) in place of an interface name.
+func (db *OfflineLsdb) Spf(level IsisLevel) []*api.Route {
+	log.Debugf("enter: %s", level)
+	defer log.Debugf("exit: %s", level)
+	db.adjacencies = make(map[offlineAdjacencyKey]*Adjacency)
+	paths := spfCalc(level, db)
+	ipv4RiDb, ipv6RiDb := newRiDbs(level, paths)
+	keys := make([][SPF_ID_KEY_LENGTH]byte, 0)
+	for k := range ipv4RiDb {
+		keys = append(keys, k)
+	}
+	for k := range ipv6RiDb {
+		keys = append(keys, k)
+	}
+	sort.Sort(SpfIdKeys(keys))
+	routes := make([]*api.Route, 0)
+	for _, k := range keys {
+		if v, ok := ipv4RiDb[k]; ok {
+			route := &api.Route{}
+			route.Level = level.String2()
+			route.AddressFamily = "ipv4"
+			fillRoute4(route, v)
+			routes = append(routes, route)
+		}
+		if v, ok := ipv6RiDb[k]; ok {
+			route := &api.Route{}
+			route.Level = level.String2()
+			route.AddressFamily = "ipv6"
+			fillRoute6(route, v)
+			routes = append(routes, route)
+		}
+	}
+	return routes
+}
+
+func (db *OfflineLsdb) nodeLss(level IsisLevel, nodeId [packet.NEIGHBOUR_ID_LENGTH]byte) []*Ls {
+	lss := make([]*Ls, 0)
+	for _, ls := range db.lsDb[level] {
+		ll := ls.pdu.LspId()
+		if ls.pdu.RemainingLifetime == 0 {
+			continue
+		}
+		if bytes.Equal(ll[0:packet.NEIGHBOUR_ID_LENGTH], nodeId[0:packet.NEIGHBOUR_ID_LENGTH]) {
+			lss = append(lss, ls)
+		}
+	}
+	return lss
+}
+
+// adjacency fakes an adjacency to systemId from what the LSPs of level
+// advertise. Its addresses, the next hops through it, are the ones the
+// root gives it in the IPv4 neighbour address sub-TLVs or else those of
+// its interface addresses on a subnet the root advertises too, that is
+// its addresses on the links to the root as far as the LSPs tell.
+func (db *OfflineLsdb) adjacency(level IsisLevel, systemId [packet.SYSTEM_ID_LENGTH]byte) *Adjacency {
+	key := offlineAdjacencyKey{level, systemId}
+	if adjacency, ok := db.adjacencies[key]; ok {
+		return adjacency
+	}
+	adjacency, _ := NewAdjacency(&Circuit{name: fmt.Sprintf("%x", systemId)})
+	adjacency.adjState = packet.ADJ_3WAY_STATE_UP
+	adjacency.systemId = systemId
+	nodeId := [packet.NEIGHBOUR_ID_LENGTH]byte{}
+	copy(nodeId[0:packet.SYSTEM_ID_LENGTH], systemId[0:packet.SYSTEM_ID_LENGTH])
+	rootLss := db.nodeLss(level, db.spfRootId())
+	rootReachabilities := lssReachabilities(level, rootLss)
+	for _, ls := range rootLss {
+		tlvs, _ := ls.pdu.ExtendedIsReachabilityTlvs()
+		for _, tlv := range tlvs {
+			for _, n := range tlv.Neighbours() {
+				if n.NeighbourId() == nodeId {
+					adjacency.ipv4Addresses = append(adjacency.ipv4Addresses,
+						n.Ipv4NeighbourAddressSubTlvs()...)
+				}
+			}
+		}
+	}
+	ipv4Addresses := make([]uint32, 0)
+	ipv6Addresses := make([][4]uint32, 0)
+	for _, ls := range db.nodeLss(level, nodeId) {
+		dynamicHostnameTlv, _ := ls.pdu.DynamicHostnameTlv()
+		if dynamicHostnameTlv != nil {
+			adjacency.circuit.name = string(dynamicHostnameTlv.DynamicHostname())
+		}
+		ipInterfaceAddressTlv, _ := ls.pdu.IpInterfaceAddressTlv()
+		if ipInterfaceAddressTlv != nil {
+			for _, address := range ipInterfaceAddressTlv.IpAddresses() {
+				if rootReachabilities.ipv4Subnet(address) {
+					ipv4Addresses = append(ipv4Addresses, address)
+				}
+			}
+		}
+		ipv6InterfaceAddressTlv, _ := ls.pdu.Ipv6InterfaceAddressTlv()
+		if ipv6InterfaceAddressTlv != nil {
+			for _, address := range ipv6InterfaceAddressTlv.Ipv6Addresses() {
+				if rootReachabilities.ipv6Subnet(address) {
+					ipv6Addresses = append(ipv6Addresses, address)
+				}
+			}
+		}
+	}
+	if len(adjacency.ipv4Addresses) == 0 {
+		adjacency.ipv4Addresses = ipv4Addresses
+	}
+	adjacency.ipv6Addresses = ipv6Addresses
+	db.adjacencies[key] = adjacency
+	return adjacency
+}
+
+// ipv4Subnet tells whether address is on a subnet of rs, a prefix shorter
+// than a host route.
+func (rs *Reachabilities) ipv4Subnet(address uint32) bool {
+	for _, ir := range rs.ipv4Reachabilities {
+		if ir.prefixLength < 32 &&
+			address&util.Plen2snmask4(ir.prefixLength) == ir.ipv4Prefix {
+			return true
+		}
+	}
+	return false
+}
+
+// ipv6Subnet tells whether address is on a subnet of rs, a prefix shorter
+// than a host route.
+func (rs *Reachabilities) ipv6Subnet(address [4]uint32) bool {
+	for _, ir := range rs.ipv6Reachabilities {
+		if ir.prefixLength >= 128 {
+			continue
+		}
+		match := true
+		for i := 0; i < 4; i++ {
+			plen := int(ir.prefixLength) - i*32
+			if plen <= 0 {
+				break
+			}
+			if plen > 32 {
+				plen = 32
+			}
+			if address[i]&util.Plen2snmask4(uint8(plen)) != ir.ipv6Prefix[i] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+func (db *OfflineLsdb) spfRootId() [packet.NEIGHBOUR_ID_LENGTH]byte {
+	rootId := [packet.NEIGHBOUR_ID_LENGTH]byte{}
+	copy(rootId[0:packet.SYSTEM_ID_LENGTH], db.systemId[0:packet.SYSTEM_ID_LENGTH])
+	return rootId
+}
+
+func (db *OfflineLsdb) spfAdjacencies(level IsisLevel) []*spfAdjacency {
+	log.Debugf("enter: %s", level)
+	defer log.Debugf("exit: %s", level)
+	adjacencies := make([]*spfAdjacency, 0)
+	rootId := db.spfRootId()
+	for _, isr := range db.spfReachabilities(level, rootId).isReachabilities {
+		if isr.neighborId[packet.NEIGHBOUR_ID_LENGTH-1] == 0 {
+			var systemId [packet.SYSTEM_ID_LENGTH]byte
+			copy(systemId[0:packet.SYSTEM_ID_LENGTH], isr.neighborId[0:packet.SYSTEM_ID_LENGTH])
+			adjacencies = append(adjacencies, &spfAdjacency{
+				adjacency: db.adjacency(level, systemId),
+				metric:    isr.metric,
+			})
+			continue
+		}
+		// a LAN: the neighbours are the other systems on the pseudonode
+		for _, pnr := range db.spfReachabilities(level, isr.neighborId).isReachabilities {
+			if pnr.neighborId[packet.NEIGHBOUR_ID_LENGTH-1] != 0 ||
+				bytes.Equal(pnr.neighborId[:], rootId[:]) {
+				continue
+			}
+			var systemId [packet.SYSTEM_ID_LENGTH]byte
+			copy(systemId[0:packet.SYSTEM_ID_LENGTH], pnr.neighborId[0:packet.SYSTEM_ID_LENGTH])
+			adjacencies = append(adjacencies, &spfAdjacency{
+				adjacency: db.adjacency(level, systemId),
+				metric:    isr.metric,
+			})
+		}
+	}
+	return adjacencies
+}
+
+func (db *OfflineLsdb) spfReachabilities(level IsisLevel, nodeId [packet.NEIGHBOUR_ID_LENGTH]byte) *Reachabilities {
+	r := lssReachabilities(level, db.nodeLss(level, nodeId))
+	isReachabilities := make([]*IsReachability, 0)
+	for _, isr := range r.isReachabilities {
+		link := offlineLink{nodeId, isr.neighborId}
+		if db.failedLinks[link] {
+			continue
+		}
+		if metric, ok := db.metrics[link]; ok {
+			isr.metric = metric
+		}
+		isReachabilities = append(isReachabilities, isr)
+	}
+	r.isReachabilities = isReachabilities
+	return r
+}
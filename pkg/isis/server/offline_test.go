@@ -0,0 +1,319 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/m-asama/golsr/pkg/isis/packet"
+)
+
+func newTestLsp(t *testing.T, id byte, seq uint32, neighbours []byte, prefix uint32, hostname string) *packet.LsPdu {
+	lsp, err := packet.NewLsPdu(packet.PDU_TYPE_LEVEL2_LSP)
+	if err != nil {
+		t.Fatalf("failed NewLsPdu: %#v", err)
+	}
+	lsp.SetLspId([packet.LSP_ID_LENGTH]byte{0, 0, 0, 0, 0, id, 0, 0})
+	lsp.SequenceNumber = seq
+	lsp.RemainingLifetime = 1200
+	isr, _ := packet.NewExtendedIsReachabilityTlv()
+	for _, n := range neighbours {
+		neighbour, _ := packet.NewExtendedIsReachabilityNeighbour([packet.NEIGHBOUR_ID_LENGTH]byte{0, 0, 0, 0, 0, n, 0})
+		neighbour.DefaultMetric = 10
+		isr.AddNeighbour(neighbour)
+	}
+	lsp.AddExtendedIsReachabilityTlv(isr)
+	ipr, _ := packet.NewExtendedIpReachabilityTlv()
+	p, _ := packet.NewExtendedIpReachabilityIpv4Prefix(prefix, 24)
+	p.MetricInformation = 10
+	ipr.AddIpv4Prefix(p)
+	lsp.AddExtendedIpReachabilityTlv(ipr)
+	ifa, _ := packet.NewIpInterfaceAddressTlv()
+	ifa.AddIpAddress(prefix + 1)
+	lsp.SetIpInterfaceAddressTlv(ifa)
+	dh, _ := packet.NewDynamicHostnameTlv()
+	dh.SetDynamicHostname([]byte(hostname))
+	lsp.SetDynamicHostnameTlv(dh)
+	return lsp
+}
+
+// setTestNeighbourAddresses readvertises the neighbours of lsp with their
+// addresses on the links to it in the IPv4 neighbour address sub-TLVs.
+func setTestNeighbourAddresses(lsp *packet.LsPdu, addresses map[byte]uint32) {
+	isr, _ := packet.NewExtendedIsReachabilityTlv()
+	tlvs, _ := lsp.ExtendedIsReachabilityTlvs()
+	for _, tlv := range tlvs {
+		for _, n := range tlv.Neighbours() {
+			neighbourId := n.NeighbourId()
+			if address, ok := addresses[neighbourId[packet.SYSTEM_ID_LENGTH-1]]; ok {
+				n.AddIpv4NeighbourAddressSubTlv(address)
+			}
+			isr.AddNeighbour(n)
+		}
+	}
+	lsp.ClearExtendedIsReachabilityTlvs()
+	lsp.AddExtendedIsReachabilityTlv(isr)
+}
+
+func TestOfflineLsdbSpf(t *testing.T) {
+	//
+	//    b
+	//   / \
+	//  a   d - 10.0.4.0/24
+	//   \ /
+	//    c
+	//
+	db := NewOfflineLsdb()
+	a := newTestLsp(t, 1, 1, []byte{2, 3}, 0x0a000100, "a")
+	setTestNeighbourAddresses(a, map[byte]uint32{2: 0x0a000201, 3: 0x0a000301})
+	db.AddLsp(a)
+	db.AddLsp(newTestLsp(t, 2, 1, []byte{1, 4}, 0x0a000200, "b"))
+	db.AddLsp(newTestLsp(t, 3, 1, []byte{1, 4}, 0x0a000300, "c"))
+	db.AddLsp(newTestLsp(t, 4, 1, []byte{2, 3}, 0x0a000400, "d"))
+	db.SetSystemId([packet.SYSTEM_ID_LENGTH]byte{0, 0, 0, 0, 0, 1})
+
+	routes := db.Spf(ISIS_LEVEL_2)
+	found := false
+	for _, route := range routes {
+		if route.Prefix != "10.0.4.0/24" {
+			continue
+		}
+		found = true
+		if route.Metric != 30 {
+			t.Fatalf("failed Metric: %d", route.Metric)
+		}
+		if len(route.NextHops) != 2 {
+			t.Fatalf("failed len(NextHops): %d", len(route.NextHops))
+		}
+	}
+	if !found {
+		t.Fatalf("failed 10.0.4.0/24 not found")
+	}
+
+	// an older instance must not replace the stored one
+	added, _ := db.AddLsp(newTestLsp(t, 4, 0, []byte{2, 3}, 0x0a000500, "d"))
+	if added {
+		t.Fatalf("failed older lsp added")
+	}
+	// c loses its link to d
+	db.AddLsp(newTestLsp(t, 3, 2, []byte{1}, 0x0a000300, "c"))
+	routes = db.Spf(ISIS_LEVEL_2)
+	for _, route := range routes {
+		if route.Prefix != "10.0.4.0/24" {
+			continue
+		}
+		if len(route.NextHops) != 1 || route.NextHops[0].OutgoingInterface != "b" {
+			t.Fatalf("failed NextHops: %v", route.NextHops)
+		}
+		if route.NextHops[0].NextHop != "10.0.2.1" {
+			t.Fatalf("failed NextHop: %s", route.NextHops[0].NextHop)
+		}
+	}
+}
+
+func TestOfflineLsdbNexthop(t *testing.T) {
+	// b advertises its loopback before its address on the link to a,
+	// 10.0.12.0/24, and a gives no neighbour address sub-TLV
+	db := NewOfflineLsdb()
+	a := newTestLsp(t, 1, 1, []byte{2}, 0x0a000c00, "a")
+	b := newTestLsp(t, 2, 1, []byte{1}, 0x02020200, "b")
+	ifa, _ := packet.NewIpInterfaceAddressTlv()
+	ifa.AddIpAddress(0x02020201)
+	ifa.AddIpAddress(0x0a000c02)
+	b.SetIpInterfaceAddressTlv(ifa)
+	db.AddLsp(a)
+	db.AddLsp(b)
+	db.SetSystemId([packet.SYSTEM_ID_LENGTH]byte{0, 0, 0, 0, 0, 1})
+
+	found := false
+	for _, route := range db.Spf(ISIS_LEVEL_2) {
+		if route.Prefix != "2.2.2.0/24" {
+			continue
+		}
+		found = true
+		if len(route.NextHops) != 1 || route.NextHops[0].NextHop != "10.0.12.2" {
+			t.Fatalf("failed NextHops: %v", route.NextHops)
+		}
+	}
+	if !found {
+		t.Fatalf("failed 2.2.2.0/24 not found")
+	}
+
+	// the neighbour address sub-TLV wins
+	setTestNeighbourAddresses(a, map[byte]uint32{2: 0x0a000c03})
+	for _, route := range db.Spf(ISIS_LEVEL_2) {
+		if route.Prefix == "2.2.2.0/24" &&
+			(len(route.NextHops) != 1 || route.NextHops[0].NextHop != "10.0.12.3") {
+			t.Fatalf("failed NextHops with sub-TLV: %v", route.NextHops)
+		}
+	}
+
+	// the adjacencies of a level are not the ones of the other
+	db.adjacency(ISIS_LEVEL_1, [packet.SYSTEM_ID_LENGTH]byte{0, 0, 0, 0, 0, 2})
+	adjacency := db.adjacency(ISIS_LEVEL_2, [packet.SYSTEM_ID_LENGTH]byte{0, 0, 0, 0, 0, 2})
+	if len(adjacency.ipv4Addresses) != 1 || adjacency.ipv4Addresses[0] != 0x0a000c03 {
+		t.Fatalf("failed level 2 adjacency: %v", adjacency.ipv4Addresses)
+	}
+}
+
+func TestOfflineLsdbWhatIf(t *testing.T) {
+	db := NewOfflineLsdb()
+	lsp := newTestLsp(t, 1, 1, []byte{2, 3}, 0x0a000100, "a")
+	setTestNeighbourAddresses(lsp, map[byte]uint32{2: 0x0a000201, 3: 0x0a000301})
+	db.AddLsp(lsp)
+	db.AddLsp(newTestLsp(t, 2, 1, []byte{1, 4}, 0x0a000200, "b"))
+	db.AddLsp(newTestLsp(t, 3, 1, []byte{1, 4}, 0x0a000300, "c"))
+	db.AddLsp(newTestLsp(t, 4, 1, []byte{2, 3}, 0x0a000400, "d"))
+	db.SetSystemId([packet.SYSTEM_ID_LENGTH]byte{0, 0, 0, 0, 0, 1})
+
+	a := [packet.NEIGHBOUR_ID_LENGTH]byte{0, 0, 0, 0, 0, 1, 0}
+	b := [packet.NEIGHBOUR_ID_LENGTH]byte{0, 0, 0, 0, 0, 2, 0}
+	c := [packet.NEIGHBOUR_ID_LENGTH]byte{0, 0, 0, 0, 0, 3, 0}
+	d := [packet.NEIGHBOUR_ID_LENGTH]byte{0, 0, 0, 0, 0, 4, 0}
+
+	// c towards d becomes expensive
+	db.SetMetric(c, d, 100)
+	for _, route := range db.Spf(ISIS_LEVEL_2) {
+		if route.Prefix != "10.0.4.0/24" {
+			continue
+		}
+		if route.Metric != 30 {
+			t.Fatalf("failed Metric: %d", route.Metric)
+		}
+		if len(route.NextHops) != 1 || route.NextHops[0].OutgoingInterface != "b" {
+			t.Fatalf("failed NextHops: %v", route.NextHops)
+		}
+	}
+
+	// a loses b as well, so d is only reachable through c
+	db.FailLink(b, a)
+	found := false
+	for _, route := range db.Spf(ISIS_LEVEL_2) {
+		if route.Prefix == "10.0.2.0/24" && route.Metric != 130 {
+			t.Fatalf("failed 10.0.2.0/24 Metric: %d", route.Metric)
+		}
+		if route.Prefix != "10.0.4.0/24" {
+			continue
+		}
+		found = true
+		if route.Metric != 120 {
+			t.Fatalf("failed Metric: %d", route.Metric)
+		}
+		if len(route.NextHops) != 1 || route.NextHops[0].OutgoingInterface != "c" {
+			t.Fatalf("failed NextHops: %v", route.NextHops)
+		}
+	}
+	if !found {
+		t.Fatalf("failed 10.0.4.0/24 not found")
+	}
+}
+
+func TestLsdbExportImport(t *testing.T) {
+	isis := NewIsisServer("", "toml")
+	isis.systemId = [packet.SYSTEM_ID_LENGTH]byte{0, 0, 0, 0, 0, 1}
+	for i := byte(1); i <= 2; i++ {
+		lsp := newTestLsp(t, i, 1, []byte{3 - i}, 0x0a000000+uint32(i)<<8, "r")
+		lsp.SetChecksum()
+		ls, _ := NewLs(lsp, false, nil)
+		isis.lsDb[ISIS_LEVEL_2] = append(isis.lsDb[ISIS_LEVEL_2], ls)
+	}
+
+	data, err := isis.ExportLsdb(ISIS_LEVEL_ALL)
+	if err != nil {
+		t.Fatalf("failed ExportLsdb: %#v", err)
+	}
+	db := NewOfflineLsdb()
+	err = db.ImportLsdb(data)
+	if err != nil {
+		t.Fatalf("failed ImportLsdb: %#v", err)
+	}
+	lsps := db.Lsps(ISIS_LEVEL_2)
+	if len(lsps) != 2 {
+		t.Fatalf("failed len(lsps): %d", len(lsps))
+	}
+	for i, lsp := range lsps {
+		d1, _ := isis.lsDb[ISIS_LEVEL_2][i].pdu.Serialize()
+		d2, _ := lsp.Serialize()
+		if !bytes.Equal(d1, d2) {
+			t.Fatalf("failed !bytes.Equal\n%x\n%x", d1, d2)
+		}
+	}
+
+	// an entry without binary is rebuilt from its decoded form
+	var export LsdbExport
+	json.Unmarshal(data, &export)
+	export.Lsps[1].Binary = nil
+	export.Lsps[1].Pdu.SequenceNumber = 2
+	data, _ = json.Marshal(export)
+	lsps, err = ParseLsdbExport(data)
+	if err != nil {
+		t.Fatalf("failed ParseLsdbExport: %#v", err)
+	}
+	if lsps[1].SequenceNumber != 2 {
+		t.Fatalf("failed SequenceNumber: %d", lsps[1].SequenceNumber)
+	}
+	if lsps[1].Checksum == isis.lsDb[ISIS_LEVEL_2][1].pdu.Checksum {
+		t.Fatalf("failed Checksum not recomputed")
+	}
+
+	export.Lsps[0].Level = "level-1"
+	data, _ = json.Marshal(export)
+	if _, err := ParseLsdbExport(data); err == nil {
+		t.Fatalf("failed level mismatch accepted")
+	}
+}
+
+func TestLsdbExportWalk(t *testing.T) {
+	isis := NewIsisServer("", "toml")
+	isis.systemId = [packet.SYSTEM_ID_LENGTH]byte{0, 0, 0, 0, 0, 1}
+	filename := filepath.Join(t.TempDir(), "lsdb.json")
+	previous := []byte("previous")
+	if err := os.WriteFile(filename, previous, 0644); err != nil {
+		t.Fatalf("failed WriteFile: %#v", err)
+	}
+	if err := isis.SetLsdbExport(filename, 2); err != nil {
+		t.Fatalf("failed SetLsdbExport: %#v", err)
+	}
+	check := func(written bool) {
+		data, _ := os.ReadFile(filename)
+		if bytes.Equal(data, previous) == written {
+			t.Fatalf("failed written %v: %s", written, data)
+		}
+	}
+
+	// neither the first tick nor an empty LSDB replaces the last export
+	isis.lsdbExportWalk(0)
+	check(false)
+	isis.lsdbExportWalk(2)
+	check(false)
+
+	lsp := newTestLsp(t, 1, 1, []byte{2}, 0x0a000100, "r")
+	lsp.SetChecksum()
+	ls, _ := NewLs(lsp, false, nil)
+	isis.lsDb[ISIS_LEVEL_2] = append(isis.lsDb[ISIS_LEVEL_2], ls)
+	isis.lsdbExportWalk(0)
+	check(false)
+	isis.lsdbExportWalk(1)
+	check(false)
+	isis.lsdbExportWalk(2)
+	check(true)
+}
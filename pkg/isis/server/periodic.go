@@ -37,7 +37,7 @@ func (isis *IsisServer) lsDbIter(ls *Ls) bool {
 			changed = true
 		}
 		if ls.expired.Before(time.Now().Add(-ZERO_AGE_LIFETIME)) {
-			isis.deleteLsp(ls, true)
+			isis.deleteLsp(ls)
 		}
 	}
 	return changed
@@ -96,6 +96,7 @@ func (isis *IsisServer) periodic(doneCh chan struct{}) {
 		case <-timer.C:
 			isis.lsDbWalk()
 			isis.adjDbWalk()
+			isis.lsdbExportWalk(int(counter))
 			counter++
 			timer.Reset(started.Add(time.Second * counter).Sub(time.Now()))
 		}
@@ -0,0 +1,436 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/m-asama/golsr/internal/pkg/isis/config"
+)
+
+type PolicyAction uint8
+
+const (
+	_ PolicyAction = iota
+	POLICY_ACTION_NONE
+	POLICY_ACTION_ACCEPT_ROUTE
+	POLICY_ACTION_REJECT_ROUTE
+)
+
+func (action PolicyAction) String() string {
+	switch action {
+	case POLICY_ACTION_NONE:
+		return "POLICY_ACTION_NONE"
+	case POLICY_ACTION_ACCEPT_ROUTE:
+		return "POLICY_ACTION_ACCEPT_ROUTE"
+	case POLICY_ACTION_REJECT_ROUTE:
+		return "POLICY_ACTION_REJECT_ROUTE"
+	}
+	return fmt.Sprintf("PolicyAction(%d)", action)
+}
+
+func policyAction(routeDisposition *string) PolicyAction {
+	if routeDisposition != nil {
+		switch *routeDisposition {
+		case "accept-route":
+			return POLICY_ACTION_ACCEPT_ROUTE
+		case "reject-route":
+			return POLICY_ACTION_REJECT_ROUTE
+		}
+	}
+	return POLICY_ACTION_NONE
+}
+
+// policyRoute is a route as the policies see it: the prefix with the level
+// and the kind of reachability it came from, and the metric, tags and
+// up/down bit the actions may change.
+type policyRoute struct {
+	prefix   *net.IPNet
+	level    IsisLevel
+	external bool
+	metric   uint32
+	tags     []uint32
+	down     bool
+}
+
+func newPolicyRouteIpv4(ri *Ipv4Ri) *policyRoute {
+	ip := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(ip, ri.prefixAddress)
+	return &policyRoute{
+		prefix: &net.IPNet{
+			IP:   ip,
+			Mask: net.CIDRMask(int(ri.prefixLength), 8*net.IPv4len),
+		},
+		level:    ri.level,
+		external: ri.external,
+		metric:   ri.metric,
+		tags:     append([]uint32{}, ri.tags...),
+		down:     ri.down,
+	}
+}
+
+func newPolicyRouteIpv6(ri *Ipv6Ri) *policyRoute {
+	ip := make(net.IP, net.IPv6len)
+	for i := 0; i < 4; i++ {
+		binary.BigEndian.PutUint32(ip[i*4:i*4+4], ri.prefixAddress[i])
+	}
+	return &policyRoute{
+		prefix: &net.IPNet{
+			IP:   ip,
+			Mask: net.CIDRMask(int(ri.prefixLength), 8*net.IPv6len),
+		},
+		level:    ri.level,
+		external: ri.external,
+		metric:   ri.metric,
+		tags:     append([]uint32{}, ri.tags...),
+		down:     ri.down,
+	}
+}
+
+func (isis *IsisServer) findPrefixSet(name string) *config.PrefixSet {
+	for _, ps := range isis.config.DefinedSets.PrefixSets {
+		if *ps.Config.PrefixSetName == name {
+			return ps
+		}
+	}
+	return nil
+}
+
+func (isis *IsisServer) findTagSet(name string) *config.TagSet {
+	for _, ts := range isis.config.DefinedSets.TagSets {
+		if *ts.Config.TagSetName == name {
+			return ts
+		}
+	}
+	return nil
+}
+
+func (isis *IsisServer) findPolicyDefinition(name string) *config.PolicyDefinition {
+	for _, pd := range isis.config.PolicyDefinitions {
+		if *pd.Config.Name == name {
+			return pd
+		}
+	}
+	return nil
+}
+
+// matchPrefixSet reports whether a prefix of the prefix set name covers
+// the prefix of route with a prefix length in its masklength-range.
+func (isis *IsisServer) matchPrefixSet(name string, route *policyRoute) bool {
+	ps := isis.findPrefixSet(name)
+	if ps == nil {
+		return false
+	}
+	length, bits := route.prefix.Mask.Size()
+	for _, prefix := range ps.Prefixes {
+		ipnet, min, max, err := prefix.ParsePrefix()
+		if err != nil {
+			continue
+		}
+		if _, b := ipnet.Mask.Size(); b != bits {
+			continue
+		}
+		if length < min || length > max {
+			continue
+		}
+		if ipnet.Contains(route.prefix.IP) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchTagSet reports whether route carries any tag of the tag set name.
+func (isis *IsisServer) matchTagSet(name string, route *policyRoute) bool {
+	ts := isis.findTagSet(name)
+	if ts == nil {
+		return false
+	}
+	for _, tag := range ts.Config.Tag {
+		for _, rtag := range route.tags {
+			if *tag == rtag {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchConditions reports whether route meets all the conditions, any
+// condition left out being met.
+func (isis *IsisServer) matchConditions(conditions *config.ConditionsConfig, route *policyRoute) bool {
+	if conditions.MatchPrefixSet != nil {
+		invert := conditions.MatchPrefixSetOptions != nil &&
+			*conditions.MatchPrefixSetOptions == "invert"
+		if isis.matchPrefixSet(*conditions.MatchPrefixSet, route) == invert {
+			return false
+		}
+	}
+	if conditions.MatchTagSet != nil {
+		invert := conditions.MatchTagSetOptions != nil &&
+			*conditions.MatchTagSetOptions == "invert"
+		if isis.matchTagSet(*conditions.MatchTagSet, route) == invert {
+			return false
+		}
+	}
+	if conditions.Level != nil && *conditions.Level != route.level.String2() {
+		return false
+	}
+	if conditions.RouteType != nil {
+		external := *conditions.RouteType == "external"
+		if external != route.external {
+			return false
+		}
+	}
+	return true
+}
+
+func applyActions(actions *config.ActionsConfig, route *policyRoute) PolicyAction {
+	if actions.SetMetric != nil {
+		route.metric = *actions.SetMetric
+	}
+	if actions.SetTag != nil {
+		route.tags = []uint32{*actions.SetTag}
+	}
+	if actions.SetUpDown != nil {
+		route.down = *actions.SetUpDown
+	}
+	return policyAction(actions.RouteDisposition)
+}
+
+// applyPolicies runs route through the statements of the policies names in
+// order. The actions of each statement whose conditions route meets are
+// applied to it, and the first accept-route or reject-route decides.
+// Without one, defaultAction does, the changes made so far being kept.
+func (isis *IsisServer) applyPolicies(names []*string, route *policyRoute, defaultAction PolicyAction) PolicyAction {
+	for _, name := range names {
+		pd := isis.findPolicyDefinition(*name)
+		if pd == nil {
+			continue
+		}
+		for _, stmt := range pd.Statements {
+			if !isis.matchConditions(&stmt.Conditions.Config, route) {
+				continue
+			}
+			action := applyActions(&stmt.Actions.Config, route)
+			if action != POLICY_ACTION_NONE {
+				return action
+			}
+		}
+	}
+	return defaultAction
+}
+
+// installPolicy runs route through the install policies, which decide
+// whether and with which metric it is installed into the kernel.
+func (isis *IsisServer) installPolicy(route *policyRoute) PolicyAction {
+	if isis.config == nil {
+		return POLICY_ACTION_ACCEPT_ROUTE
+	}
+	c := isis.config.ApplyPolicy.Config
+	defaultAction := policyAction(c.DefaultInstallPolicy)
+	if defaultAction == POLICY_ACTION_NONE {
+		defaultAction = POLICY_ACTION_ACCEPT_ROUTE
+	}
+	return isis.applyPolicies(c.InstallPolicy, route, defaultAction)
+}
+
+// leakPolicy runs route of the other level through the leak policies,
+// which decide whether and how it is leaked into level. Level 1 routes
+// are leaked into level 2 unless rejected (RFC 1195 3.1), level 2 routes
+// into level 1 only if accepted (RFC 5302 3.1). The up/down bit of the
+// latter stays set whatever the policies do (RFC 5302 3.3).
+func (isis *IsisServer) leakPolicy(level IsisLevel, route *policyRoute) PolicyAction {
+	defaultAction := POLICY_ACTION_ACCEPT_ROUTE
+	if level == ISIS_LEVEL_1 {
+		defaultAction = POLICY_ACTION_REJECT_ROUTE
+	}
+	action := defaultAction
+	if isis.config != nil {
+		action = isis.applyPolicies(isis.config.ApplyPolicy.Config.LeakPolicy, route, defaultAction)
+	}
+	if level == ISIS_LEVEL_1 {
+		route.down = true
+	}
+	return action
+}
+
+// changePolicies applies change to a copy of the config and puts it in
+// place if change succeeds and the policies are valid, the routes leaked
+// and installed then following. change replaces the slices it changes
+// rather than modifying them. The policies changed here are kept when
+// the config file is reloaded.
+func (isis *IsisServer) changePolicies(change func(c *config.IsisConfig) error) error {
+	isis.lock.Lock()
+	newConfig := *isis.config
+	newConfig.CopyPolicies()
+	if err := change(&newConfig); err != nil {
+		isis.lock.Unlock()
+		return err
+	}
+	if err := newConfig.ValidatePolicies(); err != nil {
+		isis.lock.Unlock()
+		return err
+	}
+	isis.config = &newConfig
+	isis.apiPolicies = true
+	isis.lock.Unlock()
+	isis.updateChSend(&UpdateChMsg{
+		msgType: UPDATE_CH_MSG_TYPE_CONFIG_CHANGED,
+	})
+	return nil
+}
+
+// AddDefinedSets adds prefixSets and tagSets, replacing the sets of the
+// same names.
+func (isis *IsisServer) AddDefinedSets(prefixSets []*config.PrefixSet, tagSets []*config.TagSet) error {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	return isis.changePolicies(func(c *config.IsisConfig) error {
+		newPrefixSets := make([]*config.PrefixSet, 0)
+		for _, ps := range c.DefinedSets.PrefixSets {
+			replaced := false
+			for _, nps := range prefixSets {
+				if nps.Config.PrefixSetName != nil &&
+					*nps.Config.PrefixSetName == *ps.Config.PrefixSetName {
+					replaced = true
+				}
+			}
+			if !replaced {
+				newPrefixSets = append(newPrefixSets, ps)
+			}
+		}
+		c.DefinedSets.PrefixSets = append(newPrefixSets, prefixSets...)
+		newTagSets := make([]*config.TagSet, 0)
+		for _, ts := range c.DefinedSets.TagSets {
+			replaced := false
+			for _, nts := range tagSets {
+				if nts.Config.TagSetName != nil &&
+					*nts.Config.TagSetName == *ts.Config.TagSetName {
+					replaced = true
+				}
+			}
+			if !replaced {
+				newTagSets = append(newTagSets, ts)
+			}
+		}
+		c.DefinedSets.TagSets = append(newTagSets, tagSets...)
+		return nil
+	})
+}
+
+// DeleteDefinedSets deletes the prefix sets and the tag sets of the names
+// given. Sets still used by a policy are not.
+func (isis *IsisServer) DeleteDefinedSets(prefixSetNames, tagSetNames []string) error {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	return isis.changePolicies(func(c *config.IsisConfig) error {
+		newPrefixSets := make([]*config.PrefixSet, 0)
+		for _, ps := range c.DefinedSets.PrefixSets {
+			if !stringsContain(prefixSetNames, *ps.Config.PrefixSetName) {
+				newPrefixSets = append(newPrefixSets, ps)
+			}
+		}
+		if len(c.DefinedSets.PrefixSets)-len(newPrefixSets) != len(prefixSetNames) {
+			return errors.New("prefix set not defined")
+		}
+		c.DefinedSets.PrefixSets = newPrefixSets
+		newTagSets := make([]*config.TagSet, 0)
+		for _, ts := range c.DefinedSets.TagSets {
+			if !stringsContain(tagSetNames, *ts.Config.TagSetName) {
+				newTagSets = append(newTagSets, ts)
+			}
+		}
+		if len(c.DefinedSets.TagSets)-len(newTagSets) != len(tagSetNames) {
+			return errors.New("tag set not defined")
+		}
+		c.DefinedSets.TagSets = newTagSets
+		return nil
+	})
+}
+
+// AddPolicies adds policies, replacing the policies of the same names.
+func (isis *IsisServer) AddPolicies(policies []*config.PolicyDefinition) error {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	return isis.changePolicies(func(c *config.IsisConfig) error {
+		newPolicies := make([]*config.PolicyDefinition, 0)
+		for _, pd := range c.PolicyDefinitions {
+			replaced := false
+			for _, npd := range policies {
+				if npd.Config.Name != nil && *npd.Config.Name == *pd.Config.Name {
+					replaced = true
+				}
+			}
+			if !replaced {
+				newPolicies = append(newPolicies, pd)
+			}
+		}
+		c.PolicyDefinitions = append(newPolicies, policies...)
+		return nil
+	})
+}
+
+// DeletePolicies deletes the policies of names. Policies still applied
+// are not.
+func (isis *IsisServer) DeletePolicies(names []string) error {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	return isis.changePolicies(func(c *config.IsisConfig) error {
+		newPolicies := make([]*config.PolicyDefinition, 0)
+		for _, pd := range c.PolicyDefinitions {
+			if !stringsContain(names, *pd.Config.Name) {
+				newPolicies = append(newPolicies, pd)
+			}
+		}
+		if len(c.PolicyDefinitions)-len(newPolicies) != len(names) {
+			return errors.New("policy not defined")
+		}
+		c.PolicyDefinitions = newPolicies
+		return nil
+	})
+}
+
+// SetPolicyAssignment applies the policies installPolicies in order to the
+// routes installed into the kernel, defaultInstallPolicy deciding those
+// no policy does, and leakPolicies to the routes leaked between the
+// levels. A nil defaultInstallPolicy is accept-route.
+func (isis *IsisServer) SetPolicyAssignment(installPolicies []*string, defaultInstallPolicy *string, leakPolicies []*string) error {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	return isis.changePolicies(func(c *config.IsisConfig) error {
+		c.ApplyPolicy.Config.InstallPolicy = installPolicies
+		c.ApplyPolicy.Config.DefaultInstallPolicy = defaultInstallPolicy
+		c.ApplyPolicy.Config.LeakPolicy = leakPolicies
+		return nil
+	})
+}
+
+func stringsContain(list []string, s string) bool {
+	for _, tmp := range list {
+		if tmp == s {
+			return true
+		}
+	}
+	return false
+}
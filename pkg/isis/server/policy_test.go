@@ -0,0 +1,156 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"net"
+	"testing"
+
+	"github.com/m-asama/golsr/internal/pkg/isis/config"
+)
+
+const testPolicyConfig = `
+[config]
+  system-id = "0000.0000.0001"
+  area-address-list = ["49.0001"]
+
+[[defined-sets.prefix-sets]]
+  [defined-sets.prefix-sets.config]
+    prefix-set-name = "lo"
+  [[defined-sets.prefix-sets.prefixes]]
+    [defined-sets.prefix-sets.prefixes.config]
+      ip-prefix = "192.168.0.0/24"
+      masklength-range = "32..32"
+
+[[policy-definitions]]
+  [policy-definitions.config]
+    name = "leak-lo"
+  [[policy-definitions.statements]]
+    [policy-definitions.statements.config]
+      name = "s1"
+    [policy-definitions.statements.conditions.config]
+      match-prefix-set = "lo"
+    [policy-definitions.statements.actions.config]
+      route-disposition = "accept-route"
+      set-up-down = false
+
+[apply-policy.config]
+  leak-policy-list = ["leak-lo"]
+`
+
+func newTestPolicyServer(t *testing.T) *IsisServer {
+	cfg, err := config.Parse([]byte(testPolicyConfig), "toml")
+	if err != nil {
+		t.Fatalf("failed Parse: %#v", err)
+	}
+	isis := NewIsisServer("", "")
+	isis.lock.Lock()
+	isis.handleConfigChanged(cfg)
+	isis.lock.Unlock()
+	return isis
+}
+
+func TestLeakPolicyUpDown(t *testing.T) {
+	isis := newTestPolicyServer(t)
+	_, prefix, _ := net.ParseCIDR("192.168.0.1/32")
+
+	// RFC 5302 3.3
+	route := &policyRoute{prefix: prefix, level: ISIS_LEVEL_2, metric: 10}
+	if action := isis.leakPolicy(ISIS_LEVEL_1, route); action != POLICY_ACTION_ACCEPT_ROUTE {
+		t.Fatalf("failed leakPolicy level-1: %s", action)
+	}
+	if !route.down {
+		t.Fatalf("failed up/down bit cleared on the route leaked into level 1")
+	}
+
+	route = &policyRoute{prefix: prefix, level: ISIS_LEVEL_1, metric: 10, down: true}
+	if action := isis.leakPolicy(ISIS_LEVEL_2, route); action != POLICY_ACTION_ACCEPT_ROUTE {
+		t.Fatalf("failed leakPolicy level-2: %s", action)
+	}
+	if route.down {
+		t.Fatalf("failed up/down bit not cleared on the route leaked into level 2")
+	}
+}
+
+func TestChangePolicies(t *testing.T) {
+	isis := newTestPolicyServer(t)
+
+	// the defaults filled in while validating a change go to the copy
+	old := isis.config
+	stmt := old.PolicyDefinitions[0].Statements[0]
+	stmt.Conditions.Config.MatchPrefixSetOptions = nil
+	if err := isis.SetPolicyAssignment(nil, nil, nil); err != nil {
+		t.Fatalf("failed SetPolicyAssignment: %#v", err)
+	}
+	if stmt.Conditions.Config.MatchPrefixSetOptions != nil {
+		t.Fatalf("failed statement of the old config changed")
+	}
+	if old.ApplyPolicy.Config.LeakPolicy == nil {
+		t.Fatalf("failed apply-policy of the old config changed")
+	}
+
+	// reloading the config file keeps the policies changed over the API
+	cfg, err := config.Parse([]byte(testPolicyConfig), "toml")
+	if err != nil {
+		t.Fatalf("failed Parse: %#v", err)
+	}
+	isis.lock.Lock()
+	isis.handleConfigChanged(cfg)
+	isis.lock.Unlock()
+	if len(isis.config.ApplyPolicy.Config.LeakPolicy) != 0 {
+		t.Fatalf("failed leak-policy-list reloaded: %d", len(isis.config.ApplyPolicy.Config.LeakPolicy))
+	}
+	if isis.findPolicyDefinition("leak-lo") == nil || isis.findPrefixSet("lo") == nil {
+		t.Fatalf("failed policies lost")
+	}
+}
+
+func TestLeakNarrowMetric(t *testing.T) {
+	isis := newTestPolicyServer(t)
+	oldOnly := "old-only"
+	isis.config.MetricType.Level1.Config.Value = &oldOnly
+	isis.config.MetricType.Level2.Config.Value = &oldOnly
+
+	key := NewSpfIdIpv4(0x0a000000, 24).key()
+	nexthops := []*Ipv4Nh{&Ipv4Nh{nexthopAddress: 0x0a000102}}
+	isis.ipv4RiDb[ISIS_LEVEL_1][key] = &Ipv4Ri{
+		level:         ISIS_LEVEL_1,
+		prefixAddress: 0x0a000000,
+		prefixLength:  24,
+		metric:        100,
+		nexthops:      nexthops,
+	}
+	isis.ipv4RiDb[ISIS_LEVEL_2][key] = &Ipv4Ri{
+		level:         ISIS_LEVEL_2,
+		prefixAddress: 0x0a000000,
+		prefixLength:  24,
+		metric:        100,
+		nexthops:      nexthops,
+	}
+
+	// the level 1 routes go up into level 2 without the up/down bit
+	leaked := isis.leakedIpv4Reachabilities(ISIS_LEVEL_2)
+	if len(leaked) != 1 || leaked[0].wideMetric || leaked[0].down {
+		t.Fatalf("failed leaked into level 2: %#v", leaked)
+	}
+
+	// but none comes down into level 1 where it may loop
+	if leaked := isis.leakedIpv4Reachabilities(ISIS_LEVEL_1); len(leaked) != 0 {
+		t.Fatalf("failed leaked into level 1: %#v", leaked)
+	}
+}
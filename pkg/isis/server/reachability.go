@@ -118,6 +118,9 @@ type Ipv4Reachability struct {
 	lspNumber    int
 	wideMetric   bool
 	down         bool
+	external     bool
+	tags         []uint32
+	leaked       bool
 	ls           *Ls
 }
 
@@ -166,6 +169,18 @@ func (isis *IsisServer) newIpv4Reachabilities(level IsisLevel) []*Ipv4Reachabili
 			new = append(new, ipv4r)
 		}
 	}
+	for _, ltmp := range isis.leakedIpv4Reachabilities(level) {
+		found := false
+		for _, ntmp := range new {
+			if ntmp.ipv4Prefix == ltmp.ipv4Prefix &&
+				ntmp.prefixLength == ltmp.prefixLength {
+				found = true
+			}
+		}
+		if !found {
+			new = append(new, ltmp)
+		}
+	}
 	for _, ctmp := range isis.ipv4Reachabilities[level] {
 		for _, ntmp := range new {
 			if ntmp.ipv4Prefix == ctmp.ipv4Prefix &&
@@ -188,7 +203,9 @@ func (isis *IsisServer) ipv4ReachabilitiesChanged(level IsisLevel, new []*Ipv4Re
 	for i := 0; i < len(current); i++ {
 		if current[i].ipv4Prefix != new[i].ipv4Prefix ||
 			current[i].prefixLength != new[i].prefixLength ||
-			current[i].metric != new[i].metric {
+			current[i].metric != new[i].metric ||
+			current[i].down != new[i].down ||
+			!tagsEqual(current[i].tags, new[i].tags) {
 			return true
 		}
 	}
@@ -204,6 +221,8 @@ type Ipv6Reachability struct {
 	lspNumber    int
 	down         bool
 	external     bool
+	tags         []uint32
+	leaked       bool
 	ls           *Ls
 }
 
@@ -267,6 +286,18 @@ func (isis *IsisServer) newIpv6Reachabilities(level IsisLevel) []*Ipv6Reachabili
 			new = append(new, ipv6r)
 		}
 	}
+	for _, ltmp := range isis.leakedIpv6Reachabilities(level) {
+		found := false
+		for _, ntmp := range new {
+			if ntmp.ipv6Prefix == ltmp.ipv6Prefix &&
+				ntmp.prefixLength == ltmp.prefixLength {
+				found = true
+			}
+		}
+		if !found {
+			new = append(new, ltmp)
+		}
+	}
 	for _, ctmp := range isis.ipv6Reachabilities[level] {
 		for _, ntmp := range new {
 			if ntmp.ipv6Prefix[0] == ctmp.ipv6Prefix[0] &&
@@ -295,7 +326,9 @@ func (isis *IsisServer) ipv6ReachabilitiesChanged(level IsisLevel, new []*Ipv6Re
 			current[i].ipv6Prefix[2] != new[i].ipv6Prefix[2] ||
 			current[i].ipv6Prefix[3] != new[i].ipv6Prefix[3] ||
 			current[i].prefixLength != new[i].prefixLength ||
-			current[i].metric != new[i].metric {
+			current[i].metric != new[i].metric ||
+			current[i].down != new[i].down ||
+			!tagsEqual(current[i].tags, new[i].tags) {
 			return true
 		}
 	}
@@ -320,7 +353,7 @@ func NewReachabilities() *Reachabilities {
 func (rs *Reachabilities) addIsReachability(ir *IsReachability) {
 	newIrs := make([]*IsReachability, 0)
 	for _, irTmp := range rs.isReachabilities {
-		if bytes.Compare(irTmp.neighborId[:], ir.neighborId[:]) != 0 &&
+		if bytes.Compare(irTmp.neighborId[:], ir.neighborId[:]) != 0 ||
 			irTmp.metric != ir.metric {
 			newIrs = append(newIrs, irTmp)
 		}
@@ -329,27 +362,50 @@ func (rs *Reachabilities) addIsReachability(ir *IsReachability) {
 	rs.isReachabilities = newIrs
 }
 
+// betterReachability reports whether a reachability of metric and external
+// is better than another one to the same prefix: an internal one wins
+// whatever the metric as in RFC 1195 3.10 and RFC 5302, and then the lower
+// metric.
+func betterReachability(metric uint32, external bool, otherMetric uint32, otherExternal bool) bool {
+	if external != otherExternal {
+		return !external
+	}
+	return metric < otherMetric
+}
+
+// addIpv4Reachability adds ir unless the node already advertises a better
+// reachability to the same prefix, which it replaces otherwise.
 func (rs *Reachabilities) addIpv4Reachability(ir *Ipv4Reachability) {
 	newIrs := make([]*Ipv4Reachability, 0)
 	for _, irTmp := range rs.ipv4Reachabilities {
-		if irTmp.ipv4Prefix != ir.ipv4Prefix &&
+		if irTmp.ipv4Prefix != ir.ipv4Prefix ||
 			irTmp.prefixLength != ir.prefixLength {
 			newIrs = append(newIrs, irTmp)
+			continue
+		}
+		if !betterReachability(ir.metric, ir.external, irTmp.metric, irTmp.external) {
+			return
 		}
 	}
 	newIrs = append(newIrs, ir)
 	rs.ipv4Reachabilities = newIrs
 }
 
+// addIpv6Reachability adds ir unless the node already advertises a better
+// reachability to the same prefix, which it replaces otherwise.
 func (rs *Reachabilities) addIpv6Reachability(ir *Ipv6Reachability) {
 	newIrs := make([]*Ipv6Reachability, 0)
 	for _, irTmp := range rs.ipv6Reachabilities {
-		if irTmp.ipv6Prefix[0] != ir.ipv6Prefix[0] &&
-			irTmp.ipv6Prefix[1] != ir.ipv6Prefix[1] &&
-			irTmp.ipv6Prefix[2] != ir.ipv6Prefix[2] &&
-			irTmp.ipv6Prefix[3] != ir.ipv6Prefix[3] &&
+		if irTmp.ipv6Prefix[0] != ir.ipv6Prefix[0] ||
+			irTmp.ipv6Prefix[1] != ir.ipv6Prefix[1] ||
+			irTmp.ipv6Prefix[2] != ir.ipv6Prefix[2] ||
+			irTmp.ipv6Prefix[3] != ir.ipv6Prefix[3] ||
 			irTmp.prefixLength != ir.prefixLength {
 			newIrs = append(newIrs, irTmp)
+			continue
+		}
+		if !betterReachability(ir.metric, ir.external, irTmp.metric, irTmp.external) {
+			return
 		}
 	}
 	newIrs = append(newIrs, ir)
@@ -0,0 +1,81 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"testing"
+)
+
+func TestAddIpv4Reachability(t *testing.T) {
+	rs := NewReachabilities()
+	// TLV 128 first, then the external copy of TLV 130
+	rs.addIpv4Reachability(&Ipv4Reachability{ipv4Prefix: 0xc0a80100, prefixLength: 24, metric: 10})
+	rs.addIpv4Reachability(&Ipv4Reachability{ipv4Prefix: 0xc0a80100, prefixLength: 24, metric: 10, external: true})
+	rs.addIpv4Reachability(&Ipv4Reachability{ipv4Prefix: 0xc0a80200, prefixLength: 24, metric: 20})
+	if len(rs.ipv4Reachabilities) != 2 || rs.ipv4Reachabilities[0].external {
+		t.Fatalf("failed internal before external: %#v", rs.ipv4Reachabilities)
+	}
+	// an external one does not replace an internal one whatever the
+	// metric
+	rs.addIpv4Reachability(&Ipv4Reachability{ipv4Prefix: 0xc0a80100, prefixLength: 24, metric: 5, external: true})
+	if len(rs.ipv4Reachabilities) != 2 || rs.ipv4Reachabilities[0].metric != 10 ||
+		rs.ipv4Reachabilities[0].external {
+		t.Fatalf("failed external of lower metric: %#v", rs.ipv4Reachabilities)
+	}
+	// a higher metric does not replace, a lower one does
+	rs.addIpv4Reachability(&Ipv4Reachability{ipv4Prefix: 0xc0a80200, prefixLength: 24, metric: 30})
+	rs.addIpv4Reachability(&Ipv4Reachability{ipv4Prefix: 0xc0a80100, prefixLength: 24, metric: 5})
+	if len(rs.ipv4Reachabilities) != 2 {
+		t.Fatalf("failed reachabilities: %#v", rs.ipv4Reachabilities)
+	}
+	for _, ir := range rs.ipv4Reachabilities {
+		switch ir.ipv4Prefix {
+		case 0xc0a80100:
+			if ir.metric != 5 || ir.external {
+				t.Fatalf("failed lower metric: %#v", ir)
+			}
+		case 0xc0a80200:
+			if ir.metric != 20 {
+				t.Fatalf("failed higher metric: %#v", ir)
+			}
+		}
+	}
+	// the same prefix with another length is another reachability
+	rs.addIpv4Reachability(&Ipv4Reachability{ipv4Prefix: 0xc0a80100, prefixLength: 25, metric: 50})
+	if len(rs.ipv4Reachabilities) != 3 {
+		t.Fatalf("failed prefix length: %#v", rs.ipv4Reachabilities)
+	}
+}
+
+func TestAddIpv6Reachability(t *testing.T) {
+	rs := NewReachabilities()
+	prefix := [4]uint32{0x20010db8, 0, 0, 0}
+	rs.addIpv6Reachability(&Ipv6Reachability{ipv6Prefix: prefix, prefixLength: 64, metric: 10})
+	rs.addIpv6Reachability(&Ipv6Reachability{ipv6Prefix: prefix, prefixLength: 64, metric: 10, external: true})
+	rs.addIpv6Reachability(&Ipv6Reachability{ipv6Prefix: prefix, prefixLength: 64, metric: 20})
+	if len(rs.ipv6Reachabilities) != 1 || rs.ipv6Reachabilities[0].metric != 10 ||
+		rs.ipv6Reachabilities[0].external {
+		t.Fatalf("failed reachabilities: %#v", rs.ipv6Reachabilities)
+	}
+	// prefixes differing in one word only are different
+	other := [4]uint32{0x20010db8, 0, 1, 0}
+	rs.addIpv6Reachability(&Ipv6Reachability{ipv6Prefix: other, prefixLength: 64, metric: 30})
+	if len(rs.ipv6Reachabilities) != 2 {
+		t.Fatalf("failed other prefix: %#v", rs.ipv6Reachabilities)
+	}
+}
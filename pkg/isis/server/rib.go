@@ -0,0 +1,108 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+// preference returns the administrative distance of an internal or an
+// external route, the preference configured for it or else the default
+// one, DEFAULT_PREFERENCE if there is neither.
+func (isis *IsisServer) preference(external bool) uint8 {
+	if isis.config == nil {
+		return DEFAULT_PREFERENCE
+	}
+	c := isis.config.Preference.Config
+	preferences := []*uint8{c.Internal, c.Default}
+	if external {
+		preferences = []*uint8{c.External, c.Default}
+	}
+	for _, preference := range preferences {
+		if preference != nil {
+			return *preference
+		}
+	}
+	return DEFAULT_PREFERENCE
+}
+
+// better reports whether a route of level and external and metric wins
+// over the other one: the lower preference wins, then an internal route
+// over an external one, a level 1 route over a level 2 one (RFC 1195
+// 3.10.2) and the lower metric.
+func (isis *IsisServer) better(level IsisLevel, external bool, metric uint32,
+	otherLevel IsisLevel, otherExternal bool, otherMetric uint32) bool {
+	preference := isis.preference(external)
+	otherPreference := isis.preference(otherExternal)
+	if preference != otherPreference {
+		return preference < otherPreference
+	}
+	if external != otherExternal {
+		return !external
+	}
+	if level != otherLevel {
+		return level == ISIS_LEVEL_1
+	}
+	return metric < otherMetric
+}
+
+// ribLevel returns the level a route competes as. A level 1 route which
+// came down from level 2 does not win over a level 2 one for being level 1
+// (RFC 5302 3.3).
+func ribLevel(level IsisLevel, down bool) IsisLevel {
+	if down {
+		return ISIS_LEVEL_2
+	}
+	return level
+}
+
+// ipv4Rib returns the local RIB, the best of the routes of the levels to
+// each prefix. The caller holds isis.lock.
+func (isis *IsisServer) ipv4Rib() map[[SPF_ID_KEY_LENGTH]byte]*Ipv4Ri {
+	rib := make(map[[SPF_ID_KEY_LENGTH]byte]*Ipv4Ri)
+	for _, level := range ISIS_LEVEL_ALL {
+		for key, ri := range isis.ipv4RiDb[level] {
+			if ri.down && len(ri.nexthops) == 0 {
+				// we leaked it down ourselves
+				continue
+			}
+			best, ok := rib[key]
+			if !ok || isis.better(ribLevel(ri.level, ri.down), ri.external, ri.metric,
+				ribLevel(best.level, best.down), best.external, best.metric) {
+				rib[key] = ri
+			}
+		}
+	}
+	return rib
+}
+
+// ipv6Rib returns the local RIB, the best of the routes of the levels to
+// each prefix. The caller holds isis.lock.
+func (isis *IsisServer) ipv6Rib() map[[SPF_ID_KEY_LENGTH]byte]*Ipv6Ri {
+	rib := make(map[[SPF_ID_KEY_LENGTH]byte]*Ipv6Ri)
+	for _, level := range ISIS_LEVEL_ALL {
+		for key, ri := range isis.ipv6RiDb[level] {
+			if ri.down && len(ri.nexthops) == 0 {
+				// we leaked it down ourselves
+				continue
+			}
+			best, ok := rib[key]
+			if !ok || isis.better(ribLevel(ri.level, ri.down), ri.external, ri.metric,
+				ribLevel(best.level, best.down), best.external, best.metric) {
+				rib[key] = ri
+			}
+		}
+	}
+	return rib
+}
@@ -93,7 +93,12 @@ func (circuit *Circuit) sendPsn(pduType packet.PduType, lsps []*packet.LsPdu) {
 	psn.SetSourceId(sourceId)
 	psn.AddLspEntriesTlv(lspEntriesTlv)
 
-	circuit.snSenderCh <- psn
+	frame, err := circuit.newFrame(psn)
+	if err != nil {
+		log.Infof("Serialize failed")
+		return
+	}
+	circuit.snSenderCh <- frame
 }
 
 func (circuit *Circuit) receiveSn(pdu *packet.SnPdu, lanAddress [packet.SYSTEM_ID_LENGTH]byte) {
@@ -120,12 +125,13 @@ func (circuit *Circuit) receiveSn(pdu *packet.SnPdu, lanAddress [packet.SYSTEM_I
 		return
 	}
 
+	// only the DIS handles PSNPs on a broadcast circuit
 	if circuit.configBcast() {
-		if (pdu.PduType() == packet.PDU_TYPE_LEVEL1_PSNP || pdu.PduType() == packet.PDU_TYPE_LEVEL1_CSNP) &&
+		if pdu.PduType() == packet.PDU_TYPE_LEVEL1_PSNP &&
 			!circuit.designated(ISIS_LEVEL_1) {
 			return
 		}
-		if (pdu.PduType() == packet.PDU_TYPE_LEVEL2_PSNP || pdu.PduType() == packet.PDU_TYPE_LEVEL2_CSNP) &&
+		if pdu.PduType() == packet.PDU_TYPE_LEVEL2_PSNP &&
 			!circuit.designated(ISIS_LEVEL_2) {
 			return
 		}
@@ -161,17 +167,35 @@ func (circuit *Circuit) receiveSn(pdu *packet.SnPdu, lanAddress [packet.SYSTEM_I
 	// iso10589 p.37 7.3.15.2 a) 7), 8)
 
 	// iso10589 p.37 7.3.15.2 b)
+	listed := make(map[[packet.LSP_ID_LENGTH]byte]bool)
 	lspEntriesTlvs, _ := pdu.LspEntriesTlvs()
 	for _, lspEntriesTlv := range lspEntriesTlvs {
 		for _, lspEntry := range lspEntriesTlv.LspEntries() {
 			circuit.handleLspEntry(pdu.PduType(), lspEntry.LspId(), lspEntry.LspSeqNum,
 				lspEntry.RemainingLifetime, lspEntry.Checksum)
+			listed[lspEntry.LspId()] = true
 		}
 	}
 
 	// iso10589 p.37 7.3.15.2 c)
 	if pdu.PduType() == packet.PDU_TYPE_LEVEL1_CSNP || pdu.PduType() == packet.PDU_TYPE_LEVEL2_CSNP {
-		// XXX
+		level := ISIS_LEVEL_1
+		if pdu.PduType() == packet.PDU_TYPE_LEVEL2_CSNP {
+			level = ISIS_LEVEL_2
+		}
+		startLspId := pdu.StartLspId()
+		endLspId := pdu.EndLspId()
+		for _, ls := range circuit.isis.lsDb[level] {
+			lspId := ls.pdu.LspId()
+			if bytes.Compare(lspId[:], startLspId[:]) < 0 ||
+				bytes.Compare(lspId[:], endLspId[:]) > 0 ||
+				listed[lspId] {
+				continue
+			}
+			if ls.pdu.RemainingLifetime != 0 && ls.pdu.SequenceNumber != 0 {
+				circuit.isis.setSrmFlag(ls, circuit)
+			}
+		}
 	}
 
 	go circuit.isis.scheduleHandleFlags()
@@ -184,9 +208,9 @@ func (circuit *Circuit) handleLspEntry(pduType packet.PduType, lspId [packet.LSP
 	// iso10589 p.37 7.3.15.2 b) 1)
 	var level IsisLevel
 	switch pduType {
-	case packet.PDU_TYPE_LEVEL1_PSNP:
+	case packet.PDU_TYPE_LEVEL1_PSNP, packet.PDU_TYPE_LEVEL1_CSNP:
 		level = ISIS_LEVEL_1
-	case packet.PDU_TYPE_LEVEL2_PSNP:
+	case packet.PDU_TYPE_LEVEL2_PSNP, packet.PDU_TYPE_LEVEL2_CSNP:
 		level = ISIS_LEVEL_2
 	default:
 		log.Infof("pdu type invalid")
@@ -200,9 +224,9 @@ func (circuit *Circuit) handleLspEntry(pduType packet.PduType, lspId [packet.LSP
 			lspSeqNum != 0 {
 			var lsPduType packet.PduType
 			switch pduType {
-			case packet.PDU_TYPE_LEVEL1_PSNP:
+			case packet.PDU_TYPE_LEVEL1_PSNP, packet.PDU_TYPE_LEVEL1_CSNP:
 				lsPduType = packet.PDU_TYPE_LEVEL1_LSP
-			case packet.PDU_TYPE_LEVEL2_PSNP:
+			case packet.PDU_TYPE_LEVEL2_PSNP, packet.PDU_TYPE_LEVEL2_CSNP:
 				lsPduType = packet.PDU_TYPE_LEVEL2_LSP
 			}
 			lsp, _ := packet.NewLsPdu(lsPduType)
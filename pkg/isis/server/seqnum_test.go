@@ -0,0 +1,66 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"testing"
+
+	"github.com/m-asama/golsr/pkg/isis/packet"
+)
+
+func TestReceiveCsnNonDis(t *testing.T) {
+	isis, circuit := newTestLanServer(t)
+	isis.lock.Lock()
+	defer isis.lock.Unlock()
+	adjacency := newTestLanAdjacency(circuit)
+	if circuit.designated(ISIS_LEVEL_2) {
+		t.Fatalf("failed designated")
+	}
+
+	ls3 := isis.insertLsp(newTestLsp(t, 3, 1, []byte{1}, 0x0a000300, "c"), false, nil)
+	ls4 := isis.insertLsp(newTestLsp(t, 4, 1, []byte{1}, 0x0a000400, "d"), false, nil)
+	ls7 := isis.insertLsp(newTestLsp(t, 7, 1, []byte{1}, 0x0a000700, "g"), false, nil)
+
+	// the CSNP of the DIS covers 3 to 6, lists 4 and 6 and misses 3
+	csnp, _ := packet.NewSnPdu(packet.PDU_TYPE_LEVEL2_CSNP)
+	csnp.SetStartLspId([packet.LSP_ID_LENGTH]byte{0, 0, 0, 0, 0, 3, 0, 0})
+	csnp.SetEndLspId([packet.LSP_ID_LENGTH]byte{0, 0, 0, 0, 0, 6, 0xff, 0xff})
+	entries, _ := packet.NewLspEntriesTlv()
+	for _, id := range []byte{4, 6} {
+		entry, _ := packet.NewLspEntriesLspEntry([packet.LSP_ID_LENGTH]byte{0, 0, 0, 0, 0, id, 0, 0})
+		entry.LspSeqNum = 1
+		entry.RemainingLifetime = 1200
+		entry.Checksum = 1
+		entries.AddLspEntry(entry)
+	}
+	csnp.AddLspEntriesTlv(entries)
+	circuit.receiveSn(csnp, adjacency.lanAddress)
+
+	// iso10589 p.37 7.3.15.2 c)
+	if !isis.srmFlag(ls3, circuit) {
+		t.Fatalf("failed missing LSP not flooded")
+	}
+	if isis.srmFlag(ls4, circuit) || isis.srmFlag(ls7, circuit) {
+		t.Fatalf("failed listed or out of range LSP flooded")
+	}
+	// iso10589 p.37 7.3.15.2 b) 5)
+	ls6 := isis.lookupLsp(ISIS_LEVEL_2, [packet.LSP_ID_LENGTH]byte{0, 0, 0, 0, 0, 6, 0, 0})
+	if ls6 == nil || !isis.ssnFlag(ls6, circuit) {
+		t.Fatalf("failed unknown LSP not requested")
+	}
+}
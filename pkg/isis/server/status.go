@@ -0,0 +1,144 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+
+	api "github.com/m-asama/golsr/api/isis"
+)
+
+func newApiAdjacency(circuit *Circuit, adj *Adjacency) *api.Adjacency {
+	return &api.Adjacency{
+		Interface:                 circuit.name,
+		NeighborType:              adj.adjType.String(),
+		NeighborSysid:             fmt.Sprintf("%x", adj.systemId),
+		NeighborExtendedCircuitId: adj.extendedCircuitId,
+		NeighborSnpa:              fmt.Sprintf("%x", adj.lanAddress),
+		Usage:                     adj.adjUsage.String(),
+		HoldTimer:                 uint32(adj.holdingTime),
+		NeighborPriority:          uint32(adj.priority),
+		Lastuptime:                0,
+		State:                     adj.adjState.String(),
+	}
+}
+
+// apiInstance returns the summary of the server as the instance name.
+func (isis *IsisServer) apiInstance(name string) *api.Instance {
+	instance := &api.Instance{
+		Name:          name,
+		AreaAddresses: make([]string, 0),
+		Interfaces:    make([]string, 0),
+	}
+	isis.lock.RLock()
+	defer isis.lock.RUnlock()
+	cfg := isis.config
+	if cfg.Config.SystemId != nil {
+		instance.SystemId = *cfg.Config.SystemId
+	}
+	for _, areaAddress := range cfg.Config.AreaAddress {
+		instance.AreaAddresses = append(instance.AreaAddresses, *areaAddress)
+	}
+	for _, circuit := range isis.circuitDb {
+		instance.Interfaces = append(instance.Interfaces, circuit.name)
+	}
+	sort.Strings(instance.Interfaces)
+	return instance
+}
+
+// ApiAdjacencies returns the adjacencies of all circuits as
+// AdjacencyMonitor does.
+func (isis *IsisServer) ApiAdjacencies() []*api.Adjacency {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	isis.lock.RLock()
+	defer isis.lock.RUnlock()
+	adjacencies := make([]*api.Adjacency, 0)
+	for _, circuit := range isis.circuitDb {
+		for _, adj := range circuit.adjacencyDb {
+			adjacencies = append(adjacencies, newApiAdjacency(circuit, adj))
+		}
+	}
+	return adjacencies
+}
+
+// ApiLsps returns the LSDB of level, purged LSPs included.
+func (isis *IsisServer) ApiLsps(level IsisLevel) []*api.Lsp {
+	log.Debugf("enter: %s", level)
+	defer log.Debugf("exit: %s", level)
+	// encoding the LSPs updates their length fields
+	isis.lock.Lock()
+	defer isis.lock.Unlock()
+	lsps := make([]*api.Lsp, 0)
+	for _, ls := range isis.lsDb[level] {
+		lsps = append(lsps, NewApiLsp(ls.pdu))
+	}
+	return lsps
+}
+
+// ApiRoutes returns the routes of level ordered as DbRiMonitor does.
+func (isis *IsisServer) ApiRoutes(level IsisLevel) []*api.Route {
+	log.Debugf("enter: %s", level)
+	defer log.Debugf("exit: %s", level)
+	isis.lock.RLock()
+	defer isis.lock.RUnlock()
+	keys := make([][SPF_ID_KEY_LENGTH]byte, 0)
+	for k := range isis.ipv4RiDb[level] {
+		keys = append(keys, k)
+	}
+	for k := range isis.ipv6RiDb[level] {
+		keys = append(keys, k)
+	}
+	sort.Sort(SpfIdKeys(keys))
+	routes := make([]*api.Route, 0)
+	for _, k := range keys {
+		if v, ok := isis.ipv4RiDb[level][k]; ok {
+			route := &api.Route{}
+			route.Level = level.String2()
+			route.AddressFamily = "ipv4"
+			fillRoute4(route, v)
+			routes = append(routes, route)
+		}
+		if v, ok := isis.ipv6RiDb[level][k]; ok {
+			route := &api.Route{}
+			route.Level = level.String2()
+			route.AddressFamily = "ipv6"
+			fillRoute6(route, v)
+			routes = append(routes, route)
+		}
+	}
+	return routes
+}
+
+// Designated reports whether this system is the DIS of level on the
+// circuit of interface name.
+func (isis *IsisServer) Designated(name string, level IsisLevel) bool {
+	log.Debugf("enter: %s: %s", name, level)
+	defer log.Debugf("exit: %s: %s", name, level)
+	isis.lock.RLock()
+	defer isis.lock.RUnlock()
+	for _, circuit := range isis.circuitDb {
+		if circuit.name == name {
+			return circuit.designated(level)
+		}
+	}
+	return false
+}
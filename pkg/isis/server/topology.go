@@ -0,0 +1,186 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+
+	api "github.com/m-asama/golsr/api/isis"
+	"github.com/m-asama/golsr/internal/pkg/util"
+	"github.com/m-asama/golsr/pkg/isis/packet"
+)
+
+type topologyGraphNodes []*api.TopologyGraphNode
+
+func (nodes topologyGraphNodes) Len() int {
+	return len(nodes)
+}
+
+func (nodes topologyGraphNodes) Swap(i, j int) {
+	nodes[i], nodes[j] = nodes[j], nodes[i]
+}
+
+func (nodes topologyGraphNodes) Less(i, j int) bool {
+	return nodes[i].NodeId < nodes[j].NodeId
+}
+
+func topologyNodeId(id [packet.NEIGHBOUR_ID_LENGTH]byte) string {
+	return fmt.Sprintf("%02x%02x.%02x%02x.%02x%02x.%02x",
+		id[0], id[1], id[2], id[3], id[4], id[5], id[6])
+}
+
+func newTopologyGraphNode(id [packet.NEIGHBOUR_ID_LENGTH]byte) *api.TopologyGraphNode {
+	return &api.TopologyGraphNode{
+		NodeId:        topologyNodeId(id),
+		SystemId:      topologyNodeId(id)[0:14],
+		Pseudonode:    id[packet.NEIGHBOUR_ID_LENGTH-1] != 0,
+		Ipv4Addresses: make([]string, 0),
+		Ipv6Addresses: make([]string, 0),
+	}
+}
+
+// newTopologyGraph builds the graph of one level from the IS
+// Neighbours (2) and Extended IS Reachability (22) TLVs of lsps. Every
+// advertisement becomes a directed edge so that asymmetric metrics are
+// kept. Nodes which are only referred to as a neighbour have no LSP
+// (LspPresent is false).
+func newTopologyGraph(level IsisLevel, lsps []*packet.LsPdu) *api.TopologyGraph {
+	log.Debugf("enter: %s", level)
+	defer log.Debugf("exit: %s", level)
+	graph := &api.TopologyGraph{
+		Level: level.String2(),
+		Nodes: make([]*api.TopologyGraphNode, 0),
+		Edges: make([]*api.TopologyGraphEdge, 0),
+	}
+	nodes := make(map[[packet.NEIGHBOUR_ID_LENGTH]byte]*api.TopologyGraphNode)
+	node := func(id [packet.NEIGHBOUR_ID_LENGTH]byte) *api.TopologyGraphNode {
+		n, ok := nodes[id]
+		if !ok {
+			n = newTopologyGraphNode(id)
+			nodes[id] = n
+		}
+		return n
+	}
+	for _, lsp := range lsps {
+		if lsp.RemainingLifetime == 0 {
+			continue
+		}
+		lspId := lsp.LspId()
+		var id [packet.NEIGHBOUR_ID_LENGTH]byte
+		copy(id[:], lspId[0:packet.NEIGHBOUR_ID_LENGTH])
+		from := node(id)
+		from.LspPresent = true
+		if lspId[packet.NEIGHBOUR_ID_LENGTH] == 0 {
+			from.Overload = lsp.LSPDBOverloadFlag
+			from.Attached = lsp.AttachedDefaultMetric
+		}
+		dynamicHostnameTlv, _ := lsp.DynamicHostnameTlv()
+		if dynamicHostnameTlv != nil {
+			from.Hostname = string(dynamicHostnameTlv.DynamicHostname())
+		}
+		teRouterIdTlv, _ := lsp.TrafficEngineeringRouterIdTlv()
+		if teRouterIdTlv != nil {
+			from.Ipv4TeRouterid = util.Ipv4Uint32ToString(teRouterIdTlv.RouterId)
+		}
+		ipInterfaceAddressTlv, _ := lsp.IpInterfaceAddressTlv()
+		if ipInterfaceAddressTlv != nil {
+			for _, a := range ipInterfaceAddressTlv.IpAddresses() {
+				from.Ipv4Addresses = append(from.Ipv4Addresses, util.Ipv4Uint32ToString(a))
+			}
+		}
+		ipv6InterfaceAddressTlv, _ := lsp.Ipv6InterfaceAddressTlv()
+		if ipv6InterfaceAddressTlv != nil {
+			for _, a := range ipv6InterfaceAddressTlv.Ipv6Addresses() {
+				from.Ipv6Addresses = append(from.Ipv6Addresses, util.Ipv6Uint32ArrayToString(a))
+			}
+		}
+		isNeighboursLspTlvs, _ := lsp.IsNeighboursLspTlvs()
+		for _, tlv := range isNeighboursLspTlvs {
+			for _, n := range tlv.Neighbours() {
+				to := node(n.NeighbourId())
+				graph.Edges = append(graph.Edges, &api.TopologyGraphEdge{
+					From:   from.NodeId,
+					To:     to.NodeId,
+					Tlv:    "is-neighbours",
+					Metric: uint32(n.DefaultMetric),
+				})
+			}
+		}
+		extendedIsReachabilityTlvs, _ := lsp.ExtendedIsReachabilityTlvs()
+		for _, tlv := range extendedIsReachabilityTlvs {
+			for _, n := range tlv.Neighbours() {
+				to := node(n.NeighbourId())
+				edge := &api.TopologyGraphEdge{
+					From:                   from.NodeId,
+					To:                     to.NodeId,
+					Tlv:                    "extended-is-reachability",
+					Metric:                 n.DefaultMetric,
+					Ipv4InterfaceAddresses: make([]string, 0),
+					Ipv4NeighborAddresses:  make([]string, 0),
+					UnreservedBandwidths:   make([]float32, 0),
+				}
+				if n.AdminGroupSubTlv() != nil {
+					edge.AdminGroup = *n.AdminGroupSubTlv()
+				}
+				for _, a := range n.Ipv4InterfaceAddressSubTlvs() {
+					edge.Ipv4InterfaceAddresses = append(edge.Ipv4InterfaceAddresses, util.Ipv4Uint32ToString(a))
+				}
+				for _, a := range n.Ipv4NeighbourAddressSubTlvs() {
+					edge.Ipv4NeighborAddresses = append(edge.Ipv4NeighborAddresses, util.Ipv4Uint32ToString(a))
+				}
+				if n.MaximumLinkBandwidthSubTlv() != nil {
+					edge.MaxLinkBandwidth = *n.MaximumLinkBandwidthSubTlv()
+				}
+				if n.MaximumReservableLinkBandwidthSubTlv() != nil {
+					edge.MaxReservableLinkBandwidth = *n.MaximumReservableLinkBandwidthSubTlv()
+				}
+				if n.UnreservedBandwidthSubTlv() != nil {
+					edge.UnreservedBandwidths = append(edge.UnreservedBandwidths, n.UnreservedBandwidthSubTlv()[:]...)
+				}
+				if n.TrafficEngineeringDefaultMetric() != nil {
+					edge.TeDefaultMetric = *n.TrafficEngineeringDefaultMetric()
+				}
+				graph.Edges = append(graph.Edges, edge)
+			}
+		}
+	}
+	for _, n := range nodes {
+		graph.Nodes = append(graph.Nodes, n)
+	}
+	sort.Sort(topologyGraphNodes(graph.Nodes))
+	return graph
+}
+
+func (isis *IsisServer) topologyGraph(level IsisLevel) *api.TopologyGraph {
+	log.Debugf("enter: %s", level)
+	defer log.Debugf("exit: %s", level)
+	isis.lock.RLock()
+	defer isis.lock.RUnlock()
+	lsps := make([]*packet.LsPdu, 0)
+	for _, ls := range isis.lsDb[level] {
+		lsps = append(lsps, ls.pdu)
+	}
+	return newTopologyGraph(level, lsps)
+}
+
+func (db *OfflineLsdb) TopologyGraph(level IsisLevel) *api.TopologyGraph {
+	return newTopologyGraph(level, db.Lsps(level))
+}
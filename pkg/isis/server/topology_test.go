@@ -0,0 +1,81 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"testing"
+
+	"github.com/m-asama/golsr/pkg/isis/packet"
+)
+
+func TestTopologyGraph(t *testing.T) {
+	db := NewOfflineLsdb()
+	a := newTestLsp(t, 1, 1, []byte{2}, 0x0a000100, "a")
+	a.LSPDBOverloadFlag = true
+	db.AddLsp(a)
+	b := newTestLsp(t, 2, 1, []byte{3}, 0x0a000200, "b")
+	isr, _ := packet.NewExtendedIsReachabilityTlv()
+	n, _ := packet.NewExtendedIsReachabilityNeighbour([packet.NEIGHBOUR_ID_LENGTH]byte{0, 0, 0, 0, 0, 1, 0})
+	n.DefaultMetric = 10
+	teMetric := uint32(100)
+	n.SetTrafficEngineeringDefaultMetric(&teMetric)
+	n.AddIpv4InterfaceAddressSubTlv(0x0a000201)
+	isr.AddNeighbour(n)
+	b.AddExtendedIsReachabilityTlv(isr)
+	db.AddLsp(b)
+
+	graph := db.TopologyGraph(ISIS_LEVEL_2)
+	if graph.Level != "level-2" {
+		t.Fatalf("failed Level: %s", graph.Level)
+	}
+	if len(graph.Nodes) != 3 {
+		t.Fatalf("failed len(Nodes): %d", len(graph.Nodes))
+	}
+	if graph.Nodes[0].NodeId != "0000.0000.0001.00" || graph.Nodes[0].Hostname != "a" ||
+		!graph.Nodes[0].Overload || !graph.Nodes[0].LspPresent {
+		t.Fatalf("failed Nodes[0]: %v", graph.Nodes[0])
+	}
+	// c is only known as a neighbour of b
+	if graph.Nodes[2].NodeId != "0000.0000.0003.00" || graph.Nodes[2].LspPresent {
+		t.Fatalf("failed Nodes[2]: %v", graph.Nodes[2])
+	}
+	if len(graph.Edges) != 3 {
+		t.Fatalf("failed len(Edges): %d", len(graph.Edges))
+	}
+	found := false
+	for _, edge := range graph.Edges {
+		if edge.Metric != 10 || edge.Tlv != "extended-is-reachability" {
+			t.Fatalf("failed edge: %v", edge)
+		}
+		if edge.From == "0000.0000.0002.00" && edge.To == "0000.0000.0001.00" {
+			found = true
+			if edge.TeDefaultMetric != 100 || len(edge.Ipv4InterfaceAddresses) != 1 ||
+				edge.Ipv4InterfaceAddresses[0] != "10.0.2.1" {
+				t.Fatalf("failed te edge: %v", edge)
+			}
+		}
+	}
+
+	if !found {
+		t.Fatalf("failed te edge not found")
+	}
+
+	if len(db.TopologyGraph(ISIS_LEVEL_1).Nodes) != 0 {
+		t.Fatalf("failed level-1 not empty")
+	}
+}
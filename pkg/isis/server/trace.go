@@ -0,0 +1,246 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/m-asama/golsr/internal/pkg/pcap"
+	"github.com/m-asama/golsr/pkg/isis/packet"
+)
+
+type TraceDirection uint8
+
+const (
+	_ TraceDirection = iota
+	TRACE_DIRECTION_RECV
+	TRACE_DIRECTION_SEND
+)
+
+func (direction TraceDirection) String() string {
+	switch direction {
+	case TRACE_DIRECTION_RECV:
+		return "TRACE_DIRECTION_RECV"
+	case TRACE_DIRECTION_SEND:
+		return "TRACE_DIRECTION_SEND"
+	}
+	log.Infof("")
+	panic("")
+	return ""
+}
+
+var traceDirectionNames = map[string][]TraceDirection{
+	"all":  {TRACE_DIRECTION_RECV, TRACE_DIRECTION_SEND},
+	"recv": {TRACE_DIRECTION_RECV},
+	"send": {TRACE_DIRECTION_SEND},
+}
+
+var tracePduTypeNames = map[string][]packet.PduType{
+	"all": {
+		packet.PDU_TYPE_LEVEL1_LAN_IIHP, packet.PDU_TYPE_LEVEL2_LAN_IIHP, packet.PDU_TYPE_P2P_IIHP,
+		packet.PDU_TYPE_LEVEL1_LSP, packet.PDU_TYPE_LEVEL2_LSP,
+		packet.PDU_TYPE_LEVEL1_CSNP, packet.PDU_TYPE_LEVEL2_CSNP,
+		packet.PDU_TYPE_LEVEL1_PSNP, packet.PDU_TYPE_LEVEL2_PSNP,
+	},
+	"iih":     {packet.PDU_TYPE_LEVEL1_LAN_IIHP, packet.PDU_TYPE_LEVEL2_LAN_IIHP, packet.PDU_TYPE_P2P_IIHP},
+	"l1-iih":  {packet.PDU_TYPE_LEVEL1_LAN_IIHP},
+	"l2-iih":  {packet.PDU_TYPE_LEVEL2_LAN_IIHP},
+	"p2p-iih": {packet.PDU_TYPE_P2P_IIHP},
+	"lsp":     {packet.PDU_TYPE_LEVEL1_LSP, packet.PDU_TYPE_LEVEL2_LSP},
+	"l1-lsp":  {packet.PDU_TYPE_LEVEL1_LSP},
+	"l2-lsp":  {packet.PDU_TYPE_LEVEL2_LSP},
+	"csnp":    {packet.PDU_TYPE_LEVEL1_CSNP, packet.PDU_TYPE_LEVEL2_CSNP},
+	"l1-csnp": {packet.PDU_TYPE_LEVEL1_CSNP},
+	"l2-csnp": {packet.PDU_TYPE_LEVEL2_CSNP},
+	"psnp":    {packet.PDU_TYPE_LEVEL1_PSNP, packet.PDU_TYPE_LEVEL2_PSNP},
+	"l1-psnp": {packet.PDU_TYPE_LEVEL1_PSNP},
+	"l2-psnp": {packet.PDU_TYPE_LEVEL2_PSNP},
+}
+
+type Trace struct {
+	enable     bool
+	interfaces map[string]bool
+	directions map[TraceDirection]bool
+	pduTypes   map[packet.PduType]bool
+	pcapFile   *os.File
+	pcapWriter *pcap.Writer
+	lock       sync.RWMutex
+}
+
+func NewTrace() *Trace {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	trace := &Trace{
+		interfaces: make(map[string]bool),
+		directions: make(map[TraceDirection]bool),
+		pduTypes:   make(map[packet.PduType]bool),
+	}
+	return trace
+}
+
+// set replaces the whole filter. An empty list means no restriction.
+func (trace *Trace) set(enable bool, interfaces, directions, pduTypes []string) error {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	ifs := make(map[string]bool)
+	for _, name := range interfaces {
+		if name == "all" {
+			ifs = make(map[string]bool)
+			break
+		}
+		ifs[name] = true
+	}
+	dirs := make(map[TraceDirection]bool)
+	for _, name := range directions {
+		dtmp, ok := traceDirectionNames[name]
+		if !ok {
+			return errors.New("trace.set: unknown direction " + name)
+		}
+		for _, d := range dtmp {
+			dirs[d] = true
+		}
+	}
+	pdus := make(map[packet.PduType]bool)
+	for _, name := range pduTypes {
+		ptmp, ok := tracePduTypeNames[name]
+		if !ok {
+			return errors.New("trace.set: unknown pdu type " + name)
+		}
+		for _, p := range ptmp {
+			pdus[p] = true
+		}
+	}
+	trace.lock.Lock()
+	defer trace.lock.Unlock()
+	trace.enable = enable
+	trace.interfaces = ifs
+	trace.directions = dirs
+	trace.pduTypes = pdus
+	return nil
+}
+
+func (trace *Trace) openPcap(filename string) error {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	w, err := pcap.NewWriter(f, pcap.LINKTYPE_ETHERNET)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	trace.lock.Lock()
+	defer trace.lock.Unlock()
+	if trace.pcapFile != nil {
+		trace.pcapFile.Close()
+	}
+	trace.pcapFile = f
+	trace.pcapWriter = w
+	return nil
+}
+
+func (trace *Trace) closePcap() {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	trace.lock.Lock()
+	defer trace.lock.Unlock()
+	if trace.pcapFile != nil {
+		trace.pcapFile.Close()
+	}
+	trace.pcapFile = nil
+	trace.pcapWriter = nil
+}
+
+func (trace *Trace) match(name string, direction TraceDirection, pduType packet.PduType) bool {
+	if !trace.enable {
+		return false
+	}
+	if len(trace.interfaces) > 0 && !trace.interfaces[name] {
+		return false
+	}
+	if len(trace.directions) > 0 && !trace.directions[direction] {
+		return false
+	}
+	if len(trace.pduTypes) > 0 && !trace.pduTypes[pduType] {
+		return false
+	}
+	return true
+}
+
+// frame is pdu for a pdu of which only the encoding is at hand. It is
+// decoded only when it is logged.
+func (trace *Trace) frame(name string, direction TraceDirection, data, src, dst []byte) {
+	trace.lock.RLock()
+	defer trace.lock.RUnlock()
+	trace.writePcap(data, src, dst)
+	if !trace.enable {
+		return
+	}
+	pdu, err := packet.DecodePduFromBytes(data)
+	if err != nil {
+		log.Infof("DecodePduFromBytes failed: %v", err)
+		return
+	}
+	trace.logPdu(name, direction, pdu)
+}
+
+// pdu writes data (the encoded pdu without LLC) as an 802.3 frame from
+// src to dst if a pcap file is open, whatever the filter, and logs pdu if
+// the filter matches it.
+func (trace *Trace) pdu(name string, direction TraceDirection, pdu packet.IsisPdu, data, src, dst []byte) {
+	trace.lock.RLock()
+	defer trace.lock.RUnlock()
+	trace.writePcap(data, src, dst)
+	trace.logPdu(name, direction, pdu)
+}
+
+// logPdu logs pdu if the filter matches it. The caller holds trace.lock.
+func (trace *Trace) logPdu(name string, direction TraceDirection, pdu packet.IsisPdu) {
+	if !trace.match(name, direction, pdu.PduType()) {
+		return
+	}
+	log.WithFields(log.Fields{
+		"Topic":     "Trace",
+		"Interface": name,
+		"Direction": direction.String(),
+		"PduType":   pdu.PduType().String(),
+	}).Infof("\n%s", pdu.String())
+}
+
+// writePcap writes the frame to the pcap file if one is open. The caller
+// holds trace.lock, for reading being enough as the writer keeps the
+// frames whole itself.
+func (trace *Trace) writePcap(data, src, dst []byte) {
+	if trace.pcapWriter == nil {
+		return
+	}
+	payload := make([]byte, len(packet.Llc)+len(data))
+	copy(payload[0:len(packet.Llc)], packet.Llc)
+	copy(payload[len(packet.Llc):], data)
+	err := trace.pcapWriter.WritePacket(time.Now(), pcap.Ieee8023Frame(dst, src, payload))
+	if err != nil {
+		log.Infof("WritePacket failed: %v", err)
+	}
+}
@@ -0,0 +1,122 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/m-asama/golsr/internal/pkg/pcap"
+	"github.com/m-asama/golsr/pkg/isis/packet"
+)
+
+func TestTraceSetMatch(t *testing.T) {
+	trace := NewTrace()
+	if trace.match("eth0", TRACE_DIRECTION_RECV, packet.PDU_TYPE_LEVEL1_LSP) {
+		t.Fatalf("failed match while disabled")
+	}
+	if err := trace.set(true, nil, []string{"both"}, nil); err == nil {
+		t.Fatalf("failed set: unknown direction accepted")
+	}
+	if err := trace.set(true, nil, nil, []string{"hello"}); err == nil {
+		t.Fatalf("failed set: unknown pdu type accepted")
+	}
+
+	if err := trace.set(true, []string{"eth0"}, []string{"recv"}, []string{"lsp", "l2-csnp"}); err != nil {
+		t.Fatalf("failed set: %#v", err)
+	}
+	for _, check := range []struct {
+		name      string
+		direction TraceDirection
+		pduType   packet.PduType
+		match     bool
+	}{
+		{"eth0", TRACE_DIRECTION_RECV, packet.PDU_TYPE_LEVEL1_LSP, true},
+		{"eth0", TRACE_DIRECTION_RECV, packet.PDU_TYPE_LEVEL2_CSNP, true},
+		{"eth0", TRACE_DIRECTION_RECV, packet.PDU_TYPE_LEVEL1_CSNP, false},
+		{"eth0", TRACE_DIRECTION_SEND, packet.PDU_TYPE_LEVEL1_LSP, false},
+		{"eth1", TRACE_DIRECTION_RECV, packet.PDU_TYPE_LEVEL1_LSP, false},
+	} {
+		if trace.match(check.name, check.direction, check.pduType) != check.match {
+			t.Fatalf("failed match: %v", check)
+		}
+	}
+
+	// all of a kind is no restriction
+	if err := trace.set(true, []string{"eth0", "all"}, []string{"all"}, []string{"all"}); err != nil {
+		t.Fatalf("failed set: %#v", err)
+	}
+	if !trace.match("eth1", TRACE_DIRECTION_SEND, packet.PDU_TYPE_P2P_IIHP) {
+		t.Fatalf("failed match all")
+	}
+	if err := trace.set(false, nil, nil, nil); err != nil {
+		t.Fatalf("failed set: %#v", err)
+	}
+	if trace.match("eth1", TRACE_DIRECTION_SEND, packet.PDU_TYPE_P2P_IIHP) {
+		t.Fatalf("failed match after disabled")
+	}
+}
+
+func TestTracePcap(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "trace.pcap")
+
+	trace := NewTrace()
+	if err := trace.openPcap(filename); err != nil {
+		t.Fatalf("failed openPcap: %#v", err)
+	}
+	// the frames the filter leaves out are written too
+	if err := trace.set(true, []string{"eth1"}, nil, nil); err != nil {
+		t.Fatalf("failed set: %#v", err)
+	}
+	csnp, _ := packet.NewSnPdu(packet.PDU_TYPE_LEVEL2_CSNP)
+	data, _ := csnp.Serialize()
+	src := []byte{0x02, 0, 0, 0, 0, 1}
+	trace.pdu("eth0", TRACE_DIRECTION_RECV, csnp, data, src, packet.AllL2Iss)
+	trace.set(false, nil, nil, nil)
+	trace.frame("eth0", TRACE_DIRECTION_SEND, data, src, packet.AllL2Iss)
+	trace.closePcap()
+
+	f, err := os.Open(filename)
+	if err != nil {
+		t.Fatalf("failed Open: %#v", err)
+	}
+	defer f.Close()
+	reader, err := pcap.NewReader(f)
+	if err != nil {
+		t.Fatalf("failed NewReader: %#v", err)
+	}
+	count := 0
+	for {
+		_, frame, err := reader.ReadPacket()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("failed ReadPacket: %#v", err)
+		}
+		if len(frame) != 14+len(packet.Llc)+len(data) {
+			t.Fatalf("failed frame length: %d", len(frame))
+		}
+		count++
+	}
+	if count != 2 {
+		t.Fatalf("failed frames written: %d", count)
+	}
+}
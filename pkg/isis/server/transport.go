@@ -0,0 +1,246 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"syscall"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/m-asama/golsr/internal/pkg/kernel"
+	"github.com/m-asama/golsr/pkg/isis/packet"
+)
+
+// Transport sends and receives the frames of a circuit. A frame is what
+// follows the link layer header, that is the PDU with the LLC header on
+// broadcast interfaces. Addresses are 6 octet SNPAs.
+type Transport interface {
+	Send(frame []byte, dst []byte) error
+	// Recv blocks until a frame arrives and returns its length and
+	// source. It fails once the transport is closed.
+	Recv(buf []byte) (int, []byte, error)
+	Close() error
+}
+
+// TransportFactory opens the transport of the circuit on iface.
+type TransportFactory func(iface *kernel.Interface) (Transport, error)
+
+type packetTransport struct {
+	fd      int
+	ifIndex int
+}
+
+// NewPacketTransport opens an AF_PACKET socket on iface which receives
+// IS-IS frames only. This is the transport the daemon uses.
+func NewPacketTransport(iface *kernel.Interface) (Transport, error) {
+	fd, err := isisSocket(iface)
+	if err != nil {
+		return nil, err
+	}
+	return &packetTransport{
+		fd:      fd,
+		ifIndex: iface.IfIndex,
+	}, nil
+}
+
+// NewNetnsPacketTransport returns a factory which opens the sockets of
+// NewPacketTransport in the network namespace name.
+func NewNetnsPacketTransport(name string) TransportFactory {
+	return func(iface *kernel.Interface) (Transport, error) {
+		var transport Transport
+		err := kernel.InNamespace(name, func() error {
+			var err error
+			transport, err = NewPacketTransport(iface)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		return transport, nil
+	}
+}
+
+func (transport *packetTransport) Send(frame []byte, dst []byte) error {
+	var dad [8]byte
+	copy(dad[0:6], dst[0:6])
+	dstaddr := syscall.SockaddrLinklayer{
+		Protocol: htons(uint16(len(frame))),
+		Ifindex:  transport.ifIndex,
+		Halen:    uint8(6),
+		Addr:     dad,
+	}
+	return syscall.Sendto(transport.fd, frame, 0, &dstaddr)
+}
+
+func (transport *packetTransport) Recv(buf []byte) (int, []byte, error) {
+	n, from, err := syscall.Recvfrom(transport.fd, buf, 0)
+	if err != nil {
+		return 0, nil, err
+	}
+	fromll, ok := from.(*syscall.SockaddrLinklayer)
+	if !ok {
+		return 0, nil, errors.New("packetTransport.Recv: address family invalid")
+	}
+	return n, fromll.Addr[0:packet.SYSTEM_ID_LENGTH], nil
+}
+
+func (transport *packetTransport) Close() error {
+	return syscall.Close(transport.fd)
+}
+
+type memoryFrame struct {
+	frame []byte
+	src   []byte
+}
+
+type memoryEndpoint struct {
+	network *MemoryNetwork
+	router  string
+	name    string
+	addr    []byte
+	recvCh  chan *memoryFrame
+	closeCh chan struct{}
+	once    sync.Once
+}
+
+// MemoryNetwork connects circuits of IsisServer instances in the same
+// process. Interfaces are attached to named segments; a frame sent on a
+// segment is delivered to every other interface attached to it, so a
+// segment with two interfaces is a point-to-point link and one with more
+// is a LAN. Like a real link a frame is dropped when the receiver is not
+// keeping up.
+type MemoryNetwork struct {
+	lock      sync.RWMutex
+	segments  map[string]string
+	endpoints map[string]*memoryEndpoint
+}
+
+const MEMORY_TRANSPORT_QUEUE_LENGTH = 256
+
+func NewMemoryNetwork() *MemoryNetwork {
+	return &MemoryNetwork{
+		segments:  make(map[string]string),
+		endpoints: make(map[string]*memoryEndpoint),
+	}
+}
+
+func memoryEndpointKey(router, name string) string {
+	return router + "/" + name
+}
+
+// Connect attaches interface name of router to segment. An interface is
+// on at most one segment at a time.
+func (network *MemoryNetwork) Connect(segment, router, name string) {
+	network.lock.Lock()
+	defer network.lock.Unlock()
+	network.segments[memoryEndpointKey(router, name)] = segment
+}
+
+// Disconnect detaches interface name of router from its segment. The
+// circuit stays open but neither sends nor receives anything, as if the
+// cable was pulled without the carrier going down.
+func (network *MemoryNetwork) Disconnect(router, name string) {
+	network.lock.Lock()
+	defer network.lock.Unlock()
+	delete(network.segments, memoryEndpointKey(router, name))
+}
+
+// TransportFactory returns the factory to give to IsisServer.SetTransport
+// of router.
+func (network *MemoryNetwork) TransportFactory(router string) TransportFactory {
+	return func(iface *kernel.Interface) (Transport, error) {
+		if iface == nil {
+			return nil, errors.New("MemoryNetwork.TransportFactory: iface == nil")
+		}
+		key := memoryEndpointKey(router, iface.Name)
+		endpoint := &memoryEndpoint{
+			network: network,
+			router:  router,
+			name:    iface.Name,
+			addr:    append([]byte{}, iface.HardwareAddr...),
+			recvCh:  make(chan *memoryFrame, MEMORY_TRANSPORT_QUEUE_LENGTH),
+			closeCh: make(chan struct{}),
+		}
+		network.lock.Lock()
+		defer network.lock.Unlock()
+		if _, ok := network.endpoints[key]; ok {
+			return nil, errors.New("MemoryNetwork.TransportFactory: " + key + " already opened")
+		}
+		network.endpoints[key] = endpoint
+		return endpoint, nil
+	}
+}
+
+func (endpoint *memoryEndpoint) key() string {
+	return memoryEndpointKey(endpoint.router, endpoint.name)
+}
+
+func (endpoint *memoryEndpoint) Send(frame []byte, dst []byte) error {
+	network := endpoint.network
+	network.lock.RLock()
+	defer network.lock.RUnlock()
+	segment, ok := network.segments[endpoint.key()]
+	if !ok {
+		return nil
+	}
+	multicast := len(dst) > 0 && dst[0]&0x01 != 0
+	for key, peer := range network.endpoints {
+		if peer == endpoint || network.segments[key] != segment {
+			continue
+		}
+		if !multicast && !bytes.Equal(dst, peer.addr) {
+			continue
+		}
+		f := &memoryFrame{
+			frame: append([]byte{}, frame...),
+			src:   endpoint.addr,
+		}
+		select {
+		case peer.recvCh <- f:
+		default:
+			log.Debugf("%s: queue full", key)
+		}
+	}
+	return nil
+}
+
+func (endpoint *memoryEndpoint) Recv(buf []byte) (int, []byte, error) {
+	select {
+	case f := <-endpoint.recvCh:
+		n := copy(buf, f.frame)
+		return n, f.src, nil
+	case <-endpoint.closeCh:
+		return 0, nil, errors.New("memoryEndpoint.Recv: closed")
+	}
+}
+
+func (endpoint *memoryEndpoint) Close() error {
+	endpoint.once.Do(func() {
+		network := endpoint.network
+		network.lock.Lock()
+		if network.endpoints[endpoint.key()] == endpoint {
+			delete(network.endpoints, endpoint.key())
+		}
+		network.lock.Unlock()
+		close(endpoint.closeCh)
+	})
+	return nil
+}
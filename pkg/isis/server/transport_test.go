@@ -0,0 +1,200 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/m-asama/golsr/internal/pkg/isis/config"
+	"github.com/m-asama/golsr/internal/pkg/kernel"
+	"github.com/m-asama/golsr/pkg/isis/packet"
+)
+
+func TestMemoryNetwork(t *testing.T) {
+	network := NewMemoryNetwork()
+	network.Connect("lan", "a", "eth0")
+	network.Connect("lan", "b", "eth0")
+	network.Connect("lan", "c", "eth0")
+	open := func(router string, addr byte) Transport {
+		transport, err := network.TransportFactory(router)(&kernel.Interface{
+			Name:         "eth0",
+			HardwareAddr: []byte{0x02, 0, 0, 0, 0, addr},
+		})
+		if err != nil {
+			t.Fatalf("failed TransportFactory: %#v", err)
+		}
+		return transport
+	}
+	a := open("a", 1)
+	b := open("b", 2)
+	c := open("c", 3)
+	if _, err := network.TransportFactory("a")(&kernel.Interface{Name: "eth0"}); err == nil {
+		t.Fatalf("failed reopen")
+	}
+
+	buf := make([]byte, 16)
+	a.Send([]byte{1, 2, 3}, packet.AllL1Iss)
+	for _, transport := range []Transport{b, c} {
+		n, from, err := transport.Recv(buf)
+		if err != nil || n != 3 || buf[2] != 3 || from[5] != 1 {
+			t.Fatalf("failed multicast: %d %x %#v", n, from, err)
+		}
+	}
+	a.Send([]byte{4}, []byte{0x02, 0, 0, 0, 0, 3})
+	n, _, err := c.Recv(buf)
+	if err != nil || n != 1 || buf[0] != 4 {
+		t.Fatalf("failed unicast: %d %#v", n, err)
+	}
+
+	network.Disconnect("c", "eth0")
+	a.Send([]byte{5}, packet.AllIss)
+	if n, _, err := b.Recv(buf); err != nil || buf[0] != 5 {
+		t.Fatalf("failed after disconnect: %d %#v", n, err)
+	}
+	select {
+	case <-c.(*memoryEndpoint).recvCh:
+		t.Fatalf("failed disconnected interface received")
+	default:
+	}
+
+	b.Close()
+	if _, _, err := b.Recv(buf); err == nil {
+		t.Fatalf("failed Recv after Close")
+	}
+}
+
+// serveTest runs isis as Serve does, with cfg and k in place of the
+// config file and the host interfaces.
+func serveTest(isis *IsisServer, cfg *config.IsisConfig, k *kernel.KernelStatus) {
+	var updateWg sync.WaitGroup
+	updateWg.Add(1)
+	go isis.periodic(make(chan struct{}))
+	go isis.decisionProcess()
+	go isis.updateProcess(&updateWg)
+	isis.lock.Lock()
+	isis.handleKernelChanged(k)
+	isis.handleConfigChanged(cfg)
+	isis.lock.Unlock()
+	updateWg.Done()
+}
+
+func newTestIsisServer(t *testing.T, network *MemoryNetwork, id byte) (*IsisServer, *kernel.FakeProvider) {
+	cfg, err := config.Parse([]byte(fmt.Sprintf(`
+[config]
+  system-id = "0000.0000.000%d"
+  area-address-list = ["49.0001"]
+  level-type = "level-2"
+
+[[address-families]]
+  [address-families.config]
+    address-family = "ipv4"
+
+[[interfaces]]
+  [interfaces.config]
+    name = "eth0"
+    interface-type = "point-to-point"
+    level-type = "level-2"
+  [interfaces.hello-interval.config]
+    value = 1
+
+[[interfaces]]
+  [interfaces.config]
+    name = "lo"
+    passive = true
+`, id)), "toml")
+	if err != nil {
+		t.Fatalf("failed Parse: %#v", err)
+	}
+	k := &kernel.KernelStatus{
+		Interfaces: []*kernel.Interface{
+			&kernel.Interface{
+				IfIndex: 1,
+				Name:    "lo",
+				IfType:  kernel.IF_TYPE_LOOPBACK,
+				Mtu:     65536,
+				Up:      true,
+				Ipv4Addresses: []*kernel.Ipv4Address{
+					&kernel.Ipv4Address{Address: 0xc0a80000 + uint32(id), PrefixLength: 32},
+				},
+			},
+			&kernel.Interface{
+				IfIndex:      2,
+				Name:         "eth0",
+				IfType:       kernel.IF_TYPE_POINTTOPOINT,
+				HardwareAddr: []byte{0x02, 0, 0, 0, 0, id},
+				Mtu:          1500,
+				Up:           true,
+				Ipv4Addresses: []*kernel.Ipv4Address{
+					&kernel.Ipv4Address{Address: 0x0a000000 + uint32(id), PrefixLength: 24},
+				},
+			},
+		},
+	}
+	fake := kernel.NewFakeProvider()
+	for _, iface := range k.Interfaces {
+		fake.SetInterface(iface)
+	}
+	isis := NewIsisServer("", "")
+	isis.SetKernelProvider(fake)
+	isis.SetFib(true)
+	isis.SetTransport(network.TransportFactory(fmt.Sprintf("r%d", id)))
+	network.Connect("link", fmt.Sprintf("r%d", id), "eth0")
+	serveTest(isis, cfg, k)
+	return isis, fake
+}
+
+func TestMemoryTransportAdjacency(t *testing.T) {
+	network := NewMemoryNetwork()
+	r1, fib1 := newTestIsisServer(t, network, 1)
+	r2, _ := newTestIsisServer(t, network, 2)
+
+	// r1 learns r2's loopback over the in-memory link
+	deadline := time.Now().Add(30 * time.Second)
+	for {
+		var found bool
+		r1.lock.RLock()
+		for _, ri := range r1.ipv4RiDb[ISIS_LEVEL_2] {
+			if ri.prefixAddress == 0xc0a80002 && ri.prefixLength == 32 && ri.metric == 20 {
+				found = true
+			}
+		}
+		lsps := len(r1.lsDb[ISIS_LEVEL_2])
+		r1.lock.RUnlock()
+		r2.lock.RLock()
+		lsps2 := len(r2.lsDb[ISIS_LEVEL_2])
+		r2.lock.RUnlock()
+		var installed bool
+		for _, route := range fib1.Ipv4Routes() {
+			if route.Prefix == 0xc0a80002 && route.PrefixLength == 32 &&
+				route.Protocol == kernel.ROUTE_PROTOCOL_ISIS && len(route.NextHops) == 1 &&
+				route.NextHops[0].Address == 0x0a000002 && route.NextHops[0].IfIndex == 2 {
+				installed = true
+			}
+		}
+		if found && installed && lsps == 2 && lsps2 == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("failed convergence: found %t installed %t lsps %d %d", found, installed, lsps, lsps2)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
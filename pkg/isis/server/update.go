@@ -44,6 +44,7 @@ const (
 	UPDATE_CH_MSG_TYPE_ADJACENCY_UP
 	UPDATE_CH_MSG_TYPE_ADJACENCY_DOWN
 	UPDATE_CH_MSG_TYPE_LSDB_CHANGED
+	UPDATE_CH_MSG_TYPE_ROUTES_CHANGED
 	UPDATE_CH_MSG_TYPE_EXIT
 )
 
@@ -67,6 +68,8 @@ func (msgType UpdateChMsgType) String() string {
 		return "UPDATE_CH_MSG_TYPE_ADJACENCY_DOWN"
 	case UPDATE_CH_MSG_TYPE_LSDB_CHANGED:
 		return "UPDATE_CH_MSG_TYPE_LSDB_CHANGED"
+	case UPDATE_CH_MSG_TYPE_ROUTES_CHANGED:
+		return "UPDATE_CH_MSG_TYPE_ROUTES_CHANGED"
 	case UPDATE_CH_MSG_TYPE_EXIT:
 		return "UPDATE_CH_MSG_TYPE_EXIT"
 	}
@@ -100,10 +103,11 @@ func (isis *IsisServer) updateChSend(msg *UpdateChMsg) {
 	go func() {
 		updateChSendCountLock.Lock()
 		updateChSendCount++
+		count := updateChSendCount
 		updateChSendCountLock.Unlock()
-		log.Debugf("updateChSend[%d]: begin", updateChSendCount)
+		log.Debugf("updateChSend[%d]: begin", count)
 		isis.updateCh <- msg
-		log.Debugf("updateChSend[%d]: end", updateChSendCount)
+		log.Debugf("updateChSend[%d]: end", count)
 	}()
 }
 
@@ -112,45 +116,67 @@ func (isis *IsisServer) updateProcess(wg *sync.WaitGroup) {
 	defer log.Debugf("exit")
 	wg.Wait()
 	for {
-		needUpdateOriginLsps := false
-		needDecisionProcess := false
 		msg := <-isis.updateCh
 		log.Infof("%s", msg)
-		switch msg.msgType {
-		case UPDATE_CH_MSG_TYPE_CONFIG_CHANGED:
-		case UPDATE_CH_MSG_TYPE_KERNEL_CHANGED:
-		case UPDATE_CH_MSG_TYPE_ISIS_ENABLE:
-		case UPDATE_CH_MSG_TYPE_ISIS_DISABLE:
-		case UPDATE_CH_MSG_TYPE_CIRCUIT_ENABLE:
-			isis.handleCircuitUp(msg.circuit)
-		case UPDATE_CH_MSG_TYPE_CIRCUIT_DISABLE:
-			isis.handleCircuitDown(msg.circuit)
-		case UPDATE_CH_MSG_TYPE_ADJACENCY_UP:
-			isis.handleAdjacencyUp(msg.adjacency)
-		case UPDATE_CH_MSG_TYPE_ADJACENCY_DOWN:
-			isis.handleAdjacencyDown(msg.adjacency)
-		case UPDATE_CH_MSG_TYPE_LSDB_CHANGED:
-			needDecisionProcess = true
-		case UPDATE_CH_MSG_TYPE_EXIT:
+		if msg.msgType == UPDATE_CH_MSG_TYPE_EXIT {
 			goto EXIT
 		}
-		isis.handleCircuitStateTransitions()
-		if isis.changed() {
-			needUpdateOriginLsps = true
-			needDecisionProcess = true
-		}
-		if needUpdateOriginLsps {
-			isis.updateOriginLsps()
-		}
-		if needDecisionProcess {
-			isis.decisionChSend(&DecisionChMsg{
-				msgType: DECISION_CH_MSG_TYPE_DO,
-			})
+		for _, send := range isis.handleUpdate(msg) {
+			send.ch <- send.msg
 		}
 	}
 EXIT:
 }
 
+// handleUpdate processes msg and returns the messages queued for the
+// goroutines of the circuits meanwhile.
+func (isis *IsisServer) handleUpdate(msg *UpdateChMsg) []*circuitChSend {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	isis.lock.Lock()
+	defer isis.lock.Unlock()
+	needUpdateOriginLsps := false
+	needDecisionProcess := false
+	switch msg.msgType {
+	case UPDATE_CH_MSG_TYPE_CONFIG_CHANGED:
+		// the preferences and the install policies may have changed
+		needDecisionProcess = true
+	case UPDATE_CH_MSG_TYPE_KERNEL_CHANGED:
+	case UPDATE_CH_MSG_TYPE_ISIS_ENABLE:
+	case UPDATE_CH_MSG_TYPE_ISIS_DISABLE:
+	case UPDATE_CH_MSG_TYPE_CIRCUIT_ENABLE:
+		isis.handleCircuitUp(msg.circuit)
+	case UPDATE_CH_MSG_TYPE_CIRCUIT_DISABLE:
+		isis.handleCircuitDown(msg.circuit)
+	case UPDATE_CH_MSG_TYPE_ADJACENCY_UP:
+		isis.handleAdjacencyUp(msg.adjacency)
+		needDecisionProcess = true
+	case UPDATE_CH_MSG_TYPE_ADJACENCY_DOWN:
+		isis.handleAdjacencyDown(msg.adjacency)
+		needDecisionProcess = true
+	case UPDATE_CH_MSG_TYPE_LSDB_CHANGED:
+		needDecisionProcess = true
+	case UPDATE_CH_MSG_TYPE_ROUTES_CHANGED:
+		// changed() below picks up the routes to be leaked
+	}
+	isis.handleCircuitStateTransitions()
+	if isis.changed() {
+		needUpdateOriginLsps = true
+		needDecisionProcess = true
+	}
+	if needUpdateOriginLsps {
+		isis.updateOriginLsps()
+	}
+	if needDecisionProcess {
+		isis.decisionChSend(&DecisionChMsg{
+			msgType: DECISION_CH_MSG_TYPE_DO,
+		})
+	}
+	sends := isis.circuitChSends
+	isis.circuitChSends = nil
+	return sends
+}
+
 func (isis *IsisServer) handleCircuitUp(circuit *Circuit) {
 	log.Debug("enter: %s", circuit.name)
 	defer log.Debug("exit: %s", circuit.name)
@@ -361,11 +387,17 @@ func (isis *IsisServer) updateLocalSystemLsps(level IsisLevel) {
 			return
 		}
 		for _, ir := range isis.ipv4Reachabilities[level] {
-			if ir.scopeHost {
+			// without the up/down bit the routes leaked into
+			// level 1 may loop
+			if ir.scopeHost || (ir.leaked && ir.down) {
 				continue
 			}
+			metric := ir.metric
+			if metric > MAX_NARROW_METRIC {
+				metric = MAX_NARROW_METRIC
+			}
 			subnet, err := packet.NewIpInternalReachInfoIpSubnet()
-			subnet.DefaultMetric = uint8(ir.metric)
+			subnet.DefaultMetric = uint8(metric)
 			subnet.IpAddress = ir.ipv4Prefix
 			subnet.SubnetMask = util.Plen2snmask4(ir.prefixLength)
 			err = ipInternalReachInfoTlv.AddIpSubnet(subnet)
@@ -418,7 +450,19 @@ func (isis *IsisServer) updateLocalSystemLsps(level IsisLevel) {
 			subnet, err := packet.NewExtendedIpReachabilityIpv4Prefix(
 				ir.ipv4Prefix, ir.prefixLength)
 			subnet.MetricInformation = ir.metric
+			subnet.UpDownBit = ir.down
+			subnet.SetTags(ir.tags)
 			err = extendedIpReachabilityTlv.AddIpv4Prefix(subnet)
+			if err != nil {
+				// full, go on with another one
+				err = lss[index].AddExtendedIpReachabilityTlv(extendedIpReachabilityTlv)
+				if err != nil {
+					log.Infof("AddExtendedIpReachabilityTlv failed: %v", err)
+					return
+				}
+				extendedIpReachabilityTlv, _ = packet.NewExtendedIpReachabilityTlv()
+				err = extendedIpReachabilityTlv.AddIpv4Prefix(subnet)
+			}
 			if err != nil {
 				log.Infof("AddIpv4Prefix failed: %v", err)
 				return
@@ -443,7 +487,20 @@ func (isis *IsisServer) updateLocalSystemLsps(level IsisLevel) {
 		subnet, err := packet.NewIpv6ReachabilityIpv6Prefix(
 			ir.ipv6Prefix, ir.prefixLength)
 		subnet.Metric = ir.metric
+		subnet.UpDownBit = ir.down
+		subnet.ExternalOriginalBit = ir.external
+		subnet.SetTags(ir.tags)
 		err = ipv6ReachabilityTlv.AddIpv6Prefix(subnet)
+		if err != nil {
+			// full, go on with another one
+			err = lss[index].AddIpv6ReachabilityTlv(ipv6ReachabilityTlv)
+			if err != nil {
+				log.Infof("AddIpv6ReachabilityTlv failed: %v", err)
+				return
+			}
+			ipv6ReachabilityTlv, _ = packet.NewIpv6ReachabilityTlv()
+			err = ipv6ReachabilityTlv.AddIpv6Prefix(subnet)
+		}
 		if err != nil {
 			log.Infof("AddIpv6Prefix failed: %v", err)
 			return
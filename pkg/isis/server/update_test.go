@@ -0,0 +1,147 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m-asama/golsr/internal/pkg/isis/config"
+	"github.com/m-asama/golsr/internal/pkg/kernel"
+	"github.com/m-asama/golsr/pkg/isis/packet"
+)
+
+// newTestLanServer returns a server with a broadcast circuit on which
+// neither the update process nor the decision process run, so that the
+// test plays their part.
+func newTestLanServer(t *testing.T) (*IsisServer, *Circuit) {
+	cfg, err := config.Parse([]byte(`
+[config]
+  system-id = "0000.0000.0001"
+  area-address-list = ["49.0001"]
+  level-type = "level-2"
+
+[[address-families]]
+  [address-families.config]
+    address-family = "ipv4"
+
+[[interfaces]]
+  [interfaces.config]
+    name = "eth0"
+    interface-type = "broadcast"
+    level-type = "level-2"
+`), "toml")
+	if err != nil {
+		t.Fatalf("failed Parse: %#v", err)
+	}
+	k := &kernel.KernelStatus{
+		Interfaces: []*kernel.Interface{
+			&kernel.Interface{
+				IfIndex:      2,
+				Name:         "eth0",
+				IfType:       kernel.IF_TYPE_BROADCAST,
+				HardwareAddr: []byte{0x02, 0, 0, 0, 0, 1},
+				Mtu:          1500,
+				Up:           true,
+				Ipv4Addresses: []*kernel.Ipv4Address{
+					&kernel.Ipv4Address{Address: 0x0a000001, PrefixLength: 24},
+				},
+			},
+		},
+	}
+	network := NewMemoryNetwork()
+	network.Connect("lan", "r1", "eth0")
+	isis := NewIsisServer("", "")
+	isis.SetKernelProvider(kernel.NewFakeProvider())
+	isis.SetTransport(network.TransportFactory("r1"))
+	isis.lock.Lock()
+	isis.handleKernelChanged(k)
+	isis.handleConfigChanged(cfg)
+	isis.lock.Unlock()
+	isis.handleUpdate(&UpdateChMsg{
+		msgType: UPDATE_CH_MSG_TYPE_CONFIG_CHANGED,
+	})
+	isis.lock.RLock()
+	circuit := isis.findCircuitByIfIndex(2)
+	isis.lock.RUnlock()
+	if circuit == nil {
+		t.Fatalf("failed findCircuitByIfIndex")
+	}
+	return isis, circuit
+}
+
+// newTestLanAdjacency adds an up level 2 adjacency to r2 which wins the
+// DIS election on circuit. The caller holds isis.lock.
+func newTestLanAdjacency(circuit *Circuit) *Adjacency {
+	adjacency, _ := NewAdjacency(circuit)
+	adjacency.adjState = packet.ADJ_3WAY_STATE_UP
+	adjacency.adjType = ADJ_TYPE_LEVEL2_LAN
+	adjacency.adjUsage = ADJ_USAGE_LEVEL2
+	adjacency.lanAddress = [packet.SYSTEM_ID_LENGTH]byte{0x02, 0, 0, 0, 0, 2}
+	adjacency.systemId = [packet.SYSTEM_ID_LENGTH]byte{0, 0, 0, 0, 0, 2}
+	adjacency.priority = 127
+	circuit.addAdjacency(adjacency)
+	return adjacency
+}
+
+// drainDecisionCh returns how many messages were sent to the decision
+// process until it stayed quiet for a while.
+func drainDecisionCh(isis *IsisServer) int {
+	count := 0
+	for {
+		select {
+		case <-isis.decisionCh:
+			count++
+		case <-time.After(200 * time.Millisecond):
+			return count
+		}
+	}
+}
+
+func TestAdjacencyChangeDecision(t *testing.T) {
+	isis, circuit := newTestLanServer(t)
+	isis.lock.Lock()
+	adjacency := newTestLanAdjacency(circuit)
+	isis.lock.Unlock()
+	isis.handleUpdate(&UpdateChMsg{
+		msgType: UPDATE_CH_MSG_TYPE_KERNEL_CHANGED,
+	})
+	drainDecisionCh(isis)
+
+	isis.handleUpdate(&UpdateChMsg{
+		msgType: UPDATE_CH_MSG_TYPE_KERNEL_CHANGED,
+	})
+	if count := drainDecisionCh(isis); count != 0 {
+		t.Fatalf("failed unchanged state triggered the decision process %d times", count)
+	}
+
+	// the routes through the adjacency are recalculated when it comes up
+	// or goes down even if the LSPs of the router stay the same
+	for _, msgType := range []UpdateChMsgType{
+		UPDATE_CH_MSG_TYPE_ADJACENCY_UP,
+		UPDATE_CH_MSG_TYPE_ADJACENCY_DOWN,
+	} {
+		isis.handleUpdate(&UpdateChMsg{
+			msgType:   msgType,
+			adjacency: adjacency,
+		})
+		if count := drainDecisionCh(isis); count == 0 {
+			t.Fatalf("failed %s did not trigger the decision process", msgType)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package packet
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+)
+
+type CryptoAlgorithm uint8
+
+const (
+	_ CryptoAlgorithm = iota
+	CRYPTO_ALGORITHM_MD5
+	CRYPTO_ALGORITHM_HMAC_SHA_1
+	CRYPTO_ALGORITHM_HMAC_SHA_256
+	CRYPTO_ALGORITHM_HMAC_SHA_384
+	CRYPTO_ALGORITHM_HMAC_SHA_512
+)
+
+func (algorithm CryptoAlgorithm) String() string {
+	switch algorithm {
+	case CRYPTO_ALGORITHM_MD5:
+		return "CRYPTO_ALGORITHM_MD5"
+	case CRYPTO_ALGORITHM_HMAC_SHA_1:
+		return "CRYPTO_ALGORITHM_HMAC_SHA_1"
+	case CRYPTO_ALGORITHM_HMAC_SHA_256:
+		return "CRYPTO_ALGORITHM_HMAC_SHA_256"
+	case CRYPTO_ALGORITHM_HMAC_SHA_384:
+		return "CRYPTO_ALGORITHM_HMAC_SHA_384"
+	case CRYPTO_ALGORITHM_HMAC_SHA_512:
+		return "CRYPTO_ALGORITHM_HMAC_SHA_512"
+	}
+	return fmt.Sprintf("CryptoAlgorithm(%d)", algorithm)
+}
+
+func (algorithm CryptoAlgorithm) hash() func() hash.Hash {
+	switch algorithm {
+	case CRYPTO_ALGORITHM_MD5:
+		return md5.New
+	case CRYPTO_ALGORITHM_HMAC_SHA_1:
+		return sha1.New
+	case CRYPTO_ALGORITHM_HMAC_SHA_256:
+		return sha256.New
+	case CRYPTO_ALGORITHM_HMAC_SHA_384:
+		return sha512.New384
+	case CRYPTO_ALGORITHM_HMAC_SHA_512:
+		return sha512.New
+	}
+	return nil
+}
+
+// DigestLength is the length of the message digest of the algorithm, 0
+// if unknown.
+func (algorithm CryptoAlgorithm) DigestLength() int {
+	if h := algorithm.hash(); h != nil {
+		return h().Size()
+	}
+	return 0
+}
+
+const (
+	// APAD is what Apad of RFC 5709 3.3 is made of.
+	APAD = 0x878fe1f3
+	// OSPFV3_AUTH_TRAILER_LENGTH is the length of the authentication
+	// trailer of RFC 7166 without the authentication data.
+	OSPFV3_AUTH_TRAILER_LENGTH = 16
+	// OSPFV3_AUTH_TYPE_HMAC is the HMAC cryptographic authentication of
+	// RFC 7166.
+	OSPFV3_AUTH_TYPE_HMAC = 1
+)
+
+// apad returns Apad of length octets: the address src, if any, followed
+// by 0x878FE1F3 repeated, as in RFC 5709 3.3, RFC 7474 5 and RFC 7166
+// 4.5.
+func apad(length int, src []byte) []byte {
+	pad := make([]byte, length)
+	for i := 0; i+4 <= length; i += 4 {
+		binary.BigEndian.PutUint32(pad[i:i+4], APAD)
+	}
+	copy(pad, src)
+	return pad
+}
+
+// hmacDigest computes the HMAC of data and Apad with key as in RFC 5709
+// 3.3: the key is padded with zeros or hashed to the length of the
+// digest.
+func (algorithm CryptoAlgorithm) hmacDigest(key, data, src []byte) []byte {
+	h := algorithm.hash()
+	length := h().Size()
+	ks := make([]byte, length)
+	if len(key) > length {
+		k := h()
+		k.Write(key)
+		copy(ks, k.Sum(nil))
+	} else {
+		copy(ks, key)
+	}
+	mac := hmac.New(h, ks)
+	mac.Write(data)
+	mac.Write(apad(length, src))
+	return mac.Sum(nil)
+}
+
+// Ospfv2CryptoAuthentication returns the authentication field of an
+// OSPFv2 packet of cryptographic authentication as in RFC 2328 D.3.
+func Ospfv2CryptoAuthentication(keyId uint8, length int, sequenceNumber uint32) [AUTHENTICATION_LENGTH]byte {
+	var authentication [AUTHENTICATION_LENGTH]byte
+	authentication[2] = keyId
+	authentication[3] = uint8(length)
+	binary.BigEndian.PutUint32(authentication[4:8], sequenceNumber)
+	return authentication
+}
+
+// ParseOspfv2CryptoAuthentication returns the key ID, the length of the
+// digest and the sequence number of the authentication field.
+func ParseOspfv2CryptoAuthentication(authentication [AUTHENTICATION_LENGTH]byte) (uint8, int, uint32) {
+	return authentication[2], int(authentication[3]), binary.BigEndian.Uint32(authentication[4:8])
+}
+
+// Ospfv2Digest returns the message digest of the OSPFv2 packet data,
+// followed by the low-order 32 bits of the sequence number if extended,
+// sent from src. It is the keyed MD5 of RFC 2328 D.4.3 or the HMAC-SHA of
+// RFC 5709, or the HMAC-SHA of RFC 7474 with src in Apad if extended.
+func Ospfv2Digest(algorithm CryptoAlgorithm, key []byte, data []byte, src uint32, extended bool) ([]byte, error) {
+	if algorithm.hash() == nil {
+		return nil, errors.New("Ospfv2Digest: algorithm invalid")
+	}
+	if algorithm == CRYPTO_ALGORITHM_MD5 {
+		if extended {
+			return nil, errors.New("Ospfv2Digest: md5 not extended")
+		}
+		k := make([]byte, md5.Size)
+		copy(k, key)
+		h := md5.New()
+		h.Write(data)
+		h.Write(k)
+		return h.Sum(nil), nil
+	}
+	if !extended {
+		return algorithm.hmacDigest(key, data, nil), nil
+	}
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[0:4], src)
+	return algorithm.hmacDigest(key, data, b[:]), nil
+}
+
+// Ospfv3AuthTrailer is the authentication trailer of RFC 7166 which
+// follows an OSPFv3 packet.
+type Ospfv3AuthTrailer struct {
+	SaId           uint16
+	SequenceNumber uint64
+	AuthData       []byte
+}
+
+// DecodeOspfv3AuthTrailer decodes the trailer at the beginning of data.
+func DecodeOspfv3AuthTrailer(data []byte) (*Ospfv3AuthTrailer, error) {
+	if len(data) < OSPFV3_AUTH_TRAILER_LENGTH {
+		return nil, errors.New("DecodeOspfv3AuthTrailer: data length too short")
+	}
+	if binary.BigEndian.Uint16(data[0:2]) != OSPFV3_AUTH_TYPE_HMAC {
+		return nil, errors.New("DecodeOspfv3AuthTrailer: authentication type invalid")
+	}
+	length := int(binary.BigEndian.Uint16(data[2:4]))
+	if length < OSPFV3_AUTH_TRAILER_LENGTH || len(data) < length {
+		return nil, errors.New("DecodeOspfv3AuthTrailer: data length mismatch")
+	}
+	trailer := &Ospfv3AuthTrailer{
+		SaId:           binary.BigEndian.Uint16(data[6:8]),
+		SequenceNumber: binary.BigEndian.Uint64(data[8:16]),
+		AuthData:       append([]byte{}, data[OSPFV3_AUTH_TRAILER_LENGTH:length]...),
+	}
+	return trailer, nil
+}
+
+func (trailer *Ospfv3AuthTrailer) header(length int) []byte {
+	data := make([]byte, OSPFV3_AUTH_TRAILER_LENGTH)
+	binary.BigEndian.PutUint16(data[0:2], OSPFV3_AUTH_TYPE_HMAC)
+	binary.BigEndian.PutUint16(data[2:4], uint16(OSPFV3_AUTH_TRAILER_LENGTH+length))
+	binary.BigEndian.PutUint16(data[6:8], trailer.SaId)
+	binary.BigEndian.PutUint64(data[8:16], trailer.SequenceNumber)
+	return data
+}
+
+// digest computes the authentication data of the trailer of the OSPFv3
+// packet data sent from src as in RFC 7166 4.5.
+func (trailer *Ospfv3AuthTrailer) digest(algorithm CryptoAlgorithm, key []byte, data []byte, src [4]uint32) ([]byte, error) {
+	if algorithm.hash() == nil || algorithm == CRYPTO_ALGORITHM_MD5 {
+		return nil, errors.New("Ospfv3AuthTrailer.digest: algorithm invalid")
+	}
+	var b [16]byte
+	for i, a := range src {
+		binary.BigEndian.PutUint32(b[4*i:4*i+4], a)
+	}
+	covered := append(append([]byte{}, data...), trailer.header(algorithm.DigestLength())...)
+	return algorithm.hmacDigest(key, covered, b[:]), nil
+}
+
+// Sign sets the authentication data of the trailer of the OSPFv3 packet
+// data sent from src and returns the trailer to append to it.
+func (trailer *Ospfv3AuthTrailer) Sign(algorithm CryptoAlgorithm, key []byte, data []byte, src [4]uint32) ([]byte, error) {
+	digest, err := trailer.digest(algorithm, key, data, src)
+	if err != nil {
+		return nil, err
+	}
+	trailer.AuthData = digest
+	return append(trailer.header(len(digest)), digest...), nil
+}
+
+// Valid tells whether the authentication data of the trailer of the
+// OSPFv3 packet data sent from src is the one computed with key.
+func (trailer *Ospfv3AuthTrailer) Valid(algorithm CryptoAlgorithm, key []byte, data []byte, src [4]uint32) bool {
+	digest, err := trailer.digest(algorithm, key, data, src)
+	return err == nil && hmac.Equal(digest, trailer.AuthData)
+}
@@ -0,0 +1,116 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package packet
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestOspfv2Digest(t *testing.T) {
+	data := make([]byte, 48)
+	for i := range data {
+		data[i] = uint8(i)
+	}
+	key := []byte("golsr")
+
+	tests := []struct {
+		algorithm CryptoAlgorithm
+		extended  bool
+		digest    string
+	}{
+		{CRYPTO_ALGORITHM_MD5, false, "b7a81f78fdc781b64da1f22b0727b369"},
+		{CRYPTO_ALGORITHM_HMAC_SHA_1, false, "c9b4b3d94810fdb2a8ff85a166c2083e60b1284d"},
+		{CRYPTO_ALGORITHM_HMAC_SHA_1, true, "d2963a0889e0daa683971937d9bc29b8cbd8f9e0"},
+		{CRYPTO_ALGORITHM_HMAC_SHA_256, false, "3f756b7261cd94990583a64b1fcd0dfe7fdf4d9488a14bbdbe887ac3bef2fed0"},
+		{CRYPTO_ALGORITHM_HMAC_SHA_256, true, "e8d659e2dc0ee7e9dc8a7cda112fdd37e4ee9d23eccb93567bc6de0eca45c19c"},
+	}
+	for _, test := range tests {
+		digest, err := Ospfv2Digest(test.algorithm, key, data, 0x0a000001, test.extended)
+		if err != nil {
+			t.Fatalf("failed Ospfv2Digest: %s %#v", test.algorithm, err)
+		}
+		if hex.EncodeToString(digest) != test.digest {
+			t.Fatalf("failed Ospfv2Digest: %s %t %x", test.algorithm, test.extended, digest)
+		}
+		if len(digest) != test.algorithm.DigestLength() {
+			t.Fatalf("failed DigestLength: %s", test.algorithm)
+		}
+	}
+
+	if _, err := Ospfv2Digest(CRYPTO_ALGORITHM_MD5, key, data, 0x0a000001, true); err == nil {
+		t.Fatalf("failed Ospfv2Digest: extended md5 accepted")
+	}
+
+	authentication := Ospfv2CryptoAuthentication(3, 20, 0x01020304)
+	keyId, length, seq := ParseOspfv2CryptoAuthentication(authentication)
+	if keyId != 3 || length != 20 || seq != 0x01020304 {
+		t.Fatalf("failed ParseOspfv2CryptoAuthentication: %d %d 0x%08x", keyId, length, seq)
+	}
+}
+
+func TestOspfv3AuthTrailer(t *testing.T) {
+	data := make([]byte, 48)
+	for i := range data {
+		data[i] = uint8(i)
+	}
+	key := []byte("golsr")
+	src := [4]uint32{0xfe800000, 0, 0, 1}
+
+	trailer := &Ospfv3AuthTrailer{
+		SaId:           7,
+		SequenceNumber: 0x0102030405060708,
+	}
+	b, err := trailer.Sign(CRYPTO_ALGORITHM_HMAC_SHA_256, key, data, src)
+	if err != nil {
+		t.Fatalf("failed Sign: %#v", err)
+	}
+	if len(b) != OSPFV3_AUTH_TRAILER_LENGTH+32 {
+		t.Fatalf("failed Sign: length %d", len(b))
+	}
+	if hex.EncodeToString(trailer.AuthData) != "6303310cd8e5b023dbad366f96c7ce389b6ca801c5da176b1cd036c58b90c394" {
+		t.Fatalf("failed Sign: %x", trailer.AuthData)
+	}
+
+	decoded, err := DecodeOspfv3AuthTrailer(b)
+	if err != nil {
+		t.Fatalf("failed DecodeOspfv3AuthTrailer: %#v", err)
+	}
+	if decoded.SaId != 7 || decoded.SequenceNumber != 0x0102030405060708 ||
+		!bytes.Equal(decoded.AuthData, trailer.AuthData) {
+		t.Fatalf("failed DecodeOspfv3AuthTrailer: %#v", decoded)
+	}
+	if !decoded.Valid(CRYPTO_ALGORITHM_HMAC_SHA_256, key, data, src) {
+		t.Fatalf("failed Valid")
+	}
+	if decoded.Valid(CRYPTO_ALGORITHM_HMAC_SHA_256, []byte("other"), data, src) {
+		t.Fatalf("failed Valid: wrong key accepted")
+	}
+	if decoded.Valid(CRYPTO_ALGORITHM_HMAC_SHA_256, key, data, [4]uint32{0xfe800000, 0, 0, 2}) {
+		t.Fatalf("failed Valid: wrong source accepted")
+	}
+	decoded.SequenceNumber++
+	if decoded.Valid(CRYPTO_ALGORITHM_HMAC_SHA_256, key, data, src) {
+		t.Fatalf("failed Valid: modified sequence number accepted")
+	}
+
+	if _, err := DecodeOspfv3AuthTrailer(b[:20]); err == nil {
+		t.Fatalf("failed DecodeOspfv3AuthTrailer: truncated trailer accepted")
+	}
+}
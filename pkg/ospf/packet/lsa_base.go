@@ -0,0 +1,295 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package packet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// LsaKey identifies an LSA, as a Link State Request does.
+type LsaKey struct {
+	LsType            LsType
+	LinkStateId       uint32
+	AdvertisingRouter uint32
+}
+
+func (key LsaKey) String() string {
+	return fmt.Sprintf("%s %s %s", key.LsType.String(),
+		Ipv4String(key.LinkStateId), Ipv4String(key.AdvertisingRouter))
+}
+
+// LsaHeader is the header of an OSPFv2 LSA or of an OSPFv3 one, which has
+// no options and a 16-bit LS type.
+type LsaHeader struct {
+	version           uint8
+	LsAge             uint16
+	Options           Options
+	lsType            LsType
+	LinkStateId       uint32
+	AdvertisingRouter uint32
+	LsSequenceNumber  int32
+	LsChecksum        uint16
+	length            uint16
+}
+
+func NewLsaHeader(lsType LsType) *LsaHeader {
+	return newLsaHeader(OSPF_VERSION, lsType)
+}
+
+func newLsaHeader(version uint8, lsType LsType) *LsaHeader {
+	return &LsaHeader{
+		version:          version,
+		lsType:           lsType,
+		LsSequenceNumber: INITIAL_SEQUENCE_NUMBER,
+		length:           LSA_HEADER_LENGTH,
+	}
+}
+
+func (header *LsaHeader) Version() uint8 {
+	if header.version == 0 {
+		return OSPF_VERSION
+	}
+	return header.version
+}
+
+func (header *LsaHeader) LsType() LsType {
+	return header.lsType
+}
+
+// Length is the length of the whole LSA as it was last decoded or
+// serialized.
+func (header *LsaHeader) Length() uint16 {
+	return header.length
+}
+
+func (header *LsaHeader) Key() LsaKey {
+	return LsaKey{
+		LsType:            header.lsType,
+		LinkStateId:       header.LinkStateId,
+		AdvertisingRouter: header.AdvertisingRouter,
+	}
+}
+
+func (header *LsaHeader) String() string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "LsAge                           %d\n", header.LsAge)
+	if header.Version() != OSPFV3_VERSION {
+		fmt.Fprintf(&b, "Options                         %s\n", header.Options.String())
+	}
+	fmt.Fprintf(&b, "lsType                          %s(%d)\n", header.lsType.String(), header.lsType)
+	fmt.Fprintf(&b, "LinkStateId                     %s\n", Ipv4String(header.LinkStateId))
+	fmt.Fprintf(&b, "AdvertisingRouter               %s\n", Ipv4String(header.AdvertisingRouter))
+	fmt.Fprintf(&b, "LsSequenceNumber                0x%08x\n", uint32(header.LsSequenceNumber))
+	fmt.Fprintf(&b, "LsChecksum                      0x%04x\n", header.LsChecksum)
+	fmt.Fprintf(&b, "length                          %d\n", header.length)
+	return b.String()
+}
+
+func (header *LsaHeader) DecodeFromBytes(data []byte) error {
+	if len(data) < LSA_HEADER_LENGTH {
+		return errors.New("LsaHeader.DecodeFromBytes: data length too short")
+	}
+	header.LsAge = binary.BigEndian.Uint16(data[0:2])
+	if header.Version() == OSPFV3_VERSION {
+		header.lsType = LsType(binary.BigEndian.Uint16(data[2:4]))
+	} else {
+		header.Options = Options(data[2])
+		header.lsType = LsType(data[3])
+	}
+	header.LinkStateId = binary.BigEndian.Uint32(data[4:8])
+	header.AdvertisingRouter = binary.BigEndian.Uint32(data[8:12])
+	header.LsSequenceNumber = int32(binary.BigEndian.Uint32(data[12:16]))
+	header.LsChecksum = binary.BigEndian.Uint16(data[16:18])
+	header.length = binary.BigEndian.Uint16(data[18:20])
+	if header.length < LSA_HEADER_LENGTH {
+		return errors.New("LsaHeader.DecodeFromBytes: length invalid")
+	}
+	return nil
+}
+
+func (header *LsaHeader) Serialize() ([]byte, error) {
+	data := make([]byte, LSA_HEADER_LENGTH)
+	binary.BigEndian.PutUint16(data[0:2], header.LsAge)
+	if header.Version() == OSPFV3_VERSION {
+		binary.BigEndian.PutUint16(data[2:4], uint16(header.lsType))
+	} else {
+		data[2] = uint8(header.Options)
+		data[3] = uint8(header.lsType)
+	}
+	binary.BigEndian.PutUint32(data[4:8], header.LinkStateId)
+	binary.BigEndian.PutUint32(data[8:12], header.AdvertisingRouter)
+	binary.BigEndian.PutUint32(data[12:16], uint32(header.LsSequenceNumber))
+	binary.BigEndian.PutUint16(data[16:18], header.LsChecksum)
+	binary.BigEndian.PutUint16(data[18:20], header.length)
+	return data, nil
+}
+
+// Compare tells which of the instances of an LSA is newer as in
+// RFC 2328 13.1. It returns a positive value if header is newer than
+// other, a negative value if other is newer and zero if they are the
+// same instance.
+func (header *LsaHeader) Compare(other *LsaHeader) int {
+	if header.LsSequenceNumber != other.LsSequenceNumber {
+		if header.LsSequenceNumber > other.LsSequenceNumber {
+			return 1
+		}
+		return -1
+	}
+	if header.LsChecksum != other.LsChecksum {
+		if header.LsChecksum > other.LsChecksum {
+			return 1
+		}
+		return -1
+	}
+	if header.LsAge >= MAX_AGE && other.LsAge < MAX_AGE {
+		return 1
+	}
+	if header.LsAge < MAX_AGE && other.LsAge >= MAX_AGE {
+		return -1
+	}
+	diff := int(header.LsAge) - int(other.LsAge)
+	if diff > MAX_AGE_DIFF || diff < -MAX_AGE_DIFF {
+		if diff < 0 {
+			return 1
+		}
+		return -1
+	}
+	return 0
+}
+
+// serializeLsa returns the LSA of header and body, updating the length
+// of header.
+func serializeLsa(header *LsaHeader, body []byte) ([]byte, error) {
+	if LSA_HEADER_LENGTH+len(body) > 0xffff {
+		return nil, errors.New("serializeLsa: LSA too long")
+	}
+	header.length = uint16(LSA_HEADER_LENGTH + len(body))
+	data, err := header.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	return append(data, body...), nil
+}
+
+func DecodeLsaFromBytes(data []byte) (OspfLsa, error) {
+	return decodeLsaFromBytes(OSPF_VERSION, data)
+}
+
+func DecodeOspfv3LsaFromBytes(data []byte) (OspfLsa, error) {
+	return decodeLsaFromBytes(OSPFV3_VERSION, data)
+}
+
+func decodeLsaFromBytes(version uint8, data []byte) (OspfLsa, error) {
+	header := LsaHeader{version: version}
+	err := header.DecodeFromBytes(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < int(header.length) {
+		return nil, errors.New("DecodeLsaFromBytes: data length too short")
+	}
+	var lsa OspfLsa
+	if version == OSPFV3_VERSION {
+		lsa, err = NewOspfv3Lsa(header.lsType)
+	} else {
+		lsa, err = NewLsa(header.lsType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = lsa.DecodeFromBytes(data[0:header.length])
+	return lsa, err
+}
+
+// SetLsaChecksum sets the LS checksum of lsa, which covers the LSA but
+// the LS age.
+func SetLsaChecksum(lsa OspfLsa) error {
+	lsa.Header().LsChecksum = 0
+	data, err := lsa.Serialize()
+	if err != nil {
+		return err
+	}
+	lsa.Header().LsChecksum = fletcherChecksum(data[2:], 14)
+	return nil
+}
+
+func LsaChecksumValid(lsa OspfLsa) bool {
+	data, err := lsa.Serialize()
+	if err != nil {
+		return false
+	}
+	c0, c1 := fletcherSum(data[2:])
+	return lsa.Header().LsChecksum != 0 && c0 == 0 && c1 == 0
+}
+
+type UnknownLsa struct {
+	header LsaHeader
+
+	data []byte
+}
+
+func NewUnknownLsa(lsType LsType) (*UnknownLsa, error) {
+	return newUnknownLsa(OSPF_VERSION, lsType)
+}
+
+func newUnknownLsa(version uint8, lsType LsType) (*UnknownLsa, error) {
+	lsa := UnknownLsa{
+		header: *newLsaHeader(version, lsType),
+		data:   make([]byte, 0),
+	}
+	return &lsa, nil
+}
+
+func (lsa *UnknownLsa) LsType() LsType {
+	return lsa.header.lsType
+}
+
+func (lsa *UnknownLsa) Header() *LsaHeader {
+	return &lsa.header
+}
+
+func (lsa *UnknownLsa) String() string {
+	var b bytes.Buffer
+	b.WriteString(lsa.header.String())
+	fmt.Fprintf(&b, "data                            ")
+	for _, t := range lsa.data {
+		fmt.Fprintf(&b, "%02x", t)
+	}
+	fmt.Fprintf(&b, "\n")
+	return b.String()
+}
+
+func (lsa *UnknownLsa) DecodeFromBytes(data []byte) error {
+	err := lsa.header.DecodeFromBytes(data)
+	if err != nil {
+		return err
+	}
+	if len(data) != int(lsa.header.length) {
+		return errors.New("UnknownLsa.DecodeFromBytes: data length mismatch")
+	}
+	lsa.data = make([]byte, len(data)-LSA_HEADER_LENGTH)
+	copy(lsa.data, data[LSA_HEADER_LENGTH:])
+	return nil
+}
+
+func (lsa *UnknownLsa) Serialize() ([]byte, error) {
+	return serializeLsa(&lsa.header, lsa.data)
+}
@@ -0,0 +1,97 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package packet
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestLsaChecksum(t *testing.T) {
+	var err error
+
+	d1 := []byte{
+		0x00, 0x01, 0x02, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x80, 0x00, 0x00, 0x01,
+		0x7c, 0xb9, 0x00, 0x24,
+		0x01, 0x00, 0x00, 0x01,
+		0x0a, 0x00, 0x00, 0x00, 0xff, 0xff, 0xff, 0x00, 0x03, 0x00, 0x00, 0x0a,
+	}
+
+	l1, err := DecodeLsaFromBytes(d1)
+	if err != nil {
+		t.Fatalf("failed DecodeLsaFromBytes: %#v", err)
+	}
+	if !LsaChecksumValid(l1) {
+		t.Fatalf("failed LsaChecksumValid")
+	}
+	if err = SetLsaChecksum(l1); err != nil {
+		t.Fatalf("failed SetLsaChecksum: %#v", err)
+	}
+	if l1.Header().LsChecksum != 0x7cb9 {
+		t.Fatalf("failed SetLsaChecksum: 0x%04x", l1.Header().LsChecksum)
+	}
+
+	// the checksum does not cover the age
+	l1.Header().LsAge = MAX_AGE
+	if !LsaChecksumValid(l1) {
+		t.Fatalf("failed LsaChecksumValid: age")
+	}
+	l1.(*RouterLsa).Links[0].Metric = 20
+	if LsaChecksumValid(l1) {
+		t.Fatalf("failed LsaChecksumValid: modified LSA accepted")
+	}
+
+	l1.(*RouterLsa).Links[0].Metric = 10
+	l1.Header().LsAge = 1
+	d2, err := l1.Serialize()
+	if err != nil {
+		t.Fatalf("failed Serialize: %#v", err)
+	}
+
+	if !bytes.Equal(d1, d2) {
+		t.Fatalf("failed !bytes.Equal")
+	}
+
+	//t.Fatalf("\n%s", l1.String())
+}
+
+func TestLsaHeaderCompare(t *testing.T) {
+	h1 := NewLsaHeader(LS_TYPE_ROUTER)
+	h2 := NewLsaHeader(LS_TYPE_ROUTER)
+	if h1.Compare(h2) != 0 {
+		t.Fatalf("failed Compare: same instance")
+	}
+	h2.LsSequenceNumber++
+	if h1.Compare(h2) >= 0 || h2.Compare(h1) <= 0 {
+		t.Fatalf("failed Compare: sequence number")
+	}
+	h2.LsSequenceNumber = h1.LsSequenceNumber
+	h1.LsAge = MAX_AGE
+	if h1.Compare(h2) <= 0 {
+		t.Fatalf("failed Compare: max age")
+	}
+	h1.LsAge = 1000
+	h2.LsAge = 10
+	if h1.Compare(h2) >= 0 {
+		t.Fatalf("failed Compare: age")
+	}
+	h1.LsAge = 100
+	if h1.Compare(h2) != 0 {
+		t.Fatalf("failed Compare: age within MaxAgeDiff")
+	}
+}
@@ -0,0 +1,136 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package packet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+type ExternalTos struct {
+	ExternalMetric    bool
+	Tos               uint8
+	Metric            uint32
+	ForwardingAddress uint32
+	ExternalRouteTag  uint32
+}
+
+// ExternalLsa is an LSA of either type 5, AS-external, or type 7,
+// NSSA-external (RFC 3101), which share the format.
+type ExternalLsa struct {
+	header LsaHeader
+
+	NetworkMask       uint32
+	ExternalMetric    bool
+	Metric            uint32
+	ForwardingAddress uint32
+	ExternalRouteTag  uint32
+	Tos               []*ExternalTos
+}
+
+func NewExternalLsa(lsType LsType) (*ExternalLsa, error) {
+	if lsType != LS_TYPE_AS_EXTERNAL &&
+		lsType != LS_TYPE_NSSA_EXTERNAL {
+		return nil, errors.New("NewExternalLsa: lsType invalid")
+	}
+	lsa := ExternalLsa{
+		header: *NewLsaHeader(lsType),
+		Tos:    make([]*ExternalTos, 0),
+	}
+	return &lsa, nil
+}
+
+func (lsa *ExternalLsa) LsType() LsType {
+	return lsa.header.lsType
+}
+
+func (lsa *ExternalLsa) Header() *LsaHeader {
+	return &lsa.header
+}
+
+func (lsa *ExternalLsa) String() string {
+	var b bytes.Buffer
+	b.WriteString(lsa.header.String())
+	fmt.Fprintf(&b, "NetworkMask                     %s\n", Ipv4String(lsa.NetworkMask))
+	fmt.Fprintf(&b, "ExternalMetric                  %t\n", lsa.ExternalMetric)
+	fmt.Fprintf(&b, "Metric                          %d\n", lsa.Metric)
+	fmt.Fprintf(&b, "ForwardingAddress               %s\n", Ipv4String(lsa.ForwardingAddress))
+	fmt.Fprintf(&b, "ExternalRouteTag                0x%08x\n", lsa.ExternalRouteTag)
+	for i, tos := range lsa.Tos {
+		fmt.Fprintf(&b, "Tos[%d]\n", i)
+		fmt.Fprintf(&b, "    ExternalMetric              %t\n", tos.ExternalMetric)
+		fmt.Fprintf(&b, "    Tos                         %d\n", tos.Tos)
+		fmt.Fprintf(&b, "    Metric                      %d\n", tos.Metric)
+		fmt.Fprintf(&b, "    ForwardingAddress           %s\n", Ipv4String(tos.ForwardingAddress))
+		fmt.Fprintf(&b, "    ExternalRouteTag            0x%08x\n", tos.ExternalRouteTag)
+	}
+	return b.String()
+}
+
+func (lsa *ExternalLsa) DecodeFromBytes(data []byte) error {
+	err := lsa.header.DecodeFromBytes(data)
+	if err != nil {
+		return err
+	}
+	if len(data) != int(lsa.header.length) || len(data) < LSA_HEADER_LENGTH+16 ||
+		(len(data)-LSA_HEADER_LENGTH-4)%12 != 0 {
+		return errors.New("ExternalLsa.DecodeFromBytes: data length mismatch")
+	}
+	body := data[LSA_HEADER_LENGTH:]
+	lsa.NetworkMask = binary.BigEndian.Uint32(body[0:4])
+	lsa.ExternalMetric = body[4]&0x80 == 0x80
+	lsa.Metric = binary.BigEndian.Uint32(body[4:8]) & 0xffffff
+	lsa.ForwardingAddress = binary.BigEndian.Uint32(body[8:12])
+	lsa.ExternalRouteTag = binary.BigEndian.Uint32(body[12:16])
+	lsa.Tos = make([]*ExternalTos, 0)
+	for i := 16; i < len(body); i += 12 {
+		lsa.Tos = append(lsa.Tos, &ExternalTos{
+			ExternalMetric:    body[i]&0x80 == 0x80,
+			Tos:               body[i] & 0x7f,
+			Metric:            binary.BigEndian.Uint32(body[i:i+4]) & 0xffffff,
+			ForwardingAddress: binary.BigEndian.Uint32(body[i+4 : i+8]),
+			ExternalRouteTag:  binary.BigEndian.Uint32(body[i+8 : i+12]),
+		})
+	}
+	return nil
+}
+
+func (lsa *ExternalLsa) Serialize() ([]byte, error) {
+	body := make([]byte, 16+12*len(lsa.Tos))
+	binary.BigEndian.PutUint32(body[0:4], lsa.NetworkMask)
+	binary.BigEndian.PutUint32(body[4:8], lsa.Metric&0xffffff)
+	if lsa.ExternalMetric {
+		body[4] |= 0x80
+	}
+	binary.BigEndian.PutUint32(body[8:12], lsa.ForwardingAddress)
+	binary.BigEndian.PutUint32(body[12:16], lsa.ExternalRouteTag)
+	i := 16
+	for _, tos := range lsa.Tos {
+		binary.BigEndian.PutUint32(body[i:i+4], tos.Metric&0xffffff)
+		body[i] = tos.Tos & 0x7f
+		if tos.ExternalMetric {
+			body[i] |= 0x80
+		}
+		binary.BigEndian.PutUint32(body[i+4:i+8], tos.ForwardingAddress)
+		binary.BigEndian.PutUint32(body[i+8:i+12], tos.ExternalRouteTag)
+		i += 12
+	}
+	return serializeLsa(&lsa.header, body)
+}
@@ -0,0 +1,146 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package packet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// InterAreaPrefixLsa is an inter-area-prefix-LSA of OSPFv3, which replaces
+// the type 3 summary-LSA of OSPFv2.
+type InterAreaPrefixLsa struct {
+	header LsaHeader
+
+	Metric uint32
+	Prefix Ipv6Prefix
+}
+
+func NewInterAreaPrefixLsa() (*InterAreaPrefixLsa, error) {
+	lsa := InterAreaPrefixLsa{
+		header: *newLsaHeader(OSPFV3_VERSION, LS_TYPE_INTER_AREA_PREFIX),
+	}
+	return &lsa, nil
+}
+
+func (lsa *InterAreaPrefixLsa) LsType() LsType {
+	return lsa.header.lsType
+}
+
+func (lsa *InterAreaPrefixLsa) Header() *LsaHeader {
+	return &lsa.header
+}
+
+func (lsa *InterAreaPrefixLsa) String() string {
+	var b bytes.Buffer
+	b.WriteString(lsa.header.String())
+	fmt.Fprintf(&b, "Metric                          %d\n", lsa.Metric)
+	fmt.Fprintf(&b, "Prefix\n")
+	b.WriteString(lsa.Prefix.String())
+	return b.String()
+}
+
+func (lsa *InterAreaPrefixLsa) DecodeFromBytes(data []byte) error {
+	err := lsa.header.DecodeFromBytes(data)
+	if err != nil {
+		return err
+	}
+	if len(data) != int(lsa.header.length) || len(data) < LSA_HEADER_LENGTH+8 {
+		return errors.New("InterAreaPrefixLsa.DecodeFromBytes: data length mismatch")
+	}
+	body := data[LSA_HEADER_LENGTH:]
+	lsa.Metric = binary.BigEndian.Uint32(body[0:4]) & 0xffffff
+	n, err := lsa.Prefix.decodeFromBytes(body[4:])
+	if err != nil {
+		return err
+	}
+	if 4+n != len(body) {
+		return errors.New("InterAreaPrefixLsa.DecodeFromBytes: data length mismatch")
+	}
+	return nil
+}
+
+func (lsa *InterAreaPrefixLsa) Serialize() ([]byte, error) {
+	prefix, err := lsa.Prefix.serialize()
+	if err != nil {
+		return nil, err
+	}
+	body := make([]byte, 4, 4+len(prefix))
+	binary.BigEndian.PutUint32(body[0:4], lsa.Metric&0xffffff)
+	body = append(body, prefix...)
+	return serializeLsa(&lsa.header, body)
+}
+
+// InterAreaRouterLsa is an inter-area-router-LSA of OSPFv3, which replaces
+// the type 4 summary-LSA of OSPFv2.
+type InterAreaRouterLsa struct {
+	header LsaHeader
+
+	Options             Options
+	Metric              uint32
+	DestinationRouterId uint32
+}
+
+func NewInterAreaRouterLsa() (*InterAreaRouterLsa, error) {
+	lsa := InterAreaRouterLsa{
+		header: *newLsaHeader(OSPFV3_VERSION, LS_TYPE_INTER_AREA_ROUTER),
+	}
+	return &lsa, nil
+}
+
+func (lsa *InterAreaRouterLsa) LsType() LsType {
+	return lsa.header.lsType
+}
+
+func (lsa *InterAreaRouterLsa) Header() *LsaHeader {
+	return &lsa.header
+}
+
+func (lsa *InterAreaRouterLsa) String() string {
+	var b bytes.Buffer
+	b.WriteString(lsa.header.String())
+	fmt.Fprintf(&b, "Options                         %s\n", lsa.Options.Ospfv3String())
+	fmt.Fprintf(&b, "Metric                          %d\n", lsa.Metric)
+	fmt.Fprintf(&b, "DestinationRouterId             %s\n", Ipv4String(lsa.DestinationRouterId))
+	return b.String()
+}
+
+func (lsa *InterAreaRouterLsa) DecodeFromBytes(data []byte) error {
+	err := lsa.header.DecodeFromBytes(data)
+	if err != nil {
+		return err
+	}
+	if len(data) != int(lsa.header.length) || len(data) != LSA_HEADER_LENGTH+12 {
+		return errors.New("InterAreaRouterLsa.DecodeFromBytes: data length mismatch")
+	}
+	body := data[LSA_HEADER_LENGTH:]
+	lsa.Options = Options(binary.BigEndian.Uint32(body[0:4]) & 0xffffff)
+	lsa.Metric = binary.BigEndian.Uint32(body[4:8]) & 0xffffff
+	lsa.DestinationRouterId = binary.BigEndian.Uint32(body[8:12])
+	return nil
+}
+
+func (lsa *InterAreaRouterLsa) Serialize() ([]byte, error) {
+	body := make([]byte, 12)
+	binary.BigEndian.PutUint32(body[0:4], uint32(lsa.Options)&0xffffff)
+	binary.BigEndian.PutUint32(body[4:8], lsa.Metric&0xffffff)
+	binary.BigEndian.PutUint32(body[8:12], lsa.DestinationRouterId)
+	return serializeLsa(&lsa.header, body)
+}
@@ -0,0 +1,115 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package packet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// IntraAreaPrefixLsa is an intra-area-prefix-LSA of OSPFv3 (RFC 5340
+// A.4.10), which associates prefixes with the router-LSA or the
+// network-LSA it references.
+type IntraAreaPrefixLsa struct {
+	header LsaHeader
+
+	ReferencedLsType            LsType
+	ReferencedLinkStateId       uint32
+	ReferencedAdvertisingRouter uint32
+	Prefixes                    []*Ipv6Prefix
+}
+
+func NewIntraAreaPrefixLsa() (*IntraAreaPrefixLsa, error) {
+	lsa := IntraAreaPrefixLsa{
+		header:   *newLsaHeader(OSPFV3_VERSION, LS_TYPE_INTRA_AREA_PREFIX),
+		Prefixes: make([]*Ipv6Prefix, 0),
+	}
+	return &lsa, nil
+}
+
+func (lsa *IntraAreaPrefixLsa) LsType() LsType {
+	return lsa.header.lsType
+}
+
+func (lsa *IntraAreaPrefixLsa) Header() *LsaHeader {
+	return &lsa.header
+}
+
+func (lsa *IntraAreaPrefixLsa) String() string {
+	var b bytes.Buffer
+	b.WriteString(lsa.header.String())
+	fmt.Fprintf(&b, "ReferencedLsType                %s\n", lsa.ReferencedLsType.String())
+	fmt.Fprintf(&b, "ReferencedLinkStateId           %s\n", Ipv4String(lsa.ReferencedLinkStateId))
+	fmt.Fprintf(&b, "ReferencedAdvertisingRouter     %s\n", Ipv4String(lsa.ReferencedAdvertisingRouter))
+	for i, prefix := range lsa.Prefixes {
+		fmt.Fprintf(&b, "Prefixes[%d]\n", i)
+		b.WriteString(prefix.String())
+	}
+	return b.String()
+}
+
+func (lsa *IntraAreaPrefixLsa) DecodeFromBytes(data []byte) error {
+	err := lsa.header.DecodeFromBytes(data)
+	if err != nil {
+		return err
+	}
+	if len(data) != int(lsa.header.length) || len(data) < LSA_HEADER_LENGTH+12 {
+		return errors.New("IntraAreaPrefixLsa.DecodeFromBytes: data length mismatch")
+	}
+	body := data[LSA_HEADER_LENGTH:]
+	prefixes := int(binary.BigEndian.Uint16(body[0:2]))
+	lsa.ReferencedLsType = LsType(binary.BigEndian.Uint16(body[2:4]))
+	lsa.ReferencedLinkStateId = binary.BigEndian.Uint32(body[4:8])
+	lsa.ReferencedAdvertisingRouter = binary.BigEndian.Uint32(body[8:12])
+	lsa.Prefixes = make([]*Ipv6Prefix, 0)
+	i := 12
+	for n := 0; n < prefixes; n++ {
+		prefix := &Ipv6Prefix{}
+		l, err := prefix.decodeFromBytes(body[i:])
+		if err != nil {
+			return err
+		}
+		lsa.Prefixes = append(lsa.Prefixes, prefix)
+		i += l
+	}
+	if i != len(body) {
+		return errors.New("IntraAreaPrefixLsa.DecodeFromBytes: data length mismatch")
+	}
+	return nil
+}
+
+func (lsa *IntraAreaPrefixLsa) Serialize() ([]byte, error) {
+	if len(lsa.Prefixes) > 0xffff {
+		return nil, errors.New("IntraAreaPrefixLsa.Serialize: too many prefixes")
+	}
+	body := make([]byte, 12)
+	binary.BigEndian.PutUint16(body[0:2], uint16(len(lsa.Prefixes)))
+	binary.BigEndian.PutUint16(body[2:4], uint16(lsa.ReferencedLsType))
+	binary.BigEndian.PutUint32(body[4:8], lsa.ReferencedLinkStateId)
+	binary.BigEndian.PutUint32(body[8:12], lsa.ReferencedAdvertisingRouter)
+	for _, prefix := range lsa.Prefixes {
+		data, err := prefix.serialize()
+		if err != nil {
+			return nil, err
+		}
+		body = append(body, data...)
+	}
+	return serializeLsa(&lsa.header, body)
+}
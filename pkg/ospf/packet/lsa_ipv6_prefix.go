@@ -0,0 +1,87 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package packet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Ipv6Prefix is an IPv6 prefix of an OSPFv3 LSA (RFC 5340 A.4.1). The 16
+// bits following the options are the metric in an intra-area-prefix-LSA
+// and are unused elsewhere.
+type Ipv6Prefix struct {
+	PrefixLength  uint8
+	PrefixOptions PrefixOptions
+	Metric        uint16
+	Prefix        [4]uint32
+}
+
+func (prefix *Ipv6Prefix) String() string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "    Prefix                      %s/%d\n", Ipv6String(prefix.Prefix), prefix.PrefixLength)
+	fmt.Fprintf(&b, "    PrefixOptions               %s\n", prefix.PrefixOptions.String())
+	fmt.Fprintf(&b, "    Metric                      %d\n", prefix.Metric)
+	return b.String()
+}
+
+// length is the length of the encoded prefix, which is padded to 32 bits.
+func (prefix *Ipv6Prefix) length() int {
+	return 4 + 4*((int(prefix.PrefixLength)+31)/32)
+}
+
+func (prefix *Ipv6Prefix) decodeFromBytes(data []byte) (int, error) {
+	if len(data) < 4 {
+		return 0, errors.New("Ipv6Prefix.decodeFromBytes: data length too short")
+	}
+	prefix.PrefixLength = data[0]
+	prefix.PrefixOptions = PrefixOptions(data[1])
+	prefix.Metric = binary.BigEndian.Uint16(data[2:4])
+	if prefix.PrefixLength > 128 {
+		return 0, errors.New("Ipv6Prefix.decodeFromBytes: prefix length invalid")
+	}
+	length := prefix.length()
+	if len(data) < length {
+		return 0, errors.New("Ipv6Prefix.decodeFromBytes: data length too short")
+	}
+	prefix.Prefix = [4]uint32{}
+	for i := 0; 4+4*i < length; i++ {
+		prefix.Prefix[i] = binary.BigEndian.Uint32(data[4+4*i : 8+4*i])
+	}
+	return length, nil
+}
+
+func (prefix *Ipv6Prefix) serialize() ([]byte, error) {
+	if prefix.PrefixLength > 128 {
+		return nil, errors.New("Ipv6Prefix.serialize: prefix length invalid")
+	}
+	data := make([]byte, prefix.length())
+	data[0] = prefix.PrefixLength
+	data[1] = uint8(prefix.PrefixOptions)
+	binary.BigEndian.PutUint16(data[2:4], prefix.Metric)
+	for i := 0; 4+4*i < len(data); i++ {
+		word := prefix.Prefix[i]
+		if bits := int(prefix.PrefixLength) - 32*i; bits < 32 {
+			word &^= 0xffffffff >> uint(bits)
+		}
+		binary.BigEndian.PutUint32(data[4+4*i:8+4*i], word)
+	}
+	return data, nil
+}
@@ -0,0 +1,117 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package packet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// LinkLsa is a link-LSA of OSPFv3 (RFC 5340 A.4.9), which a router
+// originates for each of its links with link-local flooding scope. Its
+// link state ID is the interface ID of the router on the link.
+type LinkLsa struct {
+	header LsaHeader
+
+	RouterPriority   uint8
+	Options          Options
+	LinkLocalAddress [4]uint32
+	Prefixes         []*Ipv6Prefix
+}
+
+func NewLinkLsa() (*LinkLsa, error) {
+	lsa := LinkLsa{
+		header:   *newLsaHeader(OSPFV3_VERSION, LS_TYPE_LINK),
+		Prefixes: make([]*Ipv6Prefix, 0),
+	}
+	return &lsa, nil
+}
+
+func (lsa *LinkLsa) LsType() LsType {
+	return lsa.header.lsType
+}
+
+func (lsa *LinkLsa) Header() *LsaHeader {
+	return &lsa.header
+}
+
+func (lsa *LinkLsa) String() string {
+	var b bytes.Buffer
+	b.WriteString(lsa.header.String())
+	fmt.Fprintf(&b, "RouterPriority                  %d\n", lsa.RouterPriority)
+	fmt.Fprintf(&b, "Options                         %s\n", lsa.Options.Ospfv3String())
+	fmt.Fprintf(&b, "LinkLocalAddress                %s\n", Ipv6String(lsa.LinkLocalAddress))
+	for i, prefix := range lsa.Prefixes {
+		fmt.Fprintf(&b, "Prefixes[%d]\n", i)
+		b.WriteString(prefix.String())
+	}
+	return b.String()
+}
+
+func (lsa *LinkLsa) DecodeFromBytes(data []byte) error {
+	err := lsa.header.DecodeFromBytes(data)
+	if err != nil {
+		return err
+	}
+	if len(data) != int(lsa.header.length) || len(data) < LSA_HEADER_LENGTH+24 {
+		return errors.New("LinkLsa.DecodeFromBytes: data length mismatch")
+	}
+	body := data[LSA_HEADER_LENGTH:]
+	lsa.RouterPriority = body[0]
+	lsa.Options = Options(binary.BigEndian.Uint32(body[0:4]) & 0xffffff)
+	for i := 0; i < 4; i++ {
+		lsa.LinkLocalAddress[i] = binary.BigEndian.Uint32(body[4+4*i : 8+4*i])
+	}
+	prefixes := int(binary.BigEndian.Uint32(body[20:24]))
+	lsa.Prefixes = make([]*Ipv6Prefix, 0)
+	i := 24
+	for n := 0; n < prefixes; n++ {
+		prefix := &Ipv6Prefix{}
+		l, err := prefix.decodeFromBytes(body[i:])
+		if err != nil {
+			return err
+		}
+		lsa.Prefixes = append(lsa.Prefixes, prefix)
+		i += l
+	}
+	if i != len(body) {
+		return errors.New("LinkLsa.DecodeFromBytes: data length mismatch")
+	}
+	return nil
+}
+
+func (lsa *LinkLsa) Serialize() ([]byte, error) {
+	body := make([]byte, 24)
+	binary.BigEndian.PutUint32(body[0:4], uint32(lsa.Options)&0xffffff)
+	body[0] = lsa.RouterPriority
+	for i, a := range lsa.LinkLocalAddress {
+		binary.BigEndian.PutUint32(body[4+4*i:8+4*i], a)
+	}
+	binary.BigEndian.PutUint32(body[20:24], uint32(len(lsa.Prefixes)))
+	for _, prefix := range lsa.Prefixes {
+		data, err := prefix.serialize()
+		if err != nil {
+			return nil, err
+		}
+		data[2], data[3] = 0, 0
+		body = append(body, data...)
+	}
+	return serializeLsa(&lsa.header, body)
+}
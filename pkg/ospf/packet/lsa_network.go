@@ -0,0 +1,85 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package packet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+type NetworkLsa struct {
+	header LsaHeader
+
+	NetworkMask     uint32
+	AttachedRouters []uint32
+}
+
+func NewNetworkLsa() (*NetworkLsa, error) {
+	lsa := NetworkLsa{
+		header:          *NewLsaHeader(LS_TYPE_NETWORK),
+		AttachedRouters: make([]uint32, 0),
+	}
+	return &lsa, nil
+}
+
+func (lsa *NetworkLsa) LsType() LsType {
+	return lsa.header.lsType
+}
+
+func (lsa *NetworkLsa) Header() *LsaHeader {
+	return &lsa.header
+}
+
+func (lsa *NetworkLsa) String() string {
+	var b bytes.Buffer
+	b.WriteString(lsa.header.String())
+	fmt.Fprintf(&b, "NetworkMask                     %s\n", Ipv4String(lsa.NetworkMask))
+	for i, router := range lsa.AttachedRouters {
+		fmt.Fprintf(&b, "AttachedRouters[%d]              %s\n", i, Ipv4String(router))
+	}
+	return b.String()
+}
+
+func (lsa *NetworkLsa) DecodeFromBytes(data []byte) error {
+	err := lsa.header.DecodeFromBytes(data)
+	if err != nil {
+		return err
+	}
+	if len(data) != int(lsa.header.length) || len(data) < LSA_HEADER_LENGTH+4 ||
+		len(data)%4 != 0 {
+		return errors.New("NetworkLsa.DecodeFromBytes: data length mismatch")
+	}
+	body := data[LSA_HEADER_LENGTH:]
+	lsa.NetworkMask = binary.BigEndian.Uint32(body[0:4])
+	lsa.AttachedRouters = make([]uint32, 0)
+	for i := 4; i < len(body); i += 4 {
+		lsa.AttachedRouters = append(lsa.AttachedRouters, binary.BigEndian.Uint32(body[i:i+4]))
+	}
+	return nil
+}
+
+func (lsa *NetworkLsa) Serialize() ([]byte, error) {
+	body := make([]byte, 4+4*len(lsa.AttachedRouters))
+	binary.BigEndian.PutUint32(body[0:4], lsa.NetworkMask)
+	for i, router := range lsa.AttachedRouters {
+		binary.BigEndian.PutUint32(body[4+4*i:8+4*i], router)
+	}
+	return serializeLsa(&lsa.header, body)
+}
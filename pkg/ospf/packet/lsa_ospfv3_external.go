@@ -0,0 +1,162 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package packet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Ospfv3ExternalLsa is an LSA of OSPFv3 of either AS-external or
+// NSSA (RFC 3101) type, which share the format (RFC 5340 A.4.7). The
+// forwarding address, the tag and the referenced link state ID are
+// optional and present if ForwardingAddressPresent, ExternalRouteTag not
+// zero and ReferencedLsType not zero respectively.
+type Ospfv3ExternalLsa struct {
+	header LsaHeader
+
+	ExternalMetric           bool
+	Metric                   uint32
+	Prefix                   Ipv6Prefix
+	ForwardingAddressPresent bool
+	ForwardingAddress        [4]uint32
+	ExternalRouteTag         uint32
+	ReferencedLsType         LsType
+	ReferencedLinkStateId    uint32
+}
+
+func NewOspfv3ExternalLsa(lsType LsType) (*Ospfv3ExternalLsa, error) {
+	if lsType != LS_TYPE_OSPFV3_AS_EXTERNAL &&
+		lsType != LS_TYPE_OSPFV3_NSSA_EXTERNAL {
+		return nil, errors.New("NewOspfv3ExternalLsa: lsType invalid")
+	}
+	lsa := Ospfv3ExternalLsa{
+		header: *newLsaHeader(OSPFV3_VERSION, lsType),
+	}
+	return &lsa, nil
+}
+
+func (lsa *Ospfv3ExternalLsa) LsType() LsType {
+	return lsa.header.lsType
+}
+
+func (lsa *Ospfv3ExternalLsa) Header() *LsaHeader {
+	return &lsa.header
+}
+
+func (lsa *Ospfv3ExternalLsa) String() string {
+	var b bytes.Buffer
+	b.WriteString(lsa.header.String())
+	fmt.Fprintf(&b, "ExternalMetric                  %t\n", lsa.ExternalMetric)
+	fmt.Fprintf(&b, "Metric                          %d\n", lsa.Metric)
+	fmt.Fprintf(&b, "Prefix\n")
+	b.WriteString(lsa.Prefix.String())
+	if lsa.ForwardingAddressPresent {
+		fmt.Fprintf(&b, "ForwardingAddress               %s\n", Ipv6String(lsa.ForwardingAddress))
+	}
+	fmt.Fprintf(&b, "ExternalRouteTag                0x%08x\n", lsa.ExternalRouteTag)
+	if lsa.ReferencedLsType != 0 {
+		fmt.Fprintf(&b, "ReferencedLsType                %s\n", lsa.ReferencedLsType.String())
+		fmt.Fprintf(&b, "ReferencedLinkStateId           %s\n", Ipv4String(lsa.ReferencedLinkStateId))
+	}
+	return b.String()
+}
+
+func (lsa *Ospfv3ExternalLsa) DecodeFromBytes(data []byte) error {
+	err := lsa.header.DecodeFromBytes(data)
+	if err != nil {
+		return err
+	}
+	if len(data) != int(lsa.header.length) || len(data) < LSA_HEADER_LENGTH+8 {
+		return errors.New("Ospfv3ExternalLsa.DecodeFromBytes: data length mismatch")
+	}
+	body := data[LSA_HEADER_LENGTH:]
+	flags := body[0]
+	lsa.ExternalMetric = flags&0x04 == 0x04
+	lsa.Metric = binary.BigEndian.Uint32(body[0:4]) & 0xffffff
+	i, err := lsa.Prefix.decodeFromBytes(body[4:])
+	if err != nil {
+		return err
+	}
+	lsa.ReferencedLsType = LsType(lsa.Prefix.Metric)
+	lsa.Prefix.Metric = 0
+	i += 4
+	lsa.ForwardingAddressPresent = flags&0x02 == 0x02
+	lsa.ForwardingAddress = [4]uint32{}
+	if lsa.ForwardingAddressPresent {
+		if len(body) < i+16 {
+			return errors.New("Ospfv3ExternalLsa.DecodeFromBytes: data length too short")
+		}
+		for n := 0; n < 4; n++ {
+			lsa.ForwardingAddress[n] = binary.BigEndian.Uint32(body[i : i+4])
+			i += 4
+		}
+	}
+	lsa.ExternalRouteTag = 0
+	if flags&0x01 == 0x01 {
+		if len(body) < i+4 {
+			return errors.New("Ospfv3ExternalLsa.DecodeFromBytes: data length too short")
+		}
+		lsa.ExternalRouteTag = binary.BigEndian.Uint32(body[i : i+4])
+		i += 4
+	}
+	lsa.ReferencedLinkStateId = 0
+	if lsa.ReferencedLsType != 0 {
+		if len(body) < i+4 {
+			return errors.New("Ospfv3ExternalLsa.DecodeFromBytes: data length too short")
+		}
+		lsa.ReferencedLinkStateId = binary.BigEndian.Uint32(body[i : i+4])
+		i += 4
+	}
+	if i != len(body) {
+		return errors.New("Ospfv3ExternalLsa.DecodeFromBytes: data length mismatch")
+	}
+	return nil
+}
+
+func (lsa *Ospfv3ExternalLsa) Serialize() ([]byte, error) {
+	prefix, err := lsa.Prefix.serialize()
+	if err != nil {
+		return nil, err
+	}
+	binary.BigEndian.PutUint16(prefix[2:4], uint16(lsa.ReferencedLsType))
+	body := make([]byte, 4, 4+len(prefix)+24)
+	binary.BigEndian.PutUint32(body[0:4], lsa.Metric&0xffffff)
+	if lsa.ExternalMetric {
+		body[0] |= 0x04
+	}
+	body = append(body, prefix...)
+	if lsa.ForwardingAddressPresent {
+		body[0] |= 0x02
+		for _, a := range lsa.ForwardingAddress {
+			body = append(body, byte(a>>24), byte(a>>16), byte(a>>8), byte(a))
+		}
+	}
+	if lsa.ExternalRouteTag != 0 {
+		body[0] |= 0x01
+		body = append(body, byte(lsa.ExternalRouteTag>>24), byte(lsa.ExternalRouteTag>>16),
+			byte(lsa.ExternalRouteTag>>8), byte(lsa.ExternalRouteTag))
+	}
+	if lsa.ReferencedLsType != 0 {
+		body = append(body, byte(lsa.ReferencedLinkStateId>>24), byte(lsa.ReferencedLinkStateId>>16),
+			byte(lsa.ReferencedLinkStateId>>8), byte(lsa.ReferencedLinkStateId))
+	}
+	return serializeLsa(&lsa.header, body)
+}
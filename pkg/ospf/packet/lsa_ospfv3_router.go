@@ -0,0 +1,143 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package packet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+type Ospfv3RouterLink struct {
+	Type                LinkType
+	Metric              uint16
+	InterfaceId         uint32
+	NeighborInterfaceId uint32
+	NeighborRouterId    uint32
+}
+
+func (link *Ospfv3RouterLink) String() string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "    Type                        %s\n", link.Type.String())
+	fmt.Fprintf(&b, "    Metric                      %d\n", link.Metric)
+	fmt.Fprintf(&b, "    InterfaceId                 %d\n", link.InterfaceId)
+	fmt.Fprintf(&b, "    NeighborInterfaceId         %d\n", link.NeighborInterfaceId)
+	fmt.Fprintf(&b, "    NeighborRouterId            %s\n", Ipv4String(link.NeighborRouterId))
+	return b.String()
+}
+
+// Ospfv3RouterLsa is a router-LSA of OSPFv3 (RFC 5340 A.4.3), which
+// describes the links of a router with no addresses. A router may
+// originate more than one of them, which together describe the router.
+type Ospfv3RouterLsa struct {
+	header LsaHeader
+
+	NssaTranslator bool
+	VirtualLink    bool
+	External       bool
+	Border         bool
+	Options        Options
+	Links          []*Ospfv3RouterLink
+}
+
+func NewOspfv3RouterLsa() (*Ospfv3RouterLsa, error) {
+	lsa := Ospfv3RouterLsa{
+		header: *newLsaHeader(OSPFV3_VERSION, LS_TYPE_OSPFV3_ROUTER),
+		Links:  make([]*Ospfv3RouterLink, 0),
+	}
+	return &lsa, nil
+}
+
+func (lsa *Ospfv3RouterLsa) LsType() LsType {
+	return lsa.header.lsType
+}
+
+func (lsa *Ospfv3RouterLsa) Header() *LsaHeader {
+	return &lsa.header
+}
+
+func (lsa *Ospfv3RouterLsa) String() string {
+	var b bytes.Buffer
+	b.WriteString(lsa.header.String())
+	fmt.Fprintf(&b, "NssaTranslator                  %t\n", lsa.NssaTranslator)
+	fmt.Fprintf(&b, "VirtualLink                     %t\n", lsa.VirtualLink)
+	fmt.Fprintf(&b, "External                        %t\n", lsa.External)
+	fmt.Fprintf(&b, "Border                          %t\n", lsa.Border)
+	fmt.Fprintf(&b, "Options                         %s\n", lsa.Options.Ospfv3String())
+	for i, link := range lsa.Links {
+		fmt.Fprintf(&b, "Links[%d]\n", i)
+		b.WriteString(link.String())
+	}
+	return b.String()
+}
+
+func (lsa *Ospfv3RouterLsa) DecodeFromBytes(data []byte) error {
+	err := lsa.header.DecodeFromBytes(data)
+	if err != nil {
+		return err
+	}
+	if len(data) != int(lsa.header.length) || len(data) < LSA_HEADER_LENGTH+4 ||
+		(len(data)-LSA_HEADER_LENGTH-4)%16 != 0 {
+		return errors.New("Ospfv3RouterLsa.DecodeFromBytes: data length mismatch")
+	}
+	body := data[LSA_HEADER_LENGTH:]
+	lsa.NssaTranslator = body[0]&0x10 == 0x10
+	lsa.VirtualLink = body[0]&0x04 == 0x04
+	lsa.External = body[0]&0x02 == 0x02
+	lsa.Border = body[0]&0x01 == 0x01
+	lsa.Options = Options(binary.BigEndian.Uint32(body[0:4]) & 0xffffff)
+	lsa.Links = make([]*Ospfv3RouterLink, 0)
+	for i := 4; i < len(body); i += 16 {
+		lsa.Links = append(lsa.Links, &Ospfv3RouterLink{
+			Type:                LinkType(body[i]),
+			Metric:              binary.BigEndian.Uint16(body[i+2 : i+4]),
+			InterfaceId:         binary.BigEndian.Uint32(body[i+4 : i+8]),
+			NeighborInterfaceId: binary.BigEndian.Uint32(body[i+8 : i+12]),
+			NeighborRouterId:    binary.BigEndian.Uint32(body[i+12 : i+16]),
+		})
+	}
+	return nil
+}
+
+func (lsa *Ospfv3RouterLsa) Serialize() ([]byte, error) {
+	body := make([]byte, 4+16*len(lsa.Links))
+	binary.BigEndian.PutUint32(body[0:4], uint32(lsa.Options)&0xffffff)
+	if lsa.NssaTranslator {
+		body[0] |= 0x10
+	}
+	if lsa.VirtualLink {
+		body[0] |= 0x04
+	}
+	if lsa.External {
+		body[0] |= 0x02
+	}
+	if lsa.Border {
+		body[0] |= 0x01
+	}
+	i := 4
+	for _, link := range lsa.Links {
+		body[i] = uint8(link.Type)
+		binary.BigEndian.PutUint16(body[i+2:i+4], link.Metric)
+		binary.BigEndian.PutUint32(body[i+4:i+8], link.InterfaceId)
+		binary.BigEndian.PutUint32(body[i+8:i+12], link.NeighborInterfaceId)
+		binary.BigEndian.PutUint32(body[i+12:i+16], link.NeighborRouterId)
+		i += 16
+	}
+	return serializeLsa(&lsa.header, body)
+}
@@ -0,0 +1,130 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package packet
+
+import (
+	"bytes"
+	"testing"
+)
+
+func testOspfv3LsaRoundTrip(t *testing.T, l1 OspfLsa) OspfLsa {
+	l1.Header().AdvertisingRouter = 0x01010101
+	if err := SetLsaChecksum(l1); err != nil {
+		t.Fatalf("failed SetLsaChecksum: %#v", err)
+	}
+	d1, err := l1.Serialize()
+	if err != nil {
+		t.Fatalf("failed Serialize: %#v", err)
+	}
+	l2, err := DecodeOspfv3LsaFromBytes(d1)
+	if err != nil {
+		t.Fatalf("failed DecodeOspfv3LsaFromBytes: %#v", err)
+	}
+	if l2.LsType() != l1.LsType() || !LsaChecksumValid(l2) {
+		t.Fatalf("failed DecodeOspfv3LsaFromBytes:\n%s", l2.String())
+	}
+	d2, err := l2.Serialize()
+	if err != nil {
+		t.Fatalf("failed Serialize: %#v", err)
+	}
+	if !bytes.Equal(d1, d2) {
+		t.Fatalf("failed !bytes.Equal:\n%s", l2.String())
+	}
+	return l2
+}
+
+func TestOspfv3RouterLsa(t *testing.T) {
+	l1, _ := NewOspfv3RouterLsa()
+	l1.Border = true
+	l1.Options = OPTIONS_V6 | OPTIONS_E | OPTIONS_R
+	l1.Links = append(l1.Links, &Ospfv3RouterLink{
+		Type:                LINK_TYPE_TRANSIT,
+		Metric:              10,
+		InterfaceId:         3,
+		NeighborInterfaceId: 5,
+		NeighborRouterId:    0x02020202,
+	})
+	l2 := testOspfv3LsaRoundTrip(t, l1).(*Ospfv3RouterLsa)
+	if !l2.Border || l2.External || l2.Options != l1.Options || len(l2.Links) != 1 ||
+		*l2.Links[0] != *l1.Links[0] {
+		t.Fatalf("failed Ospfv3RouterLsa:\n%s", l2.String())
+	}
+}
+
+func TestLinkLsa(t *testing.T) {
+	l1, _ := NewLinkLsa()
+	l1.Header().LinkStateId = 3
+	l1.RouterPriority = 1
+	l1.Options = OPTIONS_V6 | OPTIONS_E | OPTIONS_R
+	l1.LinkLocalAddress = [4]uint32{0xfe800000, 0, 0, 1}
+	l1.Prefixes = append(l1.Prefixes, &Ipv6Prefix{
+		PrefixLength: 64,
+		Prefix:       [4]uint32{0x20010db8, 0x00000001, 0, 0},
+	})
+	l2 := testOspfv3LsaRoundTrip(t, l1).(*LinkLsa)
+	if l2.Header().Length() != LSA_HEADER_LENGTH+24+12 || l2.RouterPriority != 1 ||
+		l2.LinkLocalAddress != l1.LinkLocalAddress || len(l2.Prefixes) != 1 ||
+		*l2.Prefixes[0] != *l1.Prefixes[0] {
+		t.Fatalf("failed LinkLsa:\n%s", l2.String())
+	}
+}
+
+func TestIntraAreaPrefixLsa(t *testing.T) {
+	l1, _ := NewIntraAreaPrefixLsa()
+	l1.ReferencedLsType = LS_TYPE_OSPFV3_ROUTER
+	l1.ReferencedAdvertisingRouter = 0x01010101
+	l1.Prefixes = append(l1.Prefixes, &Ipv6Prefix{
+		PrefixLength:  128,
+		PrefixOptions: PREFIX_OPTIONS_LA,
+		Prefix:        [4]uint32{0x20010db8, 0, 0, 1},
+	}, &Ipv6Prefix{
+		PrefixLength: 48,
+		Metric:       10,
+		Prefix:       [4]uint32{0x20010db8, 0x00020000, 0, 0},
+	})
+	l2 := testOspfv3LsaRoundTrip(t, l1).(*IntraAreaPrefixLsa)
+	if l2.ReferencedLsType != LS_TYPE_OSPFV3_ROUTER || len(l2.Prefixes) != 2 ||
+		*l2.Prefixes[0] != *l1.Prefixes[0] || *l2.Prefixes[1] != *l1.Prefixes[1] {
+		t.Fatalf("failed IntraAreaPrefixLsa:\n%s", l2.String())
+	}
+}
+
+func TestOspfv3ExternalLsa(t *testing.T) {
+	l1, _ := NewOspfv3ExternalLsa(LS_TYPE_OSPFV3_AS_EXTERNAL)
+	l1.ExternalMetric = true
+	l1.Metric = 20
+	l1.Prefix = Ipv6Prefix{
+		PrefixLength: 32,
+		Prefix:       [4]uint32{0x20010db8, 0, 0, 0},
+	}
+	l1.ExternalRouteTag = 100
+	l2 := testOspfv3LsaRoundTrip(t, l1).(*Ospfv3ExternalLsa)
+	if !l2.ExternalMetric || l2.Metric != 20 || l2.Prefix != l1.Prefix ||
+		l2.ForwardingAddressPresent || l2.ExternalRouteTag != 100 {
+		t.Fatalf("failed Ospfv3ExternalLsa:\n%s", l2.String())
+	}
+}
+
+func TestOspfv3LsaUnknown(t *testing.T) {
+	l1, _ := newUnknownLsa(OSPFV3_VERSION, 0xc00a)
+	l1.data = []byte{1, 2, 3, 4}
+	l2 := testOspfv3LsaRoundTrip(t, l1)
+	if l2.LsType().Scope() != FLOODING_SCOPE_AS || !l2.LsType().UBit() {
+		t.Fatalf("failed UnknownLsa:\n%s", l2.String())
+	}
+}
@@ -0,0 +1,184 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package packet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+type TosMetric struct {
+	Tos    uint8
+	Metric uint32
+}
+
+type RouterLink struct {
+	LinkId   uint32
+	LinkData uint32
+	Type     LinkType
+	Metric   uint16
+	Tos      []*TosMetric
+}
+
+func (link *RouterLink) String() string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "    LinkId                      %s\n", Ipv4String(link.LinkId))
+	fmt.Fprintf(&b, "    LinkData                    %s\n", Ipv4String(link.LinkData))
+	fmt.Fprintf(&b, "    Type                        %s\n", link.Type.String())
+	fmt.Fprintf(&b, "    Metric                      %d\n", link.Metric)
+	for _, tos := range link.Tos {
+		fmt.Fprintf(&b, "    Tos %-3d                     %d\n", tos.Tos, tos.Metric)
+	}
+	return b.String()
+}
+
+type RouterLsa struct {
+	header LsaHeader
+
+	NssaTranslator bool
+	VirtualLink    bool
+	External       bool
+	Border         bool
+	Links          []*RouterLink
+}
+
+func NewRouterLsa() (*RouterLsa, error) {
+	lsa := RouterLsa{
+		header: *NewLsaHeader(LS_TYPE_ROUTER),
+		Links:  make([]*RouterLink, 0),
+	}
+	return &lsa, nil
+}
+
+func (lsa *RouterLsa) LsType() LsType {
+	return lsa.header.lsType
+}
+
+func (lsa *RouterLsa) Header() *LsaHeader {
+	return &lsa.header
+}
+
+func (lsa *RouterLsa) String() string {
+	var b bytes.Buffer
+	b.WriteString(lsa.header.String())
+	fmt.Fprintf(&b, "NssaTranslator                  %t\n", lsa.NssaTranslator)
+	fmt.Fprintf(&b, "VirtualLink                     %t\n", lsa.VirtualLink)
+	fmt.Fprintf(&b, "External                        %t\n", lsa.External)
+	fmt.Fprintf(&b, "Border                          %t\n", lsa.Border)
+	for i, link := range lsa.Links {
+		fmt.Fprintf(&b, "Links[%d]\n", i)
+		b.WriteString(link.String())
+	}
+	return b.String()
+}
+
+func (lsa *RouterLsa) DecodeFromBytes(data []byte) error {
+	err := lsa.header.DecodeFromBytes(data)
+	if err != nil {
+		return err
+	}
+	if len(data) != int(lsa.header.length) || len(data) < LSA_HEADER_LENGTH+4 {
+		return errors.New("RouterLsa.DecodeFromBytes: data length mismatch")
+	}
+	body := data[LSA_HEADER_LENGTH:]
+	lsa.NssaTranslator = body[0]&0x10 == 0x10
+	lsa.VirtualLink = body[0]&0x04 == 0x04
+	lsa.External = body[0]&0x02 == 0x02
+	lsa.Border = body[0]&0x01 == 0x01
+	links := int(binary.BigEndian.Uint16(body[2:4]))
+	lsa.Links = make([]*RouterLink, 0, links)
+	i := 4
+	for n := 0; n < links; n++ {
+		if len(body) < i+12 {
+			return errors.New("RouterLsa.DecodeFromBytes: data length too short")
+		}
+		link := &RouterLink{
+			LinkId:   binary.BigEndian.Uint32(body[i+0 : i+4]),
+			LinkData: binary.BigEndian.Uint32(body[i+4 : i+8]),
+			Type:     LinkType(body[i+8]),
+			Metric:   binary.BigEndian.Uint16(body[i+10 : i+12]),
+			Tos:      make([]*TosMetric, 0),
+		}
+		tos := int(body[i+9])
+		i += 12
+		if len(body) < i+4*tos {
+			return errors.New("RouterLsa.DecodeFromBytes: data length too short")
+		}
+		for t := 0; t < tos; t++ {
+			link.Tos = append(link.Tos, &TosMetric{
+				Tos:    body[i],
+				Metric: uint32(binary.BigEndian.Uint16(body[i+2 : i+4])),
+			})
+			i += 4
+		}
+		lsa.Links = append(lsa.Links, link)
+	}
+	if i != len(body) {
+		return errors.New("RouterLsa.DecodeFromBytes: data length mismatch")
+	}
+	return nil
+}
+
+func (lsa *RouterLsa) Serialize() ([]byte, error) {
+	length := 4
+	for _, link := range lsa.Links {
+		if len(link.Tos) > 0xff {
+			return nil, errors.New("RouterLsa.Serialize: too many TOS")
+		}
+		length += 12 + 4*len(link.Tos)
+	}
+	if len(lsa.Links) > 0xffff {
+		return nil, errors.New("RouterLsa.Serialize: too many links")
+	}
+	body := make([]byte, length)
+	if lsa.NssaTranslator {
+		body[0] |= 0x10
+	}
+	if lsa.VirtualLink {
+		body[0] |= 0x04
+	}
+	if lsa.External {
+		body[0] |= 0x02
+	}
+	if lsa.Border {
+		body[0] |= 0x01
+	}
+	binary.BigEndian.PutUint16(body[2:4], uint16(len(lsa.Links)))
+	i := 4
+	for _, link := range lsa.Links {
+		binary.BigEndian.PutUint32(body[i+0:i+4], link.LinkId)
+		binary.BigEndian.PutUint32(body[i+4:i+8], link.LinkData)
+		body[i+8] = uint8(link.Type)
+		body[i+9] = uint8(len(link.Tos))
+		binary.BigEndian.PutUint16(body[i+10:i+12], link.Metric)
+		i += 12
+		for _, tos := range link.Tos {
+			body[i] = tos.Tos
+			binary.BigEndian.PutUint16(body[i+2:i+4], uint16(tos.Metric))
+			i += 4
+		}
+	}
+	return serializeLsa(&lsa.header, body)
+}
+
+func (lsa *RouterLsa) AddLink(link *RouterLink) error {
+	lsa.Links = append(lsa.Links, link)
+	return nil
+}
@@ -0,0 +1,98 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package packet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// SummaryLsa is a summary-LSA of either type 3, for a network, or type 4,
+// for an AS boundary router.
+type SummaryLsa struct {
+	header LsaHeader
+
+	NetworkMask uint32
+	Metric      uint32
+	Tos         []*TosMetric
+}
+
+func NewSummaryLsa(lsType LsType) (*SummaryLsa, error) {
+	if lsType != LS_TYPE_SUMMARY_NETWORK &&
+		lsType != LS_TYPE_SUMMARY_ASBR {
+		return nil, errors.New("NewSummaryLsa: lsType invalid")
+	}
+	lsa := SummaryLsa{
+		header: *NewLsaHeader(lsType),
+		Tos:    make([]*TosMetric, 0),
+	}
+	return &lsa, nil
+}
+
+func (lsa *SummaryLsa) LsType() LsType {
+	return lsa.header.lsType
+}
+
+func (lsa *SummaryLsa) Header() *LsaHeader {
+	return &lsa.header
+}
+
+func (lsa *SummaryLsa) String() string {
+	var b bytes.Buffer
+	b.WriteString(lsa.header.String())
+	fmt.Fprintf(&b, "NetworkMask                     %s\n", Ipv4String(lsa.NetworkMask))
+	fmt.Fprintf(&b, "Metric                          %d\n", lsa.Metric)
+	for _, tos := range lsa.Tos {
+		fmt.Fprintf(&b, "Tos %-3d                         %d\n", tos.Tos, tos.Metric)
+	}
+	return b.String()
+}
+
+func (lsa *SummaryLsa) DecodeFromBytes(data []byte) error {
+	err := lsa.header.DecodeFromBytes(data)
+	if err != nil {
+		return err
+	}
+	if len(data) != int(lsa.header.length) || len(data) < LSA_HEADER_LENGTH+8 ||
+		len(data)%4 != 0 {
+		return errors.New("SummaryLsa.DecodeFromBytes: data length mismatch")
+	}
+	body := data[LSA_HEADER_LENGTH:]
+	lsa.NetworkMask = binary.BigEndian.Uint32(body[0:4])
+	lsa.Metric = binary.BigEndian.Uint32(body[4:8]) & 0xffffff
+	lsa.Tos = make([]*TosMetric, 0)
+	for i := 8; i < len(body); i += 4 {
+		lsa.Tos = append(lsa.Tos, &TosMetric{
+			Tos:    body[i],
+			Metric: binary.BigEndian.Uint32(body[i:i+4]) & 0xffffff,
+		})
+	}
+	return nil
+}
+
+func (lsa *SummaryLsa) Serialize() ([]byte, error) {
+	body := make([]byte, 8+4*len(lsa.Tos))
+	binary.BigEndian.PutUint32(body[0:4], lsa.NetworkMask)
+	binary.BigEndian.PutUint32(body[4:8], lsa.Metric&0xffffff)
+	for i, tos := range lsa.Tos {
+		binary.BigEndian.PutUint32(body[8+4*i:12+4*i], uint32(tos.Tos)<<24|tos.Metric&0xffffff)
+	}
+	return serializeLsa(&lsa.header, body)
+}
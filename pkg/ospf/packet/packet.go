@@ -17,4 +17,402 @@
 
 package packet
 
-import ()
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+)
+
+const (
+	ALL_SPF_ROUTERS             = 0xe0000005
+	ALL_D_ROUTERS               = 0xe0000006
+	IP_PROTOCOL_OSPF            = 89
+	OSPF_VERSION                = 2
+	OSPFV3_VERSION              = 3
+	PACKET_HEADER_LENGTH        = 24
+	OSPFV3_PACKET_HEADER_LENGTH = 16
+	LSA_HEADER_LENGTH           = 20
+	LS_REQUEST_LENGTH           = 12
+	AUTHENTICATION_LENGTH       = 8
+)
+
+// ALL_SPF_ROUTERS_IPV6 and ALL_D_ROUTERS_IPV6 are the groups of OSPFv3,
+// ff02::5 and ff02::6.
+var (
+	ALL_SPF_ROUTERS_IPV6 = [4]uint32{0xff020000, 0, 0, 5}
+	ALL_D_ROUTERS_IPV6   = [4]uint32{0xff020000, 0, 0, 6}
+)
+
+const (
+	MAX_AGE                 = 3600
+	MAX_AGE_DIFF            = 900
+	LS_INFINITY             = 0xffffff
+	INITIAL_SEQUENCE_NUMBER = int32(-0x7fffffff)
+	MAX_SEQUENCE_NUMBER     = int32(0x7fffffff)
+)
+
+// OspfPacket is a packet of either OSPFv2 or OSPFv3 (RFC 5340). The
+// packets are created for OSPFv2 and SetVersion switches them to OSPFv3,
+// whose header has an instance ID in place of the authentication.
+type OspfPacket interface {
+	PacketType() PacketType
+	String() string
+	DecodeFromBytes(data []byte) error
+	Serialize() ([]byte, error)
+	BaseValid() bool
+	Version() uint8
+	SetVersion(version uint8) error
+	InstanceId() uint8
+	SetInstanceId(instanceId uint8) error
+	RouterId() uint32
+	SetRouterId(routerId uint32) error
+	AreaId() uint32
+	SetAreaId(areaId uint32) error
+	Authentication() (AuType, [AUTHENTICATION_LENGTH]byte)
+	SetAuthentication(auType AuType, authentication [AUTHENTICATION_LENGTH]byte) error
+}
+
+type OspfLsa interface {
+	LsType() LsType
+	Header() *LsaHeader
+	String() string
+	DecodeFromBytes(data []byte) error
+	Serialize() ([]byte, error)
+}
+
+type PacketType uint8
+
+const (
+	_                                PacketType = iota
+	PACKET_TYPE_HELLO                           = 0x01
+	PACKET_TYPE_DATABASE_DESCRIPTION            = 0x02
+	PACKET_TYPE_LINK_STATE_REQUEST              = 0x03
+	PACKET_TYPE_LINK_STATE_UPDATE               = 0x04
+	PACKET_TYPE_LINK_STATE_ACK                  = 0x05
+)
+
+func (packetType PacketType) String() string {
+	switch packetType {
+	case PACKET_TYPE_HELLO:
+		return "PACKET_TYPE_HELLO"
+	case PACKET_TYPE_DATABASE_DESCRIPTION:
+		return "PACKET_TYPE_DATABASE_DESCRIPTION"
+	case PACKET_TYPE_LINK_STATE_REQUEST:
+		return "PACKET_TYPE_LINK_STATE_REQUEST"
+	case PACKET_TYPE_LINK_STATE_UPDATE:
+		return "PACKET_TYPE_LINK_STATE_UPDATE"
+	case PACKET_TYPE_LINK_STATE_ACK:
+		return "PACKET_TYPE_LINK_STATE_ACK"
+	}
+	return fmt.Sprintf("PacketType(%d)", packetType)
+}
+
+type AuType uint16
+
+const (
+	_                     AuType = iota
+	AU_TYPE_NULL                 = 0x0000
+	AU_TYPE_SIMPLE               = 0x0001
+	AU_TYPE_CRYPTOGRAPHIC        = 0x0002
+)
+
+func (auType AuType) String() string {
+	switch auType {
+	case AU_TYPE_NULL:
+		return "AU_TYPE_NULL"
+	case AU_TYPE_SIMPLE:
+		return "AU_TYPE_SIMPLE"
+	case AU_TYPE_CRYPTOGRAPHIC:
+		return "AU_TYPE_CRYPTOGRAPHIC"
+	}
+	return fmt.Sprintf("AuType(%d)", auType)
+}
+
+// Options are the 8 bits of the options of OSPFv2 or the 24 bits of
+// those of OSPFv3, which share E, N and DC.
+type Options uint32
+
+const (
+	OPTIONS_MT = 0x01
+	OPTIONS_E  = 0x02
+	OPTIONS_MC = 0x04
+	OPTIONS_NP = 0x08
+	OPTIONS_EA = 0x10
+	OPTIONS_DC = 0x20
+	OPTIONS_O  = 0x40
+	OPTIONS_DN = 0x80
+)
+
+const (
+	OPTIONS_V6 = 0x0001
+	OPTIONS_R  = 0x0010
+	OPTIONS_AF = 0x0100
+	OPTIONS_L  = 0x0200
+	OPTIONS_AT = 0x0400
+)
+
+func (options Options) String() string {
+	return options.string([]string{"MT", "E", "MC", "NP", "EA", "DC", "O", "DN"})
+}
+
+// Ospfv3String is String with the names of the options of OSPFv3.
+func (options Options) Ospfv3String() string {
+	return options.string([]string{"V6", "E", "MC", "N", "R", "DC", "*", "*", "AF", "L", "AT"})
+}
+
+func (options Options) string(names []string) string {
+	s := ""
+	for i := len(names) - 1; i >= 0; i-- {
+		if options&(1<<uint(i)) != 0 {
+			s += names[i]
+		} else {
+			s += "-"
+		}
+	}
+	return fmt.Sprintf("%s(0x%02x)", s, uint32(options))
+}
+
+// optionsString is the string of options of the version.
+func optionsString(version uint8, options Options) string {
+	if version == OSPFV3_VERSION {
+		return options.Ospfv3String()
+	}
+	return options.String()
+}
+
+// PrefixOptions are the options of an IPv6 prefix of OSPFv3.
+type PrefixOptions uint8
+
+const (
+	PREFIX_OPTIONS_NU = 0x01
+	PREFIX_OPTIONS_LA = 0x02
+	PREFIX_OPTIONS_P  = 0x08
+	PREFIX_OPTIONS_DN = 0x10
+)
+
+func (options PrefixOptions) String() string {
+	names := []string{"NU", "LA", "*", "P", "DN"}
+	s := ""
+	for i := len(names) - 1; i >= 0; i-- {
+		if options&(1<<uint(i)) != 0 {
+			s += names[i]
+		} else {
+			s += "-"
+		}
+	}
+	return fmt.Sprintf("%s(0x%02x)", s, uint8(options))
+}
+
+// LsType is the LS type of an OSPFv2 LSA or the LS function code with
+// the U, S1 and S2 bits of an OSPFv3 one.
+type LsType uint16
+
+const (
+	_                       LsType = iota
+	LS_TYPE_ROUTER                 = 0x01
+	LS_TYPE_NETWORK                = 0x02
+	LS_TYPE_SUMMARY_NETWORK        = 0x03
+	LS_TYPE_SUMMARY_ASBR           = 0x04
+	LS_TYPE_AS_EXTERNAL            = 0x05
+	LS_TYPE_NSSA_EXTERNAL          = 0x07
+)
+
+const (
+	LS_TYPE_OSPFV3_ROUTER        = 0x2001
+	LS_TYPE_OSPFV3_NETWORK       = 0x2002
+	LS_TYPE_INTER_AREA_PREFIX    = 0x2003
+	LS_TYPE_INTER_AREA_ROUTER    = 0x2004
+	LS_TYPE_OSPFV3_AS_EXTERNAL   = 0x4005
+	LS_TYPE_OSPFV3_NSSA_EXTERNAL = 0x2007
+	LS_TYPE_LINK                 = 0x0008
+	LS_TYPE_INTRA_AREA_PREFIX    = 0x2009
+)
+
+type FloodingScope uint8
+
+const (
+	FLOODING_SCOPE_LINK     FloodingScope = 0
+	FLOODING_SCOPE_AREA     FloodingScope = 1
+	FLOODING_SCOPE_AS       FloodingScope = 2
+	FLOODING_SCOPE_RESERVED FloodingScope = 3
+)
+
+func (scope FloodingScope) String() string {
+	switch scope {
+	case FLOODING_SCOPE_LINK:
+		return "FLOODING_SCOPE_LINK"
+	case FLOODING_SCOPE_AREA:
+		return "FLOODING_SCOPE_AREA"
+	case FLOODING_SCOPE_AS:
+		return "FLOODING_SCOPE_AS"
+	case FLOODING_SCOPE_RESERVED:
+		return "FLOODING_SCOPE_RESERVED"
+	}
+	return fmt.Sprintf("FloodingScope(%d)", scope)
+}
+
+// Scope is the flooding scope the S1 and S2 bits of an OSPFv3 LS type
+// encode.
+func (lsType LsType) Scope() FloodingScope {
+	return FloodingScope((lsType >> 13) & 0x03)
+}
+
+// UBit tells how a router which does not know an OSPFv3 LS type handles
+// it: as if it knew it if set, or else with link-local flooding scope.
+func (lsType LsType) UBit() bool {
+	return lsType&0x8000 != 0
+}
+
+func (lsType LsType) String() string {
+	switch lsType {
+	case LS_TYPE_ROUTER:
+		return "LS_TYPE_ROUTER"
+	case LS_TYPE_NETWORK:
+		return "LS_TYPE_NETWORK"
+	case LS_TYPE_SUMMARY_NETWORK:
+		return "LS_TYPE_SUMMARY_NETWORK"
+	case LS_TYPE_SUMMARY_ASBR:
+		return "LS_TYPE_SUMMARY_ASBR"
+	case LS_TYPE_AS_EXTERNAL:
+		return "LS_TYPE_AS_EXTERNAL"
+	case LS_TYPE_NSSA_EXTERNAL:
+		return "LS_TYPE_NSSA_EXTERNAL"
+	case LS_TYPE_OSPFV3_ROUTER:
+		return "LS_TYPE_OSPFV3_ROUTER"
+	case LS_TYPE_OSPFV3_NETWORK:
+		return "LS_TYPE_OSPFV3_NETWORK"
+	case LS_TYPE_INTER_AREA_PREFIX:
+		return "LS_TYPE_INTER_AREA_PREFIX"
+	case LS_TYPE_INTER_AREA_ROUTER:
+		return "LS_TYPE_INTER_AREA_ROUTER"
+	case LS_TYPE_OSPFV3_AS_EXTERNAL:
+		return "LS_TYPE_OSPFV3_AS_EXTERNAL"
+	case LS_TYPE_OSPFV3_NSSA_EXTERNAL:
+		return "LS_TYPE_OSPFV3_NSSA_EXTERNAL"
+	case LS_TYPE_LINK:
+		return "LS_TYPE_LINK"
+	case LS_TYPE_INTRA_AREA_PREFIX:
+		return "LS_TYPE_INTRA_AREA_PREFIX"
+	}
+	return fmt.Sprintf("LsType(%d)", lsType)
+}
+
+type LinkType uint8
+
+const (
+	_                        LinkType = iota
+	LINK_TYPE_POINT_TO_POINT          = 0x01
+	LINK_TYPE_TRANSIT                 = 0x02
+	LINK_TYPE_STUB                    = 0x03
+	LINK_TYPE_VIRTUAL                 = 0x04
+)
+
+func (linkType LinkType) String() string {
+	switch linkType {
+	case LINK_TYPE_POINT_TO_POINT:
+		return "LINK_TYPE_POINT_TO_POINT"
+	case LINK_TYPE_TRANSIT:
+		return "LINK_TYPE_TRANSIT"
+	case LINK_TYPE_STUB:
+		return "LINK_TYPE_STUB"
+	case LINK_TYPE_VIRTUAL:
+		return "LINK_TYPE_VIRTUAL"
+	}
+	return fmt.Sprintf("LinkType(%d)", linkType)
+}
+
+func NewLsa(lsType LsType) (OspfLsa, error) {
+	var lsa OspfLsa
+	var err error
+	switch lsType {
+	case LS_TYPE_ROUTER:
+		lsa, err = NewRouterLsa()
+	case LS_TYPE_NETWORK:
+		lsa, err = NewNetworkLsa()
+	case LS_TYPE_SUMMARY_NETWORK, LS_TYPE_SUMMARY_ASBR:
+		lsa, err = NewSummaryLsa(lsType)
+	case LS_TYPE_AS_EXTERNAL, LS_TYPE_NSSA_EXTERNAL:
+		lsa, err = NewExternalLsa(lsType)
+	default:
+		lsa, err = NewUnknownLsa(lsType)
+	}
+	return lsa, err
+}
+
+// NewOspfv3Lsa is NewLsa for the LS types of OSPFv3.
+func NewOspfv3Lsa(lsType LsType) (OspfLsa, error) {
+	var lsa OspfLsa
+	var err error
+	switch lsType {
+	case LS_TYPE_OSPFV3_ROUTER:
+		lsa, err = NewOspfv3RouterLsa()
+	case LS_TYPE_OSPFV3_NETWORK:
+		lsa, err = NewOspfv3NetworkLsa()
+	case LS_TYPE_INTER_AREA_PREFIX:
+		lsa, err = NewInterAreaPrefixLsa()
+	case LS_TYPE_INTER_AREA_ROUTER:
+		lsa, err = NewInterAreaRouterLsa()
+	case LS_TYPE_OSPFV3_AS_EXTERNAL, LS_TYPE_OSPFV3_NSSA_EXTERNAL:
+		lsa, err = NewOspfv3ExternalLsa(lsType)
+	case LS_TYPE_LINK:
+		lsa, err = NewLinkLsa()
+	case LS_TYPE_INTRA_AREA_PREFIX:
+		lsa, err = NewIntraAreaPrefixLsa()
+	default:
+		lsa, err = newUnknownLsa(OSPFV3_VERSION, lsType)
+	}
+	return lsa, err
+}
+
+func Ipv4String(addr uint32) string {
+	return fmt.Sprintf("%d.%d.%d.%d", addr>>24, (addr>>16)&0xff, (addr>>8)&0xff, addr&0xff)
+}
+
+func Ipv6String(addr [4]uint32) string {
+	b := make([]byte, 16)
+	for i, a := range addr {
+		binary.BigEndian.PutUint32(b[4*i:4*i+4], a)
+	}
+	return net.IP(b).String()
+}
+
+// ipChecksum is the checksum of the OSPF packet header, the 16-bit one's
+// complement of the one's complement sum of data.
+func ipChecksum(data []byte) uint16 {
+	var sum uint32
+	for i := 0; i+1 < len(data); i += 2 {
+		sum += uint32(data[i])<<8 | uint32(data[i+1])
+	}
+	if len(data)%2 == 1 {
+		sum += uint32(data[len(data)-1]) << 8
+	}
+	for sum>>16 != 0 {
+		sum = (sum & 0xffff) + (sum >> 16)
+	}
+	return ^uint16(sum)
+}
+
+// fletcherChecksum is the ISO 8473 checksum of data which is placed at
+// offset of data, the field being zero.
+func fletcherChecksum(data []byte, offset int) uint16 {
+	c0, c1 := fletcherSum(data)
+	x := ((len(data)-offset-1)*c0 - c1) % 255
+	if x <= 0 {
+		x += 255
+	}
+	y := 510 - c0 - x
+	if y > 255 {
+		y -= 255
+	}
+	return uint16(x)<<8 | uint16(y)
+}
+
+func fletcherSum(data []byte) (int, int) {
+	c0 := 0
+	c1 := 0
+	for _, d := range data {
+		c0 = (c0 + int(d)) % 255
+		c1 = (c1 + c0) % 255
+	}
+	return c0, c1
+}
@@ -0,0 +1,201 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package packet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+type packetBase struct {
+	originalData []byte
+
+	version        uint8
+	packetType     PacketType
+	packetLength   uint16
+	RouterId       uint32
+	AreaId         uint32
+	checksum       uint16
+	AuType         AuType
+	Authentication [AUTHENTICATION_LENGTH]byte
+	InstanceId     uint8
+}
+
+func (base *packetBase) init() {
+	base.originalData = make([]byte, 0)
+
+	base.version = OSPF_VERSION
+}
+
+func (base *packetBase) headerLength() int {
+	if base.version == OSPFV3_VERSION {
+		return OSPFV3_PACKET_HEADER_LENGTH
+	}
+	return PACKET_HEADER_LENGTH
+}
+
+func (base *packetBase) setVersion(version uint8) error {
+	if version != OSPF_VERSION && version != OSPFV3_VERSION {
+		return errors.New("packetBase.setVersion: version invalid")
+	}
+	base.version = version
+	return nil
+}
+
+// valid checks the version and, for OSPFv2, the checksum. The checksum of
+// OSPFv3 covers an IPv6 pseudo-header and is left to the kernel.
+func (base *packetBase) valid() bool {
+	if base.version == OSPFV3_VERSION {
+		return true
+	}
+	if base.version != OSPF_VERSION {
+		return false
+	}
+	if base.AuType != AU_TYPE_CRYPTOGRAPHIC &&
+		len(base.originalData) >= int(base.packetLength) {
+		data := make([]byte, base.packetLength)
+		copy(data, base.originalData)
+		for i := 16; i < PACKET_HEADER_LENGTH; i++ {
+			data[i] = 0
+		}
+		if ipChecksum(data) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (base *packetBase) StringFixed() string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "version                         %d\n", base.version)
+	fmt.Fprintf(&b, "packetType                      %s(%d)\n", base.packetType.String(), base.packetType)
+	fmt.Fprintf(&b, "packetLength                    %d\n", base.packetLength)
+	fmt.Fprintf(&b, "RouterId                        %s\n", Ipv4String(base.RouterId))
+	fmt.Fprintf(&b, "AreaId                          %s\n", Ipv4String(base.AreaId))
+	fmt.Fprintf(&b, "checksum                        0x%04x\n", base.checksum)
+	if base.version == OSPFV3_VERSION {
+		fmt.Fprintf(&b, "InstanceId                      %d\n", base.InstanceId)
+		return b.String()
+	}
+	fmt.Fprintf(&b, "AuType                          %s(%d)\n", base.AuType.String(), base.AuType)
+	fmt.Fprintf(&b, "Authentication                  ")
+	for _, t := range base.Authentication {
+		fmt.Fprintf(&b, "%02x", t)
+	}
+	fmt.Fprintf(&b, "\n")
+	return b.String()
+}
+
+// DecodeFromBytes decodes the header of the packet. Data may be longer
+// than the packet by the trailer of cryptographic authentication.
+func (base *packetBase) DecodeFromBytes(data []byte) error {
+	base.originalData = make([]byte, len(data))
+	copy(base.originalData, data)
+	if len(data) < OSPFV3_PACKET_HEADER_LENGTH {
+		return errors.New("packetBase.DecodeFromBytes: data length too short")
+	}
+	base.version = data[0]
+	if len(data) < base.headerLength() {
+		return errors.New("packetBase.DecodeFromBytes: data length too short")
+	}
+	base.packetType = PacketType(data[1])
+	base.packetLength = binary.BigEndian.Uint16(data[2:4])
+	base.RouterId = binary.BigEndian.Uint32(data[4:8])
+	base.AreaId = binary.BigEndian.Uint32(data[8:12])
+	base.checksum = binary.BigEndian.Uint16(data[12:14])
+	if base.version == OSPFV3_VERSION {
+		base.InstanceId = data[14]
+	} else {
+		base.AuType = AuType(binary.BigEndian.Uint16(data[14:16]))
+		copy(base.Authentication[0:AUTHENTICATION_LENGTH], data[16:PACKET_HEADER_LENGTH])
+	}
+	if int(base.packetLength) < base.headerLength() ||
+		len(data) < int(base.packetLength) {
+		s := fmt.Sprintf("packetBase.DecodeFromBytes: data length mismatch %d %d", len(data), int(base.packetLength))
+		return errors.New(s)
+	}
+	return nil
+}
+
+// Serialize returns the packet of the header and body. The checksum is
+// left zero for cryptographic authentication, which covers the packet
+// instead, and for OSPFv3, whose checksum the kernel computes.
+func (base *packetBase) Serialize(body []byte) ([]byte, error) {
+	headerLength := base.headerLength()
+	if headerLength+len(body) > 0xffff {
+		return nil, errors.New("packetBase.Serialize: packet too long")
+	}
+	base.packetLength = uint16(headerLength + len(body))
+	data := make([]byte, base.packetLength)
+	data[0] = base.version
+	data[1] = uint8(base.packetType)
+	binary.BigEndian.PutUint16(data[2:4], base.packetLength)
+	binary.BigEndian.PutUint32(data[4:8], base.RouterId)
+	binary.BigEndian.PutUint32(data[8:12], base.AreaId)
+	copy(data[headerLength:], body)
+	if base.version == OSPFV3_VERSION {
+		data[14] = base.InstanceId
+		base.checksum = 0
+		return data, nil
+	}
+	binary.BigEndian.PutUint16(data[14:16], uint16(base.AuType))
+	base.checksum = 0
+	if base.AuType != AU_TYPE_CRYPTOGRAPHIC {
+		base.checksum = ipChecksum(data)
+	}
+	binary.BigEndian.PutUint16(data[12:14], base.checksum)
+	copy(data[16:PACKET_HEADER_LENGTH], base.Authentication[0:AUTHENTICATION_LENGTH])
+	return data, nil
+}
+
+func (base *packetBase) body() []byte {
+	return base.originalData[base.headerLength():base.packetLength]
+}
+
+func DecodePacketFromBytes(data []byte) (OspfPacket, error) {
+	var packet OspfPacket
+	if len(data) < OSPFV3_PACKET_HEADER_LENGTH {
+		return nil, errors.New("DecodePacketFromBytes: data length too short")
+	}
+	if data[0] != OSPF_VERSION && data[0] != OSPFV3_VERSION {
+		return nil, errors.New("DecodePacketFromBytes: version not supported")
+	}
+	packetType := PacketType(data[1])
+	var err error
+	switch packetType {
+	case PACKET_TYPE_HELLO:
+		packet, err = NewHelloPacket()
+	case PACKET_TYPE_DATABASE_DESCRIPTION:
+		packet, err = NewDatabaseDescriptionPacket()
+	case PACKET_TYPE_LINK_STATE_REQUEST:
+		packet, err = NewLinkStateRequestPacket()
+	case PACKET_TYPE_LINK_STATE_UPDATE:
+		packet, err = NewLinkStateUpdatePacket()
+	case PACKET_TYPE_LINK_STATE_ACK:
+		packet, err = NewLinkStateAckPacket()
+	default:
+		return nil, errors.New("DecodePacketFromBytes: packet type invalid")
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = packet.DecodeFromBytes(data)
+	return packet, err
+}
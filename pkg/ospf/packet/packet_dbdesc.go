@@ -0,0 +1,192 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package packet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+type DatabaseDescriptionPacket struct {
+	base packetBase
+
+	InterfaceMtu     uint16
+	Options          Options
+	Init             bool
+	More             bool
+	MasterSlave      bool
+	DdSequenceNumber uint32
+	LsaHeaders       []*LsaHeader
+}
+
+func NewDatabaseDescriptionPacket() (*DatabaseDescriptionPacket, error) {
+	dd := DatabaseDescriptionPacket{
+		base: packetBase{
+			packetType: PACKET_TYPE_DATABASE_DESCRIPTION,
+		},
+		LsaHeaders: make([]*LsaHeader, 0),
+	}
+	dd.base.init()
+	return &dd, nil
+}
+
+func (dd *DatabaseDescriptionPacket) PacketType() PacketType {
+	return dd.base.packetType
+}
+
+func (dd *DatabaseDescriptionPacket) String() string {
+	var b bytes.Buffer
+	b.WriteString(dd.base.StringFixed())
+	fmt.Fprintf(&b, "InterfaceMtu                    %d\n", dd.InterfaceMtu)
+	fmt.Fprintf(&b, "Options                         %s\n", optionsString(dd.base.version, dd.Options))
+	fmt.Fprintf(&b, "Init                            %t\n", dd.Init)
+	fmt.Fprintf(&b, "More                            %t\n", dd.More)
+	fmt.Fprintf(&b, "MasterSlave                     %t\n", dd.MasterSlave)
+	fmt.Fprintf(&b, "DdSequenceNumber                0x%08x\n", dd.DdSequenceNumber)
+	for i, header := range dd.LsaHeaders {
+		fmt.Fprintf(&b, "LsaHeaders[%d]\n", i)
+		b.WriteString(header.String())
+	}
+	return b.String()
+}
+
+func (dd *DatabaseDescriptionPacket) DecodeFromBytes(data []byte) error {
+	err := dd.base.DecodeFromBytes(data)
+	if err != nil {
+		return err
+	}
+	body := dd.base.body()
+	fixed := 8
+	if dd.base.version == OSPFV3_VERSION {
+		fixed = 12
+	}
+	if len(body) < fixed || (len(body)-fixed)%LSA_HEADER_LENGTH != 0 {
+		return errors.New("DatabaseDescriptionPacket.DecodeFromBytes: data length invalid")
+	}
+	var flags uint8
+	if dd.base.version == OSPFV3_VERSION {
+		dd.Options = Options(binary.BigEndian.Uint32(body[0:4]) & 0xffffff)
+		dd.InterfaceMtu = binary.BigEndian.Uint16(body[4:6])
+		flags = body[7]
+		dd.DdSequenceNumber = binary.BigEndian.Uint32(body[8:12])
+	} else {
+		dd.InterfaceMtu = binary.BigEndian.Uint16(body[0:2])
+		dd.Options = Options(body[2])
+		flags = body[3]
+		dd.DdSequenceNumber = binary.BigEndian.Uint32(body[4:8])
+	}
+	dd.Init = flags&0x04 == 0x04
+	dd.More = flags&0x02 == 0x02
+	dd.MasterSlave = flags&0x01 == 0x01
+	dd.LsaHeaders = make([]*LsaHeader, 0)
+	for i := fixed; i < len(body); i += LSA_HEADER_LENGTH {
+		header := &LsaHeader{version: dd.base.version}
+		err = header.DecodeFromBytes(body[i : i+LSA_HEADER_LENGTH])
+		if err != nil {
+			return err
+		}
+		dd.LsaHeaders = append(dd.LsaHeaders, header)
+	}
+	return nil
+}
+
+func (dd *DatabaseDescriptionPacket) Serialize() ([]byte, error) {
+	var flags uint8
+	if dd.Init {
+		flags |= 0x04
+	}
+	if dd.More {
+		flags |= 0x02
+	}
+	if dd.MasterSlave {
+		flags |= 0x01
+	}
+	var body []byte
+	if dd.base.version == OSPFV3_VERSION {
+		body = make([]byte, 12, 12+LSA_HEADER_LENGTH*len(dd.LsaHeaders))
+		binary.BigEndian.PutUint32(body[0:4], uint32(dd.Options)&0xffffff)
+		binary.BigEndian.PutUint16(body[4:6], dd.InterfaceMtu)
+		body[7] = flags
+		binary.BigEndian.PutUint32(body[8:12], dd.DdSequenceNumber)
+	} else {
+		body = make([]byte, 8, 8+LSA_HEADER_LENGTH*len(dd.LsaHeaders))
+		binary.BigEndian.PutUint16(body[0:2], dd.InterfaceMtu)
+		body[2] = uint8(dd.Options)
+		body[3] = flags
+		binary.BigEndian.PutUint32(body[4:8], dd.DdSequenceNumber)
+	}
+	for _, header := range dd.LsaHeaders {
+		data, err := header.Serialize()
+		if err != nil {
+			return nil, err
+		}
+		body = append(body, data...)
+	}
+	return dd.base.Serialize(body)
+}
+
+func (dd *DatabaseDescriptionPacket) BaseValid() bool {
+	return dd.base.valid()
+}
+
+func (dd *DatabaseDescriptionPacket) Version() uint8 {
+	return dd.base.version
+}
+
+func (dd *DatabaseDescriptionPacket) SetVersion(version uint8) error {
+	return dd.base.setVersion(version)
+}
+
+func (dd *DatabaseDescriptionPacket) InstanceId() uint8 {
+	return dd.base.InstanceId
+}
+
+func (dd *DatabaseDescriptionPacket) SetInstanceId(instanceId uint8) error {
+	dd.base.InstanceId = instanceId
+	return nil
+}
+
+func (dd *DatabaseDescriptionPacket) RouterId() uint32 {
+	return dd.base.RouterId
+}
+
+func (dd *DatabaseDescriptionPacket) SetRouterId(routerId uint32) error {
+	dd.base.RouterId = routerId
+	return nil
+}
+
+func (dd *DatabaseDescriptionPacket) AreaId() uint32 {
+	return dd.base.AreaId
+}
+
+func (dd *DatabaseDescriptionPacket) SetAreaId(areaId uint32) error {
+	dd.base.AreaId = areaId
+	return nil
+}
+
+func (dd *DatabaseDescriptionPacket) Authentication() (AuType, [AUTHENTICATION_LENGTH]byte) {
+	return dd.base.AuType, dd.base.Authentication
+}
+
+func (dd *DatabaseDescriptionPacket) SetAuthentication(auType AuType, authentication [AUTHENTICATION_LENGTH]byte) error {
+	dd.base.AuType = auType
+	dd.base.Authentication = authentication
+	return nil
+}
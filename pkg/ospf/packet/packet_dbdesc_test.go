@@ -0,0 +1,121 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package packet
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDatabaseDescriptionPacketNew(t *testing.T) {
+	var err error
+
+	p1, err := NewDatabaseDescriptionPacket()
+	if err != nil {
+		t.Fatalf("failed NewDatabaseDescriptionPacket: %#v", err)
+	}
+	p1.SetRouterId(0x01010101)
+	p1.InterfaceMtu = 1500
+	p1.Options = OPTIONS_E
+	p1.More = true
+	p1.MasterSlave = true
+	p1.DdSequenceNumber = 0x12345678
+	for i := 1; i <= 3; i++ {
+		header := NewLsaHeader(LS_TYPE_ROUTER)
+		header.LinkStateId = uint32(i)
+		header.AdvertisingRouter = uint32(i)
+		header.LsChecksum = 0x1234
+		p1.LsaHeaders = append(p1.LsaHeaders, header)
+	}
+
+	d1, err := p1.Serialize()
+	if err != nil {
+		t.Fatalf("failed Serialize: %#v", err)
+	}
+
+	p2, err := DecodePacketFromBytes(d1)
+	if err != nil {
+		t.Fatalf("failed DecodePacketFromBytes: %#v", err)
+	}
+	dd, ok := p2.(*DatabaseDescriptionPacket)
+	if !ok || dd.Init || !dd.More || !dd.MasterSlave || len(dd.LsaHeaders) != 3 ||
+		dd.LsaHeaders[2].Key() != (LsaKey{LS_TYPE_ROUTER, 3, 3}) {
+		t.Fatalf("failed DatabaseDescriptionPacket:\n%s", p2.String())
+	}
+
+	d2, err := p2.Serialize()
+	if err != nil {
+		t.Fatalf("failed Serialize: %#v", err)
+	}
+
+	if !bytes.Equal(d1, d2) {
+		t.Fatalf("failed !bytes.Equal")
+	}
+
+	//t.Fatalf("\n%s", p2.String())
+}
+
+func TestDatabaseDescriptionPacketOspfv3(t *testing.T) {
+	var err error
+
+	p1, err := NewDatabaseDescriptionPacket()
+	if err != nil {
+		t.Fatalf("failed NewDatabaseDescriptionPacket: %#v", err)
+	}
+	if err = p1.SetVersion(OSPFV3_VERSION); err != nil {
+		t.Fatalf("failed SetVersion: %#v", err)
+	}
+	p1.SetRouterId(0x01010101)
+	p1.InterfaceMtu = 1500
+	p1.Options = OPTIONS_V6 | OPTIONS_E | OPTIONS_R
+	p1.Init = true
+	p1.DdSequenceNumber = 0x12345678
+	header := newLsaHeader(OSPFV3_VERSION, LS_TYPE_INTRA_AREA_PREFIX)
+	header.AdvertisingRouter = 0x01010101
+	p1.LsaHeaders = append(p1.LsaHeaders, header)
+
+	d1, err := p1.Serialize()
+	if err != nil {
+		t.Fatalf("failed Serialize: %#v", err)
+	}
+	if len(d1) != OSPFV3_PACKET_HEADER_LENGTH+12+LSA_HEADER_LENGTH {
+		t.Fatalf("failed Serialize: length %d", len(d1))
+	}
+
+	p2, err := DecodePacketFromBytes(d1)
+	if err != nil {
+		t.Fatalf("failed DecodePacketFromBytes: %#v", err)
+	}
+	dd, ok := p2.(*DatabaseDescriptionPacket)
+	if !ok || !dd.Init || dd.More || dd.MasterSlave || dd.InterfaceMtu != 1500 ||
+		dd.Options != p1.Options || len(dd.LsaHeaders) != 1 ||
+		dd.LsaHeaders[0].Key() != (LsaKey{LS_TYPE_INTRA_AREA_PREFIX, 0, 0x01010101}) {
+		t.Fatalf("failed DatabaseDescriptionPacket:\n%s", p2.String())
+	}
+
+	d2, err := p2.Serialize()
+	if err != nil {
+		t.Fatalf("failed Serialize: %#v", err)
+	}
+
+	if !bytes.Equal(d1, d2) {
+		t.Fatalf("failed !bytes.Equal")
+	}
+
+	//t.Fatalf("\n%s", p2.String())
+}
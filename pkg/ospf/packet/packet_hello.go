@@ -0,0 +1,183 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package packet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// HelloPacket is a hello of OSPFv2, which has NetworkMask, or of OSPFv3,
+// which has InterfaceId and where RouterDeadInterval is 16 bits long and
+// the designated routers are router IDs.
+type HelloPacket struct {
+	base packetBase
+
+	NetworkMask            uint32
+	InterfaceId            uint32
+	HelloInterval          uint16
+	Options                Options
+	RouterPriority         uint8
+	RouterDeadInterval     uint32
+	DesignatedRouter       uint32
+	BackupDesignatedRouter uint32
+	Neighbors              []uint32
+}
+
+func NewHelloPacket() (*HelloPacket, error) {
+	hello := HelloPacket{
+		base: packetBase{
+			packetType: PACKET_TYPE_HELLO,
+		},
+		Neighbors: make([]uint32, 0),
+	}
+	hello.base.init()
+	return &hello, nil
+}
+
+func (hello *HelloPacket) PacketType() PacketType {
+	return hello.base.packetType
+}
+
+func (hello *HelloPacket) String() string {
+	var b bytes.Buffer
+	b.WriteString(hello.base.StringFixed())
+	if hello.base.version == OSPFV3_VERSION {
+		fmt.Fprintf(&b, "InterfaceId                     %d\n", hello.InterfaceId)
+	} else {
+		fmt.Fprintf(&b, "NetworkMask                     %s\n", Ipv4String(hello.NetworkMask))
+	}
+	fmt.Fprintf(&b, "HelloInterval                   %d\n", hello.HelloInterval)
+	fmt.Fprintf(&b, "Options                         %s\n", optionsString(hello.base.version, hello.Options))
+	fmt.Fprintf(&b, "RouterPriority                  %d\n", hello.RouterPriority)
+	fmt.Fprintf(&b, "RouterDeadInterval              %d\n", hello.RouterDeadInterval)
+	fmt.Fprintf(&b, "DesignatedRouter                %s\n", Ipv4String(hello.DesignatedRouter))
+	fmt.Fprintf(&b, "BackupDesignatedRouter          %s\n", Ipv4String(hello.BackupDesignatedRouter))
+	for i, neighbor := range hello.Neighbors {
+		fmt.Fprintf(&b, "Neighbors[%d]                    %s\n", i, Ipv4String(neighbor))
+	}
+	return b.String()
+}
+
+func (hello *HelloPacket) DecodeFromBytes(data []byte) error {
+	err := hello.base.DecodeFromBytes(data)
+	if err != nil {
+		return err
+	}
+	body := hello.base.body()
+	if len(body) < 20 || len(body)%4 != 0 {
+		return errors.New("HelloPacket.DecodeFromBytes: data length invalid")
+	}
+	if hello.base.version == OSPFV3_VERSION {
+		hello.InterfaceId = binary.BigEndian.Uint32(body[0:4])
+		hello.RouterPriority = body[4]
+		hello.Options = Options(binary.BigEndian.Uint32(body[4:8]) & 0xffffff)
+		hello.HelloInterval = binary.BigEndian.Uint16(body[8:10])
+		hello.RouterDeadInterval = uint32(binary.BigEndian.Uint16(body[10:12]))
+	} else {
+		hello.NetworkMask = binary.BigEndian.Uint32(body[0:4])
+		hello.HelloInterval = binary.BigEndian.Uint16(body[4:6])
+		hello.Options = Options(body[6])
+		hello.RouterPriority = body[7]
+		hello.RouterDeadInterval = binary.BigEndian.Uint32(body[8:12])
+	}
+	hello.DesignatedRouter = binary.BigEndian.Uint32(body[12:16])
+	hello.BackupDesignatedRouter = binary.BigEndian.Uint32(body[16:20])
+	hello.Neighbors = make([]uint32, 0)
+	for i := 20; i < len(body); i += 4 {
+		hello.Neighbors = append(hello.Neighbors, binary.BigEndian.Uint32(body[i:i+4]))
+	}
+	return nil
+}
+
+func (hello *HelloPacket) Serialize() ([]byte, error) {
+	body := make([]byte, 20+4*len(hello.Neighbors))
+	if hello.base.version == OSPFV3_VERSION {
+		if hello.RouterDeadInterval > 0xffff {
+			return nil, errors.New("HelloPacket.Serialize: RouterDeadInterval too long")
+		}
+		binary.BigEndian.PutUint32(body[0:4], hello.InterfaceId)
+		binary.BigEndian.PutUint32(body[4:8], uint32(hello.Options)&0xffffff)
+		body[4] = hello.RouterPriority
+		binary.BigEndian.PutUint16(body[8:10], hello.HelloInterval)
+		binary.BigEndian.PutUint16(body[10:12], uint16(hello.RouterDeadInterval))
+	} else {
+		binary.BigEndian.PutUint32(body[0:4], hello.NetworkMask)
+		binary.BigEndian.PutUint16(body[4:6], hello.HelloInterval)
+		body[6] = uint8(hello.Options)
+		body[7] = hello.RouterPriority
+		binary.BigEndian.PutUint32(body[8:12], hello.RouterDeadInterval)
+	}
+	binary.BigEndian.PutUint32(body[12:16], hello.DesignatedRouter)
+	binary.BigEndian.PutUint32(body[16:20], hello.BackupDesignatedRouter)
+	for i, neighbor := range hello.Neighbors {
+		binary.BigEndian.PutUint32(body[20+4*i:24+4*i], neighbor)
+	}
+	return hello.base.Serialize(body)
+}
+
+func (hello *HelloPacket) BaseValid() bool {
+	return hello.base.valid()
+}
+
+func (hello *HelloPacket) Version() uint8 {
+	return hello.base.version
+}
+
+func (hello *HelloPacket) SetVersion(version uint8) error {
+	return hello.base.setVersion(version)
+}
+
+func (hello *HelloPacket) InstanceId() uint8 {
+	return hello.base.InstanceId
+}
+
+func (hello *HelloPacket) SetInstanceId(instanceId uint8) error {
+	hello.base.InstanceId = instanceId
+	return nil
+}
+
+func (hello *HelloPacket) RouterId() uint32 {
+	return hello.base.RouterId
+}
+
+func (hello *HelloPacket) SetRouterId(routerId uint32) error {
+	hello.base.RouterId = routerId
+	return nil
+}
+
+func (hello *HelloPacket) AreaId() uint32 {
+	return hello.base.AreaId
+}
+
+func (hello *HelloPacket) SetAreaId(areaId uint32) error {
+	hello.base.AreaId = areaId
+	return nil
+}
+
+func (hello *HelloPacket) Authentication() (AuType, [AUTHENTICATION_LENGTH]byte) {
+	return hello.base.AuType, hello.base.Authentication
+}
+
+func (hello *HelloPacket) SetAuthentication(auType AuType, authentication [AUTHENTICATION_LENGTH]byte) error {
+	hello.base.AuType = auType
+	hello.base.Authentication = authentication
+	return nil
+}
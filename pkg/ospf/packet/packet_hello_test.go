@@ -0,0 +1,155 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package packet
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestHelloPacketDecode(t *testing.T) {
+	var err error
+
+	d1 := []byte{
+		0x02, 0x01, 0x00, 0x30, 0x01, 0x01, 0x01, 0x01, 0x00, 0x00, 0x00, 0x00, 0xec, 0x93, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0xff, 0xff, 0xff, 0x00, 0x00, 0x0a, 0x02, 0x01, 0x00, 0x00, 0x00, 0x28,
+		0x0a, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00,
+		0x02, 0x02, 0x02, 0x02,
+	}
+
+	p1, err := DecodePacketFromBytes(d1)
+	if err != nil {
+		t.Fatalf("failed DecodePacketFromBytes: %#v", err)
+	}
+	if !p1.BaseValid() {
+		t.Fatalf("failed BaseValid")
+	}
+	hello, ok := p1.(*HelloPacket)
+	if !ok {
+		t.Fatalf("failed HelloPacket: %#v", p1)
+	}
+	if hello.RouterId() != 0x01010101 || hello.HelloInterval != 10 ||
+		hello.RouterDeadInterval != 40 || hello.DesignatedRouter != 0x0a000001 ||
+		len(hello.Neighbors) != 1 || hello.Neighbors[0] != 0x02020202 {
+		t.Fatalf("failed HelloPacket:\n%s", hello.String())
+	}
+
+	d2, err := p1.Serialize()
+	if err != nil {
+		t.Fatalf("failed Serialize: %#v", err)
+	}
+
+	if !bytes.Equal(d1, d2) {
+		t.Fatalf("failed !bytes.Equal")
+	}
+
+	d1[30] = 0x00
+	p2, err := DecodePacketFromBytes(d1)
+	if err != nil {
+		t.Fatalf("failed DecodePacketFromBytes: %#v", err)
+	}
+	if p2.BaseValid() {
+		t.Fatalf("failed BaseValid: bad checksum accepted")
+	}
+
+	//t.Fatalf("\n%s", p1.String())
+}
+
+func TestHelloPacketNew(t *testing.T) {
+	var err error
+
+	p1, err := NewHelloPacket()
+	if err != nil {
+		t.Fatalf("failed NewHelloPacket: %#v", err)
+	}
+	p1.SetRouterId(0x02020202)
+	p1.SetAreaId(0x00000001)
+	p1.SetAuthentication(AU_TYPE_SIMPLE, [AUTHENTICATION_LENGTH]byte{'s', 'e', 'c', 'r', 'e', 't'})
+	p1.NetworkMask = 0xfffffffc
+	p1.HelloInterval = 10
+	p1.Options = OPTIONS_E
+	p1.RouterDeadInterval = 40
+	p1.Neighbors = append(p1.Neighbors, 0x01010101, 0x03030303)
+
+	d1, err := p1.Serialize()
+	if err != nil {
+		t.Fatalf("failed Serialize: %#v", err)
+	}
+
+	p2, err := DecodePacketFromBytes(d1)
+	if err != nil {
+		t.Fatalf("failed DecodePacketFromBytes: %#v", err)
+	}
+	if !p2.BaseValid() {
+		t.Fatalf("failed BaseValid")
+	}
+
+	d2, err := p2.Serialize()
+	if err != nil {
+		t.Fatalf("failed Serialize: %#v", err)
+	}
+
+	if !bytes.Equal(d1, d2) {
+		t.Fatalf("failed !bytes.Equal")
+	}
+
+	//t.Fatalf("\n%s", p2.String())
+}
+
+func TestHelloPacketOspfv3Decode(t *testing.T) {
+	var err error
+
+	d1 := []byte{
+		0x03, 0x01, 0x00, 0x28, 0x01, 0x01, 0x01, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x05, 0x01, 0x00, 0x00, 0x13, 0x00, 0x0a, 0x00, 0x28,
+		0x01, 0x01, 0x01, 0x01, 0x00, 0x00, 0x00, 0x00,
+		0x02, 0x02, 0x02, 0x02,
+	}
+
+	p1, err := DecodePacketFromBytes(d1)
+	if err != nil {
+		t.Fatalf("failed DecodePacketFromBytes: %#v", err)
+	}
+	if !p1.BaseValid() || p1.Version() != OSPFV3_VERSION || p1.InstanceId() != 0 {
+		t.Fatalf("failed BaseValid")
+	}
+	hello, ok := p1.(*HelloPacket)
+	if !ok {
+		t.Fatalf("failed HelloPacket: %#v", p1)
+	}
+	if hello.InterfaceId != 5 || hello.RouterPriority != 1 ||
+		hello.Options != OPTIONS_V6|OPTIONS_E|OPTIONS_R || hello.HelloInterval != 10 ||
+		hello.RouterDeadInterval != 40 || hello.DesignatedRouter != 0x01010101 ||
+		len(hello.Neighbors) != 1 || hello.Neighbors[0] != 0x02020202 {
+		t.Fatalf("failed HelloPacket:\n%s", hello.String())
+	}
+
+	hello.SetInstanceId(64)
+	d2, err := p1.Serialize()
+	if err != nil {
+		t.Fatalf("failed Serialize: %#v", err)
+	}
+
+	d1[14] = 64
+	if !bytes.Equal(d1, d2) {
+		t.Fatalf("failed !bytes.Equal")
+	}
+
+	//t.Fatalf("\n%s", p1.String())
+}
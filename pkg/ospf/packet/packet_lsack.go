@@ -0,0 +1,137 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package packet
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+type LinkStateAckPacket struct {
+	base packetBase
+
+	LsaHeaders []*LsaHeader
+}
+
+func NewLinkStateAckPacket() (*LinkStateAckPacket, error) {
+	ack := LinkStateAckPacket{
+		base: packetBase{
+			packetType: PACKET_TYPE_LINK_STATE_ACK,
+		},
+		LsaHeaders: make([]*LsaHeader, 0),
+	}
+	ack.base.init()
+	return &ack, nil
+}
+
+func (ack *LinkStateAckPacket) PacketType() PacketType {
+	return ack.base.packetType
+}
+
+func (ack *LinkStateAckPacket) String() string {
+	var b bytes.Buffer
+	b.WriteString(ack.base.StringFixed())
+	for i, header := range ack.LsaHeaders {
+		fmt.Fprintf(&b, "LsaHeaders[%d]\n", i)
+		b.WriteString(header.String())
+	}
+	return b.String()
+}
+
+func (ack *LinkStateAckPacket) DecodeFromBytes(data []byte) error {
+	err := ack.base.DecodeFromBytes(data)
+	if err != nil {
+		return err
+	}
+	body := ack.base.body()
+	if len(body)%LSA_HEADER_LENGTH != 0 {
+		return errors.New("LinkStateAckPacket.DecodeFromBytes: data length invalid")
+	}
+	ack.LsaHeaders = make([]*LsaHeader, 0)
+	for i := 0; i < len(body); i += LSA_HEADER_LENGTH {
+		header := &LsaHeader{version: ack.base.version}
+		err = header.DecodeFromBytes(body[i : i+LSA_HEADER_LENGTH])
+		if err != nil {
+			return err
+		}
+		ack.LsaHeaders = append(ack.LsaHeaders, header)
+	}
+	return nil
+}
+
+func (ack *LinkStateAckPacket) Serialize() ([]byte, error) {
+	body := make([]byte, 0, LSA_HEADER_LENGTH*len(ack.LsaHeaders))
+	for _, header := range ack.LsaHeaders {
+		data, err := header.Serialize()
+		if err != nil {
+			return nil, err
+		}
+		body = append(body, data...)
+	}
+	return ack.base.Serialize(body)
+}
+
+func (ack *LinkStateAckPacket) BaseValid() bool {
+	return ack.base.valid()
+}
+
+func (ack *LinkStateAckPacket) Version() uint8 {
+	return ack.base.version
+}
+
+func (ack *LinkStateAckPacket) SetVersion(version uint8) error {
+	return ack.base.setVersion(version)
+}
+
+func (ack *LinkStateAckPacket) InstanceId() uint8 {
+	return ack.base.InstanceId
+}
+
+func (ack *LinkStateAckPacket) SetInstanceId(instanceId uint8) error {
+	ack.base.InstanceId = instanceId
+	return nil
+}
+
+func (ack *LinkStateAckPacket) RouterId() uint32 {
+	return ack.base.RouterId
+}
+
+func (ack *LinkStateAckPacket) SetRouterId(routerId uint32) error {
+	ack.base.RouterId = routerId
+	return nil
+}
+
+func (ack *LinkStateAckPacket) AreaId() uint32 {
+	return ack.base.AreaId
+}
+
+func (ack *LinkStateAckPacket) SetAreaId(areaId uint32) error {
+	ack.base.AreaId = areaId
+	return nil
+}
+
+func (ack *LinkStateAckPacket) Authentication() (AuType, [AUTHENTICATION_LENGTH]byte) {
+	return ack.base.AuType, ack.base.Authentication
+}
+
+func (ack *LinkStateAckPacket) SetAuthentication(auType AuType, authentication [AUTHENTICATION_LENGTH]byte) error {
+	ack.base.AuType = auType
+	ack.base.Authentication = authentication
+	return nil
+}
@@ -0,0 +1,63 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package packet
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestLinkStateAckPacketNew(t *testing.T) {
+	var err error
+
+	p1, err := NewLinkStateAckPacket()
+	if err != nil {
+		t.Fatalf("failed NewLinkStateAckPacket: %#v", err)
+	}
+	header := NewLsaHeader(LS_TYPE_SUMMARY_NETWORK)
+	header.LsAge = MAX_AGE
+	header.LinkStateId = 0xc0a80100
+	header.AdvertisingRouter = 0x01010101
+	header.LsChecksum = 0xabcd
+	p1.LsaHeaders = append(p1.LsaHeaders, header)
+
+	d1, err := p1.Serialize()
+	if err != nil {
+		t.Fatalf("failed Serialize: %#v", err)
+	}
+
+	p2, err := DecodePacketFromBytes(d1)
+	if err != nil {
+		t.Fatalf("failed DecodePacketFromBytes: %#v", err)
+	}
+	ack, ok := p2.(*LinkStateAckPacket)
+	if !ok || len(ack.LsaHeaders) != 1 || *ack.LsaHeaders[0] != *header {
+		t.Fatalf("failed LinkStateAckPacket:\n%s", p2.String())
+	}
+
+	d2, err := p2.Serialize()
+	if err != nil {
+		t.Fatalf("failed Serialize: %#v", err)
+	}
+
+	if !bytes.Equal(d1, d2) {
+		t.Fatalf("failed !bytes.Equal")
+	}
+
+	//t.Fatalf("\n%s", p2.String())
+}
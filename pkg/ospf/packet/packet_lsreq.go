@@ -0,0 +1,135 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package packet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+type LinkStateRequestPacket struct {
+	base packetBase
+
+	Requests []*LsaKey
+}
+
+func NewLinkStateRequestPacket() (*LinkStateRequestPacket, error) {
+	lsr := LinkStateRequestPacket{
+		base: packetBase{
+			packetType: PACKET_TYPE_LINK_STATE_REQUEST,
+		},
+		Requests: make([]*LsaKey, 0),
+	}
+	lsr.base.init()
+	return &lsr, nil
+}
+
+func (lsr *LinkStateRequestPacket) PacketType() PacketType {
+	return lsr.base.packetType
+}
+
+func (lsr *LinkStateRequestPacket) String() string {
+	var b bytes.Buffer
+	b.WriteString(lsr.base.StringFixed())
+	for i, request := range lsr.Requests {
+		fmt.Fprintf(&b, "Requests[%d]                     %s\n", i, request.String())
+	}
+	return b.String()
+}
+
+func (lsr *LinkStateRequestPacket) DecodeFromBytes(data []byte) error {
+	err := lsr.base.DecodeFromBytes(data)
+	if err != nil {
+		return err
+	}
+	body := lsr.base.body()
+	if len(body)%LS_REQUEST_LENGTH != 0 {
+		return errors.New("LinkStateRequestPacket.DecodeFromBytes: data length invalid")
+	}
+	lsr.Requests = make([]*LsaKey, 0)
+	for i := 0; i < len(body); i += LS_REQUEST_LENGTH {
+		lsr.Requests = append(lsr.Requests, &LsaKey{
+			LsType:            LsType(binary.BigEndian.Uint32(body[i+0:i+4]) & 0xffff),
+			LinkStateId:       binary.BigEndian.Uint32(body[i+4 : i+8]),
+			AdvertisingRouter: binary.BigEndian.Uint32(body[i+8 : i+12]),
+		})
+	}
+	return nil
+}
+
+func (lsr *LinkStateRequestPacket) Serialize() ([]byte, error) {
+	body := make([]byte, LS_REQUEST_LENGTH*len(lsr.Requests))
+	for n, request := range lsr.Requests {
+		i := LS_REQUEST_LENGTH * n
+		binary.BigEndian.PutUint32(body[i+0:i+4], uint32(request.LsType))
+		binary.BigEndian.PutUint32(body[i+4:i+8], request.LinkStateId)
+		binary.BigEndian.PutUint32(body[i+8:i+12], request.AdvertisingRouter)
+	}
+	return lsr.base.Serialize(body)
+}
+
+func (lsr *LinkStateRequestPacket) BaseValid() bool {
+	return lsr.base.valid()
+}
+
+func (lsr *LinkStateRequestPacket) Version() uint8 {
+	return lsr.base.version
+}
+
+func (lsr *LinkStateRequestPacket) SetVersion(version uint8) error {
+	return lsr.base.setVersion(version)
+}
+
+func (lsr *LinkStateRequestPacket) InstanceId() uint8 {
+	return lsr.base.InstanceId
+}
+
+func (lsr *LinkStateRequestPacket) SetInstanceId(instanceId uint8) error {
+	lsr.base.InstanceId = instanceId
+	return nil
+}
+
+func (lsr *LinkStateRequestPacket) RouterId() uint32 {
+	return lsr.base.RouterId
+}
+
+func (lsr *LinkStateRequestPacket) SetRouterId(routerId uint32) error {
+	lsr.base.RouterId = routerId
+	return nil
+}
+
+func (lsr *LinkStateRequestPacket) AreaId() uint32 {
+	return lsr.base.AreaId
+}
+
+func (lsr *LinkStateRequestPacket) SetAreaId(areaId uint32) error {
+	lsr.base.AreaId = areaId
+	return nil
+}
+
+func (lsr *LinkStateRequestPacket) Authentication() (AuType, [AUTHENTICATION_LENGTH]byte) {
+	return lsr.base.AuType, lsr.base.Authentication
+}
+
+func (lsr *LinkStateRequestPacket) SetAuthentication(auType AuType, authentication [AUTHENTICATION_LENGTH]byte) error {
+	lsr.base.AuType = auType
+	lsr.base.Authentication = authentication
+	return nil
+}
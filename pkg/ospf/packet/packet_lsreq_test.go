@@ -0,0 +1,64 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package packet
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestLinkStateRequestPacketNew(t *testing.T) {
+	var err error
+
+	p1, err := NewLinkStateRequestPacket()
+	if err != nil {
+		t.Fatalf("failed NewLinkStateRequestPacket: %#v", err)
+	}
+	p1.Requests = append(p1.Requests,
+		&LsaKey{LS_TYPE_ROUTER, 0x01010101, 0x01010101},
+		&LsaKey{LS_TYPE_NETWORK, 0x0a000001, 0x01010101},
+		&LsaKey{LS_TYPE_AS_EXTERNAL, 0xc0a80000, 0x02020202})
+
+	d1, err := p1.Serialize()
+	if err != nil {
+		t.Fatalf("failed Serialize: %#v", err)
+	}
+	if len(d1) != PACKET_HEADER_LENGTH+3*LS_REQUEST_LENGTH {
+		t.Fatalf("failed Serialize: length %d", len(d1))
+	}
+
+	p2, err := DecodePacketFromBytes(d1)
+	if err != nil {
+		t.Fatalf("failed DecodePacketFromBytes: %#v", err)
+	}
+	lsr, ok := p2.(*LinkStateRequestPacket)
+	if !ok || len(lsr.Requests) != 3 || *lsr.Requests[2] != *p1.Requests[2] {
+		t.Fatalf("failed LinkStateRequestPacket:\n%s", p2.String())
+	}
+
+	d2, err := p2.Serialize()
+	if err != nil {
+		t.Fatalf("failed Serialize: %#v", err)
+	}
+
+	if !bytes.Equal(d1, d2) {
+		t.Fatalf("failed !bytes.Equal")
+	}
+
+	//t.Fatalf("\n%s", p2.String())
+}
@@ -0,0 +1,149 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package packet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+type LinkStateUpdatePacket struct {
+	base packetBase
+
+	Lsas []OspfLsa
+}
+
+func NewLinkStateUpdatePacket() (*LinkStateUpdatePacket, error) {
+	lsu := LinkStateUpdatePacket{
+		base: packetBase{
+			packetType: PACKET_TYPE_LINK_STATE_UPDATE,
+		},
+		Lsas: make([]OspfLsa, 0),
+	}
+	lsu.base.init()
+	return &lsu, nil
+}
+
+func (lsu *LinkStateUpdatePacket) PacketType() PacketType {
+	return lsu.base.packetType
+}
+
+func (lsu *LinkStateUpdatePacket) String() string {
+	var b bytes.Buffer
+	b.WriteString(lsu.base.StringFixed())
+	for i, lsa := range lsu.Lsas {
+		fmt.Fprintf(&b, "Lsas[%d]\n", i)
+		b.WriteString(lsa.String())
+	}
+	return b.String()
+}
+
+func (lsu *LinkStateUpdatePacket) DecodeFromBytes(data []byte) error {
+	err := lsu.base.DecodeFromBytes(data)
+	if err != nil {
+		return err
+	}
+	body := lsu.base.body()
+	if len(body) < 4 {
+		return errors.New("LinkStateUpdatePacket.DecodeFromBytes: data length too short")
+	}
+	lsas := int(binary.BigEndian.Uint32(body[0:4]))
+	lsu.Lsas = make([]OspfLsa, 0)
+	i := 4
+	for n := 0; n < lsas; n++ {
+		lsa, err := decodeLsaFromBytes(lsu.base.version, body[i:])
+		if err != nil {
+			return err
+		}
+		lsu.Lsas = append(lsu.Lsas, lsa)
+		i += int(lsa.Header().Length())
+	}
+	if i != len(body) {
+		return errors.New("LinkStateUpdatePacket.DecodeFromBytes: data length mismatch")
+	}
+	return nil
+}
+
+func (lsu *LinkStateUpdatePacket) Serialize() ([]byte, error) {
+	body := make([]byte, 4)
+	binary.BigEndian.PutUint32(body[0:4], uint32(len(lsu.Lsas)))
+	for _, lsa := range lsu.Lsas {
+		data, err := lsa.Serialize()
+		if err != nil {
+			return nil, err
+		}
+		body = append(body, data...)
+	}
+	return lsu.base.Serialize(body)
+}
+
+func (lsu *LinkStateUpdatePacket) AddLsa(lsa OspfLsa) error {
+	lsu.Lsas = append(lsu.Lsas, lsa)
+	return nil
+}
+
+func (lsu *LinkStateUpdatePacket) BaseValid() bool {
+	return lsu.base.valid()
+}
+
+func (lsu *LinkStateUpdatePacket) Version() uint8 {
+	return lsu.base.version
+}
+
+func (lsu *LinkStateUpdatePacket) SetVersion(version uint8) error {
+	return lsu.base.setVersion(version)
+}
+
+func (lsu *LinkStateUpdatePacket) InstanceId() uint8 {
+	return lsu.base.InstanceId
+}
+
+func (lsu *LinkStateUpdatePacket) SetInstanceId(instanceId uint8) error {
+	lsu.base.InstanceId = instanceId
+	return nil
+}
+
+func (lsu *LinkStateUpdatePacket) RouterId() uint32 {
+	return lsu.base.RouterId
+}
+
+func (lsu *LinkStateUpdatePacket) SetRouterId(routerId uint32) error {
+	lsu.base.RouterId = routerId
+	return nil
+}
+
+func (lsu *LinkStateUpdatePacket) AreaId() uint32 {
+	return lsu.base.AreaId
+}
+
+func (lsu *LinkStateUpdatePacket) SetAreaId(areaId uint32) error {
+	lsu.base.AreaId = areaId
+	return nil
+}
+
+func (lsu *LinkStateUpdatePacket) Authentication() (AuType, [AUTHENTICATION_LENGTH]byte) {
+	return lsu.base.AuType, lsu.base.Authentication
+}
+
+func (lsu *LinkStateUpdatePacket) SetAuthentication(auType AuType, authentication [AUTHENTICATION_LENGTH]byte) error {
+	lsu.base.AuType = auType
+	lsu.base.Authentication = authentication
+	return nil
+}
@@ -0,0 +1,136 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package packet
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestLinkStateUpdatePacketNew(t *testing.T) {
+	var err error
+
+	p1, err := NewLinkStateUpdatePacket()
+	if err != nil {
+		t.Fatalf("failed NewLinkStateUpdatePacket: %#v", err)
+	}
+
+	router, err := NewRouterLsa()
+	if err != nil {
+		t.Fatalf("failed NewRouterLsa: %#v", err)
+	}
+	router.Border = true
+	router.AddLink(&RouterLink{
+		LinkId:   0x02020202,
+		LinkData: 0x0a000001,
+		Type:     LINK_TYPE_POINT_TO_POINT,
+		Metric:   10,
+		Tos:      []*TosMetric{&TosMetric{Tos: 8, Metric: 20}},
+	})
+	router.AddLink(&RouterLink{
+		LinkId:   0x0a000000,
+		LinkData: 0xfffffffc,
+		Type:     LINK_TYPE_STUB,
+		Metric:   10,
+		Tos:      []*TosMetric{},
+	})
+	p1.AddLsa(router)
+
+	network, err := NewNetworkLsa()
+	if err != nil {
+		t.Fatalf("failed NewNetworkLsa: %#v", err)
+	}
+	network.NetworkMask = 0xffffff00
+	network.AttachedRouters = append(network.AttachedRouters, 0x01010101, 0x02020202)
+	p1.AddLsa(network)
+
+	for _, lsType := range []LsType{LS_TYPE_SUMMARY_NETWORK, LS_TYPE_SUMMARY_ASBR} {
+		summary, err := NewSummaryLsa(lsType)
+		if err != nil {
+			t.Fatalf("failed NewSummaryLsa: %#v", err)
+		}
+		summary.NetworkMask = 0xffffff00
+		summary.Metric = LS_INFINITY
+		summary.Tos = append(summary.Tos, &TosMetric{Tos: 2, Metric: 0x123456})
+		p1.AddLsa(summary)
+	}
+
+	for _, lsType := range []LsType{LS_TYPE_AS_EXTERNAL, LS_TYPE_NSSA_EXTERNAL} {
+		external, err := NewExternalLsa(lsType)
+		if err != nil {
+			t.Fatalf("failed NewExternalLsa: %#v", err)
+		}
+		external.NetworkMask = 0xffff0000
+		external.ExternalMetric = true
+		external.Metric = 20
+		external.ForwardingAddress = 0x0a000002
+		external.ExternalRouteTag = 0xdeadbeef
+		external.Tos = append(external.Tos, &ExternalTos{Tos: 4, Metric: 30})
+		p1.AddLsa(external)
+	}
+
+	unknown, err := NewUnknownLsa(LsType(11))
+	if err != nil {
+		t.Fatalf("failed NewUnknownLsa: %#v", err)
+	}
+	p1.AddLsa(unknown)
+
+	for _, lsa := range p1.Lsas {
+		if err = SetLsaChecksum(lsa); err != nil {
+			t.Fatalf("failed SetLsaChecksum: %#v", err)
+		}
+	}
+
+	d1, err := p1.Serialize()
+	if err != nil {
+		t.Fatalf("failed Serialize: %#v", err)
+	}
+
+	p2, err := DecodePacketFromBytes(d1)
+	if err != nil {
+		t.Fatalf("failed DecodePacketFromBytes: %#v", err)
+	}
+	lsu, ok := p2.(*LinkStateUpdatePacket)
+	if !ok || len(lsu.Lsas) != len(p1.Lsas) {
+		t.Fatalf("failed LinkStateUpdatePacket:\n%s", p2.String())
+	}
+	for i, lsa := range lsu.Lsas {
+		if lsa.LsType() != p1.Lsas[i].LsType() {
+			t.Fatalf("failed LsType: %s", lsa.LsType())
+		}
+		if !LsaChecksumValid(lsa) {
+			t.Fatalf("failed LsaChecksumValid:\n%s", lsa.String())
+		}
+	}
+	if lsu.Lsas[0].(*RouterLsa).Links[0].Tos[0].Metric != 20 ||
+		lsu.Lsas[3].(*SummaryLsa).Metric != LS_INFINITY ||
+		lsu.Lsas[5].(*ExternalLsa).ExternalRouteTag != 0xdeadbeef {
+		t.Fatalf("failed LinkStateUpdatePacket:\n%s", p2.String())
+	}
+
+	d2, err := p2.Serialize()
+	if err != nil {
+		t.Fatalf("failed Serialize: %#v", err)
+	}
+
+	if !bytes.Equal(d1, d2) {
+		t.Fatalf("failed !bytes.Equal")
+	}
+
+	//t.Fatalf("\n%s", p2.String())
+}
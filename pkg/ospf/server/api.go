@@ -18,7 +18,10 @@
 package server
 
 import (
+	"errors"
+	"fmt"
 	"net"
+	"sort"
 	"strings"
 	"sync"
 
@@ -27,8 +30,9 @@ import (
 	"google.golang.org/grpc"
 
 	api "github.com/m-asama/golsr/api/ospf"
+	"github.com/m-asama/golsr/internal/pkg/ospf/config"
 	_ "github.com/m-asama/golsr/internal/pkg/util"
-	_ "github.com/m-asama/golsr/pkg/ospf/packet"
+	"github.com/m-asama/golsr/pkg/ospf/packet"
 )
 
 type ApiServer struct {
@@ -114,3 +118,492 @@ func (s *ApiServer) Disable(ctx context.Context, in *api.DisableRequest) (*api.D
 	}
 	return response, nil
 }
+
+// matchInterface tells whether the interface of the name is the one
+// requested, all of them if none or "all" is.
+func matchInterface(requested, name string) bool {
+	return requested == "" || requested == "all" || requested == name
+}
+
+func newApiInterface(iface *Interface) *api.Interface {
+	apiInterface := &api.Interface{
+		Name:          iface.name,
+		AreaId:        packet.Ipv4String(iface.area.areaId),
+		NetworkType:   iface.networkType().String(),
+		State:         iface.state.String(),
+		Cost:          uint32(iface.cost()),
+		Priority:      uint32(iface.priority()),
+		Dr:            packet.Ipv4String(iface.dr),
+		Bdr:           packet.Ipv4String(iface.bdr),
+		HelloInterval: uint32(iface.helloInterval()),
+		DeadInterval:  iface.deadInterval(),
+		Passive:       iface.passive(),
+		NeighborCount: uint32(len(iface.neighborDb)),
+
+		TtlSecurity:      iface.ttlSecurity(),
+		TtlSecurityDrops: iface.ttlSecurityDrops,
+	}
+	if iface.state != INTERFACE_STATE_DOWN {
+		if iface.ospf.version == packet.OSPFV3_VERSION {
+			apiInterface.Address = packet.Ipv6String(iface.linkLocal)
+		} else {
+			apiInterface.Address = fmt.Sprintf("%s/%d", packet.Ipv4String(iface.address), iface.prefixLength)
+		}
+	}
+	for _, nbr := range iface.neighborDb {
+		if nbr.state == NEIGHBOR_STATE_FULL {
+			apiInterface.AdjacencyCount++
+		}
+	}
+	return apiInterface
+}
+
+// apiInterfaces returns the interfaces requested other than the virtual
+// links.
+func (ospf *OspfServer) apiInterfaces(requested string) []*api.Interface {
+	interfaces := make([]*api.Interface, 0)
+	for _, iface := range ospf.interfaceDb {
+		if iface.virtualLink != nil || iface.ifConfig == nil || !matchInterface(requested, iface.name) {
+			continue
+		}
+		interfaces = append(interfaces, newApiInterface(iface))
+	}
+	return interfaces
+}
+
+func (s *ApiServer) InterfaceGet(ctx context.Context, in *api.InterfaceGetRequest) (*api.InterfaceGetResponse, error) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	ospf := s.ospfServer
+	ospf.lock.RLock()
+	defer ospf.lock.RUnlock()
+	response := &api.InterfaceGetResponse{
+		Interfaces: ospf.apiInterfaces(in.Interface),
+	}
+	return response, nil
+}
+
+func (s *ApiServer) InterfaceMonitor(in *api.InterfaceMonitorRequest, stream api.GoospfApi_InterfaceMonitorServer) error {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	ospf := s.ospfServer
+	ospf.lock.RLock()
+	defer ospf.lock.RUnlock()
+	for _, iface := range ospf.apiInterfaces(in.Interface) {
+		r := &api.InterfaceMonitorResponse{
+			Interfaces: []*api.Interface{iface},
+		}
+		if err := stream.Send(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newApiNeighbor(nbr *Neighbor) *api.Neighbor {
+	return &api.Neighbor{
+		Interface:           nbr.iface.name,
+		AreaId:              packet.Ipv4String(nbr.iface.area.areaId),
+		RouterId:            packet.Ipv4String(nbr.routerId),
+		Address:             nbr.src().String(),
+		Priority:            uint32(nbr.priority),
+		State:               nbr.state.String(),
+		Dr:                  packet.Ipv4String(nbr.dr),
+		Bdr:                 packet.Ipv4String(nbr.bdr),
+		DeadTimer:           uint32(nbr.inactivityTimer),
+		RetransmissionCount: uint32(len(nbr.lsRetransmissionList)),
+	}
+}
+
+// apiNeighbors returns the neighbors on the interface requested by
+// interface.
+func (ospf *OspfServer) apiNeighbors(requested string) [][]*api.Neighbor {
+	neighbors := make([][]*api.Neighbor, 0)
+	for _, iface := range ospf.interfaceDb {
+		if !matchInterface(requested, iface.name) || len(iface.neighborDb) == 0 {
+			continue
+		}
+		nbrs := make([]*api.Neighbor, 0)
+		for _, nbr := range iface.neighborDb {
+			nbrs = append(nbrs, newApiNeighbor(nbr))
+		}
+		neighbors = append(neighbors, nbrs)
+	}
+	return neighbors
+}
+
+func (s *ApiServer) NeighborGet(ctx context.Context, in *api.NeighborGetRequest) (*api.NeighborGetResponse, error) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	ospf := s.ospfServer
+	ospf.lock.RLock()
+	defer ospf.lock.RUnlock()
+	response := &api.NeighborGetResponse{
+		Neighbors: make([]*api.Neighbor, 0),
+	}
+	for _, nbrs := range ospf.apiNeighbors(in.Interface) {
+		response.Neighbors = append(response.Neighbors, nbrs...)
+	}
+	return response, nil
+}
+
+func (s *ApiServer) NeighborMonitor(in *api.NeighborMonitorRequest, stream api.GoospfApi_NeighborMonitorServer) error {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	ospf := s.ospfServer
+	ospf.lock.RLock()
+	defer ospf.lock.RUnlock()
+	for _, nbrs := range ospf.apiNeighbors(in.Interface) {
+		r := &api.NeighborMonitorResponse{
+			Neighbors: nbrs,
+		}
+		if err := stream.Send(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newApiVirtualLink(iface *Interface) *api.VirtualLink {
+	vlink := iface.virtualLink
+	apiVirtualLink := &api.VirtualLink{
+		TransitAreaId: packet.Ipv4String(vlink.transitAreaId),
+		RouterId:      packet.Ipv4String(vlink.routerId),
+		State:         iface.state.String(),
+		NeighborState: NEIGHBOR_STATE_DOWN.String(),
+
+		TtlSecurity:      iface.ttlSecurity(),
+		TtlSecurityDrops: iface.ttlSecurityDrops,
+	}
+	if iface.state == INTERFACE_STATE_DOWN {
+		return apiVirtualLink
+	}
+	apiVirtualLink.Address = packet.Ipv4String(iface.address)
+	apiVirtualLink.NeighborAddress = packet.Ipv4String(vlink.address)
+	apiVirtualLink.Cost = vlink.cost
+	if vlink.transitIface != nil {
+		apiVirtualLink.TransitInterface = vlink.transitIface.name
+	}
+	for _, nbr := range iface.neighborDb {
+		apiVirtualLink.NeighborState = nbr.state.String()
+	}
+	return apiVirtualLink
+}
+
+func (s *ApiServer) VirtualLinkGet(ctx context.Context, in *api.VirtualLinkGetRequest) (*api.VirtualLinkGetResponse, error) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	ospf := s.ospfServer
+	ospf.lock.RLock()
+	defer ospf.lock.RUnlock()
+	response := &api.VirtualLinkGetResponse{
+		VirtualLinks: make([]*api.VirtualLink, 0),
+	}
+	for _, iface := range ospf.interfaceDb {
+		if iface.virtualLink != nil {
+			response.VirtualLinks = append(response.VirtualLinks, newApiVirtualLink(iface))
+		}
+	}
+	return response, nil
+}
+
+func (s *ApiServer) StubRouterGet(ctx context.Context, in *api.StubRouterGetRequest) (*api.StubRouterGetResponse, error) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	ospf := s.ospfServer
+	ospf.lock.RLock()
+	defer ospf.lock.RUnlock()
+	stubRouter := &api.StubRouter{
+		Active:             ospf.stub.active,
+		Always:             *ospf.config.StubRouter.Config.Always,
+		OnDemand:           ospf.stub.onDemand,
+		OnStartupRemaining: ospf.stub.startup,
+	}
+	if ospf.config.StubRouter.Config.WaitFor != nil {
+		stubRouter.WaitFor = *ospf.config.StubRouter.Config.WaitFor
+	}
+	return &api.StubRouterGetResponse{StubRouter: stubRouter}, nil
+}
+
+func (s *ApiServer) StubRouterEnable(ctx context.Context, in *api.StubRouterEnableRequest) (*api.StubRouterEnableResponse, error) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	response := &api.StubRouterEnableResponse{}
+	if s.ospfServer.stubRouterOnDemand() {
+		response.Result = "already enabled"
+	} else {
+		s.ospfServer.SetStubRouter(true)
+		response.Result = "enabled"
+	}
+	return response, nil
+}
+
+func (s *ApiServer) StubRouterDisable(ctx context.Context, in *api.StubRouterDisableRequest) (*api.StubRouterDisableResponse, error) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	response := &api.StubRouterDisableResponse{}
+	if s.ospfServer.stubRouterOnDemand() {
+		s.ospfServer.SetStubRouter(false)
+		response.Result = "disabled"
+	} else {
+		response.Result = "already disabled"
+	}
+	return response, nil
+}
+
+// lsTypeName is the name of the LS type in the requests and the
+// responses of the API. The LSAs of OSPFv2 and OSPFv3 which play the
+// same role have the same name.
+func lsTypeName(lsType packet.LsType) string {
+	switch lsType {
+	case packet.LS_TYPE_ROUTER, packet.LS_TYPE_OSPFV3_ROUTER:
+		return "router"
+	case packet.LS_TYPE_NETWORK, packet.LS_TYPE_OSPFV3_NETWORK:
+		return "network"
+	case packet.LS_TYPE_SUMMARY_NETWORK:
+		return "summary-network"
+	case packet.LS_TYPE_SUMMARY_ASBR:
+		return "summary-asbr"
+	case packet.LS_TYPE_AS_EXTERNAL, packet.LS_TYPE_OSPFV3_AS_EXTERNAL:
+		return "as-external"
+	case packet.LS_TYPE_NSSA_EXTERNAL, packet.LS_TYPE_OSPFV3_NSSA_EXTERNAL:
+		return "nssa-external"
+	case packet.LS_TYPE_INTER_AREA_PREFIX:
+		return "inter-area-prefix"
+	case packet.LS_TYPE_INTER_AREA_ROUTER:
+		return "inter-area-router"
+	case packet.LS_TYPE_LINK:
+		return "link"
+	case packet.LS_TYPE_INTRA_AREA_PREFIX:
+		return "intra-area-prefix"
+	}
+	return fmt.Sprintf("0x%04x", uint16(lsType))
+}
+
+func newApiLsa(lsdb *Lsdb, lsa packet.OspfLsa) *api.Lsa {
+	header := lsa.Header()
+	apiLsa := &api.Lsa{
+		LsType:            lsTypeName(header.LsType()),
+		LinkStateId:       packet.Ipv4String(header.LinkStateId),
+		AdvertisingRouter: packet.Ipv4String(header.AdvertisingRouter),
+		Age:               uint32(header.LsAge),
+		SequenceNumber:    uint32(header.LsSequenceNumber),
+		Checksum:          uint32(header.LsChecksum),
+		Length:            uint32(header.Length()),
+		Detail:            lsa.String(),
+	}
+	if lsdb.area != nil {
+		apiLsa.AreaId = packet.Ipv4String(lsdb.area.areaId)
+	}
+	if lsdb.iface != nil {
+		apiLsa.Interface = lsdb.iface.name
+	}
+	apiLsa.Binary, _ = lsa.Serialize()
+	return apiLsa
+}
+
+// apiLsdbs returns the LSAs of the LS type requested in the LSDBs of the
+// area requested, of the area and of the links in it, or else of all the
+// areas, the links and the AS.
+func (ospf *OspfServer) apiLsdbs(areaId, lsType string) ([][]*api.Lsa, error) {
+	lsdbs := make([]*Lsdb, 0)
+	all := areaId == "" || areaId == "all"
+	var id uint32
+	if !all {
+		var err error
+		id, err = config.ParseAreaId(areaId)
+		if err != nil {
+			return nil, err
+		}
+		if ospf.findArea(id) == nil {
+			return nil, errors.New("area " + areaId + " not found")
+		}
+	}
+	for _, area := range ospf.areaDb {
+		if !all && area.areaId != id {
+			continue
+		}
+		lsdbs = append(lsdbs, area.lsdb)
+		for _, iface := range area.interfaces() {
+			if ospf.version == packet.OSPFV3_VERSION && iface.virtualLink == nil {
+				lsdbs = append(lsdbs, iface.lsdb)
+			}
+		}
+	}
+	if all {
+		lsdbs = append(lsdbs, ospf.externalLsdb)
+	}
+	lsas := make([][]*api.Lsa, 0)
+	for _, lsdb := range lsdbs {
+		tmp := make([]*api.Lsa, 0)
+		for _, key := range lsdb.keys() {
+			lsa := lsdb.lookup(key)
+			if lsa == nil {
+				continue
+			}
+			if lsType != "" && lsType != "all" && lsTypeName(key.LsType) != lsType {
+				continue
+			}
+			tmp = append(tmp, newApiLsa(lsdb, lsa))
+		}
+		lsas = append(lsas, tmp)
+	}
+	return lsas, nil
+}
+
+func (s *ApiServer) DbLsGet(ctx context.Context, in *api.DbLsGetRequest) (*api.DbLsGetResponse, error) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	ospf := s.ospfServer
+	ospf.lock.RLock()
+	defer ospf.lock.RUnlock()
+	lsdbs, err := ospf.apiLsdbs(in.AreaId, in.LsType)
+	if err != nil {
+		return nil, err
+	}
+	response := &api.DbLsGetResponse{
+		Lsas: make([]*api.Lsa, 0),
+	}
+	for _, lsas := range lsdbs {
+		response.Lsas = append(response.Lsas, lsas...)
+	}
+	return response, nil
+}
+
+func (s *ApiServer) DbLsMonitor(in *api.DbLsMonitorRequest, stream api.GoospfApi_DbLsMonitorServer) error {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	ospf := s.ospfServer
+	ospf.lock.RLock()
+	defer ospf.lock.RUnlock()
+	lsdbs, err := ospf.apiLsdbs(in.AreaId, in.LsType)
+	if err != nil {
+		return err
+	}
+	for _, lsas := range lsdbs {
+		if len(lsas) == 0 {
+			continue
+		}
+		r := &api.DbLsMonitorResponse{
+			Lsas: lsas,
+		}
+		if err := stream.Send(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newApiIpv4Route(ri *Ipv4Ri) *api.Route {
+	apiRoute := &api.Route{
+		AddressFamily: "ipv4",
+		Prefix:        fmt.Sprintf("%s/%d", packet.Ipv4String(ri.prefixAddress), ri.prefixLength),
+		RouteType:     ri.routeType.String(),
+		AreaId:        packet.Ipv4String(ri.areaId),
+		Metric:        ri.metric,
+		Type2Metric:   ri.type2Metric,
+		NextHops:      make([]*api.NextHop, 0),
+	}
+	for _, nh := range ri.nexthops {
+		apiNh := &api.NextHop{}
+		if nh.nexthopInterface != nil {
+			apiNh.OutgoingInterface = nh.nexthopInterface.name
+		}
+		if nh.nexthopAddress != 0 {
+			apiNh.NextHop = packet.Ipv4String(nh.nexthopAddress)
+		}
+		apiRoute.NextHops = append(apiRoute.NextHops, apiNh)
+	}
+	return apiRoute
+}
+
+func newApiIpv6Route(ri *Ipv6Ri) *api.Route {
+	apiRoute := &api.Route{
+		AddressFamily: "ipv6",
+		Prefix:        fmt.Sprintf("%s/%d", packet.Ipv6String(ri.prefixAddress), ri.prefixLength),
+		RouteType:     ri.routeType.String(),
+		AreaId:        packet.Ipv4String(ri.areaId),
+		Metric:        ri.metric,
+		Type2Metric:   ri.type2Metric,
+		NextHops:      make([]*api.NextHop, 0),
+	}
+	for _, nh := range ri.nexthops {
+		apiNh := &api.NextHop{}
+		if nh.nexthopInterface != nil {
+			apiNh.OutgoingInterface = nh.nexthopInterface.name
+		}
+		if nh.nexthopAddress != [4]uint32{} {
+			apiNh.NextHop = packet.Ipv6String(nh.nexthopAddress)
+		}
+		apiRoute.NextHops = append(apiRoute.NextHops, apiNh)
+	}
+	return apiRoute
+}
+
+// apiRoutes returns the routes of the address family requested, of both
+// if none or "all" is, in the order of their prefixes.
+func (ospf *OspfServer) apiRoutes(addressFamily string) []*api.Route {
+	routes := make([]*api.Route, 0)
+	if addressFamily == "" || addressFamily == "all" || addressFamily == "ipv4" {
+		keys := make([]Ipv4RiKey, 0, len(ospf.ipv4RiDb))
+		for key := range ospf.ipv4RiDb {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if keys[i].prefixAddress != keys[j].prefixAddress {
+				return keys[i].prefixAddress < keys[j].prefixAddress
+			}
+			return keys[i].prefixLength < keys[j].prefixLength
+		})
+		for _, key := range keys {
+			routes = append(routes, newApiIpv4Route(ospf.ipv4RiDb[key]))
+		}
+	}
+	if addressFamily == "" || addressFamily == "all" || addressFamily == "ipv6" {
+		keys := make([]Ipv6RiKey, 0, len(ospf.ipv6RiDb))
+		for key := range ospf.ipv6RiDb {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			for k := 0; k < 4; k++ {
+				if keys[i].prefixAddress[k] != keys[j].prefixAddress[k] {
+					return keys[i].prefixAddress[k] < keys[j].prefixAddress[k]
+				}
+			}
+			return keys[i].prefixLength < keys[j].prefixLength
+		})
+		for _, key := range keys {
+			routes = append(routes, newApiIpv6Route(ospf.ipv6RiDb[key]))
+		}
+	}
+	return routes
+}
+
+func (s *ApiServer) DbRiGet(ctx context.Context, in *api.DbRiGetRequest) (*api.DbRiGetResponse, error) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	ospf := s.ospfServer
+	ospf.lock.RLock()
+	defer ospf.lock.RUnlock()
+	response := &api.DbRiGetResponse{
+		Routes: ospf.apiRoutes(in.AddressFamily),
+	}
+	return response, nil
+}
+
+func (s *ApiServer) DbRiMonitor(in *api.DbRiMonitorRequest, stream api.GoospfApi_DbRiMonitorServer) error {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	ospf := s.ospfServer
+	ospf.lock.RLock()
+	defer ospf.lock.RUnlock()
+	for _, route := range ospf.apiRoutes(in.AddressFamily) {
+		r := &api.DbRiMonitorResponse{
+			Routes: []*api.Route{route},
+		}
+		if err := stream.Send(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
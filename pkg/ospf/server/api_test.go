@@ -0,0 +1,104 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"testing"
+
+	"golang.org/x/net/context"
+
+	api "github.com/m-asama/golsr/api/ospf"
+	"github.com/m-asama/golsr/internal/pkg/kernel"
+)
+
+func TestApi(t *testing.T) {
+	network := NewMemoryNetwork()
+	r1 := startTestRouter(t, network, "r1", 0x01010101, []*testPort{
+		&testPort{segment: "l", name: "eth0", address: 0x0a000001, ifType: kernel.IF_TYPE_POINTTOPOINT, priority: 1},
+		&testPort{name: "lo", address: 0x01010101, ifType: kernel.IF_TYPE_LOOPBACK},
+	})
+	defer r1.stop()
+	r2 := startTestRouter(t, network, "r2", 0x02020202, []*testPort{
+		&testPort{segment: "l", name: "eth0", address: 0x0a000002, ifType: kernel.IF_TYPE_POINTTOPOINT, priority: 1},
+		&testPort{name: "lo", address: 0x02020202, ifType: kernel.IF_TYPE_LOOPBACK},
+	})
+	defer r2.stop()
+	waitFor(t, "route", func() bool {
+		return r1.fibRoute(0x02020202, 32) != nil
+	})
+
+	s := &ApiServer{ospfServer: r1.ospf}
+	ctx := context.Background()
+
+	interfaces, _ := s.InterfaceGet(ctx, &api.InterfaceGetRequest{Interface: "eth0"})
+	if len(interfaces.Interfaces) != 1 {
+		t.Fatalf("failed InterfaceGet: %v", interfaces.Interfaces)
+	}
+	iface := interfaces.Interfaces[0]
+	if iface.Name != "eth0" || iface.AreaId != "0.0.0.0" || iface.Address != "10.0.0.1/24" ||
+		iface.Cost != 10 || iface.AdjacencyCount != 1 {
+		t.Fatalf("failed InterfaceGet: %v", iface)
+	}
+	interfaces, _ = s.InterfaceGet(ctx, &api.InterfaceGetRequest{})
+	if len(interfaces.Interfaces) != 2 {
+		t.Fatalf("failed InterfaceGet: all %v", interfaces.Interfaces)
+	}
+
+	neighbors, _ := s.NeighborGet(ctx, &api.NeighborGetRequest{Interface: "all"})
+	if len(neighbors.Neighbors) != 1 {
+		t.Fatalf("failed NeighborGet: %v", neighbors.Neighbors)
+	}
+	nbr := neighbors.Neighbors[0]
+	if nbr.RouterId != "2.2.2.2" || nbr.Address != "10.0.0.2" || nbr.State != NEIGHBOR_STATE_FULL.String() {
+		t.Fatalf("failed NeighborGet: %v", nbr)
+	}
+
+	lsas, err := s.DbLsGet(ctx, &api.DbLsGetRequest{AreaId: "0.0.0.0", LsType: "router"})
+	if err != nil || len(lsas.Lsas) != 2 {
+		t.Fatalf("failed DbLsGet: %v %v", lsas, err)
+	}
+	for _, lsa := range lsas.Lsas {
+		if lsa.LsType != "router" || lsa.AreaId != "0.0.0.0" || lsa.LinkStateId != lsa.AdvertisingRouter {
+			t.Fatalf("failed DbLsGet: %v", lsa)
+		}
+	}
+	lsas, _ = s.DbLsGet(ctx, &api.DbLsGetRequest{LsType: "network"})
+	if len(lsas.Lsas) != 0 {
+		t.Fatalf("failed DbLsGet: network %v", lsas.Lsas)
+	}
+	if _, err = s.DbLsGet(ctx, &api.DbLsGetRequest{AreaId: "0.0.0.9"}); err == nil {
+		t.Fatalf("failed DbLsGet: unknown area accepted")
+	}
+
+	routes, _ := s.DbRiGet(ctx, &api.DbRiGetRequest{AddressFamily: "ipv4"})
+	var route *api.Route
+	for _, r := range routes.Routes {
+		if r.Prefix == "2.2.2.2/32" {
+			route = r
+		}
+	}
+	if route == nil || route.RouteType != ROUTE_TYPE_INTRA_AREA.String() || route.Metric != 10 ||
+		len(route.NextHops) != 1 || route.NextHops[0].OutgoingInterface != "eth0" ||
+		route.NextHops[0].NextHop != "10.0.0.2" {
+		t.Fatalf("failed DbRiGet: %v", route)
+	}
+	routes, _ = s.DbRiGet(ctx, &api.DbRiGetRequest{AddressFamily: "ipv6"})
+	if len(routes.Routes) != 0 {
+		t.Fatalf("failed DbRiGet: ipv6 %v", routes.Routes)
+	}
+}
@@ -0,0 +1,306 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	log "github.com/sirupsen/logrus"
+
+	"github.com/m-asama/golsr/internal/pkg/ospf/config"
+	"github.com/m-asama/golsr/pkg/ospf/packet"
+)
+
+type Area struct {
+	ospf       *OspfServer
+	areaId     uint32
+	areaConfig *config.Area
+
+	lsdb       *Lsdb
+	routerRiDb map[uint32]*RouterRi
+	// transitCapability is set if the area carries the traffic of a
+	// virtual link, a router-LSA of it having the V-bit set.
+	transitCapability bool
+}
+
+func NewArea(ospf *OspfServer, areaId uint32, areaConfig *config.Area) *Area {
+	log.Debugf("enter: %s", packet.Ipv4String(areaId))
+	defer log.Debugf("exit: %s", packet.Ipv4String(areaId))
+	area := &Area{
+		ospf:       ospf,
+		areaId:     areaId,
+		areaConfig: areaConfig,
+		routerRiDb: make(map[uint32]*RouterRi),
+	}
+	area.lsdb = NewLsdb(ospf, area, nil)
+	return area
+}
+
+func (area *Area) areaType() AreaType {
+	switch *area.areaConfig.Config.AreaType {
+	case "stub":
+		return AREA_TYPE_STUB
+	case "nssa":
+		return AREA_TYPE_NSSA
+	}
+	return AREA_TYPE_NORMAL
+}
+
+// summary tells whether summary-LSAs other than the default are
+// originated into the area if it is a stub area or an NSSA. A stub area
+// without them is totally stubby.
+func (area *Area) summary() bool {
+	return *area.areaConfig.Config.Summary
+}
+
+func (area *Area) defaultCost() uint32 {
+	return *area.areaConfig.Config.DefaultCost
+}
+
+// externalCapable tells whether the AS-external-LSAs are flooded into the
+// area, that is whether it is neither a stub area nor an NSSA.
+func (area *Area) externalCapable() bool {
+	return area.areaType() == AREA_TYPE_NORMAL
+}
+
+// options are the options the router sends in the packets and LSAs of
+// the area. The E-bit tells the area is external capable and the N-bit
+// it is an NSSA.
+func (area *Area) options() packet.Options {
+	options := packet.Options(0)
+	switch area.areaType() {
+	case AREA_TYPE_NORMAL:
+		options |= packet.OPTIONS_E
+	case AREA_TYPE_NSSA:
+		options |= packet.OPTIONS_NP
+	}
+	if area.ospf.version == packet.OSPFV3_VERSION {
+		options |= packet.OPTIONS_V6 | packet.OPTIONS_R
+	}
+	return options
+}
+
+func (area *Area) interfaces() []*Interface {
+	interfaces := make([]*Interface, 0)
+	for _, iface := range area.ospf.interfaceDb {
+		if iface.area == area {
+			interfaces = append(interfaces, iface)
+		}
+	}
+	return interfaces
+}
+
+// active tells whether the router has an interface up in the area.
+func (area *Area) active() bool {
+	for _, iface := range area.interfaces() {
+		if iface.state != INTERFACE_STATE_DOWN {
+			return true
+		}
+	}
+	return false
+}
+
+// areaBorder tells whether the router is an area border router, that is
+// attached to more than one area.
+func (ospf *OspfServer) areaBorder() bool {
+	active := 0
+	for _, area := range ospf.areaDb {
+		if area.active() {
+			active++
+		}
+	}
+	return active > 1
+}
+
+// areaRange is an address range of an area. The intra-area routes of the
+// area it contains are advertised to the other areas as a single
+// summary, or not at all, as in RFC 2328 12.4.3.
+type areaRange struct {
+	prefixAddress  uint32
+	prefixAddress6 [4]uint32
+	prefixLength   uint8
+	advertise      bool
+	cost           *uint32
+}
+
+func (area *Area) ranges() []*areaRange {
+	ranges := make([]*areaRange, 0)
+	for _, rangeConfig := range area.areaConfig.Ranges {
+		r := &areaRange{
+			advertise: *rangeConfig.Config.Advertise,
+			cost:      rangeConfig.Config.Cost,
+		}
+		var err error
+		if area.ospf.version == packet.OSPFV3_VERSION {
+			r.prefixAddress6, r.prefixLength, err = config.ParseIpv6Prefix(*rangeConfig.Config.Prefix)
+		} else {
+			r.prefixAddress, r.prefixLength, err = config.ParseIpv4Prefix(*rangeConfig.Config.Prefix)
+		}
+		if err == nil {
+			ranges = append(ranges, r)
+		}
+	}
+	return ranges
+}
+
+// ipv4Range returns the range of the area which contains the prefix, or
+// nil if none does.
+func (area *Area) ipv4Range(prefixAddress uint32, prefixLength uint8) *areaRange {
+	for _, r := range area.ranges() {
+		if prefixLength >= r.prefixLength && prefixAddress&maskOf(r.prefixLength) == r.prefixAddress {
+			return r
+		}
+	}
+	return nil
+}
+
+// rangeSummary is the summary of a range advertised: the range and the
+// highest cost of the routes it contains.
+type rangeSummary struct {
+	r      *areaRange
+	metric uint32
+}
+
+func (summary *rangeSummary) cost() uint32 {
+	if summary.r.cost != nil {
+		return *summary.r.cost
+	}
+	return summary.metric
+}
+
+// summaries returns the summary-LSAs the router originates into the area
+// as an area border router as in RFC 2328 12.4.3. A stub area and an NSSA
+// get a default summary, and nothing else if they are totally stubby, and
+// no ASBR-summaries.
+func (ospf *OspfServer) summaries(area *Area) map[packet.LsaKey]packet.OspfLsa {
+	lsas := make(map[packet.LsaKey]packet.OspfLsa)
+	if !ospf.areaBorder() || !area.active() {
+		return lsas
+	}
+	// RFC 2328 12.4.3.1 and RFC 3101 2.7
+	if area.areaType() == AREA_TYPE_STUB || (area.areaType() == AREA_TYPE_NSSA && !area.summary()) {
+		lsa := ospf.newDefaultSummary(area)
+		lsas[lsa.Header().Key()] = lsa
+	}
+	if !area.externalCapable() && !area.summary() {
+		return lsas
+	}
+	if ospf.version == packet.OSPFV3_VERSION {
+		ospf.ospfv3Summaries(area, lsas)
+		return lsas
+	}
+	addSummary := func(prefixAddress uint32, prefixLength uint8, metric uint32) {
+		lsa, _ := packet.NewSummaryLsa(packet.LS_TYPE_SUMMARY_NETWORK)
+		lsa.Header().LinkStateId = prefixAddress
+		lsa.Header().AdvertisingRouter = ospf.routerId
+		lsa.Header().Options = area.options()
+		lsa.NetworkMask = maskOf(prefixLength)
+		lsa.Metric = metric
+		lsas[lsa.Header().Key()] = lsa
+	}
+	ranges := make(map[Ipv4RiKey]*rangeSummary)
+	for _, ri := range ospf.ipv4RiDb {
+		if ri.routeType != ROUTE_TYPE_INTRA_AREA && ri.routeType != ROUTE_TYPE_INTER_AREA {
+			continue
+		}
+		if ri.areaId == area.areaId || ri.metric >= packet.LS_INFINITY {
+			continue
+		}
+		if origin := ospf.findArea(ri.areaId); origin != nil && ri.routeType == ROUTE_TYPE_INTRA_AREA {
+			if r := origin.ipv4Range(ri.prefixAddress, ri.prefixLength); r != nil {
+				key := Ipv4RiKey{r.prefixAddress, r.prefixLength}
+				if summary := ranges[key]; summary == nil {
+					ranges[key] = &rangeSummary{r: r, metric: ri.metric}
+				} else if ri.metric > summary.metric {
+					summary.metric = ri.metric
+				}
+				continue
+			}
+		}
+		addSummary(ri.prefixAddress, ri.prefixLength, ri.metric)
+	}
+	for key, summary := range ranges {
+		if summary.r.advertise {
+			addSummary(key.prefixAddress, key.prefixLength, summary.cost())
+		}
+	}
+	if !area.externalCapable() {
+		return lsas
+	}
+	for _, ri := range ospf.asbrRiDb {
+		if ri.areaId == area.areaId || ri.metric >= packet.LS_INFINITY {
+			continue
+		}
+		// the AS boundary routers of an NSSA are reached through the
+		// translators
+		if origin := ospf.findArea(ri.areaId); origin != nil && origin.areaType() == AREA_TYPE_NSSA {
+			continue
+		}
+		lsa, _ := packet.NewSummaryLsa(packet.LS_TYPE_SUMMARY_ASBR)
+		lsa.Header().LinkStateId = ri.routerId
+		lsa.Header().AdvertisingRouter = ospf.routerId
+		lsa.Header().Options = area.options()
+		lsa.Metric = ri.metric
+		lsas[lsa.Header().Key()] = lsa
+	}
+	return lsas
+}
+
+// newDefaultSummary returns the summary of the default route the router
+// originates into a stub area or an NSSA.
+func (ospf *OspfServer) newDefaultSummary(area *Area) packet.OspfLsa {
+	if ospf.version == packet.OSPFV3_VERSION {
+		lsa, _ := packet.NewInterAreaPrefixLsa()
+		lsa.Header().LinkStateId = ospf.prefixId(Ipv6RiKey{})
+		lsa.Header().AdvertisingRouter = ospf.routerId
+		lsa.Metric = area.defaultCost()
+		return lsa
+	}
+	lsa, _ := packet.NewSummaryLsa(packet.LS_TYPE_SUMMARY_NETWORK)
+	lsa.Header().LinkStateId = 0
+	lsa.Header().AdvertisingRouter = ospf.routerId
+	lsa.Header().Options = area.options()
+	lsa.NetworkMask = 0
+	lsa.Metric = area.defaultCost()
+	return lsa
+}
+
+// originateSummaries brings the summary-LSAs the router originates in
+// line with the routing table.
+func (ospf *OspfServer) originateSummaries() {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	if ospf.routerId == 0 {
+		return
+	}
+	for _, area := range ospf.areaDb {
+		lsas := ospf.summaries(area)
+		for _, key := range area.lsdb.keys() {
+			switch key.LsType {
+			case packet.LS_TYPE_SUMMARY_NETWORK, packet.LS_TYPE_SUMMARY_ASBR,
+				packet.LS_TYPE_INTER_AREA_PREFIX, packet.LS_TYPE_INTER_AREA_ROUTER:
+			default:
+				continue
+			}
+			if _, ok := lsas[key]; !ok && key.AdvertisingRouter == ospf.routerId {
+				area.flushLsa(key)
+			}
+		}
+		for _, key := range sortedLsaKeys(lsas) {
+			area.originateLsa(lsas[key])
+		}
+	}
+}
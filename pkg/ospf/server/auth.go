@@ -0,0 +1,361 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/binary"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/m-asama/golsr/internal/pkg/ospf/config"
+	"github.com/m-asama/golsr/pkg/ospf/packet"
+)
+
+// authKey is a key the packets of an interface are authenticated with.
+// A key without algorithm is a simple password.
+type authKey struct {
+	algorithm packet.CryptoAlgorithm
+	keyId     uint32
+	key       []byte
+}
+
+func newAuthKey(algorithm string, keyId uint32, key string) *authKey {
+	authKey := &authKey{
+		keyId: keyId,
+		key:   []byte(key),
+	}
+	switch algorithm {
+	case "md5":
+		authKey.algorithm = packet.CRYPTO_ALGORITHM_MD5
+	case "hmac-sha-1":
+		authKey.algorithm = packet.CRYPTO_ALGORITHM_HMAC_SHA_1
+	case "hmac-sha-256":
+		authKey.algorithm = packet.CRYPTO_ALGORITHM_HMAC_SHA_256
+	case "hmac-sha-384":
+		authKey.algorithm = packet.CRYPTO_ALGORITHM_HMAC_SHA_384
+	case "hmac-sha-512":
+		authKey.algorithm = packet.CRYPTO_ALGORITHM_HMAC_SHA_512
+	}
+	return authKey
+}
+
+func (key *authKey) simple() bool {
+	return key.algorithm == 0
+}
+
+// keyLifetime tells whether now is within the lifetime from start to
+// end. An undefined start or end leaves the lifetime open.
+func keyLifetime(start, end *string, now time.Time) bool {
+	if start != nil {
+		if t, err := config.ParseKeyTime(*start); err != nil || now.Before(t) {
+			return false
+		}
+	}
+	if end != nil {
+		if t, err := config.ParseKeyTime(*end); err != nil || !now.Before(t) {
+			return false
+		}
+	}
+	return true
+}
+
+func (ospf *OspfServer) findKeyChain(name string) *config.KeyChain {
+	for _, keyChain := range ospf.config.KeyChains {
+		if *keyChain.Config.Name == name {
+			return keyChain
+		}
+	}
+	return nil
+}
+
+// authentication returns the key chain or the key the interface is
+// configured with for the OSPF version, neither for null
+// authentication.
+func (iface *Interface) authentication() (*config.KeyChain, *authKey) {
+	c := &iface.ifConfig.Authentication.Config
+	if iface.ospf.version == packet.OSPFV3_VERSION {
+		if c.Ospfv3KeyChain != nil {
+			return iface.ospf.findKeyChain(*c.Ospfv3KeyChain), nil
+		}
+		if c.Ospfv3Key != nil && c.Ospfv3CryptoAlgorithm != nil && c.Ospfv3SaId != nil {
+			return nil, newAuthKey(*c.Ospfv3CryptoAlgorithm, uint32(*c.Ospfv3SaId), *c.Ospfv3Key)
+		}
+		return nil, nil
+	}
+	if c.Ospfv2KeyChain != nil {
+		return iface.ospf.findKeyChain(*c.Ospfv2KeyChain), nil
+	}
+	if c.Ospfv2Key != nil && c.Ospfv2CryptoAlgorithm != nil {
+		keyId := uint32(0)
+		if c.Ospfv2KeyId != nil {
+			keyId = *c.Ospfv2KeyId
+		}
+		return nil, newAuthKey(*c.Ospfv2CryptoAlgorithm, keyId, *c.Ospfv2Key)
+	}
+	return nil, nil
+}
+
+// authenticated tells whether the packets of the interface are
+// authenticated.
+func (iface *Interface) authenticated() bool {
+	keyChain, key := iface.authentication()
+	return keyChain != nil || key != nil
+}
+
+// cryptographic tells whether the packets of the interface are
+// authenticated with a message digest, and carry sequence numbers.
+func (iface *Interface) cryptographic() bool {
+	keyChain, key := iface.authentication()
+	return keyChain != nil || (key != nil && !key.simple())
+}
+
+// options are the options the interface sends in the hellos and the
+// database descriptions, with the AT-bit of RFC 7166 if the packets of
+// an OSPFv3 interface carry the authentication trailer.
+func (iface *Interface) options() packet.Options {
+	options := iface.area.options()
+	if iface.ospf.version == packet.OSPFV3_VERSION && iface.authenticated() {
+		options |= packet.OPTIONS_AT
+	}
+	return options
+}
+
+// extended tells whether the packets of the interface carry the 64 bits
+// sequence numbers of RFC 7474.
+func (iface *Interface) extended() bool {
+	return iface.ospf.version == packet.OSPF_VERSION &&
+		*iface.ifConfig.Authentication.Config.Ospfv2AuthTrailerRfc == "rfc7474"
+}
+
+// sendKey returns the key the interface sends the packets with. Of the
+// keys of a key chain whose send lifetime has begun, the one which began
+// last is used, of the highest key ID if several did at once.
+func (iface *Interface) sendKey(now time.Time) *authKey {
+	keyChain, key := iface.authentication()
+	if keyChain == nil {
+		return key
+	}
+	var latest time.Time
+	for _, k := range keyChain.Keys {
+		c := &k.Config
+		if !keyLifetime(c.SendStartTime, c.SendEndTime, now) {
+			continue
+		}
+		var start time.Time
+		if c.SendStartTime != nil {
+			start, _ = config.ParseKeyTime(*c.SendStartTime)
+		}
+		if key != nil && (start.Before(latest) || (start.Equal(latest) && *c.KeyId < key.keyId)) {
+			continue
+		}
+		key = newAuthKey(*c.CryptoAlgorithm, *c.KeyId, *c.KeyString)
+		latest = start
+	}
+	return key
+}
+
+// acceptKey returns the key of keyId the interface accepts the packets
+// with, nil if there is none.
+func (iface *Interface) acceptKey(keyId uint32, now time.Time) *authKey {
+	keyChain, key := iface.authentication()
+	if keyChain == nil {
+		if key != nil && (key.simple() || key.keyId == keyId) {
+			return key
+		}
+		return nil
+	}
+	for _, k := range keyChain.Keys {
+		c := &k.Config
+		if *c.KeyId == keyId && keyLifetime(c.AcceptStartTime, c.AcceptEndTime, now) {
+			return newAuthKey(*c.CryptoAlgorithm, *c.KeyId, *c.KeyString)
+		}
+	}
+	return nil
+}
+
+// authLength is the length of the authentication data appended to the
+// packets the interface sends.
+func (iface *Interface) authLength() int {
+	key := iface.sendKey(time.Now())
+	if key == nil || key.simple() {
+		return 0
+	}
+	if iface.ospf.version == packet.OSPFV3_VERSION {
+		return packet.OSPFV3_AUTH_TRAILER_LENGTH + key.algorithm.DigestLength()
+	}
+	if iface.extended() {
+		return 4 + key.algorithm.DigestLength()
+	}
+	return key.algorithm.DigestLength()
+}
+
+// nextCryptoSeqNum returns the cryptographic sequence number of the next
+// packet sent. The high-order 32 bits are the time the router started,
+// as the boot count of RFC 7474, and the low-order 32 bits count the
+// packets sent since.
+func (ospf *OspfServer) nextCryptoSeqNum() uint64 {
+	ospf.cryptoSeqNum++
+	return ospf.cryptoSeqNum
+}
+
+// signPacket serializes the packet and authenticates it with the key as
+// in RFC 2328 D.4 and RFC 5709, or RFC 7474, for OSPFv2 and appends the
+// authentication trailer of RFC 7166 for OSPFv3.
+func (iface *Interface) signPacket(pkt packet.OspfPacket) ([]byte, error) {
+	key := iface.sendKey(time.Now())
+	if iface.ospf.version == packet.OSPFV3_VERSION {
+		data, err := pkt.Serialize()
+		if err != nil || key == nil {
+			return data, err
+		}
+		trailer := &packet.Ospfv3AuthTrailer{
+			SaId:           uint16(key.keyId),
+			SequenceNumber: iface.ospf.nextCryptoSeqNum(),
+		}
+		b, err := trailer.Sign(key.algorithm, key.key, data, iface.linkLocal)
+		if err != nil {
+			return nil, err
+		}
+		return append(data, b...), nil
+	}
+	var authentication [packet.AUTHENTICATION_LENGTH]byte
+	if key == nil {
+		pkt.SetAuthentication(packet.AU_TYPE_NULL, authentication)
+		return pkt.Serialize()
+	}
+	if key.simple() {
+		copy(authentication[:], key.key)
+		pkt.SetAuthentication(packet.AU_TYPE_SIMPLE, authentication)
+		return pkt.Serialize()
+	}
+	extended := iface.extended()
+	seqNum := iface.ospf.nextCryptoSeqNum()
+	seqNum32 := uint32(seqNum>>32) + uint32(seqNum)
+	if extended {
+		seqNum32 = uint32(seqNum >> 32)
+	}
+	authentication = packet.Ospfv2CryptoAuthentication(uint8(key.keyId), key.algorithm.DigestLength(), seqNum32)
+	pkt.SetAuthentication(packet.AU_TYPE_CRYPTOGRAPHIC, authentication)
+	data, err := pkt.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	if extended {
+		var low [4]byte
+		binary.BigEndian.PutUint32(low[:], uint32(seqNum))
+		data = append(data, low[:]...)
+	}
+	digest, err := packet.Ospfv2Digest(key.algorithm, key.key, data, iface.address, extended)
+	if err != nil {
+		return nil, err
+	}
+	return append(data, digest...), nil
+}
+
+// verifyPacket checks the authentication of the packet received from src
+// in data and returns its cryptographic sequence number, 0 if it has
+// none.
+func (iface *Interface) verifyPacket(pkt packet.OspfPacket, data []byte, src Address) (uint64, bool) {
+	now := time.Now()
+	packetLength := int(binary.BigEndian.Uint16(data[2:4]))
+	if iface.ospf.version == packet.OSPFV3_VERSION {
+		if !iface.authenticated() {
+			return 0, true
+		}
+		// RFC 7166 4.2: the hellos and database descriptions tell the
+		// trailer with the AT-bit
+		switch p := pkt.(type) {
+		case *packet.HelloPacket:
+			if p.Options&packet.OPTIONS_AT == 0 {
+				return 0, false
+			}
+		case *packet.DatabaseDescriptionPacket:
+			if p.Options&packet.OPTIONS_AT == 0 {
+				return 0, false
+			}
+		}
+		trailer, err := packet.DecodeOspfv3AuthTrailer(data[packetLength:])
+		if err != nil {
+			return 0, false
+		}
+		key := iface.acceptKey(uint32(trailer.SaId), now)
+		if key == nil || !trailer.Valid(key.algorithm, key.key, data[:packetLength], src.Ipv6) {
+			return 0, false
+		}
+		return trailer.SequenceNumber, true
+	}
+	auType, authentication := pkt.Authentication()
+	keyChain, key := iface.authentication()
+	if keyChain == nil && key == nil {
+		return 0, auType == packet.AU_TYPE_NULL
+	}
+	if keyChain == nil && key.simple() {
+		var password [packet.AUTHENTICATION_LENGTH]byte
+		copy(password[:], key.key)
+		return 0, auType == packet.AU_TYPE_SIMPLE &&
+			subtle.ConstantTimeCompare(password[:], authentication[:]) == 1
+	}
+	if auType != packet.AU_TYPE_CRYPTOGRAPHIC {
+		return 0, false
+	}
+	keyId, length, seqNum32 := packet.ParseOspfv2CryptoAuthentication(authentication)
+	key = iface.acceptKey(uint32(keyId), now)
+	if key == nil || key.simple() || length != key.algorithm.DigestLength() {
+		return 0, false
+	}
+	extended := iface.extended()
+	seqNum := uint64(seqNum32)
+	if extended {
+		if len(data) < packetLength+4 {
+			return 0, false
+		}
+		seqNum = uint64(seqNum32)<<32 | uint64(binary.BigEndian.Uint32(data[packetLength:packetLength+4]))
+		packetLength += 4
+	}
+	if len(data) < packetLength+length {
+		return 0, false
+	}
+	digest, err := packet.Ospfv2Digest(key.algorithm, key.key, data[:packetLength], src.Ipv4, extended)
+	if err != nil || !bytes.Equal(digest, data[packetLength:packetLength+length]) {
+		return 0, false
+	}
+	return seqNum, true
+}
+
+// replayed tells whether the cryptographic sequence number of a packet
+// from the neighbor is one it sent before. RFC 2328 D.5.2 accepts the
+// same sequence number again but RFC 7474 and RFC 7166 require it
+// strictly increasing.
+func (nbr *Neighbor) replayed(seqNum uint64) bool {
+	if nbr.iface.ospf.version == packet.OSPFV3_VERSION || nbr.iface.extended() {
+		return seqNum <= nbr.cryptoSeqNum
+	}
+	return seqNum < nbr.cryptoSeqNum
+}
+
+func (iface *Interface) authFailed(pkt packet.OspfPacket, src Address) {
+	log.WithFields(log.Fields{
+		"Topic":     "Interface",
+		"Interface": iface.name,
+		"Type":      pkt.PacketType().String(),
+		"Src":       src.String(),
+	}).Debug("Authentication failed")
+}
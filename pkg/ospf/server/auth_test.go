@@ -0,0 +1,189 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/m-asama/golsr/internal/pkg/kernel"
+)
+
+func ospfv2AuthConfig(algorithm string, keyId int, key, trailerRfc string) string {
+	return fmt.Sprintf("    [areas.interfaces.authentication.config]\n"+
+		"      ospfv2-crypto-algorithm = %q\n"+
+		"      ospfv2-key-id = %d\n"+
+		"      ospfv2-key = %q\n"+
+		"      ospfv2-auth-trailer-rfc = %q\n", algorithm, keyId, key, trailerRfc)
+}
+
+// startAuthPair starts two routers on a point-to-point link with the
+// authentication configurations of their interfaces.
+func startAuthPair(t *testing.T, c1, c2 *testPort) (*testRouter, *testRouter) {
+	network := NewMemoryNetwork()
+	c1.segment, c1.name, c1.address, c1.ifType, c1.priority = "l", "eth0", 0x0a000001, kernel.IF_TYPE_POINTTOPOINT, 1
+	c2.segment, c2.name, c2.address, c2.ifType, c2.priority = "l", "eth0", 0x0a000002, kernel.IF_TYPE_POINTTOPOINT, 1
+	r1 := startTestRouter(t, network, "r1", 0x01010101, []*testPort{c1})
+	r2 := startTestRouter(t, network, "r2", 0x02020202, []*testPort{c2})
+	return r1, r2
+}
+
+func waitForFull(t *testing.T, r1, r2 *testRouter) {
+	waitFor(t, "full adjacency", func() bool {
+		s1, _, _ := r1.neighborStates("eth0")
+		s2, _, _ := r2.neighborStates("eth0")
+		return s1[0x02020202] == NEIGHBOR_STATE_FULL && s2[0x01010101] == NEIGHBOR_STATE_FULL
+	})
+}
+
+func TestAuthentication(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		c1, c2 string
+		full   bool
+	}{
+		{"simple", ospfv2AuthConfig("cleartext", 0, "golsr", "rfc5709"),
+			ospfv2AuthConfig("cleartext", 0, "golsr", "rfc5709"), true},
+		{"md5", ospfv2AuthConfig("md5", 1, "golsr", "rfc5709"),
+			ospfv2AuthConfig("md5", 1, "golsr", "rfc5709"), true},
+		{"hmac-sha-256", ospfv2AuthConfig("hmac-sha-256", 2, "golsr", "rfc5709"),
+			ospfv2AuthConfig("hmac-sha-256", 2, "golsr", "rfc5709"), true},
+		{"rfc7474", ospfv2AuthConfig("hmac-sha-512", 3, "golsr", "rfc7474"),
+			ospfv2AuthConfig("hmac-sha-512", 3, "golsr", "rfc7474"), true},
+		{"null and md5", "",
+			ospfv2AuthConfig("md5", 1, "golsr", "rfc5709"), false},
+		{"key mismatch", ospfv2AuthConfig("hmac-sha-1", 1, "golsr", "rfc5709"),
+			ospfv2AuthConfig("hmac-sha-1", 1, "other", "rfc5709"), false},
+		{"key-id mismatch", ospfv2AuthConfig("md5", 1, "golsr", "rfc5709"),
+			ospfv2AuthConfig("md5", 2, "golsr", "rfc5709"), false},
+	} {
+		r1, r2 := startAuthPair(t, &testPort{ifConfig: test.c1}, &testPort{ifConfig: test.c2})
+		if test.full {
+			waitForFull(t, r1, r2)
+		} else {
+			time.Sleep(3 * time.Second)
+			s1, _, _ := r1.neighborStates("eth0")
+			s2, _, _ := r2.neighborStates("eth0")
+			if len(s1) != 0 || len(s2) != 0 {
+				t.Fatalf("failed %s: neighbors %v %v", test.name, s1, s2)
+			}
+		}
+		r1.stop()
+		r2.stop()
+	}
+}
+
+func TestAuthenticationReplay(t *testing.T) {
+	for _, trailerRfc := range []string{"rfc5709", "rfc7474"} {
+		c := ospfv2AuthConfig("hmac-sha-256", 1, "golsr", trailerRfc)
+		r1, r2 := startAuthPair(t, &testPort{ifConfig: c}, &testPort{ifConfig: c})
+		waitForFull(t, r1, r2)
+
+		r1.ospf.lock.RLock()
+		nbr := r1.ospf.findInterface("eth0").neighborDb[0]
+		seqNum := nbr.cryptoSeqNum
+		older, same := nbr.replayed(seqNum-1), nbr.replayed(seqNum)
+		r1.ospf.lock.RUnlock()
+		if seqNum == 0 || !older {
+			t.Fatalf("failed %s: sequence number 0x%016x", trailerRfc, seqNum)
+		}
+		// RFC 7474 requires the sequence numbers strictly increasing
+		if same != (trailerRfc == "rfc7474") {
+			t.Fatalf("failed %s: same sequence number", trailerRfc)
+		}
+		r1.stop()
+		r2.stop()
+	}
+}
+
+func TestAuthenticationKeyChain(t *testing.T) {
+	// r1 sends with key 2, which became valid later than key 1, and key
+	// 3 is not valid yet
+	keyChain := "[[key-chains]]\n" +
+		"  [key-chains.config]\n" +
+		"    name = \"chain\"\n" +
+		"  [[key-chains.keys]]\n" +
+		"    [key-chains.keys.config]\n" +
+		"      key-id = 1\n" +
+		"      key-string = \"one\"\n" +
+		"      crypto-algorithm = \"hmac-sha-256\"\n" +
+		"      send-start-time = \"2000-01-01T00:00:00Z\"\n" +
+		"  [[key-chains.keys]]\n" +
+		"    [key-chains.keys.config]\n" +
+		"      key-id = 2\n" +
+		"      key-string = \"two\"\n" +
+		"      crypto-algorithm = \"hmac-sha-256\"\n" +
+		"      send-start-time = \"2001-01-01T00:00:00Z\"\n" +
+		"  [[key-chains.keys]]\n" +
+		"    [key-chains.keys.config]\n" +
+		"      key-id = 3\n" +
+		"      key-string = \"three\"\n" +
+		"      crypto-algorithm = \"hmac-sha-256\"\n" +
+		"      send-start-time = \"2999-01-01T00:00:00Z\"\n" +
+		"      accept-start-time = \"2999-01-01T00:00:00Z\"\n"
+	c1 := &testPort{
+		globalConfig: keyChain,
+		ifConfig: "    [areas.interfaces.authentication.config]\n" +
+			"      ospfv2-key-chain = \"chain\"\n",
+	}
+	c2 := &testPort{ifConfig: ospfv2AuthConfig("hmac-sha-256", 2, "two", "rfc5709")}
+	r1, r2 := startAuthPair(t, c1, c2)
+	defer r1.stop()
+	defer r2.stop()
+	waitForFull(t, r1, r2)
+
+	r1.ospf.lock.RLock()
+	iface := r1.ospf.findInterface("eth0")
+	key := iface.sendKey(time.Now())
+	accept := iface.acceptKey(3, time.Now())
+	r1.ospf.lock.RUnlock()
+	if key == nil || key.keyId != 2 {
+		t.Fatalf("failed sendKey: %v", key)
+	}
+	if accept != nil {
+		t.Fatalf("failed acceptKey: %v", accept)
+	}
+}
+
+func TestOspfv3Authentication(t *testing.T) {
+	network := NewMemoryNetwork()
+	c := "    [areas.interfaces.authentication.config]\n" +
+		"      ospfv3-crypto-algorithm = \"hmac-sha-256\"\n" +
+		"      ospfv3-sa-id = 1\n" +
+		"      ospfv3-key = \"golsr\"\n"
+	r1 := startTestRouter(t, network, "r1", 0x01010101, []*testPort{
+		&testPort{segment: "l", name: "eth0", ifType: kernel.IF_TYPE_POINTTOPOINT, priority: 1,
+			address6: [4]uint32{0x20010db8, 1, 0, 1}, ifConfig: c},
+	})
+	defer r1.stop()
+	r2 := startTestRouter(t, network, "r2", 0x02020202, []*testPort{
+		&testPort{segment: "l", name: "eth0", ifType: kernel.IF_TYPE_POINTTOPOINT, priority: 1,
+			address6: [4]uint32{0x20010db8, 1, 0, 2}, ifConfig: c},
+	})
+	defer r2.stop()
+	waitForFull(t, r1, r2)
+
+	r1.ospf.lock.RLock()
+	nbr := r1.ospf.findInterface("eth0").neighborDb[0]
+	seqNum := nbr.cryptoSeqNum
+	r1.ospf.lock.RUnlock()
+	if seqNum == 0 {
+		t.Fatalf("failed sequence number")
+	}
+}
@@ -0,0 +1,274 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+)
+
+const (
+	OSPF_PACKET_BUFFER_LENGTH = 65536
+	IPV4_HEADER_LENGTH        = 20
+	IPV6_HEADER_LENGTH        = 40
+	IPV6_PKTINFO_LENGTH       = 20
+	LS_REFRESH_TIME           = 1800
+	MIN_LS_ARRIVAL            = 1
+	BACKBONE_AREA_ID          = 0
+	MAX_LINK_METRIC           = 0xffff
+	DEFAULT_PREFERENCE        = 110
+)
+
+type NetworkType uint8
+
+const (
+	_ NetworkType = iota
+	NETWORK_TYPE_BROADCAST
+	NETWORK_TYPE_POINT_TO_POINT
+	NETWORK_TYPE_NBMA
+	NETWORK_TYPE_POINT_TO_MULTIPOINT
+)
+
+func (networkType NetworkType) String() string {
+	switch networkType {
+	case NETWORK_TYPE_BROADCAST:
+		return "NETWORK_TYPE_BROADCAST"
+	case NETWORK_TYPE_POINT_TO_POINT:
+		return "NETWORK_TYPE_POINT_TO_POINT"
+	case NETWORK_TYPE_NBMA:
+		return "NETWORK_TYPE_NBMA"
+	case NETWORK_TYPE_POINT_TO_MULTIPOINT:
+		return "NETWORK_TYPE_POINT_TO_MULTIPOINT"
+	}
+	return fmt.Sprintf("NetworkType(%d)", networkType)
+}
+
+type InterfaceState uint8
+
+const (
+	_ InterfaceState = iota
+	INTERFACE_STATE_DOWN
+	INTERFACE_STATE_LOOPBACK
+	INTERFACE_STATE_WAITING
+	INTERFACE_STATE_POINT_TO_POINT
+	INTERFACE_STATE_DR_OTHER
+	INTERFACE_STATE_BACKUP
+	INTERFACE_STATE_DR
+)
+
+func (state InterfaceState) String() string {
+	switch state {
+	case INTERFACE_STATE_DOWN:
+		return "INTERFACE_STATE_DOWN"
+	case INTERFACE_STATE_LOOPBACK:
+		return "INTERFACE_STATE_LOOPBACK"
+	case INTERFACE_STATE_WAITING:
+		return "INTERFACE_STATE_WAITING"
+	case INTERFACE_STATE_POINT_TO_POINT:
+		return "INTERFACE_STATE_POINT_TO_POINT"
+	case INTERFACE_STATE_DR_OTHER:
+		return "INTERFACE_STATE_DR_OTHER"
+	case INTERFACE_STATE_BACKUP:
+		return "INTERFACE_STATE_BACKUP"
+	case INTERFACE_STATE_DR:
+		return "INTERFACE_STATE_DR"
+	}
+	return fmt.Sprintf("InterfaceState(%d)", state)
+}
+
+type InterfaceEvent uint8
+
+const (
+	_ InterfaceEvent = iota
+	INTERFACE_EVENT_INTERFACE_UP
+	INTERFACE_EVENT_WAIT_TIMER
+	INTERFACE_EVENT_BACKUP_SEEN
+	INTERFACE_EVENT_NEIGHBOR_CHANGE
+	INTERFACE_EVENT_LOOP_IND
+	INTERFACE_EVENT_UNLOOP_IND
+	INTERFACE_EVENT_INTERFACE_DOWN
+)
+
+func (event InterfaceEvent) String() string {
+	switch event {
+	case INTERFACE_EVENT_INTERFACE_UP:
+		return "INTERFACE_EVENT_INTERFACE_UP"
+	case INTERFACE_EVENT_WAIT_TIMER:
+		return "INTERFACE_EVENT_WAIT_TIMER"
+	case INTERFACE_EVENT_BACKUP_SEEN:
+		return "INTERFACE_EVENT_BACKUP_SEEN"
+	case INTERFACE_EVENT_NEIGHBOR_CHANGE:
+		return "INTERFACE_EVENT_NEIGHBOR_CHANGE"
+	case INTERFACE_EVENT_LOOP_IND:
+		return "INTERFACE_EVENT_LOOP_IND"
+	case INTERFACE_EVENT_UNLOOP_IND:
+		return "INTERFACE_EVENT_UNLOOP_IND"
+	case INTERFACE_EVENT_INTERFACE_DOWN:
+		return "INTERFACE_EVENT_INTERFACE_DOWN"
+	}
+	return fmt.Sprintf("InterfaceEvent(%d)", event)
+}
+
+type NeighborState uint8
+
+const (
+	_ NeighborState = iota
+	NEIGHBOR_STATE_DOWN
+	NEIGHBOR_STATE_ATTEMPT
+	NEIGHBOR_STATE_INIT
+	NEIGHBOR_STATE_TWO_WAY
+	NEIGHBOR_STATE_EXSTART
+	NEIGHBOR_STATE_EXCHANGE
+	NEIGHBOR_STATE_LOADING
+	NEIGHBOR_STATE_FULL
+)
+
+func (state NeighborState) String() string {
+	switch state {
+	case NEIGHBOR_STATE_DOWN:
+		return "NEIGHBOR_STATE_DOWN"
+	case NEIGHBOR_STATE_ATTEMPT:
+		return "NEIGHBOR_STATE_ATTEMPT"
+	case NEIGHBOR_STATE_INIT:
+		return "NEIGHBOR_STATE_INIT"
+	case NEIGHBOR_STATE_TWO_WAY:
+		return "NEIGHBOR_STATE_TWO_WAY"
+	case NEIGHBOR_STATE_EXSTART:
+		return "NEIGHBOR_STATE_EXSTART"
+	case NEIGHBOR_STATE_EXCHANGE:
+		return "NEIGHBOR_STATE_EXCHANGE"
+	case NEIGHBOR_STATE_LOADING:
+		return "NEIGHBOR_STATE_LOADING"
+	case NEIGHBOR_STATE_FULL:
+		return "NEIGHBOR_STATE_FULL"
+	}
+	return fmt.Sprintf("NeighborState(%d)", state)
+}
+
+type NeighborEvent uint8
+
+const (
+	_ NeighborEvent = iota
+	NEIGHBOR_EVENT_HELLO_RECEIVED
+	NEIGHBOR_EVENT_START
+	NEIGHBOR_EVENT_TWO_WAY_RECEIVED
+	NEIGHBOR_EVENT_NEGOTIATION_DONE
+	NEIGHBOR_EVENT_EXCHANGE_DONE
+	NEIGHBOR_EVENT_BAD_LS_REQ
+	NEIGHBOR_EVENT_LOADING_DONE
+	NEIGHBOR_EVENT_ADJ_OK
+	NEIGHBOR_EVENT_SEQ_NUMBER_MISMATCH
+	NEIGHBOR_EVENT_ONE_WAY_RECEIVED
+	NEIGHBOR_EVENT_KILL_NBR
+	NEIGHBOR_EVENT_INACTIVITY_TIMER
+	NEIGHBOR_EVENT_LL_DOWN
+)
+
+func (event NeighborEvent) String() string {
+	switch event {
+	case NEIGHBOR_EVENT_HELLO_RECEIVED:
+		return "NEIGHBOR_EVENT_HELLO_RECEIVED"
+	case NEIGHBOR_EVENT_START:
+		return "NEIGHBOR_EVENT_START"
+	case NEIGHBOR_EVENT_TWO_WAY_RECEIVED:
+		return "NEIGHBOR_EVENT_TWO_WAY_RECEIVED"
+	case NEIGHBOR_EVENT_NEGOTIATION_DONE:
+		return "NEIGHBOR_EVENT_NEGOTIATION_DONE"
+	case NEIGHBOR_EVENT_EXCHANGE_DONE:
+		return "NEIGHBOR_EVENT_EXCHANGE_DONE"
+	case NEIGHBOR_EVENT_BAD_LS_REQ:
+		return "NEIGHBOR_EVENT_BAD_LS_REQ"
+	case NEIGHBOR_EVENT_LOADING_DONE:
+		return "NEIGHBOR_EVENT_LOADING_DONE"
+	case NEIGHBOR_EVENT_ADJ_OK:
+		return "NEIGHBOR_EVENT_ADJ_OK"
+	case NEIGHBOR_EVENT_SEQ_NUMBER_MISMATCH:
+		return "NEIGHBOR_EVENT_SEQ_NUMBER_MISMATCH"
+	case NEIGHBOR_EVENT_ONE_WAY_RECEIVED:
+		return "NEIGHBOR_EVENT_ONE_WAY_RECEIVED"
+	case NEIGHBOR_EVENT_KILL_NBR:
+		return "NEIGHBOR_EVENT_KILL_NBR"
+	case NEIGHBOR_EVENT_INACTIVITY_TIMER:
+		return "NEIGHBOR_EVENT_INACTIVITY_TIMER"
+	case NEIGHBOR_EVENT_LL_DOWN:
+		return "NEIGHBOR_EVENT_LL_DOWN"
+	}
+	return fmt.Sprintf("NeighborEvent(%d)", event)
+}
+
+type VertexType uint8
+
+const (
+	_ VertexType = iota
+	VERTEX_TYPE_ROUTER
+	VERTEX_TYPE_NETWORK
+)
+
+func (vertexType VertexType) String() string {
+	switch vertexType {
+	case VERTEX_TYPE_ROUTER:
+		return "VERTEX_TYPE_ROUTER"
+	case VERTEX_TYPE_NETWORK:
+		return "VERTEX_TYPE_NETWORK"
+	}
+	return fmt.Sprintf("VertexType(%d)", vertexType)
+}
+
+type AreaType uint8
+
+const (
+	_ AreaType = iota
+	AREA_TYPE_NORMAL
+	AREA_TYPE_STUB
+	AREA_TYPE_NSSA
+)
+
+func (areaType AreaType) String() string {
+	switch areaType {
+	case AREA_TYPE_NORMAL:
+		return "AREA_TYPE_NORMAL"
+	case AREA_TYPE_STUB:
+		return "AREA_TYPE_STUB"
+	case AREA_TYPE_NSSA:
+		return "AREA_TYPE_NSSA"
+	}
+	return fmt.Sprintf("AreaType(%d)", areaType)
+}
+
+type RouteType uint8
+
+const (
+	_ RouteType = iota
+	ROUTE_TYPE_INTRA_AREA
+	ROUTE_TYPE_INTER_AREA
+	ROUTE_TYPE_EXTERNAL_1
+	ROUTE_TYPE_EXTERNAL_2
+)
+
+func (routeType RouteType) String() string {
+	switch routeType {
+	case ROUTE_TYPE_INTRA_AREA:
+		return "ROUTE_TYPE_INTRA_AREA"
+	case ROUTE_TYPE_INTER_AREA:
+		return "ROUTE_TYPE_INTER_AREA"
+	case ROUTE_TYPE_EXTERNAL_1:
+		return "ROUTE_TYPE_EXTERNAL_1"
+	case ROUTE_TYPE_EXTERNAL_2:
+		return "ROUTE_TYPE_EXTERNAL_2"
+	}
+	return fmt.Sprintf("RouteType(%d)", routeType)
+}
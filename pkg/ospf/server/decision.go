@@ -23,6 +23,8 @@ import (
 	"sync"
 
 	log "github.com/sirupsen/logrus"
+
+	"github.com/m-asama/golsr/pkg/ospf/packet"
 )
 
 type DecisionChMsgType uint8
@@ -55,6 +57,852 @@ func (msg *DecisionChMsg) String() string {
 	return b.String()
 }
 
+type Ipv4Nh struct {
+	nexthopAddress   uint32
+	nexthopInterface *Interface
+}
+
+type Ipv4RiKey struct {
+	prefixAddress uint32
+	prefixLength  uint8
+}
+
+type Ipv4Ri struct {
+	prefixAddress uint32
+	prefixLength  uint8
+	routeType     RouteType
+	areaId        uint32
+	metric        uint32
+	type2Metric   uint32
+	nexthops      []*Ipv4Nh
+}
+
+func (ri *Ipv4Ri) String() string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s/%d %s area %s metric %d",
+		packet.Ipv4String(ri.prefixAddress), ri.prefixLength, ri.routeType.String(),
+		packet.Ipv4String(ri.areaId), ri.metric)
+	if ri.routeType == ROUTE_TYPE_EXTERNAL_2 {
+		fmt.Fprintf(&b, " type2-metric %d", ri.type2Metric)
+	}
+	for _, nh := range ri.nexthops {
+		fmt.Fprintf(&b, " via %s", packet.Ipv4String(nh.nexthopAddress))
+		if nh.nexthopInterface != nil {
+			fmt.Fprintf(&b, "%%%s", nh.nexthopInterface.name)
+		}
+	}
+	return b.String()
+}
+
+// RouterRi is a route to an area border router or an AS boundary router.
+type RouterRi struct {
+	routerId  uint32
+	routeType RouteType
+	areaId    uint32
+	metric    uint32
+	border    bool
+	asbr      bool
+	// nssaTranslator is the Nt-bit of an NSSA border router, which
+	// translates unconditionally.
+	nssaTranslator bool
+	nexthops       []*spfNexthop
+}
+
+func prefixLength(mask uint32) uint8 {
+	length := uint8(0)
+	for mask&0x80000000 != 0 {
+		length++
+		mask <<= 1
+	}
+	return length
+}
+
+func maskOf(prefixLength uint8) uint32 {
+	if prefixLength == 0 {
+		return 0
+	}
+	return ^uint32(0) << (32 - prefixLength)
+}
+
+func mergeNexthops(nexthops, others []*Ipv4Nh) []*Ipv4Nh {
+	merged := append([]*Ipv4Nh{}, nexthops...)
+	for _, other := range others {
+		found := false
+		for _, nh := range merged {
+			if nh.nexthopAddress == other.nexthopAddress &&
+				nh.nexthopInterface == other.nexthopInterface {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, other)
+		}
+	}
+	return merged
+}
+
+// spfNexthop is a next hop calculated by the SPF, which is an address of
+// the family of the OSPF version.
+type spfNexthop struct {
+	iface   *Interface
+	address Address
+}
+
+func mergeSpfNexthops(nexthops, others []*spfNexthop) []*spfNexthop {
+	merged := append([]*spfNexthop{}, nexthops...)
+	for _, other := range others {
+		found := false
+		for _, nh := range merged {
+			if *nh == *other {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, other)
+		}
+	}
+	return merged
+}
+
+func sameSpfNexthops(nexthops, others []*spfNexthop) bool {
+	if len(nexthops) != len(others) {
+		return false
+	}
+	for _, nh := range nexthops {
+		found := false
+		for _, other := range others {
+			if *nh == *other {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func ipv4Nexthops(nexthops []*spfNexthop) []*Ipv4Nh {
+	ipv4Nexthops := make([]*Ipv4Nh, 0, len(nexthops))
+	for _, nh := range nexthops {
+		ipv4Nexthops = append(ipv4Nexthops, &Ipv4Nh{
+			nexthopAddress:   nh.address.Ipv4,
+			nexthopInterface: nh.iface,
+		})
+	}
+	return ipv4Nexthops
+}
+
+// spfVertexKey identifies a vertex of the SPF. A network is identified by
+// the interface address of its designated router for OSPFv2, and by the
+// router ID and the interface ID of its designated router for OSPFv3.
+type spfVertexKey struct {
+	vertexType  VertexType
+	id          uint32
+	interfaceId uint32
+}
+
+type spfVertex struct {
+	spfVertexKey
+	lsa      packet.OspfLsa
+	distance uint32
+	nexthops []*spfNexthop
+}
+
+// directlyAttached tells whether the vertex is a network the router is
+// attached to.
+func (v *spfVertex) directlyAttached() bool {
+	return v.vertexType == VERTEX_TYPE_NETWORK && len(v.nexthops) > 0 &&
+		v.nexthops[0].address.isZero()
+}
+
+// spfLink is a link of a vertex. Its data identifies the interface of the
+// vertex the link is on: the address for OSPFv2 and the interface ID for
+// OSPFv3.
+type spfLink struct {
+	key  spfVertexKey
+	lsa  packet.OspfLsa
+	cost uint32
+	data uint32
+}
+
+func (area *Area) interfaceByLinkData(data uint32) *Interface {
+	for _, iface := range area.interfaces() {
+		if iface.state == INTERFACE_STATE_DOWN {
+			continue
+		}
+		if area.ospf.version == packet.OSPFV3_VERSION {
+			if iface.interfaceId == data {
+				return iface
+			}
+		} else if iface.address == data {
+			return iface
+		}
+	}
+	return nil
+}
+
+// spfRouterLsa returns the router-LSA of routerId. The router-LSAs an
+// OSPFv3 router originates are merged into one.
+func (area *Area) spfRouterLsa(routerId uint32) packet.OspfLsa {
+	if area.ospf.version == packet.OSPFV3_VERSION {
+		return area.spfOspfv3RouterLsa(routerId)
+	}
+	lsa := area.lookupLsa(packet.LsaKey{
+		LsType:            packet.LS_TYPE_ROUTER,
+		LinkStateId:       routerId,
+		AdvertisingRouter: routerId,
+	})
+	if lsa == nil || lsa.Header().LsAge >= packet.MAX_AGE {
+		return nil
+	}
+	return lsa
+}
+
+// spfNetworkLsas returns the network-LSAs of the area by their vertices.
+func (area *Area) spfNetworkLsas() map[spfVertexKey]packet.OspfLsa {
+	networks := make(map[spfVertexKey]packet.OspfLsa)
+	for _, key := range area.lsdb.keys() {
+		lsa := area.lsdb.lsas[key]
+		if lsa.Header().LsAge >= packet.MAX_AGE {
+			continue
+		}
+		switch key.LsType {
+		case packet.LS_TYPE_NETWORK:
+			networks[spfVertexKey{VERTEX_TYPE_NETWORK, key.LinkStateId, 0}] = lsa
+		case packet.LS_TYPE_OSPFV3_NETWORK:
+			networks[spfVertexKey{VERTEX_TYPE_NETWORK, key.AdvertisingRouter, key.LinkStateId}] = lsa
+		}
+	}
+	return networks
+}
+
+// spfLinks returns the vertices the vertex v has links to.
+func (area *Area) spfLinks(v *spfVertex, networks map[spfVertexKey]packet.OspfLsa) []*spfLink {
+	links := make([]*spfLink, 0)
+	switch lsa := v.lsa.(type) {
+	case *packet.RouterLsa:
+		for _, link := range lsa.Links {
+			switch link.Type {
+			case packet.LINK_TYPE_POINT_TO_POINT, packet.LINK_TYPE_VIRTUAL:
+				if w := area.spfRouterLsa(link.LinkId); w != nil {
+					links = append(links, &spfLink{
+						key:  spfVertexKey{VERTEX_TYPE_ROUTER, link.LinkId, 0},
+						lsa:  w,
+						cost: uint32(link.Metric),
+						data: link.LinkData,
+					})
+				}
+			case packet.LINK_TYPE_TRANSIT:
+				key := spfVertexKey{VERTEX_TYPE_NETWORK, link.LinkId, 0}
+				if w, ok := networks[key]; ok {
+					links = append(links, &spfLink{
+						key:  key,
+						lsa:  w,
+						cost: uint32(link.Metric),
+						data: link.LinkData,
+					})
+				}
+			}
+		}
+	case *packet.Ospfv3RouterLsa:
+		for _, link := range lsa.Links {
+			switch link.Type {
+			case packet.LINK_TYPE_POINT_TO_POINT:
+				if w := area.spfRouterLsa(link.NeighborRouterId); w != nil {
+					links = append(links, &spfLink{
+						key:  spfVertexKey{VERTEX_TYPE_ROUTER, link.NeighborRouterId, 0},
+						lsa:  w,
+						cost: uint32(link.Metric),
+						data: link.InterfaceId,
+					})
+				}
+			case packet.LINK_TYPE_TRANSIT:
+				key := spfVertexKey{VERTEX_TYPE_NETWORK, link.NeighborRouterId, link.NeighborInterfaceId}
+				if w, ok := networks[key]; ok {
+					links = append(links, &spfLink{
+						key:  key,
+						lsa:  w,
+						cost: uint32(link.Metric),
+						data: link.InterfaceId,
+					})
+				}
+			}
+		}
+	case *packet.NetworkLsa:
+		links = area.spfAttachedRouters(lsa.AttachedRouters)
+	case *packet.Ospfv3NetworkLsa:
+		links = area.spfAttachedRouters(lsa.AttachedRouters)
+	}
+	return links
+}
+
+func (area *Area) spfAttachedRouters(attachedRouters []uint32) []*spfLink {
+	links := make([]*spfLink, 0)
+	for _, routerId := range attachedRouters {
+		if w := area.spfRouterLsa(routerId); w != nil {
+			links = append(links, &spfLink{
+				key: spfVertexKey{VERTEX_TYPE_ROUTER, routerId, 0},
+				lsa: w,
+			})
+		}
+	}
+	return links
+}
+
+// linksBack tells whether the LSA of w has a link back to the vertex v.
+func linksBack(w packet.OspfLsa, v *spfVertex) bool {
+	switch lsa := w.(type) {
+	case *packet.RouterLsa:
+		for _, link := range lsa.Links {
+			if link.LinkId != v.id {
+				continue
+			}
+			if v.vertexType == VERTEX_TYPE_ROUTER &&
+				(link.Type == packet.LINK_TYPE_POINT_TO_POINT || link.Type == packet.LINK_TYPE_VIRTUAL) {
+				return true
+			}
+			if v.vertexType == VERTEX_TYPE_NETWORK && link.Type == packet.LINK_TYPE_TRANSIT {
+				return true
+			}
+		}
+	case *packet.Ospfv3RouterLsa:
+		for _, link := range lsa.Links {
+			if link.NeighborRouterId != v.id {
+				continue
+			}
+			if v.vertexType == VERTEX_TYPE_ROUTER && link.Type == packet.LINK_TYPE_POINT_TO_POINT {
+				return true
+			}
+			if v.vertexType == VERTEX_TYPE_NETWORK && link.Type == packet.LINK_TYPE_TRANSIT &&
+				link.NeighborInterfaceId == v.interfaceId {
+				return true
+			}
+		}
+	case *packet.NetworkLsa:
+		return v.vertexType == VERTEX_TYPE_ROUTER && attached(lsa.AttachedRouters, v.id)
+	case *packet.Ospfv3NetworkLsa:
+		return v.vertexType == VERTEX_TYPE_ROUTER && attached(lsa.AttachedRouters, v.id)
+	}
+	return false
+}
+
+func attached(attachedRouters []uint32, routerId uint32) bool {
+	for _, attachedRouter := range attachedRouters {
+		if attachedRouter == routerId {
+			return true
+		}
+	}
+	return false
+}
+
+// spfNexthops calculates the next hops to the vertex of link from its
+// parent v as in RFC 2328 16.1.1.
+func (area *Area) spfNexthops(v *spfVertex, link *spfLink) []*spfNexthop {
+	nexthops := make([]*spfNexthop, 0)
+	if v.id == area.ospf.routerId && v.vertexType == VERTEX_TYPE_ROUTER {
+		iface := area.interfaceByLinkData(link.data)
+		if iface == nil {
+			return nexthops
+		}
+		if iface.virtualLink != nil {
+			// the next hops of the path through the transit area
+			return append(nexthops, iface.virtualLink.nexthops...)
+		}
+		if link.key.vertexType == VERTEX_TYPE_NETWORK {
+			return append(nexthops, &spfNexthop{iface: iface})
+		}
+		for _, nbr := range iface.neighborDb {
+			if nbr.routerId == link.key.id && nbr.state == NEIGHBOR_STATE_FULL {
+				nexthops = append(nexthops, &spfNexthop{
+					iface:   iface,
+					address: nbr.src(),
+				})
+			}
+		}
+		return nexthops
+	}
+	if v.directlyAttached() && link.key.vertexType == VERTEX_TYPE_ROUTER {
+		switch lsa := link.lsa.(type) {
+		case *packet.RouterLsa:
+			for _, l := range lsa.Links {
+				if l.Type != packet.LINK_TYPE_TRANSIT || l.LinkId != v.id {
+					continue
+				}
+				for _, nh := range v.nexthops {
+					nexthops = append(nexthops, &spfNexthop{
+						iface:   nh.iface,
+						address: Address{Ipv4: l.LinkData},
+					})
+				}
+			}
+		case *packet.Ospfv3RouterLsa:
+			for _, l := range lsa.Links {
+				if l.Type != packet.LINK_TYPE_TRANSIT || l.NeighborRouterId != v.id ||
+					l.NeighborInterfaceId != v.interfaceId {
+					continue
+				}
+				for _, nh := range v.nexthops {
+					address := nh.iface.linkLocalOf(link.key.id, l.InterfaceId)
+					if address.isZero() {
+						continue
+					}
+					nexthops = append(nexthops, &spfNexthop{
+						iface:   nh.iface,
+						address: address,
+					})
+				}
+			}
+		}
+		return nexthops
+	}
+	return append(nexthops, v.nexthops...)
+}
+
+// closer tells whether the candidate v is added to the shortest-path tree
+// before w: the closer one, a network before a router at the same
+// distance, or else the one of the lower ID.
+func (v *spfVertex) closer(w *spfVertex) bool {
+	if v.distance != w.distance {
+		return v.distance < w.distance
+	}
+	if v.vertexType != w.vertexType {
+		return v.vertexType > w.vertexType
+	}
+	if v.id != w.id {
+		return v.id < w.id
+	}
+	return v.interfaceId < w.interfaceId
+}
+
+// spf builds the shortest-path tree of the area as in RFC 2328 16.1.
+func (area *Area) spf() map[spfVertexKey]*spfVertex {
+	tree := make(map[spfVertexKey]*spfVertex)
+	rootLsa := area.spfRouterLsa(area.ospf.routerId)
+	if rootLsa == nil {
+		return tree
+	}
+	networks := area.spfNetworkLsas()
+	candidates := make(map[spfVertexKey]*spfVertex)
+	v := &spfVertex{
+		spfVertexKey: spfVertexKey{VERTEX_TYPE_ROUTER, area.ospf.routerId, 0},
+		lsa:          rootLsa,
+	}
+	for v != nil {
+		tree[v.spfVertexKey] = v
+		for _, link := range area.spfLinks(v, networks) {
+			if _, ok := tree[link.key]; ok {
+				continue
+			}
+			if !linksBack(link.lsa, v) {
+				continue
+			}
+			distance := v.distance + link.cost
+			candidate := candidates[link.key]
+			if candidate != nil && distance > candidate.distance {
+				continue
+			}
+			nexthops := area.spfNexthops(v, link)
+			if candidate == nil || distance < candidate.distance {
+				candidates[link.key] = &spfVertex{
+					spfVertexKey: link.key,
+					lsa:          link.lsa,
+					distance:     distance,
+					nexthops:     nexthops,
+				}
+			} else {
+				candidate.nexthops = mergeSpfNexthops(candidate.nexthops, nexthops)
+			}
+		}
+		v = nil
+		for _, candidate := range candidates {
+			if v == nil || candidate.closer(v) {
+				v = candidate
+			}
+		}
+		if v != nil {
+			delete(candidates, v.spfVertexKey)
+		}
+	}
+	return tree
+}
+
+// addAsbrRoutes adds the intra-area routes to the AS boundary routers of
+// the area to asbrRiDb, keeping the cheapest one.
+func (area *Area) addAsbrRoutes(asbrRiDb map[uint32]*RouterRi) {
+	for routerId, ri := range area.routerRiDb {
+		if !ri.asbr {
+			continue
+		}
+		if current := asbrRiDb[routerId]; current == nil || ri.metric < current.metric {
+			asbrRiDb[routerId] = ri
+		}
+	}
+}
+
+// addIntraAreaRoute adds a route calculated from the area to ipv4RiDb,
+// keeping the cheapest one.
+func (ospf *OspfServer) addIntraAreaRoute(ipv4RiDb map[Ipv4RiKey]*Ipv4Ri, area *Area,
+	prefixAddress uint32, prefixLength uint8, metric uint32, nexthops []*Ipv4Nh) {
+	key := Ipv4RiKey{prefixAddress & maskOf(prefixLength), prefixLength}
+	ri := ipv4RiDb[key]
+	if ri != nil && ri.metric < metric {
+		return
+	}
+	if ri != nil && ri.metric == metric {
+		ri.nexthops = mergeNexthops(ri.nexthops, nexthops)
+		return
+	}
+	ipv4RiDb[key] = &Ipv4Ri{
+		prefixAddress: key.prefixAddress,
+		prefixLength:  key.prefixLength,
+		routeType:     ROUTE_TYPE_INTRA_AREA,
+		areaId:        area.areaId,
+		metric:        metric,
+		nexthops:      nexthops,
+	}
+}
+
+// connectedNexthops returns the interface of the area a stub network of
+// the router itself is on.
+func (area *Area) connectedNexthops(prefixAddress uint32, mask uint32) []*Ipv4Nh {
+	for _, iface := range area.interfaces() {
+		if iface.state != INTERFACE_STATE_DOWN && iface.address&mask == prefixAddress&mask {
+			return []*Ipv4Nh{&Ipv4Nh{nexthopInterface: iface}}
+		}
+	}
+	return []*Ipv4Nh{}
+}
+
+// intraAreaRoutes adds the routes to the networks and the border routers
+// of the area to the routing table as in RFC 2328 16.1.
+func (ospf *OspfServer) intraAreaRoutes(area *Area, ipv4RiDb map[Ipv4RiKey]*Ipv4Ri) {
+	tree := area.spf()
+	area.updateRouterRiDb(tree)
+	area.transitCapability = false
+	for _, v := range tree {
+		switch lsa := v.lsa.(type) {
+		case *packet.RouterLsa:
+			if lsa.VirtualLink {
+				area.transitCapability = true
+			}
+			for _, link := range lsa.Links {
+				if link.Type != packet.LINK_TYPE_STUB {
+					continue
+				}
+				nexthops := ipv4Nexthops(v.nexthops)
+				if v.id == ospf.routerId {
+					nexthops = area.connectedNexthops(link.LinkId, link.LinkData)
+				}
+				ospf.addIntraAreaRoute(ipv4RiDb, area, link.LinkId, prefixLength(link.LinkData),
+					v.distance+uint32(link.Metric), nexthops)
+			}
+		case *packet.NetworkLsa:
+			ospf.addIntraAreaRoute(ipv4RiDb, area, v.id, prefixLength(lsa.NetworkMask),
+				v.distance, ipv4Nexthops(v.nexthops))
+		}
+	}
+}
+
+// updateRouterRiDb keeps the routes to the area border routers and the
+// AS boundary routers of the shortest-path tree of the area.
+func (area *Area) updateRouterRiDb(tree map[spfVertexKey]*spfVertex) {
+	area.routerRiDb = make(map[uint32]*RouterRi)
+	for _, v := range tree {
+		if v.vertexType != VERTEX_TYPE_ROUTER || v.id == area.ospf.routerId {
+			continue
+		}
+		var border, external, nssaTranslator bool
+		switch lsa := v.lsa.(type) {
+		case *packet.RouterLsa:
+			border, external, nssaTranslator = lsa.Border, lsa.External, lsa.NssaTranslator
+		case *packet.Ospfv3RouterLsa:
+			border, external, nssaTranslator = lsa.Border, lsa.External, lsa.NssaTranslator
+		}
+		if !border && !external {
+			continue
+		}
+		area.routerRiDb[v.id] = &RouterRi{
+			routerId:       v.id,
+			routeType:      ROUTE_TYPE_INTRA_AREA,
+			areaId:         area.areaId,
+			metric:         v.distance,
+			border:         border,
+			asbr:           external,
+			nssaTranslator: nssaTranslator,
+			nexthops:       v.nexthops,
+		}
+	}
+}
+
+// interAreaRoutes adds the routes of the summary-LSAs of the area as in
+// RFC 2328 16.2.
+func (ospf *OspfServer) interAreaRoutes(area *Area, ipv4RiDb map[Ipv4RiKey]*Ipv4Ri, asbrRiDb map[uint32]*RouterRi) {
+	for _, key := range area.lsdb.keys() {
+		if key.LsType != packet.LS_TYPE_SUMMARY_NETWORK && key.LsType != packet.LS_TYPE_SUMMARY_ASBR {
+			continue
+		}
+		lsa := area.lsdb.lsas[key].(*packet.SummaryLsa)
+		if lsa.Header().LsAge >= packet.MAX_AGE || lsa.Metric >= packet.LS_INFINITY ||
+			ospf.selfOriginated(lsa) {
+			continue
+		}
+		br := area.routerRiDb[key.AdvertisingRouter]
+		if br == nil || !br.border {
+			continue
+		}
+		metric := br.metric + lsa.Metric
+		if key.LsType == packet.LS_TYPE_SUMMARY_ASBR {
+			ri := asbrRiDb[key.LinkStateId]
+			if ri != nil && (ri.routeType == ROUTE_TYPE_INTRA_AREA || ri.metric < metric) {
+				continue
+			}
+			if ri != nil && ri.metric == metric {
+				ri.nexthops = mergeSpfNexthops(ri.nexthops, br.nexthops)
+				continue
+			}
+			asbrRiDb[key.LinkStateId] = &RouterRi{
+				routerId:  key.LinkStateId,
+				routeType: ROUTE_TYPE_INTER_AREA,
+				areaId:    area.areaId,
+				metric:    metric,
+				asbr:      true,
+				nexthops:  br.nexthops,
+			}
+			continue
+		}
+		length := prefixLength(lsa.NetworkMask)
+		riKey := Ipv4RiKey{key.LinkStateId & lsa.NetworkMask, length}
+		ri := ipv4RiDb[riKey]
+		if ri != nil && (ri.routeType == ROUTE_TYPE_INTRA_AREA || ri.metric < metric) {
+			continue
+		}
+		if ri != nil && ri.metric == metric {
+			ri.nexthops = mergeNexthops(ri.nexthops, ipv4Nexthops(br.nexthops))
+			continue
+		}
+		ipv4RiDb[riKey] = &Ipv4Ri{
+			prefixAddress: riKey.prefixAddress,
+			prefixLength:  riKey.prefixLength,
+			routeType:     ROUTE_TYPE_INTER_AREA,
+			areaId:        area.areaId,
+			metric:        metric,
+			nexthops:      ipv4Nexthops(br.nexthops),
+		}
+	}
+}
+
+// transitAreaRoutes looks for better paths than the ones through the
+// backbone to the destinations of the backbone routes in the summary-LSAs
+// of the transit area as in RFC 2328 16.3. The routes keep the backbone
+// as their area.
+func (ospf *OspfServer) transitAreaRoutes(area *Area, ipv4RiDb map[Ipv4RiKey]*Ipv4Ri, asbrRiDb map[uint32]*RouterRi) {
+	for _, key := range area.lsdb.keys() {
+		if key.LsType != packet.LS_TYPE_SUMMARY_NETWORK && key.LsType != packet.LS_TYPE_SUMMARY_ASBR {
+			continue
+		}
+		lsa := area.lsdb.lsas[key].(*packet.SummaryLsa)
+		if lsa.Header().LsAge >= packet.MAX_AGE || lsa.Metric >= packet.LS_INFINITY ||
+			ospf.selfOriginated(lsa) {
+			continue
+		}
+		br := area.routerRiDb[key.AdvertisingRouter]
+		if br == nil || !br.border {
+			continue
+		}
+		metric := br.metric + lsa.Metric
+		if key.LsType == packet.LS_TYPE_SUMMARY_ASBR {
+			ri := asbrRiDb[key.LinkStateId]
+			if ri == nil || ri.areaId != 0 || ri.metric < metric {
+				continue
+			}
+			// the route may be the one of the routerRiDb of the backbone
+			better := *ri
+			if ri.metric == metric {
+				better.nexthops = mergeSpfNexthops(ri.nexthops, br.nexthops)
+			} else {
+				better.metric = metric
+				better.nexthops = br.nexthops
+			}
+			asbrRiDb[key.LinkStateId] = &better
+			continue
+		}
+		riKey := Ipv4RiKey{key.LinkStateId & lsa.NetworkMask, prefixLength(lsa.NetworkMask)}
+		ri := ipv4RiDb[riKey]
+		if ri == nil || ri.areaId != 0 || ri.metric < metric ||
+			ri.routeType != ROUTE_TYPE_INTRA_AREA && ri.routeType != ROUTE_TYPE_INTER_AREA {
+			continue
+		}
+		if ri.metric == metric {
+			ri.nexthops = mergeNexthops(ri.nexthops, ipv4Nexthops(br.nexthops))
+			continue
+		}
+		ri.metric = metric
+		ri.nexthops = ipv4Nexthops(br.nexthops)
+	}
+}
+
+// lookupRoute returns the intra-area or inter-area route with the
+// longest prefix matching address.
+func lookupRoute(ipv4RiDb map[Ipv4RiKey]*Ipv4Ri, address uint32) *Ipv4Ri {
+	var best *Ipv4Ri
+	for key, ri := range ipv4RiDb {
+		if ri.routeType != ROUTE_TYPE_INTRA_AREA && ri.routeType != ROUTE_TYPE_INTER_AREA {
+			continue
+		}
+		if address&maskOf(key.prefixLength) != key.prefixAddress {
+			continue
+		}
+		if best == nil || key.prefixLength > best.prefixLength {
+			best = ri
+		}
+	}
+	return best
+}
+
+// betterExternal tells whether the external route a is preferred to b as
+// in RFC 2328 16.4 (6).
+func betterExternal(a, b *Ipv4Ri) int {
+	return compareExternal(a.routeType, a.type2Metric, a.metric, b.routeType, b.type2Metric, b.metric)
+}
+
+func compareExternal(aType RouteType, aType2Metric, aMetric uint32,
+	bType RouteType, bType2Metric, bMetric uint32) int {
+	if aType != bType {
+		if aType == ROUTE_TYPE_EXTERNAL_1 {
+			return 1
+		}
+		return -1
+	}
+	if aType == ROUTE_TYPE_EXTERNAL_2 && aType2Metric != bType2Metric {
+		if aType2Metric < bType2Metric {
+			return 1
+		}
+		return -1
+	}
+	if aMetric != bMetric {
+		if aMetric < bMetric {
+			return 1
+		}
+		return -1
+	}
+	return 0
+}
+
+// externalRoutes adds the routes of the AS-external-LSAs as in RFC 2328
+// 16.4, and of the NSSA-LSAs of the NSSAs.
+func (ospf *OspfServer) externalRoutes(ipv4RiDb map[Ipv4RiKey]*Ipv4Ri, asbrRiDb map[uint32]*RouterRi) {
+	lsdb := ospf.externalLsdb
+	for _, key := range lsdb.keys() {
+		lsa := lsdb.lsas[key].(*packet.ExternalLsa)
+		if lsa.Header().LsAge >= packet.MAX_AGE || lsa.Metric >= packet.LS_INFINITY ||
+			key.AdvertisingRouter == ospf.routerId {
+			continue
+		}
+		asbr := asbrRiDb[key.AdvertisingRouter]
+		if asbr == nil {
+			continue
+		}
+		ospf.addExternalRoute(ipv4RiDb, lsa, asbr)
+	}
+	for _, area := range ospf.areaDb {
+		if area.areaType() == AREA_TYPE_NSSA {
+			ospf.nssaRoutes(area, ipv4RiDb)
+		}
+	}
+}
+
+// addExternalRoute adds the route of the AS-external-LSA or NSSA-LSA lsa
+// originated by the AS boundary router asbr.
+func (ospf *OspfServer) addExternalRoute(ipv4RiDb map[Ipv4RiKey]*Ipv4Ri, lsa *packet.ExternalLsa, asbr *RouterRi) {
+	key := lsa.Header().Key()
+	metric := asbr.metric
+	nexthops := ipv4Nexthops(asbr.nexthops)
+	if lsa.ForwardingAddress != 0 {
+		forwarding := lookupRoute(ipv4RiDb, lsa.ForwardingAddress)
+		if forwarding == nil {
+			return
+		}
+		metric = forwarding.metric
+		nexthops = make([]*Ipv4Nh, 0)
+		for _, nh := range forwarding.nexthops {
+			if nh.nexthopAddress == 0 {
+				nh = &Ipv4Nh{
+					nexthopAddress:   lsa.ForwardingAddress,
+					nexthopInterface: nh.nexthopInterface,
+				}
+			}
+			nexthops = append(nexthops, nh)
+		}
+	}
+	length := prefixLength(lsa.NetworkMask)
+	external := &Ipv4Ri{
+		prefixAddress: key.LinkStateId & lsa.NetworkMask,
+		prefixLength:  length,
+		routeType:     ROUTE_TYPE_EXTERNAL_1,
+		areaId:        asbr.areaId,
+		metric:        metric + lsa.Metric,
+		nexthops:      nexthops,
+	}
+	if lsa.ExternalMetric {
+		external.routeType = ROUTE_TYPE_EXTERNAL_2
+		external.metric = metric
+		external.type2Metric = lsa.Metric
+	}
+	riKey := Ipv4RiKey{external.prefixAddress, length}
+	ri := ipv4RiDb[riKey]
+	if ri != nil && (ri.routeType == ROUTE_TYPE_INTRA_AREA || ri.routeType == ROUTE_TYPE_INTER_AREA) {
+		return
+	}
+	if ri == nil || betterExternal(external, ri) > 0 {
+		ipv4RiDb[riKey] = external
+	} else if betterExternal(external, ri) == 0 {
+		ri.nexthops = mergeNexthops(ri.nexthops, nexthops)
+	}
+}
+
+// routeCalc calculates the routing table as in RFC 2328 16, or RFC 5340
+// 4.8 for OSPFv3.
+func (ospf *OspfServer) routeCalc() {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	if ospf.version == packet.OSPFV3_VERSION {
+		ospf.ospfv3RouteCalc()
+		return
+	}
+	ipv4RiDb := make(map[Ipv4RiKey]*Ipv4Ri)
+	asbrRiDb := make(map[uint32]*RouterRi)
+	for _, area := range ospf.areaDb {
+		ospf.intraAreaRoutes(area, ipv4RiDb)
+		area.addAsbrRoutes(asbrRiDb)
+	}
+	// an area border router only looks at the summaries of the backbone
+	border := ospf.areaBorder()
+	for _, area := range ospf.areaDb {
+		if !border || area.areaId == 0 {
+			ospf.interAreaRoutes(area, ipv4RiDb, asbrRiDb)
+		}
+	}
+	if border {
+		for _, area := range ospf.areaDb {
+			if area.areaId != 0 && area.transitCapability {
+				ospf.transitAreaRoutes(area, ipv4RiDb, asbrRiDb)
+			}
+		}
+	}
+	ospf.externalRoutes(ipv4RiDb, asbrRiDb)
+	ospf.ipv4RiDb = ipv4RiDb
+	ospf.asbrRiDb = asbrRiDb
+	if log.IsLevelEnabled(log.DebugLevel) {
+		for _, ri := range ipv4RiDb {
+			log.Debugf("%s", ri)
+		}
+	}
+}
+
 var decisionChSendCount int
 var decisionChSendCountLock sync.RWMutex
 
@@ -62,10 +910,11 @@ func (ospf *OspfServer) decisionChSend(msg *DecisionChMsg) {
 	go func() {
 		decisionChSendCountLock.Lock()
 		decisionChSendCount++
+		count := decisionChSendCount
 		decisionChSendCountLock.Unlock()
-		log.Debugf("decisionChSend[%d]: begin", decisionChSendCount)
+		log.Debugf("decisionChSend[%d]: begin", count)
 		ospf.decisionCh <- msg
-		log.Debugf("decisionChSend[%d]: end", decisionChSendCount)
+		log.Debugf("decisionChSend[%d]: end", count)
 	}()
 }
 
@@ -76,6 +925,13 @@ func (ospf *OspfServer) decisionProcess() {
 		msg := <-ospf.decisionCh
 		switch msg.msgType {
 		case DECISION_CH_MSG_TYPE_DO:
+			ospf.lock.Lock()
+			ospf.routeCalc()
+			ospf.updateVirtualLinks()
+			ospf.originateSummaries()
+			ospf.originateExternals()
+			ospf.lock.Unlock()
+			ospf.fibUpdate()
 		case DECISION_CH_MSG_TYPE_EXIT:
 			goto EXIT
 		}
@@ -0,0 +1,385 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/m-asama/golsr/pkg/ospf/packet"
+)
+
+type Ipv6Nh struct {
+	nexthopAddress   [4]uint32
+	nexthopInterface *Interface
+}
+
+type Ipv6RiKey struct {
+	prefixAddress [4]uint32
+	prefixLength  uint8
+}
+
+type Ipv6Ri struct {
+	prefixAddress [4]uint32
+	prefixLength  uint8
+	routeType     RouteType
+	areaId        uint32
+	metric        uint32
+	type2Metric   uint32
+	nexthops      []*Ipv6Nh
+}
+
+func (ri *Ipv6Ri) String() string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s/%d %s area %s metric %d",
+		packet.Ipv6String(ri.prefixAddress), ri.prefixLength, ri.routeType.String(),
+		packet.Ipv4String(ri.areaId), ri.metric)
+	if ri.routeType == ROUTE_TYPE_EXTERNAL_2 {
+		fmt.Fprintf(&b, " type2-metric %d", ri.type2Metric)
+	}
+	for _, nh := range ri.nexthops {
+		fmt.Fprintf(&b, " via %s", packet.Ipv6String(nh.nexthopAddress))
+		if nh.nexthopInterface != nil {
+			fmt.Fprintf(&b, "%%%s", nh.nexthopInterface.name)
+		}
+	}
+	return b.String()
+}
+
+func maskIpv6(address [4]uint32, prefixLength uint8) [4]uint32 {
+	var masked [4]uint32
+	for i := 0; i < 4; i++ {
+		bits := int(prefixLength) - 32*i
+		switch {
+		case bits >= 32:
+			masked[i] = address[i]
+		case bits > 0:
+			masked[i] = address[i] &^ (0xffffffff >> uint(bits))
+		}
+	}
+	return masked
+}
+
+func ipv6Nexthops(nexthops []*spfNexthop) []*Ipv6Nh {
+	ipv6Nexthops := make([]*Ipv6Nh, 0, len(nexthops))
+	for _, nh := range nexthops {
+		ipv6Nexthops = append(ipv6Nexthops, &Ipv6Nh{
+			nexthopAddress:   nh.address.Ipv6,
+			nexthopInterface: nh.iface,
+		})
+	}
+	return ipv6Nexthops
+}
+
+func mergeIpv6Nexthops(nexthops, others []*Ipv6Nh) []*Ipv6Nh {
+	merged := append([]*Ipv6Nh{}, nexthops...)
+	for _, other := range others {
+		found := false
+		for _, nh := range merged {
+			if *nh == *other {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, other)
+		}
+	}
+	return merged
+}
+
+// spfOspfv3RouterLsa returns the router-LSAs of routerId merged into one
+// as in RFC 5340 4.8.1, or nil if it has none.
+func (area *Area) spfOspfv3RouterLsa(routerId uint32) packet.OspfLsa {
+	keys := make([]packet.LsaKey, 0)
+	for key, lsa := range area.lsdb.lsas {
+		if key.LsType == packet.LS_TYPE_OSPFV3_ROUTER && key.AdvertisingRouter == routerId &&
+			lsa.Header().LsAge < packet.MAX_AGE {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].LinkStateId < keys[j].LinkStateId
+	})
+	merged, _ := packet.NewOspfv3RouterLsa()
+	for i, key := range keys {
+		lsa, ok := area.lsdb.lsas[key].(*packet.Ospfv3RouterLsa)
+		if !ok {
+			continue
+		}
+		if i == 0 {
+			*merged.Header() = *lsa.Header()
+			merged.Options = lsa.Options
+		}
+		merged.Border = merged.Border || lsa.Border
+		merged.External = merged.External || lsa.External
+		merged.VirtualLink = merged.VirtualLink || lsa.VirtualLink
+		merged.Links = append(merged.Links, lsa.Links...)
+	}
+	return merged
+}
+
+// addIpv6IntraAreaRoute adds a route calculated from the area to
+// ipv6RiDb, keeping the cheapest one.
+func (ospf *OspfServer) addIpv6IntraAreaRoute(ipv6RiDb map[Ipv6RiKey]*Ipv6Ri, area *Area,
+	prefix *packet.Ipv6Prefix, metric uint32, nexthops []*Ipv6Nh) {
+	key := Ipv6RiKey{maskIpv6(prefix.Prefix, prefix.PrefixLength), prefix.PrefixLength}
+	ri := ipv6RiDb[key]
+	if ri != nil && ri.metric < metric {
+		return
+	}
+	if ri != nil && ri.metric == metric {
+		ri.nexthops = mergeIpv6Nexthops(ri.nexthops, nexthops)
+		return
+	}
+	ipv6RiDb[key] = &Ipv6Ri{
+		prefixAddress: key.prefixAddress,
+		prefixLength:  key.prefixLength,
+		routeType:     ROUTE_TYPE_INTRA_AREA,
+		areaId:        area.areaId,
+		metric:        metric,
+		nexthops:      nexthops,
+	}
+}
+
+// ipv6ConnectedNexthops returns the interface of the area a prefix of the
+// router itself is on.
+func (area *Area) ipv6ConnectedNexthops(prefix *packet.Ipv6Prefix) []*Ipv6Nh {
+	for _, iface := range area.interfaces() {
+		if iface.state == INTERFACE_STATE_DOWN {
+			continue
+		}
+		for _, addr := range iface.kernelPrefixes() {
+			if maskIpv6(addr.Address, prefix.PrefixLength) == maskIpv6(prefix.Prefix, prefix.PrefixLength) {
+				return []*Ipv6Nh{&Ipv6Nh{nexthopInterface: iface}}
+			}
+		}
+	}
+	return []*Ipv6Nh{}
+}
+
+// ospfv3IntraAreaRoutes adds the routes to the prefixes of the
+// intra-area-prefix-LSAs of the area as in RFC 5340 4.8.1.
+func (ospf *OspfServer) ospfv3IntraAreaRoutes(area *Area, ipv6RiDb map[Ipv6RiKey]*Ipv6Ri) {
+	tree := area.spf()
+	area.updateRouterRiDb(tree)
+	for _, key := range area.lsdb.keys() {
+		if key.LsType != packet.LS_TYPE_INTRA_AREA_PREFIX {
+			continue
+		}
+		lsa, ok := area.lsdb.lsas[key].(*packet.IntraAreaPrefixLsa)
+		if !ok || lsa.Header().LsAge >= packet.MAX_AGE ||
+			lsa.ReferencedAdvertisingRouter != key.AdvertisingRouter {
+			continue
+		}
+		var v *spfVertex
+		switch lsa.ReferencedLsType {
+		case packet.LS_TYPE_OSPFV3_ROUTER:
+			v = tree[spfVertexKey{VERTEX_TYPE_ROUTER, lsa.ReferencedAdvertisingRouter, 0}]
+		case packet.LS_TYPE_OSPFV3_NETWORK:
+			v = tree[spfVertexKey{VERTEX_TYPE_NETWORK, lsa.ReferencedAdvertisingRouter,
+				lsa.ReferencedLinkStateId}]
+		}
+		if v == nil {
+			continue
+		}
+		for _, prefix := range lsa.Prefixes {
+			if prefix.PrefixOptions&packet.PREFIX_OPTIONS_NU != 0 {
+				continue
+			}
+			nexthops := ipv6Nexthops(v.nexthops)
+			if v.vertexType == VERTEX_TYPE_ROUTER && v.id == ospf.routerId {
+				nexthops = area.ipv6ConnectedNexthops(prefix)
+			}
+			ospf.addIpv6IntraAreaRoute(ipv6RiDb, area, prefix, v.distance+uint32(prefix.Metric), nexthops)
+		}
+	}
+}
+
+// ospfv3InterAreaRoutes adds the routes of the inter-area-prefix-LSAs and
+// the inter-area-router-LSAs of the area as in RFC 5340 4.8.2.
+func (ospf *OspfServer) ospfv3InterAreaRoutes(area *Area, ipv6RiDb map[Ipv6RiKey]*Ipv6Ri, asbrRiDb map[uint32]*RouterRi) {
+	for _, key := range area.lsdb.keys() {
+		lsa := area.lsdb.lsas[key]
+		if lsa.Header().LsAge >= packet.MAX_AGE || ospf.selfOriginated(lsa) {
+			continue
+		}
+		br := area.routerRiDb[key.AdvertisingRouter]
+		switch lsa := lsa.(type) {
+		case *packet.InterAreaRouterLsa:
+			if br == nil || !br.border || lsa.Metric >= packet.LS_INFINITY {
+				continue
+			}
+			metric := br.metric + lsa.Metric
+			ri := asbrRiDb[lsa.DestinationRouterId]
+			if ri != nil && (ri.routeType == ROUTE_TYPE_INTRA_AREA || ri.metric < metric) {
+				continue
+			}
+			if ri != nil && ri.metric == metric {
+				ri.nexthops = mergeSpfNexthops(ri.nexthops, br.nexthops)
+				continue
+			}
+			asbrRiDb[lsa.DestinationRouterId] = &RouterRi{
+				routerId:  lsa.DestinationRouterId,
+				routeType: ROUTE_TYPE_INTER_AREA,
+				areaId:    area.areaId,
+				metric:    metric,
+				asbr:      true,
+				nexthops:  br.nexthops,
+			}
+		case *packet.InterAreaPrefixLsa:
+			if br == nil || !br.border || lsa.Metric >= packet.LS_INFINITY ||
+				lsa.Prefix.PrefixOptions&packet.PREFIX_OPTIONS_NU != 0 {
+				continue
+			}
+			metric := br.metric + lsa.Metric
+			riKey := Ipv6RiKey{maskIpv6(lsa.Prefix.Prefix, lsa.Prefix.PrefixLength), lsa.Prefix.PrefixLength}
+			ri := ipv6RiDb[riKey]
+			if ri != nil && (ri.routeType == ROUTE_TYPE_INTRA_AREA || ri.metric < metric) {
+				continue
+			}
+			if ri != nil && ri.metric == metric {
+				ri.nexthops = mergeIpv6Nexthops(ri.nexthops, ipv6Nexthops(br.nexthops))
+				continue
+			}
+			ipv6RiDb[riKey] = &Ipv6Ri{
+				prefixAddress: riKey.prefixAddress,
+				prefixLength:  riKey.prefixLength,
+				routeType:     ROUTE_TYPE_INTER_AREA,
+				areaId:        area.areaId,
+				metric:        metric,
+				nexthops:      ipv6Nexthops(br.nexthops),
+			}
+		}
+	}
+}
+
+// lookupIpv6Route returns the intra-area or inter-area route with the
+// longest prefix matching address.
+func lookupIpv6Route(ipv6RiDb map[Ipv6RiKey]*Ipv6Ri, address [4]uint32) *Ipv6Ri {
+	var best *Ipv6Ri
+	for key, ri := range ipv6RiDb {
+		if ri.routeType != ROUTE_TYPE_INTRA_AREA && ri.routeType != ROUTE_TYPE_INTER_AREA {
+			continue
+		}
+		if maskIpv6(address, key.prefixLength) != key.prefixAddress {
+			continue
+		}
+		if best == nil || key.prefixLength > best.prefixLength {
+			best = ri
+		}
+	}
+	return best
+}
+
+// ospfv3ExternalRoutes adds the routes of the AS-external-LSAs as in RFC
+// 5340 4.8.3.
+func (ospf *OspfServer) ospfv3ExternalRoutes(ipv6RiDb map[Ipv6RiKey]*Ipv6Ri, asbrRiDb map[uint32]*RouterRi) {
+	lsdb := ospf.externalLsdb
+	for _, key := range lsdb.keys() {
+		lsa, ok := lsdb.lsas[key].(*packet.Ospfv3ExternalLsa)
+		if !ok || lsa.Header().LsAge >= packet.MAX_AGE || lsa.Metric >= packet.LS_INFINITY ||
+			key.AdvertisingRouter == ospf.routerId ||
+			lsa.Prefix.PrefixOptions&packet.PREFIX_OPTIONS_NU != 0 {
+			continue
+		}
+		asbr := asbrRiDb[key.AdvertisingRouter]
+		if asbr == nil {
+			continue
+		}
+		metric := asbr.metric
+		nexthops := ipv6Nexthops(asbr.nexthops)
+		if lsa.ForwardingAddressPresent {
+			forwarding := lookupIpv6Route(ipv6RiDb, lsa.ForwardingAddress)
+			if forwarding == nil {
+				continue
+			}
+			metric = forwarding.metric
+			nexthops = make([]*Ipv6Nh, 0)
+			for _, nh := range forwarding.nexthops {
+				if nh.nexthopAddress == [4]uint32{} {
+					nh = &Ipv6Nh{
+						nexthopAddress:   lsa.ForwardingAddress,
+						nexthopInterface: nh.nexthopInterface,
+					}
+				}
+				nexthops = append(nexthops, nh)
+			}
+		}
+		external := &Ipv6Ri{
+			prefixAddress: maskIpv6(lsa.Prefix.Prefix, lsa.Prefix.PrefixLength),
+			prefixLength:  lsa.Prefix.PrefixLength,
+			routeType:     ROUTE_TYPE_EXTERNAL_1,
+			areaId:        asbr.areaId,
+			metric:        metric + lsa.Metric,
+			nexthops:      nexthops,
+		}
+		if lsa.ExternalMetric {
+			external.routeType = ROUTE_TYPE_EXTERNAL_2
+			external.metric = metric
+			external.type2Metric = lsa.Metric
+		}
+		riKey := Ipv6RiKey{external.prefixAddress, external.prefixLength}
+		ri := ipv6RiDb[riKey]
+		if ri != nil && (ri.routeType == ROUTE_TYPE_INTRA_AREA || ri.routeType == ROUTE_TYPE_INTER_AREA) {
+			continue
+		}
+		if ri == nil {
+			ipv6RiDb[riKey] = external
+			continue
+		}
+		switch compareExternal(external.routeType, external.type2Metric, external.metric,
+			ri.routeType, ri.type2Metric, ri.metric) {
+		case 1:
+			ipv6RiDb[riKey] = external
+		case 0:
+			ri.nexthops = mergeIpv6Nexthops(ri.nexthops, nexthops)
+		}
+	}
+}
+
+// ospfv3RouteCalc calculates the IPv6 routing table as in RFC 5340 4.8.
+func (ospf *OspfServer) ospfv3RouteCalc() {
+	ipv6RiDb := make(map[Ipv6RiKey]*Ipv6Ri)
+	asbrRiDb := make(map[uint32]*RouterRi)
+	for _, area := range ospf.areaDb {
+		ospf.ospfv3IntraAreaRoutes(area, ipv6RiDb)
+		area.addAsbrRoutes(asbrRiDb)
+	}
+	// an area border router only looks at the summaries of the backbone
+	border := ospf.areaBorder()
+	for _, area := range ospf.areaDb {
+		if !border || area.areaId == 0 {
+			ospf.ospfv3InterAreaRoutes(area, ipv6RiDb, asbrRiDb)
+		}
+	}
+	ospf.ospfv3ExternalRoutes(ipv6RiDb, asbrRiDb)
+	ospf.ipv6RiDb = ipv6RiDb
+	ospf.asbrRiDb = asbrRiDb
+	if log.IsLevelEnabled(log.DebugLevel) {
+		for _, ri := range ipv6RiDb {
+			log.Debugf("%s", ri)
+		}
+	}
+}
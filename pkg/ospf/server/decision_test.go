@@ -0,0 +1,90 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"testing"
+
+	"github.com/m-asama/golsr/internal/pkg/kernel"
+)
+
+func (router *testRouter) fibRoute(prefix uint32, prefixLength int) *kernel.Ipv4Route {
+	for _, route := range router.kernel.Ipv4Routes() {
+		if route.Prefix == prefix && route.PrefixLength == prefixLength {
+			return route
+		}
+	}
+	return nil
+}
+
+func TestDecisionInterArea(t *testing.T) {
+	// r1 --(area 0.0.0.1)-- r2 --(backbone)-- r3
+	network := NewMemoryNetwork()
+	r1 := startTestRouter(t, network, "r1", 0x01010101, []*testPort{
+		&testPort{area: "0.0.0.1", name: "lo", address: 0x01010101, ifType: kernel.IF_TYPE_LOOPBACK},
+		&testPort{area: "0.0.0.1", segment: "a", name: "eth0", address: 0x0a000101, ifType: kernel.IF_TYPE_POINTTOPOINT, priority: 1},
+	})
+	defer r1.stop()
+	r2 := startTestRouter(t, network, "r2", 0x02020202, []*testPort{
+		&testPort{area: "0.0.0.1", segment: "a", name: "eth0", address: 0x0a000102, ifType: kernel.IF_TYPE_POINTTOPOINT, priority: 1},
+		&testPort{segment: "b", name: "eth1", address: 0x0a000202, ifType: kernel.IF_TYPE_BROADCAST, priority: 1},
+		&testPort{name: "lo", address: 0x02020202, ifType: kernel.IF_TYPE_LOOPBACK},
+	})
+	defer r2.stop()
+	r3 := startTestRouter(t, network, "r3", 0x03030303, []*testPort{
+		&testPort{segment: "b", name: "eth0", address: 0x0a000203, ifType: kernel.IF_TYPE_BROADCAST, priority: 1},
+		&testPort{name: "lo", address: 0x03030303, ifType: kernel.IF_TYPE_LOOPBACK},
+	})
+	defer r3.stop()
+
+	waitFor(t, "routes", func() bool {
+		for _, check := range []struct {
+			router  *testRouter
+			prefix  uint32
+			length  int
+			nexthop uint32
+		}{
+			{r1, 0x03030303, 32, 0x0a000102},
+			{r1, 0x0a000200, 24, 0x0a000102},
+			{r1, 0x02020202, 32, 0x0a000102},
+			{r3, 0x01010101, 32, 0x0a000202},
+			{r3, 0x0a000100, 24, 0x0a000202},
+			{r2, 0x01010101, 32, 0x0a000101},
+			{r2, 0x03030303, 32, 0x0a000203},
+		} {
+			route := check.router.fibRoute(check.prefix, check.length)
+			if route == nil || len(route.NextHops) != 1 || route.NextHops[0].Address != check.nexthop {
+				return false
+			}
+		}
+		return true
+	})
+
+	r3.ospf.lock.RLock()
+	ri := r3.ospf.ipv4RiDb[Ipv4RiKey{0x01010101, 32}]
+	r3.ospf.lock.RUnlock()
+	if ri == nil || ri.routeType != ROUTE_TYPE_INTER_AREA || ri.metric != 20 {
+		t.Fatalf("failed inter-area route: %v", ri)
+	}
+
+	// the routes are withdrawn with the adjacency
+	network.Disconnect("r2", "eth1")
+	waitFor(t, "routes withdrawn", func() bool {
+		return r1.fibRoute(0x03030303, 32) == nil && r3.fibRoute(0x01010101, 32) == nil
+	})
+}
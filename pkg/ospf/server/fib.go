@@ -0,0 +1,211 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+	"reflect"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/m-asama/golsr/internal/pkg/kernel"
+)
+
+// SetFib enables or disables installing the routes into the kernel, which
+// is disabled by default.
+func (ospf *OspfServer) SetFib(enable bool) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	ospf.fibLock.Lock()
+	defer ospf.fibLock.Unlock()
+	ospf.fibEnable = enable
+}
+
+func ipv4FibKey(route *kernel.Ipv4Route) string {
+	return fmt.Sprintf("%d/%08x/%d", route.Table, route.Prefix, route.PrefixLength)
+}
+
+func ipv6FibKey(route *kernel.Ipv6Route) string {
+	return fmt.Sprintf("%d/%08x%08x%08x%08x/%d", route.Table,
+		route.Prefix[0], route.Prefix[1], route.Prefix[2], route.Prefix[3], route.PrefixLength)
+}
+
+func newFibIpv4Route(ri *Ipv4Ri) *kernel.Ipv4Route {
+	route := &kernel.Ipv4Route{
+		Protocol:     kernel.ROUTE_PROTOCOL_OSPF,
+		Prefix:       ri.prefixAddress,
+		PrefixLength: int(ri.prefixLength),
+		Metric:       ri.metric,
+		NextHops:     make([]*kernel.Ipv4NextHop, 0),
+	}
+	for _, nh := range ri.nexthops {
+		if nh.nexthopInterface == nil || nh.nexthopInterface.ifKernel == nil ||
+			nh.nexthopAddress == 0 {
+			continue
+		}
+		route.NextHops = append(route.NextHops, &kernel.Ipv4NextHop{
+			Address: nh.nexthopAddress,
+			IfIndex: nh.nexthopInterface.ifKernel.IfIndex,
+		})
+	}
+	return route
+}
+
+func newFibIpv6Route(ri *Ipv6Ri) *kernel.Ipv6Route {
+	route := &kernel.Ipv6Route{
+		Protocol:     kernel.ROUTE_PROTOCOL_OSPF,
+		Prefix:       ri.prefixAddress,
+		PrefixLength: int(ri.prefixLength),
+		Metric:       ri.metric,
+		NextHops:     make([]*kernel.Ipv6NextHop, 0),
+	}
+	for _, nh := range ri.nexthops {
+		if nh.nexthopInterface == nil || nh.nexthopInterface.ifKernel == nil ||
+			nh.nexthopAddress == [4]uint32{} {
+			continue
+		}
+		route.NextHops = append(route.NextHops, &kernel.Ipv6NextHop{
+			Address: nh.nexthopAddress,
+			IfIndex: nh.nexthopInterface.ifKernel.IfIndex,
+		})
+	}
+	return route
+}
+
+// preference returns the administrative distance of the routes of
+// routeType, the most specific of the configured preferences or
+// DEFAULT_PREFERENCE if there is none.
+func (ospf *OspfServer) preference(routeType RouteType) uint8 {
+	if ospf.config == nil {
+		return DEFAULT_PREFERENCE
+	}
+	c := ospf.config.Preference.Config
+	var preferences []*uint8
+	switch routeType {
+	case ROUTE_TYPE_INTRA_AREA:
+		preferences = []*uint8{c.IntraArea, c.Internal, c.All}
+	case ROUTE_TYPE_INTER_AREA:
+		preferences = []*uint8{c.InterArea, c.Internal, c.All}
+	default:
+		preferences = []*uint8{c.External, c.All}
+	}
+	for _, preference := range preferences {
+		if preference != nil {
+			return *preference
+		}
+	}
+	return DEFAULT_PREFERENCE
+}
+
+// fibRoutes returns the routes to be installed. The routes to the
+// networks the router is attached to are left to the kernel.
+func (ospf *OspfServer) fibRoutes() (map[string]*kernel.Ipv4Route, map[string]*kernel.Ipv6Route) {
+	ipv4Routes := make(map[string]*kernel.Ipv4Route)
+	ipv6Routes := make(map[string]*kernel.Ipv6Route)
+	ospf.lock.RLock()
+	defer ospf.lock.RUnlock()
+	for _, ri := range ospf.ipv4RiDb {
+		route := newFibIpv4Route(ri)
+		if len(route.NextHops) == 0 {
+			continue
+		}
+		route.Distance = ospf.preference(ri.routeType)
+		ipv4Routes[ipv4FibKey(route)] = route
+	}
+	for _, ri := range ospf.ipv6RiDb {
+		route := newFibIpv6Route(ri)
+		if len(route.NextHops) == 0 {
+			continue
+		}
+		route.Distance = ospf.preference(ri.routeType)
+		ipv6Routes[ipv6FibKey(route)] = route
+	}
+	return ipv4Routes, ipv6Routes
+}
+
+// fibUpdate brings the routes installed in the kernel in line with the
+// result of the last routing table calculation.
+func (ospf *OspfServer) fibUpdate() {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	ospf.fibLock.Lock()
+	defer ospf.fibLock.Unlock()
+	ipv4Routes := make(map[string]*kernel.Ipv4Route)
+	ipv6Routes := make(map[string]*kernel.Ipv6Route)
+	if ospf.fibEnable {
+		ipv4Routes, ipv6Routes = ospf.fibRoutes()
+	}
+	for key, route := range ospf.fibIpv4Routes {
+		// the kernel keeps a route of another metric beside the
+		// installed one instead of replacing it
+		if r, ok := ipv4Routes[key]; ok && r.Metric == route.Metric {
+			continue
+		}
+		if err := ospf.provider.DeleteIpv4Route(route); err != nil {
+			log.WithFields(log.Fields{
+				"Topic": "Fib",
+				"Route": key,
+				"Error": err,
+			}).Warn("Can't delete route")
+		}
+		delete(ospf.fibIpv4Routes, key)
+	}
+	for key, route := range ipv4Routes {
+		if installed, ok := ospf.fibIpv4Routes[key]; ok && reflect.DeepEqual(installed, route) {
+			continue
+		}
+		if err := ospf.provider.AddIpv4Route(route); err != nil {
+			log.WithFields(log.Fields{
+				"Topic": "Fib",
+				"Route": key,
+				"Error": err,
+			}).Warn("Can't add route")
+			continue
+		}
+		ospf.fibIpv4Routes[key] = route
+	}
+	for key, route := range ospf.fibIpv6Routes {
+		// the kernel keeps a route of another metric beside the
+		// installed one instead of replacing it
+		if r, ok := ipv6Routes[key]; ok && r.Metric == route.Metric {
+			continue
+		}
+		if err := ospf.provider.DeleteIpv6Route(route); err != nil {
+			log.WithFields(log.Fields{
+				"Topic": "Fib",
+				"Route": key,
+				"Error": err,
+			}).Warn("Can't delete route")
+		}
+		delete(ospf.fibIpv6Routes, key)
+	}
+	for key, route := range ipv6Routes {
+		if installed, ok := ospf.fibIpv6Routes[key]; ok && reflect.DeepEqual(installed, route) {
+			continue
+		}
+		if err := ospf.provider.AddIpv6Route(route); err != nil {
+			log.WithFields(log.Fields{
+				"Topic": "Fib",
+				"Route": key,
+				"Error": err,
+			}).Warn("Can't add route")
+			continue
+		}
+		ospf.fibIpv6Routes[key] = route
+	}
+}
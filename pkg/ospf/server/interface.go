@@ -0,0 +1,935 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/m-asama/golsr/internal/pkg/kernel"
+	"github.com/m-asama/golsr/internal/pkg/ospf/config"
+	"github.com/m-asama/golsr/pkg/ospf/packet"
+)
+
+const DEFAULT_MTU = 1500
+
+type Interface struct {
+	ospf     *OspfServer
+	area     *Area
+	name     string
+	ifConfig *config.Interface
+	ifKernel *kernel.Interface
+
+	transport    Transport
+	state        InterfaceState
+	address      uint32
+	prefixLength int
+	mtu          int
+	dr           uint32
+	bdr          uint32
+	helloTimer   int
+	waitTimer    int
+	neighborDb   []*Neighbor
+	delayedAcks  []*packet.LsaHeader
+
+	// linkLocal and interfaceId are the link-local address and the
+	// interface ID, the ifindex, of an OSPFv3 interface, and lsdb holds
+	// the LSAs of link-local flooding scope.
+	linkLocal   [4]uint32
+	interfaceId uint32
+	lsdb        *Lsdb
+
+	// virtualLink is set if the interface is a virtual link.
+	virtualLink *virtualLink
+
+	// ttlSecurityDrops counts the packets dropped by the TTL security
+	// check.
+	ttlSecurityDrops uint64
+}
+
+func NewInterface(ospf *OspfServer, area *Area, name string) *Interface {
+	log.Debugf("enter: %s", name)
+	defer log.Debugf("exit: %s", name)
+	iface := &Interface{
+		ospf:       ospf,
+		area:       area,
+		name:       name,
+		state:      INTERFACE_STATE_DOWN,
+		neighborDb: make([]*Neighbor, 0),
+	}
+	iface.lsdb = NewLsdb(ospf, area, iface)
+	return iface
+}
+
+func (iface *Interface) String() string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "name                            %s\n", iface.name)
+	fmt.Fprintf(&b, "area                            %s\n", packet.Ipv4String(iface.area.areaId))
+	fmt.Fprintf(&b, "state                           %s\n", iface.state.String())
+	if iface.ospf.version == packet.OSPFV3_VERSION {
+		fmt.Fprintf(&b, "address                         %s\n", packet.Ipv6String(iface.linkLocal))
+		fmt.Fprintf(&b, "interface-id                    %d\n", iface.interfaceId)
+	} else {
+		fmt.Fprintf(&b, "address                         %s/%d\n", packet.Ipv4String(iface.address), iface.prefixLength)
+	}
+	fmt.Fprintf(&b, "dr                              %s\n", packet.Ipv4String(iface.dr))
+	fmt.Fprintf(&b, "bdr                             %s\n", packet.Ipv4String(iface.bdr))
+	for _, nbr := range iface.neighborDb {
+		fmt.Fprintf(&b, "neighbor                        %s %s\n",
+			packet.Ipv4String(nbr.routerId), nbr.state.String())
+	}
+	return b.String()
+}
+
+func (iface *Interface) enable() bool {
+	return *iface.ifConfig.Config.Enable
+}
+
+func (iface *Interface) passive() bool {
+	return *iface.ifConfig.Config.Passive
+}
+
+func (iface *Interface) networkType() NetworkType {
+	switch *iface.ifConfig.Config.InterfaceType {
+	case "broadcast":
+		return NETWORK_TYPE_BROADCAST
+	case "non-broadcast":
+		return NETWORK_TYPE_NBMA
+	case "point-to-multipoint":
+		return NETWORK_TYPE_POINT_TO_MULTIPOINT
+	}
+	return NETWORK_TYPE_POINT_TO_POINT
+}
+
+// multiAccess tells whether the network of the interface elects a
+// designated router: a broadcast or an NBMA network.
+func (iface *Interface) multiAccess() bool {
+	switch iface.networkType() {
+	case NETWORK_TYPE_BROADCAST, NETWORK_TYPE_NBMA:
+		return true
+	}
+	return false
+}
+
+// multicast tells whether the packets of the interface are multicast.
+// Those of an NBMA network, and of a point-to-multipoint network with
+// static neighbors, are unicast to each neighbor instead.
+func (iface *Interface) multicast() bool {
+	switch iface.networkType() {
+	case NETWORK_TYPE_NBMA:
+		return false
+	case NETWORK_TYPE_POINT_TO_MULTIPOINT:
+		return len(iface.ifConfig.StaticNeighbors) == 0
+	}
+	return true
+}
+
+func (iface *Interface) helloInterval() uint16 {
+	return *iface.ifConfig.Config.HelloInterval
+}
+
+func (iface *Interface) deadInterval() uint32 {
+	return *iface.ifConfig.Config.DeadInterval
+}
+
+func (iface *Interface) retransmitInterval() uint16 {
+	return *iface.ifConfig.Config.RetransmitInterval
+}
+
+func (iface *Interface) priority() uint8 {
+	return *iface.ifConfig.Config.Priority
+}
+
+func (iface *Interface) cost() uint16 {
+	return *iface.ifConfig.Config.Cost
+}
+
+func (iface *Interface) mtuIgnore() bool {
+	return *iface.ifConfig.Config.MtuIgnore
+}
+
+func (iface *Interface) instanceId() uint8 {
+	if iface.ifConfig.Config.InstanceId == nil {
+		return 0
+	}
+	return *iface.ifConfig.Config.InstanceId
+}
+
+// headerLength is the length of the IP and OSPF headers of the packets
+// of the interface, together with the authentication data appended to
+// them.
+func (iface *Interface) headerLength() int {
+	if iface.ospf.version == packet.OSPFV3_VERSION {
+		return IPV6_HEADER_LENGTH + packet.OSPFV3_PACKET_HEADER_LENGTH + iface.authLength()
+	}
+	return IPV4_HEADER_LENGTH + packet.PACKET_HEADER_LENGTH + iface.authLength()
+}
+
+// id identifies the router on the network in the designated router
+// fields: the interface address for OSPFv2 and the router ID for OSPFv3.
+func (iface *Interface) id() uint32 {
+	if iface.ospf.version == packet.OSPFV3_VERSION {
+		return iface.ospf.routerId
+	}
+	return iface.address
+}
+
+func (iface *Interface) allSpfRouters() Address {
+	return allSpfRouters(iface.ospf.version)
+}
+
+func (iface *Interface) allDRouters() Address {
+	return allDRouters(iface.ospf.version)
+}
+
+func (iface *Interface) kernelUp() bool {
+	return iface.ifKernel != nil && iface.ifKernel.Up
+}
+
+// kernelAddress returns the first address of the interface in the
+// kernel, which is the one OSPF runs on.
+func (iface *Interface) kernelAddress() (uint32, int) {
+	if iface.ifKernel == nil {
+		return 0, 0
+	}
+	for _, addr := range iface.ifKernel.Ipv4Addresses {
+		if !addr.ScopeHost {
+			return addr.Address, addr.PrefixLength
+		}
+	}
+	return 0, 0
+}
+
+// kernelLinkLocal returns the link-local address of the interface in the
+// kernel, which OSPFv3 runs on.
+func (iface *Interface) kernelLinkLocal() [4]uint32 {
+	if iface.ifKernel == nil {
+		return [4]uint32{}
+	}
+	for _, addr := range iface.ifKernel.Ipv6Addresses {
+		if addr.ScopeLink {
+			return addr.Address
+		}
+	}
+	return [4]uint32{}
+}
+
+// kernelPrefixes returns the global IPv6 addresses of the interface in
+// the kernel, which OSPFv3 advertises.
+func (iface *Interface) kernelPrefixes() []*kernel.Ipv6Address {
+	prefixes := make([]*kernel.Ipv6Address, 0)
+	if iface.ifKernel == nil {
+		return prefixes
+	}
+	for _, addr := range iface.ifKernel.Ipv6Addresses {
+		if !addr.ScopeLink && !addr.ScopeHost {
+			prefixes = append(prefixes, addr)
+		}
+	}
+	return prefixes
+}
+
+// ready tells whether the interface can be up. An OSPFv3 interface
+// needs a link-local address, which a loopback does not have.
+func (iface *Interface) ready() bool {
+	if !iface.ospf.ready() || iface.ifConfig == nil || !iface.enable() || !iface.kernelUp() {
+		return false
+	}
+	if iface.ospf.version == packet.OSPFV3_VERSION {
+		return iface.ifKernel.IfType == kernel.IF_TYPE_LOOPBACK ||
+			iface.kernelLinkLocal() != [4]uint32{}
+	}
+	address, _ := iface.kernelAddress()
+	return address != 0
+}
+
+func (iface *Interface) networkMask() uint32 {
+	if iface.prefixLength == 0 {
+		return 0
+	}
+	return ^uint32(0) << uint(32-iface.prefixLength)
+}
+
+func (iface *Interface) setState(state InterfaceState) {
+	if state == iface.state {
+		return
+	}
+	log.WithFields(log.Fields{
+		"Topic":     "Interface",
+		"Interface": iface.name,
+		"Old":       iface.state.String(),
+		"New":       state.String(),
+	}).Info("Interface state changed")
+	wasDr := iface.state == INTERFACE_STATE_DR || iface.state == INTERFACE_STATE_BACKUP
+	isDr := state == INTERFACE_STATE_DR || state == INTERFACE_STATE_BACKUP
+	iface.state = state
+	if iface.transport == nil || wasDr == isDr {
+		return
+	}
+	var err error
+	if isDr {
+		err = iface.transport.JoinGroup(iface.allDRouters())
+	} else {
+		err = iface.transport.LeaveGroup(iface.allDRouters())
+	}
+	if err != nil {
+		log.WithFields(log.Fields{
+			"Topic":     "Interface",
+			"Interface": iface.name,
+			"Error":     err,
+		}).Warn("AllDRouters membership failed")
+	}
+}
+
+// up runs the InterfaceUp event.
+func (iface *Interface) up() {
+	log.Debugf("enter: %s", iface.name)
+	defer log.Debugf("exit: %s", iface.name)
+	iface.address, iface.prefixLength = iface.kernelAddress()
+	iface.linkLocal = iface.kernelLinkLocal()
+	iface.interfaceId = uint32(iface.ifKernel.IfIndex)
+	iface.mtu = iface.ifKernel.Mtu
+	if iface.mtu == 0 {
+		iface.mtu = DEFAULT_MTU
+	}
+	if iface.ifKernel.IfType == kernel.IF_TYPE_LOOPBACK {
+		iface.setState(INTERFACE_STATE_LOOPBACK)
+		iface.area.originateRouterLsa()
+		return
+	}
+	if !iface.passive() {
+		transport, err := iface.ospf.transport(iface.ifKernel, iface.ospf.version)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"Topic":     "Interface",
+				"Interface": iface.name,
+				"Error":     err,
+			}).Warn("Can't open transport")
+			return
+		}
+		iface.transport = transport
+		iface.updateTtlSecurity()
+		go iface.receiver(transport)
+	}
+	if !iface.multicast() {
+		iface.addStaticNeighbors()
+	}
+	switch {
+	case !iface.multiAccess():
+		iface.setState(INTERFACE_STATE_POINT_TO_POINT)
+	case iface.passive():
+		// no neighbor will ever be seen
+		iface.electDr()
+	case iface.priority() == 0:
+		iface.setState(INTERFACE_STATE_DR_OTHER)
+	default:
+		iface.setState(INTERFACE_STATE_WAITING)
+		iface.waitTimer = int(iface.deadInterval())
+	}
+	iface.sendHello()
+	iface.startStaticNeighbors()
+	iface.originateLinkLsa()
+	iface.area.originateRouterLsa()
+}
+
+// down runs the InterfaceDown event.
+func (iface *Interface) down() {
+	log.Debugf("enter: %s", iface.name)
+	defer log.Debugf("exit: %s", iface.name)
+	if iface.state == INTERFACE_STATE_DOWN {
+		return
+	}
+	iface.setState(INTERFACE_STATE_DOWN)
+	for _, nbr := range append([]*Neighbor{}, iface.neighborDb...) {
+		nbr.event(NEIGHBOR_EVENT_KILL_NBR)
+	}
+	iface.neighborDb = make([]*Neighbor, 0)
+	if iface.transport != nil {
+		iface.transport.Close()
+		iface.transport = nil
+	}
+	iface.originateNetworkLsa()
+	iface.dr = 0
+	iface.bdr = 0
+	iface.delayedAcks = nil
+	iface.lsdb = NewLsdb(iface.ospf, iface.area, iface)
+	iface.area.originateRouterLsa()
+}
+
+func (iface *Interface) event(event InterfaceEvent) {
+	log.WithFields(log.Fields{
+		"Topic":     "Interface",
+		"Interface": iface.name,
+		"State":     iface.state.String(),
+		"Event":     event.String(),
+	}).Debug("event")
+	switch event {
+	case INTERFACE_EVENT_WAIT_TIMER, INTERFACE_EVENT_BACKUP_SEEN:
+		if iface.state == INTERFACE_STATE_WAITING {
+			iface.electDr()
+		}
+	case INTERFACE_EVENT_NEIGHBOR_CHANGE:
+		switch iface.state {
+		case INTERFACE_STATE_DR_OTHER, INTERFACE_STATE_BACKUP, INTERFACE_STATE_DR:
+			iface.electDr()
+		}
+	case INTERFACE_EVENT_INTERFACE_DOWN:
+		iface.down()
+	}
+}
+
+// drCandidate is a router eligible to become the designated router, which
+// is identified by its id, the interface address for OSPFv2 and the
+// router ID for OSPFv3.
+type drCandidate struct {
+	routerId uint32
+	id       uint32
+	priority uint8
+	dr       uint32
+	bdr      uint32
+}
+
+func (c *drCandidate) better(other *drCandidate) bool {
+	if c.priority != other.priority {
+		return c.priority > other.priority
+	}
+	return c.routerId > other.routerId
+}
+
+// electBdr and electDrOf are steps 2 and 3 of RFC 2328 9.4.
+func electBdr(candidates []*drCandidate) uint32 {
+	var best *drCandidate
+	bestDeclared := false
+	for _, c := range candidates {
+		if c.dr == c.id {
+			continue
+		}
+		declared := c.bdr == c.id
+		if best == nil || (declared && !bestDeclared) ||
+			(declared == bestDeclared && c.better(best)) {
+			best = c
+			bestDeclared = declared
+		}
+	}
+	if best == nil {
+		return 0
+	}
+	return best.id
+}
+
+func electDrOf(candidates []*drCandidate, bdr uint32) uint32 {
+	var best *drCandidate
+	for _, c := range candidates {
+		if c.dr == c.id && (best == nil || c.better(best)) {
+			best = c
+		}
+	}
+	if best == nil {
+		return bdr
+	}
+	return best.id
+}
+
+// electDr elects the designated router and the backup designated router
+// of the network as in RFC 2328 9.4.
+func (iface *Interface) electDr() {
+	log.Debugf("enter: %s", iface.name)
+	defer log.Debugf("exit: %s", iface.name)
+	self := &drCandidate{
+		routerId: iface.ospf.routerId,
+		id:       iface.id(),
+		priority: iface.priority(),
+		dr:       iface.dr,
+		bdr:      iface.bdr,
+	}
+	candidates := make([]*drCandidate, 0)
+	if self.priority > 0 {
+		candidates = append(candidates, self)
+	}
+	for _, nbr := range iface.neighborDb {
+		if nbr.state >= NEIGHBOR_STATE_TWO_WAY && nbr.priority > 0 {
+			candidates = append(candidates, &drCandidate{
+				routerId: nbr.routerId,
+				id:       nbr.id(),
+				priority: nbr.priority,
+				dr:       nbr.dr,
+				bdr:      nbr.bdr,
+			})
+		}
+	}
+	bdr := electBdr(candidates)
+	dr := electDrOf(candidates, bdr)
+	if self.priority > 0 &&
+		((dr == self.id) != (self.dr == self.id) ||
+			(bdr == self.id) != (self.bdr == self.id)) {
+		self.dr = dr
+		self.bdr = bdr
+		bdr = electBdr(candidates)
+		dr = electDrOf(candidates, bdr)
+	}
+	oldDr, oldBdr := iface.dr, iface.bdr
+	iface.dr, iface.bdr = dr, bdr
+	switch {
+	case dr == iface.id():
+		iface.setState(INTERFACE_STATE_DR)
+	case bdr == iface.id():
+		iface.setState(INTERFACE_STATE_BACKUP)
+	default:
+		iface.setState(INTERFACE_STATE_DR_OTHER)
+	}
+	if dr != oldDr || bdr != oldBdr {
+		log.WithFields(log.Fields{
+			"Topic":     "Interface",
+			"Interface": iface.name,
+			"DR":        packet.Ipv4String(dr),
+			"BDR":       packet.Ipv4String(bdr),
+		}).Info("DR elected")
+		for _, nbr := range append([]*Neighbor{}, iface.neighborDb...) {
+			if nbr.state >= NEIGHBOR_STATE_TWO_WAY {
+				nbr.event(NEIGHBOR_EVENT_ADJ_OK)
+			}
+		}
+		// RFC 2328 9.4 (7): the designated router and the backup of an
+		// NBMA network start the neighbors ineligible to become one
+		iface.startStaticNeighbors()
+	}
+	iface.area.originateRouterLsa()
+	iface.originateNetworkLsa()
+}
+
+// fullWithDr tells whether the router is fully adjacent to the designated
+// router of the network, which makes it a transit network.
+func (iface *Interface) fullWithDr() bool {
+	for _, nbr := range iface.neighborDb {
+		if nbr.state != NEIGHBOR_STATE_FULL {
+			continue
+		}
+		if iface.state == INTERFACE_STATE_DR || nbr.id() == iface.dr {
+			return true
+		}
+	}
+	return false
+}
+
+// routerLinks returns the links the interface adds to the router-LSA of
+// its area as in RFC 2328 12.4.1.
+func (iface *Interface) routerLinks() []*packet.RouterLink {
+	if iface.virtualLink != nil {
+		return iface.virtualLinkRouterLinks()
+	}
+	links := make([]*packet.RouterLink, 0)
+	stub := &packet.RouterLink{
+		LinkId:   iface.address & iface.networkMask(),
+		LinkData: iface.networkMask(),
+		Type:     packet.LINK_TYPE_STUB,
+		Metric:   iface.cost(),
+	}
+	switch iface.state {
+	case INTERFACE_STATE_DOWN:
+	case INTERFACE_STATE_LOOPBACK:
+		links = append(links, &packet.RouterLink{
+			LinkId:   iface.address,
+			LinkData: 0xffffffff,
+			Type:     packet.LINK_TYPE_STUB,
+			Metric:   0,
+		})
+	case INTERFACE_STATE_POINT_TO_POINT:
+		for _, nbr := range iface.neighborDb {
+			if nbr.state == NEIGHBOR_STATE_FULL {
+				links = append(links, &packet.RouterLink{
+					LinkId:   nbr.routerId,
+					LinkData: iface.address,
+					Type:     packet.LINK_TYPE_POINT_TO_POINT,
+					Metric:   nbr.cost(),
+				})
+			}
+		}
+		if iface.networkType() == NETWORK_TYPE_POINT_TO_MULTIPOINT {
+			// RFC 2328 12.4.1.4: the address of the interface
+			// instead of the network
+			stub = &packet.RouterLink{
+				LinkId:   iface.address,
+				LinkData: 0xffffffff,
+				Type:     packet.LINK_TYPE_STUB,
+				Metric:   0,
+			}
+		}
+		links = append(links, stub)
+	case INTERFACE_STATE_WAITING:
+		links = append(links, stub)
+	default:
+		if iface.fullWithDr() {
+			links = append(links, &packet.RouterLink{
+				LinkId:   iface.dr,
+				LinkData: iface.address,
+				Type:     packet.LINK_TYPE_TRANSIT,
+				Metric:   iface.cost(),
+			})
+		} else {
+			links = append(links, stub)
+		}
+	}
+	return links
+}
+
+func (iface *Interface) tick() {
+	if iface.state == INTERFACE_STATE_DOWN || iface.state == INTERFACE_STATE_LOOPBACK {
+		return
+	}
+	if iface.waitTimer > 0 {
+		iface.waitTimer--
+		if iface.waitTimer == 0 {
+			iface.event(INTERFACE_EVENT_WAIT_TIMER)
+		}
+	}
+	iface.helloTimer--
+	if iface.helloTimer <= 0 {
+		iface.sendHello()
+	}
+	iface.pollStaticNeighbors()
+	if len(iface.delayedAcks) > 0 {
+		for _, dst := range iface.floodDsts() {
+			iface.sendAcks(iface.delayedAcks, dst)
+		}
+		iface.delayedAcks = nil
+	}
+	for _, nbr := range append([]*Neighbor{}, iface.neighborDb...) {
+		nbr.tick()
+	}
+}
+
+// findNeighbor finds the neighbor a packet is from. OSPFv2 neighbors on a
+// network other than a point-to-point one are identified by their
+// addresses and the others by their router IDs, but for the static
+// neighbors not heard from yet.
+func (iface *Interface) findNeighbor(routerId uint32, src Address) *Neighbor {
+	for _, nbr := range iface.neighborDb {
+		if iface.networkType() != NETWORK_TYPE_POINT_TO_POINT &&
+			iface.ospf.version != packet.OSPFV3_VERSION {
+			if nbr.src() == src {
+				return nbr
+			}
+		} else if nbr.routerId == routerId || (nbr.routerId == 0 && nbr.src() == src) {
+			return nbr
+		}
+	}
+	return nil
+}
+
+func (iface *Interface) removeNeighbor(nbr *Neighbor) {
+	neighborDb := make([]*Neighbor, 0)
+	for _, tmp := range iface.neighborDb {
+		if tmp != nbr {
+			neighborDb = append(neighborDb, tmp)
+		}
+	}
+	iface.neighborDb = neighborDb
+}
+
+func (iface *Interface) send(pkt packet.OspfPacket, dst Address) {
+	if iface.transport == nil {
+		return
+	}
+	pkt.SetVersion(iface.ospf.version)
+	if iface.ospf.version == packet.OSPFV3_VERSION {
+		pkt.SetInstanceId(iface.instanceId())
+	}
+	pkt.SetRouterId(iface.ospf.routerId)
+	pkt.SetAreaId(iface.area.areaId)
+	data, err := iface.signPacket(pkt)
+	if err == nil {
+		err = iface.transport.Send(data, dst)
+	}
+	if err != nil {
+		log.WithFields(log.Fields{
+			"Topic":     "Interface",
+			"Interface": iface.name,
+			"Type":      pkt.PacketType().String(),
+			"Error":     err,
+		}).Warn("Send failed")
+	}
+}
+
+// floodDsts are the addresses LSAs and delayed acknowledgments are sent
+// to on the interface: a multicast address or, without multicast, those
+// of the adjacent neighbors as in RFC 2328 13.3.
+func (iface *Interface) floodDsts() []Address {
+	if !iface.multicast() {
+		dsts := make([]Address, 0)
+		for _, nbr := range iface.neighborDb {
+			if nbr.state >= NEIGHBOR_STATE_EXCHANGE {
+				dsts = append(dsts, nbr.src())
+			}
+		}
+		return dsts
+	}
+	if iface.networkType() == NETWORK_TYPE_BROADCAST &&
+		iface.state != INTERFACE_STATE_DR && iface.state != INTERFACE_STATE_BACKUP {
+		return []Address{iface.allDRouters()}
+	}
+	return []Address{iface.allSpfRouters()}
+}
+
+// sendLsas sends lsas to dst in as many link state updates as the MTU
+// requires, their ages incremented by InfTransDelay.
+func (iface *Interface) sendLsas(lsas []packet.OspfLsa, dst Address) {
+	lsu, _ := packet.NewLinkStateUpdatePacket()
+	length := iface.headerLength() + 4
+	for _, lsa := range lsas {
+		copied := copyLsa(lsa)
+		if copied == nil {
+			continue
+		}
+		age := copied.Header().LsAge + *iface.ifConfig.Config.TransmitDelay
+		if age > packet.MAX_AGE {
+			age = packet.MAX_AGE
+		}
+		copied.Header().LsAge = age
+		if len(lsu.Lsas) > 0 && length+int(copied.Header().Length()) > iface.mtu {
+			iface.send(lsu, dst)
+			lsu, _ = packet.NewLinkStateUpdatePacket()
+			length = iface.headerLength() + 4
+		}
+		lsu.AddLsa(copied)
+		length += int(copied.Header().Length())
+	}
+	if len(lsu.Lsas) > 0 {
+		iface.send(lsu, dst)
+	}
+}
+
+// sendAcks acknowledges the LSAs of headers to dst.
+func (iface *Interface) sendAcks(headers []*packet.LsaHeader, dst Address) {
+	n := (iface.mtu - iface.headerLength()) / packet.LSA_HEADER_LENGTH
+	for len(headers) > 0 {
+		if n > len(headers) {
+			n = len(headers)
+		}
+		ack, _ := packet.NewLinkStateAckPacket()
+		ack.LsaHeaders = append(ack.LsaHeaders, headers[:n]...)
+		headers = headers[n:]
+		iface.send(ack, dst)
+	}
+}
+
+func (iface *Interface) delayAck(header *packet.LsaHeader) {
+	iface.delayedAcks = append(iface.delayedAcks, header)
+}
+
+func (iface *Interface) newHello() *packet.HelloPacket {
+	hello, _ := packet.NewHelloPacket()
+	if iface.ospf.version == packet.OSPFV3_VERSION {
+		hello.InterfaceId = iface.interfaceId
+	} else {
+		hello.NetworkMask = iface.networkMask()
+	}
+	hello.HelloInterval = iface.helloInterval()
+	hello.Options = iface.options()
+	hello.RouterPriority = iface.priority()
+	hello.RouterDeadInterval = iface.deadInterval()
+	hello.DesignatedRouter = iface.dr
+	hello.BackupDesignatedRouter = iface.bdr
+	for _, nbr := range iface.neighborDb {
+		if nbr.state >= NEIGHBOR_STATE_INIT {
+			hello.Neighbors = append(hello.Neighbors, nbr.routerId)
+		}
+	}
+	return hello
+}
+
+// sendHello sends a hello to the network or, without multicast, to each
+// neighbor it is sent to but those down, which are polled instead.
+func (iface *Interface) sendHello() {
+	iface.helloTimer = int(iface.helloInterval())
+	hello := iface.newHello()
+	if iface.multicast() {
+		iface.send(hello, iface.allSpfRouters())
+		return
+	}
+	for _, nbr := range iface.neighborDb {
+		if nbr.state > NEIGHBOR_STATE_DOWN && iface.helloTo(nbr) {
+			iface.send(hello, nbr.src())
+		}
+	}
+}
+
+// receiver passes the packets received on transport to receive until the
+// interface stops using it.
+func (iface *Interface) receiver(transport Transport) {
+	buf := make([]byte, OSPF_PACKET_BUFFER_LENGTH)
+	for {
+		n, info, err := transport.Recv(buf)
+		iface.ospf.lock.Lock()
+		if iface.transport != transport {
+			iface.ospf.lock.Unlock()
+			return
+		}
+		if err == nil {
+			iface.receive(append([]byte{}, buf[:n]...), info)
+		}
+		iface.ospf.lock.Unlock()
+	}
+}
+
+// receive checks a received packet as in RFC 2328 8.2 and passes it to
+// the interface or the neighbor it is for.
+func (iface *Interface) receive(data []byte, info *PacketInfo) {
+	pkt, err := packet.DecodePacketFromBytes(data)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"Topic":     "Interface",
+			"Interface": iface.name,
+			"Error":     err,
+		}).Debug("Decode failed")
+		return
+	}
+	if !pkt.BaseValid() || pkt.Version() != iface.ospf.version {
+		return
+	}
+	if pkt.AreaId() == BACKBONE_AREA_ID && iface.area.areaId != BACKBONE_AREA_ID {
+		// RFC 2328 8.2: a packet of a virtual link through the area
+		vlink := iface.ospf.findVirtualLink(iface.area.areaId, pkt.RouterId())
+		if vlink != nil && iface.area.virtualLinkDst(info.Dst) {
+			vlink.receivePacket(pkt, data, info)
+		}
+		return
+	}
+	iface.receivePacket(pkt, data, info)
+}
+
+// receivePacket checks the rest of a packet received on the interface in
+// data, its TTL and its authentication included.
+func (iface *Interface) receivePacket(pkt packet.OspfPacket, data []byte, info *PacketInfo) {
+	if !iface.ttlValid(info) {
+		return
+	}
+	if info.Dst == iface.allDRouters() &&
+		iface.state != INTERFACE_STATE_DR && iface.state != INTERFACE_STATE_BACKUP {
+		return
+	}
+	if pkt.AreaId() != iface.area.areaId || pkt.RouterId() == iface.ospf.routerId {
+		return
+	}
+	if iface.ospf.version == packet.OSPFV3_VERSION {
+		// RFC 5340 4.2.2
+		if pkt.InstanceId() != iface.instanceId() {
+			return
+		}
+	} else {
+		if iface.multiAccess() &&
+			info.Src.Ipv4&iface.networkMask() != iface.address&iface.networkMask() {
+			return
+		}
+	}
+	seqNum, ok := iface.verifyPacket(pkt, data, info.Src)
+	if !ok {
+		iface.authFailed(pkt, info.Src)
+		return
+	}
+	cryptographic := iface.cryptographic()
+	nbr := iface.findNeighbor(pkt.RouterId(), info.Src)
+	if cryptographic && nbr != nil && nbr.replayed(seqNum) {
+		iface.authFailed(pkt, info.Src)
+		return
+	}
+	if cryptographic {
+		// the hello may create the neighbor
+		defer func() {
+			if nbr := iface.findNeighbor(pkt.RouterId(), info.Src); nbr != nil {
+				nbr.cryptoSeqNum = seqNum
+			}
+		}()
+	}
+	if hello, ok := pkt.(*packet.HelloPacket); ok {
+		iface.receiveHello(hello, info)
+		return
+	}
+	if nbr == nil {
+		return
+	}
+	switch p := pkt.(type) {
+	case *packet.DatabaseDescriptionPacket:
+		nbr.receiveDd(p)
+	case *packet.LinkStateRequestPacket:
+		nbr.receiveLsr(p)
+	case *packet.LinkStateUpdatePacket:
+		nbr.receiveLsu(p)
+	case *packet.LinkStateAckPacket:
+		nbr.receiveLsAck(p)
+	}
+}
+
+// receiveHello processes a hello as in RFC 2328 10.5.
+func (iface *Interface) receiveHello(hello *packet.HelloPacket, info *PacketInfo) {
+	if iface.multiAccess() &&
+		iface.ospf.version != packet.OSPFV3_VERSION &&
+		hello.NetworkMask != iface.networkMask() {
+		return
+	}
+	if hello.HelloInterval != iface.helloInterval() ||
+		hello.RouterDeadInterval != iface.deadInterval() {
+		return
+	}
+	// RFC 2328 10.5 and RFC 3101 2.3
+	mask := packet.Options(packet.OPTIONS_E | packet.OPTIONS_NP)
+	if hello.Options&mask != iface.area.options()&mask {
+		return
+	}
+	nbr := iface.findNeighbor(hello.RouterId(), info.Src)
+	if nbr == nil {
+		nbr = NewNeighbor(iface, hello.RouterId(), info.Src)
+		iface.neighborDb = append(iface.neighborDb, nbr)
+	}
+	nbr.routerId = hello.RouterId()
+	nbr.address = info.Src.Ipv4
+	nbr.address6 = info.Src.Ipv6
+	nbr.interfaceId = hello.InterfaceId
+	oldPriority, oldDr, oldBdr := nbr.priority, nbr.dr, nbr.bdr
+	nbr.priority = hello.RouterPriority
+	nbr.dr = hello.DesignatedRouter
+	nbr.bdr = hello.BackupDesignatedRouter
+	nbr.event(NEIGHBOR_EVENT_HELLO_RECEIVED)
+	iface.replyHello(nbr)
+	seen := false
+	for _, routerId := range hello.Neighbors {
+		if routerId == iface.ospf.routerId {
+			seen = true
+			break
+		}
+	}
+	if !seen {
+		nbr.event(NEIGHBOR_EVENT_ONE_WAY_RECEIVED)
+		return
+	}
+	nbr.event(NEIGHBOR_EVENT_TWO_WAY_RECEIVED)
+	if !iface.multiAccess() {
+		return
+	}
+	if iface.state == INTERFACE_STATE_WAITING &&
+		((nbr.dr == nbr.id() && nbr.bdr == 0) || nbr.bdr == nbr.id()) {
+		iface.event(INTERFACE_EVENT_BACKUP_SEEN)
+		return
+	}
+	if nbr.priority != oldPriority ||
+		(nbr.dr == nbr.id()) != (oldDr == nbr.id()) ||
+		(nbr.bdr == nbr.id()) != (oldBdr == nbr.id()) {
+		iface.event(INTERFACE_EVENT_NEIGHBOR_CHANGE)
+	}
+}
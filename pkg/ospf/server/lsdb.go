@@ -0,0 +1,676 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/m-asama/golsr/pkg/ospf/packet"
+)
+
+// Lsdb holds the LSAs of one flooding scope: a link for the link-LSAs of
+// OSPFv3, an area, or the whole AS for the AS-external-LSAs.
+type Lsdb struct {
+	ospf  *OspfServer
+	area  *Area
+	iface *Interface
+
+	lsas     map[packet.LsaKey]packet.OspfLsa
+	arrivals map[packet.LsaKey]time.Time
+	// pending are the LSAs waiting for the instance of maximum sequence
+	// number to be flushed before they can be originated.
+	pending map[packet.LsaKey]packet.OspfLsa
+}
+
+// NewLsdb returns an LSDB of the link of iface if not nil, or else of
+// area if not nil, or else of the AS.
+func NewLsdb(ospf *OspfServer, area *Area, iface *Interface) *Lsdb {
+	return &Lsdb{
+		ospf:     ospf,
+		area:     area,
+		iface:    iface,
+		lsas:     make(map[packet.LsaKey]packet.OspfLsa),
+		arrivals: make(map[packet.LsaKey]time.Time),
+		pending:  make(map[packet.LsaKey]packet.OspfLsa),
+	}
+}
+
+func (lsdb *Lsdb) String() string {
+	switch {
+	case lsdb.iface != nil:
+		return "link " + lsdb.iface.name
+	case lsdb.area != nil:
+		return "area " + packet.Ipv4String(lsdb.area.areaId)
+	}
+	return "as"
+}
+
+// keys returns the keys of the LSAs in their order.
+func (lsdb *Lsdb) keys() []packet.LsaKey {
+	return sortedLsaKeys(lsdb.lsas)
+}
+
+func sortedLsaKeys(lsas map[packet.LsaKey]packet.OspfLsa) []packet.LsaKey {
+	keys := make([]packet.LsaKey, 0, len(lsas))
+	for key := range lsas {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return lsaKeyLess(keys[i], keys[j])
+	})
+	return keys
+}
+
+func lsaKeyLess(a, b packet.LsaKey) bool {
+	if a.LsType != b.LsType {
+		return a.LsType < b.LsType
+	}
+	if a.LinkStateId != b.LinkStateId {
+		return a.LinkStateId < b.LinkStateId
+	}
+	return a.AdvertisingRouter < b.AdvertisingRouter
+}
+
+func lsaBody(lsa packet.OspfLsa) []byte {
+	data, err := lsa.Serialize()
+	if err != nil {
+		return nil
+	}
+	return data[packet.LSA_HEADER_LENGTH:]
+}
+
+// copyLsa returns a copy of lsa which can be changed without changing
+// the instance in the LSDB.
+func copyLsa(lsa packet.OspfLsa) packet.OspfLsa {
+	data, err := lsa.Serialize()
+	if err != nil {
+		return nil
+	}
+	var copied packet.OspfLsa
+	if lsa.Header().Version() == packet.OSPFV3_VERSION {
+		copied, err = packet.DecodeOspfv3LsaFromBytes(data)
+	} else {
+		copied, err = packet.DecodeLsaFromBytes(data)
+	}
+	if err != nil {
+		return nil
+	}
+	return copied
+}
+
+// ospfv3LsTypeKnown tells whether the router understands the OSPFv3 LSAs
+// of lsType.
+func ospfv3LsTypeKnown(lsType packet.LsType) bool {
+	switch lsType {
+	case packet.LS_TYPE_OSPFV3_ROUTER, packet.LS_TYPE_OSPFV3_NETWORK,
+		packet.LS_TYPE_INTER_AREA_PREFIX, packet.LS_TYPE_INTER_AREA_ROUTER,
+		packet.LS_TYPE_OSPFV3_AS_EXTERNAL, packet.LS_TYPE_LINK,
+		packet.LS_TYPE_INTRA_AREA_PREFIX:
+		return true
+	}
+	return false
+}
+
+// lsTypeValid tells whether the LSAs of lsType are accepted in the area.
+// OSPFv3 LSAs of unknown types are kept and flooded unless their scope is
+// reserved. No LSAs of AS flooding scope are accepted in a stub area or
+// an NSSA, and the NSSA-LSAs only in an NSSA.
+func (area *Area) lsTypeValid(lsType packet.LsType) bool {
+	ospf := area.ospf
+	if ospf.floodingScope(lsType) == packet.FLOODING_SCOPE_AS && !area.externalCapable() {
+		return false
+	}
+	if ospf.version == packet.OSPFV3_VERSION {
+		return lsType.Scope() != packet.FLOODING_SCOPE_RESERVED
+	}
+	if lsType == packet.LS_TYPE_NSSA_EXTERNAL {
+		return area.areaType() == AREA_TYPE_NSSA
+	}
+	return lsType >= packet.LS_TYPE_ROUTER && lsType <= packet.LS_TYPE_AS_EXTERNAL
+}
+
+// lsTypeValid tells whether the LSAs of lsType are accepted on the
+// interface: those of its area but of AS flooding scope on a virtual link
+// as in RFC 2328 13.3.
+func (iface *Interface) lsTypeValid(lsType packet.LsType) bool {
+	if iface.virtualLink != nil && iface.ospf.floodingScope(lsType) == packet.FLOODING_SCOPE_AS {
+		return false
+	}
+	return iface.area.lsTypeValid(lsType)
+}
+
+// externalCapable tells whether the LSAs of AS flooding scope are flooded
+// through the interface, which is neither in a stub area or an NSSA nor
+// a virtual link.
+func (iface *Interface) externalCapable() bool {
+	return iface.area.externalCapable() && iface.virtualLink == nil
+}
+
+// floodingScope is the flooding scope of the LSAs of lsType. The OSPFv3
+// LSAs of an unknown type without the U-bit have link-local scope as in
+// RFC 5340 4.5.2.
+func (ospf *OspfServer) floodingScope(lsType packet.LsType) packet.FloodingScope {
+	if ospf.version == packet.OSPFV3_VERSION {
+		if !ospfv3LsTypeKnown(lsType) && !lsType.UBit() {
+			return packet.FLOODING_SCOPE_LINK
+		}
+		return lsType.Scope()
+	}
+	if lsType == packet.LS_TYPE_AS_EXTERNAL {
+		return packet.FLOODING_SCOPE_AS
+	}
+	return packet.FLOODING_SCOPE_AREA
+}
+
+func (area *Area) clearLsdb() {
+	area.lsdb = NewLsdb(area.ospf, area, nil)
+	area.routerRiDb = make(map[uint32]*RouterRi)
+}
+
+// lsdbOf returns the LSDB the LSAs of lsType of area are kept in.
+func (area *Area) lsdbOf(lsType packet.LsType) *Lsdb {
+	if area.ospf.floodingScope(lsType) == packet.FLOODING_SCOPE_AS {
+		return area.ospf.externalLsdb
+	}
+	return area.lsdb
+}
+
+// lsdbOf returns the LSDB the LSAs of lsType received on the interface
+// are kept in.
+func (iface *Interface) lsdbOf(lsType packet.LsType) *Lsdb {
+	if iface.ospf.floodingScope(lsType) == packet.FLOODING_SCOPE_LINK {
+		return iface.lsdb
+	}
+	return iface.area.lsdbOf(lsType)
+}
+
+func (lsdb *Lsdb) lookup(key packet.LsaKey) packet.OspfLsa {
+	return lsdb.lsas[key]
+}
+
+func (area *Area) lookupLsa(key packet.LsaKey) packet.OspfLsa {
+	return area.lsdbOf(key.LsType).lookup(key)
+}
+
+// install installs lsa as in RFC 2328 13.2 and has the routing table
+// recalculated if its contents changed.
+func (lsdb *Lsdb) install(lsa packet.OspfLsa) {
+	key := lsa.Header().Key()
+	log.WithFields(log.Fields{
+		"Topic": "Lsdb",
+		"Scope": lsdb.String(),
+		"Key":   key.String(),
+	}).Debug("install")
+	current := lsdb.lsas[key]
+	changed := current == nil ||
+		(current.Header().LsAge >= packet.MAX_AGE) != (lsa.Header().LsAge >= packet.MAX_AGE) ||
+		current.Header().Options != lsa.Header().Options ||
+		!bytes.Equal(lsaBody(current), lsaBody(lsa))
+	lsdb.lsas[key] = lsa
+	lsdb.arrivals[key] = time.Now()
+	if !changed {
+		return
+	}
+	lsdb.ospf.spfRequired = true
+	if lsdb.iface != nil && key.LsType == packet.LS_TYPE_LINK {
+		// the prefixes of the link-LSAs go into the
+		// intra-area-prefix-LSA of the network
+		lsdb.iface.originateNetworkLsa()
+	}
+}
+
+// recentlyArrived tells whether the current instance of the LSA of key
+// was installed less than MinLSArrival ago.
+func (lsdb *Lsdb) recentlyArrived(key packet.LsaKey) bool {
+	arrival, ok := lsdb.arrivals[key]
+	return ok && time.Since(arrival) < MIN_LS_ARRIVAL*time.Second
+}
+
+// lsaHeaders returns the headers of the LSAs the neighbors on the
+// interface are told about in the database exchange, in the order of
+// their keys.
+func (iface *Interface) lsaHeaders() []*packet.LsaHeader {
+	headers := make([]*packet.LsaHeader, 0)
+	lsdbs := []*Lsdb{iface.lsdb, iface.area.lsdb}
+	if iface.externalCapable() {
+		lsdbs = append(lsdbs, iface.ospf.externalLsdb)
+	}
+	for _, lsdb := range lsdbs {
+		for _, key := range lsdb.keys() {
+			header := *lsdb.lsas[key].Header()
+			headers = append(headers, &header)
+		}
+	}
+	return headers
+}
+
+// interfaces returns the interfaces the LSAs of the LSDB are flooded
+// through. The LSAs of the AS are not flooded into stub areas and NSSAs.
+func (lsdb *Lsdb) interfaces() []*Interface {
+	switch {
+	case lsdb.iface != nil:
+		return []*Interface{lsdb.iface}
+	case lsdb.area != nil:
+		return lsdb.area.interfaces()
+	}
+	interfaces := make([]*Interface, 0)
+	for _, iface := range lsdb.ospf.interfaceDb {
+		if iface.externalCapable() {
+			interfaces = append(interfaces, iface)
+		}
+	}
+	return interfaces
+}
+
+// exchanging tells whether a neighbor the LSAs of the LSDB are flooded to
+// is exchanging its database with the router.
+func (lsdb *Lsdb) exchanging() bool {
+	for _, iface := range lsdb.interfaces() {
+		for _, nbr := range iface.neighborDb {
+			if nbr.state == NEIGHBOR_STATE_EXCHANGE || nbr.state == NEIGHBOR_STATE_LOADING {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (lsdb *Lsdb) removeRetransmissions(key packet.LsaKey) {
+	for _, iface := range lsdb.interfaces() {
+		for _, nbr := range iface.neighborDb {
+			delete(nbr.lsRetransmissionList, key)
+		}
+	}
+}
+
+func (lsdb *Lsdb) retransmitting(key packet.LsaKey) bool {
+	for _, iface := range lsdb.interfaces() {
+		for _, nbr := range iface.neighborDb {
+			if _, ok := nbr.lsRetransmissionList[key]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// flood floods lsa, received from the neighbor from or originated by the
+// router if from is nil, as in RFC 2328 13.3. It tells whether lsa was
+// flooded back out the interface it was received on.
+func (lsdb *Lsdb) flood(lsa packet.OspfLsa, from *Neighbor) bool {
+	key := lsa.Header().Key()
+	floodedBack := false
+	loading := make([]*Neighbor, 0)
+	for _, iface := range lsdb.interfaces() {
+		if iface.transport == nil {
+			continue
+		}
+		added := false
+		for _, nbr := range iface.neighborDb {
+			if nbr.state < NEIGHBOR_STATE_EXCHANGE {
+				continue
+			}
+			if nbr.state != NEIGHBOR_STATE_FULL {
+				if request := nbr.findRequest(key); request != nil {
+					cmp := lsa.Header().Compare(request)
+					if cmp < 0 {
+						continue
+					}
+					nbr.removeRequest(key)
+					loading = append(loading, nbr)
+					if cmp == 0 {
+						continue
+					}
+				}
+			}
+			if nbr == from {
+				continue
+			}
+			nbr.addRetransmission(lsa)
+			added = true
+		}
+		if !added {
+			continue
+		}
+		if from != nil && from.iface == iface {
+			if from.id() == iface.dr || from.id() == iface.bdr ||
+				iface.state == INTERFACE_STATE_BACKUP {
+				continue
+			}
+			floodedBack = true
+		}
+		for _, dst := range iface.floodDsts() {
+			iface.sendLsas([]packet.OspfLsa{lsa}, dst)
+		}
+	}
+	for _, nbr := range loading {
+		nbr.checkLoadingDone()
+	}
+	return floodedBack
+}
+
+// age ages the LSAs of the LSDB by a second. Self-originated LSAs are
+// refreshed at LSRefreshTime and LSAs reaching MaxAge are flushed and
+// removed once no neighbor needs them as in RFC 2328 14.
+func (lsdb *Lsdb) age() {
+	ospf := lsdb.ospf
+	for _, key := range lsdb.keys() {
+		lsa := lsdb.lsas[key]
+		header := lsa.Header()
+		if header.LsAge < packet.MAX_AGE {
+			header.LsAge++
+			if header.LsAge >= packet.MAX_AGE {
+				ospf.spfRequired = true
+				lsdb.flood(lsa, nil)
+			} else if ospf.selfOriginated(lsa) && header.LsAge >= LS_REFRESH_TIME {
+				lsdb.refresh(lsa)
+			}
+			continue
+		}
+		if lsdb.retransmitting(key) || lsdb.exchanging() {
+			continue
+		}
+		log.WithFields(log.Fields{
+			"Topic": "Lsdb",
+			"Scope": lsdb.String(),
+			"Key":   key.String(),
+		}).Debug("remove")
+		delete(lsdb.lsas, key)
+		delete(lsdb.arrivals, key)
+		if pending, ok := lsdb.pending[key]; ok {
+			delete(lsdb.pending, key)
+			lsdb.newInstance(pending)
+		}
+	}
+}
+
+func (ospf *OspfServer) ageLsdb() {
+	for _, iface := range ospf.interfaceDb {
+		iface.lsdb.age()
+	}
+	for _, area := range ospf.areaDb {
+		area.lsdb.age()
+	}
+	ospf.externalLsdb.age()
+}
+
+// originate installs a new instance of the self-originated lsa unless
+// the current one has the same contents.
+func (lsdb *Lsdb) originate(lsa packet.OspfLsa) {
+	lsa.Header().AdvertisingRouter = lsdb.ospf.routerId
+	if current := lsdb.lookup(lsa.Header().Key()); current != nil {
+		if current.Header().LsAge < packet.MAX_AGE &&
+			current.Header().Options == lsa.Header().Options &&
+			bytes.Equal(lsaBody(current), lsaBody(lsa)) {
+			return
+		}
+	}
+	lsdb.newInstance(lsa)
+}
+
+func (area *Area) originateLsa(lsa packet.OspfLsa) {
+	area.lsdbOf(lsa.LsType()).originate(lsa)
+}
+
+// refresh originates a new instance of the self-originated lsa with the
+// same contents.
+func (lsdb *Lsdb) refresh(lsa packet.OspfLsa) {
+	if copied := copyLsa(lsa); copied != nil {
+		lsdb.newInstance(copied)
+	}
+}
+
+// newInstance installs and floods lsa as the instance following the
+// current one.
+func (lsdb *Lsdb) newInstance(lsa packet.OspfLsa) {
+	header := lsa.Header()
+	header.AdvertisingRouter = lsdb.ospf.routerId
+	key := header.Key()
+	header.LsAge = 0
+	header.LsSequenceNumber = packet.INITIAL_SEQUENCE_NUMBER
+	if current := lsdb.lookup(key); current != nil {
+		if current.Header().LsSequenceNumber == packet.MAX_SEQUENCE_NUMBER {
+			// RFC 2328 12.1.6
+			lsdb.pending[key] = lsa
+			lsdb.flush(key)
+			return
+		}
+		header.LsSequenceNumber = current.Header().LsSequenceNumber + 1
+	}
+	err := packet.SetLsaChecksum(lsa)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"Topic": "Lsdb",
+			"Key":   key.String(),
+			"Error": err,
+		}).Warn("originate failed")
+		return
+	}
+	lsdb.removeRetransmissions(key)
+	lsdb.install(lsa)
+	lsdb.flood(lsa, nil)
+}
+
+// flush prematurely ages the self-originated LSA of key out of the
+// flooding scope.
+func (lsdb *Lsdb) flush(key packet.LsaKey) {
+	lsa := lsdb.lookup(key)
+	if lsa == nil || lsa.Header().LsAge >= packet.MAX_AGE {
+		return
+	}
+	lsa.Header().LsAge = packet.MAX_AGE
+	lsdb.removeRetransmissions(key)
+	lsdb.install(lsa)
+	lsdb.flood(lsa, nil)
+}
+
+func (area *Area) flushLsa(key packet.LsaKey) {
+	area.lsdbOf(key.LsType).flush(key)
+}
+
+func (ospf *OspfServer) selfOriginated(lsa packet.OspfLsa) bool {
+	return lsa.Header().AdvertisingRouter == ospf.routerId
+}
+
+// receiveSelfOriginated handles a self-originated LSA newer than the
+// current instance, received after a restart for example, as in RFC 2328
+// 13.4: it is superseded by a newer instance or flushed if the router no
+// longer originates it.
+func (lsdb *Lsdb) receiveSelfOriginated(lsa packet.OspfLsa) {
+	log.WithFields(log.Fields{
+		"Topic": "Lsdb",
+		"Key":   lsa.Header().Key().String(),
+	}).Info("Self-originated LSA received")
+	key := lsa.Header().Key()
+	switch key.LsType {
+	case packet.LS_TYPE_SUMMARY_NETWORK, packet.LS_TYPE_SUMMARY_ASBR,
+		packet.LS_TYPE_INTER_AREA_PREFIX, packet.LS_TYPE_INTER_AREA_ROUTER,
+		packet.LS_TYPE_AS_EXTERNAL, packet.LS_TYPE_NSSA_EXTERNAL:
+		// the summaries, the translated AS-external-LSAs and the
+		// NSSA defaults are originated again or flushed after the
+		// routing table calculation
+		lsdb.ospf.spfRequired = true
+		return
+	}
+	if own := lsdb.ownLsa(key); own != nil {
+		lsdb.newInstance(own)
+		return
+	}
+	lsdb.flush(key)
+}
+
+// ownLsa returns the LSA of key the router originates into the LSDB now,
+// or nil if it originates none.
+func (lsdb *Lsdb) ownLsa(key packet.LsaKey) packet.OspfLsa {
+	area := lsdb.area
+	if lsdb.iface != nil {
+		area = lsdb.iface.area
+	}
+	if area == nil {
+		return nil
+	}
+	switch key.LsType {
+	case packet.LS_TYPE_ROUTER:
+		if key.LinkStateId == area.ospf.routerId {
+			return area.newRouterLsa()
+		}
+	case packet.LS_TYPE_OSPFV3_ROUTER:
+		if key.LinkStateId == 0 {
+			return area.newRouterLsa()
+		}
+	case packet.LS_TYPE_NETWORK, packet.LS_TYPE_OSPFV3_NETWORK:
+		for _, iface := range area.interfaces() {
+			if iface.networkLsaId() == key.LinkStateId {
+				return iface.newNetworkLsa()
+			}
+		}
+	case packet.LS_TYPE_LINK:
+		if lsdb.iface != nil && lsdb.iface.interfaceId == key.LinkStateId {
+			return lsdb.iface.newLinkLsa()
+		}
+	case packet.LS_TYPE_INTRA_AREA_PREFIX:
+		if key.LinkStateId == 0 {
+			return area.newRouterPrefixLsa()
+		}
+		for _, iface := range area.interfaces() {
+			if iface.interfaceId == key.LinkStateId {
+				return iface.newNetworkPrefixLsa()
+			}
+		}
+	}
+	return nil
+}
+
+func (area *Area) newRouterLsa() packet.OspfLsa {
+	var lsa packet.OspfLsa
+	if area.ospf.version == packet.OSPFV3_VERSION {
+		lsa = area.newOspfv3RouterLsa()
+	} else {
+		lsa = area.newOspfv2RouterLsa()
+	}
+	if area.ospf.maxMetric() {
+		maxLinkMetric(lsa)
+	}
+	return lsa
+}
+
+func (area *Area) newOspfv2RouterLsa() packet.OspfLsa {
+	ospf := area.ospf
+	lsa, _ := packet.NewRouterLsa()
+	lsa.Header().LinkStateId = ospf.routerId
+	lsa.Header().Options = area.options()
+	lsa.Border = ospf.areaBorder()
+	lsa.External = ospf.asBoundary() && area.areaType() != AREA_TYPE_STUB
+	lsa.VirtualLink = area.virtualLinksFull()
+	for _, iface := range area.interfaces() {
+		for _, link := range iface.routerLinks() {
+			lsa.AddLink(link)
+		}
+	}
+	return lsa
+}
+
+// originateRouterLsa originates the router-LSA of the area and, for
+// OSPFv3, the intra-area-prefix-LSA of the router which goes with it.
+func (area *Area) originateRouterLsa() {
+	log.Debugf("enter: %s", packet.Ipv4String(area.areaId))
+	defer log.Debugf("exit: %s", packet.Ipv4String(area.areaId))
+	if area.ospf.routerId == 0 {
+		return
+	}
+	area.originateLsa(area.newRouterLsa())
+	if area.ospf.version == packet.OSPFV3_VERSION {
+		area.originateRouterPrefixLsa()
+	}
+}
+
+// networkLsaId is the link state ID of the network-LSA the router
+// originates as the designated router of the interface.
+func (iface *Interface) networkLsaId() uint32 {
+	if iface.ospf.version == packet.OSPFV3_VERSION {
+		return iface.interfaceId
+	}
+	return iface.address
+}
+
+// attachedRouters returns the routers attached to the network of the
+// interface, or nil if the router is not the designated router of a
+// network with adjacencies.
+func (iface *Interface) attachedRouters() []uint32 {
+	attachedRouters := []uint32{iface.ospf.routerId}
+	for _, nbr := range iface.neighborDb {
+		if nbr.state == NEIGHBOR_STATE_FULL {
+			attachedRouters = append(attachedRouters, nbr.routerId)
+		}
+	}
+	if iface.state != INTERFACE_STATE_DR || len(attachedRouters) < 2 {
+		return nil
+	}
+	return attachedRouters
+}
+
+// newNetworkLsa returns the network-LSA of the interface, or nil if the
+// router is not the designated router of a network with adjacencies.
+func (iface *Interface) newNetworkLsa() packet.OspfLsa {
+	attachedRouters := iface.attachedRouters()
+	if attachedRouters == nil {
+		return nil
+	}
+	if iface.ospf.version == packet.OSPFV3_VERSION {
+		lsa, _ := packet.NewOspfv3NetworkLsa()
+		lsa.Header().LinkStateId = iface.interfaceId
+		lsa.Options = iface.area.options()
+		lsa.AttachedRouters = attachedRouters
+		return lsa
+	}
+	lsa, _ := packet.NewNetworkLsa()
+	lsa.Header().LinkStateId = iface.address
+	lsa.Header().Options = iface.area.options()
+	lsa.NetworkMask = iface.networkMask()
+	lsa.AttachedRouters = attachedRouters
+	return lsa
+}
+
+// originateNetworkLsa originates or flushes the network-LSA of the
+// interface and, for OSPFv3, the intra-area-prefix-LSA of the network.
+func (iface *Interface) originateNetworkLsa() {
+	log.Debugf("enter: %s", iface.name)
+	defer log.Debugf("exit: %s", iface.name)
+	if iface.ospf.routerId == 0 {
+		return
+	}
+	lsType := packet.LsType(packet.LS_TYPE_NETWORK)
+	if iface.ospf.version == packet.OSPFV3_VERSION {
+		lsType = packet.LS_TYPE_OSPFV3_NETWORK
+		defer iface.originateNetworkPrefixLsa()
+	}
+	lsa := iface.newNetworkLsa()
+	if lsa == nil {
+		iface.area.flushLsa(packet.LsaKey{
+			LsType:            lsType,
+			LinkStateId:       iface.networkLsaId(),
+			AdvertisingRouter: iface.ospf.routerId,
+		})
+		return
+	}
+	iface.area.originateLsa(lsa)
+}
@@ -0,0 +1,115 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	log "github.com/sirupsen/logrus"
+
+	"github.com/m-asama/golsr/internal/pkg/ospf/config"
+	"github.com/m-asama/golsr/pkg/ospf/packet"
+)
+
+// staticNeighborAddress returns the address of a static neighbor: an
+// IPv4 address for OSPFv2 and a link-local address for OSPFv3.
+func (iface *Interface) staticNeighborAddress(nbrConfig *config.StaticNeighbor) (Address, error) {
+	if iface.ospf.version == packet.OSPFV3_VERSION {
+		address, err := config.ParseIpv6Address(*nbrConfig.Config.Identifier)
+		return ipv6Address(address), err
+	}
+	address, err := config.ParseIpv4Address(*nbrConfig.Config.Identifier)
+	return ipv4Address(address), err
+}
+
+// addStaticNeighbors adds the static neighbors of an interface without
+// multicast, which are down until they are heard from or started, and
+// polled meanwhile as in RFC 2328 9.5.1.
+func (iface *Interface) addStaticNeighbors() {
+	for _, nbrConfig := range iface.ifConfig.StaticNeighbors {
+		address, err := iface.staticNeighborAddress(nbrConfig)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"Topic":     "Interface",
+				"Interface": iface.name,
+				"Error":     err,
+			}).Warn("Invalid static neighbor")
+			continue
+		}
+		nbr := NewNeighbor(iface, 0, address)
+		nbr.static = nbrConfig
+		nbr.priority = *nbrConfig.Config.Priority
+		iface.neighborDb = append(iface.neighborDb, nbr)
+	}
+}
+
+// startStaticNeighbors runs the Start event on the static neighbors down
+// the hellos are sent to.
+func (iface *Interface) startStaticNeighbors() {
+	if iface.multicast() {
+		return
+	}
+	for _, nbr := range iface.neighborDb {
+		if nbr.static != nil && nbr.state == NEIGHBOR_STATE_DOWN && iface.helloTo(nbr) {
+			nbr.event(NEIGHBOR_EVENT_START)
+		}
+	}
+}
+
+// pollStaticNeighbors sends hellos to the static neighbors down every
+// PollInterval.
+func (iface *Interface) pollStaticNeighbors() {
+	if iface.multicast() {
+		return
+	}
+	for _, nbr := range iface.neighborDb {
+		if nbr.static == nil || nbr.state != NEIGHBOR_STATE_DOWN || !iface.helloTo(nbr) {
+			continue
+		}
+		nbr.pollTimer--
+		if nbr.pollTimer <= 0 {
+			nbr.pollTimer = int(*nbr.static.Config.PollInterval)
+			iface.send(iface.newHello(), nbr.src())
+		}
+	}
+}
+
+// helloTo tells whether hellos are sent to the neighbor as in RFC 2328
+// 9.5.1. On an NBMA network a router eligible to become the designated
+// router sends them to the eligible neighbors, the designated router and
+// the backup to all of them and the others only to those two.
+func (iface *Interface) helloTo(nbr *Neighbor) bool {
+	if iface.networkType() != NETWORK_TYPE_NBMA {
+		return true
+	}
+	switch {
+	case iface.state == INTERFACE_STATE_DR, iface.state == INTERFACE_STATE_BACKUP:
+		return true
+	case iface.priority() > 0:
+		return nbr.priority > 0
+	}
+	return nbr.id() == iface.dr || nbr.id() == iface.bdr
+}
+
+// replyHello replies to a hello from an eligible neighbor on an NBMA
+// network the router is not eligible on, which lets the neighbor see it
+// as in RFC 2328 9.5.1.
+func (iface *Interface) replyHello(nbr *Neighbor) {
+	if iface.networkType() != NETWORK_TYPE_NBMA || iface.helloTo(nbr) || nbr.priority == 0 {
+		return
+	}
+	iface.send(iface.newHello(), nbr.src())
+}
@@ -0,0 +1,160 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/m-asama/golsr/internal/pkg/kernel"
+	"github.com/m-asama/golsr/pkg/ospf/packet"
+)
+
+// staticNeighborConfig returns the configuration of the static neighbors
+// of the addresses, of the cost if not 0.
+func staticNeighborConfig(cost uint16, addresses ...uint32) string {
+	s := ""
+	for _, address := range addresses {
+		s += "    [[areas.interfaces.static-neighbors]]\n" +
+			"      [areas.interfaces.static-neighbors.config]\n" +
+			fmt.Sprintf("        identifier = %q\n", packet.Ipv4String(address)) +
+			"        poll-interval = 2\n"
+		if cost != 0 {
+			s += fmt.Sprintf("        cost = %d\n", cost)
+		}
+	}
+	return s
+}
+
+func TestNbma(t *testing.T) {
+	// r3 is not eligible to become the designated router and hears of
+	// r1 and r2 only once they send it hellos
+	network := NewMemoryNetwork()
+	network.SetNonBroadcast("n")
+	routers := make([]*testRouter, 0)
+	for i := 1; i <= 3; i++ {
+		priority := uint8(1)
+		if i == 3 {
+			priority = 0
+		}
+		others := make([]uint32, 0)
+		for j := 1; j <= 3; j++ {
+			if j != i {
+				others = append(others, 0x0a000000+uint32(j))
+			}
+		}
+		router := startTestRouter(t, network, fmt.Sprintf("r%d", i), uint32(0x01010101*i), []*testPort{
+			&testPort{name: "lo", address: uint32(0x01010101 * i), ifType: kernel.IF_TYPE_LOOPBACK},
+			&testPort{segment: "n", name: "eth0", address: 0x0a000000 + uint32(i), ifType: kernel.IF_TYPE_BROADCAST,
+				priority: priority, interfaceType: "non-broadcast", ifConfig: staticNeighborConfig(0, others...)},
+		})
+		defer router.stop()
+		routers = append(routers, router)
+	}
+
+	// r2 is elected the designated router and r1 the backup, both
+	// adjacent with all the others
+	waitFor(t, "full adjacencies", func() bool {
+		for i, router := range routers {
+			states, dr, bdr := router.neighborStates("eth0")
+			if dr != 0x0a000002 || bdr != 0x0a000001 {
+				return false
+			}
+			for j := range routers {
+				if j != i && (i < 2 || j < 2) && states[uint32(0x01010101*(j+1))] != NEIGHBOR_STATE_FULL {
+					return false
+				}
+			}
+		}
+		return true
+	})
+	waitFor(t, "routes over the NBMA network", func() bool {
+		route := routers[2].fibRoute(0x01010101, 32)
+		return route != nil && len(route.NextHops) == 1 && route.NextHops[0].Address == 0x0a000001
+	})
+
+	// the neighbor stays down, polled, once it stops answering
+	network.Disconnect("r3", "eth0")
+	waitFor(t, "static neighbor down", func() bool {
+		r1 := routers[0].ospf
+		r1.lock.RLock()
+		defer r1.lock.RUnlock()
+		nbr := r1.findInterface("eth0").findNeighbor(0x03030303, ipv4Address(0x0a000003))
+		return nbr != nil && nbr.state == NEIGHBOR_STATE_DOWN
+	})
+	network.Connect("n", "r3", "eth0")
+	waitFor(t, "static neighbor back", func() bool {
+		states, _, _ := routers[0].neighborStates("eth0")
+		return states[0x03030303] == NEIGHBOR_STATE_FULL
+	})
+}
+
+func TestPointToMultipoint(t *testing.T) {
+	// r1 is the hub of r2 and r3, which reach each other through it
+	network := NewMemoryNetwork()
+	network.SetNonBroadcast("n")
+	r1 := startTestRouter(t, network, "r1", 0x01010101, []*testPort{
+		&testPort{segment: "n", name: "eth0", address: 0x0a000001, ifType: kernel.IF_TYPE_BROADCAST,
+			interfaceType: "point-to-multipoint",
+			ifConfig:      staticNeighborConfig(5, 0x0a000002) + staticNeighborConfig(50, 0x0a000003)},
+	})
+	defer r1.stop()
+	spokes := make([]*testRouter, 0)
+	for i := 2; i <= 3; i++ {
+		spoke := startTestRouter(t, network, fmt.Sprintf("r%d", i), uint32(0x01010101*i), []*testPort{
+			&testPort{name: "lo", address: uint32(0x01010101 * i), ifType: kernel.IF_TYPE_LOOPBACK},
+			&testPort{segment: "n", name: "eth0", address: 0x0a000000 + uint32(i), ifType: kernel.IF_TYPE_BROADCAST,
+				interfaceType: "point-to-multipoint", ifConfig: staticNeighborConfig(0, 0x0a000001)},
+		})
+		defer spoke.stop()
+		spokes = append(spokes, spoke)
+	}
+
+	waitFor(t, "routes through the hub", func() bool {
+		for _, check := range []struct {
+			router *testRouter
+			prefix uint32
+		}{
+			{spokes[0], 0x03030303},
+			{spokes[0], 0x0a000003},
+			{spokes[1], 0x02020202},
+		} {
+			route := check.router.fibRoute(check.prefix, 32)
+			if route == nil || len(route.NextHops) != 1 || route.NextHops[0].Address != 0x0a000001 {
+				return false
+			}
+		}
+		return true
+	})
+
+	// the links to the neighbors are of their costs and the address of
+	// the interface is a host route
+	r1.ospf.lock.RLock()
+	links := r1.ospf.findArea(0).newRouterLsa().(*packet.RouterLsa).Links
+	r1.ospf.lock.RUnlock()
+	metrics := make(map[uint32]uint16)
+	for _, link := range links {
+		metrics[link.LinkId] = link.Metric
+		if link.Type == packet.LINK_TYPE_STUB && link.LinkData != 0xffffffff {
+			t.Fatalf("failed stub link: %v", link)
+		}
+	}
+	if len(links) != 3 || metrics[0x02020202] != 5 || metrics[0x03030303] != 50 || metrics[0x0a000001] != 0 {
+		t.Fatalf("failed router links: %v", metrics)
+	}
+}
@@ -0,0 +1,638 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/m-asama/golsr/internal/pkg/ospf/config"
+	"github.com/m-asama/golsr/pkg/ospf/packet"
+)
+
+type Neighbor struct {
+	iface    *Interface
+	routerId uint32
+	address  uint32
+	priority uint8
+	dr       uint32
+	bdr      uint32
+	options  packet.Options
+	state    NeighborState
+
+	// address6 and interfaceId are the link-local address and the
+	// interface ID of an OSPFv3 neighbor.
+	address6    [4]uint32
+	interfaceId uint32
+
+	master       bool
+	ddSeqNum     uint32
+	lastRecvDd   *packet.DatabaseDescriptionPacket
+	lastSentDd   *packet.DatabaseDescriptionPacket
+	lastSentMore bool
+
+	dbSummaryList []*packet.LsaHeader
+	lsRequestList []*packet.LsaHeader
+	lsrPending    map[packet.LsaKey]bool
+
+	lsRetransmissionList map[packet.LsaKey]packet.OspfLsa
+
+	inactivityTimer int
+	rxmtTimer       int
+	lsuRxmtTimer    int
+
+	// cryptoSeqNum is the cryptographic sequence number of the last
+	// packet received from the neighbor.
+	cryptoSeqNum uint64
+
+	// static is the configuration of a static neighbor, which stays
+	// down instead of being removed and is polled every pollTimer.
+	static    *config.StaticNeighbor
+	pollTimer int
+}
+
+func NewNeighbor(iface *Interface, routerId uint32, address Address) *Neighbor {
+	log.Debugf("enter: %s", packet.Ipv4String(routerId))
+	defer log.Debugf("exit: %s", packet.Ipv4String(routerId))
+	nbr := &Neighbor{
+		iface:         iface,
+		routerId:      routerId,
+		address:       address.Ipv4,
+		address6:      address.Ipv6,
+		state:         NEIGHBOR_STATE_DOWN,
+		dbSummaryList: make([]*packet.LsaHeader, 0),
+		lsRequestList: make([]*packet.LsaHeader, 0),
+		lsrPending:    make(map[packet.LsaKey]bool),
+
+		lsRetransmissionList: make(map[packet.LsaKey]packet.OspfLsa),
+	}
+	return nbr
+}
+
+func (nbr *Neighbor) String() string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "router-id                       %s\n", packet.Ipv4String(nbr.routerId))
+	fmt.Fprintf(&b, "address                         %s\n", nbr.src().String())
+	fmt.Fprintf(&b, "priority                        %d\n", nbr.priority)
+	fmt.Fprintf(&b, "state                           %s\n", nbr.state.String())
+	fmt.Fprintf(&b, "dr                              %s\n", packet.Ipv4String(nbr.dr))
+	fmt.Fprintf(&b, "bdr                             %s\n", packet.Ipv4String(nbr.bdr))
+	return b.String()
+}
+
+// id identifies the neighbor in the designated router fields of the
+// hellos: the interface address for OSPFv2 and the router ID for OSPFv3.
+func (nbr *Neighbor) id() uint32 {
+	if nbr.iface.ospf.version == packet.OSPFV3_VERSION {
+		return nbr.routerId
+	}
+	return nbr.address
+}
+
+// src is the address the packets of the neighbor are sent from.
+func (nbr *Neighbor) src() Address {
+	if nbr.iface.ospf.version == packet.OSPFV3_VERSION {
+		return ipv6Address(nbr.address6)
+	}
+	return ipv4Address(nbr.address)
+}
+
+// cost is the cost of the link to the neighbor, the one configured for
+// a static neighbor on a point-to-multipoint network or the cost of the
+// interface.
+func (nbr *Neighbor) cost() uint16 {
+	if nbr.static != nil && nbr.static.Config.Cost != nil {
+		return *nbr.static.Config.Cost
+	}
+	return nbr.iface.cost()
+}
+
+// adjOk tells whether an adjacency should be established with the
+// neighbor as in RFC 2328 10.4.
+func (nbr *Neighbor) adjOk() bool {
+	iface := nbr.iface
+	if !iface.multiAccess() {
+		return true
+	}
+	return iface.state == INTERFACE_STATE_DR || iface.state == INTERFACE_STATE_BACKUP ||
+		nbr.id() == iface.dr || nbr.id() == iface.bdr
+}
+
+// dst is the address the packets to the neighbor are sent to.
+func (nbr *Neighbor) dst() Address {
+	if nbr.iface.networkType() == NETWORK_TYPE_POINT_TO_POINT {
+		return nbr.iface.allSpfRouters()
+	}
+	return nbr.src()
+}
+
+func (nbr *Neighbor) setState(state NeighborState) {
+	if state == nbr.state {
+		return
+	}
+	iface := nbr.iface
+	log.WithFields(log.Fields{
+		"Topic":     "Neighbor",
+		"Interface": iface.name,
+		"Neighbor":  packet.Ipv4String(nbr.routerId),
+		"Old":       nbr.state.String(),
+		"New":       state.String(),
+	}).Info("Neighbor state changed")
+	oldState := nbr.state
+	nbr.state = state
+	if (oldState >= NEIGHBOR_STATE_TWO_WAY) != (state >= NEIGHBOR_STATE_TWO_WAY) {
+		iface.event(INTERFACE_EVENT_NEIGHBOR_CHANGE)
+	}
+	if (oldState == NEIGHBOR_STATE_FULL) != (state == NEIGHBOR_STATE_FULL) {
+		if state == NEIGHBOR_STATE_FULL {
+			iface.ospf.updateChSend(&UpdateChMsg{
+				msgType: UPDATE_CH_MSG_TYPE_ADJACENCY_UP,
+			})
+		} else {
+			iface.ospf.updateChSend(&UpdateChMsg{
+				msgType: UPDATE_CH_MSG_TYPE_ADJACENCY_DOWN,
+			})
+		}
+		iface.area.originateRouterLsa()
+		iface.originateNetworkLsa()
+		if transit := iface.transitArea(); transit != nil {
+			transit.originateRouterLsa()
+		}
+	}
+}
+
+func (nbr *Neighbor) clearLists() {
+	nbr.dbSummaryList = make([]*packet.LsaHeader, 0)
+	nbr.lsRequestList = make([]*packet.LsaHeader, 0)
+	nbr.lsrPending = make(map[packet.LsaKey]bool)
+	nbr.lsRetransmissionList = make(map[packet.LsaKey]packet.OspfLsa)
+	nbr.lastRecvDd = nil
+	nbr.lastSentDd = nil
+}
+
+// event runs the neighbor state machine of RFC 2328 10.3.
+func (nbr *Neighbor) event(event NeighborEvent) {
+	log.WithFields(log.Fields{
+		"Topic":     "Neighbor",
+		"Interface": nbr.iface.name,
+		"Neighbor":  packet.Ipv4String(nbr.routerId),
+		"State":     nbr.state.String(),
+		"Event":     event.String(),
+	}).Debug("event")
+	switch event {
+	case NEIGHBOR_EVENT_START:
+		if nbr.state == NEIGHBOR_STATE_DOWN {
+			nbr.setState(NEIGHBOR_STATE_ATTEMPT)
+			nbr.inactivityTimer = int(nbr.iface.deadInterval())
+			nbr.iface.send(nbr.iface.newHello(), nbr.src())
+		}
+	case NEIGHBOR_EVENT_HELLO_RECEIVED:
+		nbr.inactivityTimer = int(nbr.iface.deadInterval())
+		if nbr.state == NEIGHBOR_STATE_DOWN || nbr.state == NEIGHBOR_STATE_ATTEMPT {
+			nbr.setState(NEIGHBOR_STATE_INIT)
+		}
+	case NEIGHBOR_EVENT_TWO_WAY_RECEIVED:
+		if nbr.state == NEIGHBOR_STATE_INIT {
+			if nbr.adjOk() {
+				nbr.exStart()
+			} else {
+				nbr.setState(NEIGHBOR_STATE_TWO_WAY)
+			}
+		}
+	case NEIGHBOR_EVENT_ONE_WAY_RECEIVED:
+		if nbr.state >= NEIGHBOR_STATE_TWO_WAY {
+			nbr.clearLists()
+			nbr.setState(NEIGHBOR_STATE_INIT)
+		}
+	case NEIGHBOR_EVENT_ADJ_OK:
+		if nbr.state == NEIGHBOR_STATE_TWO_WAY && nbr.adjOk() {
+			nbr.exStart()
+		} else if nbr.state >= NEIGHBOR_STATE_EXSTART && !nbr.adjOk() {
+			nbr.clearLists()
+			nbr.setState(NEIGHBOR_STATE_TWO_WAY)
+		}
+	case NEIGHBOR_EVENT_NEGOTIATION_DONE:
+		if nbr.state == NEIGHBOR_STATE_EXSTART {
+			nbr.dbSummaryList = make([]*packet.LsaHeader, 0)
+			for _, header := range nbr.iface.lsaHeaders() {
+				if header.LsAge < packet.MAX_AGE {
+					nbr.dbSummaryList = append(nbr.dbSummaryList, header)
+				}
+			}
+			nbr.setState(NEIGHBOR_STATE_EXCHANGE)
+		}
+	case NEIGHBOR_EVENT_EXCHANGE_DONE:
+		if nbr.state == NEIGHBOR_STATE_EXCHANGE {
+			if len(nbr.lsRequestList) == 0 {
+				nbr.setState(NEIGHBOR_STATE_FULL)
+			} else {
+				nbr.setState(NEIGHBOR_STATE_LOADING)
+				nbr.sendLsr()
+			}
+		}
+	case NEIGHBOR_EVENT_LOADING_DONE:
+		if nbr.state == NEIGHBOR_STATE_LOADING {
+			nbr.setState(NEIGHBOR_STATE_FULL)
+		}
+	case NEIGHBOR_EVENT_SEQ_NUMBER_MISMATCH, NEIGHBOR_EVENT_BAD_LS_REQ:
+		if nbr.state >= NEIGHBOR_STATE_EXCHANGE {
+			nbr.clearLists()
+			nbr.exStart()
+		}
+	case NEIGHBOR_EVENT_KILL_NBR, NEIGHBOR_EVENT_INACTIVITY_TIMER, NEIGHBOR_EVENT_LL_DOWN:
+		nbr.clearLists()
+		if nbr.static == nil {
+			nbr.iface.removeNeighbor(nbr)
+		} else {
+			nbr.pollTimer = int(*nbr.static.Config.PollInterval)
+		}
+		nbr.setState(NEIGHBOR_STATE_DOWN)
+	}
+}
+
+// exStart enters ExStart, where the router claims to be the master until
+// the neighbor tells otherwise.
+func (nbr *Neighbor) exStart() {
+	if nbr.ddSeqNum == 0 {
+		nbr.ddSeqNum = uint32(time.Now().Unix())
+	}
+	nbr.ddSeqNum++
+	nbr.master = true
+	nbr.clearLists()
+	nbr.setState(NEIGHBOR_STATE_EXSTART)
+	dd, _ := packet.NewDatabaseDescriptionPacket()
+	dd.Init = true
+	dd.More = true
+	dd.MasterSlave = true
+	nbr.sendDd(dd)
+}
+
+func (nbr *Neighbor) tick() {
+	if nbr.inactivityTimer > 0 {
+		nbr.inactivityTimer--
+		if nbr.inactivityTimer == 0 {
+			nbr.event(NEIGHBOR_EVENT_INACTIVITY_TIMER)
+			return
+		}
+	}
+	if nbr.rxmtTimer > 0 {
+		nbr.rxmtTimer--
+		if nbr.rxmtTimer == 0 {
+			switch {
+			case nbr.state == NEIGHBOR_STATE_EXSTART,
+				nbr.state == NEIGHBOR_STATE_EXCHANGE && nbr.master:
+				if nbr.lastSentDd != nil {
+					nbr.sendDd(nbr.lastSentDd)
+				}
+			case nbr.state == NEIGHBOR_STATE_LOADING:
+				nbr.lsrPending = make(map[packet.LsaKey]bool)
+				nbr.sendLsr()
+			}
+		}
+	}
+	if len(nbr.lsRetransmissionList) > 0 {
+		nbr.lsuRxmtTimer--
+		if nbr.lsuRxmtTimer <= 0 {
+			nbr.retransmitLsas()
+		}
+	}
+}
+
+// ddChunkLength is the number of LSA headers which fit in a database
+// description packet on the interface.
+func (nbr *Neighbor) ddChunkLength() int {
+	fixed := 8
+	if nbr.iface.ospf.version == packet.OSPFV3_VERSION {
+		fixed = 12
+	}
+	return (nbr.iface.mtu - nbr.iface.headerLength() - fixed) / packet.LSA_HEADER_LENGTH
+}
+
+func (nbr *Neighbor) sendDd(dd *packet.DatabaseDescriptionPacket) {
+	iface := nbr.iface
+	if iface.virtualLink == nil {
+		// RFC 2328 A.3.3: 0 on a virtual link
+		dd.InterfaceMtu = uint16(iface.mtu)
+	}
+	dd.Options = iface.options()
+	dd.DdSequenceNumber = nbr.ddSeqNum
+	nbr.lastSentDd = dd
+	nbr.lastSentMore = dd.More
+	nbr.rxmtTimer = int(iface.retransmitInterval())
+	iface.send(dd, nbr.dst())
+}
+
+// sendNextDd sends the next part of the database summary list.
+func (nbr *Neighbor) sendNextDd() {
+	dd, _ := packet.NewDatabaseDescriptionPacket()
+	dd.MasterSlave = nbr.master
+	n := nbr.ddChunkLength()
+	if n > len(nbr.dbSummaryList) {
+		n = len(nbr.dbSummaryList)
+	}
+	dd.LsaHeaders = append(dd.LsaHeaders, nbr.dbSummaryList[:n]...)
+	nbr.dbSummaryList = nbr.dbSummaryList[n:]
+	dd.More = len(nbr.dbSummaryList) > 0
+	nbr.sendDd(dd)
+}
+
+func sameDd(a, b *packet.DatabaseDescriptionPacket) bool {
+	return a.Init == b.Init && a.More == b.More && a.MasterSlave == b.MasterSlave &&
+		a.Options == b.Options && a.DdSequenceNumber == b.DdSequenceNumber
+}
+
+// receiveDd processes a database description packet as in RFC 2328 10.6.
+func (nbr *Neighbor) receiveDd(dd *packet.DatabaseDescriptionPacket) {
+	iface := nbr.iface
+	if !iface.mtuIgnore() && int(dd.InterfaceMtu) > iface.mtu {
+		log.WithFields(log.Fields{
+			"Topic":     "Neighbor",
+			"Interface": iface.name,
+			"Neighbor":  packet.Ipv4String(nbr.routerId),
+			"Mtu":       dd.InterfaceMtu,
+		}).Warn("MTU mismatch")
+		return
+	}
+	duplicate := nbr.lastRecvDd != nil && sameDd(dd, nbr.lastRecvDd)
+	if nbr.state == NEIGHBOR_STATE_INIT {
+		nbr.event(NEIGHBOR_EVENT_TWO_WAY_RECEIVED)
+	}
+	switch nbr.state {
+	case NEIGHBOR_STATE_EXSTART:
+		if dd.Init && dd.More && dd.MasterSlave && len(dd.LsaHeaders) == 0 &&
+			dd.RouterId() > iface.ospf.routerId {
+			nbr.master = false
+			nbr.ddSeqNum = dd.DdSequenceNumber
+			nbr.options = dd.Options
+			nbr.lastRecvDd = dd
+			nbr.event(NEIGHBOR_EVENT_NEGOTIATION_DONE)
+			nbr.sendNextDd()
+			return
+		}
+		if dd.Init || dd.MasterSlave || dd.DdSequenceNumber != nbr.ddSeqNum ||
+			dd.RouterId() > iface.ospf.routerId {
+			return
+		}
+		nbr.master = true
+		nbr.options = dd.Options
+		nbr.event(NEIGHBOR_EVENT_NEGOTIATION_DONE)
+	case NEIGHBOR_STATE_EXCHANGE:
+		if duplicate {
+			if !nbr.master {
+				nbr.sendDd(nbr.lastSentDd)
+			}
+			return
+		}
+		expected := nbr.ddSeqNum
+		if !nbr.master {
+			expected++
+		}
+		if dd.MasterSlave == nbr.master || dd.Init || dd.Options != nbr.options ||
+			dd.DdSequenceNumber != expected {
+			nbr.event(NEIGHBOR_EVENT_SEQ_NUMBER_MISMATCH)
+			return
+		}
+	case NEIGHBOR_STATE_LOADING, NEIGHBOR_STATE_FULL:
+		if !duplicate {
+			nbr.event(NEIGHBOR_EVENT_SEQ_NUMBER_MISMATCH)
+		} else if !nbr.master {
+			nbr.sendDd(nbr.lastSentDd)
+		}
+		return
+	default:
+		return
+	}
+	nbr.lastRecvDd = dd
+	for _, header := range dd.LsaHeaders {
+		if !iface.lsTypeValid(header.LsType()) {
+			nbr.event(NEIGHBOR_EVENT_SEQ_NUMBER_MISMATCH)
+			return
+		}
+		lsa := iface.lsdbOf(header.LsType()).lookup(header.Key())
+		if lsa == nil || header.Compare(lsa.Header()) > 0 {
+			nbr.lsRequestList = append(nbr.lsRequestList, header)
+		}
+	}
+	if nbr.master {
+		nbr.ddSeqNum++
+		if !nbr.lastSentMore && !dd.More {
+			nbr.rxmtTimer = 0
+			nbr.event(NEIGHBOR_EVENT_EXCHANGE_DONE)
+		} else {
+			nbr.sendNextDd()
+		}
+	} else {
+		nbr.ddSeqNum = dd.DdSequenceNumber
+		nbr.sendNextDd()
+		nbr.rxmtTimer = 0
+		if !dd.More && !nbr.lastSentMore {
+			nbr.event(NEIGHBOR_EVENT_EXCHANGE_DONE)
+		}
+	}
+}
+
+// lsrChunkLength is the number of requests which fit in a link state
+// request packet on the interface.
+func (nbr *Neighbor) lsrChunkLength() int {
+	return (nbr.iface.mtu - nbr.iface.headerLength()) / packet.LS_REQUEST_LENGTH
+}
+
+// sendLsr requests the next LSAs of the link state request list unless
+// some of those already requested have not arrived yet.
+func (nbr *Neighbor) sendLsr() {
+	if len(nbr.lsRequestList) == 0 || len(nbr.lsrPending) > 0 {
+		return
+	}
+	lsr, _ := packet.NewLinkStateRequestPacket()
+	for _, header := range nbr.lsRequestList {
+		if len(lsr.Requests) >= nbr.lsrChunkLength() {
+			break
+		}
+		key := header.Key()
+		lsr.Requests = append(lsr.Requests, &key)
+		nbr.lsrPending[key] = true
+	}
+	nbr.rxmtTimer = int(nbr.iface.retransmitInterval())
+	nbr.iface.send(lsr, nbr.dst())
+}
+
+// receiveLsr answers a link state request as in RFC 2328 10.7.
+func (nbr *Neighbor) receiveLsr(lsr *packet.LinkStateRequestPacket) {
+	if nbr.state < NEIGHBOR_STATE_EXCHANGE {
+		return
+	}
+	iface := nbr.iface
+	lsas := make([]packet.OspfLsa, 0, len(lsr.Requests))
+	for _, key := range lsr.Requests {
+		lsa := iface.lsdbOf(key.LsType).lookup(*key)
+		if lsa == nil {
+			nbr.event(NEIGHBOR_EVENT_BAD_LS_REQ)
+			return
+		}
+		lsas = append(lsas, lsa)
+	}
+	iface.sendLsas(lsas, nbr.dst())
+}
+
+func (nbr *Neighbor) findRequest(key packet.LsaKey) *packet.LsaHeader {
+	for _, request := range nbr.lsRequestList {
+		if request.Key() == key {
+			return request
+		}
+	}
+	return nil
+}
+
+func (nbr *Neighbor) removeRequest(key packet.LsaKey) {
+	for i, request := range nbr.lsRequestList {
+		if request.Key() == key {
+			nbr.lsRequestList = append(nbr.lsRequestList[:i], nbr.lsRequestList[i+1:]...)
+			break
+		}
+	}
+	delete(nbr.lsrPending, key)
+}
+
+// checkLoadingDone finishes loading once all the requested LSAs have
+// arrived or requests the next ones.
+func (nbr *Neighbor) checkLoadingDone() {
+	if nbr.state != NEIGHBOR_STATE_LOADING {
+		return
+	}
+	if len(nbr.lsRequestList) == 0 {
+		nbr.rxmtTimer = 0
+		nbr.event(NEIGHBOR_EVENT_LOADING_DONE)
+		return
+	}
+	nbr.sendLsr()
+}
+
+func (nbr *Neighbor) addRetransmission(lsa packet.OspfLsa) {
+	if len(nbr.lsRetransmissionList) == 0 {
+		nbr.lsuRxmtTimer = int(nbr.iface.retransmitInterval())
+	}
+	nbr.lsRetransmissionList[lsa.Header().Key()] = lsa
+}
+
+// retransmitLsas sends the LSAs of the link state retransmission list
+// which have not been acknowledged in time directly to the neighbor.
+func (nbr *Neighbor) retransmitLsas() {
+	keys := make([]packet.LsaKey, 0, len(nbr.lsRetransmissionList))
+	for key := range nbr.lsRetransmissionList {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return lsaKeyLess(keys[i], keys[j])
+	})
+	lsas := make([]packet.OspfLsa, 0, len(keys))
+	for _, key := range keys {
+		lsas = append(lsas, nbr.lsRetransmissionList[key])
+	}
+	nbr.lsuRxmtTimer = int(nbr.iface.retransmitInterval())
+	nbr.iface.sendLsas(lsas, nbr.dst())
+}
+
+// receiveLsu processes the LSAs of a link state update as in RFC 2328
+// 13.
+func (nbr *Neighbor) receiveLsu(lsu *packet.LinkStateUpdatePacket) {
+	if nbr.state < NEIGHBOR_STATE_EXCHANGE {
+		return
+	}
+	iface := nbr.iface
+	ospf := iface.ospf
+	acks := make([]*packet.LsaHeader, 0)
+	for _, lsa := range lsu.Lsas {
+		header := lsa.Header()
+		if !iface.lsTypeValid(header.LsType()) || !packet.LsaChecksumValid(lsa) {
+			continue
+		}
+		key := header.Key()
+		delete(nbr.lsrPending, key)
+		lsdb := iface.lsdbOf(key.LsType)
+		current := lsdb.lookup(key)
+		if header.LsAge >= packet.MAX_AGE && current == nil && !lsdb.exchanging() {
+			acks = append(acks, header)
+			continue
+		}
+		cmp := 1
+		if current != nil {
+			cmp = header.Compare(current.Header())
+		}
+		if cmp > 0 {
+			if current != nil && lsdb.recentlyArrived(key) {
+				continue
+			}
+			lsdb.removeRetransmissions(key)
+			lsdb.install(lsa)
+			if request := nbr.findRequest(key); request != nil && header.Compare(request) >= 0 {
+				nbr.removeRequest(key)
+			}
+			if !lsdb.flood(lsa, nbr) &&
+				(iface.state != INTERFACE_STATE_BACKUP || nbr.id() == iface.dr) {
+				iface.delayAck(header)
+			}
+			if ospf.selfOriginated(lsa) {
+				lsdb.receiveSelfOriginated(lsa)
+			}
+			continue
+		}
+		if nbr.findRequest(key) != nil {
+			nbr.event(NEIGHBOR_EVENT_BAD_LS_REQ)
+			break
+		}
+		if cmp == 0 {
+			if _, ok := nbr.lsRetransmissionList[key]; ok {
+				// implied acknowledgment
+				delete(nbr.lsRetransmissionList, key)
+				if iface.state == INTERFACE_STATE_BACKUP && nbr.id() == iface.dr {
+					iface.delayAck(header)
+				}
+			} else {
+				acks = append(acks, header)
+			}
+			continue
+		}
+		if current.Header().LsAge >= packet.MAX_AGE &&
+			current.Header().LsSequenceNumber == packet.MAX_SEQUENCE_NUMBER {
+			continue
+		}
+		iface.sendLsas([]packet.OspfLsa{current}, nbr.dst())
+	}
+	if len(acks) > 0 {
+		iface.sendAcks(acks, nbr.dst())
+	}
+	nbr.checkLoadingDone()
+}
+
+// receiveLsAck removes the acknowledged LSAs from the link state
+// retransmission list as in RFC 2328 13.7.
+func (nbr *Neighbor) receiveLsAck(ack *packet.LinkStateAckPacket) {
+	if nbr.state < NEIGHBOR_STATE_EXCHANGE {
+		return
+	}
+	for _, header := range ack.LsaHeaders {
+		lsa, ok := nbr.lsRetransmissionList[header.Key()]
+		if ok && header.Compare(lsa.Header()) == 0 {
+			delete(nbr.lsRetransmissionList, header.Key())
+		}
+	}
+}
@@ -0,0 +1,269 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/m-asama/golsr/internal/pkg/kernel"
+	"github.com/m-asama/golsr/internal/pkg/ospf/config"
+	"github.com/m-asama/golsr/pkg/ospf/packet"
+)
+
+type testPort struct {
+	area     string
+	segment  string
+	name     string
+	address  uint32
+	ifType   kernel.IfType
+	priority uint8
+	// address6 is the global IPv6 address of the port, which makes
+	// the router run OSPFv3.
+	address6 [4]uint32
+	// areaConfig is added to the configuration of the area of the
+	// port as is.
+	areaConfig string
+	// ifConfig is added to the configuration of the interface of the
+	// port as is, and globalConfig to the top of the configuration.
+	ifConfig     string
+	globalConfig string
+	// interfaceType overrides the interface type given by ifType.
+	interfaceType string
+}
+
+type testRouter struct {
+	ospf     *OspfServer
+	kernel   *kernel.FakeProvider
+	configCh chan *config.OspfConfig
+	kernelCh chan *kernel.KernelStatus
+	doneCh   chan struct{}
+	wg       sync.WaitGroup
+}
+
+// startTestRouter starts a router with the ports, connecting those with a
+// segment to network. The ports without an area are in the backbone, and
+// those of an area are to be consecutive.
+func startTestRouter(t *testing.T, network *MemoryNetwork, name string, routerId uint32, ports []*testPort) *testRouter {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[config]\n")
+	fmt.Fprintf(&b, "  explicit-router-id = %q\n", packet.Ipv4String(routerId))
+	for _, port := range ports {
+		if port.address6 != [4]uint32{} {
+			fmt.Fprintf(&b, "  address-family = \"ipv6\"\n")
+			break
+		}
+	}
+	for _, port := range ports {
+		b.WriteString(port.globalConfig)
+	}
+	fake := kernel.NewFakeProvider()
+	areas := make([]string, 0)
+	for _, port := range ports {
+		if port.area == "" {
+			port.area = "0.0.0.0"
+		}
+		if len(areas) == 0 || areas[len(areas)-1] != port.area {
+			areas = append(areas, port.area)
+		}
+	}
+	for _, area := range areas {
+		fmt.Fprintf(&b, "[[areas]]\n")
+		fmt.Fprintf(&b, "  [areas.config]\n")
+		fmt.Fprintf(&b, "    area-id = %q\n", area)
+		for _, port := range ports {
+			if port.area == area && port.areaConfig != "" {
+				b.WriteString(port.areaConfig)
+				break
+			}
+		}
+		for i, port := range ports {
+			if port.area != area {
+				continue
+			}
+			interfaceType := "broadcast"
+			if port.ifType == kernel.IF_TYPE_POINTTOPOINT {
+				interfaceType = "point-to-point"
+			}
+			if port.interfaceType != "" {
+				interfaceType = port.interfaceType
+			}
+			fmt.Fprintf(&b, "  [[areas.interfaces]]\n")
+			fmt.Fprintf(&b, "    [areas.interfaces.config]\n")
+			fmt.Fprintf(&b, "      name = %q\n", port.name)
+			fmt.Fprintf(&b, "      interface-type = %q\n", interfaceType)
+			fmt.Fprintf(&b, "      hello-interval = 1\n")
+			fmt.Fprintf(&b, "      priority = %d\n", port.priority)
+			b.WriteString(port.ifConfig)
+			prefixLength := 24
+			if port.ifType == kernel.IF_TYPE_LOOPBACK {
+				prefixLength = 32
+			}
+			ipv6Addresses := make([]*kernel.Ipv6Address, 0)
+			if port.address6 != [4]uint32{} {
+				if port.ifType == kernel.IF_TYPE_LOOPBACK {
+					ipv6Addresses = append(ipv6Addresses, &kernel.Ipv6Address{Address: port.address6, PrefixLength: 128})
+				} else {
+					ipv6Addresses = append(ipv6Addresses,
+						&kernel.Ipv6Address{Address: [4]uint32{0xfe800000, 0, 0, routerId}, PrefixLength: 64, ScopeLink: true},
+						&kernel.Ipv6Address{Address: port.address6, PrefixLength: 64})
+				}
+			}
+			fake.SetInterface(&kernel.Interface{
+				IfIndex: i + 2,
+				Name:    port.name,
+				IfType:  port.ifType,
+				Mtu:     1500,
+				Up:      true,
+				Ipv4Addresses: []*kernel.Ipv4Address{
+					&kernel.Ipv4Address{Address: port.address, PrefixLength: prefixLength},
+				},
+				Ipv6Addresses: ipv6Addresses,
+			})
+			if port.segment != "" {
+				network.Connect(port.segment, name, port.name)
+			}
+		}
+	}
+	cfg, err := config.Parse([]byte(b.String()), "toml")
+	if err != nil {
+		t.Fatalf("failed config.Parse: %v", err)
+	}
+	router := &testRouter{
+		ospf:     NewOspfServer("", ""),
+		kernel:   fake,
+		configCh: make(chan *config.OspfConfig),
+		kernelCh: make(chan *kernel.KernelStatus),
+		doneCh:   make(chan struct{}),
+	}
+	router.ospf.SetKernelProvider(fake)
+	router.ospf.SetFib(true)
+	router.ospf.SetTransport(network.TransportFactory(name))
+	router.wg.Add(1)
+	go router.ospf.ServeWith(&router.wg, router.configCh, router.kernelCh)
+	go fake.Watch(router.kernelCh, router.doneCh)
+	router.configCh <- cfg
+	return router
+}
+
+func (router *testRouter) stop() {
+	router.ospf.Exit()
+	router.wg.Wait()
+	close(router.doneCh)
+}
+
+// neighborStates returns the states of the neighbors of the interface
+// name by their router IDs, and the DR and BDR of the interface.
+func (router *testRouter) neighborStates(name string) (map[uint32]NeighborState, uint32, uint32) {
+	ospf := router.ospf
+	ospf.lock.RLock()
+	defer ospf.lock.RUnlock()
+	states := make(map[uint32]NeighborState)
+	iface := ospf.findInterface(name)
+	if iface == nil {
+		return states, 0, 0
+	}
+	for _, nbr := range iface.neighborDb {
+		states[nbr.routerId] = nbr.state
+	}
+	return states, iface.dr, iface.bdr
+}
+
+func (router *testRouter) hasLsa(key packet.LsaKey) bool {
+	ospf := router.ospf
+	ospf.lock.RLock()
+	defer ospf.lock.RUnlock()
+	area := ospf.findArea(0)
+	return area != nil && area.lookupLsa(key) != nil
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	deadline := time.Now().Add(30 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+func TestNeighborPointToPoint(t *testing.T) {
+	network := NewMemoryNetwork()
+	r1 := startTestRouter(t, network, "r1", 0x01010101, []*testPort{
+		&testPort{segment: "l", name: "eth0", address: 0x0a000001, ifType: kernel.IF_TYPE_POINTTOPOINT, priority: 1},
+	})
+	defer r1.stop()
+	r2 := startTestRouter(t, network, "r2", 0x02020202, []*testPort{
+		&testPort{segment: "l", name: "eth0", address: 0x0a000002, ifType: kernel.IF_TYPE_POINTTOPOINT, priority: 1},
+	})
+	defer r2.stop()
+
+	waitFor(t, "full adjacency", func() bool {
+		s1, _, _ := r1.neighborStates("eth0")
+		s2, _, _ := r2.neighborStates("eth0")
+		return s1[0x02020202] == NEIGHBOR_STATE_FULL && s2[0x01010101] == NEIGHBOR_STATE_FULL
+	})
+	waitFor(t, "router-LSAs", func() bool {
+		return r1.hasLsa(packet.LsaKey{LsType: packet.LS_TYPE_ROUTER, LinkStateId: 0x02020202, AdvertisingRouter: 0x02020202}) &&
+			r2.hasLsa(packet.LsaKey{LsType: packet.LS_TYPE_ROUTER, LinkStateId: 0x01010101, AdvertisingRouter: 0x01010101})
+	})
+}
+
+func TestNeighborBroadcast(t *testing.T) {
+	network := NewMemoryNetwork()
+	routers := make([]*testRouter, 0)
+	for i := 1; i <= 3; i++ {
+		router := startTestRouter(t, network, fmt.Sprintf("r%d", i), uint32(0x01010101*i), []*testPort{
+			&testPort{segment: "lan", name: "eth0", address: 0x0a000000 + uint32(i), ifType: kernel.IF_TYPE_BROADCAST, priority: 1},
+		})
+		defer router.stop()
+		routers = append(routers, router)
+	}
+
+	// the highest router ID is elected DR and the next one BDR and all
+	// of them become adjacent with both
+	waitFor(t, "full adjacencies", func() bool {
+		for i, router := range routers {
+			states, dr, bdr := router.neighborStates("eth0")
+			if dr != 0x0a000003 || bdr != 0x0a000002 || len(states) != 2 {
+				return false
+			}
+			for j := range routers {
+				if j != i && states[uint32(0x01010101*(j+1))] != NEIGHBOR_STATE_FULL {
+					return false
+				}
+			}
+		}
+		return true
+	})
+	waitFor(t, "network-LSA", func() bool {
+		for _, router := range routers {
+			ospf := router.ospf
+			ospf.lock.RLock()
+			lsa := ospf.findArea(0).lookupLsa(packet.LsaKey{LsType: packet.LS_TYPE_NETWORK, LinkStateId: 0x0a000003, AdvertisingRouter: 0x03030303})
+			ospf.lock.RUnlock()
+			if lsa == nil || len(lsa.(*packet.NetworkLsa).AttachedRouters) != 3 {
+				return false
+			}
+		}
+		return true
+	})
+}
@@ -0,0 +1,189 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	log "github.com/sirupsen/logrus"
+
+	"github.com/m-asama/golsr/pkg/ospf/packet"
+)
+
+// asBoundary tells whether the router is an AS boundary router, that is
+// originates AS-external-LSAs or NSSA-LSAs.
+func (ospf *OspfServer) asBoundary() bool {
+	lsdbs := []*Lsdb{ospf.externalLsdb}
+	for _, area := range ospf.areaDb {
+		lsdbs = append(lsdbs, area.lsdb)
+	}
+	for _, lsdb := range lsdbs {
+		for key, lsa := range lsdb.lsas {
+			switch key.LsType {
+			case packet.LS_TYPE_AS_EXTERNAL, packet.LS_TYPE_NSSA_EXTERNAL,
+				packet.LS_TYPE_OSPFV3_AS_EXTERNAL, packet.LS_TYPE_OSPFV3_NSSA_EXTERNAL:
+			default:
+				continue
+			}
+			if key.AdvertisingRouter == ospf.routerId && lsa.Header().LsAge < packet.MAX_AGE {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// nssaTranslator tells whether the router translates the NSSA-LSAs of
+// the area into AS-external-LSAs, being the NSSA border router of the
+// highest router ID as in RFC 3101 3.1 and none of the others translating
+// unconditionally.
+func (area *Area) nssaTranslator() bool {
+	ospf := area.ospf
+	if area.areaType() != AREA_TYPE_NSSA || !ospf.areaBorder() || !area.active() {
+		return false
+	}
+	for _, ri := range area.routerRiDb {
+		if !ri.border {
+			continue
+		}
+		if ri.nssaTranslator || ri.routerId > ospf.routerId {
+			return false
+		}
+	}
+	return true
+}
+
+// translations returns the AS-external-LSAs the router originates for the
+// NSSA-LSAs of the NSSAs it translates as in RFC 3101 3.2. Only those
+// with the P-bit and a forwarding address which are the routes the
+// router uses are translated.
+func (ospf *OspfServer) translations() map[packet.LsaKey]packet.OspfLsa {
+	lsas := make(map[packet.LsaKey]packet.OspfLsa)
+	for _, area := range ospf.areaDb {
+		if !area.nssaTranslator() {
+			continue
+		}
+		for _, key := range area.lsdb.keys() {
+			if key.LsType != packet.LS_TYPE_NSSA_EXTERNAL || key.AdvertisingRouter == ospf.routerId {
+				continue
+			}
+			lsa := area.lsdb.lsas[key].(*packet.ExternalLsa)
+			if lsa.Header().LsAge >= packet.MAX_AGE || lsa.Header().Options&packet.OPTIONS_NP == 0 ||
+				lsa.ForwardingAddress == 0 || lsa.Metric >= packet.LS_INFINITY {
+				continue
+			}
+			riKey := Ipv4RiKey{key.LinkStateId & lsa.NetworkMask, prefixLength(lsa.NetworkMask)}
+			ri := ospf.ipv4RiDb[riKey]
+			if ri == nil || ri.areaId != area.areaId ||
+				(ri.routeType != ROUTE_TYPE_EXTERNAL_1 && ri.routeType != ROUTE_TYPE_EXTERNAL_2) {
+				continue
+			}
+			translated, _ := packet.NewExternalLsa(packet.LS_TYPE_AS_EXTERNAL)
+			translated.Header().LinkStateId = key.LinkStateId
+			translated.Header().AdvertisingRouter = ospf.routerId
+			translated.Header().Options = packet.OPTIONS_E
+			translated.NetworkMask = lsa.NetworkMask
+			translated.ExternalMetric = lsa.ExternalMetric
+			translated.Metric = lsa.Metric
+			translated.ForwardingAddress = lsa.ForwardingAddress
+			translated.ExternalRouteTag = lsa.ExternalRouteTag
+			if _, ok := lsas[translated.Header().Key()]; !ok {
+				lsas[translated.Header().Key()] = translated
+			}
+		}
+	}
+	return lsas
+}
+
+// newNssaDefault returns the NSSA-LSA of the default route the router
+// originates into an NSSA as an NSSA border router as in RFC 3101 2.7.
+// The P-bit is clear so that it is not translated.
+func (ospf *OspfServer) newNssaDefault(area *Area) packet.OspfLsa {
+	lsa, _ := packet.NewExternalLsa(packet.LS_TYPE_NSSA_EXTERNAL)
+	lsa.Header().LinkStateId = 0
+	lsa.Header().AdvertisingRouter = ospf.routerId
+	lsa.NetworkMask = 0
+	lsa.ExternalMetric = true
+	lsa.Metric = area.defaultCost()
+	return lsa
+}
+
+// originateExternals brings the AS-external-LSAs translated from the
+// NSSA-LSAs and the NSSA defaults in line with the routing table, and
+// then the router-LSAs in line with whether the router is an AS boundary
+// router and a translator as a result.
+func (ospf *OspfServer) originateExternals() {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	if ospf.routerId == 0 {
+		return
+	}
+	if ospf.version == packet.OSPF_VERSION {
+		lsas := ospf.translations()
+		for _, key := range ospf.externalLsdb.keys() {
+			if key.LsType != packet.LS_TYPE_AS_EXTERNAL || key.AdvertisingRouter != ospf.routerId {
+				continue
+			}
+			if _, ok := lsas[key]; !ok {
+				ospf.externalLsdb.flush(key)
+			}
+		}
+		for _, key := range sortedLsaKeys(lsas) {
+			ospf.externalLsdb.originate(lsas[key])
+		}
+		for _, area := range ospf.areaDb {
+			if area.areaType() == AREA_TYPE_NSSA && area.summary() &&
+				ospf.areaBorder() && area.active() {
+				area.originateLsa(ospf.newNssaDefault(area))
+				continue
+			}
+			area.flushLsa(packet.LsaKey{
+				LsType:            packet.LS_TYPE_NSSA_EXTERNAL,
+				LinkStateId:       0,
+				AdvertisingRouter: ospf.routerId,
+			})
+		}
+	}
+	for _, area := range ospf.areaDb {
+		area.originateRouterLsa()
+	}
+}
+
+// nssaRoutes adds the routes of the NSSA-LSAs of the area as in RFC 3101
+// 2.5.
+func (ospf *OspfServer) nssaRoutes(area *Area, ipv4RiDb map[Ipv4RiKey]*Ipv4Ri) {
+	border := ospf.areaBorder()
+	for _, key := range area.lsdb.keys() {
+		if key.LsType != packet.LS_TYPE_NSSA_EXTERNAL {
+			continue
+		}
+		lsa := area.lsdb.lsas[key].(*packet.ExternalLsa)
+		if lsa.Header().LsAge >= packet.MAX_AGE || lsa.Metric >= packet.LS_INFINITY ||
+			key.AdvertisingRouter == ospf.routerId {
+			continue
+		}
+		// the defaults of the other NSSA border routers are not used by
+		// a border router
+		if border && key.LinkStateId == 0 && lsa.NetworkMask == 0 {
+			continue
+		}
+		asbr := area.routerRiDb[key.AdvertisingRouter]
+		if asbr == nil || (!asbr.asbr && !asbr.border) {
+			continue
+		}
+		ospf.addExternalRoute(ipv4RiDb, lsa, asbr)
+	}
+}
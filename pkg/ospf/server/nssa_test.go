@@ -0,0 +1,127 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"testing"
+
+	"github.com/m-asama/golsr/internal/pkg/kernel"
+	"github.com/m-asama/golsr/pkg/ospf/packet"
+)
+
+func TestStubAndNssa(t *testing.T) {
+	// r1 --(0.0.0.1 totally stubby)-- r2 --(backbone)-- r4
+	//                                  |
+	//                          (0.0.0.2 NSSA)
+	//                                  |
+	//                                  r3
+	stub := "    area-type = \"stub\"\n    summary = false\n"
+	nssa := "    area-type = \"nssa\"\n"
+	network := NewMemoryNetwork()
+	r1 := startTestRouter(t, network, "r1", 0x01010101, []*testPort{
+		&testPort{area: "0.0.0.1", name: "lo", address: 0x01010101, ifType: kernel.IF_TYPE_LOOPBACK, areaConfig: stub},
+		&testPort{area: "0.0.0.1", name: "lo1", address: 0x01010201, ifType: kernel.IF_TYPE_LOOPBACK},
+		&testPort{area: "0.0.0.1", segment: "a", name: "eth0", address: 0x0a000101, ifType: kernel.IF_TYPE_POINTTOPOINT, priority: 1},
+	})
+	defer r1.stop()
+	r2 := startTestRouter(t, network, "r2", 0x02020202, []*testPort{
+		&testPort{area: "0.0.0.1", segment: "a", name: "eth0", address: 0x0a000102, ifType: kernel.IF_TYPE_POINTTOPOINT, priority: 1,
+			areaConfig: stub + "  [[areas.ranges]]\n    [areas.ranges.config]\n      prefix = \"1.1.0.0/16\"\n"},
+		&testPort{area: "0.0.0.2", segment: "c", name: "eth2", address: 0x0a000302, ifType: kernel.IF_TYPE_POINTTOPOINT, priority: 1, areaConfig: nssa},
+		&testPort{segment: "b", name: "eth1", address: 0x0a000202, ifType: kernel.IF_TYPE_BROADCAST, priority: 1},
+		&testPort{name: "lo", address: 0x02020202, ifType: kernel.IF_TYPE_LOOPBACK},
+	})
+	defer r2.stop()
+	r3 := startTestRouter(t, network, "r3", 0x03030303, []*testPort{
+		&testPort{area: "0.0.0.2", segment: "c", name: "eth0", address: 0x0a000303, ifType: kernel.IF_TYPE_POINTTOPOINT, priority: 1, areaConfig: nssa},
+		&testPort{area: "0.0.0.2", name: "lo", address: 0x03030303, ifType: kernel.IF_TYPE_LOOPBACK},
+	})
+	defer r3.stop()
+	r4 := startTestRouter(t, network, "r4", 0x04040404, []*testPort{
+		&testPort{segment: "b", name: "eth0", address: 0x0a000204, ifType: kernel.IF_TYPE_BROADCAST, priority: 1},
+		&testPort{name: "lo", address: 0x04040404, ifType: kernel.IF_TYPE_LOOPBACK},
+	})
+	defer r4.stop()
+
+	waitFor(t, "routes", func() bool {
+		return r1.fibRoute(0, 0) != nil && r4.fibRoute(0x03030303, 32) != nil &&
+			r3.fibRoute(0x04040404, 32) != nil
+	})
+
+	// r3 originates an NSSA-LSA with the P-bit which r2 translates
+	r3.ospf.lock.Lock()
+	lsa, _ := packet.NewExternalLsa(packet.LS_TYPE_NSSA_EXTERNAL)
+	lsa.Header().LinkStateId = 0x0a630000
+	lsa.Header().Options = packet.OPTIONS_NP
+	lsa.NetworkMask = 0xffff0000
+	lsa.ExternalMetric = true
+	lsa.Metric = 100
+	lsa.ForwardingAddress = 0x03030303
+	area := r3.ospf.findArea(2)
+	area.originateLsa(lsa)
+	area.originateRouterLsa()
+	r3.ospf.lock.Unlock()
+
+	waitFor(t, "translated route", func() bool {
+		route := r4.fibRoute(0x0a630000, 16)
+		return route != nil && len(route.NextHops) == 1 && route.NextHops[0].Address == 0x0a000202
+	})
+	r4.ospf.lock.RLock()
+	ri := r4.ospf.ipv4RiDb[Ipv4RiKey{0x0a630000, 16}]
+	translated := r4.ospf.externalLsdb.lookup(packet.LsaKey{
+		LsType:            packet.LS_TYPE_AS_EXTERNAL,
+		LinkStateId:       0x0a630000,
+		AdvertisingRouter: 0x02020202,
+	})
+	r4.ospf.lock.RUnlock()
+	if ri == nil || ri.routeType != ROUTE_TYPE_EXTERNAL_2 || ri.type2Metric != 100 || translated == nil {
+		t.Fatalf("failed translated route: %v", ri)
+	}
+
+	// the routes of area 0.0.0.1 are summarized by the range
+	waitFor(t, "range", func() bool {
+		return r4.fibRoute(0x01010000, 16) != nil
+	})
+	if r4.fibRoute(0x01010101, 32) != nil || r4.fibRoute(0x01010201, 32) != nil {
+		t.Fatalf("failed range")
+	}
+
+	// the NSSA gets a default from r2
+	waitFor(t, "nssa default", func() bool {
+		route := r3.fibRoute(0, 0)
+		return route != nil && len(route.NextHops) == 1 && route.NextHops[0].Address == 0x0a000302
+	})
+
+	// the totally stubby area gets the default summary only
+	r1.ospf.lock.RLock()
+	defer r1.ospf.lock.RUnlock()
+	for key := range r1.ospf.findArea(1).lsdb.lsas {
+		if key.LsType == packet.LS_TYPE_SUMMARY_NETWORK && key.LinkStateId != 0 {
+			t.Fatalf("summary in totally stubby area: %v", key)
+		}
+		if key.LsType == packet.LS_TYPE_SUMMARY_ASBR {
+			t.Fatalf("ASBR-summary in totally stubby area: %v", key)
+		}
+	}
+	for key := range r1.ospf.externalLsdb.lsas {
+		t.Fatalf("AS-external-LSA in stub area: %v", key)
+	}
+	if _, ok := r1.ospf.ipv4RiDb[Ipv4RiKey{0x04040404, 32}]; ok {
+		t.Fatalf("inter-area route in totally stubby area")
+	}
+}
@@ -22,12 +22,13 @@ import (
 	"os/signal"
 	"sync"
 	"syscall"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
 	"github.com/m-asama/golsr/internal/pkg/kernel"
 	"github.com/m-asama/golsr/internal/pkg/ospf/config"
-	_ "github.com/m-asama/golsr/pkg/ospf/packet"
+	"github.com/m-asama/golsr/pkg/ospf/packet"
 )
 
 type OspfChMsg uint8
@@ -46,6 +47,34 @@ type OspfServer struct {
 	configType string
 	config     *config.OspfConfig
 	kernel     *kernel.KernelStatus
+	provider   kernel.Provider
+	transport  TransportFactory
+
+	// version is the OSPF version the router runs, 3 if the
+	// address-family is ipv6 and 2 otherwise.
+	version      uint8
+	routerId     uint32
+	areaDb       []*Area
+	interfaceDb  []*Interface
+	externalLsdb *Lsdb
+	spfRequired  bool
+	ipv4RiDb     map[Ipv4RiKey]*Ipv4Ri
+	ipv6RiDb     map[Ipv6RiKey]*Ipv6Ri
+	asbrRiDb     map[uint32]*RouterRi
+	// prefixIds are the link state IDs of the inter-area-prefix-LSAs
+	// the router originates, by prefix.
+	prefixIds map[Ipv6RiKey]uint32
+
+	fibEnable     bool
+	fibIpv4Routes map[string]*kernel.Ipv4Route
+	fibIpv6Routes map[string]*kernel.Ipv6Route
+	fibLock       sync.Mutex
+
+	// cryptoSeqNum is the cryptographic sequence number of the last
+	// packet sent.
+	cryptoSeqNum uint64
+
+	stub stubRouter
 
 	lock sync.RWMutex
 }
@@ -54,20 +83,78 @@ func NewOspfServer(configFile, configType string) *OspfServer {
 	log.Debugf("enter")
 	defer log.Debugf("exit")
 	ospf := &OspfServer{
-		ospfCh:     make(chan OspfChMsg),
-		decisionCh: make(chan *DecisionChMsg, 8),
-		updateCh:   make(chan *UpdateChMsg, 8),
-		configFile: configFile,
-		configType: configType,
-		config:     config.NewOspfConfig(),
-		kernel:     kernel.NewKernelStatus(),
+		ospfCh:      make(chan OspfChMsg),
+		decisionCh:  make(chan *DecisionChMsg, 8),
+		updateCh:    make(chan *UpdateChMsg, 8),
+		configFile:  configFile,
+		configType:  configType,
+		config:      config.NewOspfConfig(),
+		kernel:      &kernel.KernelStatus{},
+		provider:    kernel.DefaultProvider(),
+		transport:   NewRawTransport,
+		areaDb:      make([]*Area, 0),
+		interfaceDb: make([]*Interface, 0),
+		version:     packet.OSPF_VERSION,
+
+		ipv4RiDb:      make(map[Ipv4RiKey]*Ipv4Ri),
+		ipv6RiDb:      make(map[Ipv6RiKey]*Ipv6Ri),
+		asbrRiDb:      make(map[uint32]*RouterRi),
+		prefixIds:     make(map[Ipv6RiKey]uint32),
+		fibIpv4Routes: make(map[string]*kernel.Ipv4Route),
+		fibIpv6Routes: make(map[string]*kernel.Ipv6Route),
+		cryptoSeqNum:  uint64(time.Now().Unix()) << 32,
 	}
+	ospf.externalLsdb = NewLsdb(ospf, nil, nil)
+	enable := false
+	ospf.config.Config.Enable = &enable
 	return ospf
 }
 
+// SetKernelProvider sets the provider the interfaces are watched on. It
+// must be called before Serve.
+func (ospf *OspfServer) SetKernelProvider(provider kernel.Provider) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	ospf.provider = provider
+}
+
+// SetTransport sets the factory the transports of the interfaces are
+// opened with. It must be called before Serve.
+func (ospf *OspfServer) SetTransport(factory TransportFactory) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	ospf.transport = factory
+}
+
 func (ospf *OspfServer) Serve(wg *sync.WaitGroup) {
 	log.Debugf("enter")
 	defer log.Debugf("exit")
+
+	configCh := make(chan *config.OspfConfig)
+	if ospf.configFile != "" {
+		go config.Serve(ospf.configFile, ospf.configType, configCh)
+	}
+
+	kernelCh := make(chan *kernel.KernelStatus)
+	kernelDoneCh := make(chan struct{})
+	defer close(kernelDoneCh)
+	go ospf.provider.Watch(kernelCh, kernelDoneCh)
+
+	ospf.serve(wg, configCh, ospf.configFile != "", kernelCh)
+}
+
+// ServeWith runs the server on the configs and interface lists received
+// from configCh and kernelCh in place of the config file and the host's
+// interfaces. Nothing is processed until one of each has been received.
+func (ospf *OspfServer) ServeWith(wg *sync.WaitGroup, configCh chan *config.OspfConfig, kernelCh chan *kernel.KernelStatus) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	ospf.serve(wg, configCh, true, kernelCh)
+}
+
+func (ospf *OspfServer) serve(wg *sync.WaitGroup, configCh chan *config.OspfConfig, waitConfig bool, kernelCh chan *kernel.KernelStatus) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
 	defer wg.Done()
 
 	log.Debugf("")
@@ -75,17 +162,13 @@ func (ospf *OspfServer) Serve(wg *sync.WaitGroup) {
 	var updateWg sync.WaitGroup
 
 	sigCh := make(chan os.Signal, 1)
-	configCh := make(chan *config.OspfConfig)
-	if ospf.configFile != "" {
+	if waitConfig {
 		updateWg.Add(1)
-		go config.Serve(ospf.configFile, ospf.configType, configCh)
 	} else {
 		signal.Notify(sigCh, syscall.SIGHUP)
 	}
 
-	kernelCh := make(chan *kernel.KernelStatus)
 	updateWg.Add(1)
-	go kernel.Serve(kernelCh)
 
 	periodicCh := make(chan struct{})
 	go ospf.periodic(periodicCh)
@@ -106,6 +189,13 @@ func (ospf *OspfServer) Serve(wg *sync.WaitGroup) {
 			case OSPF_CH_MSG_EXIT:
 				log.Debugf("OSPF_CH_MSG_EXIT")
 				periodicCh <- struct{}{}
+				ospf.lock.Lock()
+				for _, iface := range ospf.interfaceDb {
+					iface.down()
+				}
+				ospf.lock.Unlock()
+				ospf.SetFib(false)
+				ospf.fibUpdate()
 				goto EXIT
 			}
 		case c := <-configCh:
@@ -134,7 +224,9 @@ func (ospf *OspfServer) Exit() {
 func (ospf *OspfServer) SetEnable() {
 	log.Debugf("enter")
 	defer log.Debugf("exit")
+	ospf.lock.Lock()
 	*ospf.config.Config.Enable = true
+	ospf.lock.Unlock()
 	ospf.updateChSend(&UpdateChMsg{
 		msgType: UPDATE_CH_MSG_TYPE_OSPF_ENABLE,
 	})
@@ -143,7 +235,9 @@ func (ospf *OspfServer) SetEnable() {
 func (ospf *OspfServer) SetDisable() {
 	log.Debugf("enter")
 	defer log.Debugf("exit")
+	ospf.lock.Lock()
 	*ospf.config.Config.Enable = false
+	ospf.lock.Unlock()
 	ospf.updateChSend(&UpdateChMsg{
 		msgType: UPDATE_CH_MSG_TYPE_OSPF_DISABLE,
 	})
@@ -153,77 +247,230 @@ func (ospf *OspfServer) enable() bool {
 	return *ospf.config.Config.Enable
 }
 
+func (ospf *OspfServer) ready() bool {
+	return ospf.enable() && ospf.routerId != 0
+}
+
+// newRouterId returns the explicit router ID if configured or else the
+// highest address of the loopback interfaces, or of any interface if the
+// loopbacks have none.
+func (ospf *OspfServer) newRouterId() uint32 {
+	if ospf.config.Config.ExplicitRouterId != nil {
+		routerId, err := config.ParseRouterId(*ospf.config.Config.ExplicitRouterId)
+		if err == nil {
+			return routerId
+		}
+	}
+	var loopback, other uint32
+	for _, iface := range ospf.kernel.Interfaces {
+		for _, addr := range iface.Ipv4Addresses {
+			if addr.Address>>24 == 127 {
+				continue
+			}
+			if iface.IfType == kernel.IF_TYPE_LOOPBACK {
+				if addr.Address > loopback {
+					loopback = addr.Address
+				}
+			} else if addr.Address > other {
+				other = addr.Address
+			}
+		}
+	}
+	if loopback != 0 {
+		return loopback
+	}
+	return other
+}
+
+// reset brings all the interfaces down and clears the link state
+// databases, as a change of the router ID or of the OSPF version does.
+func (ospf *OspfServer) reset() {
+	for _, iface := range ospf.interfaceDb {
+		iface.down()
+	}
+	for _, area := range ospf.areaDb {
+		area.clearLsdb()
+	}
+	ospf.externalLsdb = NewLsdb(ospf, nil, nil)
+	ospf.prefixIds = make(map[Ipv6RiKey]uint32)
+	ospf.spfRequired = true
+}
+
+func (ospf *OspfServer) updateRouterId() {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	routerId := ospf.newRouterId()
+	if routerId == ospf.routerId {
+		return
+	}
+	log.WithFields(log.Fields{
+		"Topic":    "Ospf",
+		"RouterId": packet.Ipv4String(routerId),
+	}).Info("Router ID changed")
+	ospf.reset()
+	ospf.routerId = routerId
+}
+
+// updateVersion runs OSPFv3 (RFC 5340) if the address-family is ipv6 and
+// OSPFv2 otherwise.
+func (ospf *OspfServer) updateVersion() {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	version := uint8(packet.OSPF_VERSION)
+	if ospf.config.Config.AddressFamily != nil && *ospf.config.Config.AddressFamily == "ipv6" {
+		version = packet.OSPFV3_VERSION
+	}
+	if version == ospf.version {
+		return
+	}
+	log.WithFields(log.Fields{
+		"Topic":   "Ospf",
+		"Version": version,
+	}).Info("Version changed")
+	ospf.reset()
+	ospf.version = version
+}
+
+func (ospf *OspfServer) findArea(areaId uint32) *Area {
+	for _, area := range ospf.areaDb {
+		if area.areaId == areaId {
+			return area
+		}
+	}
+	return nil
+}
+
+func (ospf *OspfServer) findInterface(name string) *Interface {
+	for _, iface := range ospf.interfaceDb {
+		if iface.name == name {
+			return iface
+		}
+	}
+	return nil
+}
+
+func (ospf *OspfServer) removeInterface(iface *Interface) {
+	log.Debugf("enter: %s", iface.name)
+	defer log.Debugf("exit: %s", iface.name)
+	iface.down()
+	interfaceDb := make([]*Interface, 0)
+	for _, tmp := range ospf.interfaceDb {
+		if tmp != iface {
+			interfaceDb = append(interfaceDb, tmp)
+		}
+	}
+	ospf.interfaceDb = interfaceDb
+}
+
 func (ospf *OspfServer) handleConfigChanged(newConfig *config.OspfConfig) {
 	log.Debugf("enter")
 	defer log.Debugf("exit")
-	/*
-		added := make(map[string]*config.Interface)
-		removed := make(map[string]*config.Interface)
-		for _, iface := range ospf.config.Interfaces {
-			removed[*iface.Config.Name] = iface
-		}
-		for _, iface := range newConfig.Interfaces {
-			if _, ok := removed[*iface.Config.Name]; ok {
-				delete(removed, *iface.Config.Name)
-			} else {
-				added[*iface.Config.Name] = iface
+	ospf.lock.Lock()
+	defer ospf.lock.Unlock()
+	areaDb := make([]*Area, 0)
+	ifConfigs := make(map[string]*config.Interface)
+	ifAreas := make(map[string]*Area)
+	virtualLinks := make(map[string]*virtualLink)
+	for _, areaConfig := range newConfig.Areas {
+		areaId, _ := config.ParseAreaId(*areaConfig.Config.AreaId)
+		area := ospf.findArea(areaId)
+		if area == nil {
+			area = NewArea(ospf, areaId, areaConfig)
+		}
+		area.areaConfig = areaConfig
+		areaDb = append(areaDb, area)
+		for _, ifConfig := range areaConfig.Interfaces {
+			ifConfigs[*ifConfig.Config.Name] = ifConfig
+			ifAreas[*ifConfig.Config.Name] = area
+		}
+		for _, vlinkConfig := range areaConfig.VirtualLinks {
+			transitAreaId, _ := config.ParseAreaId(*vlinkConfig.Config.RransitAreaId)
+			routerId, _ := config.ParseRouterId(*vlinkConfig.Config.RouterId)
+			name := virtualLinkName(transitAreaId, routerId)
+			ifConfigs[name] = newVirtualLinkConfig(name, vlinkConfig)
+			ifAreas[name] = area
+			virtualLinks[name] = &virtualLink{
+				transitAreaId: transitAreaId,
+				routerId:      routerId,
 			}
 		}
-		for name, _ := range removed {
-			log.Debugf("remove: %s", name)
-			ospf.removeCircuit(name)
+	}
+	for _, iface := range ospf.interfaceDb {
+		if ifAreas[iface.name] != iface.area {
+			log.Debugf("remove: %s", iface.name)
+			ospf.removeInterface(iface)
 		}
-		for name, iface := range added {
+	}
+	for name, ifConfig := range ifConfigs {
+		iface := ospf.findInterface(name)
+		if iface == nil {
 			log.Debugf("add: %s", name)
-			ospf.addCircuit(name, iface)
-		}
-		for _, tmp := range ospf.circuitDb {
-			for _, iface := range newConfig.Interfaces {
-				if *tmp.ifConfig.Config.Name == *iface.Config.Name {
-					tmp.ifConfig = iface
-				}
-			}
+			iface = NewInterface(ospf, ifAreas[name], name)
+			iface.virtualLink = virtualLinks[name]
+			ospf.interfaceDb = append(ospf.interfaceDb, iface)
 		}
-		ospf.config = newConfig
-		ospf.updateChSend(&UpdateChMsg{
-			msgType: UPDATE_CH_MSG_TYPE_CONFIG_CHANGED,
-		})
-	*/
+		iface.ifConfig = ifConfig
+		iface.ifKernel = ospf.kernel.Interface(name)
+	}
+	ospf.areaDb = areaDb
+	ospf.config = newConfig
+	ospf.updateChSend(&UpdateChMsg{
+		msgType: UPDATE_CH_MSG_TYPE_CONFIG_CHANGED,
+	})
 }
 
 func (ospf *OspfServer) handleKernelChanged(newKernel *kernel.KernelStatus) {
 	log.Debugf("enter")
 	defer log.Debugf("exit")
-	/*
-		removed := make(map[string]*config.Interface)
-		for _, iface := range ospf.config.Interfaces {
-			removed[*iface.Config.Name] = iface
-		}
-		for _, iface := range newKernel.Interfaces {
-			if _, ok := removed[iface.Name]; ok {
-				delete(removed, iface.Name)
+	ospf.lock.Lock()
+	defer ospf.lock.Unlock()
+	for _, iface := range ospf.interfaceDb {
+		iface.ifKernel = newKernel.Interface(iface.name)
+	}
+	ospf.kernel = newKernel
+	ospf.updateChSend(&UpdateChMsg{
+		msgType: UPDATE_CH_MSG_TYPE_KERNEL_CHANGED,
+	})
+}
+
+// updateInterfaces brings the interfaces up and down as the config, the
+// kernel and the router ID allow.
+func (ospf *OspfServer) updateInterfaces() {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	ospf.updateVersion()
+	ospf.updateRouterId()
+	for _, iface := range ospf.interfaceDb {
+		if iface.virtualLink != nil {
+			continue
+		}
+		if iface.state != INTERFACE_STATE_DOWN {
+			address, prefixLength := iface.kernelAddress()
+			if !iface.ready() || address != iface.address ||
+				prefixLength != iface.prefixLength ||
+				iface.kernelLinkLocal() != iface.linkLocal {
+				iface.down()
 			}
 		}
-		for name, _ := range removed {
-			log.Debugf("remove: %s", name)
-			ospf.removeCircuit(name)
-		}
-		for _, tmp := range ospf.circuitDb {
-			for _, iface := range newKernel.Interfaces {
-				if tmp.ifKernel.IfIndex == iface.IfIndex {
-					tmp.ifKernel = iface
-					if tmp.name != tmp.ifKernel.Name {
-						log.Debugf("renamed %s to %s",
-							tmp.name, tmp.ifKernel.Name)
-						tmp.name = tmp.ifKernel.Name
-						*tmp.ifConfig.Config.Name = tmp.ifKernel.Name
-					}
-				}
+		if iface.state == INTERFACE_STATE_DOWN && iface.ready() {
+			iface.up()
+		}
+	}
+	ospf.updateVirtualLinks()
+	for _, iface := range ospf.interfaceDb {
+		iface.updateTtlSecurity()
+	}
+	if ospf.version == packet.OSPFV3_VERSION {
+		// The global addresses are advertised in LSAs of their own
+		// and may change without bringing the interfaces down.
+		for _, iface := range ospf.interfaceDb {
+			if iface.state != INTERFACE_STATE_DOWN {
+				iface.originateLinkLsa()
 			}
 		}
-		ospf.kernel = newKernel
-		ospf.updateChSend(&UpdateChMsg{
-			msgType: UPDATE_CH_MSG_TYPE_KERNEL_CHANGED,
-		})
-	*/
+		for _, area := range ospf.areaDb {
+			area.originateRouterPrefixLsa()
+		}
+	}
+	ospf.updateStubRouter()
 }
@@ -0,0 +1,350 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	log "github.com/sirupsen/logrus"
+
+	"github.com/m-asama/golsr/pkg/ospf/packet"
+)
+
+// newOspfv3RouterLsa returns the router-LSA of the area as in RFC 5340
+// 4.4.3.2. The router originates a single router-LSA, of link state ID 0,
+// into each area.
+func (area *Area) newOspfv3RouterLsa() packet.OspfLsa {
+	ospf := area.ospf
+	lsa, _ := packet.NewOspfv3RouterLsa()
+	lsa.Header().LinkStateId = 0
+	lsa.Options = area.options()
+	lsa.Border = ospf.areaBorder()
+	lsa.External = ospf.asBoundary() && area.areaType() != AREA_TYPE_STUB
+	for _, iface := range area.interfaces() {
+		lsa.Links = append(lsa.Links, iface.ospfv3RouterLinks()...)
+	}
+	return lsa
+}
+
+// ospfv3RouterLinks returns the links of the interface in the router-LSA.
+// Unlike OSPFv2, no stub links are described: the prefixes go into the
+// intra-area-prefix-LSAs.
+func (iface *Interface) ospfv3RouterLinks() []*packet.Ospfv3RouterLink {
+	links := make([]*packet.Ospfv3RouterLink, 0)
+	switch iface.state {
+	case INTERFACE_STATE_DOWN, INTERFACE_STATE_LOOPBACK, INTERFACE_STATE_WAITING:
+	case INTERFACE_STATE_POINT_TO_POINT:
+		for _, nbr := range iface.neighborDb {
+			if nbr.state == NEIGHBOR_STATE_FULL {
+				links = append(links, &packet.Ospfv3RouterLink{
+					Type:                packet.LINK_TYPE_POINT_TO_POINT,
+					Metric:              nbr.cost(),
+					InterfaceId:         iface.interfaceId,
+					NeighborInterfaceId: nbr.interfaceId,
+					NeighborRouterId:    nbr.routerId,
+				})
+			}
+		}
+	default:
+		if !iface.fullWithDr() {
+			break
+		}
+		drInterfaceId := iface.interfaceId
+		if iface.state != INTERFACE_STATE_DR {
+			nbr := iface.findNeighbor(iface.dr, Address{})
+			if nbr == nil {
+				break
+			}
+			drInterfaceId = nbr.interfaceId
+		}
+		links = append(links, &packet.Ospfv3RouterLink{
+			Type:                packet.LINK_TYPE_TRANSIT,
+			Metric:              iface.cost(),
+			InterfaceId:         iface.interfaceId,
+			NeighborInterfaceId: drInterfaceId,
+			NeighborRouterId:    iface.dr,
+		})
+	}
+	return links
+}
+
+// transit tells whether the interface is described by a transit link, and
+// its prefixes by the intra-area-prefix-LSA of the network.
+func (iface *Interface) transit() bool {
+	switch iface.state {
+	case INTERFACE_STATE_DR, INTERFACE_STATE_BACKUP, INTERFACE_STATE_DR_OTHER:
+		return iface.fullWithDr()
+	}
+	return false
+}
+
+// ipv6Prefixes returns the global prefixes of the interface as described
+// in the LSAs.
+func (iface *Interface) ipv6Prefixes() []*packet.Ipv6Prefix {
+	prefixes := make([]*packet.Ipv6Prefix, 0)
+	for _, addr := range iface.kernelPrefixes() {
+		prefixes = append(prefixes, &packet.Ipv6Prefix{
+			PrefixLength: uint8(addr.PrefixLength),
+			Prefix:       maskIpv6(addr.Address, uint8(addr.PrefixLength)),
+		})
+	}
+	return prefixes
+}
+
+// newLinkLsa returns the link-LSA of the interface as in RFC 5340
+// 4.4.3.8, or nil if the interface originates none.
+func (iface *Interface) newLinkLsa() packet.OspfLsa {
+	if iface.state == INTERFACE_STATE_DOWN || iface.state == INTERFACE_STATE_LOOPBACK {
+		return nil
+	}
+	lsa, _ := packet.NewLinkLsa()
+	lsa.Header().LinkStateId = iface.interfaceId
+	lsa.RouterPriority = iface.priority()
+	lsa.Options = iface.area.options()
+	lsa.LinkLocalAddress = iface.linkLocal
+	lsa.Prefixes = iface.ipv6Prefixes()
+	return lsa
+}
+
+func (iface *Interface) originateLinkLsa() {
+	log.Debugf("enter: %s", iface.name)
+	defer log.Debugf("exit: %s", iface.name)
+	if iface.ospf.routerId == 0 || iface.ospf.version != packet.OSPFV3_VERSION {
+		return
+	}
+	if lsa := iface.newLinkLsa(); lsa != nil {
+		iface.lsdb.originate(lsa)
+	}
+}
+
+// newRouterPrefixLsa returns the intra-area-prefix-LSA which goes with the
+// router-LSA of the area as in RFC 5340 4.4.3.9. It describes the prefixes
+// of the interfaces which are not transit links, or nil if there are none.
+func (area *Area) newRouterPrefixLsa() packet.OspfLsa {
+	prefixes := make([]*packet.Ipv6Prefix, 0)
+	for _, iface := range area.interfaces() {
+		switch {
+		case iface.state == INTERFACE_STATE_DOWN || iface.transit():
+		case iface.state == INTERFACE_STATE_LOOPBACK:
+			for _, addr := range iface.kernelPrefixes() {
+				prefixes = append(prefixes, &packet.Ipv6Prefix{
+					PrefixLength:  128,
+					PrefixOptions: packet.PREFIX_OPTIONS_LA,
+					Prefix:        addr.Address,
+				})
+			}
+		default:
+			for _, prefix := range iface.ipv6Prefixes() {
+				prefix.Metric = iface.cost()
+				prefixes = append(prefixes, prefix)
+			}
+		}
+	}
+	if len(prefixes) == 0 {
+		return nil
+	}
+	lsa, _ := packet.NewIntraAreaPrefixLsa()
+	lsa.Header().LinkStateId = 0
+	lsa.ReferencedLsType = packet.LS_TYPE_OSPFV3_ROUTER
+	lsa.ReferencedLinkStateId = 0
+	lsa.ReferencedAdvertisingRouter = area.ospf.routerId
+	lsa.Prefixes = prefixes
+	return lsa
+}
+
+func (area *Area) originateRouterPrefixLsa() {
+	log.Debugf("enter: %s", packet.Ipv4String(area.areaId))
+	defer log.Debugf("exit: %s", packet.Ipv4String(area.areaId))
+	if area.ospf.routerId == 0 || area.ospf.version != packet.OSPFV3_VERSION {
+		return
+	}
+	lsa := area.newRouterPrefixLsa()
+	if lsa == nil {
+		area.flushLsa(packet.LsaKey{
+			LsType:            packet.LS_TYPE_INTRA_AREA_PREFIX,
+			LinkStateId:       0,
+			AdvertisingRouter: area.ospf.routerId,
+		})
+		return
+	}
+	area.originateLsa(lsa)
+}
+
+// newNetworkPrefixLsa returns the intra-area-prefix-LSA which goes with
+// the network-LSA of the interface as in RFC 5340 4.4.3.9. It describes
+// the prefixes of the link-LSAs of the router and of the neighbors fully
+// adjacent to it, or nil if the router originates no network-LSA.
+func (iface *Interface) newNetworkPrefixLsa() packet.OspfLsa {
+	if iface.attachedRouters() == nil {
+		return nil
+	}
+	prefixes := make([]*packet.Ipv6Prefix, 0)
+	seen := make(map[Ipv6RiKey]bool)
+	add := func(routerId uint32) {
+		for _, key := range iface.lsdb.keys() {
+			if key.LsType != packet.LS_TYPE_LINK || key.AdvertisingRouter != routerId {
+				continue
+			}
+			lsa, ok := iface.lsdb.lsas[key].(*packet.LinkLsa)
+			if !ok || lsa.Header().LsAge >= packet.MAX_AGE {
+				continue
+			}
+			for _, prefix := range lsa.Prefixes {
+				if prefix.PrefixOptions&(packet.PREFIX_OPTIONS_NU|packet.PREFIX_OPTIONS_LA) != 0 {
+					continue
+				}
+				riKey := Ipv6RiKey{maskIpv6(prefix.Prefix, prefix.PrefixLength), prefix.PrefixLength}
+				if seen[riKey] {
+					continue
+				}
+				seen[riKey] = true
+				prefixes = append(prefixes, &packet.Ipv6Prefix{
+					PrefixLength:  prefix.PrefixLength,
+					PrefixOptions: prefix.PrefixOptions,
+					Prefix:        riKey.prefixAddress,
+				})
+			}
+		}
+	}
+	add(iface.ospf.routerId)
+	for _, nbr := range iface.neighborDb {
+		if nbr.state == NEIGHBOR_STATE_FULL {
+			add(nbr.routerId)
+		}
+	}
+	lsa, _ := packet.NewIntraAreaPrefixLsa()
+	lsa.Header().LinkStateId = iface.interfaceId
+	lsa.ReferencedLsType = packet.LS_TYPE_OSPFV3_NETWORK
+	lsa.ReferencedLinkStateId = iface.interfaceId
+	lsa.ReferencedAdvertisingRouter = iface.ospf.routerId
+	lsa.Prefixes = prefixes
+	return lsa
+}
+
+func (iface *Interface) originateNetworkPrefixLsa() {
+	log.Debugf("enter: %s", iface.name)
+	defer log.Debugf("exit: %s", iface.name)
+	lsa := iface.newNetworkPrefixLsa()
+	if lsa == nil {
+		iface.area.flushLsa(packet.LsaKey{
+			LsType:            packet.LS_TYPE_INTRA_AREA_PREFIX,
+			LinkStateId:       iface.interfaceId,
+			AdvertisingRouter: iface.ospf.routerId,
+		})
+		return
+	}
+	iface.area.originateLsa(lsa)
+}
+
+// linkLocalOf returns the link-local address of the interface of routerId
+// on the link, which is the next hop to it, from its link-LSA.
+func (iface *Interface) linkLocalOf(routerId, interfaceId uint32) Address {
+	lsa, ok := iface.lsdb.lookup(packet.LsaKey{
+		LsType:            packet.LS_TYPE_LINK,
+		LinkStateId:       interfaceId,
+		AdvertisingRouter: routerId,
+	}).(*packet.LinkLsa)
+	if ok && lsa.Header().LsAge < packet.MAX_AGE {
+		return Address{Ipv6: lsa.LinkLocalAddress}
+	}
+	for _, nbr := range iface.neighborDb {
+		if nbr.routerId == routerId {
+			return Address{Ipv6: nbr.address6}
+		}
+	}
+	return Address{}
+}
+
+// prefixId returns the link state ID of the inter-area-prefix-LSAs of
+// the prefix of key. The IDs are kept for the prefixes as long as the
+// router runs.
+func (ospf *OspfServer) prefixId(key Ipv6RiKey) uint32 {
+	if id, ok := ospf.prefixIds[key]; ok {
+		return id
+	}
+	id := uint32(len(ospf.prefixIds) + 1)
+	ospf.prefixIds[key] = id
+	return id
+}
+
+// ipv6Range returns the range of the area which contains the prefix, or
+// nil if none does.
+func (area *Area) ipv6Range(prefixAddress [4]uint32, prefixLength uint8) *areaRange {
+	for _, r := range area.ranges() {
+		if prefixLength >= r.prefixLength && maskIpv6(prefixAddress, r.prefixLength) == r.prefixAddress6 {
+			return r
+		}
+	}
+	return nil
+}
+
+// ospfv3Summaries adds the inter-area-prefix-LSAs and the
+// inter-area-router-LSAs the router originates into the area as an area
+// border router as in RFC 5340 4.4.3.4 and 4.4.3.5 to lsas.
+func (ospf *OspfServer) ospfv3Summaries(area *Area, lsas map[packet.LsaKey]packet.OspfLsa) {
+	addSummary := func(key Ipv6RiKey, metric uint32) {
+		lsa, _ := packet.NewInterAreaPrefixLsa()
+		lsa.Header().LinkStateId = ospf.prefixId(key)
+		lsa.Header().AdvertisingRouter = ospf.routerId
+		lsa.Metric = metric
+		lsa.Prefix = packet.Ipv6Prefix{
+			PrefixLength: key.prefixLength,
+			Prefix:       key.prefixAddress,
+		}
+		lsas[lsa.Header().Key()] = lsa
+	}
+	ranges := make(map[Ipv6RiKey]*rangeSummary)
+	for key, ri := range ospf.ipv6RiDb {
+		if ri.routeType != ROUTE_TYPE_INTRA_AREA && ri.routeType != ROUTE_TYPE_INTER_AREA {
+			continue
+		}
+		if ri.areaId == area.areaId || ri.metric >= packet.LS_INFINITY {
+			continue
+		}
+		if origin := ospf.findArea(ri.areaId); origin != nil && ri.routeType == ROUTE_TYPE_INTRA_AREA {
+			if r := origin.ipv6Range(ri.prefixAddress, ri.prefixLength); r != nil {
+				rangeKey := Ipv6RiKey{r.prefixAddress6, r.prefixLength}
+				if summary := ranges[rangeKey]; summary == nil {
+					ranges[rangeKey] = &rangeSummary{r: r, metric: ri.metric}
+				} else if ri.metric > summary.metric {
+					summary.metric = ri.metric
+				}
+				continue
+			}
+		}
+		addSummary(key, ri.metric)
+	}
+	for key, summary := range ranges {
+		if summary.r.advertise {
+			addSummary(key, summary.cost())
+		}
+	}
+	if !area.externalCapable() {
+		return
+	}
+	for _, ri := range ospf.asbrRiDb {
+		if ri.areaId == area.areaId || ri.metric >= packet.LS_INFINITY {
+			continue
+		}
+		lsa, _ := packet.NewInterAreaRouterLsa()
+		lsa.Header().LinkStateId = ri.routerId
+		lsa.Header().AdvertisingRouter = ospf.routerId
+		lsa.Options = area.options()
+		lsa.Metric = ri.metric
+		lsa.DestinationRouterId = ri.routerId
+		lsas[lsa.Header().Key()] = lsa
+	}
+}
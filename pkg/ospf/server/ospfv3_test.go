@@ -0,0 +1,114 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"testing"
+
+	"github.com/m-asama/golsr/internal/pkg/kernel"
+	"github.com/m-asama/golsr/pkg/ospf/packet"
+)
+
+func (router *testRouter) fibRoute6(prefix [4]uint32, prefixLength int) *kernel.Ipv6Route {
+	for _, route := range router.kernel.Ipv6Routes() {
+		if route.Prefix == prefix && route.PrefixLength == prefixLength {
+			return route
+		}
+	}
+	return nil
+}
+
+func TestOspfv3(t *testing.T) {
+	// r1 --(area 0.0.0.1)-- r2 --(backbone)-- r3
+	network := NewMemoryNetwork()
+	r1 := startTestRouter(t, network, "r1", 0x01010101, []*testPort{
+		&testPort{area: "0.0.0.1", name: "lo", ifType: kernel.IF_TYPE_LOOPBACK,
+			address6: [4]uint32{0x20010db8, 0xff, 0, 1}},
+		&testPort{area: "0.0.0.1", segment: "a", name: "eth0", ifType: kernel.IF_TYPE_POINTTOPOINT, priority: 1,
+			address6: [4]uint32{0x20010db8, 1, 0, 1}},
+	})
+	defer r1.stop()
+	r2 := startTestRouter(t, network, "r2", 0x02020202, []*testPort{
+		&testPort{area: "0.0.0.1", segment: "a", name: "eth0", ifType: kernel.IF_TYPE_POINTTOPOINT, priority: 1,
+			address6: [4]uint32{0x20010db8, 1, 0, 2}},
+		&testPort{segment: "b", name: "eth1", ifType: kernel.IF_TYPE_BROADCAST, priority: 1,
+			address6: [4]uint32{0x20010db8, 2, 0, 2}},
+	})
+	defer r2.stop()
+	r3 := startTestRouter(t, network, "r3", 0x03030303, []*testPort{
+		&testPort{segment: "b", name: "eth0", ifType: kernel.IF_TYPE_BROADCAST, priority: 1,
+			address6: [4]uint32{0x20010db8, 2, 0, 3}},
+		&testPort{name: "lo", ifType: kernel.IF_TYPE_LOOPBACK,
+			address6: [4]uint32{0x20010db8, 0xff, 0, 3}},
+	})
+	defer r3.stop()
+
+	// the DR is identified by its router ID
+	waitFor(t, "full adjacencies", func() bool {
+		s2, dr, _ := r2.neighborStates("eth1")
+		s3, _, _ := r3.neighborStates("eth0")
+		return dr == 0x03030303 && s2[0x03030303] == NEIGHBOR_STATE_FULL &&
+			s3[0x02020202] == NEIGHBOR_STATE_FULL
+	})
+	waitFor(t, "routes", func() bool {
+		for _, check := range []struct {
+			router  *testRouter
+			prefix  [4]uint32
+			length  int
+			nexthop uint32
+		}{
+			{r1, [4]uint32{0x20010db8, 0xff, 0, 3}, 128, 0x02020202},
+			{r1, [4]uint32{0x20010db8, 2, 0, 0}, 64, 0x02020202},
+			{r3, [4]uint32{0x20010db8, 0xff, 0, 1}, 128, 0x02020202},
+			{r3, [4]uint32{0x20010db8, 1, 0, 0}, 64, 0x02020202},
+			{r2, [4]uint32{0x20010db8, 0xff, 0, 1}, 128, 0x01010101},
+			{r2, [4]uint32{0x20010db8, 0xff, 0, 3}, 128, 0x03030303},
+		} {
+			// the next hops are the link-local addresses
+			route := check.router.fibRoute6(check.prefix, check.length)
+			if route == nil || len(route.NextHops) != 1 ||
+				route.NextHops[0].Address != [4]uint32{0xfe800000, 0, 0, check.nexthop} {
+				return false
+			}
+		}
+		return true
+	})
+
+	r3.ospf.lock.RLock()
+	ri := r3.ospf.ipv6RiDb[Ipv6RiKey{[4]uint32{0x20010db8, 0xff, 0, 1}, 128}]
+	lsa := r3.ospf.findArea(0).lookupLsa(packet.LsaKey{
+		LsType:            packet.LS_TYPE_INTRA_AREA_PREFIX,
+		LinkStateId:       2,
+		AdvertisingRouter: 0x03030303,
+	})
+	r3.ospf.lock.RUnlock()
+	if ri == nil || ri.routeType != ROUTE_TYPE_INTER_AREA || ri.metric != 20 {
+		t.Fatalf("failed inter-area route: %v", ri)
+	}
+	// the prefix of the transit network is advertised by the DR with the
+	// interface ID, the ifindex, of its interface
+	if lsa == nil || len(lsa.(*packet.IntraAreaPrefixLsa).Prefixes) != 1 {
+		t.Fatalf("failed intra-area-prefix-LSA of the network: %v", lsa)
+	}
+
+	network.Disconnect("r2", "eth1")
+	waitFor(t, "routes withdrawn", func() bool {
+		return r1.fibRoute6([4]uint32{0x20010db8, 0xff, 0, 3}, 128) == nil &&
+			r3.fibRoute6([4]uint32{0x20010db8, 0xff, 0, 1}, 128) == nil
+	})
+}
@@ -35,6 +35,19 @@ func (ospf *OspfServer) periodic(doneCh chan struct{}) {
 			goto EXIT
 		case <-timer.C:
 			counter++
+			ospf.lock.Lock()
+			for _, iface := range ospf.interfaceDb {
+				iface.tick()
+			}
+			ospf.ageLsdb()
+			ospf.stubRouterTick()
+			if ospf.spfRequired {
+				ospf.spfRequired = false
+				ospf.decisionChSend(&DecisionChMsg{
+					msgType: DECISION_CH_MSG_TYPE_DO,
+				})
+			}
+			ospf.lock.Unlock()
 			timer.Reset(started.Add(time.Second * counter).Sub(time.Now()))
 		}
 	}
@@ -0,0 +1,266 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/binary"
+	"errors"
+	"syscall"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/m-asama/golsr/internal/pkg/kernel"
+	"github.com/m-asama/golsr/pkg/ospf/packet"
+)
+
+func ipv4Bytes(addr uint32) [4]byte {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[0:4], addr)
+	return b
+}
+
+func ospfSocket(iface *kernel.Interface) (int, error) {
+	var err error
+
+	if iface == nil {
+		s := "iface == nil"
+		log.Info(s)
+		return -1, errors.New(s)
+	}
+
+	fd, err := syscall.Socket(syscall.AF_INET, syscall.SOCK_RAW, packet.IP_PROTOCOL_OSPF)
+	if err != nil {
+		s := "syscall.Socket failed"
+		log.Info(s)
+		return -1, errors.New(s)
+	}
+
+	err = syscall.SetsockoptString(fd, syscall.SOL_SOCKET, syscall.SO_BINDTODEVICE, iface.Name)
+	if err != nil {
+		syscall.Close(fd)
+		s := "SetsockoptString(SO_BINDTODEVICE) failed"
+		log.Info(s)
+		return -1, errors.New(s)
+	}
+
+	mreqn := syscall.IPMreqn{
+		Ifindex: int32(iface.IfIndex),
+	}
+	err = syscall.SetsockoptIPMreqn(fd, syscall.IPPROTO_IP, syscall.IP_MULTICAST_IF, &mreqn)
+	if err != nil {
+		syscall.Close(fd)
+		s := "SetsockoptIPMreqn(IP_MULTICAST_IF) failed"
+		log.Info(s)
+		return -1, errors.New(s)
+	}
+
+	opts := []struct {
+		opt   int
+		value int
+	}{
+		{syscall.IP_MULTICAST_LOOP, 0},
+		{syscall.IP_MULTICAST_TTL, 1},
+		{syscall.IP_TOS, 0xc0},
+	}
+	for _, opt := range opts {
+		err = syscall.SetsockoptInt(fd, syscall.IPPROTO_IP, opt.opt, opt.value)
+		if err != nil {
+			syscall.Close(fd)
+			s := "SetsockoptInt failed"
+			log.Info(s)
+			return -1, errors.New(s)
+		}
+	}
+
+	err = ospfSocketMembership(fd, iface.IfIndex, packet.ALL_SPF_ROUTERS, true)
+	if err != nil {
+		syscall.Close(fd)
+		return -1, err
+	}
+
+	return fd, nil
+}
+
+// ospfSocketTtlSecurity makes the socket send with TTL 255, or the
+// defaults if not enabled, and the kernel drop the packets of a TTL below
+// minTtl, none if 0, as in RFC 5082.
+func ospfSocketTtlSecurity(fd int, enable bool, minTtl uint8) error {
+	ttl, multicastTtl := -1, 1
+	if enable {
+		ttl, multicastTtl = 255, 255
+	}
+	opts := []struct {
+		opt   int
+		value int
+	}{
+		{syscall.IP_TTL, ttl},
+		{syscall.IP_MULTICAST_TTL, multicastTtl},
+		{syscall.IP_MINTTL, int(minTtl)},
+	}
+	for _, opt := range opts {
+		err := syscall.SetsockoptInt(fd, syscall.IPPROTO_IP, opt.opt, opt.value)
+		if err != nil {
+			s := "SetsockoptInt failed"
+			log.Info(s)
+			return errors.New(s)
+		}
+	}
+	return nil
+}
+
+func ospfSocketMembership(fd int, ifIndex int, group uint32, join bool) error {
+	mreqn := syscall.IPMreqn{
+		Multiaddr: ipv4Bytes(group),
+		Ifindex:   int32(ifIndex),
+	}
+	opt := syscall.IP_ADD_MEMBERSHIP
+	if !join {
+		opt = syscall.IP_DROP_MEMBERSHIP
+	}
+	err := syscall.SetsockoptIPMreqn(fd, syscall.IPPROTO_IP, opt, &mreqn)
+	if err != nil {
+		s := "SetsockoptIPMreqn(IP_ADD_MEMBERSHIP) failed"
+		if !join {
+			s = "SetsockoptIPMreqn(IP_DROP_MEMBERSHIP) failed"
+		}
+		log.Info(s)
+		return errors.New(s)
+	}
+	return nil
+}
+
+func ipv6Bytes(addr [4]uint32) [16]byte {
+	var b [16]byte
+	for i, a := range addr {
+		binary.BigEndian.PutUint32(b[4*i:4*i+4], a)
+	}
+	return b
+}
+
+func ipv6Uint32s(b [16]byte) [4]uint32 {
+	var addr [4]uint32
+	for i := range addr {
+		addr[i] = binary.BigEndian.Uint32(b[4*i : 4*i+4])
+	}
+	return addr
+}
+
+func ospfv3Socket(iface *kernel.Interface) (int, error) {
+	var err error
+
+	if iface == nil {
+		s := "iface == nil"
+		log.Info(s)
+		return -1, errors.New(s)
+	}
+
+	fd, err := syscall.Socket(syscall.AF_INET6, syscall.SOCK_RAW, packet.IP_PROTOCOL_OSPF)
+	if err != nil {
+		s := "syscall.Socket failed"
+		log.Info(s)
+		return -1, errors.New(s)
+	}
+
+	err = syscall.SetsockoptString(fd, syscall.SOL_SOCKET, syscall.SO_BINDTODEVICE, iface.Name)
+	if err != nil {
+		syscall.Close(fd)
+		s := "SetsockoptString(SO_BINDTODEVICE) failed"
+		log.Info(s)
+		return -1, errors.New(s)
+	}
+
+	// the checksum is at offset 12 of the OSPFv3 header
+	opts := []struct {
+		opt   int
+		value int
+	}{
+		{syscall.IPV6_CHECKSUM, 12},
+		{syscall.IPV6_MULTICAST_IF, iface.IfIndex},
+		{syscall.IPV6_MULTICAST_LOOP, 0},
+		{syscall.IPV6_MULTICAST_HOPS, 1},
+		{syscall.IPV6_TCLASS, 0xc0},
+		{syscall.IPV6_RECVPKTINFO, 1},
+		{syscall.IPV6_RECVHOPLIMIT, 1},
+	}
+	for _, opt := range opts {
+		err = syscall.SetsockoptInt(fd, syscall.IPPROTO_IPV6, opt.opt, opt.value)
+		if err != nil {
+			syscall.Close(fd)
+			s := "SetsockoptInt failed"
+			log.Info(s)
+			return -1, errors.New(s)
+		}
+	}
+
+	err = ospfv3SocketMembership(fd, iface.IfIndex, packet.ALL_SPF_ROUTERS_IPV6, true)
+	if err != nil {
+		syscall.Close(fd)
+		return -1, err
+	}
+
+	return fd, nil
+}
+
+// IPV6_MINHOPCOUNT is the IPv6 counterpart of IP_MINTTL, which the
+// syscall package lacks.
+const IPV6_MINHOPCOUNT = 0x49
+
+func ospfv3SocketTtlSecurity(fd int, enable bool, minTtl uint8) error {
+	hops, multicastHops := -1, 1
+	if enable {
+		hops, multicastHops = 255, 255
+	}
+	opts := []struct {
+		opt   int
+		value int
+	}{
+		{syscall.IPV6_UNICAST_HOPS, hops},
+		{syscall.IPV6_MULTICAST_HOPS, multicastHops},
+		{IPV6_MINHOPCOUNT, int(minTtl)},
+	}
+	for _, opt := range opts {
+		err := syscall.SetsockoptInt(fd, syscall.IPPROTO_IPV6, opt.opt, opt.value)
+		if err != nil {
+			s := "SetsockoptInt failed"
+			log.Info(s)
+			return errors.New(s)
+		}
+	}
+	return nil
+}
+
+func ospfv3SocketMembership(fd int, ifIndex int, group [4]uint32, join bool) error {
+	mreq := syscall.IPv6Mreq{
+		Multiaddr: ipv6Bytes(group),
+		Interface: uint32(ifIndex),
+	}
+	opt := syscall.IPV6_JOIN_GROUP
+	if !join {
+		opt = syscall.IPV6_LEAVE_GROUP
+	}
+	err := syscall.SetsockoptIPv6Mreq(fd, syscall.IPPROTO_IPV6, opt, &mreq)
+	if err != nil {
+		s := "SetsockoptIPv6Mreq(IPV6_JOIN_GROUP) failed"
+		if !join {
+			s = "SetsockoptIPv6Mreq(IPV6_LEAVE_GROUP) failed"
+		}
+		log.Info(s)
+		return errors.New(s)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	log "github.com/sirupsen/logrus"
+
+	"github.com/m-asama/golsr/pkg/ospf/packet"
+)
+
+// stubRouter is the state of the stub router advertisement of RFC 6987,
+// in which the router-LSAs advertise the links to the other routers with
+// MaxLinkMetric so that no transit traffic goes through the router while
+// another path exists.
+type stubRouter struct {
+	// onDemand is set and cleared through the API.
+	onDemand bool
+	// started is set once the router is ready and startup counts down
+	// the seconds left of the on-startup interval.
+	started bool
+	startup uint32
+	// active tells the router-LSAs originated last are of a stub
+	// router.
+	active bool
+}
+
+// maxMetric tells whether the router advertises itself as a stub router:
+// always, on demand, or on startup until the interval expires or the
+// condition is met.
+func (ospf *OspfServer) maxMetric() bool {
+	return *ospf.config.StubRouter.Config.Always || ospf.stub.onDemand || ospf.stub.startup > 0
+}
+
+// adjacenciesFull tells whether the router has formed adjacencies and
+// none is still being formed with the neighbors it has heard of.
+func (ospf *OspfServer) adjacenciesFull() bool {
+	full := false
+	for _, iface := range ospf.interfaceDb {
+		for _, nbr := range iface.neighborDb {
+			switch nbr.state {
+			case NEIGHBOR_STATE_INIT, NEIGHBOR_STATE_EXSTART, NEIGHBOR_STATE_EXCHANGE, NEIGHBOR_STATE_LOADING:
+				return false
+			case NEIGHBOR_STATE_TWO_WAY:
+				if nbr.adjOk() {
+					return false
+				}
+			case NEIGHBOR_STATE_FULL:
+				full = true
+			}
+		}
+	}
+	return full
+}
+
+// updateStubRouter starts the on-startup interval once the router is
+// ready and re-originates the router-LSAs when the router becomes or
+// ceases to be a stub router.
+func (ospf *OspfServer) updateStubRouter() {
+	if !ospf.stub.started && ospf.ready() {
+		ospf.stub.started = true
+		if onStartup := ospf.config.StubRouter.Config.OnStartup; onStartup != nil {
+			ospf.stub.startup = *onStartup
+		}
+	}
+	if ospf.maxMetric() == ospf.stub.active {
+		return
+	}
+	ospf.stub.active = ospf.maxMetric()
+	log.WithFields(log.Fields{
+		"Topic":  "StubRouter",
+		"Active": ospf.stub.active,
+	}).Info("Stub router changed")
+	for _, area := range ospf.areaDb {
+		area.originateRouterLsa()
+	}
+}
+
+// stubRouterTick counts down the on-startup interval, which ends early
+// once the condition of wait-for is met.
+func (ospf *OspfServer) stubRouterTick() {
+	if ospf.stub.startup > 0 {
+		ospf.stub.startup--
+		if waitFor := ospf.config.StubRouter.Config.WaitFor; waitFor != nil &&
+			*waitFor == "adjacencies" && ospf.adjacenciesFull() {
+			ospf.stub.startup = 0
+		}
+	}
+	ospf.updateStubRouter()
+}
+
+func (ospf *OspfServer) stubRouterOnDemand() bool {
+	ospf.lock.RLock()
+	defer ospf.lock.RUnlock()
+	return ospf.stub.onDemand
+}
+
+// SetStubRouter makes the router a stub router, or not, on demand.
+func (ospf *OspfServer) SetStubRouter(enable bool) {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	ospf.lock.Lock()
+	defer ospf.lock.Unlock()
+	ospf.stub.onDemand = enable
+	ospf.updateStubRouter()
+}
+
+// maxLinkMetric sets the metrics of the links of a stub router to the
+// other routers to MaxLinkMetric as in RFC 6987 2. The stub links of
+// OSPFv2 keep theirs.
+func maxLinkMetric(lsa packet.OspfLsa) {
+	switch lsa := lsa.(type) {
+	case *packet.RouterLsa:
+		for _, link := range lsa.Links {
+			if link.Type != packet.LINK_TYPE_STUB {
+				link.Metric = MAX_LINK_METRIC
+			}
+		}
+	case *packet.Ospfv3RouterLsa:
+		for _, link := range lsa.Links {
+			link.Metric = MAX_LINK_METRIC
+		}
+	}
+}
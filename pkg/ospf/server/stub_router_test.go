@@ -0,0 +1,84 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"testing"
+
+	"github.com/m-asama/golsr/internal/pkg/kernel"
+	"github.com/m-asama/golsr/pkg/ospf/packet"
+)
+
+func TestStubRouter(t *testing.T) {
+	// r1 reaches the loopback of r3 through r2 unless r2 is a stub router
+	// r1 --(a)-- r2 --(b)-- r3
+	//  \----------(c)------/
+	network := NewMemoryNetwork()
+	r1 := startTestRouter(t, network, "r1", 0x01010101, []*testPort{
+		&testPort{segment: "a", name: "eth0", address: 0x0a000101, ifType: kernel.IF_TYPE_POINTTOPOINT, priority: 1},
+		&testPort{segment: "c", name: "eth1", address: 0x0a000301, ifType: kernel.IF_TYPE_POINTTOPOINT, priority: 1,
+			ifConfig: "      cost = 30\n"},
+	})
+	defer r1.stop()
+	r2 := startTestRouter(t, network, "r2", 0x02020202, []*testPort{
+		&testPort{segment: "a", name: "eth0", address: 0x0a000102, ifType: kernel.IF_TYPE_POINTTOPOINT, priority: 1,
+			globalConfig: "[stub-router]\n" +
+				"  [stub-router.config]\n" +
+				"    on-startup = 600\n" +
+				"    wait-for = \"adjacencies\"\n"},
+		&testPort{segment: "b", name: "eth1", address: 0x0a000202, ifType: kernel.IF_TYPE_POINTTOPOINT, priority: 1},
+	})
+	defer r2.stop()
+	r3 := startTestRouter(t, network, "r3", 0x03030303, []*testPort{
+		&testPort{name: "lo", address: 0x03030303, ifType: kernel.IF_TYPE_LOOPBACK},
+		&testPort{segment: "b", name: "eth0", address: 0x0a000203, ifType: kernel.IF_TYPE_POINTTOPOINT, priority: 1},
+		&testPort{segment: "c", name: "eth1", address: 0x0a000303, ifType: kernel.IF_TYPE_POINTTOPOINT, priority: 1},
+	})
+	defer r3.stop()
+
+	nexthop := func(nexthop uint32) func() bool {
+		return func() bool {
+			route := r1.fibRoute(0x03030303, 32)
+			return route != nil && len(route.NextHops) == 1 && route.NextHops[0].Address == nexthop
+		}
+	}
+
+	// r2 ceases to be a stub router once its adjacencies are formed
+	// long before on-startup expires
+	waitFor(t, "route through r2", nexthop(0x0a000102))
+	r2.ospf.lock.RLock()
+	startup, active := r2.ospf.stub.startup, r2.ospf.stub.active
+	r2.ospf.lock.RUnlock()
+	if startup != 0 || active {
+		t.Fatalf("failed on-startup: %d %v", startup, active)
+	}
+
+	// traffic is drained from r2 on demand and comes back
+	r2.ospf.SetStubRouter(true)
+	waitFor(t, "route around r2", nexthop(0x0a000303))
+	r2.ospf.lock.RLock()
+	for _, link := range r2.ospf.findArea(0).newRouterLsa().(*packet.RouterLsa).Links {
+		if (link.Type == packet.LINK_TYPE_STUB) == (link.Metric == MAX_LINK_METRIC) {
+			r2.ospf.lock.RUnlock()
+			t.Fatalf("failed link metric: %v", link)
+		}
+	}
+	r2.ospf.lock.RUnlock()
+	r2.ospf.SetStubRouter(false)
+	waitFor(t, "route through r2 again", nexthop(0x0a000102))
+}
@@ -0,0 +1,427 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/binary"
+	"errors"
+	"sync"
+	"syscall"
+
+	"github.com/m-asama/golsr/internal/pkg/kernel"
+	"github.com/m-asama/golsr/pkg/ospf/packet"
+)
+
+// Address is an IPv4 address of OSPFv2 or an IPv6 address of OSPFv3, in
+// host byte order.
+type Address struct {
+	Ipv4 uint32
+	Ipv6 [4]uint32
+}
+
+func ipv4Address(address uint32) Address {
+	return Address{Ipv4: address}
+}
+
+func ipv6Address(address [4]uint32) Address {
+	return Address{Ipv6: address}
+}
+
+func (address Address) isZero() bool {
+	return address == Address{}
+}
+
+func (address Address) String() string {
+	if address.Ipv6 != [4]uint32{} {
+		return packet.Ipv6String(address.Ipv6)
+	}
+	return packet.Ipv4String(address.Ipv4)
+}
+
+// allSpfRouters and allDRouters are the groups of the version.
+func allSpfRouters(version uint8) Address {
+	if version == packet.OSPFV3_VERSION {
+		return ipv6Address(packet.ALL_SPF_ROUTERS_IPV6)
+	}
+	return ipv4Address(packet.ALL_SPF_ROUTERS)
+}
+
+func allDRouters(version uint8) Address {
+	if version == packet.OSPFV3_VERSION {
+		return ipv6Address(packet.ALL_D_ROUTERS_IPV6)
+	}
+	return ipv4Address(packet.ALL_D_ROUTERS)
+}
+
+// PacketInfo is what the IP header of a received packet tells.
+type PacketInfo struct {
+	Src Address
+	Dst Address
+	Ttl uint8
+}
+
+// Transport sends and receives the OSPF packets of an interface, without
+// the IP header.
+type Transport interface {
+	Send(data []byte, dst Address) error
+	// Recv blocks until a packet arrives and returns its length and the
+	// addresses it was sent from and to. It fails once the transport is
+	// closed.
+	Recv(buf []byte) (int, *PacketInfo, error)
+	JoinGroup(group Address) error
+	LeaveGroup(group Address) error
+	// SetTtlSecurity makes the transport send with TTL 255, or the
+	// default TTLs if not enabled, and drop the packets of a TTL below
+	// minTtl where it can, none if 0.
+	SetTtlSecurity(enable bool, minTtl uint8) error
+	Close() error
+}
+
+// TransportFactory opens the transport of the interface iface for the
+// OSPF version, 2 over IPv4 or 3 over IPv6.
+type TransportFactory func(iface *kernel.Interface, version uint8) (Transport, error)
+
+type rawTransport struct {
+	fd      int
+	ifIndex int
+	buf     []byte
+}
+
+// NewRawTransport opens a raw IP socket of protocol 89 bound to iface and
+// a member of AllSPFRouters. This is the transport the daemon uses.
+func NewRawTransport(iface *kernel.Interface, version uint8) (Transport, error) {
+	if version == packet.OSPFV3_VERSION {
+		return newRawIpv6Transport(iface)
+	}
+	fd, err := ospfSocket(iface)
+	if err != nil {
+		return nil, err
+	}
+	return &rawTransport{
+		fd:      fd,
+		ifIndex: iface.IfIndex,
+		buf:     make([]byte, OSPF_PACKET_BUFFER_LENGTH),
+	}, nil
+}
+
+func (transport *rawTransport) Send(data []byte, dst Address) error {
+	dstaddr := syscall.SockaddrInet4{
+		Addr: ipv4Bytes(dst.Ipv4),
+	}
+	return syscall.Sendto(transport.fd, data, 0, &dstaddr)
+}
+
+func (transport *rawTransport) Recv(buf []byte) (int, *PacketInfo, error) {
+	n, _, err := syscall.Recvfrom(transport.fd, transport.buf, 0)
+	if err != nil {
+		return 0, nil, err
+	}
+	if n < IPV4_HEADER_LENGTH {
+		return 0, nil, errors.New("rawTransport.Recv: packet too short")
+	}
+	ihl := int(transport.buf[0]&0x0f) * 4
+	if n < ihl {
+		return 0, nil, errors.New("rawTransport.Recv: packet too short")
+	}
+	info := &PacketInfo{
+		Src: ipv4Address(binary.BigEndian.Uint32(transport.buf[12:16])),
+		Dst: ipv4Address(binary.BigEndian.Uint32(transport.buf[16:20])),
+		Ttl: transport.buf[8],
+	}
+	return copy(buf, transport.buf[ihl:n]), info, nil
+}
+
+func (transport *rawTransport) JoinGroup(group Address) error {
+	return ospfSocketMembership(transport.fd, transport.ifIndex, group.Ipv4, true)
+}
+
+func (transport *rawTransport) LeaveGroup(group Address) error {
+	return ospfSocketMembership(transport.fd, transport.ifIndex, group.Ipv4, false)
+}
+
+func (transport *rawTransport) SetTtlSecurity(enable bool, minTtl uint8) error {
+	return ospfSocketTtlSecurity(transport.fd, enable, minTtl)
+}
+
+func (transport *rawTransport) Close() error {
+	return syscall.Close(transport.fd)
+}
+
+// rawIpv6Transport is the transport of OSPFv3. The kernel computes the
+// checksum and tells the destination and the hop limit of a received
+// packet in ancillary data, as there is no IPv6 header to look at.
+type rawIpv6Transport struct {
+	fd      int
+	ifIndex int
+	buf     []byte
+	oob     []byte
+}
+
+func newRawIpv6Transport(iface *kernel.Interface) (Transport, error) {
+	fd, err := ospfv3Socket(iface)
+	if err != nil {
+		return nil, err
+	}
+	return &rawIpv6Transport{
+		fd:      fd,
+		ifIndex: iface.IfIndex,
+		buf:     make([]byte, OSPF_PACKET_BUFFER_LENGTH),
+		oob:     make([]byte, syscall.CmsgSpace(IPV6_PKTINFO_LENGTH)+syscall.CmsgSpace(4)),
+	}, nil
+}
+
+func (transport *rawIpv6Transport) Send(data []byte, dst Address) error {
+	dstaddr := syscall.SockaddrInet6{
+		Addr:   ipv6Bytes(dst.Ipv6),
+		ZoneId: uint32(transport.ifIndex),
+	}
+	return syscall.Sendto(transport.fd, data, 0, &dstaddr)
+}
+
+func (transport *rawIpv6Transport) Recv(buf []byte) (int, *PacketInfo, error) {
+	n, oobn, _, from, err := syscall.Recvmsg(transport.fd, transport.buf, transport.oob, 0)
+	if err != nil {
+		return 0, nil, err
+	}
+	info := &PacketInfo{}
+	if sa, ok := from.(*syscall.SockaddrInet6); ok {
+		info.Src = ipv6Address(ipv6Uint32s(sa.Addr))
+	}
+	cmsgs, err := syscall.ParseSocketControlMessage(transport.oob[:oobn])
+	if err != nil {
+		return 0, nil, err
+	}
+	for _, cmsg := range cmsgs {
+		if cmsg.Header.Level != syscall.IPPROTO_IPV6 {
+			continue
+		}
+		switch {
+		case cmsg.Header.Type == syscall.IPV6_PKTINFO && len(cmsg.Data) >= IPV6_PKTINFO_LENGTH:
+			var addr [16]byte
+			copy(addr[:], cmsg.Data[0:16])
+			info.Dst = ipv6Address(ipv6Uint32s(addr))
+		case cmsg.Header.Type == syscall.IPV6_HOPLIMIT && len(cmsg.Data) >= 4:
+			info.Ttl = uint8(binary.LittleEndian.Uint32(cmsg.Data[0:4]))
+		}
+	}
+	return copy(buf, transport.buf[:n]), info, nil
+}
+
+func (transport *rawIpv6Transport) JoinGroup(group Address) error {
+	return ospfv3SocketMembership(transport.fd, transport.ifIndex, group.Ipv6, true)
+}
+
+func (transport *rawIpv6Transport) LeaveGroup(group Address) error {
+	return ospfv3SocketMembership(transport.fd, transport.ifIndex, group.Ipv6, false)
+}
+
+func (transport *rawIpv6Transport) SetTtlSecurity(enable bool, minTtl uint8) error {
+	return ospfv3SocketTtlSecurity(transport.fd, enable, minTtl)
+}
+
+func (transport *rawIpv6Transport) Close() error {
+	return syscall.Close(transport.fd)
+}
+
+type memoryPacket struct {
+	data []byte
+	info *PacketInfo
+}
+
+type memoryEndpoint struct {
+	network *MemoryNetwork
+	router  string
+	name    string
+	addr    Address
+	ttl     uint8
+	groups  map[Address]bool
+	recvCh  chan *memoryPacket
+	closeCh chan struct{}
+	once    sync.Once
+}
+
+// MemoryNetwork connects interfaces of OspfServer instances in the same
+// process. Interfaces are attached to named segments; a packet sent to a
+// group is delivered to every other interface on the segment which is a
+// member of it and a packet sent to an address to the interface which has
+// it. OSPFv3 interfaces are addressed by their link-local addresses. Like
+// a real link a packet is dropped when the receiver is not keeping up.
+// Packets sent to a group are not delivered on a non-broadcast segment.
+type MemoryNetwork struct {
+	lock         sync.RWMutex
+	segments     map[string]string
+	endpoints    map[string]*memoryEndpoint
+	nonBroadcast map[string]bool
+}
+
+const MEMORY_TRANSPORT_QUEUE_LENGTH = 256
+
+func NewMemoryNetwork() *MemoryNetwork {
+	return &MemoryNetwork{
+		segments:     make(map[string]string),
+		endpoints:    make(map[string]*memoryEndpoint),
+		nonBroadcast: make(map[string]bool),
+	}
+}
+
+func memoryEndpointKey(router, name string) string {
+	return router + "/" + name
+}
+
+// Connect attaches interface name of router to segment.
+func (network *MemoryNetwork) Connect(segment, router, name string) {
+	network.lock.Lock()
+	defer network.lock.Unlock()
+	network.segments[memoryEndpointKey(router, name)] = segment
+}
+
+// SetNonBroadcast makes segment a non-broadcast one.
+func (network *MemoryNetwork) SetNonBroadcast(segment string) {
+	network.lock.Lock()
+	defer network.lock.Unlock()
+	network.nonBroadcast[segment] = true
+}
+
+// Disconnect detaches interface name of router from its segment.
+func (network *MemoryNetwork) Disconnect(router, name string) {
+	network.lock.Lock()
+	defer network.lock.Unlock()
+	delete(network.segments, memoryEndpointKey(router, name))
+}
+
+// TransportFactory returns the factory to give to OspfServer.SetTransport
+// of router.
+func (network *MemoryNetwork) TransportFactory(router string) TransportFactory {
+	return func(iface *kernel.Interface, version uint8) (Transport, error) {
+		if iface == nil {
+			return nil, errors.New("MemoryNetwork.TransportFactory: iface == nil")
+		}
+		key := memoryEndpointKey(router, iface.Name)
+		endpoint := &memoryEndpoint{
+			network: network,
+			router:  router,
+			name:    iface.Name,
+			ttl:     1,
+			groups:  map[Address]bool{allSpfRouters(version): true},
+			recvCh:  make(chan *memoryPacket, MEMORY_TRANSPORT_QUEUE_LENGTH),
+			closeCh: make(chan struct{}),
+		}
+		if version == packet.OSPFV3_VERSION {
+			for _, addr := range iface.Ipv6Addresses {
+				if addr.ScopeLink {
+					endpoint.addr = ipv6Address(addr.Address)
+					break
+				}
+			}
+		} else {
+			for _, addr := range iface.Ipv4Addresses {
+				if !addr.ScopeHost {
+					endpoint.addr = ipv4Address(addr.Address)
+					break
+				}
+			}
+		}
+		network.lock.Lock()
+		defer network.lock.Unlock()
+		if _, ok := network.endpoints[key]; ok {
+			return nil, errors.New("MemoryNetwork.TransportFactory: " + key + " already opened")
+		}
+		network.endpoints[key] = endpoint
+		return endpoint, nil
+	}
+}
+
+func (endpoint *memoryEndpoint) key() string {
+	return memoryEndpointKey(endpoint.router, endpoint.name)
+}
+
+func (endpoint *memoryEndpoint) Send(data []byte, dst Address) error {
+	network := endpoint.network
+	network.lock.RLock()
+	defer network.lock.RUnlock()
+	segment, ok := network.segments[endpoint.key()]
+	if !ok {
+		return nil
+	}
+	for key, peer := range network.endpoints {
+		if peer == endpoint || network.segments[key] != segment {
+			continue
+		}
+		if (network.nonBroadcast[segment] || !peer.groups[dst]) && peer.addr != dst {
+			continue
+		}
+		p := &memoryPacket{
+			data: append([]byte{}, data...),
+			info: &PacketInfo{
+				Src: endpoint.addr,
+				Dst: dst,
+				Ttl: endpoint.ttl,
+			},
+		}
+		select {
+		case peer.recvCh <- p:
+		default:
+		}
+	}
+	return nil
+}
+
+func (endpoint *memoryEndpoint) Recv(buf []byte) (int, *PacketInfo, error) {
+	select {
+	case p := <-endpoint.recvCh:
+		return copy(buf, p.data), p.info, nil
+	case <-endpoint.closeCh:
+		return 0, nil, errors.New("memoryEndpoint.Recv: closed")
+	}
+}
+
+func (endpoint *memoryEndpoint) JoinGroup(group Address) error {
+	endpoint.network.lock.Lock()
+	defer endpoint.network.lock.Unlock()
+	endpoint.groups[group] = true
+	return nil
+}
+
+func (endpoint *memoryEndpoint) LeaveGroup(group Address) error {
+	endpoint.network.lock.Lock()
+	defer endpoint.network.lock.Unlock()
+	delete(endpoint.groups, group)
+	return nil
+}
+
+// SetTtlSecurity sets the TTL of the packets sent. Packets are delivered
+// whatever their TTLs, which the interfaces check.
+func (endpoint *memoryEndpoint) SetTtlSecurity(enable bool, minTtl uint8) error {
+	endpoint.network.lock.Lock()
+	defer endpoint.network.lock.Unlock()
+	endpoint.ttl = 1
+	if enable {
+		endpoint.ttl = 255
+	}
+	return nil
+}
+
+func (endpoint *memoryEndpoint) Close() error {
+	endpoint.once.Do(func() {
+		endpoint.network.lock.Lock()
+		delete(endpoint.network.endpoints, endpoint.key())
+		endpoint.network.lock.Unlock()
+		close(endpoint.closeCh)
+	})
+	return nil
+}
@@ -0,0 +1,82 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	log "github.com/sirupsen/logrus"
+)
+
+// ttlSecurity tells whether the GTSM of RFC 5082 is enabled on the
+// interface.
+func (iface *Interface) ttlSecurity() bool {
+	return *iface.ifConfig.TtlSecurity.Config.Enable
+}
+
+// minTtl is the lowest TTL of the packets accepted on the interface, 0
+// without TTL security.
+func (iface *Interface) minTtl() uint8 {
+	if !iface.ttlSecurity() {
+		return 0
+	}
+	return 255 - *iface.ifConfig.TtlSecurity.Config.Hops
+}
+
+// updateTtlSecurity sets the TTL security of the transport of the
+// interface. The packets of the virtual links through its area go through
+// the transport too: it sends with TTL 255 if any of them has TTL
+// security, and drops the packets of a TTL lower than any of them
+// accepts.
+func (iface *Interface) updateTtlSecurity() {
+	if iface.transport == nil || iface.virtualLink != nil {
+		return
+	}
+	enable, minTtl := iface.ttlSecurity(), iface.minTtl()
+	for _, vlink := range iface.ospf.interfaceDb {
+		if vlink.virtualLink == nil || vlink.virtualLink.transitAreaId != iface.area.areaId {
+			continue
+		}
+		enable = enable || vlink.ttlSecurity()
+		if vlink.minTtl() < minTtl {
+			minTtl = vlink.minTtl()
+		}
+	}
+	err := iface.transport.SetTtlSecurity(enable, minTtl)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"Topic":     "Interface",
+			"Interface": iface.name,
+			"Error":     err,
+		}).Warn("Can't set TTL security")
+	}
+}
+
+// ttlValid checks the TTL of a packet received on the interface. The
+// packets dropped are counted.
+func (iface *Interface) ttlValid(info *PacketInfo) bool {
+	if info.Ttl >= iface.minTtl() {
+		return true
+	}
+	iface.ttlSecurityDrops++
+	log.WithFields(log.Fields{
+		"Topic":     "Interface",
+		"Interface": iface.name,
+		"Source":    info.Src.String(),
+		"Ttl":       info.Ttl,
+	}).Debug("TTL security check failed")
+	return false
+}
@@ -0,0 +1,73 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"testing"
+
+	"github.com/m-asama/golsr/internal/pkg/kernel"
+)
+
+const ttlSecurityConfig = "    [areas.interfaces.ttl-security.config]\n" +
+	"      enable = true\n"
+
+func TestTtlSecurity(t *testing.T) {
+	// r3 sends with TTL 1 and r1 drops its packets
+	network := NewMemoryNetwork()
+	r1 := startTestRouter(t, network, "r1", 0x01010101, []*testPort{
+		&testPort{segment: "a", name: "eth0", address: 0x0a000101, ifType: kernel.IF_TYPE_POINTTOPOINT, priority: 1,
+			ifConfig: ttlSecurityConfig},
+		&testPort{segment: "b", name: "eth1", address: 0x0a000201, ifType: kernel.IF_TYPE_POINTTOPOINT, priority: 1,
+			ifConfig: ttlSecurityConfig},
+	})
+	defer r1.stop()
+	r2 := startTestRouter(t, network, "r2", 0x02020202, []*testPort{
+		&testPort{segment: "a", name: "eth0", address: 0x0a000102, ifType: kernel.IF_TYPE_POINTTOPOINT, priority: 1,
+			ifConfig: ttlSecurityConfig},
+	})
+	defer r2.stop()
+	r3 := startTestRouter(t, network, "r3", 0x03030303, []*testPort{
+		&testPort{segment: "b", name: "eth0", address: 0x0a000203, ifType: kernel.IF_TYPE_POINTTOPOINT, priority: 1},
+	})
+	defer r3.stop()
+
+	waitFor(t, "full adjacency with TTL security", func() bool {
+		s1, _, _ := r1.neighborStates("eth0")
+		s2, _, _ := r2.neighborStates("eth0")
+		return s1[0x02020202] == NEIGHBOR_STATE_FULL && s2[0x01010101] == NEIGHBOR_STATE_FULL
+	})
+	waitFor(t, "packets dropped", func() bool {
+		r1.ospf.lock.RLock()
+		defer r1.ospf.lock.RUnlock()
+		return r1.ospf.findInterface("eth1").ttlSecurityDrops >= 3
+	})
+	r1.ospf.lock.RLock()
+	eth0Drops := r1.ospf.findInterface("eth0").ttlSecurityDrops
+	eth1 := newApiInterface(r1.ospf.findInterface("eth1"))
+	r1.ospf.lock.RUnlock()
+	if eth0Drops != 0 || !eth1.TtlSecurity || eth1.TtlSecurityDrops < 3 {
+		t.Fatalf("failed drops: %d %v", eth0Drops, eth1)
+	}
+	if states, _, _ := r1.neighborStates("eth1"); len(states) != 0 {
+		t.Fatalf("failed neighbor: %v", states)
+	}
+	// r3 hears of r1 but never sees itself in its hellos
+	if states, _, _ := r3.neighborStates("eth0"); states[0x01010101] != NEIGHBOR_STATE_INIT {
+		t.Fatalf("failed neighbor: %v", states)
+	}
+}
@@ -86,10 +86,11 @@ func (ospf *OspfServer) updateChSend(msg *UpdateChMsg) {
 	go func() {
 		updateChSendCountLock.Lock()
 		updateChSendCount++
+		count := updateChSendCount
 		updateChSendCountLock.Unlock()
-		log.Debugf("updateChSend[%d]: begin", updateChSendCount)
+		log.Debugf("updateChSend[%d]: begin", count)
 		ospf.updateCh <- msg
-		log.Debugf("updateChSend[%d]: end", updateChSendCount)
+		log.Debugf("updateChSend[%d]: end", count)
 	}()
 }
 
@@ -101,10 +102,13 @@ func (ospf *OspfServer) updateProcess(wg *sync.WaitGroup) {
 		msg := <-ospf.updateCh
 		log.Infof("%s", msg)
 		switch msg.msgType {
-		case UPDATE_CH_MSG_TYPE_CONFIG_CHANGED:
-		case UPDATE_CH_MSG_TYPE_KERNEL_CHANGED:
-		case UPDATE_CH_MSG_TYPE_OSPF_ENABLE:
-		case UPDATE_CH_MSG_TYPE_OSPF_DISABLE:
+		case UPDATE_CH_MSG_TYPE_CONFIG_CHANGED,
+			UPDATE_CH_MSG_TYPE_KERNEL_CHANGED,
+			UPDATE_CH_MSG_TYPE_OSPF_ENABLE,
+			UPDATE_CH_MSG_TYPE_OSPF_DISABLE:
+			ospf.lock.Lock()
+			ospf.updateInterfaces()
+			ospf.lock.Unlock()
 		case UPDATE_CH_MSG_TYPE_EXIT:
 			goto EXIT
 		}
@@ -0,0 +1,319 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"errors"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/m-asama/golsr/internal/pkg/ospf/config"
+	"github.com/m-asama/golsr/pkg/ospf/packet"
+)
+
+// virtualLink is what an interface of the backbone which is a virtual
+// link has on top of the others as in RFC 2328 15: the other endpoint
+// and the path to it through the transit area, which the routing table
+// calculation of the transit area gives.
+type virtualLink struct {
+	transitAreaId uint32
+	routerId      uint32
+	address       uint32
+	cost          uint32
+	transitIface  *Interface
+	nexthops      []*spfNexthop
+}
+
+// virtualLinkName is the name of the interface of the virtual link, which
+// can not be the one of an interface of the kernel.
+func virtualLinkName(transitAreaId, routerId uint32) string {
+	return fmt.Sprintf("vlink/%s/%s", packet.Ipv4String(transitAreaId), packet.Ipv4String(routerId))
+}
+
+// newVirtualLinkConfig returns the config of the interface of the virtual
+// link, an unnumbered point-to-point interface the MTU of which is not
+// checked as in RFC 2328 10.6.
+func newVirtualLinkConfig(name string, vlinkConfig *config.VirtualLink) *config.Interface {
+	interfaceType := "point-to-point"
+	passive := false
+	priority := uint8(0)
+	cost := uint16(0)
+	mtuIgnore := true
+	ifConfig := &config.Interface{
+		TtlSecurity:    vlinkConfig.TtlSecurity,
+		Authentication: vlinkConfig.Authentication,
+	}
+	ifConfig.Config.Name = &name
+	ifConfig.Config.InterfaceType = &interfaceType
+	ifConfig.Config.Passive = &passive
+	ifConfig.Config.Priority = &priority
+	ifConfig.Config.HelloInterval = vlinkConfig.Config.HelloInterval
+	ifConfig.Config.DeadInterval = vlinkConfig.Config.DeadInterval
+	ifConfig.Config.RetransmitInterval = vlinkConfig.Config.RetransmitInterval
+	ifConfig.Config.TransmitDelay = vlinkConfig.Config.TransmitDelay
+	ifConfig.Config.Enable = vlinkConfig.Config.Enable
+	ifConfig.Config.Cost = &cost
+	ifConfig.Config.MtuIgnore = &mtuIgnore
+	return ifConfig
+}
+
+// transitArea returns the transit area of the interface if it is a
+// virtual link.
+func (iface *Interface) transitArea() *Area {
+	if iface.virtualLink == nil {
+		return nil
+	}
+	return iface.ospf.findArea(iface.virtualLink.transitAreaId)
+}
+
+// findVirtualLink finds the virtual link up through the transit area to
+// routerId, which the packets from routerId of the backbone received in
+// the transit area are for.
+func (ospf *OspfServer) findVirtualLink(transitAreaId, routerId uint32) *Interface {
+	for _, iface := range ospf.interfaceDb {
+		vlink := iface.virtualLink
+		if vlink == nil || iface.state == INTERFACE_STATE_DOWN {
+			continue
+		}
+		if vlink.transitAreaId == transitAreaId && vlink.routerId == routerId {
+			return iface
+		}
+	}
+	return nil
+}
+
+// virtualLinkDst tells whether dst, the destination of a packet of a
+// virtual link received in the transit area, is an address of the router
+// there. The other endpoint sends to the address it finds in our
+// router-LSA, which need not be the one of the receiving interface.
+func (area *Area) virtualLinkDst(dst Address) bool {
+	for _, iface := range area.interfaces() {
+		if iface.state != INTERFACE_STATE_DOWN && dst == ipv4Address(iface.address) {
+			return true
+		}
+	}
+	return false
+}
+
+// virtualLinksFull tells whether the router has a fully adjacent virtual
+// link through the area, which sets the V-bit of its router-LSA there.
+func (area *Area) virtualLinksFull() bool {
+	for _, iface := range area.ospf.interfaceDb {
+		if iface.virtualLink == nil || iface.virtualLink.transitAreaId != area.areaId {
+			continue
+		}
+		for _, nbr := range iface.neighborDb {
+			if nbr.state == NEIGHBOR_STATE_FULL {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// virtualLinkPath returns the route through the transit area to the other
+// endpoint of the virtual link and the address of the endpoint, an
+// address of an interface in its router-LSA there as in RFC 2328 15.
+func (ospf *OspfServer) virtualLinkPath(vlink *virtualLink) (*RouterRi, uint32) {
+	transit := ospf.findArea(vlink.transitAreaId)
+	if transit == nil || !transit.externalCapable() {
+		return nil, 0
+	}
+	ri := transit.routerRiDb[vlink.routerId]
+	if ri == nil || !ri.border || ri.metric >= packet.LS_INFINITY || len(ri.nexthops) == 0 {
+		return nil, 0
+	}
+	lsa, _ := transit.spfRouterLsa(vlink.routerId).(*packet.RouterLsa)
+	if lsa == nil {
+		return nil, 0
+	}
+	address := routerLsaAddress(lsa, ospf.routerId)
+	if address == 0 {
+		return nil, 0
+	}
+	return ri, address
+}
+
+// routerLsaAddress returns an address of an interface of the router of
+// lsa, or 0 if it has none, the one of its numbered point-to-point link
+// to routerId if any so that it does not depend on the order of the
+// links. The Link Data of a transit link is one, the one of a
+// point-to-point link only if the link is numbered, which its subnet
+// described by a stub link tells, and is the ifIndex of the interface
+// otherwise as in RFC 2328 12.4.1.1.
+func routerLsaAddress(lsa *packet.RouterLsa, routerId uint32) uint32 {
+	for _, link := range lsa.Links {
+		if link.Type == packet.LINK_TYPE_POINT_TO_POINT && link.LinkId == routerId &&
+			routerLsaNumbered(lsa, link) {
+			return link.LinkData
+		}
+	}
+	for _, link := range lsa.Links {
+		if link.Type == packet.LINK_TYPE_TRANSIT {
+			return link.LinkData
+		}
+	}
+	for _, link := range lsa.Links {
+		if link.Type == packet.LINK_TYPE_POINT_TO_POINT && routerLsaNumbered(lsa, link) {
+			return link.LinkData
+		}
+	}
+	return 0
+}
+
+// routerLsaNumbered tells whether the point-to-point link of lsa is
+// numbered, that is whether a stub link of lsa covers its Link Data.
+func routerLsaNumbered(lsa *packet.RouterLsa, link *packet.RouterLink) bool {
+	for _, stub := range lsa.Links {
+		if stub.Type == packet.LINK_TYPE_STUB && stub.LinkData != 0 &&
+			link.LinkData&stub.LinkData == stub.LinkId {
+			return true
+		}
+	}
+	return false
+}
+
+// updateVirtualLinks brings the virtual links up and down as the routing
+// tables of their transit areas allow and updates their costs, the costs
+// of the paths through the transit areas. The routes of the backbone over
+// a virtual link take the next hops of its path, so they are calculated
+// again when the path changes but not its cost.
+func (ospf *OspfServer) updateVirtualLinks() {
+	log.Debugf("enter")
+	defer log.Debugf("exit")
+	for _, iface := range ospf.interfaceDb {
+		vlink := iface.virtualLink
+		if vlink == nil {
+			continue
+		}
+		var ri *RouterRi
+		var address uint32
+		if ospf.ready() && iface.ifConfig != nil && iface.enable() {
+			ri, address = ospf.virtualLinkPath(vlink)
+		}
+		if ri == nil {
+			iface.down()
+			continue
+		}
+		transitIface := ri.nexthops[0].iface
+		if iface.state != INTERFACE_STATE_DOWN &&
+			(address != vlink.address || transitIface.address != iface.address) {
+			iface.down()
+		}
+		changed := !sameSpfNexthops(vlink.nexthops, ri.nexthops)
+		vlink.transitIface = transitIface
+		vlink.nexthops = ri.nexthops
+		if iface.state == INTERFACE_STATE_DOWN {
+			vlink.address = address
+			vlink.cost = ri.metric
+			iface.upVirtualLink()
+			continue
+		}
+		if ri.metric != vlink.cost {
+			vlink.cost = ri.metric
+			iface.area.originateRouterLsa()
+		} else if changed {
+			ospf.decisionChSend(&DecisionChMsg{
+				msgType: DECISION_CH_MSG_TYPE_DO,
+			})
+		}
+	}
+}
+
+// upVirtualLink runs the InterfaceUp event of a virtual link, which takes
+// the address of the interface of the path through the transit area.
+func (iface *Interface) upVirtualLink() {
+	log.Debugf("enter: %s", iface.name)
+	defer log.Debugf("exit: %s", iface.name)
+	vlink := iface.virtualLink
+	iface.address = vlink.transitIface.address
+	iface.prefixLength = 0
+	iface.mtu = vlink.transitIface.mtu
+	iface.transport = &virtualTransport{vlink: vlink}
+	iface.setState(INTERFACE_STATE_POINT_TO_POINT)
+	iface.sendHello()
+	iface.area.originateRouterLsa()
+}
+
+// virtualLinkCost is the cost of the virtual link in the router-LSA of
+// the backbone.
+func (iface *Interface) virtualLinkCost() uint16 {
+	if iface.virtualLink.cost > 0xffff {
+		return 0xffff
+	}
+	return uint16(iface.virtualLink.cost)
+}
+
+// virtualLinkRouterLinks returns the link the virtual link adds to the
+// router-LSA of the backbone as in RFC 2328 12.4.1.3.
+func (iface *Interface) virtualLinkRouterLinks() []*packet.RouterLink {
+	links := make([]*packet.RouterLink, 0)
+	if iface.state == INTERFACE_STATE_DOWN {
+		return links
+	}
+	for _, nbr := range iface.neighborDb {
+		if nbr.state == NEIGHBOR_STATE_FULL {
+			links = append(links, &packet.RouterLink{
+				LinkId:   nbr.routerId,
+				LinkData: iface.address,
+				Type:     packet.LINK_TYPE_VIRTUAL,
+				Metric:   iface.virtualLinkCost(),
+			})
+		}
+	}
+	return links
+}
+
+// virtualTransport sends the packets of a virtual link to the other
+// endpoint through the transport of the interface of the path to it. The
+// packets of the virtual link are received by that interface.
+type virtualTransport struct {
+	vlink *virtualLink
+}
+
+func (transport *virtualTransport) Send(data []byte, dst Address) error {
+	iface := transport.vlink.transitIface
+	if iface == nil || iface.transport == nil {
+		return nil
+	}
+	return iface.transport.Send(data, ipv4Address(transport.vlink.address))
+}
+
+func (transport *virtualTransport) Recv(buf []byte) (int, *PacketInfo, error) {
+	return 0, nil, errors.New("virtualTransport.Recv: received by the transit interface")
+}
+
+func (transport *virtualTransport) JoinGroup(group Address) error {
+	return nil
+}
+
+func (transport *virtualTransport) LeaveGroup(group Address) error {
+	return nil
+}
+
+// SetTtlSecurity does nothing: the packets of the virtual link are sent
+// and received by the transport of the transit interface, the TTL
+// security of which takes the virtual link into account.
+func (transport *virtualTransport) SetTtlSecurity(enable bool, minTtl uint8) error {
+	return nil
+}
+
+func (transport *virtualTransport) Close() error {
+	return nil
+}
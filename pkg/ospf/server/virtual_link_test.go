@@ -0,0 +1,178 @@
+//
+// Copyright (C) 2019-2019 Masakazu Asama.
+// Copyright (C) 2019-2019 Ginzado Co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/m-asama/golsr/internal/pkg/kernel"
+	"github.com/m-asama/golsr/pkg/ospf/packet"
+)
+
+func virtualLinkConfig(transitAreaId, routerId string) string {
+	return fmt.Sprintf("  [[areas.virtual-links]]\n"+
+		"    [areas.virtual-links.config]\n"+
+		"      transit-area-id = %q\n"+
+		"      router-id = %q\n"+
+		"      hello-interval = 1\n", transitAreaId, routerId)
+}
+
+func TestVirtualLink(t *testing.T) {
+	// r1 is attached to a part of the backbone cut off from the rest but
+	// through the virtual link through area 0.0.0.1.
+	// r1 --(0.0.0.1)-- r2 --(backbone)-- r3
+	network := NewMemoryNetwork()
+	r1 := startTestRouter(t, network, "r1", 0x01010101, []*testPort{
+		&testPort{name: "lo", address: 0x01010101, ifType: kernel.IF_TYPE_LOOPBACK,
+			areaConfig: virtualLinkConfig("0.0.0.1", "2.2.2.2")},
+		&testPort{area: "0.0.0.1", segment: "a", name: "eth0", address: 0x0a000101, ifType: kernel.IF_TYPE_POINTTOPOINT, priority: 1},
+	})
+	defer r1.stop()
+	r2 := startTestRouter(t, network, "r2", 0x02020202, []*testPort{
+		&testPort{segment: "b", name: "eth1", address: 0x0a000202, ifType: kernel.IF_TYPE_BROADCAST, priority: 1,
+			areaConfig: virtualLinkConfig("0.0.0.1", "1.1.1.1")},
+		&testPort{name: "lo", address: 0x02020202, ifType: kernel.IF_TYPE_LOOPBACK},
+		&testPort{area: "0.0.0.1", segment: "a", name: "eth0", address: 0x0a000102, ifType: kernel.IF_TYPE_POINTTOPOINT, priority: 1},
+	})
+	defer r2.stop()
+	r3 := startTestRouter(t, network, "r3", 0x03030303, []*testPort{
+		&testPort{segment: "b", name: "eth0", address: 0x0a000203, ifType: kernel.IF_TYPE_BROADCAST, priority: 1},
+		&testPort{name: "lo", address: 0x03030303, ifType: kernel.IF_TYPE_LOOPBACK},
+	})
+	defer r3.stop()
+
+	waitFor(t, "routes over the virtual link", func() bool {
+		for _, check := range []struct {
+			router  *testRouter
+			prefix  uint32
+			nexthop uint32
+		}{
+			{r1, 0x03030303, 0x0a000102},
+			{r3, 0x01010101, 0x0a000202},
+		} {
+			route := check.router.fibRoute(check.prefix, 32)
+			if route == nil || len(route.NextHops) != 1 || route.NextHops[0].Address != check.nexthop {
+				return false
+			}
+		}
+		return true
+	})
+
+	r1.ospf.lock.RLock()
+	ri := r1.ospf.ipv4RiDb[Ipv4RiKey{0x03030303, 32}]
+	iface := r1.ospf.findInterface(virtualLinkName(1, 0x02020202))
+	vlink := newApiVirtualLink(iface)
+	v := r1.ospf.findArea(1).spfRouterLsa(0x01010101).(*packet.RouterLsa).VirtualLink
+	r1.ospf.lock.RUnlock()
+	if ri == nil || ri.routeType != ROUTE_TYPE_INTRA_AREA || ri.areaId != 0 || ri.metric != 20 {
+		t.Fatalf("failed route over the virtual link: %v", ri)
+	}
+	if vlink.State != INTERFACE_STATE_POINT_TO_POINT.String() ||
+		vlink.NeighborState != NEIGHBOR_STATE_FULL.String() || vlink.Cost != 10 ||
+		vlink.NeighborAddress != "10.0.1.2" || vlink.TransitInterface != "eth0" {
+		t.Fatalf("failed virtual link: %v", vlink)
+	}
+	if !v {
+		t.Fatalf("failed V-bit")
+	}
+
+	// the virtual link goes down with the path through the transit area
+	network.Disconnect("r1", "eth0")
+	waitFor(t, "virtual link down", func() bool {
+		r1.ospf.lock.RLock()
+		defer r1.ospf.lock.RUnlock()
+		return iface.state == INTERFACE_STATE_DOWN
+	})
+}
+
+func TestVirtualLinkTransitArea(t *testing.T) {
+	// r1 reaches the backbone through the virtual link to r2, but the
+	// summary-LSA r3 originates into the transit area gives a shorter
+	// path to the loopback of r3 as in RFC 2328 16.3.
+	// r1 --(0.0.0.1)-- r2 --(backbone)-- r3
+	//  \                                 /
+	//   `------------(0.0.0.1)----------'
+	network := NewMemoryNetwork()
+	r1 := startTestRouter(t, network, "r1", 0x01010101, []*testPort{
+		&testPort{name: "lo", address: 0x01010101, ifType: kernel.IF_TYPE_LOOPBACK,
+			areaConfig: virtualLinkConfig("0.0.0.1", "2.2.2.2")},
+		&testPort{area: "0.0.0.1", segment: "a", name: "eth0", address: 0x0a000101, ifType: kernel.IF_TYPE_POINTTOPOINT, priority: 1},
+		&testPort{area: "0.0.0.1", segment: "c", name: "eth1", address: 0x0a000301, ifType: kernel.IF_TYPE_POINTTOPOINT, priority: 1},
+	})
+	defer r1.stop()
+	r2 := startTestRouter(t, network, "r2", 0x02020202, []*testPort{
+		&testPort{segment: "b", name: "eth1", address: 0x0a000202, ifType: kernel.IF_TYPE_BROADCAST, priority: 1,
+			areaConfig: virtualLinkConfig("0.0.0.1", "1.1.1.1")},
+		&testPort{name: "lo", address: 0x02020202, ifType: kernel.IF_TYPE_LOOPBACK},
+		&testPort{area: "0.0.0.1", segment: "a", name: "eth0", address: 0x0a000102, ifType: kernel.IF_TYPE_POINTTOPOINT, priority: 1},
+	})
+	defer r2.stop()
+	r3 := startTestRouter(t, network, "r3", 0x03030303, []*testPort{
+		&testPort{segment: "b", name: "eth0", address: 0x0a000203, ifType: kernel.IF_TYPE_BROADCAST, priority: 1},
+		&testPort{name: "lo", address: 0x03030303, ifType: kernel.IF_TYPE_LOOPBACK},
+		&testPort{area: "0.0.0.1", segment: "c", name: "eth1", address: 0x0a000303, ifType: kernel.IF_TYPE_POINTTOPOINT, priority: 1},
+	})
+	defer r3.stop()
+
+	waitFor(t, "route through the transit area", func() bool {
+		route := r1.fibRoute(0x03030303, 32)
+		return route != nil && len(route.NextHops) == 1 && route.NextHops[0].Address == 0x0a000303
+	})
+
+	r1.ospf.lock.RLock()
+	ri := r1.ospf.ipv4RiDb[Ipv4RiKey{0x03030303, 32}]
+	transitCapability := r1.ospf.findArea(1).transitCapability
+	r1.ospf.lock.RUnlock()
+	if ri == nil || ri.routeType != ROUTE_TYPE_INTRA_AREA || ri.areaId != 0 || ri.metric != 10 {
+		t.Fatalf("failed route through the transit area: %v", ri)
+	}
+	if !transitCapability {
+		t.Fatalf("failed TransitCapability")
+	}
+}
+
+func TestRouterLsaAddress(t *testing.T) {
+	// the unnumbered link comes first, its Link Data being the ifIndex
+	lsa, _ := packet.NewRouterLsa()
+	lsa.Links = []*packet.RouterLink{
+		&packet.RouterLink{LinkId: 0x01010101, LinkData: 3, Type: packet.LINK_TYPE_POINT_TO_POINT, Metric: 10},
+		&packet.RouterLink{LinkId: 0x03030303, LinkData: 0x0a000102, Type: packet.LINK_TYPE_POINT_TO_POINT, Metric: 10},
+		&packet.RouterLink{LinkId: 0x0a000100, LinkData: 0xffffff00, Type: packet.LINK_TYPE_STUB, Metric: 10},
+		&packet.RouterLink{LinkId: 0x02020202, LinkData: 0xffffffff, Type: packet.LINK_TYPE_STUB, Metric: 0},
+	}
+	if address := routerLsaAddress(lsa, 0x04040404); address != 0x0a000102 {
+		t.Fatalf("failed numbered point-to-point: %s", packet.Ipv4String(address))
+	}
+
+	lsa.Links = append(lsa.Links, &packet.RouterLink{LinkId: 0x0a000203, LinkData: 0x0a000202, Type: packet.LINK_TYPE_TRANSIT, Metric: 10})
+	if address := routerLsaAddress(lsa, 0x04040404); address != 0x0a000202 {
+		t.Fatalf("failed transit: %s", packet.Ipv4String(address))
+	}
+
+	// the link to the calculating router comes before the others
+	lsa.Links = append(lsa.Links, &packet.RouterLink{LinkId: 0x04040404, LinkData: 0x0a000101, Type: packet.LINK_TYPE_POINT_TO_POINT, Metric: 10})
+	if address := routerLsaAddress(lsa, 0x04040404); address != 0x0a000101 {
+		t.Fatalf("failed link to the calculating router: %s", packet.Ipv4String(address))
+	}
+
+	lsa.Links = lsa.Links[:1]
+	if address := routerLsaAddress(lsa, 0x04040404); address != 0 {
+		t.Fatalf("failed unnumbered: %s", packet.Ipv4String(address))
+	}
+}